@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+type (
+	// Resource describes a resource administered by the admin UI.
+	Resource struct {
+		// Name is the name of the resource type.
+		Name string `json:"name"`
+		// Title is the title of the resource in the UI.
+		Title string `json:"title"`
+		// ID is the name of the field holding the resource ID.
+		ID string `json:"id"`
+		// Fields lists the fields of the resources.
+		Fields []*Field `json:"fields"`
+		// List is the endpoint listing the resources.
+		List *Endpoint `json:"list"`
+		// Show is the endpoint returning a single resource, nil if
+		// there is none.
+		Show *Endpoint `json:"show,omitempty"`
+		// Create is the endpoint creating a resource, nil if there is
+		// none.
+		Create *Endpoint `json:"create,omitempty"`
+	}
+
+	// Field describes a field of a resource or of a request body.
+	Field struct {
+		// Name is the JSON name of the field.
+		Name string `json:"name"`
+		// Type is the JSON schema type of the field: "string",
+		// "integer", "number", "boolean", "array", "object" or "any".
+		Type string `json:"type"`
+		// Description describes the field.
+		Description string `json:"description,omitempty"`
+		// Required is true if the field is required.
+		Required bool `json:"required,omitempty"`
+	}
+
+	// Endpoint describes an HTTP endpoint called by the admin UI.
+	Endpoint struct {
+		// Method is the HTTP method of the endpoint.
+		Method string `json:"method"`
+		// Path is the path of the endpoint. The UI replaces the path
+		// parameter of the show endpoints with the resource ID.
+		Path string `json:"path"`
+		// Fields lists the fields of the request body, the UI renders
+		// a form input for each.
+		Fields []*Field `json:"fields,omitempty"`
+		// Security lists the security schemes whose credentials the UI
+		// sends with the requests.
+		Security []*Scheme `json:"security,omitempty"`
+	}
+
+	// Scheme describes a security scheme of an endpoint.
+	Scheme struct {
+		// SchemeName is the name of the scheme in the design.
+		SchemeName string `json:"scheme"`
+		// Kind is the kind of scheme: "basic", "apikey", "jwt" or
+		// "oauth2".
+		Kind string `json:"kind"`
+		// In is the location of the credentials, "header" or "query".
+		In string `json:"in"`
+		// Name is the name of the header or query string parameter
+		// holding the credentials.
+		Name string `json:"name"`
+	}
+)
+
+// Handler returns a HTTP handler serving the admin UI of the given resources
+// under prefix. The UI is a single page which calls the resource endpoints
+// from the browser and sends the credentials of their security schemes
+// entered by the user. The requests for unknown paths are answered with 404
+// Not Found.
+func Handler(prefix, title string, resources []*Resource) http.Handler {
+	prefix = strings.TrimSuffix(prefix, "/")
+	res, err := json.Marshal(resources)
+	if err != nil {
+		panic(err) // bug: resources are plain data
+	}
+	var index strings.Builder
+	if err := indexT.Execute(&index, map[string]string{"Prefix": prefix, "Title": title}); err != nil {
+		panic(err) // bug
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ct, body string
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "", "/":
+			ct, body = "text/html; charset=utf-8", index.String()
+		case "/admin.css":
+			ct, body = "text/css; charset=utf-8", adminCSS
+		case "/admin.js":
+			ct, body = "application/javascript", adminJS
+		case "/resources.json":
+			ct, body = "application/json", string(res)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", ct)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write([]byte(body))
+	})
+}
+
+// indexT renders the page of the admin UI.
+var indexT = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<link rel="stylesheet" href="{{ .Prefix }}/admin.css">
+</head>
+<body>
+<header><a href="#/">{{ .Title }}</a></header>
+<main id="admin" data-resources="{{ .Prefix }}/resources.json"></main>
+<script src="{{ .Prefix }}/admin.js"></script>
+</body>
+</html>
+`))
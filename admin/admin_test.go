@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	resources := []*Resource{{
+		Name:   "Article",
+		Title:  "Articles",
+		ID:     "id",
+		Fields: []*Field{{Name: "id", Type: "integer", Required: true}},
+		List: &Endpoint{
+			Method:   "GET",
+			Path:     "/articles",
+			Security: []*Scheme{{SchemeName: "jwt", Kind: "jwt", In: "header", Name: "Authorization"}},
+		},
+	}}
+	h := Handler("/admin/", "Blog <API>", resources)
+	cases := []struct {
+		Name        string
+		Path        string
+		Status      int
+		ContentType string
+		Contains    string
+	}{
+		{"root", "/admin", http.StatusOK, "text/html; charset=utf-8", "<title>Blog &lt;API&gt;</title>"},
+		{"index", "/admin/", http.StatusOK, "text/html; charset=utf-8", `src="/admin/admin.js"`},
+		{"style", "/admin/admin.css", http.StatusOK, "text/css; charset=utf-8", "table {"},
+		{"script", "/admin/admin.js", http.StatusOK, "application/javascript", "function route()"},
+		{"resources", "/admin/resources.json", http.StatusOK, "application/json", `"name":"Article"`},
+		{"unknown", "/admin/unknown", http.StatusNotFound, "text/plain; charset=utf-8", "not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", c.Path, nil))
+			if w.Code != c.Status {
+				t.Errorf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != c.ContentType {
+				t.Errorf("got content type %q, expected %q", ct, c.ContentType)
+			}
+			if !strings.Contains(w.Body.String(), c.Contains) {
+				t.Errorf("got body %q, expected it to contain %q", w.Body.String(), c.Contains)
+			}
+		})
+	}
+}
+
+func TestHandlerResources(t *testing.T) {
+	resources := []*Resource{{
+		Name:  "Page",
+		Title: "Pages",
+		ID:    "slug",
+		List:  &Endpoint{Method: "GET", Path: "/pages"},
+	}}
+	w := httptest.NewRecorder()
+	Handler("/admin", "Pages", resources).ServeHTTP(w, httptest.NewRequest("GET", "/admin/resources.json", nil))
+	var got []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d resources, expected 1", len(got))
+	}
+	if _, ok := got[0]["show"]; ok {
+		t.Errorf("got show endpoint %v, expected none", got[0]["show"])
+	}
+	if got[0]["id"] != "slug" {
+		t.Errorf("got ID %v, expected slug", got[0]["id"])
+	}
+}
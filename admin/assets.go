@@ -0,0 +1,344 @@
+package admin
+
+// adminCSS is the style sheet of the admin UI.
+const adminCSS = `body {
+  margin: 0;
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif;
+  color: #24292e;
+}
+
+header {
+  padding: 0.75rem 2rem;
+  background: #24292e;
+}
+
+header a {
+  color: #fff;
+  font-weight: bold;
+}
+
+main {
+  max-width: 60rem;
+  padding: 1rem 2rem;
+}
+
+a {
+  color: #0366d6;
+  text-decoration: none;
+}
+
+table {
+  border-collapse: collapse;
+  margin: 0.5rem 0;
+}
+
+th, td {
+  border: 1px solid #e1e4e8;
+  padding: 0.3rem 0.6rem;
+  text-align: left;
+  vertical-align: top;
+}
+
+form label {
+  display: block;
+  margin: 0.5rem 0 0.2rem;
+  font-size: 0.85rem;
+}
+
+form input[type=text], form input[type=number], form input[type=password], form textarea {
+  width: 100%;
+  max-width: 30rem;
+  box-sizing: border-box;
+}
+
+form textarea {
+  font-family: monospace;
+}
+
+button {
+  margin-top: 0.5rem;
+}
+
+.error {
+  color: #cb2431;
+  white-space: pre-wrap;
+}
+`
+
+// adminJS renders the pages of the admin UI from the resources loaded from
+// resources.json and calls the resource endpoints.
+const adminJS = `(function () {
+  "use strict";
+
+  var root = document.getElementById("admin");
+  var resources = [];
+
+  function el(tag, text) {
+    var e = document.createElement(tag);
+    if (text !== undefined) {
+      e.textContent = text;
+    }
+    return e;
+  }
+
+  function link(href, text) {
+    var a = el("a", text);
+    a.href = href;
+    return a;
+  }
+
+  function format(v) {
+    if (v === undefined || v === null) {
+      return "";
+    }
+    return typeof v === "object" ? JSON.stringify(v) : String(v);
+  }
+
+  function fail(err) {
+    root.appendChild(el("p", String(err.message || err))).className = "error";
+  }
+
+  function key(scheme) {
+    return "admin:" + scheme.scheme;
+  }
+
+  function schemes() {
+    var seen = {}, res = [];
+    resources.forEach(function (r) {
+      [r.list, r.show, r.create].forEach(function (ep) {
+        (ep && ep.security || []).forEach(function (s) {
+          if (!seen[s.scheme]) {
+            seen[s.scheme] = true;
+            res.push(s);
+          }
+        });
+      });
+    });
+    return res;
+  }
+
+  function request(ep, path, body) {
+    var headers = {}, query = [];
+    (ep.security || []).forEach(function (s) {
+      var c = JSON.parse(sessionStorage.getItem(key(s)) || "null");
+      if (!c) {
+        return;
+      }
+      var v = c.value;
+      switch (s.kind) {
+      case "basic":
+        v = "Basic " + btoa(c.username + ":" + c.password);
+        break;
+      case "jwt":
+      case "oauth2":
+        if (s.in === "header") {
+          v = "Bearer " + c.value;
+        }
+        break;
+      }
+      if (s.in === "query") {
+        query.push(encodeURIComponent(s.name) + "=" + encodeURIComponent(v));
+      } else {
+        headers[s.name] = v;
+      }
+    });
+    var init = { method: ep.method, headers: headers };
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+    return fetch(path + (query.length > 0 ? "?" + query.join("&") : ""), init)
+      .then(function (resp) {
+        return resp.text().then(function (text) {
+          if (!resp.ok) {
+            throw new Error(resp.status + " " + resp.statusText + (text ? "\n" + text : ""));
+          }
+          return text ? JSON.parse(text) : null;
+        });
+      });
+  }
+
+  function input(form, f) {
+    var l = el("label", f.name + (f.required ? " (required)" : "") + (f.description ? " - " + f.description : ""));
+    var i;
+    switch (f.type) {
+    case "boolean":
+      i = el("input");
+      i.type = "checkbox";
+      break;
+    case "integer":
+    case "number":
+      i = el("input");
+      i.type = "number";
+      i.step = f.type === "integer" ? "1" : "any";
+      break;
+    case "string":
+      i = el("input");
+      i.type = "text";
+      break;
+    default:
+      i = el("textarea");
+      i.rows = 4;
+      i.placeholder = "JSON";
+    }
+    i.name = f.name;
+    form.appendChild(l);
+    form.appendChild(i);
+  }
+
+  function value(form, f) {
+    var i = form.elements[f.name];
+    if (f.type === "boolean") {
+      return i.checked;
+    }
+    if (i.value === "") {
+      return undefined;
+    }
+    switch (f.type) {
+    case "integer":
+      return parseInt(i.value, 10);
+    case "number":
+      return parseFloat(i.value);
+    case "string":
+      return i.value;
+    default:
+      return JSON.parse(i.value);
+    }
+  }
+
+  function home() {
+    root.appendChild(el("h1", "Resources"));
+    var ul = root.appendChild(el("ul"));
+    resources.forEach(function (r) {
+      ul.appendChild(el("li")).appendChild(link("#/" + r.name, r.title));
+    });
+    var ss = schemes();
+    if (ss.length === 0) {
+      return;
+    }
+    root.appendChild(el("h2", "Credentials"));
+    ss.forEach(function (s) {
+      var form = root.appendChild(el("form"));
+      form.appendChild(el("h3", s.scheme + " (" + s.kind + ")"));
+      var fields = s.kind === "basic" ? ["username", "password"] : ["value"];
+      var saved = JSON.parse(sessionStorage.getItem(key(s)) || "{}");
+      fields.forEach(function (name) {
+        form.appendChild(el("label", name));
+        var i = form.appendChild(el("input"));
+        i.type = name === "username" ? "text" : "password";
+        i.name = name;
+        i.value = saved[name] || "";
+      });
+      var button = form.appendChild(el("button", "Save"));
+      button.type = "submit";
+      form.addEventListener("submit", function (e) {
+        e.preventDefault();
+        var c = {};
+        fields.forEach(function (name) {
+          c[name] = form.elements[name].value;
+        });
+        sessionStorage.setItem(key(s), JSON.stringify(c));
+      });
+    });
+  }
+
+  function list(r) {
+    root.appendChild(el("h1", r.title));
+    if (r.create) {
+      root.appendChild(link("#/" + r.name + "/new", "New"));
+    }
+    request(r.list, r.list.path).then(function (items) {
+      var table = root.appendChild(el("table"));
+      var tr = table.appendChild(el("tr"));
+      r.fields.forEach(function (f) {
+        tr.appendChild(el("th", f.name));
+      });
+      (items || []).forEach(function (item) {
+        var tr = table.appendChild(el("tr"));
+        r.fields.forEach(function (f) {
+          var td = tr.appendChild(el("td"));
+          var v = format(item[f.name]);
+          if (f.name === r.id && r.show) {
+            td.appendChild(link("#/" + r.name + "/" + encodeURIComponent(v), v));
+          } else {
+            td.textContent = v;
+          }
+        });
+      });
+    }).catch(fail);
+  }
+
+  function show(r, id) {
+    root.appendChild(el("h1", r.title + " " + id));
+    root.appendChild(link("#/" + r.name, "Back"));
+    request(r.show, r.show.path.replace(/\{[^}]*\}/, encodeURIComponent(id))).then(function (item) {
+      var table = root.appendChild(el("table"));
+      r.fields.forEach(function (f) {
+        var tr = table.appendChild(el("tr"));
+        tr.appendChild(el("th", f.name));
+        tr.appendChild(el("td", format(item[f.name])));
+      });
+    }).catch(fail);
+  }
+
+  function create(r) {
+    root.appendChild(el("h1", "New " + r.title));
+    root.appendChild(link("#/" + r.name, "Back"));
+    var form = root.appendChild(el("form"));
+    (r.create.fields || []).forEach(function (f) {
+      input(form, f);
+    });
+    var button = form.appendChild(el("button", "Create"));
+    button.type = "submit";
+    form.addEventListener("submit", function (e) {
+      e.preventDefault();
+      var body = {};
+      try {
+        (r.create.fields || []).forEach(function (f) {
+          var v = value(form, f);
+          if (v !== undefined) {
+            body[f.name] = v;
+          }
+        });
+      } catch (err) {
+        fail(err);
+        return;
+      }
+      request(r.create, r.create.path, body).then(function (item) {
+        var id = item && item[r.id];
+        location.hash = "#/" + r.name + (r.show && id !== undefined ? "/" + encodeURIComponent(format(id)) : "");
+      }).catch(fail);
+    });
+  }
+
+  function route() {
+    root.textContent = "";
+    var parts = location.hash.replace(/^#\/?/, "").split("/");
+    var r = resources.filter(function (r) {
+      return r.name === parts[0];
+    })[0];
+    if (!r) {
+      home();
+    } else if (parts.length === 1) {
+      list(r);
+    } else if (parts[1] === "new" && r.create) {
+      create(r);
+    } else if (r.show) {
+      show(r, decodeURIComponent(parts[1]));
+    } else {
+      list(r);
+    }
+  }
+
+  fetch(root.dataset.resources)
+    .then(function (resp) {
+      return resp.json();
+    })
+    .then(function (res) {
+      resources = res || [];
+      window.addEventListener("hashchange", route);
+      route();
+    })
+    .catch(fail);
+})();
+`
@@ -0,0 +1,67 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/admin/expr"
+	"goa.design/plugins/v3/genutil"
+
+	// Register code generators for the admin plugin
+	_ "goa.design/plugins/v3/admin"
+)
+
+// Resource declares that the enclosing type is administered by the generated
+// admin UI. The UI lists the resources with the first HTTP GET endpoint
+// returning an array or a collection of the type, shows a single resource
+// with the first HTTP GET endpoint returning the type and whose path has a
+// parameter and creates resources with the first HTTP POST endpoint returning
+// the type. The show and create endpoints are optional.
+//
+// Resource must appear in a Type or ResultType expression.
+//
+// Resource accepts the title of the resource in the admin UI as first
+// argument and an optional DSL function as second argument.
+//
+// Example:
+//
+//    import admin "goa.design/plugins/v3/admin/dsl"
+//
+//    var Article = ResultType("application/vnd.article", func() {
+//        admin.Resource("Articles", func() {
+//            admin.ID("slug") // Defaults to "id"
+//        })
+//        Attributes(func() {
+//            Attribute("slug", String)
+//            Attribute("title", String)
+//        })
+//    })
+//
+func Resource(title string, fn ...func()) {
+	ut := genutil.UserType(eval.Current())
+	if ut == nil {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	r := &expr.ResourceExpr{Title: title, ID: "id", UserType: ut}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], r) {
+			return
+		}
+	}
+	expr.Root.Resources = append(expr.Root.Resources, r)
+}
+
+// ID sets the name of the attribute holding the resource ID, "id" by default.
+// The admin UI uses the ID to build the path of the show endpoint.
+//
+// ID must appear in a Resource expression.
+func ID(name string) {
+	if r, ok := eval.Current().(*expr.ResourceExpr); ok {
+		r.ID = name
+		return
+	}
+	eval.IncompatibleDSL()
+}
@@ -0,0 +1,121 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// ResourceExpr describes a user type administered by the admin UI.
+	ResourceExpr struct {
+		// Title is the title of the resource in the admin UI, e.g.
+		// "Articles".
+		Title string
+		// ID is the name of the attribute holding the resource ID.
+		ID string
+		// UserType is the user type describing the resource.
+		UserType expr.UserType
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *ResourceExpr) EvalName() string {
+	return fmt.Sprintf("admin resource %q of type %q", r.Title, r.UserType.Name())
+}
+
+// Validate makes sure the resource is an object with an ID attribute and that
+// an HTTP endpoint lists it.
+func (r *ResourceExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if r.Title == "" {
+		verr.Add(r, "resource title cannot be empty")
+	}
+	for _, other := range Root.Resources {
+		if other != r && other.UserType.Name() == r.UserType.Name() {
+			verr.Add(r, "type %q is declared as an admin resource more than once", r.UserType.Name())
+		}
+	}
+	obj := expr.AsObject(r.UserType.Attribute().Type)
+	if obj == nil {
+		verr.Add(r, "resource type must be an object")
+		return verr
+	}
+	if att := obj.Attribute(r.ID); att == nil {
+		verr.Add(r, "ID attribute %q not found", r.ID)
+	} else if !isPrimitive(att.Type) {
+		verr.Add(r, "ID attribute %q must be a primitive", r.ID)
+	}
+	if r.List() == nil {
+		verr.Add(r, "no HTTP GET endpoint returns an array of %q", r.UserType.Name())
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// List returns the HTTP endpoint listing the resources: the first GET
+// endpoint whose method result is an array or a collection of the resource
+// type. It returns nil if there is none.
+func (r *ResourceExpr) List() *expr.HTTPEndpointExpr {
+	return r.endpoint("GET", func(m *expr.MethodExpr) bool {
+		arr := expr.AsArray(m.Result.Type)
+		return arr != nil && r.is(arr.ElemType.Type)
+	})
+}
+
+// Show returns the HTTP endpoint returning a single resource: the first GET
+// endpoint whose method result is the resource type and whose path has a
+// parameter. It returns nil if there is none.
+func (r *ResourceExpr) Show() *expr.HTTPEndpointExpr {
+	e := r.endpoint("GET", func(m *expr.MethodExpr) bool {
+		return r.is(m.Result.Type)
+	})
+	if e == nil || len(e.Routes[0].Params()) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Create returns the HTTP endpoint creating a resource: the first POST
+// endpoint whose method result is the resource type. It returns nil if there
+// is none.
+func (r *ResourceExpr) Create() *expr.HTTPEndpointExpr {
+	return r.endpoint("POST", func(m *expr.MethodExpr) bool {
+		return r.is(m.Result.Type)
+	})
+}
+
+// endpoint returns the first non streaming HTTP endpoint of the design whose
+// first route uses the given HTTP method and whose method satisfies match.
+func (r *ResourceExpr) endpoint(method string, match func(*expr.MethodExpr) bool) *expr.HTTPEndpointExpr {
+	for _, svc := range expr.Root.API.HTTP.Services {
+		for _, e := range svc.HTTPEndpoints {
+			m := e.MethodExpr
+			if len(e.Routes) == 0 || e.Routes[0].Method != method || m.IsStreaming() || m.Result == nil {
+				continue
+			}
+			if match(m) {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// is returns true if t is the resource type.
+func (r *ResourceExpr) is(t expr.DataType) bool {
+	ut, ok := t.(expr.UserType)
+	return ok && ut.Name() == r.UserType.Name()
+}
+
+// isPrimitive returns true if t is a primitive type or an alias of one.
+func isPrimitive(t expr.DataType) bool {
+	if ut, ok := t.(expr.UserType); ok {
+		return isPrimitive(ut.Attribute().Type)
+	}
+	_, ok := t.(expr.Primitive)
+	return ok
+}
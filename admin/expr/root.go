@@ -0,0 +1,49 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the resources administered by the admin UI.
+	RootExpr struct {
+		// Resources lists the resources in the order they appear in
+		// the design.
+		Resources []*ResourceExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "admin plugin"
+}
+
+// WalkSets iterates over the resources.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	rexps := make(eval.ExpressionSet, len(r.Resources))
+	for i, res := range r.Resources {
+		rexps[i] = res
+	}
+	walk(rexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/admin/dsl"}
+}
@@ -0,0 +1,278 @@
+package admin
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/admin/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// resourceData contains the data necessary to render the description
+	// of a resource.
+	resourceData struct {
+		// Name is the name of the resource type.
+		Name string
+		// Title is the title of the resource.
+		Title string
+		// ID is the name of the ID field.
+		ID string
+		// Fields lists the fields of the resource.
+		Fields []*fieldData
+		// List describes the endpoint listing the resources.
+		List *endpointData
+		// Show describes the endpoint returning a single resource if
+		// any.
+		Show *endpointData
+		// Create describes the endpoint creating a resource if any.
+		Create *endpointData
+	}
+
+	// fieldData contains the data necessary to render the description of
+	// a field.
+	fieldData struct {
+		// Name is the JSON name of the field.
+		Name string
+		// Type is the JSON schema type of the field.
+		Type string
+		// Description describes the field.
+		Description string
+		// Required is true if the field is required.
+		Required bool
+	}
+
+	// endpointData contains the data necessary to render the description
+	// of an endpoint.
+	endpointData struct {
+		// Method is the HTTP method.
+		Method string
+		// Path is the request path.
+		Path string
+		// Fields lists the fields of the request body.
+		Fields []*fieldData
+		// Security lists the security schemes of the endpoint.
+		Security []*schemeData
+	}
+
+	// schemeData contains the data necessary to render the description of
+	// a security scheme.
+	schemeData struct {
+		// SchemeName is the name of the scheme.
+		SchemeName string
+		// Kind is the kind of scheme.
+		Kind string
+		// In is the location of the credentials.
+		In string
+		// Name is the name of the header or query string parameter.
+		Name string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "admin",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the file defining the resources administered by the admin
+// UI and the function mounting the UI.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Resources) == 0 {
+		return files, nil
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			files = append(files, adminFile(r))
+		}
+	}
+	return files, nil
+}
+
+// adminFile returns the file defining the admin UI resources and the function
+// mounting the UI.
+func adminFile(r *goaexpr.RootExpr) *codegen.File {
+	resources := make([]*resourceData, len(expr.Root.Resources))
+	for i, res := range expr.Root.Resources {
+		resources[i] = &resourceData{
+			Name:   res.UserType.Name(),
+			Title:  res.Title,
+			ID:     res.ID,
+			Fields: fields(res.UserType.Attribute()),
+			List:   endpoint(res.List(), false),
+			Show:   endpoint(res.Show(), false),
+			Create: endpoint(res.Create(), true),
+		}
+	}
+	title := r.API.Title
+	if title == "" {
+		title = r.API.Name
+	}
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "http", "admin", "admin.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header("Admin UI", "admin", []*codegen.ImportSpec{
+				{Path: "net/http"},
+				codegen.GoaNamedImport("http", "goahttp"),
+				{Path: "goa.design/plugins/v3/admin"},
+			}),
+			{Name: "admin-resources", Source: resourcesT, Data: resources},
+			{Name: "admin-mount", Source: mountT, Data: title},
+		},
+	}
+}
+
+// endpoint returns the data describing the given HTTP endpoint, nil if e is
+// nil or if the plugin is turned off for its service. The data lists the
+// fields of the request body if body is true.
+func endpoint(e *goaexpr.HTTPEndpointExpr, body bool) *endpointData {
+	if e == nil || !config.Enabled("admin", e.Service.Name()) {
+		return nil
+	}
+	d := &endpointData{
+		Method: e.Routes[0].Method,
+		Path:   e.Routes[0].FullPaths()[0],
+	}
+	if body && e.Body != nil {
+		d.Fields = fields(e.Body)
+	}
+	if len(e.Requirements) > 0 {
+		for _, s := range e.Requirements[0].Schemes {
+			kind := schemeKind(s.Kind)
+			if kind == "" {
+				continue
+			}
+			d.Security = append(d.Security, &schemeData{
+				SchemeName: s.SchemeName,
+				Kind:       kind,
+				In:         s.In,
+				Name:       s.Name,
+			})
+		}
+	}
+	return d
+}
+
+// fields returns the data describing the attributes of the given object
+// attribute, nil if the attribute is not an object.
+func fields(att *goaexpr.AttributeExpr) []*fieldData {
+	if ut, ok := att.Type.(goaexpr.UserType); ok {
+		att = ut.Attribute()
+	}
+	obj := goaexpr.AsObject(att.Type)
+	if obj == nil {
+		return nil
+	}
+	fs := make([]*fieldData, len(*obj))
+	for i, nat := range *obj {
+		fs[i] = &fieldData{
+			Name:        nat.Name,
+			Type:        jsonType(nat.Attribute.Type),
+			Description: nat.Attribute.Description,
+			Required:    att.IsRequired(nat.Name),
+		}
+	}
+	return fs
+}
+
+// jsonType returns the JSON schema type of the given data type.
+func jsonType(t goaexpr.DataType) string {
+	if ut, ok := t.(goaexpr.UserType); ok {
+		return jsonType(ut.Attribute().Type)
+	}
+	switch t.Kind() {
+	case goaexpr.BooleanKind:
+		return "boolean"
+	case goaexpr.IntKind, goaexpr.Int32Kind, goaexpr.Int64Kind,
+		goaexpr.UIntKind, goaexpr.UInt32Kind, goaexpr.UInt64Kind:
+		return "integer"
+	case goaexpr.Float32Kind, goaexpr.Float64Kind:
+		return "number"
+	case goaexpr.StringKind, goaexpr.BytesKind:
+		return "string"
+	case goaexpr.ArrayKind:
+		return "array"
+	case goaexpr.ObjectKind, goaexpr.MapKind:
+		return "object"
+	default:
+		return "any"
+	}
+}
+
+// schemeKind returns the name of the given security scheme kind used by the
+// admin UI, the empty string for NoKind.
+func schemeKind(k goaexpr.SchemeKind) string {
+	switch k {
+	case goaexpr.BasicAuthKind:
+		return "basic"
+	case goaexpr.APIKeyKind:
+		return "apikey"
+	case goaexpr.JWTKind:
+		return "jwt"
+	case goaexpr.OAuth2Kind:
+		return "oauth2"
+	default:
+		return ""
+	}
+}
+
+// input: []*resourceData
+const resourcesT = `{{ define "fields" }}[]*admin.Field{
+{{- range . }}
+	{Name: {{ printf "%q" .Name }}, Type: {{ printf "%q" .Type }}{{ if .Description }}, Description: {{ printf "%q" .Description }}{{ end }}{{ if .Required }}, Required: true{{ end }}},
+{{- end }}
+}{{ end }}
+{{- define "endpoint" }}&admin.Endpoint{
+	Method: {{ printf "%q" .Method }},
+	Path:   {{ printf "%q" .Path }},
+{{- if .Fields }}
+	Fields: {{ template "fields" .Fields }},
+{{- end }}
+{{- if .Security }}
+	Security: []*admin.Scheme{
+	{{- range .Security }}
+		{SchemeName: {{ printf "%q" .SchemeName }}, Kind: {{ printf "%q" .Kind }}, In: {{ printf "%q" .In }}, Name: {{ printf "%q" .Name }}},
+	{{- end }}
+	},
+{{- end }}
+}{{ end -}}
+// Resources lists the resources administered by the admin UI.
+var Resources = []*admin.Resource{
+{{- range . }}
+	{
+		Name:   {{ printf "%q" .Name }},
+		Title:  {{ printf "%q" .Title }},
+		ID:     {{ printf "%q" .ID }},
+		Fields: {{ template "fields" .Fields }},
+		List:   {{ template "endpoint" .List }},
+	{{- if .Show }}
+		Show:   {{ template "endpoint" .Show }},
+	{{- end }}
+	{{- if .Create }}
+		Create: {{ template "endpoint" .Create }},
+	{{- end }}
+	},
+{{- end }}
+}
+`
+
+// input: string
+const mountT = `// Mount configures the mux to serve the admin UI under /admin. The handler
+// serving the UI is wrapped with the given middlewares, the first one being
+// the outermost. The resource endpoints called by the UI enforce their own
+// security schemes.
+func Mount(mux goahttp.Muxer, middlewares ...func(http.Handler) http.Handler) {
+	h := admin.Handler("/admin", {{ printf "%q" . }}, Resources)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	mux.Handle("GET", "/admin", h.ServeHTTP)
+	mux.Handle("GET", "/admin/{*path}", h.ServeHTTP)
+}
+`
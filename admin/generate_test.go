@@ -0,0 +1,68 @@
+package admin_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/admin"
+	"goa.design/plugins/v3/admin/expr"
+	"goa.design/plugins/v3/admin/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name      string
+		DSL       func()
+		Resources string
+	}{
+		{"blog", testdata.BlogDSL, testdata.BlogResourcesCode},
+		{"slug", testdata.SlugDSL, testdata.SlugResourcesCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Resources = nil
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs, err := admin.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			if fs[0].Path != "gen/http/admin/admin.go" {
+				t.Errorf("got path %q, expected gen/http/admin/admin.go", fs[0].Path)
+			}
+			code := codegen.SectionCode(t, fs[0].Section("admin-resources")[0])
+			if code != c.Resources {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Resources))
+			}
+		})
+	}
+}
+
+func TestGenerateMount(t *testing.T) {
+	expr.Root.Resources = nil
+	httpcodegen.RunHTTPDSL(t, testdata.BlogDSL)
+	fs, err := admin.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := codegen.SectionCode(t, fs[0].Section("admin-mount")[0])
+	if code != testdata.BlogMountCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.BlogMountCode))
+	}
+}
+
+func TestGenerateNoResource(t *testing.T) {
+	expr.Root.Resources = nil
+	fs, err := admin.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
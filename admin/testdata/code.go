@@ -0,0 +1,73 @@
+package testdata
+
+const BlogResourcesCode = `// Resources lists the resources administered by the admin UI.
+var Resources = []*admin.Resource{
+	{
+		Name:  "Article",
+		Title: "Articles",
+		ID:    "id",
+		Fields: []*admin.Field{
+			{Name: "id", Type: "integer", Description: "Article ID", Required: true},
+			{Name: "title", Type: "string", Required: true},
+			{Name: "tags", Type: "array"},
+		},
+		List: &admin.Endpoint{
+			Method: "GET",
+			Path:   "/articles",
+			Security: []*admin.Scheme{
+				{SchemeName: "jwt", Kind: "jwt", In: "header", Name: "Authorization"},
+			},
+		},
+		Show: &admin.Endpoint{
+			Method: "GET",
+			Path:   "/articles/{id}",
+			Security: []*admin.Scheme{
+				{SchemeName: "jwt", Kind: "jwt", In: "header", Name: "Authorization"},
+			},
+		},
+		Create: &admin.Endpoint{
+			Method: "POST",
+			Path:   "/articles",
+			Fields: []*admin.Field{
+				{Name: "title", Type: "string", Description: "Article title", Required: true},
+				{Name: "draft", Type: "boolean"},
+			},
+			Security: []*admin.Scheme{
+				{SchemeName: "jwt", Kind: "jwt", In: "header", Name: "Authorization"},
+			},
+		},
+	},
+}
+`
+
+const BlogMountCode = `// Mount configures the mux to serve the admin UI under /admin. The handler
+// serving the UI is wrapped with the given middlewares, the first one being
+// the outermost. The resource endpoints called by the UI enforce their own
+// security schemes.
+func Mount(mux goahttp.Muxer, middlewares ...func(http.Handler) http.Handler) {
+	h := admin.Handler("/admin", "Blog API", Resources)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	mux.Handle("GET", "/admin", h.ServeHTTP)
+	mux.Handle("GET", "/admin/{*path}", h.ServeHTTP)
+}
+`
+
+const SlugResourcesCode = `// Resources lists the resources administered by the admin UI.
+var Resources = []*admin.Resource{
+	{
+		Name:  "Page",
+		Title: "Pages",
+		ID:    "slug",
+		Fields: []*admin.Field{
+			{Name: "slug", Type: "string"},
+			{Name: "weight", Type: "number"},
+		},
+		List: &admin.Endpoint{
+			Method: "GET",
+			Path:   "/pages",
+		},
+	},
+}
+`
@@ -0,0 +1,85 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	admin "goa.design/plugins/v3/admin/dsl"
+)
+
+var BlogDSL = func() {
+	API("blog", func() {
+		Title("Blog API")
+	})
+	var JWTAuth = JWTSecurity("jwt")
+	var Article = ResultType("application/vnd.article", func() {
+		admin.Resource("Articles")
+		Attributes(func() {
+			Attribute("id", Int, "Article ID")
+			Attribute("title", String)
+			Attribute("tags", ArrayOf(String))
+			Required("id", "title")
+		})
+	})
+	Service("blog", func() {
+		Security(JWTAuth)
+		Method("list", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(CollectionOf(Article))
+			HTTP(func() {
+				GET("/articles")
+			})
+		})
+		Method("show", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("id", Int)
+			})
+			Result(Article)
+			HTTP(func() {
+				GET("/articles/{id}")
+			})
+		})
+		Method("create", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("title", String, "Article title")
+				Attribute("draft", Boolean)
+				Required("title")
+			})
+			Result(Article)
+			HTTP(func() {
+				POST("/articles")
+				Response(StatusCreated)
+			})
+		})
+	})
+}
+
+var SlugDSL = func() {
+	var Page = Type("Page", func() {
+		admin.Resource("Pages", func() {
+			admin.ID("slug")
+		})
+		Attribute("slug", String)
+		Attribute("weight", Float64)
+	})
+	Service("pages", func() {
+		HTTP(func() {
+			Path("/pages")
+		})
+		Method("list", func() {
+			Result(ArrayOf(Page))
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("update", func() {
+			Payload(Page)
+			Result(Page)
+			HTTP(func() {
+				PUT("/{slug}")
+			})
+		})
+	})
+}
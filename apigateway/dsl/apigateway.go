@@ -0,0 +1,222 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/apigateway/expr"
+
+	// Register code generators for the API Gateway plugin
+	_ "goa.design/plugins/v3/apigateway"
+)
+
+// Lambda forwards the requests to the Lambda function with the given ARN using
+// the Lambda proxy integration.
+//
+// Lambda may appear in an API, Service or Method expression. Method level
+// integrations override service level integrations which override the API
+// level integration.
+//
+// Lambda accepts an optional DSL function as last argument.
+//
+// Example:
+//
+//    import apigateway "goa.design/plugins/v3/apigateway/dsl"
+//
+//    var _ = Service("calc", func() {
+//        apigateway.Lambda("arn:aws:lambda:us-east-1:123456789012:function:calc", func() {
+//            apigateway.Timeout(10000)
+//        })
+//    })
+//
+func Lambda(arn string, fn ...func()) {
+	integration(expr.LambdaProxyKind, arn, fn)
+}
+
+// HTTPProxy forwards the requests to the HTTP backend with the given base URL.
+// The request path is appended to the base URL and the path parameters are
+// forwarded.
+//
+// HTTPProxy may appear in an API, Service or Method expression.
+//
+// HTTPProxy accepts an optional DSL function as last argument.
+//
+// Example:
+//
+//    var _ = API("calc", func() {
+//        apigateway.HTTPProxy("https://calc.internal.example.com")
+//    })
+//
+func HTTPProxy(url string, fn ...func()) {
+	integration(expr.HTTPProxyKind, url, fn)
+}
+
+// Timeout sets the integration timeout in milliseconds, between 50 and 29000
+// (the default).
+//
+// Timeout must appear in a Lambda or HTTPProxy expression.
+//
+// Example:
+//
+//    Lambda("arn:aws:lambda:us-east-1:123456789012:function:calc", func() {
+//        Timeout(10000)
+//    })
+//
+func Timeout(ms int) {
+	switch i := eval.Current().(type) {
+	case *expr.IntegrationExpr:
+		i.Timeout = ms
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Authorizer uses the Lambda function with the given ARN to authorize the
+// requests made to the operations secured with the given security scheme.
+// scheme is the security scheme expression returned by the goa security DSL
+// (e.g. JWTSecurity) or its name.
+//
+// Authorizer must appear in an API expression.
+//
+// Authorizer accepts an optional DSL function as last argument.
+//
+// Example:
+//
+//    var JWTAuth = JWTSecurity("jwt")
+//
+//    var _ = API("calc", func() {
+//        apigateway.Authorizer(JWTAuth, "arn:aws:lambda:us-east-1:123456789012:function:auth", func() {
+//            apigateway.TTL(60)
+//        })
+//    })
+//
+func Authorizer(scheme interface{}, arn string, fn ...func()) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	var name string
+	switch s := scheme.(type) {
+	case string:
+		name = s
+	case *goaexpr.SchemeExpr:
+		name = s.SchemeName
+	default:
+		eval.InvalidArgError("security scheme or name", scheme)
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	if expr.Root.Authorizer(name) != nil {
+		eval.ReportError("authorizer of security scheme %q defined twice", name)
+		return
+	}
+	a := &expr.AuthorizerExpr{
+		Scheme:      name,
+		FunctionARN: arn,
+		TTL:         expr.DefaultAuthorizerTTL,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], a) {
+			return
+		}
+	}
+	expr.Root.Authorizers = append(expr.Root.Authorizers, a)
+}
+
+// RequestAuthorizer makes the authorizer receive the request headers, query
+// string and path parameters instead of the token only.
+//
+// RequestAuthorizer must appear in an Authorizer expression.
+//
+// Example:
+//
+//    Authorizer(APIKeyAuth, "arn:aws:lambda:us-east-1:123456789012:function:auth", func() {
+//        RequestAuthorizer()
+//        IdentitySource("method.request.header.X-Api-Key", "method.request.querystring.tenant")
+//    })
+//
+func RequestAuthorizer() {
+	switch a := eval.Current().(type) {
+	case *expr.AuthorizerExpr:
+		a.Request = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// IdentitySource sets the request elements used as identity by the
+// authorizer. It defaults to the header or query string parameter of the
+// security scheme.
+//
+// IdentitySource must appear in an Authorizer expression.
+//
+// Example:
+//
+//    Authorizer(JWTAuth, "arn:aws:lambda:us-east-1:123456789012:function:auth", func() {
+//        IdentitySource("method.request.header.Authorization")
+//    })
+//
+func IdentitySource(sources ...string) {
+	switch a := eval.Current().(type) {
+	case *expr.AuthorizerExpr:
+		a.IdentitySource = append(a.IdentitySource, sources...)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// TTL sets the duration in seconds the authorizer results are cached, between
+// 0 (no caching) and 3600. The default is 300.
+//
+// TTL must appear in an Authorizer expression.
+//
+// Example:
+//
+//    Authorizer(JWTAuth, "arn:aws:lambda:us-east-1:123456789012:function:auth", func() {
+//        TTL(60)
+//    })
+//
+func TTL(seconds int) {
+	switch a := eval.Current().(type) {
+	case *expr.AuthorizerExpr:
+		a.TTL = seconds
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// integration adds the integration of the current API, service or method.
+func integration(kind expr.IntegrationKind, uri string, fn []func()) {
+	var parent eval.Expression
+	switch e := eval.Current().(type) {
+	case *goaexpr.APIExpr, *goaexpr.ServiceExpr, *goaexpr.MethodExpr:
+		parent = e
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	for _, in := range expr.Root.Integrations {
+		if in.Parent == parent {
+			eval.ReportError("integration defined twice")
+			return
+		}
+	}
+	i := &expr.IntegrationExpr{
+		Kind:    kind,
+		URI:     uri,
+		Timeout: expr.DefaultTimeout,
+		Parent:  parent,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], i) {
+			return
+		}
+	}
+	expr.Root.Integrations = append(expr.Root.Integrations, i)
+}
@@ -0,0 +1,139 @@
+package expr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// IntegrationKind is the kind of API Gateway integration.
+type IntegrationKind string
+
+const (
+	// LambdaProxyKind forwards the requests to a Lambda function using the
+	// Lambda proxy integration.
+	LambdaProxyKind IntegrationKind = "aws_proxy"
+	// HTTPProxyKind forwards the requests to an HTTP backend.
+	HTTPProxyKind IntegrationKind = "http_proxy"
+)
+
+const (
+	// DefaultTimeout is the default integration timeout in milliseconds.
+	DefaultTimeout = 29000
+	// DefaultAuthorizerTTL is the default authorizer result cache duration
+	// in seconds.
+	DefaultAuthorizerTTL = 300
+)
+
+type (
+	// IntegrationExpr describes the API Gateway integration of the methods
+	// of the API, a service or a single method.
+	IntegrationExpr struct {
+		// Kind is the integration kind.
+		Kind IntegrationKind
+		// URI is the Lambda function ARN or the base URL of the HTTP
+		// backend.
+		URI string
+		// Timeout is the integration timeout in milliseconds.
+		Timeout int
+		// Parent is the API, service or method expression.
+		Parent eval.Expression
+	}
+
+	// AuthorizerExpr describes the Lambda authorizer of a security scheme.
+	AuthorizerExpr struct {
+		// Scheme is the name of the security scheme.
+		Scheme string
+		// FunctionARN is the ARN of the authorizer Lambda function.
+		FunctionARN string
+		// Request is true if the authorizer receives the whole request
+		// instead of the token only.
+		Request bool
+		// IdentitySource lists the request elements used as identity
+		// (e.g. "method.request.header.Authorization").
+		IdentitySource []string
+		// TTL is the duration in seconds the authorizer results are
+		// cached.
+		TTL int
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (i *IntegrationExpr) EvalName() string {
+	var suffix string
+	if i.Parent != nil {
+		suffix = " of " + i.Parent.EvalName()
+	}
+	return "API Gateway integration" + suffix
+}
+
+// Validate ensures the integration expression is valid.
+func (i *IntegrationExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	switch i.Kind {
+	case LambdaProxyKind:
+		if Region(i.URI) == "" {
+			verr.Add(i, "invalid Lambda function ARN %q", i.URI)
+		}
+	case HTTPProxyKind:
+		if u, err := url.Parse(i.URI); err != nil || u.Scheme == "" || u.Host == "" {
+			verr.Add(i, "invalid backend URL %q", i.URI)
+		}
+	}
+	if i.Timeout < 50 || i.Timeout > DefaultTimeout {
+		verr.Add(i, "timeout must be between 50 and %d milliseconds", DefaultTimeout)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (a *AuthorizerExpr) EvalName() string {
+	return fmt.Sprintf("API Gateway authorizer of security scheme %q", a.Scheme)
+}
+
+// Validate ensures the authorizer expression is valid.
+func (a *AuthorizerExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	var found bool
+	for _, s := range expr.Root.Schemes {
+		if s.SchemeName == a.Scheme {
+			found = true
+			break
+		}
+	}
+	if !found {
+		verr.Add(a, "security scheme %q is not defined", a.Scheme)
+	}
+	if Region(a.FunctionARN) == "" {
+		verr.Add(a, "invalid Lambda function ARN %q", a.FunctionARN)
+	}
+	if a.TTL < 0 || a.TTL > 3600 {
+		verr.Add(a, "TTL must be between 0 and 3600 seconds")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Region returns the AWS region of the given Lambda function ARN, empty if
+// arn is not a valid Lambda function ARN.
+func Region(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 || parts[0] != "arn" || parts[2] != "lambda" || parts[5] != "function" {
+		return ""
+	}
+	return parts[3]
+}
+
+// InvocationURI returns the API Gateway URI used to invoke the Lambda function
+// with the given ARN.
+func InvocationURI(arn string) string {
+	return fmt.Sprintf("arn:aws:apigateway:%s:lambda:path/2015-03-31/functions/%s/invocations", Region(arn), arn)
+}
@@ -0,0 +1,95 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the API Gateway integrations and authorizers
+	// defined in the design.
+	RootExpr struct {
+		// Integrations lists the integrations in the order they appear
+		// in the design.
+		Integrations []*IntegrationExpr
+		// Authorizers lists the Lambda authorizers in the order they
+		// appear in the design.
+		Authorizers []*AuthorizerExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "API Gateway plugin"
+}
+
+// WalkSets iterates over the integrations and authorizers.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	iexps := make(eval.ExpressionSet, len(r.Integrations))
+	for i, in := range r.Integrations {
+		iexps[i] = in
+	}
+	walk(iexps)
+	aexps := make(eval.ExpressionSet, len(r.Authorizers))
+	for i, a := range r.Authorizers {
+		aexps[i] = a
+	}
+	walk(aexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/apigateway/dsl"}
+}
+
+// Integration returns the integration of the given method. Method level
+// integrations override service level integrations which override the API
+// level integration. Integration returns nil if the method has no
+// integration.
+func (r *RootExpr) Integration(m *expr.MethodExpr) *IntegrationExpr {
+	var svc, api *IntegrationExpr
+	for _, in := range r.Integrations {
+		switch p := in.Parent.(type) {
+		case *expr.MethodExpr:
+			if p == m {
+				return in
+			}
+		case *expr.ServiceExpr:
+			if p == m.Service {
+				svc = in
+			}
+		case *expr.APIExpr:
+			api = in
+		}
+	}
+	if svc != nil {
+		return svc
+	}
+	return api
+}
+
+// Authorizer returns the authorizer of the given security scheme, nil if
+// there isn't one.
+func (r *RootExpr) Authorizer(scheme string) *AuthorizerExpr {
+	for _, a := range r.Authorizers {
+		if a.Scheme == scheme {
+			return a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package apigateway
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/apigateway/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+	"goa.design/plugins/v3/walk"
+)
+
+const (
+	// integrationExt is the name of the OpenAPI extension describing the
+	// integration of an operation.
+	integrationExt = "x-amazon-apigateway-integration"
+	// authTypeExt is the name of the OpenAPI extension describing the type
+	// of authorizer of a security definition.
+	authTypeExt = "x-amazon-apigateway-authtype"
+	// authorizerExt is the name of the OpenAPI extension describing the
+	// authorizer of a security definition.
+	authorizerExt = "x-amazon-apigateway-authorizer"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "apigateway",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the OpenAPI specification enriched with the API Gateway
+// extensions describing the integrations and authorizers.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("apigateway", "") {
+		return files, nil
+	}
+	if len(expr.Root.Integrations) == 0 && len(expr.Root.Authorizers) == 0 {
+		return files, nil
+	}
+	root := goaexpr.Root
+	if len(root.API.HTTP.Services) == 0 {
+		return files, nil
+	}
+	// Build a new specification so that the extensions do not leak into
+	// the OpenAPI files generated by goa.
+	spec, err := openapi.NewV2(root, root.API.Servers[0].Hosts[0])
+	if err != nil {
+		return nil, err
+	}
+	addIntegrations(spec)
+	addAuthorizers(spec)
+	path := filepath.Join(codegen.Gendir, "http", "openapi_apigateway.json")
+	return append(files, stream.File(path, "apigateway-openapi", spec, stream.JSON)), nil
+}
+
+// addIntegrations sets the integration extension of the operations whose
+// method has an integration.
+func addIntegrations(spec *openapi.V2) {
+	walk.Operations(spec, func(path, verb string, op *openapi.Operation) error {
+		if !config.EnabledOperation("apigateway", op) {
+			return nil
+		}
+		m := method(op)
+		if m == nil {
+			return nil
+		}
+		in := expr.Root.Integration(m)
+		if in == nil {
+			return nil
+		}
+		if op.Extensions == nil {
+			op.Extensions = make(map[string]interface{})
+		}
+		op.Extensions[integrationExt] = integration(in, strings.ToUpper(verb), path, op)
+		return nil
+	})
+}
+
+// integration returns the integration extension of an operation.
+func integration(in *expr.IntegrationExpr, verb, path string, op *openapi.Operation) map[string]interface{} {
+	ext := map[string]interface{}{
+		"type":                string(in.Kind),
+		"passthroughBehavior": "when_no_match",
+		"timeoutInMillis":     in.Timeout,
+	}
+	switch in.Kind {
+	case expr.LambdaProxyKind:
+		ext["httpMethod"] = "POST" // Lambda functions are always invoked with POST
+		ext["uri"] = expr.InvocationURI(in.URI)
+	case expr.HTTPProxyKind:
+		ext["httpMethod"] = verb
+		ext["uri"] = strings.TrimSuffix(in.URI, "/") + path
+		params := make(map[string]interface{})
+		for _, p := range op.Parameters {
+			if p.In == "path" {
+				params["integration.request.path."+p.Name] = "method.request.path." + p.Name
+			}
+		}
+		if len(params) > 0 {
+			ext["requestParameters"] = params
+		}
+	}
+	return ext
+}
+
+// addAuthorizers sets the authorizer extensions of the security definitions
+// whose scheme has an authorizer.
+func addAuthorizers(spec *openapi.V2) {
+	for key, sd := range spec.SecurityDefinitions {
+		// goa uses the scheme name, location and parameter name as key.
+		var a *expr.AuthorizerExpr
+		for _, auth := range expr.Root.Authorizers {
+			if strings.HasPrefix(key, auth.Scheme+"_") {
+				a = auth
+				break
+			}
+		}
+		if a == nil {
+			continue
+		}
+		authorize(sd, a)
+	}
+}
+
+// authorize turns the security definition into an API key definition using
+// the given Lambda authorizer. API Gateway only supports API key security
+// definitions for custom authorizers.
+func authorize(sd *openapi.SecurityDefinition, a *expr.AuthorizerExpr) {
+	in, name := sd.In, sd.Name
+	if sd.Type != "apiKey" || name == "" {
+		in, name = "header", "Authorization"
+	}
+	ext := sd.Extensions
+	if ext == nil {
+		ext = make(map[string]interface{})
+	}
+	*sd = openapi.SecurityDefinition{
+		Type:        "apiKey",
+		Description: sd.Description,
+		Name:        name,
+		In:          in,
+		Extensions:  ext,
+	}
+	kind := "token"
+	if a.Request {
+		kind = "request"
+	}
+	source := a.IdentitySource
+	if len(source) == 0 {
+		loc := "header"
+		if in == "query" {
+			loc = "querystring"
+		}
+		source = []string{fmt.Sprintf("method.request.%s.%s", loc, name)}
+	}
+	ext[authTypeExt] = "custom"
+	ext[authorizerExt] = map[string]interface{}{
+		"type":                         kind,
+		"authorizerUri":                expr.InvocationURI(a.FunctionARN),
+		"authorizerResultTtlInSeconds": a.TTL,
+		"identitySource":               strings.Join(source, ", "),
+	}
+}
+
+// method returns the method corresponding to the given operation, nil if the
+// operation does not correspond to a method (e.g. file servers).
+func method(op *openapi.Operation) *goaexpr.MethodExpr {
+	return walk.OperationMethod(goaexpr.Root, op)
+}
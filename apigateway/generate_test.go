@@ -0,0 +1,101 @@
+package apigateway_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/apigateway"
+	"goa.design/plugins/v3/apigateway/expr"
+	"goa.design/plugins/v3/apigateway/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	expr.Root.Integrations = nil
+	expr.Root.Authorizers = nil
+	codegen.RunDSLWithFunc(t, testdata.IntegrationsDSL, func() {
+		eval.Register(expr.Root)
+	})
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := apigateway.Generate("", []eval.Root{goaexpr.Root}, ofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 3 {
+		t.Fatalf("got %d files, expected 3", len(fs))
+	}
+	if _, ok := ofs[0].SectionTemplates[0].Data.(*openapi.V2).Paths["/add/{a}/{b}"].(*openapi.Path).Get.Extensions["x-amazon-apigateway-integration"]; ok {
+		t.Error("integration extension leaked into the goa OpenAPI specification")
+	}
+	spec := fs[2].SectionTemplates[0].Data.(*openapi.V2)
+
+	add := spec.Paths["/add/{a}/{b}"].(*openapi.Path).Get
+	assertJSON(t, "add integration", add.Extensions["x-amazon-apigateway-integration"], `{
+		"type": "http_proxy",
+		"httpMethod": "GET",
+		"uri": "https://calc.internal.example.com/add/{a}/{b}",
+		"passthroughBehavior": "when_no_match",
+		"timeoutInMillis": 29000,
+		"requestParameters": {
+			"integration.request.path.a": "method.request.path.a",
+			"integration.request.path.b": "method.request.path.b"
+		}
+	}`)
+	reset := spec.Paths["/reset"].(*openapi.Path).Post
+	assertJSON(t, "reset integration", reset.Extensions["x-amazon-apigateway-integration"], `{
+		"type": "aws_proxy",
+		"httpMethod": "POST",
+		"uri": "arn:aws:apigateway:eu-west-1:lambda:path/2015-03-31/functions/arn:aws:lambda:eu-west-1:123456789012:function:reset/invocations",
+		"passthroughBehavior": "when_no_match",
+		"timeoutInMillis": 5000
+	}`)
+
+	jwt := spec.SecurityDefinitions["jwt_header_Authorization"]
+	if jwt.Extensions["x-amazon-apigateway-authtype"] != "custom" {
+		t.Errorf("invalid jwt auth type %v", jwt.Extensions["x-amazon-apigateway-authtype"])
+	}
+	assertJSON(t, "jwt authorizer", jwt.Extensions["x-amazon-apigateway-authorizer"], `{
+		"type": "token",
+		"authorizerUri": "arn:aws:apigateway:us-east-1:lambda:path/2015-03-31/functions/arn:aws:lambda:us-east-1:123456789012:function:jwt-auth/invocations",
+		"authorizerResultTtlInSeconds": 60,
+		"identitySource": "method.request.header.Authorization"
+	}`)
+	basic := spec.SecurityDefinitions["basic_header_Authorization"]
+	if basic.Type != "apiKey" || basic.In != "header" || basic.Name != "Authorization" {
+		t.Errorf("got basic security definition %s %s %s, expected apiKey header Authorization", basic.Type, basic.In, basic.Name)
+	}
+	assertJSON(t, "basic authorizer", basic.Extensions["x-amazon-apigateway-authorizer"], `{
+		"type": "request",
+		"authorizerUri": "arn:aws:apigateway:us-east-1:lambda:path/2015-03-31/functions/arn:aws:lambda:us-east-1:123456789012:function:basic-auth/invocations",
+		"authorizerResultTtlInSeconds": 300,
+		"identitySource": "method.request.header.Authorization, method.request.querystring.tenant"
+	}`)
+}
+
+// assertJSON compares the JSON representation of v with expected.
+func assertJSON(t *testing.T, name string, v interface{}, expected string) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, exp interface{}
+	json.Unmarshal(b, &got)
+	if err := json.Unmarshal([]byte(expected), &exp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("invalid %s, got %s", name, b)
+	}
+}
@@ -0,0 +1,48 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	apigateway "goa.design/plugins/v3/apigateway/dsl"
+)
+
+var IntegrationsDSL = func() {
+	var JWTAuth = JWTSecurity("jwt")
+	var BasicAuth = BasicAuthSecurity("basic")
+	API("calc", func() {
+		apigateway.HTTPProxy("https://calc.internal.example.com/")
+		apigateway.Authorizer(JWTAuth, "arn:aws:lambda:us-east-1:123456789012:function:jwt-auth", func() {
+			apigateway.TTL(60)
+		})
+		apigateway.Authorizer("basic", "arn:aws:lambda:us-east-1:123456789012:function:basic-auth", func() {
+			apigateway.RequestAuthorizer()
+			apigateway.IdentitySource("method.request.header.Authorization", "method.request.querystring.tenant")
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Security(JWTAuth)
+			Payload(func() {
+				Token("token", String)
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+		Method("reset", func() {
+			Security(BasicAuth)
+			Payload(func() {
+				Username("user", String)
+				Password("pass", String)
+			})
+			apigateway.Lambda("arn:aws:lambda:eu-west-1:123456789012:function:reset", func() {
+				apigateway.Timeout(5000)
+			})
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package async
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Status is the status of an operation.
+type Status string
+
+const (
+	// Pending is the status of operations that have not started.
+	Pending Status = "pending"
+	// Running is the status of operations in progress.
+	Running Status = "running"
+	// Succeeded is the status of operations that completed successfully.
+	Succeeded Status = "succeeded"
+	// Failed is the status of operations that completed with an error.
+	Failed Status = "failed"
+)
+
+type (
+	// Operation is a snapshot of the state of a long-running operation.
+	Operation struct {
+		// ID is the unique operation ID.
+		ID string
+		// Status is the operation status.
+		Status Status
+		// Error is the error message if the operation failed.
+		Error string
+		// CreatedAt is the time the operation was started.
+		CreatedAt time.Time
+		// UpdatedAt is the time the status last changed.
+		UpdatedAt time.Time
+	}
+
+	// Tracker runs operations in the background and keeps track of their
+	// status. The operations are kept in memory for the lifetime of the
+	// tracker. Tracker is safe for concurrent use.
+	Tracker struct {
+		mu  sync.RWMutex
+		ops map[string]*Operation
+	}
+)
+
+// NewTracker returns an empty operation tracker.
+func NewTracker() *Tracker {
+	return &Tracker{ops: make(map[string]*Operation)}
+}
+
+// Start runs fn in a new goroutine and returns the pending operation. The
+// context given to fn is not derived from the request context so that the
+// operation keeps running after the response is sent.
+func (t *Tracker) Start(fn func(context.Context) error) Operation {
+	now := time.Now().UTC()
+	op := &Operation{ID: newID(), Status: Pending, CreatedAt: now, UpdatedAt: now}
+	t.mu.Lock()
+	t.ops[op.ID] = op
+	res := *op
+	t.mu.Unlock()
+	go func() {
+		t.update(op, Running, nil)
+		t.update(op, Succeeded, fn(context.Background()))
+	}()
+	return res
+}
+
+// Get returns the operation with the given ID and true, or false if there is
+// no such operation.
+func (t *Tracker) Get(id string) (Operation, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	op, ok := t.ops[id]
+	if !ok {
+		return Operation{}, false
+	}
+	return *op, true
+}
+
+// update sets the status of the operation, Failed if err is not nil.
+func (t *Tracker) update(op *Operation, status Status, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	op.Status = status
+	if err != nil {
+		op.Status = Failed
+		op.Error = err.Error()
+	}
+	op.UpdatedAt = time.Now().UTC()
+}
+
+// newID returns a random operation ID.
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
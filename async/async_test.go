@@ -0,0 +1,63 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Err    error
+		Status Status
+		Error  string
+	}{
+		{"succeeded", nil, Succeeded, ""},
+		{"failed", errors.New("boom"), Failed, "boom"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			tr := NewTracker()
+			release := make(chan struct{})
+			op := tr.Start(func(context.Context) error {
+				<-release
+				return c.Err
+			})
+			if op.ID == "" {
+				t.Fatal("operation ID is empty")
+			}
+			if op.Status != Pending && op.Status != Running {
+				t.Errorf("got status %q, expected %q or %q", op.Status, Pending, Running)
+			}
+			close(release)
+			deadline := time.Now().Add(time.Second)
+			for {
+				got, ok := tr.Get(op.ID)
+				if !ok {
+					t.Fatalf("operation %q not found", op.ID)
+				}
+				if got.Status == c.Status {
+					if got.Error != c.Error {
+						t.Errorf("got error %q, expected %q", got.Error, c.Error)
+					}
+					if got.UpdatedAt.Before(got.CreatedAt) {
+						t.Errorf("updated at %s is before created at %s", got.UpdatedAt, got.CreatedAt)
+					}
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("got status %q, expected %q", got.Status, c.Status)
+				}
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestTrackerGetUnknown(t *testing.T) {
+	if _, ok := NewTracker().Get("unknown"); ok {
+		t.Error("got operation for unknown ID")
+	}
+}
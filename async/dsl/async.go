@@ -0,0 +1,50 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/async/expr"
+
+	// Register code generators for the async plugin
+	_ "goa.design/plugins/v3/async"
+)
+
+// Async makes the method start a long-running operation. The method result is
+// the operation and its HTTP endpoints respond with 202 Accepted and a
+// Location header referencing the status of the operation.
+//
+// The plugin adds the "get_operation" method to the service which returns the
+// status of an operation given its ID. The HTTP endpoint of the method is
+// "GET /operations/{id}" relative to the service base path and responds with
+// 404 Not Found if the operation does not exist.
+//
+// Async must appear in a Method expression. The method cannot define a result
+// and cannot be streaming.
+//
+// Example:
+//
+//    import async "goa.design/plugins/v3/async/dsl"
+//
+//    var _ = Service("reports", func() {
+//        Method("generate", func() {
+//            async.Async()
+//            Payload(ReportRequest)
+//            HTTP(func() {
+//                POST("/reports")
+//            })
+//        })
+//    })
+//
+func Async() {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if expr.Root.Async(m.Service.Name, m.Name) != nil {
+		eval.ReportError("Async used more than once in method %q", m.Name)
+		return
+	}
+	expr.StatusMethod(m.Service)
+	expr.Root.Asyncs = append(expr.Root.Asyncs, &expr.AsyncExpr{Method: m})
+}
@@ -0,0 +1,150 @@
+package expr
+
+import (
+	"fmt"
+
+	goadsl "goa.design/goa/v3/dsl"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// OperationTypeName is the name of the result type describing the
+	// operations.
+	OperationTypeName = "Operation"
+	// OperationIdentifier is the media type identifier of the operations.
+	OperationIdentifier = "application/vnd.goa.operation"
+	// StatusMethodName is the name of the method added to the services
+	// with asynchronous methods to poll the status of the operations.
+	StatusMethodName = "get_operation"
+	// StatusPath is the path of the status polling endpoint relative to
+	// the service base path.
+	StatusPath = "/operations/{id}"
+	// NotFoundError is the name of the error returned by the status method
+	// when the operation does not exist.
+	NotFoundError = "operation_not_found"
+)
+
+// Operation status values.
+const (
+	// StatusPending is the status of operations that have not started.
+	StatusPending = "pending"
+	// StatusRunning is the status of operations in progress.
+	StatusRunning = "running"
+	// StatusSucceeded is the status of operations that completed
+	// successfully.
+	StatusSucceeded = "succeeded"
+	// StatusFailed is the status of operations that completed with an
+	// error.
+	StatusFailed = "failed"
+)
+
+type (
+	// AsyncExpr describes a method that starts a long-running operation.
+	AsyncExpr struct {
+		// Method is the asynchronous method.
+		Method *expr.MethodExpr
+		// result is true if the design defines a result for the method.
+		result bool
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (a *AsyncExpr) EvalName() string {
+	return fmt.Sprintf("Async of %s", a.Method.EvalName())
+}
+
+// Prepare makes the method return the operation and its HTTP endpoints
+// respond with 202 Accepted.
+func (a *AsyncExpr) Prepare() {
+	if a.Method.Result.Type != expr.Empty {
+		a.result = true
+		return // reported by Validate
+	}
+	a.Method.Result = &expr.AttributeExpr{Type: Root.Operation}
+	for _, svc := range expr.Root.API.HTTP.Services {
+		for _, e := range svc.HTTPEndpoints {
+			if e.MethodExpr != a.Method {
+				continue
+			}
+			r := &expr.HTTPResponseExpr{StatusCode: expr.StatusAccepted, Parent: e}
+			r.Prepare()
+			e.Responses = []*expr.HTTPResponseExpr{r}
+		}
+	}
+}
+
+// Validate ensures the asynchronous method is valid.
+func (a *AsyncExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if a.result {
+		verr.Add(a, "asynchronous methods cannot define a result, the result is the operation")
+	}
+	if a.Method.IsStreaming() {
+		verr.Add(a, "streaming methods cannot be asynchronous")
+	}
+	if a.Method.Name == StatusMethodName {
+		verr.Add(a, "method name %q is reserved for the operation status method", StatusMethodName)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// OperationType returns the result type describing the operations, creating
+// it if needed.
+func OperationType() *expr.ResultTypeExpr {
+	if Root.Operation != nil {
+		return Root.Operation
+	}
+	rt := expr.NewResultTypeExpr(OperationTypeName, OperationIdentifier, func() {
+		goadsl.Description("Operation describes a long-running operation started by an asynchronous method.")
+		goadsl.Attribute("id", goadsl.String, "ID of the operation.")
+		goadsl.Attribute("status", goadsl.String, "Status of the operation.", func() {
+			goadsl.Enum(StatusPending, StatusRunning, StatusSucceeded, StatusFailed)
+		})
+		goadsl.Attribute("error", goadsl.String, "Error message, set when the operation failed.")
+		goadsl.Attribute("created_at", goadsl.String, "Time the operation was created.", func() {
+			goadsl.Format(goadsl.FormatDateTime)
+		})
+		goadsl.Attribute("updated_at", goadsl.String, "Time the status of the operation last changed.", func() {
+			goadsl.Format(goadsl.FormatDateTime)
+		})
+		goadsl.Required("id", "status", "created_at", "updated_at")
+	})
+	expr.Root.ResultTypes = append(expr.Root.ResultTypes, rt)
+	eval.Execute(rt.DSL(), rt)
+	Root.Operation = rt
+	return rt
+}
+
+// StatusMethod returns the method of the given service that polls the status
+// of the operations, creating it if needed. The goa DSL has already walked
+// the methods when the Async DSL runs so the method DSL is executed right
+// away. The HTTP endpoint DSL runs together with the other endpoints.
+func StatusMethod(svc *expr.ServiceExpr) *expr.MethodExpr {
+	if m := svc.Method(StatusMethodName); m != nil {
+		return m
+	}
+	op := OperationType()
+	m := &expr.MethodExpr{
+		Name:        StatusMethodName,
+		Description: "Retrieve the status of an operation started by an asynchronous method.",
+		Service:     svc,
+	}
+	svc.Methods = append(svc.Methods, m)
+	eval.Execute(func() {
+		goadsl.Payload(func() {
+			goadsl.Attribute("id", goadsl.String, "ID of the operation.")
+			goadsl.Required("id")
+		})
+		goadsl.Result(op)
+		goadsl.Error(NotFoundError, expr.ErrorResult, "Operation does not exist.")
+		goadsl.HTTP(func() {
+			goadsl.GET(StatusPath)
+			goadsl.Response(NotFoundError, goadsl.StatusNotFound)
+		})
+	}, m)
+	return m
+}
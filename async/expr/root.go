@@ -0,0 +1,73 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the asynchronous methods of the design.
+	RootExpr struct {
+		// Asyncs lists the asynchronous methods in the order they appear
+		// in the design.
+		Asyncs []*AsyncExpr
+		// Operation is the result type describing the operations, nil
+		// if the design does not use the Async DSL.
+		Operation *expr.ResultTypeExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "async plugin"
+}
+
+// WalkSets iterates over the asynchronous methods.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	aexps := make(eval.ExpressionSet, len(r.Asyncs))
+	for i, a := range r.Asyncs {
+		aexps[i] = a
+	}
+	walk(aexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/async/dsl"}
+}
+
+// Async returns the asynchronous definition of the given method, nil if the
+// method is synchronous.
+func (r *RootExpr) Async(svc, method string) *AsyncExpr {
+	for _, a := range r.Asyncs {
+		if a.Method.Service.Name == svc && a.Method.Name == method {
+			return a
+		}
+	}
+	return nil
+}
+
+// HasAsync returns true if the given service defines asynchronous methods.
+func (r *RootExpr) HasAsync(svc string) bool {
+	for _, a := range r.Asyncs {
+		if a.Method.Service.Name == svc {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package async
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/async/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "async",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the function converting the tracked operations into the
+// service operation type, sets the Location header in the response encoders
+// of the asynchronous methods and documents it in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Asyncs) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("async", f) {
+			continue
+		}
+		serverAsync(f)
+		documentAsync(f)
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("async", svc.Name) {
+					continue
+				}
+				if expr.Root.HasAsync(svc.Name) {
+					files = append(files, operationFile(svc))
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// operationFile returns the file defining the function that converts a
+// tracked operation into the service operation type.
+func operationFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	sd := service.Services.Get(svc.Name)
+	svcPath := codegen.SnakeCase(sd.VarName)
+	header := codegen.Header(svc.Name+" service asynchronous operations", sd.PkgName, []*codegen.ImportSpec{
+		{Path: "time"},
+		{Path: "goa.design/plugins/v3/async"},
+	})
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, svcPath, "async.go"),
+		SectionTemplates: []*codegen.SectionTemplate{header, {
+			Name:   "async-operation",
+			Source: operationT,
+			Data:   sd.Scope.GoTypeName(&goaexpr.AttributeExpr{Type: expr.Root.Operation}),
+		}},
+	}
+}
+
+// serverAsync sets the Location header in the HTTP response encoders of the
+// asynchronous methods.
+func serverAsync(f *codegen.File) {
+	if filepath.Base(f.Path) != "encode_decode.go" {
+		return
+	}
+	for _, s := range f.SectionTemplates {
+		if s.Name != "response-encoder" {
+			continue
+		}
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || expr.Root.Async(ed.ServiceName, ed.Method.Name) == nil {
+			continue
+		}
+		status := httpcodegen.HTTPServices.Get(ed.ServiceName).Endpoint(expr.StatusMethodName)
+		if status == nil || len(status.Routes) == 0 {
+			continue
+		}
+		set := fmt.Sprintf(`w.Header().Set("Location", %s({{ if .Method.ViewedResult }}*res.Projected.ID{{ else }}res.ID{{ end }}))`,
+			status.Routes[0].PathInit.Name)
+		s.Source = strings.Replace(s.Source, "{{- range .Result.Responses }}",
+			set+"\n\t\t{{- range .Result.Responses }}", 1)
+	}
+}
+
+// documentAsync documents the Location header of the 202 responses of the
+// asynchronous operations if f is an OpenAPI file.
+func documentAsync(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("async", op) || !async(op) {
+				return nil
+			}
+			resp, ok := op.Responses["202"]
+			if !ok {
+				return nil
+			}
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*openapi.Header)
+			}
+			resp.Headers["Location"] = &openapi.Header{
+				Description: "URL of the operation status.",
+				Type:        "string",
+			}
+			return nil
+		})
+	})
+}
+
+// async returns true if the given operation corresponds to an asynchronous
+// method.
+func async(op *openapi.Operation) bool {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	for _, a := range expr.Root.Asyncs {
+		if m != nil && a.Method == m {
+			return true
+		}
+	}
+	return false
+}
+
+// input: string
+const operationT = `// NewAsync{{ . }} returns the {{ . }} result describing the given tracked
+// operation.
+func NewAsync{{ . }}(op async.Operation) *{{ . }} {
+	res := &{{ . }}{
+		ID:        op.ID,
+		Status:    string(op.Status),
+		CreatedAt: op.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: op.UpdatedAt.Format(time.RFC3339),
+	}
+	if op.Error != "" {
+		res.Error = &op.Error
+	}
+	return res
+}
+`
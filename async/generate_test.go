@@ -0,0 +1,78 @@
+package async_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/async"
+	"goa.design/plugins/v3/async/expr"
+	"goa.design/plugins/v3/async/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	runDSL(t, testdata.AsyncDSL)
+	if m := goaexpr.Root.Service("Reports").Method(expr.StatusMethodName); m == nil {
+		t.Fatalf("%s method not found", expr.StatusMethodName)
+	}
+	fs := httpcodegen.ServerFiles("", goaexpr.Root)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = async.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, f := range fs {
+		switch filepath.Base(f.Path) {
+		case "encode_decode.go":
+			var code string
+			for _, s := range f.Section("response-encoder") {
+				if s.Data.(*httpcodegen.EndpointData).Method.Name == "Generate" {
+					code = codegen.SectionCode(t, s)
+				}
+			}
+			if code != testdata.GenerateResponseEncoderCode {
+				t.Errorf("invalid response encoder code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.GenerateResponseEncoderCode))
+			}
+		case "async.go":
+			found = true
+			sections := f.Section("async-operation")
+			if len(sections) != 1 {
+				t.Fatalf("got %d async operation sections, expected 1", len(sections))
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != testdata.NewAsyncOperationCode {
+				t.Errorf("invalid async operation code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.NewAsyncOperationCode))
+			}
+		}
+	}
+	if !found {
+		t.Error("async.go file not generated")
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	op := spec.Paths["/reports"].(*openapi.Path).Post
+	resp, ok := op.Responses["202"]
+	if !ok {
+		t.Fatal("202 response not found in OpenAPI spec")
+	}
+	if _, ok := resp.Headers["Location"]; !ok {
+		t.Error("Location header not found in OpenAPI spec")
+	}
+	if _, ok := spec.Paths["/operations/{id}"]; !ok {
+		t.Error("operation status path not found in OpenAPI spec")
+	}
+}
+
+func runDSL(t *testing.T, dsl func()) {
+	expr.Root.Asyncs = nil
+	expr.Root.Operation = nil
+	plugintest.RunDSL(t, dsl, expr.Root)
+}
@@ -0,0 +1,31 @@
+package testdata
+
+var GenerateResponseEncoderCode = `// EncodeGenerateResponse returns an encoder for responses returned by the
+// Reports Generate endpoint.
+func EncodeGenerateResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		res := v.(*reportsviews.Operation)
+		w.Header().Set("Location", GetOperationReportsPath(*res.Projected.ID))
+		enc := encoder(ctx, w)
+		body := NewGenerateResponseBody(res.Projected)
+		w.WriteHeader(http.StatusAccepted)
+		return enc.Encode(body)
+	}
+}
+`
+
+var NewAsyncOperationCode = `// NewAsyncOperation returns the Operation result describing the given tracked
+// operation.
+func NewAsyncOperation(op async.Operation) *Operation {
+	res := &Operation{
+		ID:        op.ID,
+		Status:    string(op.Status),
+		CreatedAt: op.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: op.UpdatedAt.Format(time.RFC3339),
+	}
+	if op.Error != "" {
+		res.Error = &op.Error
+	}
+	return res
+}
+`
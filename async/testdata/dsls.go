@@ -0,0 +1,20 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	async "goa.design/plugins/v3/async/dsl"
+)
+
+var AsyncDSL = func() {
+	Service("Reports", func() {
+		Method("Generate", func() {
+			async.Async()
+			Payload(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				POST("/reports")
+			})
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package avro
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "avro",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the Avro schemas of the object user types and result types
+// with the "avro:generate" meta.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("avro", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok {
+			continue
+		}
+		uts := make([]goaexpr.UserType, 0, len(r.Types)+len(r.ResultTypes))
+		uts = append(uts, r.Types...)
+		for _, rt := range r.ResultTypes {
+			uts = append(uts, rt)
+		}
+		for _, ut := range uts {
+			if !selected(ut) || goaexpr.AsObject(ut) == nil {
+				continue
+			}
+			path := filepath.Join(codegen.Gendir, "avro", recordName(ut)+".avsc")
+			s := typeSchema(ut, avroName(codegen.SnakeCase(r.API.Name)))
+			files = append(files, stream.File(path, "avro-schema", s, stream.IndentedJSON))
+		}
+	}
+	return files, nil
+}
+
+// selected returns true if the given type has the "avro:generate" meta with
+// a value other than "false".
+func selected(ut goaexpr.UserType) bool {
+	v, ok := ut.Attribute().Meta["avro:generate"]
+	return ok && (len(v) == 0 || v[0] != "false")
+}
@@ -0,0 +1,30 @@
+package avro_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/avro"
+	"goa.design/plugins/v3/avro/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	root := codegen.RunDSL(t, testdata.SchemasDSL)
+	fs, err := avro.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range fs {
+		names = append(names, filepath.Base(f.Path))
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got files %v, expected Bottle.avsc and StoredBottle.avsc", names)
+	}
+	for _, f := range fs {
+		plugintest.Golden(t, filepath.Base(f.Path), plugintest.Render(t, f))
+	}
+}
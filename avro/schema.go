@@ -0,0 +1,223 @@
+package avro
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"goa.design/goa/v3/codegen"
+	goaexpr "goa.design/goa/v3/expr"
+)
+
+type (
+	// record is an Avro record schema.
+	record struct {
+		Type      string   `json:"type"`
+		Name      string   `json:"name"`
+		Namespace string   `json:"namespace,omitempty"`
+		Doc       string   `json:"doc,omitempty"`
+		Fields    []*field `json:"fields"`
+	}
+
+	// field is a field of an Avro record schema.
+	field struct {
+		Name    string          `json:"name"`
+		Doc     string          `json:"doc,omitempty"`
+		Type    interface{}     `json:"type"`
+		Default json.RawMessage `json:"default,omitempty"`
+	}
+
+	// enum is an Avro enum schema.
+	enum struct {
+		Type    string   `json:"type"`
+		Name    string   `json:"name"`
+		Symbols []string `json:"symbols"`
+	}
+
+	// array is an Avro array schema.
+	array struct {
+		Type  string      `json:"type"`
+		Items interface{} `json:"items"`
+	}
+
+	// mapSchema is an Avro map schema.
+	mapSchema struct {
+		Type   string      `json:"type"`
+		Values interface{} `json:"values"`
+	}
+
+	// logical is an Avro schema with a logical type.
+	logical struct {
+		Type        string `json:"type"`
+		LogicalType string `json:"logicalType"`
+	}
+
+	// builder computes the schema of a user type. The named schemas are
+	// defined the first time they are used and referenced by name
+	// afterwards as required by Avro.
+	builder struct {
+		// api is the default namespace of the user types.
+		api string
+		// namespace is the namespace of the enclosing record.
+		namespace string
+		// defined records the full names of the named schemas already
+		// defined.
+		defined map[string]bool
+	}
+)
+
+var (
+	// invalidChars matches the characters that are not allowed in Avro
+	// names.
+	invalidChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+	// symbolRegex matches the valid Avro enum symbols.
+	symbolRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// typeSchema returns the Avro schema of the given object user type. api is the
+// default namespace of the user types.
+func typeSchema(ut goaexpr.UserType, api string) *record {
+	b := &builder{api: api, defined: make(map[string]bool)}
+	return b.record(recordName(ut), b.typeNamespace(ut), ut.Attribute())
+}
+
+// record returns the record schema of the given object attribute defined in
+// the given namespace.
+func (b *builder) record(name, namespace string, att *goaexpr.AttributeExpr) *record {
+	b.defined[namespace+"."+name] = true
+	r := &record{Type: "record", Name: name, Doc: att.Description, Fields: []*field{}}
+	if namespace != b.namespace {
+		r.Namespace = namespace
+	}
+	enclosing := b.namespace
+	b.namespace = namespace
+	defer func() { b.namespace = enclosing }()
+	for _, nat := range *goaexpr.AsObject(att.Type) {
+		f := &field{
+			Name: avroName(nat.Name),
+			Doc:  nat.Attribute.Description,
+			Type: b.schema(name+codegen.Goify(nat.Name, true), nat.Attribute),
+		}
+		switch {
+		case nat.Attribute.DefaultValue != nil:
+			switch f.Type.(type) {
+			case string, *enum:
+				f.Default, _ = json.Marshal(nat.Attribute.DefaultValue)
+			}
+		case !att.IsRequired(nat.Name):
+			f.Type = []interface{}{"null", f.Type}
+			f.Default = json.RawMessage("null")
+		}
+		r.Fields = append(r.Fields, f)
+	}
+	return r
+}
+
+// schema returns the schema of the given attribute. name is the name of the
+// named schema describing the attribute if it is an inline object or an enum.
+func (b *builder) schema(name string, att *goaexpr.AttributeExpr) interface{} {
+	switch t := att.Type.(type) {
+	case goaexpr.UserType:
+		if goaexpr.AsObject(t) == nil {
+			return b.schema(recordName(t), t.Attribute())
+		}
+		rn, ns := recordName(t), b.typeNamespace(t)
+		if b.defined[ns+"."+rn] {
+			return b.ref(rn, ns)
+		}
+		return b.record(rn, ns, t.Attribute())
+	case *goaexpr.Object:
+		if b.defined[b.namespace+"."+name] {
+			return name
+		}
+		return b.record(name, b.namespace, att)
+	case *goaexpr.Array:
+		return &array{Type: "array", Items: b.schema(name+"Item", t.ElemType)}
+	case *goaexpr.Map:
+		return &mapSchema{Type: "map", Values: b.schema(name+"Value", t.ElemType)}
+	case goaexpr.Primitive:
+		if s := b.enum(name, att); s != nil {
+			return s
+		}
+		if v := att.Validation; v != nil && v.Format == goaexpr.FormatUUID && t.Kind() == goaexpr.StringKind {
+			return &logical{Type: "string", LogicalType: "uuid"}
+		}
+		return primitive(t)
+	default:
+		return "bytes"
+	}
+}
+
+// ref returns the reference to the named schema with the given name defined in
+// the given namespace, the full name is used if the namespace is not the one
+// of the enclosing record.
+func (b *builder) ref(name, namespace string) string {
+	if namespace == b.namespace {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// typeNamespace returns the namespace of the record describing the given user
+// type: the value of the "avro:namespace" meta of the type if any, the API
+// namespace otherwise.
+func (b *builder) typeNamespace(ut goaexpr.UserType) string {
+	if v, ok := ut.Attribute().Meta["avro:namespace"]; ok && len(v) > 0 {
+		return v[0]
+	}
+	return b.api
+}
+
+// enum returns the enum schema of the given string attribute if its values
+// are restricted to valid Avro symbols, nil otherwise.
+func (b *builder) enum(name string, att *goaexpr.AttributeExpr) interface{} {
+	v := att.Validation
+	if att.Type.Kind() != goaexpr.StringKind || v == nil || len(v.Values) == 0 {
+		return nil
+	}
+	symbols := make([]string, len(v.Values))
+	for i, val := range v.Values {
+		s, ok := val.(string)
+		if !ok || !symbolRegex.MatchString(s) {
+			return nil
+		}
+		symbols[i] = s
+	}
+	if b.defined[b.namespace+"."+name] {
+		return name
+	}
+	b.defined[b.namespace+"."+name] = true
+	return &enum{Type: "enum", Name: name, Symbols: symbols}
+}
+
+// primitive returns the Avro schema of the given primitive type. The unsigned
+// integers use the long type which cannot hold the largest UInt64 values and
+// Any values are described as strings holding their JSON representation.
+func primitive(dt goaexpr.DataType) string {
+	switch dt.Kind() {
+	case goaexpr.BooleanKind:
+		return "boolean"
+	case goaexpr.Int32Kind:
+		return "int"
+	case goaexpr.IntKind, goaexpr.Int64Kind, goaexpr.UIntKind, goaexpr.UInt32Kind, goaexpr.UInt64Kind:
+		return "long"
+	case goaexpr.Float32Kind:
+		return "float"
+	case goaexpr.Float64Kind:
+		return "double"
+	case goaexpr.BytesKind:
+		return "bytes"
+	default:
+		return "string"
+	}
+}
+
+// recordName returns the name of the record describing the given user type.
+func recordName(ut goaexpr.UserType) string {
+	return codegen.Goify(ut.Name(), true)
+}
+
+// avroName returns the given name with the characters not allowed in Avro
+// names replaced with underscores.
+func avroName(name string) string {
+	return invalidChars.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,59 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var SchemasDSL = func() {
+	var Winery = Type("Winery", func() {
+		Description("Winery producing wines")
+		Attribute("name", String, "Name of the winery")
+		Attribute("country", String)
+		Required("name")
+	})
+	var Bottle = Type("Bottle", func() {
+		Meta("avro:generate", "true")
+		Meta("avro:namespace", "com.example.cellar")
+		Description("Bottle of wine")
+		Attribute("id", String, func() {
+			Format(FormatUUID)
+		})
+		Attribute("name", String, "Name of the wine")
+		Attribute("vintage", Int32)
+		Attribute("color", String, func() {
+			Enum("red", "white", "rose")
+			Default("red")
+		})
+		Attribute("size", String, func() {
+			Enum("0.75l", "1.5l")
+		})
+		Attribute("tags", ArrayOf(String))
+		Attribute("scores", MapOf(String, Float64))
+		Attribute("winery", Winery)
+		Attribute("producer", Winery)
+		Attribute("label", func() {
+			Attribute("image", Bytes)
+			Required("image")
+		})
+		Required("id", "name", "vintage", "winery")
+	})
+	var _ = ResultType("application/vnd.stored-bottle", func() {
+		TypeName("StoredBottle")
+		Meta("avro:generate")
+		Attributes(func() {
+			Attribute("bottle", Bottle)
+			Attribute("stored_at", String)
+			Required("bottle")
+		})
+	})
+	API("cellar", func() {})
+	Service("cellar", func() {
+		Method("show", func() {
+			Payload(Winery)
+			Result(Bottle)
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
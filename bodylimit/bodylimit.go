@@ -0,0 +1,56 @@
+package bodylimit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// body is a request body limited with http.MaxBytesReader which records
+// whether the limit was exceeded.
+type body struct {
+	io.ReadCloser
+	max      int64
+	read     int64
+	exceeded bool
+}
+
+// Limit limits the size of the body of r to n bytes using http.MaxBytesReader.
+// It writes a 413 Request Entity Too Large response and returns false if the
+// Content-Length of the request already exceeds n.
+func Limit(w http.ResponseWriter, r *http.Request, n int64) bool {
+	if r.ContentLength > n {
+		reject(w, n)
+		return false
+	}
+	r.Body = &body{ReadCloser: http.MaxBytesReader(w, r.Body, n), max: n}
+	return true
+}
+
+// Exceeded writes a 413 Request Entity Too Large response and returns true if
+// the body of r exceeds the limit set with Limit. It returns false otherwise.
+func Exceeded(w http.ResponseWriter, r *http.Request) bool {
+	b, ok := r.Body.(*body)
+	if !ok || !b.exceeded {
+		return false
+	}
+	reject(w, b.max)
+	return true
+}
+
+// Read reads from the limited body and records whether the limit is
+// exceeded.
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.max {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// reject writes the response to requests whose body exceeds max bytes.
+func reject(w http.ResponseWriter, max int64) {
+	w.Header().Set("Connection", "close")
+	http.Error(w, fmt.Sprintf("request body exceeds %d bytes", max), http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,51 @@
+package bodylimit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimit(t *testing.T) {
+	cases := map[string]struct {
+		Body          string
+		ContentLength int64
+		Status        int
+	}{
+		"within limit":          {"abcd", 4, http.StatusOK},
+		"content length":        {"abcdefghij", 10, http.StatusRequestEntityTooLarge},
+		"chunked":               {"abcdefghij", -1, http.StatusRequestEntityTooLarge},
+		"chunked within limits": {"abc", -1, http.StatusOK},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !Limit(w, r, 5) {
+					return
+				}
+				if _, err := ioutil.ReadAll(r.Body); err != nil {
+					if Exceeded(w, r) {
+						return
+					}
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			})
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tc.Body))
+			r.ContentLength = tc.ContentLength
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.Status {
+				t.Errorf("got status %d, expected %d", w.Code, tc.Status)
+			}
+		})
+	}
+}
+
+func TestExceededUnlimited(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader("abc"))
+	if Exceeded(httptest.NewRecorder(), r) {
+		t.Error("got exceeded for a request without limit")
+	}
+}
@@ -0,0 +1,66 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/bodylimit/expr"
+
+	// Register code generators for the body limit plugin
+	_ "goa.design/plugins/v3/bodylimit"
+)
+
+// Units accepted by MaxBodySize.
+const (
+	// KB is one kibibyte.
+	KB int64 = 1 << 10
+	// MB is one mebibyte.
+	MB int64 = 1 << 20
+	// GB is one gibibyte.
+	GB int64 = 1 << 30
+)
+
+// MaxBodySize sets the maximum size in bytes of the HTTP request bodies of the
+// enclosing method, of the methods of the enclosing service or of all the
+// methods of the API. A method limit overrides the limit of its service which
+// overrides the API limit.
+//
+// The generated HTTP handlers read the request bodies through
+// http.MaxBytesReader and respond with 413 Request Entity Too Large when a
+// body exceeds the limit. The limit is documented in the OpenAPI
+// specification with the x-max-body-size extension.
+//
+// MaxBodySize must appear in an API, Service or Method expression.
+//
+// Example:
+//
+//    import bodylimit "goa.design/plugins/v3/bodylimit/dsl"
+//
+//    var _ = API("catalog", func() {
+//        bodylimit.MaxBodySize(1 * bodylimit.MB)
+//    })
+//
+//    var _ = Service("catalog", func() {
+//        Method("upload", func() {
+//            bodylimit.MaxBodySize(100 * bodylimit.MB)
+//            Payload(Bytes)
+//            HTTP(func() {
+//                POST("/uploads")
+//            })
+//        })
+//    })
+//
+func MaxBodySize(n int64) {
+	l := &expr.LimitExpr{MaxSize: n}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+		l.API = actual
+	case *goaexpr.ServiceExpr:
+		l.Service = actual
+	case *goaexpr.MethodExpr:
+		l.Method = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Limits = append(expr.Root.Limits, l)
+}
@@ -0,0 +1,49 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// LimitExpr describes the maximum size of the HTTP request bodies of
+	// the methods of an API, of a service or of a method.
+	LimitExpr struct {
+		// MaxSize is the maximum size in bytes.
+		MaxSize int64
+		// API is the API the limit applies to, nil for service and
+		// method limits.
+		API *expr.APIExpr
+		// Service is the service the limit applies to, nil for API and
+		// method limits.
+		Service *expr.ServiceExpr
+		// Method is the method the limit applies to, nil for API and
+		// service limits.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LimitExpr) EvalName() string {
+	switch {
+	case l.Method != nil:
+		return fmt.Sprintf("body size limit of %s", l.Method.EvalName())
+	case l.Service != nil:
+		return fmt.Sprintf("body size limit of %s", l.Service.EvalName())
+	}
+	return fmt.Sprintf("body size limit of %s", l.API.EvalName())
+}
+
+// Validate makes sure the limit is positive.
+func (l *LimitExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if l.MaxSize <= 0 {
+		verr.Add(l, "max body size must be positive")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,69 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the body size limits defined in the design.
+	RootExpr struct {
+		// Limits lists the API, service and method body size limits.
+		Limits []*LimitExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "body limit plugin"
+}
+
+// WalkSets iterates over the body size limits.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	lexps := make(eval.ExpressionSet, len(r.Limits))
+	for i, l := range r.Limits {
+		lexps[i] = l
+	}
+	walk(lexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/bodylimit/dsl"}
+}
+
+// MaxBodySize returns the maximum size of the request bodies of the given
+// method: the method limit if defined, the service limit otherwise and the API
+// limit if neither is defined. It returns 0 if there is no limit.
+func (r *RootExpr) MaxBodySize(svc, method string) int64 {
+	var res, api int64
+	for _, l := range r.Limits {
+		switch {
+		case l.Method != nil && l.Method.Service.Name == svc && l.Method.Name == method:
+			return l.MaxSize
+		case l.Service != nil && l.Service.Name == svc:
+			res = l.MaxSize
+		case l.API != nil:
+			api = l.MaxSize
+		}
+	}
+	if res == 0 {
+		res = api
+	}
+	return res
+}
@@ -0,0 +1,107 @@
+package bodylimit
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/bodylimit/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "bodylimit",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate limits the size of the request bodies read by the HTTP handlers of
+// the methods that define a body size limit and documents the limits in the
+// OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Limits) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("bodylimit", f) {
+			continue
+		}
+		serverLimit(f)
+		documentLimit(f)
+	}
+	return files, nil
+}
+
+// serverLimit makes the HTTP handlers of the methods that define a body size
+// limit read the request bodies through http.MaxBytesReader and respond with
+// 413 Request Entity Too Large when a body exceeds the limit.
+func serverLimit(f *codegen.File) {
+	if filepath.Base(f.Path) != "server.go" {
+		return
+	}
+	for _, s := range f.Section("server-handler-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || ed.Payload.Ref == "" {
+			continue
+		}
+		n := expr.Root.MaxBodySize(ed.ServiceName, ed.Method.Name)
+		if n == 0 {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Path: "goa.design/plugins/v3/bodylimit"})
+		decode := "\t\tpayload, err := decodeRequest(r)\n\t\tif err != nil {"
+		s.Source = strings.Replace(s.Source, decode,
+			fmt.Sprintf("\t\tif !bodylimit.Limit(w, r, %d) {\n\t\t\treturn\n\t\t}\n", n)+
+				decode+"\n\t\t\tif bodylimit.Exceeded(w, r) {\n\t\t\t\treturn\n\t\t\t}", 1)
+	}
+}
+
+// documentLimit adds the x-max-body-size extension and the response returned
+// when the request body is too large to the operations that define a body size
+// limit if f is an OpenAPI file.
+func documentLimit(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("bodylimit", op) {
+				return nil
+			}
+			n := maxBodySize(op)
+			if n == 0 {
+				return nil
+			}
+			if op.Extensions == nil {
+				op.Extensions = make(map[string]interface{})
+			}
+			op.Extensions["x-max-body-size"] = n
+			status := strconv.Itoa(http.StatusRequestEntityTooLarge)
+			if _, ok := op.Responses[status]; !ok {
+				op.Responses[status] = &openapi.Response{
+					Description: fmt.Sprintf("%s response returned when the request body exceeds %d bytes.", http.StatusText(http.StatusRequestEntityTooLarge), n),
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// maxBodySize returns the body size limit of the method corresponding to the
+// given operation, 0 if the method has none or has no payload.
+func maxBodySize(op *openapi.Operation) int64 {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	if m == nil || m.Payload == nil || m.Payload.Type == goaexpr.Empty {
+		return 0
+	}
+	return expr.Root.MaxBodySize(m.Service.Name, m.Name)
+}
@@ -0,0 +1,79 @@
+package bodylimit_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/bodylimit"
+	"goa.design/plugins/v3/bodylimit/expr"
+	"goa.design/plugins/v3/bodylimit/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Limits = nil
+	httpcodegen.RunHTTPDSL(t, testdata.MediaDSL)
+	fs := httpcodegen.ServerFiles("", goaexpr.Root)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = bodylimit.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits := map[string]string{
+		"NewUploadHandler": "bodylimit.Limit(w, r, 104857600)",
+		"NewRenameHandler": "bodylimit.Limit(w, r, 1048576)",
+		"NewShowHandler":   "",
+		"NewAddHandler":    "bodylimit.Limit(w, r, 4096)",
+	}
+	for _, f := range fs {
+		for _, s := range f.Section("server-handler-init") {
+			ed := s.Data.(*httpcodegen.EndpointData)
+			code := codegen.SectionCode(t, s)
+			if ed.HandlerInit == "NewUploadHandler" && code != testdata.UploadHandlerCode {
+				t.Errorf("invalid handler code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.UploadHandlerCode))
+			}
+			expected := limits[ed.HandlerInit]
+			if expected == "" {
+				if strings.Contains(code, "bodylimit") {
+					t.Errorf("%s: unexpected body size limit", ed.HandlerInit)
+				}
+				continue
+			}
+			if !strings.Contains(code, expected) {
+				t.Errorf("%s: missing %q", ed.HandlerInit, expected)
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	cases := map[string]struct {
+		Op       *openapi.Operation
+		Expected int64
+	}{
+		"upload": {spec.Paths["/media"].(*openapi.Path).Post, 104857600},
+		"rename": {spec.Paths["/media/{id}"].(*openapi.Path).Put, 1048576},
+		"show":   {spec.Paths["/media"].(*openapi.Path).Get, 0},
+		"add":    {spec.Paths["/notes"].(*openapi.Path).Post, 4096},
+	}
+	for k, tc := range cases {
+		_, ok := tc.Op.Responses["413"]
+		if ok != (tc.Expected != 0) {
+			t.Errorf("%s: got 413 response %v, expected %v", k, ok, tc.Expected != 0)
+		}
+		if tc.Expected == 0 {
+			if _, ok := tc.Op.Extensions["x-max-body-size"]; ok {
+				t.Errorf("%s: unexpected x-max-body-size extension", k)
+			}
+			continue
+		}
+		if got := tc.Op.Extensions["x-max-body-size"]; got != tc.Expected {
+			t.Errorf("%s: got x-max-body-size %v, expected %d", k, got, tc.Expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package testdata
+
+var UploadHandlerCode = `// NewUploadHandler creates a HTTP handler which loads the HTTP request and
+// calls the "Media" service "Upload" endpoint.
+func NewUploadHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		decodeRequest  = DecodeUploadRequest(mux, dec)
+		encodeResponse = EncodeUploadResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Upload")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Media")
+		if !bodylimit.Limit(w, r, 104857600) {
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if bodylimit.Exceeded(w, r) {
+				return
+			}
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, payload)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
@@ -0,0 +1,45 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	bodylimit "goa.design/plugins/v3/bodylimit/dsl"
+)
+
+var MediaDSL = func() {
+	API("media", func() {
+		bodylimit.MaxBodySize(1 * bodylimit.MB)
+	})
+	Service("Media", func() {
+		Method("Upload", func() {
+			bodylimit.MaxBodySize(100 * bodylimit.MB)
+			Payload(Bytes)
+			HTTP(func() {
+				POST("/media")
+			})
+		})
+		Method("Rename", func() {
+			Payload(func() {
+				Attribute("id", String)
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				PUT("/media/{id}")
+			})
+		})
+		Method("Show", func() {
+			Result(Bytes)
+			HTTP(func() {
+				GET("/media")
+			})
+		})
+	})
+	Service("Notes", func() {
+		bodylimit.MaxBodySize(4 * bodylimit.KB)
+		Method("Add", func() {
+			Payload(String)
+			HTTP(func() {
+				POST("/notes")
+			})
+		})
+	})
+}
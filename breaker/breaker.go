@@ -0,0 +1,155 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// State is the state of a circuit.
+type State int
+
+const (
+	// Closed is the state of a circuit letting the requests through.
+	Closed State = iota
+	// Open is the state of a circuit rejecting the requests.
+	Open
+	// HalfOpen is the state of a circuit letting a trial request through.
+	HalfOpen
+)
+
+// ErrorName is the name of the error returned by the open circuits.
+const ErrorName = "circuit_open"
+
+// Breaker is a circuit breaker. It opens after a number of consecutive
+// failures and rejects the requests until a reset timeout elapses. It then
+// lets one trial request through and closes if the request succeeds.
+type Breaker struct {
+	maxFailures  int
+	resetTimeout time.Duration
+
+	mu         sync.Mutex
+	state      State
+	generation int
+	failures   int
+	openedAt   time.Time
+	now        func() time.Time
+}
+
+// New returns a closed circuit breaker which opens after maxFailures
+// consecutive failures and rejects the requests during resetTimeout.
+func New(maxFailures int, resetTimeout time.Duration) *Breaker {
+	return &Breaker{maxFailures: maxFailures, resetTimeout: resetTimeout, now: time.Now}
+}
+
+// Wrap returns an endpoint which calls e through the circuit breaker b. The
+// endpoint returns a temporary error named "circuit_open" without calling e
+// while the circuit is open.
+func Wrap(e goa.Endpoint, b *Breaker) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		gen, ok := b.allow()
+		if !ok {
+			return nil, goa.TemporaryError(ErrorName, "circuit breaker is open")
+		}
+		res, err := e(ctx, req)
+		if ctx.Err() == context.Canceled {
+			b.abort(gen)
+		} else {
+			b.done(gen, IsFailure(err))
+		}
+		return res, err
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.resetTimeout {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// IsFailure returns true if err denotes a failure of the remote service as
+// opposed to an error described in the design: request errors, timeouts,
+// faults and temporary errors.
+func IsFailure(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *goahttp.ClientError:
+		return e.Name == "request_error" || e.Timeout || e.Temporary || e.Fault
+	case *goa.ServiceError:
+		return e.Timeout || e.Temporary || e.Fault
+	}
+	return false
+}
+
+// allow returns the generation of the circuit and true if a request may be
+// sent. It moves an open circuit whose reset timeout elapsed to the half-open
+// state and lets the trial request through.
+func (b *Breaker) allow() (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Closed:
+		return b.generation, true
+	case Open:
+		if b.now().Sub(b.openedAt) < b.resetTimeout {
+			return 0, false
+		}
+		b.setState(HalfOpen)
+		return b.generation, true
+	}
+	// A trial request is in flight.
+	return 0, false
+}
+
+// done records the outcome of a request sent during the given generation.
+// The outcomes of the requests sent before the last state change are ignored.
+func (b *Breaker) done(gen int, failure bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.generation {
+		return
+	}
+	switch {
+	case !failure:
+		if b.state == HalfOpen {
+			b.setState(Closed)
+		}
+		b.failures = 0
+	case b.state == HalfOpen:
+		b.setState(Open)
+	default:
+		b.failures++
+		if b.failures >= b.maxFailures {
+			b.setState(Open)
+		}
+	}
+}
+
+// abort records a request canceled by the caller. The outcome of the request
+// is unknown so a half-open circuit lets the next request through instead.
+func (b *Breaker) abort(gen int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen == b.generation && b.state == HalfOpen {
+		b.state = Open
+		b.generation++
+	}
+}
+
+// setState changes the state of the circuit and starts a new generation.
+func (b *Breaker) setState(s State) {
+	b.state = s
+	b.generation++
+	b.failures = 0
+	if s == Open {
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,105 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestBreaker(t *testing.T) {
+	var (
+		now     = time.Now()
+		failure = goahttp.ErrInvalidResponse("svc", "method", 503, "")
+		calls   int
+		result  error
+	)
+	b := New(2, time.Minute)
+	b.now = func() time.Time { return now }
+	e := Wrap(func(context.Context, interface{}) (interface{}, error) {
+		calls++
+		return nil, result
+	}, b)
+	call := func(err error) error {
+		result = err
+		_, err = e(context.Background(), nil)
+		return err
+	}
+
+	call(failure)
+	call(errors.New("not a failure"))
+	call(failure)
+	if s := b.State(); s != Closed {
+		t.Fatalf("got state %d after non-consecutive failures, expected closed", s)
+	}
+	call(failure)
+	if s := b.State(); s != Open {
+		t.Fatalf("got state %d after consecutive failures, expected open", s)
+	}
+	calls = 0
+	err := call(nil)
+	if serr, ok := err.(*goa.ServiceError); !ok || serr.Name != ErrorName || calls != 0 {
+		t.Fatalf("got error %v and %d calls while open, expected circuit open error and no call", err, calls)
+	}
+
+	now = now.Add(time.Minute)
+	if s := b.State(); s != HalfOpen {
+		t.Fatalf("got state %d after reset timeout, expected half-open", s)
+	}
+	call(failure)
+	if s := b.State(); s != Open || calls != 1 {
+		t.Fatalf("got state %d and %d calls after failed trial, expected open and 1 call", s, calls)
+	}
+	now = now.Add(time.Minute)
+	if err := call(nil); err != nil {
+		t.Fatalf("got error %v for trial request, expected none", err)
+	}
+	if s := b.State(); s != Closed {
+		t.Fatalf("got state %d after successful trial, expected closed", s)
+	}
+}
+
+func TestBreakerCanceledTrial(t *testing.T) {
+	now := time.Now()
+	b := New(1, time.Minute)
+	b.now = func() time.Time { return now }
+	ctx, cancel := context.WithCancel(context.Background())
+	e := Wrap(func(context.Context, interface{}) (interface{}, error) {
+		cancel()
+		return nil, goahttp.ErrRequestError("svc", "method", context.Canceled)
+	}, b)
+	b.done(b.generation, true)
+	now = now.Add(time.Minute)
+	e(ctx, nil)
+	if s := b.State(); s != HalfOpen {
+		t.Errorf("got state %d after canceled trial, expected half-open", s)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Err      error
+		Expected bool
+	}{
+		{"nil", nil, false},
+		{"request", goahttp.ErrRequestError("svc", "method", errors.New("connection refused")), true},
+		{"unavailable", goahttp.ErrInvalidResponse("svc", "method", 503, ""), true},
+		{"bad-gateway", goahttp.ErrInvalidResponse("svc", "method", 502, ""), true},
+		{"not-found", goahttp.ErrInvalidResponse("svc", "method", 404, ""), false},
+		{"decoding", goahttp.ErrDecodingError("svc", "method", errors.New("bad")), false},
+		{"fault", goa.Fault("oops"), true},
+		{"designed", goa.PermanentError("not_found", "not found"), false},
+		{"other", errors.New("other"), false},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if got := IsFailure(c.Err); got != c.Expected {
+				t.Errorf("got %v, expected %v", got, c.Expected)
+			}
+		})
+	}
+}
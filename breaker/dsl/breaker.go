@@ -0,0 +1,88 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/breaker/expr"
+
+	// Register code generators for the circuit breaker plugin
+	_ "goa.design/plugins/v3/breaker"
+)
+
+// CircuitBreaker wraps the client endpoints of the enclosing service or
+// method with circuit breakers. A method circuit breaker overrides the circuit
+// breaker of its service.
+//
+// The circuit opens after MaxFailures consecutive failures, 5 by default. The
+// open circuit rejects the requests without sending them during ResetTimeout,
+// 30 seconds by default, then lets one trial request through: the circuit
+// closes if the request succeeds and opens again otherwise.
+//
+// The generated service packages define a NewClientWithBreakers function which
+// initializes the service client with the wrapped endpoints.
+//
+// CircuitBreaker must appear in a Service or Method expression.
+//
+// CircuitBreaker accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import breaker "goa.design/plugins/v3/breaker/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        breaker.CircuitBreaker()
+//        Method("export", func() {
+//            breaker.CircuitBreaker(func() {
+//                breaker.MaxFailures(2)
+//                breaker.ResetTimeout(time.Minute)
+//            })
+//        })
+//    })
+//
+func CircuitBreaker(fn ...func()) {
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	b := &expr.BreakerExpr{MaxFailures: expr.DefaultMaxFailures, ResetTimeout: expr.DefaultResetTimeout}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.ServiceExpr:
+		b.Service = actual
+	case *goaexpr.MethodExpr:
+		b.Method = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], b) {
+			return
+		}
+	}
+	expr.Root.Breakers = append(expr.Root.Breakers, b)
+}
+
+// MaxFailures sets the number of consecutive failures that open the circuit.
+//
+// MaxFailures must appear in a CircuitBreaker expression.
+func MaxFailures(n int) {
+	if b, ok := eval.Current().(*expr.BreakerExpr); ok {
+		b.MaxFailures = n
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ResetTimeout sets the duration during which the open circuit rejects the
+// requests.
+//
+// ResetTimeout must appear in a CircuitBreaker expression.
+func ResetTimeout(d time.Duration) {
+	if b, ok := eval.Current().(*expr.BreakerExpr); ok {
+		b.ResetTimeout = d
+		return
+	}
+	eval.IncompatibleDSL()
+}
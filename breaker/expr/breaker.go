@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// DefaultMaxFailures is the default number of consecutive failures
+	// that open a circuit.
+	DefaultMaxFailures = 5
+	// DefaultResetTimeout is the default duration during which an open
+	// circuit rejects the requests.
+	DefaultResetTimeout = 30 * time.Second
+)
+
+type (
+	// BreakerExpr describes the circuit breaker wrapping the client
+	// endpoints of a service or of a method.
+	BreakerExpr struct {
+		// MaxFailures is the number of consecutive failures that open
+		// the circuit.
+		MaxFailures int
+		// ResetTimeout is the duration during which the open circuit
+		// rejects the requests before letting a trial request through.
+		ResetTimeout time.Duration
+		// Service is the service the circuit breaker applies to, nil
+		// for method circuit breakers.
+		Service *expr.ServiceExpr
+		// Method is the method the circuit breaker applies to, nil for
+		// service circuit breakers.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (b *BreakerExpr) EvalName() string {
+	if b.Method != nil {
+		return fmt.Sprintf("circuit breaker of %s", b.Method.EvalName())
+	}
+	return fmt.Sprintf("circuit breaker of %s", b.Service.EvalName())
+}
+
+// Validate makes sure the thresholds are positive.
+func (b *BreakerExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if b.MaxFailures <= 0 {
+		verr.Add(b, "max failures must be positive")
+	}
+	if b.ResetTimeout <= 0 {
+		verr.Add(b, "reset timeout must be positive")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
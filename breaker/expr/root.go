@@ -0,0 +1,64 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the circuit breakers defined in the design.
+	RootExpr struct {
+		// Breakers lists the service and method circuit breakers.
+		Breakers []*BreakerExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "circuit breaker plugin"
+}
+
+// WalkSets iterates over the circuit breakers.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	bexps := make(eval.ExpressionSet, len(r.Breakers))
+	for i, b := range r.Breakers {
+		bexps[i] = b
+	}
+	walk(bexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/breaker/dsl"}
+}
+
+// Breaker returns the circuit breaker of the given method: the method circuit
+// breaker if defined, the service circuit breaker otherwise. It returns nil if
+// neither is defined.
+func (r *RootExpr) Breaker(svc, method string) *BreakerExpr {
+	var res *BreakerExpr
+	for _, b := range r.Breakers {
+		switch {
+		case b.Method != nil && b.Method.Service.Name == svc && b.Method.Name == method:
+			return b
+		case b.Service != nil && b.Service.Name == svc:
+			res = b
+		}
+	}
+	return res
+}
@@ -0,0 +1,124 @@
+package breaker
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/breaker/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// clientData contains the data necessary to render the function
+	// initializing a service client with circuit breakers.
+	clientData struct {
+		// Name is the name of the service.
+		Name string
+		// ClientVarName is the name of the client struct.
+		ClientVarName string
+		// Endpoints lists the client endpoints.
+		Endpoints []*endpointData
+	}
+
+	// endpointData describes a client endpoint.
+	endpointData struct {
+		// ArgName is the name of the endpoint argument.
+		ArgName string
+		// MaxFailures is the number of consecutive failures that open
+		// the circuit, 0 if the endpoint has no circuit breaker.
+		MaxFailures int
+		// ResetTimeout is the Go expression of the reset timeout.
+		ResetTimeout string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "breaker",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the function initializing the client of each service
+// that defines circuit breakers with the wrapped endpoints.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("breaker", svc.Name) {
+					continue
+				}
+				if f := breakerFile(svc); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// breakerFile returns the file defining the function initializing the client
+// of the given service with circuit breakers, nil if the service defines none.
+func breakerFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	sd := service.Services.Get(svc.Name)
+	data := &clientData{Name: svc.Name, ClientVarName: "Client"}
+	found := false
+	for _, m := range sd.Methods {
+		ed := &endpointData{ArgName: codegen.Goify(m.VarName, false)}
+		if b := expr.Root.Breaker(svc.Name, m.Name); b != nil {
+			ed.MaxFailures = b.MaxFailures
+			ed.ResetTimeout = genutil.DurationCode(b.ResetTimeout)
+			found = true
+		}
+		data.Endpoints = append(data.Endpoints, ed)
+	}
+	if !found {
+		return nil
+	}
+	header := codegen.Header(svc.Name+" client circuit breakers", sd.PkgName, []*codegen.ImportSpec{
+		{Path: "time"},
+		codegen.GoaImport(""),
+		{Path: "goa.design/plugins/v3/breaker"},
+	})
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, codegen.SnakeCase(sd.VarName), "breaker.go"),
+		SectionTemplates: []*codegen.SectionTemplate{header, {
+			Name:    "breaker-client-init",
+			Source:  clientInitT,
+			Data:    data,
+			FuncMap: map[string]interface{}{"args": args},
+		}},
+	}
+}
+
+// args returns the comma separated names of the endpoint arguments.
+func args(eps []*endpointData) string {
+	names := make([]string, len(eps))
+	for i, e := range eps {
+		names[i] = e.ArgName
+	}
+	return strings.Join(names, ", ")
+}
+
+// input: clientData
+const clientInitT = `{{ printf "New%sWithBreakers initializes a %q service client given the endpoints and wraps the endpoints of the methods that define a circuit breaker in the design. Each call creates new circuit breakers." .ClientVarName .Name | comment }}
+func New{{ .ClientVarName }}WithBreakers({{ args .Endpoints }} goa.Endpoint) *{{ .ClientVarName }} {
+	return New{{ .ClientVarName }}(
+{{- range .Endpoints }}
+	{{- if .MaxFailures }}
+		breaker.Wrap({{ .ArgName }}, breaker.New({{ .MaxFailures }}, {{ .ResetTimeout }})),
+	{{- else }}
+		{{ .ArgName }},
+	{{- end }}
+{{- end }}
+	)
+}
+`
@@ -0,0 +1,44 @@
+package breaker_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/breaker"
+	"goa.design/plugins/v3/breaker/expr"
+	"goa.design/plugins/v3/breaker/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Breakers = nil
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	fs, err := breaker.Generate("", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"gen/catalog/breaker.go":   testdata.CatalogClientInitCode,
+		"gen/inventory/breaker.go": testdata.InventoryClientInitCode,
+	}
+	if len(fs) != len(expected) {
+		t.Fatalf("got %d files, expected %d", len(fs), len(expected))
+	}
+	for _, f := range fs {
+		exp, ok := expected[f.Path]
+		if !ok {
+			t.Errorf("unexpected file %s", f.Path)
+			continue
+		}
+		sections := f.Section("breaker-client-init")
+		if len(sections) != 1 {
+			t.Fatalf("got %d breaker-client-init sections in %s, expected 1", len(sections), f.Path)
+		}
+		code := codegen.SectionCode(t, sections[0])
+		if code != exp {
+			t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", f.Path, code, codegen.Diff(t, code, exp))
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package testdata
+
+var CatalogClientInitCode = `// NewClientWithBreakers initializes a "Catalog" service client given the
+// endpoints and wraps the endpoints of the methods that define a circuit
+// breaker in the design. Each call creates new circuit breakers.
+func NewClientWithBreakers(list, export goa.Endpoint) *Client {
+	return NewClient(
+		list,
+		breaker.Wrap(export, breaker.New(2, time.Minute)),
+	)
+}
+`
+
+var InventoryClientInitCode = `// NewClientWithBreakers initializes a "Inventory" service client given the
+// endpoints and wraps the endpoints of the methods that define a circuit
+// breaker in the design. Each call creates new circuit breakers.
+func NewClientWithBreakers(count, reserve goa.Endpoint) *Client {
+	return NewClient(
+		breaker.Wrap(count, breaker.New(5, 30*time.Second)),
+		breaker.Wrap(reserve, breaker.New(1, 30*time.Second)),
+	)
+}
+`
@@ -0,0 +1,56 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	breaker "goa.design/plugins/v3/breaker/dsl"
+)
+
+var CatalogDSL = func() {
+	Service("Catalog", func() {
+		Method("List", func() {
+			Result(ArrayOf(String))
+			HTTP(func() {
+				GET("/items")
+				Response(StatusOK)
+			})
+		})
+		Method("Export", func() {
+			breaker.CircuitBreaker(func() {
+				breaker.MaxFailures(2)
+				breaker.ResetTimeout(time.Minute)
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/export")
+			})
+		})
+	})
+	Service("Inventory", func() {
+		breaker.CircuitBreaker()
+		Method("Count", func() {
+			Result(Int)
+			HTTP(func() {
+				GET("/count")
+			})
+		})
+		Method("Reserve", func() {
+			breaker.CircuitBreaker(func() {
+				breaker.MaxFailures(1)
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/reservations")
+			})
+		})
+	})
+	Service("Health", func() {
+		Method("Check", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+}
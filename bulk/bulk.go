@@ -0,0 +1,108 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Result is the outcome of a single item of a bulk request.
+type Result struct {
+	// Status is the HTTP status code of the item response.
+	Status int `json:"status"`
+	// Body is the body of the item response, a JSON string if the
+	// response is not JSON.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Handler returns a HTTP handler that serves bulk requests with h. The body
+// of a bulk request must be a JSON array of at most maxItems items. h is
+// called concurrently once per item with a copy of the request whose body is
+// the item. The handler responds with 207 Multi-Status and a JSON array of
+// results listing the status and body of the response to each item in order.
+// It responds with 400 Bad Request if the body is not a JSON array or if it
+// does not contain between 1 and maxItems items.
+func Handler(h http.Handler, maxItems int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var items []json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+			http.Error(w, fmt.Sprintf("invalid bulk request body, must be a JSON array: %s", err), http.StatusBadRequest)
+			return
+		}
+		if len(items) == 0 || len(items) > maxItems {
+			http.Error(w, fmt.Sprintf("invalid bulk request, must contain between 1 and %d items, got %d", maxItems, len(items)), http.StatusBadRequest)
+			return
+		}
+		results := make([]*Result, len(items))
+		var wg sync.WaitGroup
+		for i, item := range items {
+			wg.Add(1)
+			go func(i int, item []byte) {
+				defer wg.Done()
+				results[i] = serveItem(h, r, item)
+			}(i, item)
+		}
+		wg.Wait()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(results)
+	})
+}
+
+// serveItem calls h with a copy of r whose body is item and returns the
+// outcome.
+func serveItem(h http.Handler, r *http.Request, item []byte) *Result {
+	ir := r.WithContext(r.Context())
+	ir.Header = make(http.Header, len(r.Header))
+	for k, v := range r.Header {
+		ir.Header[k] = append([]string(nil), v...)
+	}
+	ir.Header.Set("Content-Length", strconv.Itoa(len(item)))
+	ir.ContentLength = int64(len(item))
+	ir.Body = ioutil.NopCloser(bytes.NewReader(item))
+	rec := &recorder{header: make(http.Header)}
+	h.ServeHTTP(rec, ir)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	res := &Result{Status: rec.status}
+	if body := bytes.TrimSpace(rec.body.Bytes()); len(body) > 0 {
+		if json.Valid(body) {
+			res.Body = body
+		} else {
+			res.Body, _ = json.Marshal(string(body))
+		}
+	}
+	return res
+}
+
+// recorder is a http.ResponseWriter that records the response to an item.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the response headers.
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+// Write records the response body.
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// WriteHeader records the response status code.
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
@@ -0,0 +1,62 @@
+package bulk
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if string(b) == `"fail"` {
+			http.Error(w, "item failed", http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(b)
+	})
+	cases := []struct {
+		Name    string
+		Body    string
+		Status  int
+		Results []*Result
+	}{
+		{"ok", `[{"a":1},"fail",2]`, http.StatusMultiStatus, []*Result{
+			{Status: http.StatusCreated, Body: json.RawMessage(`{"a":1}`)},
+			{Status: http.StatusUnprocessableEntity, Body: json.RawMessage(`"item failed"`)},
+			{Status: http.StatusCreated, Body: json.RawMessage(`2`)},
+		}},
+		{"not-array", `{"a":1}`, http.StatusBadRequest, nil},
+		{"empty", `[]`, http.StatusBadRequest, nil},
+		{"too-many", `[1,2,3,4]`, http.StatusBadRequest, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handler(echo, 3).ServeHTTP(w, httptest.NewRequest("POST", "/items/bulk", strings.NewReader(c.Body)))
+			if w.Code != c.Status {
+				t.Fatalf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if c.Results == nil {
+				return
+			}
+			var results []*Result
+			if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(c.Results) {
+				t.Fatalf("got %d results, expected %d", len(results), len(c.Results))
+			}
+			for i, r := range results {
+				if r.Status != c.Results[i].Status || string(r.Body) != string(c.Results[i].Body) {
+					t.Errorf("result %d: got %d %s, expected %d %s", i, r.Status, r.Body, c.Results[i].Status, c.Results[i].Body)
+				}
+			}
+		})
+	}
+}
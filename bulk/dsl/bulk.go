@@ -0,0 +1,71 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/bulk/expr"
+
+	// Register code generators for the bulk plugin
+	_ "goa.design/plugins/v3/bulk"
+)
+
+// Bulk makes the method accept bulk requests. Each HTTP route of the method
+// gets a bulk route whose path is the route path followed by "/bulk". The
+// body of the bulk requests is a JSON array of the request bodies of the
+// method. The generated handler calls the method once per item and responds
+// with 207 Multi-Status and a JSON array listing the status and body of the
+// response to each item in order.
+//
+// Bulk must appear in a Method expression. The method must define a payload
+// and cannot be streaming.
+//
+// Bulk accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import bulk "goa.design/plugins/v3/bulk/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        Method("create", func() {
+//            bulk.Bulk(func() {
+//                bulk.MaxItems(50) // Defaults to 100
+//            })
+//            Payload(Item)
+//            Result(Item)
+//            HTTP(func() {
+//                POST("/items")     // Bulk route is "POST /items/bulk"
+//                Response(StatusCreated)
+//            })
+//        })
+//    })
+//
+func Bulk(fn ...func()) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	b := &expr.BulkExpr{MaxItems: expr.DefaultMaxItems, Method: m}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], b) {
+			return
+		}
+	}
+	expr.Root.Bulks = append(expr.Root.Bulks, b)
+}
+
+// MaxItems sets the maximum number of items in a bulk request. Requests with
+// more items are rejected with 400 Bad Request.
+//
+// MaxItems must appear in a Bulk expression.
+func MaxItems(n uint) {
+	if b, ok := eval.Current().(*expr.BulkExpr); ok {
+		b.MaxItems = n
+		return
+	}
+	eval.IncompatibleDSL()
+}
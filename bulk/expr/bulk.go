@@ -0,0 +1,50 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// PathSuffix is appended to the paths of the method HTTP routes to
+	// build the paths of the bulk routes.
+	PathSuffix = "/bulk"
+	// DefaultMaxItems is the default maximum number of items in a bulk
+	// request.
+	DefaultMaxItems = 100
+)
+
+type (
+	// BulkExpr describes a method that accepts bulk requests.
+	BulkExpr struct {
+		// MaxItems is the maximum number of items in a bulk request.
+		MaxItems uint
+		// Method is the method called for each item.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (b *BulkExpr) EvalName() string {
+	return fmt.Sprintf("Bulk of %s", b.Method.EvalName())
+}
+
+// Validate ensures the bulk method is valid.
+func (b *BulkExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if b.MaxItems == 0 {
+		verr.Add(b, "maximum number of items must be greater than 0")
+	}
+	if b.Method.Payload.Type == expr.Empty {
+		verr.Add(b, "bulk methods must define a payload")
+	}
+	if b.Method.IsStreaming() {
+		verr.Add(b, "streaming methods cannot accept bulk requests")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
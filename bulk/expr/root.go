@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the bulk methods of the design.
+	RootExpr struct {
+		// Bulks lists the bulk methods in the order they appear in the
+		// design.
+		Bulks []*BulkExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "bulk plugin"
+}
+
+// WalkSets iterates over the bulk methods.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	bexps := make(eval.ExpressionSet, len(r.Bulks))
+	for i, b := range r.Bulks {
+		bexps[i] = b
+	}
+	walk(bexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/bulk/dsl"}
+}
+
+// Bulk returns the bulk definition of the given method, nil if the method
+// does not accept bulk requests.
+func (r *RootExpr) Bulk(svc, method string) *BulkExpr {
+	for _, b := range r.Bulks {
+		if b.Method.Service.Name == svc && b.Method.Name == method {
+			return b
+		}
+	}
+	return nil
+}
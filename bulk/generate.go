@@ -0,0 +1,290 @@
+package bulk
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/bulk/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// mountData contains the data necessary to render the function
+	// mounting the bulk handler of an endpoint.
+	mountData struct {
+		// MountHandler is the name of the function.
+		MountHandler string
+		// ServiceName is the name of the service.
+		ServiceName string
+		// MethodName is the name of the method.
+		MethodName string
+		// VarName is the name of the handler field of the server struct.
+		VarName string
+		// MaxItems is the maximum number of items in a bulk request.
+		MaxItems uint
+		// Routes lists the bulk routes.
+		Routes []*routeData
+	}
+
+	// routeData describes a bulk route.
+	routeData struct {
+		// Verb is the HTTP method.
+		Verb string
+		// Path is the route path.
+		Path string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "bulk",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the functions mounting the bulk handlers of each HTTP
+// server, calls them from the server Mount function and documents the bulk
+// operations in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Bulks) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("bulk", f) {
+			continue
+		}
+		documentBulk(f)
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.API.HTTP.Services {
+				if !config.Enabled("bulk", svc.Name()) {
+					continue
+				}
+				mounts := bulkMounts(svc)
+				if len(mounts) == 0 {
+					continue
+				}
+				serverBulk(files, svc, mounts)
+				files = append(files, bulkFile(svc, mounts))
+			}
+		}
+	}
+	return files, nil
+}
+
+// bulkMounts returns the data needed to mount the bulk handlers of the given
+// HTTP service.
+func bulkMounts(svc *goaexpr.HTTPServiceExpr) []*mountData {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var mounts []*mountData
+	for _, ed := range data.Endpoints {
+		b := expr.Root.Bulk(svc.Name(), ed.Method.Name)
+		if b == nil {
+			continue
+		}
+		m := &mountData{
+			MountHandler: strings.TrimSuffix(ed.MountHandler, "Handler") + "BulkHandler",
+			ServiceName:  ed.ServiceName,
+			MethodName:   ed.Method.Name,
+			VarName:      ed.Method.VarName,
+			MaxItems:     b.MaxItems,
+		}
+		for _, r := range ed.Routes {
+			m.Routes = append(m.Routes, &routeData{Verb: r.Verb, Path: bulkPath(r.Path)})
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts
+}
+
+// bulkFile returns the file defining the functions mounting the bulk handlers
+// of the given HTTP service.
+func bulkFile(svc *goaexpr.HTTPServiceExpr, mounts []*mountData) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name()+" HTTP server bulk handlers", "server", []*codegen.ImportSpec{
+			{Path: "net/http"},
+			codegen.GoaNamedImport("http", "goahttp"),
+			{Path: "goa.design/plugins/v3/bulk"},
+		}),
+	}
+	for _, m := range mounts {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "bulk-mount-handler",
+			Source: mountBulkHandlerT,
+			Data:   m,
+		})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(data.Service.VarName), "server", "bulk.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// serverBulk lists the bulk routes in the mount points of the given HTTP
+// server and mounts the bulk handlers in its Mount function.
+func serverBulk(files []*codegen.File, svc *goaexpr.HTTPServiceExpr, mounts []*mountData) {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var points, calls string
+	for _, m := range mounts {
+		for _, r := range m.Routes {
+			points += fmt.Sprintf("\n\t\t\t{%q, %q, %q},", m.VarName, r.Verb, r.Path)
+		}
+		calls += fmt.Sprintf("\n\t%s(mux, h.%s)", m.MountHandler, m.VarName)
+	}
+	for _, f := range files {
+		if filepath.Base(f.Path) != "server.go" {
+			continue
+		}
+		for _, s := range f.SectionTemplates {
+			if s.Data != data {
+				continue
+			}
+			switch s.Name {
+			case "server-init":
+				s.Source = strings.Replace(s.Source, "{{- range .FileServers }}", strings.TrimPrefix(points, "\n\t\t\t")+"\n\t\t\t{{- range .FileServers }}", 1)
+			case "server-mount":
+				s.Source = strings.Replace(s.Source, "{{- range .FileServers }}", strings.TrimPrefix(calls, "\n\t")+"\n\t{{- range .FileServers }}", 1)
+			}
+		}
+	}
+}
+
+// documentBulk adds the bulk operations to the OpenAPI specification if f is
+// an OpenAPI file.
+func documentBulk(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		bulks := make(map[string]interface{})
+		for key, p := range spec.Paths {
+			path, ok := p.(*openapi.Path)
+			if !ok || strings.HasSuffix(key, expr.PathSuffix) {
+				continue
+			}
+			bp := &openapi.Path{}
+			bp.Get = bulkOperation(path.Get)
+			bp.Put = bulkOperation(path.Put)
+			bp.Post = bulkOperation(path.Post)
+			bp.Delete = bulkOperation(path.Delete)
+			bp.Patch = bulkOperation(path.Patch)
+			if bp.Get != nil || bp.Put != nil || bp.Post != nil || bp.Delete != nil || bp.Patch != nil {
+				bulks[bulkPath(key)] = bp
+			}
+		}
+		// The JSON and YAML OpenAPI files share the same specification,
+		// the bulk paths are only added the first time.
+		for key, bp := range bulks {
+			if _, ok := spec.Paths[key]; !ok {
+				spec.Paths[key] = bp
+			}
+		}
+		return nil
+	})
+}
+
+// bulkOperation returns the bulk operation corresponding to the given
+// operation, nil if the operation does not accept bulk requests or is already
+// a bulk operation.
+func bulkOperation(op *openapi.Operation) *openapi.Operation {
+	if op == nil || strings.HasSuffix(op.OperationID, "#bulk") {
+		return nil
+	}
+	var b *expr.BulkExpr
+	m := walk.OperationMethod(goaexpr.Root, op)
+	for _, bb := range expr.Root.Bulks {
+		if m != nil && bb.Method == m {
+			b = bb
+			break
+		}
+	}
+	if b == nil {
+		return nil
+	}
+	minItems, maxItems := 1, int(b.MaxItems)
+	items := &openapi.Schema{Type: openapi.Object}
+	var params []*openapi.Parameter
+	for _, p := range op.Parameters {
+		if p.In == "body" {
+			items = p.Schema
+			continue
+		}
+		params = append(params, p)
+	}
+	params = append(params, &openapi.Parameter{
+		Name:        "items",
+		In:          "body",
+		Description: "Request bodies of the items.",
+		Required:    true,
+		Schema: &openapi.Schema{
+			Type:     openapi.Array,
+			Items:    items,
+			MinItems: &minItems,
+			MaxItems: &maxItems,
+		},
+	})
+	body := &openapi.Schema{Description: "Body of the item response, the error if the item failed."}
+	for code, resp := range op.Responses {
+		if strings.HasPrefix(code, "2") && resp.Schema != nil {
+			body.AnyOf = append(body.AnyOf, resp.Schema)
+		}
+	}
+	summary := "Bulk " + op.Summary
+	if op.Summary == "" {
+		summary = "Bulk " + b.Method.Name
+	}
+	return &openapi.Operation{
+		Tags:        op.Tags,
+		Summary:     summary,
+		Description: fmt.Sprintf("Calls the %q method once per item of the request body.", b.Method.Name),
+		OperationID: op.OperationID + "#bulk",
+		Consumes:    []string{"application/json"},
+		Produces:    []string{"application/json"},
+		Parameters:  params,
+		Responses: map[string]*openapi.Response{
+			"207": {
+				Description: "Multi-Status response listing the outcome of each item in order.",
+				Schema: &openapi.Schema{
+					Type: openapi.Array,
+					Items: &openapi.Schema{
+						Type: openapi.Object,
+						Properties: map[string]*openapi.Schema{
+							"status": {Type: openapi.Integer, Description: "HTTP status code of the item response."},
+							"body":   body,
+						},
+						Required: []string{"status"},
+					},
+				},
+			},
+			"400": {Description: "Bad Request response, the body is not a JSON array or its number of items is invalid."},
+		},
+		Schemes:    op.Schemes,
+		Deprecated: op.Deprecated,
+		Security:   op.Security,
+	}
+}
+
+// bulkPath returns the path of the bulk route corresponding to the given
+// route path.
+func bulkPath(path string) string {
+	return strings.TrimSuffix(path, "/") + expr.PathSuffix
+}
+
+// input: mountData
+const mountBulkHandlerT = `{{ printf "%s configures the mux to serve the bulk requests of the %q service %q endpoint." .MountHandler .ServiceName .MethodName | comment }}
+func {{ .MountHandler }}(mux goahttp.Muxer, h http.Handler) {
+	f := bulk.Handler(h, {{ .MaxItems }}).ServeHTTP
+	{{- range .Routes }}
+	mux.Handle({{ printf "%q" .Verb }}, {{ printf "%q" .Path }}, f)
+	{{- end }}
+}
+`
@@ -0,0 +1,83 @@
+package bulk_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/bulk"
+	"goa.design/plugins/v3/bulk/expr"
+	"goa.design/plugins/v3/bulk/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	expr.Root.Bulks = nil
+	httpcodegen.RunHTTPDSL(t, testdata.BulkDSL)
+	fs := httpcodegen.ServerFiles("", goaexpr.Root)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = bulk.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, f := range fs {
+		var names, expected []string
+		switch filepath.Base(f.Path) {
+		case "server.go":
+			names = []string{"server-init", "server-mount"}
+			expected = []string{testdata.ServerInitCode, testdata.MountCode}
+		case "bulk.go":
+			found = true
+			names = []string{"bulk-mount-handler"}
+			expected = []string{testdata.MountBulkHandlerCode}
+		default:
+			continue
+		}
+		for i, name := range names {
+			sections := f.Section(name)
+			if len(sections) != 1 {
+				t.Fatalf("got %d %s sections, expected 1", len(sections), name)
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != expected[i] {
+				t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, expected[i]))
+			}
+		}
+	}
+	if !found {
+		t.Error("bulk.go file not generated")
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	p, ok := spec.Paths["/items/bulk"].(*openapi.Path)
+	if !ok || p.Post == nil {
+		t.Fatal("bulk operation not found in OpenAPI spec")
+	}
+	if _, ok := p.Post.Responses["207"]; !ok {
+		t.Error("207 response not found in bulk operation")
+	}
+	var body *openapi.Parameter
+	for _, param := range p.Post.Parameters {
+		if param.In == "body" {
+			body = param
+		}
+	}
+	if body == nil || body.Schema.Type != openapi.Array || *body.Schema.MaxItems != 50 {
+		t.Errorf("invalid bulk request body parameter %+v", body)
+	}
+	if _, ok := spec.Paths["/items/{id}/bulk"]; ok {
+		t.Error("unexpected bulk operation for method without Bulk")
+	}
+	if _, ok := spec.Paths["/items/bulk/bulk"]; ok {
+		t.Error("unexpected bulk operation for bulk operation")
+	}
+}
@@ -0,0 +1,37 @@
+package testdata
+
+var ServerInitCode = `// New instantiates HTTP handlers for all the Catalog service endpoints.
+func New(
+	e *catalog.Endpoints,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) *Server {
+	return &Server{
+		Mounts: []*MountPoint{
+			{"Create", "POST", "/items"},
+			{"Show", "GET", "/items/{id}"},
+			{"Create", "POST", "/items/bulk"},
+		},
+		Create: NewCreateHandler(e.Create, mux, dec, enc, eh),
+		Show:   NewShowHandler(e.Show, mux, dec, enc, eh),
+	}
+}
+`
+
+var MountCode = `// Mount configures the mux to serve the Catalog endpoints.
+func Mount(mux goahttp.Muxer, h *Server) {
+	MountCreateHandler(mux, h.Create)
+	MountShowHandler(mux, h.Show)
+	MountCreateBulkHandler(mux, h.Create)
+}
+`
+
+var MountBulkHandlerCode = `// MountCreateBulkHandler configures the mux to serve the bulk requests of the
+// "Catalog" service "Create" endpoint.
+func MountCreateBulkHandler(mux goahttp.Muxer, h http.Handler) {
+	f := bulk.Handler(h, 50).ServeHTTP
+	mux.Handle("POST", "/items/bulk", f)
+}
+`
@@ -0,0 +1,39 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	bulk "goa.design/plugins/v3/bulk/dsl"
+)
+
+var BulkDSL = func() {
+	Service("Catalog", func() {
+		Method("Create", func() {
+			bulk.Bulk(func() {
+				bulk.MaxItems(50)
+			})
+			Payload(func() {
+				Attribute("name", String)
+				Required("name")
+			})
+			Result(func() {
+				Attribute("id", String)
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				POST("/items")
+				Response(StatusCreated)
+			})
+		})
+		Method("Show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+	})
+}
@@ -0,0 +1,54 @@
+package bulkhead
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Limiter limits the number of requests handled concurrently by an HTTP
+// handler.
+type Limiter struct {
+	sem        chan struct{}
+	retryAfter string
+}
+
+// NewLimiter returns a limiter which lets at most n requests through
+// concurrently and asks the clients of the rejected requests to retry after
+// the given delay.
+func NewLimiter(n int, retryAfter time.Duration) *Limiter {
+	secs := int((retryAfter + time.Second - 1) / time.Second)
+	return &Limiter{sem: make(chan struct{}, n), retryAfter: strconv.Itoa(secs)}
+}
+
+// Acquire returns true if the request may be handled, in which case Release
+// must be called once the request is handled. Otherwise it writes a 503
+// Service Unavailable response with the Retry-After header and returns false.
+func (l *Limiter) Acquire(w http.ResponseWriter) bool {
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	default:
+		w.Header().Set("Retry-After", l.retryAfter)
+		http.Error(w, "too many concurrent requests", http.StatusServiceUnavailable)
+		return false
+	}
+}
+
+// Release releases the slot acquired by a successful call to Acquire.
+func (l *Limiter) Release() {
+	<-l.sem
+}
+
+// Handler returns a handler which calls h with at most n concurrent requests
+// and rejects the other requests as described in Acquire.
+func Handler(h http.Handler, n int, retryAfter time.Duration) http.Handler {
+	l := NewLimiter(n, retryAfter)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Acquire(w) {
+			return
+		}
+		defer l.Release()
+		h.ServeHTTP(w, r)
+	})
+}
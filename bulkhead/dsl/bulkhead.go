@@ -0,0 +1,75 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/bulkhead/expr"
+
+	// Register code generators for the bulkhead plugin
+	_ "goa.design/plugins/v3/bulkhead"
+)
+
+// MaxConcurrentRequests sets the maximum number of requests handled
+// concurrently by the HTTP endpoint of the enclosing method, or by each HTTP
+// endpoint of the enclosing service. A method limit overrides the limit of
+// its service.
+//
+// The generated HTTP handlers reject the requests received while the endpoint
+// is saturated with a 503 Service Unavailable response whose Retry-After
+// header is set to 1 second unless specified otherwise with RetryAfter.
+//
+// MaxConcurrentRequests must appear in a Service or Method expression.
+//
+// MaxConcurrentRequests accepts the limit as first argument and an optional
+// DSL function as second argument.
+//
+// Example:
+//
+//    import bulkhead "goa.design/plugins/v3/bulkhead/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        bulkhead.MaxConcurrentRequests(100)
+//        Method("export", func() {
+//            bulkhead.MaxConcurrentRequests(2, func() {
+//                bulkhead.RetryAfter(30 * time.Second)
+//            })
+//        })
+//    })
+//
+func MaxConcurrentRequests(n int, fn ...func()) {
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	l := &expr.LimitExpr{MaxRequests: n, RetryAfter: expr.DefaultRetryAfter}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.ServiceExpr:
+		l.Service = actual
+	case *goaexpr.MethodExpr:
+		l.Method = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], l) {
+			return
+		}
+	}
+	expr.Root.Limits = append(expr.Root.Limits, l)
+}
+
+// RetryAfter sets the delay returned in the Retry-After header of the
+// responses to the requests rejected while the endpoint is saturated. The
+// delay must be a whole number of seconds.
+//
+// RetryAfter must appear in a MaxConcurrentRequests expression.
+func RetryAfter(d time.Duration) {
+	if l, ok := eval.Current().(*expr.LimitExpr); ok {
+		l.RetryAfter = d
+		return
+	}
+	eval.IncompatibleDSL()
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// DefaultRetryAfter is the default delay after which the clients of a
+// saturated endpoint may retry.
+const DefaultRetryAfter = time.Second
+
+type (
+	// LimitExpr describes the maximum number of requests handled
+	// concurrently by each HTTP endpoint of a service or by the HTTP
+	// endpoint of a method.
+	LimitExpr struct {
+		// MaxRequests is the maximum number of concurrent requests.
+		MaxRequests int
+		// RetryAfter is the delay returned in the Retry-After header
+		// of the responses to the rejected requests.
+		RetryAfter time.Duration
+		// Service is the service the limit applies to, nil for method
+		// limits.
+		Service *expr.ServiceExpr
+		// Method is the method the limit applies to, nil for service
+		// limits.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LimitExpr) EvalName() string {
+	if l.Method != nil {
+		return fmt.Sprintf("concurrency limit of %s", l.Method.EvalName())
+	}
+	return fmt.Sprintf("concurrency limit of %s", l.Service.EvalName())
+}
+
+// Validate makes sure the limit is positive and that the retry delay is a
+// whole number of seconds.
+func (l *LimitExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if l.MaxRequests <= 0 {
+		verr.Add(l, "max concurrent requests must be positive")
+	}
+	if l.RetryAfter <= 0 || l.RetryAfter%time.Second != 0 {
+		verr.Add(l, "retry after must be a positive whole number of seconds")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,111 @@
+package bulkhead
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/bulkhead/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "bulkhead",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate limits the number of concurrent requests in the HTTP handlers of
+// the methods that define a concurrency limit and documents the responses to
+// the rejected requests in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Limits) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("bulkhead", f) {
+			continue
+		}
+		serverLimit(f)
+		documentLimit(f)
+	}
+	return files, nil
+}
+
+// serverLimit makes the HTTP handlers of the methods that define a concurrency
+// limit acquire a slot of their limiter before handling the requests.
+func serverLimit(f *codegen.File) {
+	if filepath.Base(f.Path) != "server.go" {
+		return
+	}
+	for _, s := range f.Section("server-handler-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok {
+			continue
+		}
+		l := expr.Root.Limit(ed.ServiceName, ed.Method.Name)
+		if l == nil {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "time"},
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/bulkhead"})
+		retry := "time.Second"
+		if secs := l.RetryAfter / time.Second; secs != 1 {
+			retry = fmt.Sprintf("%d * time.Second", secs)
+		}
+		handler := "return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {"
+		s.Source = strings.Replace(s.Source, handler,
+			fmt.Sprintf("limiter := bulkhead.NewLimiter(%d, %s)\n\t", l.MaxRequests, retry)+
+				handler+"\n\t\tif !limiter.Acquire(w) {\n\t\t\treturn\n\t\t}\n\t\tdefer limiter.Release()", 1)
+	}
+}
+
+// documentLimit adds the response returned when the endpoint is saturated to
+// the operations that define a concurrency limit if f is an OpenAPI file.
+func documentLimit(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("bulkhead", op) {
+				return nil
+			}
+			l := limit(op)
+			if l == nil {
+				return nil
+			}
+			status := strconv.Itoa(http.StatusServiceUnavailable)
+			if _, ok := op.Responses[status]; !ok {
+				op.Responses[status] = &openapi.Response{
+					Description: fmt.Sprintf("%s response returned when the endpoint is already handling %d requests.", http.StatusText(http.StatusServiceUnavailable), l.MaxRequests),
+					Headers: map[string]*openapi.Header{
+						"Retry-After": {Description: "Number of seconds after which the request may be retried.", Type: "integer"},
+					},
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// limit returns the concurrency limit of the method corresponding to the
+// given operation, nil if the method has none.
+func limit(op *openapi.Operation) *expr.LimitExpr {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	if m == nil {
+		return nil
+	}
+	return expr.Root.Limit(m.Service.Name, m.Name)
+}
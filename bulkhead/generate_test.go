@@ -0,0 +1,53 @@
+package bulkhead_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/bulkhead"
+	"goa.design/plugins/v3/bulkhead/expr"
+	"goa.design/plugins/v3/bulkhead/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Limits = nil
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	fs := httpcodegen.ServerFiles("", goaexpr.Root)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = bulkhead.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	limited := map[string]bool{"NewListHandler": true, "NewExportHandler": true, "NewCheckHandler": false}
+	for _, f := range fs {
+		for _, s := range f.Section("server-handler-init") {
+			ed := s.Data.(*httpcodegen.EndpointData)
+			code := codegen.SectionCode(t, s)
+			if ed.HandlerInit == "NewExportHandler" && code != testdata.ExportHandlerCode {
+				t.Errorf("invalid handler code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ExportHandlerCode))
+			}
+			if got := strings.Contains(code, "limiter.Acquire(w)"); got != limited[ed.HandlerInit] {
+				t.Errorf("%s: got limited %v, expected %v", ed.HandlerInit, got, limited[ed.HandlerInit])
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	for path, expected := range map[string]bool{"/items": true, "/export": true, "/health": false} {
+		resp, ok := spec.Paths[path].(*openapi.Path).Get.Responses["503"]
+		if ok != expected {
+			t.Errorf("%s: got 503 response %v, expected %v", path, ok, expected)
+			continue
+		}
+		if ok && resp.Headers["Retry-After"] == nil {
+			t.Errorf("%s: Retry-After header not documented", path)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package testdata
+
+var ExportHandlerCode = `// NewExportHandler creates a HTTP handler which loads the HTTP request and
+// calls the "Catalog" service "Export" endpoint.
+func NewExportHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		encodeResponse = EncodeExportResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	limiter := bulkhead.NewLimiter(2, 30*time.Second)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.Acquire(w) {
+			return
+		}
+		defer limiter.Release()
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Export")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Catalog")
+
+		res, err := endpoint(ctx, nil)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
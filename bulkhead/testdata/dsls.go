@@ -0,0 +1,38 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	bulkhead "goa.design/plugins/v3/bulkhead/dsl"
+)
+
+var CatalogDSL = func() {
+	Service("Catalog", func() {
+		bulkhead.MaxConcurrentRequests(100)
+		Method("List", func() {
+			Result(ArrayOf(String))
+			HTTP(func() {
+				GET("/items")
+				Response(StatusOK)
+			})
+		})
+		Method("Export", func() {
+			bulkhead.MaxConcurrentRequests(2, func() {
+				bulkhead.RetryAfter(30 * time.Second)
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/export")
+			})
+		})
+	})
+	Service("Health", func() {
+		Method("Check", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package cachecontrol
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Set sets the caching headers of a response. The Cache-Control header is set
+// to value and the Expires header to the current time plus maxAge seconds, or
+// to "0" if maxAge is 0, unless the headers are already set. The vary headers
+// are added to the Vary header. Nothing is set for the Cache-Control and
+// Expires headers if value is empty.
+func Set(w http.ResponseWriter, value string, maxAge int, vary ...string) {
+	h := w.Header()
+	if value != "" && h.Get("Cache-Control") == "" {
+		h.Set("Cache-Control", value)
+		if h.Get("Expires") == "" {
+			if maxAge > 0 {
+				h.Set("Expires", time.Now().Add(time.Duration(maxAge)*time.Second).UTC().Format(http.TimeFormat))
+			} else {
+				h.Set("Expires", strconv.Itoa(0))
+			}
+		}
+	}
+	for _, v := range vary {
+		h.Add("Vary", v)
+	}
+}
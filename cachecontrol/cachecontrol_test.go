@@ -0,0 +1,59 @@
+package cachecontrol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	cases := []struct {
+		Name         string
+		Value        string
+		MaxAge       int
+		Vary         []string
+		Preset       string
+		CacheControl string
+		Expires      bool
+		VaryHeader   []string
+	}{
+		{"max-age", "public, max-age=60", 60, nil, "", "public, max-age=60", true, nil},
+		{"no-store", "no-store", 0, nil, "", "no-store", false, nil},
+		{"vary", "private, max-age=0", 0, []string{"Accept", "Cookie"}, "", "private, max-age=0", false, []string{"Accept", "Cookie"}},
+		{"preset", "public, max-age=60", 60, nil, "no-cache", "no-cache", false, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if c.Preset != "" {
+				w.Header().Set("Cache-Control", c.Preset)
+			}
+			Set(w, c.Value, c.MaxAge, c.Vary...)
+			if got := w.Header().Get("Cache-Control"); got != c.CacheControl {
+				t.Errorf("got Cache-Control %q, expected %q", got, c.CacheControl)
+			}
+			expires := w.Header().Get("Expires")
+			if c.Expires {
+				exp, err := http.ParseTime(expires)
+				if err != nil {
+					t.Fatalf("invalid Expires header %q: %s", expires, err)
+				}
+				if d := time.Until(exp); d <= 0 || d > time.Duration(c.MaxAge)*time.Second {
+					t.Errorf("got Expires %q, expected about %ds from now", expires, c.MaxAge)
+				}
+			} else if c.Preset == "" && expires != "0" {
+				t.Errorf("got Expires %q, expected \"0\"", expires)
+			}
+			vary := w.Header()["Vary"]
+			if len(vary) != len(c.VaryHeader) {
+				t.Fatalf("got Vary %v, expected %v", vary, c.VaryHeader)
+			}
+			for i, v := range vary {
+				if v != c.VaryHeader[i] {
+					t.Errorf("got Vary %v, expected %v", vary, c.VaryHeader)
+				}
+			}
+		})
+	}
+}
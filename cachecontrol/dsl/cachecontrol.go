@@ -0,0 +1,93 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/cachecontrol/expr"
+
+	// Register code generators for the cache control plugin
+	_ "goa.design/plugins/v3/cachecontrol"
+)
+
+// Cache-Control response directives accepted by CacheControl.
+const (
+	// Public allows shared caches to store the response.
+	Public = "public"
+	// Private restricts caching to the client.
+	Private = "private"
+	// NoCache requires caches to revalidate the response before use.
+	NoCache = "no-cache"
+	// NoStore forbids caching the response.
+	NoStore = "no-store"
+	// NoTransform forbids intermediaries from transforming the response.
+	NoTransform = "no-transform"
+	// MustRevalidate forbids serving the response once stale.
+	MustRevalidate = "must-revalidate"
+	// ProxyRevalidate is MustRevalidate for shared caches only.
+	ProxyRevalidate = "proxy-revalidate"
+	// Immutable indicates the response never changes while fresh.
+	Immutable = "immutable"
+)
+
+// CacheControl sets the caching headers of the successful HTTP responses of
+// the method. The Cache-Control header combines the max-age directive with
+// the given directives and the Expires header is set to the current time plus
+// maxAge. The headers are documented in the OpenAPI specification.
+//
+// CacheControl must appear in a Method expression.
+//
+// Example:
+//
+//    import cache "goa.design/plugins/v3/cachecontrol/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        Method("list", func() {
+//            cache.CacheControl(5*time.Minute, cache.Public, cache.MustRevalidate)
+//            cache.Vary("Accept-Language")
+//            Result(CollectionOf(Item))
+//            HTTP(func() {
+//                GET("/items")
+//            })
+//        })
+//    })
+//
+func CacheControl(maxAge time.Duration, directives ...string) {
+	p := policy()
+	if p == nil {
+		return
+	}
+	p.MaxAge = maxAge
+	p.Directives = append(p.Directives, directives...)
+}
+
+// Vary adds request headers to the Vary header of the successful HTTP
+// responses of the method so that caches store one response per value of
+// the headers.
+//
+// Vary must appear in a Method expression.
+func Vary(headers ...string) {
+	p := policy()
+	if p == nil {
+		return
+	}
+	p.Vary = append(p.Vary, headers...)
+}
+
+// policy returns the caching policy of the current method, creating it if
+// needed. It reports an error and returns nil if the current expression is
+// not a method.
+func policy() *expr.PolicyExpr {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if p := expr.Root.Policy(m.Service.Name, m.Name); p != nil {
+		return p
+	}
+	p := &expr.PolicyExpr{Method: m}
+	expr.Root.Policies = append(expr.Root.Policies, p)
+	return p
+}
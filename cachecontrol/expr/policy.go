@@ -0,0 +1,106 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// directives lists the supported Cache-Control response directives.
+var directives = map[string]bool{
+	"public":           true,
+	"private":          true,
+	"no-cache":         true,
+	"no-store":         true,
+	"no-transform":     true,
+	"must-revalidate":  true,
+	"proxy-revalidate": true,
+	"immutable":        true,
+}
+
+type (
+	// PolicyExpr describes the caching headers of the successful responses
+	// of a method.
+	PolicyExpr struct {
+		// MaxAge is the duration during which the responses are fresh.
+		MaxAge time.Duration
+		// Directives lists the Cache-Control directives other than
+		// max-age.
+		Directives []string
+		// Vary lists the request headers that select the cached
+		// response.
+		Vary []string
+		// Method is the method the policy applies to.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PolicyExpr) EvalName() string {
+	return fmt.Sprintf("cache control of %s", p.Method.EvalName())
+}
+
+// Validate makes sure the max age and directives are consistent.
+func (p *PolicyExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if p.MaxAge < 0 {
+		verr.Add(p, "max age cannot be negative")
+	}
+	if p.MaxAge%time.Second != 0 {
+		verr.Add(p, "max age must be a whole number of seconds")
+	}
+	for _, d := range p.Directives {
+		if !directives[d] {
+			verr.Add(p, "unknown directive %q", d)
+		}
+	}
+	if p.Has("public") && p.Has("private") {
+		verr.Add(p, "public and private directives are mutually exclusive")
+	}
+	if p.Has("no-store") && p.MaxAge > 0 {
+		verr.Add(p, "no-store directive cannot be combined with a max age")
+	}
+	for _, h := range p.Vary {
+		if h == "" || strings.ContainsAny(h, " ,") {
+			verr.Add(p, "invalid Vary header name %q", h)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Has returns true if the policy uses the given directive.
+func (p *PolicyExpr) Has(directive string) bool {
+	for _, d := range p.Directives {
+		if d == directive {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the value of the Cache-Control header. The public or private
+// directive comes first followed by the max-age directive and the other
+// directives.
+func (p *PolicyExpr) Value() string {
+	var ds []string
+	for _, d := range p.Directives {
+		if d == "public" || d == "private" {
+			ds = append(ds, d)
+		}
+	}
+	if !p.Has("no-store") {
+		ds = append(ds, fmt.Sprintf("max-age=%d", p.MaxAge/time.Second))
+	}
+	for _, d := range p.Directives {
+		if d != "public" && d != "private" {
+			ds = append(ds, d)
+		}
+	}
+	return strings.Join(ds, ", ")
+}
@@ -0,0 +1,125 @@
+package cachecontrol
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/cachecontrol/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "cachecontrol",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate sets the caching headers in the response encoders of the methods
+// that define a caching policy and documents the headers in the OpenAPI
+// specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Policies) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("cachecontrol", f) {
+			continue
+		}
+		serverCacheControl(f)
+		documentCacheControl(f)
+	}
+	return files, nil
+}
+
+// serverCacheControl modifies the HTTP response encoders of the methods that
+// define a caching policy.
+func serverCacheControl(f *codegen.File) {
+	if filepath.Base(f.Path) != "encode_decode.go" {
+		return
+	}
+	for _, s := range f.SectionTemplates {
+		if s.Name != "response-encoder" {
+			continue
+		}
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok {
+			continue
+		}
+		p := expr.Root.Policy(ed.ServiceName, ed.Method.Name)
+		if p == nil {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/cachecontrol"})
+		args := []string{fmt.Sprintf("%q", p.Value()), fmt.Sprint(int(p.MaxAge / time.Second))}
+		for _, v := range p.Vary {
+			args = append(args, fmt.Sprintf("%q", v))
+		}
+		decl := "return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {"
+		s.Source = strings.Replace(s.Source, decl,
+			decl+"\n\t\tcachecontrol.Set(w, "+strings.Join(args, ", ")+")", 1)
+	}
+}
+
+// documentCacheControl documents the caching headers of the successful
+// responses of the operations that define a caching policy if f is an
+// OpenAPI file.
+func documentCacheControl(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("cachecontrol", op) {
+				return nil
+			}
+			pol := policy(op)
+			if pol == nil {
+				return nil
+			}
+			headers := map[string]*openapi.Header{
+				"Cache-Control": {Description: fmt.Sprintf("Caching directives of the response, always %q.", pol.Value()), Type: "string"},
+				"Expires":       {Description: "Date after which the response is stale.", Type: "string"},
+			}
+			if len(pol.Vary) > 0 {
+				headers["Vary"] = &openapi.Header{Description: fmt.Sprintf("Request headers selecting the response, always %q.", strings.Join(pol.Vary, ", ")), Type: "string"}
+			}
+			// The JSON and YAML OpenAPI files share the same
+			// specification so the operations may be visited twice,
+			// setting the headers is idempotent.
+			for code, resp := range op.Responses {
+				if !strings.HasPrefix(code, "2") || resp == nil {
+					continue
+				}
+				if resp.Headers == nil {
+					resp.Headers = make(map[string]*openapi.Header)
+				}
+				for n, h := range headers {
+					resp.Headers[n] = h
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// policy returns the caching policy corresponding to the given operation, nil
+// if the operation does not define one.
+func policy(op *openapi.Operation) *expr.PolicyExpr {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	for _, p := range expr.Root.Policies {
+		if m != nil && p.Method == m {
+			return p
+		}
+	}
+	return nil
+}
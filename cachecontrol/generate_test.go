@@ -0,0 +1,72 @@
+package cachecontrol_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/cachecontrol"
+	"goa.design/plugins/v3/cachecontrol/expr"
+	"goa.design/plugins/v3/cachecontrol/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name        string
+		DSL         func()
+		EncoderCode string
+		Path        string
+		Headers     []string
+	}{
+		{"public", testdata.PublicDSL, testdata.PublicResponseEncoderCode, "/items", []string{"Cache-Control", "Expires", "Vary"}},
+		{"no-store", testdata.NoStoreDSL, testdata.NoStoreResponseEncoderCode, "/items/{id}", []string{"Cache-Control", "Expires"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Policies = nil
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs := httpcodegen.ServerFiles("", goaexpr.Root)
+			ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err = cachecontrol.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range fs {
+				if filepath.Base(f.Path) != "encode_decode.go" {
+					continue
+				}
+				sections := f.Section("response-encoder")
+				if len(sections) != 1 {
+					t.Fatalf("got %d response-encoder sections, expected 1", len(sections))
+				}
+				code := codegen.SectionCode(t, sections[0])
+				if code != c.EncoderCode {
+					t.Errorf("invalid response-encoder code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.EncoderCode))
+				}
+			}
+			spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+			op := spec.Paths[c.Path].(*openapi.Path).Get
+			var headers map[string]*openapi.Header
+			for code, resp := range op.Responses {
+				if code[0] == '2' {
+					headers = resp.Headers
+				}
+			}
+			if len(headers) != len(c.Headers) {
+				t.Errorf("got %d response headers in OpenAPI spec, expected %d", len(headers), len(c.Headers))
+			}
+			for _, h := range c.Headers {
+				if _, ok := headers[h]; !ok {
+					t.Errorf("%s header not found in OpenAPI spec", h)
+				}
+			}
+		})
+	}
+}
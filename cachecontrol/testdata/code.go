@@ -0,0 +1,29 @@
+package testdata
+
+var PublicResponseEncoderCode = `// EncodeListResponse returns an encoder for responses returned by the Catalog
+// List endpoint.
+func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		cachecontrol.Set(w, "public, max-age=300, must-revalidate", 300, "Accept-Language", "Accept-Encoding")
+		res := v.(*catalog.ListResult)
+		enc := encoder(ctx, w)
+		body := NewListResponseBody(res)
+		w.WriteHeader(http.StatusNoContent)
+		return enc.Encode(body)
+	}
+}
+`
+
+var NoStoreResponseEncoderCode = `// EncodeShowResponse returns an encoder for responses returned by the Catalog
+// Show endpoint.
+func EncodeShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		cachecontrol.Set(w, "private, no-store", 0)
+		res := v.(*catalog.ShowResult)
+		enc := encoder(ctx, w)
+		body := NewShowResponseBody(res)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+`
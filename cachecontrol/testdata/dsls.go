@@ -0,0 +1,40 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	cache "goa.design/plugins/v3/cachecontrol/dsl"
+)
+
+var PublicDSL = func() {
+	Service("Catalog", func() {
+		Method("List", func() {
+			cache.CacheControl(5*time.Minute, cache.Public, cache.MustRevalidate)
+			cache.Vary("Accept-Language", "Accept-Encoding")
+			Result(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
+
+var NoStoreDSL = func() {
+	Service("Catalog", func() {
+		Method("Show", func() {
+			cache.CacheControl(0, cache.Private, cache.NoStore)
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+	})
+}
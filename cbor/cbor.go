@@ -0,0 +1,70 @@
+package cbor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	fxcbor "github.com/fxamacker/cbor/v2"
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/plugins/v3/encoding"
+)
+
+// MediaType is the CBOR media type.
+const MediaType = "application/cbor"
+
+// Format is the CBOR format: application/cbor and the media types with the
+// +cbor suffix.
+var Format = &encoding.Format{
+	MediaType: MediaType,
+	Match: func(mt string) bool {
+		return mt == MediaType || strings.HasSuffix(mt, "+cbor")
+	},
+	Codec: encoding.NewCodec(NewEncoder, NewDecoder),
+}
+
+// WrapRequestDecoder returns a HTTP request decoder that decodes the bodies of
+// the requests whose Content-Type is CBOR and uses dec for the other requests.
+func WrapRequestDecoder(dec func(*http.Request) goahttp.Decoder) func(*http.Request) goahttp.Decoder {
+	return Format.WrapRequestDecoder(dec)
+}
+
+// WrapResponseEncoder returns a HTTP response encoder that encodes the
+// responses with CBOR if the response content type set in the design or the
+// first media type of the request Accept header is CBOR and uses enc
+// otherwise.
+func WrapResponseEncoder(enc func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter) goahttp.Encoder {
+	return Format.WrapResponseEncoder(enc)
+}
+
+// WrapResponseDecoder returns a HTTP response decoder that decodes the bodies
+// of the responses whose Content-Type is CBOR and uses dec for the other
+// responses.
+func WrapResponseDecoder(dec func(*http.Response) goahttp.Decoder) func(*http.Response) goahttp.Decoder {
+	return Format.WrapResponseDecoder(dec)
+}
+
+// RequestEncoder is a HTTP request encoder that encodes the request bodies
+// with CBOR. It also sets the Accept header so that the servers generated with
+// the plugin respond with CBOR.
+func RequestEncoder(r *http.Request) goahttp.Encoder {
+	return Format.RequestEncoder(r)
+}
+
+// NewEncoder returns a CBOR encoder writing to w. The encoder names the fields
+// after the json struct tags of the generated types.
+func NewEncoder(w io.Writer) goahttp.Encoder {
+	return fxcbor.NewEncoder(w)
+}
+
+// NewDecoder returns a CBOR decoder reading from r.
+func NewDecoder(r io.Reader) goahttp.Decoder {
+	return fxcbor.NewDecoder(r)
+}
+
+// IsCBOR returns true if the given Content-Type or Accept header value is
+// application/cbor or a media type with the +cbor suffix.
+func IsCBOR(ct string) bool {
+	return Format.Is(ct)
+}
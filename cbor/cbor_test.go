@@ -0,0 +1,68 @@
+package cbor
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	fxcbor "github.com/fxamacker/cbor/v2"
+	goahttp "goa.design/goa/v3/http"
+)
+
+type reading struct {
+	Device *string  `json:"device,omitempty"`
+	Value  *float64 `json:"value,omitempty"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	device, value := "probe", 21.5
+	r := httptest.NewRequest("POST", "/readings", nil)
+	if err := RequestEncoder(r).Encode(&reading{Device: &device, Value: &value}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded reading
+	if err := WrapRequestDecoder(goahttp.RequestDecoder)(r).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Device == nil || *decoded.Device != device || decoded.Value == nil || *decoded.Value != value {
+		t.Errorf("got %+v, expected device %q and value %v", decoded, device, value)
+	}
+
+	ctx := context.WithValue(context.Background(), goahttp.ContentTypeKey, MediaType)
+	w := httptest.NewRecorder()
+	if err := WrapResponseEncoder(goahttp.ResponseEncoder)(ctx, w).Encode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != MediaType {
+		t.Errorf("got Content-Type %q, expected %q", ct, MediaType)
+	}
+	var fields map[string]interface{}
+	if err := fxcbor.Unmarshal(w.Body.Bytes(), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["device"] != device {
+		t.Errorf("got fields %v, expected the json tag names", fields)
+	}
+	var res reading
+	if err := WrapResponseDecoder(goahttp.ResponseDecoder)(w.Result()).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Device == nil || *res.Device != device {
+		t.Errorf("got response %+v, expected device %q", res, device)
+	}
+}
+
+func TestIsCBOR(t *testing.T) {
+	cases := map[string]bool{
+		"application/cbor":                true,
+		"application/senml+cbor":          true,
+		"application/cbor; charset=utf-8": true,
+		"application/json":                false,
+		"":                                false,
+	}
+	for ct, expected := range cases {
+		if got := IsCBOR(ct); got != expected {
+			t.Errorf("%q: got %v, expected %v", ct, got, expected)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cbor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "cbor",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate makes the HTTP handlers and clients of the endpoints that respond
+// with CBOR encode and decode CBOR bodies and documents the CBOR request
+// bodies in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok || r.API == nil || r.API.HTTP == nil {
+			continue
+		}
+		for _, f := range files {
+			if !config.EnabledFile("cbor", f) {
+				continue
+			}
+			switch filepath.Base(f.Path) {
+			case "server.go":
+				serverCBOR(f, r)
+			case "client.go":
+				clientCBOR(f, r)
+			default:
+				documentCBOR(f, r)
+			}
+		}
+	}
+	return files, nil
+}
+
+// serverCBOR wraps the request decoder and response encoder of the HTTP
+// handlers of the CBOR endpoints with their CBOR counterparts.
+func serverCBOR(f *codegen.File, r *goaexpr.RootExpr) {
+	for _, s := range f.Section("server-handler-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || ed.ServerStream != nil || !isCBOR(r, ed.ServiceName, ed.Method.Name) {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/cbor"})
+		s.Source = strings.Replace(s.Source, ") http.Handler {",
+			") http.Handler {\n\tdec = cbor.WrapRequestDecoder(dec)\n\tenc = cbor.WrapResponseEncoder(enc)", 1)
+	}
+}
+
+// clientCBOR wraps the response decoder of the HTTP client endpoints of the
+// CBOR endpoints with its CBOR counterpart.
+func clientCBOR(f *codegen.File, r *goaexpr.RootExpr) {
+	for _, s := range f.Section("client-endpoint-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || ed.ClientStream != nil || !isCBOR(r, ed.ServiceName, ed.Method.Name) {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/cbor"})
+		s.Source = strings.Replace(s.Source, "(c.decoder, c.RestoreResponseBody)",
+			"(cbor.WrapResponseDecoder(c.decoder), c.RestoreResponseBody)", 1)
+	}
+}
+
+// documentCBOR adds CBOR to the media types consumed by the operations of the
+// CBOR endpoints that accept a request body if f is an OpenAPI file.
+func documentCBOR(f *codegen.File, r *goaexpr.RootExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("cbor", op) || !walk.HasBody(op) || !isCBOROperation(r, op) {
+				return nil
+			}
+			if len(op.Consumes) == 0 {
+				op.Consumes = append([]string{}, spec.Consumes...)
+			}
+			op.Consumes = walk.AppendMediaType(op.Consumes, MediaType)
+			return nil
+		})
+	})
+}
+
+// isCBOR returns true if one of the responses of the HTTP endpoint of the
+// given method has a CBOR content type.
+func isCBOR(r *goaexpr.RootExpr, svc, method string) bool {
+	hs := r.API.HTTP.Service(svc)
+	if hs == nil {
+		return false
+	}
+	e := hs.Endpoint(method)
+	if e == nil {
+		return false
+	}
+	for _, resp := range e.Responses {
+		if IsCBOR(resp.ContentType) {
+			return true
+		}
+	}
+	for _, he := range e.HTTPErrors {
+		if IsCBOR(he.Response.ContentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCBOROperation returns true if the given operation corresponds to a CBOR
+// endpoint.
+func isCBOROperation(r *goaexpr.RootExpr, op *openapi.Operation) bool {
+	m := walk.OperationMethod(r, op)
+	return m != nil && isCBOR(r, m.Service.Name, m.Name)
+}
@@ -0,0 +1,63 @@
+package cbor_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/cbor"
+	"goa.design/plugins/v3/cbor/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.SensorDSL)
+	fs := append(httpcodegen.ServerFiles("", goaexpr.Root), httpcodegen.ClientFiles("", goaexpr.Root)...)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = cbor.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]map[string]string{
+		"server-handler-init":  {"Record": testdata.RecordHandlerCode},
+		"client-endpoint-init": {"Record": testdata.RecordClientCode},
+	}
+	for _, f := range fs {
+		for name, codes := range expected {
+			for _, s := range f.Section(name) {
+				ed := s.Data.(*httpcodegen.EndpointData)
+				code := codegen.SectionCode(t, s)
+				exp, ok := codes[ed.Method.Name]
+				if !ok {
+					if strings.Contains(code, "cbor.") {
+						t.Errorf("%s: unexpected CBOR support in %s", ed.Method.Name, name)
+					}
+					continue
+				}
+				if code != exp {
+					t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, exp))
+				}
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	op := spec.Paths["/readings"].(*openapi.Path).Post
+	var consumes bool
+	for _, mt := range op.Consumes {
+		if mt == cbor.MediaType {
+			consumes = true
+		}
+	}
+	if !consumes {
+		t.Errorf("got consumes %v, expected %s", op.Consumes, cbor.MediaType)
+	}
+	if len(op.Produces) != 1 || op.Produces[0] != cbor.MediaType {
+		t.Errorf("got produces %v, expected %s", op.Produces, cbor.MediaType)
+	}
+}
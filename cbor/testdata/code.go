@@ -0,0 +1,70 @@
+package testdata
+
+var RecordHandlerCode = `// NewRecordHandler creates a HTTP handler which loads the HTTP request and
+// calls the "Sensor" service "Record" endpoint.
+func NewRecordHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	dec = cbor.WrapRequestDecoder(dec)
+	enc = cbor.WrapResponseEncoder(enc)
+	var (
+		decodeRequest  = DecodeRecordRequest(mux, dec)
+		encodeResponse = EncodeRecordResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Record")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Sensor")
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, payload)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
+
+var RecordClientCode = `// Record returns an endpoint that makes HTTP requests to the Sensor service
+// Record server.
+func (c *Client) Record() goa.Endpoint {
+	var (
+		encodeRequest  = EncodeRecordRequest(c.encoder)
+		decodeResponse = DecodeRecordResponse(cbor.WrapResponseDecoder(c.decoder), c.RestoreResponseBody)
+	)
+	return func(ctx context.Context, v interface{}) (interface{}, error) {
+		req, err := c.BuildRecordRequest(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		err = encodeRequest(req, v)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.RecordDoer.Do(req)
+
+		if err != nil {
+			return nil, goahttp.ErrRequestError("Sensor", "Record", err)
+		}
+		return decodeResponse(resp)
+	}
+}
+`
@@ -0,0 +1,31 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var SensorDSL = func() {
+	Service("Sensor", func() {
+		Method("Record", func() {
+			Payload(func() {
+				Attribute("device", String)
+				Attribute("value", Float64)
+			})
+			Result(func() {
+				Attribute("id", String)
+			})
+			HTTP(func() {
+				POST("/readings")
+				Response(StatusCreated, func() {
+					ContentType("application/cbor")
+				})
+			})
+		})
+		Method("List", func() {
+			Result(ArrayOf(Float64))
+			HTTP(func() {
+				GET("/readings")
+			})
+		})
+	})
+}
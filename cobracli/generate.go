@@ -0,0 +1,591 @@
+package cobracli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/example"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// rootData contains the data necessary to render the root command of
+	// a server command line interface.
+	rootData struct {
+		// Use is the name of the command line tool.
+		Use string
+		// Short is the description of the command line tool.
+		Short string
+		// URL is the default URL of the server.
+		URL string
+		// Services lists the service commands.
+		Services []*serviceData
+		// HasEnums is true if a flag completes enum values.
+		HasEnums bool
+	}
+
+	// serviceData describes the command grouping the commands of a
+	// service.
+	serviceData struct {
+		// Name is the name of the service.
+		Name string
+		// Use is the name of the command.
+		Use string
+		// Short is the description of the command.
+		Short string
+		// FuncName is the name of the function returning the command.
+		FuncName string
+		// ClientPkg is the name of the service HTTP client package.
+		ClientPkg string
+		// NeedStream is true if the client constructor accepts a
+		// websocket dialer.
+		NeedStream bool
+		// Methods lists the method commands.
+		Methods []*methodData
+	}
+
+	// methodData describes the command sending requests to an endpoint.
+	methodData struct {
+		// Name is the name of the method.
+		Name string
+		// Use is the name of the command.
+		Use string
+		// Short is the description of the command.
+		Short string
+		// FuncName is the name of the function returning the command.
+		FuncName string
+		// VarName is the name of the client method returning the
+		// endpoint.
+		VarName string
+		// PayloadInit is the expression initializing the payload of an
+		// object payload, empty if the payload is not an object.
+		PayloadInit string
+		// PayloadRef is the reference to the payload type, empty if the
+		// method has no payload.
+		PayloadRef string
+		// Flags lists the command flags.
+		Flags []*flagData
+	}
+
+	// flagData describes a command flag set from a payload attribute.
+	flagData struct {
+		// Name is the name of the flag.
+		Name string
+		// VarName is the name of the variable holding the flag value.
+		VarName string
+		// Type is the Go type of the flag variable.
+		Type string
+		// Setter is the name of the pflag function defining the flag,
+		// e.g. "IntVar". It is "StringVar" for the flags holding a
+		// JSON representation of the value.
+		Setter string
+		// JSON is true if the flag holds the JSON representation of the
+		// value.
+		JSON bool
+		// Default is the Go literal of the flag default value.
+		Default string
+		// Usage is the flag description.
+		Usage string
+		// Required is true if the flag must be set.
+		Required bool
+		// Field is the name of the payload struct field set by the flag,
+		// empty if the flag sets the payload itself.
+		Field string
+		// Pointer is true if the payload field is a pointer.
+		Pointer bool
+		// Values lists the values suggested by the shell completion.
+		Values []string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "cobracli",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+	registry.Register(&registry.Plugin{
+		Name:     "cobracli-example",
+		Cmd:      "example",
+		Generate: Example,
+	})
+}
+
+// Generate produces a cobra based command line interface for each server
+// which defines typed flags for the payload attributes and shell completion.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			if r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+				continue
+			}
+			for _, svr := range r.API.Servers {
+				if f := commandsFile(genpkg, r, svr); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// Example replaces the main function of the example command line tools with
+// one executing the cobra root command. The tools of the APIs that define
+// gRPC services are left untouched.
+func Example(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("cobracli", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		r, ok := root.(*expr.RootExpr)
+		if !ok || r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+			continue
+		}
+		if r.API.GRPC != nil && len(r.API.GRPC.Services) > 0 {
+			continue
+		}
+		for _, svr := range r.API.Servers {
+			dir := example.Servers.Get(svr).Dir
+			path := filepath.Join("cmd", dir+"-cli", "main.go")
+			for _, f := range files {
+				if f.Path != path {
+					continue
+				}
+				f.SectionTemplates = []*codegen.SectionTemplate{
+					codegen.Header("", "main", []*codegen.ImportSpec{
+						{Path: "os"},
+						codegen.GoaNamedImport("http", "goahttp"),
+						{Path: genpkg + "/http/cli/" + dir, Name: "cli"},
+					}),
+					{Name: "cobracli-main", Source: mainT},
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// commandsFile returns the file defining the commands of the given server,
+// nil if the server does not expose HTTP services.
+func commandsFile(genpkg string, r *expr.RootExpr, svr *expr.ServerExpr) *codegen.File {
+	svrdata := example.Servers.Get(svr)
+	data := &rootData{
+		Use:   svrdata.Dir + "-cli",
+		Short: fmt.Sprintf("Command line interface of the %s server", svr.Name),
+		URL:   defaultURL(svrdata),
+	}
+	if svr.Description != "" {
+		data.Short = firstLine(svr.Description)
+	}
+	specs := []*codegen.ImportSpec{
+		{Path: "encoding/json"},
+		{Path: "fmt"},
+		{Path: "net/http"},
+		{Path: "net/url"},
+		{Path: "time"},
+		{Path: "github.com/spf13/cobra"},
+		codegen.GoaImport(""),
+		codegen.GoaNamedImport("http", "goahttp"),
+	}
+	for _, name := range svr.Services {
+		if !config.Enabled("cobracli", name) {
+			continue
+		}
+		hsvc := r.API.HTTP.Service(name)
+		if hsvc == nil {
+			continue
+		}
+		hd := httpcodegen.HTTPServices.Get(name)
+		sd := service.Services.Get(name)
+		sdata := &serviceData{
+			Name:       name,
+			Use:        codegen.KebabCase(name),
+			Short:      fmt.Sprintf("Send requests to the %s service", name),
+			FuncName:   "new" + codegen.Goify(name, true) + "Command",
+			ClientPkg:  sd.PkgName + "c",
+			NeedStream: needStream(hd),
+		}
+		if sd.Description != "" {
+			sdata.Short = firstLine(sd.Description)
+		}
+		var needSvc bool
+		for _, ed := range hd.Endpoints {
+			if ed.ServerStream != nil || ed.ClientStream != nil {
+				continue
+			}
+			m := buildMethod(r, sd, sdata, ed)
+			if strings.Contains(m.PayloadRef, sd.PkgName+".") {
+				needSvc = true
+			}
+			for _, f := range m.Flags {
+				if len(f.Values) > 0 {
+					data.HasEnums = true
+				}
+			}
+			sdata.Methods = append(sdata.Methods, m)
+		}
+		if len(sdata.Methods) == 0 {
+			continue
+		}
+		specs = append(specs, &codegen.ImportSpec{
+			Path: genpkg + "/http/" + codegen.SnakeCase(sd.VarName) + "/client",
+			Name: sdata.ClientPkg,
+		})
+		if needSvc {
+			specs = append(specs, &codegen.ImportSpec{Path: genpkg + "/" + codegen.SnakeCase(sd.VarName), Name: sd.PkgName})
+		}
+		data.Services = append(data.Services, sdata)
+	}
+	if len(data.Services) == 0 {
+		return nil
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svr.Name+" cobra command line interface", "cli", specs),
+		{Name: "cobracli-root", Source: rootT, Data: data},
+	}
+	for _, s := range data.Services {
+		sections = append(sections, &codegen.SectionTemplate{Name: "cobracli-service", Source: serviceT, Data: s})
+		for _, m := range s.Methods {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:    "cobracli-method",
+				Source:  methodT,
+				Data:    map[string]interface{}{"Service": s, "Method": m},
+				FuncMap: map[string]interface{}{"join": strings.Join},
+			})
+		}
+	}
+	sections = append(sections, &codegen.SectionTemplate{Name: "cobracli-helpers", Source: helpersT, Data: data})
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", "cli", svrdata.Dir, "cobra.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// buildMethod returns the data of the command sending requests to the given
+// endpoint.
+func buildMethod(r *expr.RootExpr, sd *service.Data, svc *serviceData, ed *httpcodegen.EndpointData) *methodData {
+	md := ed.Method
+	data := &methodData{
+		Name:     md.Name,
+		Use:      codegen.KebabCase(md.Name),
+		Short:    fmt.Sprintf("Send a request to the %s endpoint", md.Name),
+		FuncName: "new" + codegen.Goify(svc.Name, true) + md.VarName + "Command",
+		VarName:  md.VarName,
+	}
+	if md.Description != "" {
+		data.Short = firstLine(md.Description)
+	}
+	if md.PayloadRef == "" {
+		return data
+	}
+	payload := r.Service(svc.Name).Method(md.Name).Payload
+	data.PayloadRef = sd.Scope.GoFullTypeRef(payload, sd.PkgName)
+	obj := expr.AsObject(payload.Type)
+	if obj == nil {
+		f := buildFlag("payload", "payload", payload)
+		if f.Usage == "" {
+			f.Usage = "Request payload"
+		}
+		f.Required = true
+		data.Flags = append(data.Flags, f)
+		return data
+	}
+	data.PayloadInit = "&" + sd.Scope.GoFullTypeName(payload, sd.PkgName) + "{}"
+	for _, nat := range *obj {
+		f := buildFlag(nat.Name, codegen.Goify(nat.Name, false), nat.Attribute)
+		f.Field = codegen.GoifyAtt(nat.Attribute, nat.Name, true)
+		f.Pointer = payload.IsPrimitivePointer(nat.Name, true) && !f.JSON
+		f.Required = payload.IsRequiredNoDefault(nat.Name)
+		if !f.JSON && nat.Attribute.DefaultValue != nil && expr.IsPrimitive(nat.Attribute.Type) {
+			f.Default = fmt.Sprintf("%#v", nat.Attribute.DefaultValue)
+		}
+		data.Flags = append(data.Flags, f)
+	}
+	return data
+}
+
+// buildFlag returns the flag setting the given attribute. Primitive
+// attributes and arrays of primitives use typed flags, other attributes use
+// flags holding the JSON representation of the value.
+func buildFlag(name, varName string, att *expr.AttributeExpr) *flagData {
+	f := &flagData{
+		Name:    codegen.KebabCase(name),
+		VarName: varName + "Flag",
+		Usage:   firstLine(att.Description),
+	}
+	if _, ok := att.Type.(expr.UserType); !ok {
+		f.Setter, f.Type, f.Default = setter(att.Type)
+	}
+	if f.Setter == "" {
+		f.Setter, f.Type, f.Default, f.JSON = "StringVar", "string", `""`, true
+		if f.Usage == "" {
+			f.Usage = "JSON encoded value"
+		} else {
+			f.Usage += " (JSON encoded)"
+		}
+	}
+	if v := att.Validation; v != nil && !f.JSON {
+		for _, val := range v.Values {
+			f.Values = append(f.Values, fmt.Sprint(val))
+		}
+	}
+	return f
+}
+
+// setter returns the name of the pflag function, the Go type and the zero
+// value of the flag setting a value of the given type. It returns empty
+// strings if the type cannot be set with a typed flag.
+func setter(dt expr.DataType) (string, string, string) {
+	switch dt.Kind() {
+	case expr.BooleanKind:
+		return "BoolVar", "bool", "false"
+	case expr.IntKind:
+		return "IntVar", "int", "0"
+	case expr.Int32Kind:
+		return "Int32Var", "int32", "0"
+	case expr.Int64Kind:
+		return "Int64Var", "int64", "0"
+	case expr.UIntKind:
+		return "UintVar", "uint", "0"
+	case expr.UInt32Kind:
+		return "Uint32Var", "uint32", "0"
+	case expr.UInt64Kind:
+		return "Uint64Var", "uint64", "0"
+	case expr.Float32Kind:
+		return "Float32Var", "float32", "0"
+	case expr.Float64Kind:
+		return "Float64Var", "float64", "0"
+	case expr.StringKind:
+		return "StringVar", "string", `""`
+	case expr.BytesKind:
+		return "BytesBase64Var", "[]byte", "nil"
+	case expr.ArrayKind:
+		elem := expr.AsArray(dt).ElemType.Type
+		if _, ok := elem.(expr.UserType); ok {
+			return "", "", ""
+		}
+		switch elem.Kind() {
+		case expr.BooleanKind:
+			return "BoolSliceVar", "[]bool", "nil"
+		case expr.IntKind:
+			return "IntSliceVar", "[]int", "nil"
+		case expr.Int32Kind:
+			return "Int32SliceVar", "[]int32", "nil"
+		case expr.Int64Kind:
+			return "Int64SliceVar", "[]int64", "nil"
+		case expr.UIntKind:
+			return "UintSliceVar", "[]uint", "nil"
+		case expr.Float32Kind:
+			return "Float32SliceVar", "[]float32", "nil"
+		case expr.Float64Kind:
+			return "Float64SliceVar", "[]float64", "nil"
+		case expr.StringKind:
+			return "StringSliceVar", "[]string", "nil"
+		}
+	}
+	return "", "", ""
+}
+
+// needStream returns true if the service defines streaming endpoints in which
+// case the client constructor accepts a websocket dialer.
+func needStream(data *httpcodegen.ServiceData) bool {
+	for _, ed := range data.Endpoints {
+		if ed.ServerStream != nil || ed.ClientStream != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultURL returns the first HTTP URL of the server with the host variables
+// replaced with their default values.
+func defaultURL(svr *example.Data) string {
+	for _, h := range svr.Hosts {
+		u := h.DefaultURL(example.TransportHTTP)
+		if u == "" {
+			continue
+		}
+		for _, v := range h.Variables {
+			u = strings.Replace(u, "{"+v.Name+"}", v.DefaultValue, -1)
+		}
+		return u
+	}
+	return "http://localhost:80"
+}
+
+// firstLine returns the first line of the given text.
+func firstLine(s string) string {
+	return strings.TrimSpace(strings.SplitN(s, "\n", 2)[0])
+}
+
+// input: rootData
+const rootT = `// NewRootCommand returns the root command of the {{ .Use }} command line
+// interface. The command groups one subcommand per service which groups one
+// subcommand per method. The requests are encoded and the responses decoded
+// using the given encoder and decoder. The root command also defines the
+// "completion" command which generates the bash, zsh and fish completion
+// scripts.
+func NewRootCommand(enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restore bool) *cobra.Command {
+	c := &commandConfig{enc: enc, dec: dec, restore: restore}
+	root := &cobra.Command{
+		Use:          {{ printf "%q" .Use }},
+		Short:        {{ printf "%q" .Short }},
+		SilenceUsage: true,
+	}
+	root.PersistentFlags().StringVar(&c.url, "url", {{ printf "%q" .URL }}, "URL to service host")
+	root.PersistentFlags().IntVar(&c.timeout, "timeout", 30, "Maximum number of seconds to wait for response")
+	root.PersistentFlags().BoolVarP(&c.verbose, "verbose", "v", false, "Print request and response details")
+	root.AddCommand(
+	{{- range .Services }}
+		{{ .FuncName }}(c),
+	{{- end }}
+	)
+	return root
+}
+`
+
+// input: serviceData
+const serviceT = `{{ printf "%s returns the command grouping the commands of the %q service." .FuncName .Name | comment }}
+func {{ .FuncName }}(c *commandConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   {{ printf "%q" .Use }},
+		Short: {{ printf "%q" .Short }},
+	}
+	cmd.AddCommand(
+	{{- range .Methods }}
+		{{ .FuncName }}(c),
+	{{- end }}
+	)
+	return cmd
+}
+`
+
+// input: map[string]interface{}{"Service": serviceData, "Method": methodData}
+const methodT = `{{ with .Method }}{{ printf "%s returns the command sending requests to the %q endpoint of the %q service." .FuncName .Name $.Service.Name | comment }}
+func {{ .FuncName }}(c *commandConfig) *cobra.Command {
+	{{- if .Flags }}
+	var (
+		{{- range .Flags }}
+		{{ .VarName }} {{ .Type }}
+		{{- end }}
+	)
+	{{- end }}
+	cmd := &cobra.Command{
+		Use:   {{ printf "%q" .Use }},
+		Short: {{ printf "%q" .Short }},
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+		{{- if .PayloadRef }}
+			{{- if .PayloadInit }}
+			p := {{ .PayloadInit }}
+			{{- else }}
+			var p {{ .PayloadRef }}
+			{{- end }}
+			{{- range .Flags }}
+				{{- $target := "p" }}{{ if .Field }}{{ $target = printf "p.%s" .Field }}{{ end }}
+				{{- if .JSON }}
+			if {{ .VarName }} != "" {
+				if err := json.Unmarshal([]byte({{ .VarName }}), &{{ $target }}); err != nil {
+					return fmt.Errorf("invalid JSON for --{{ .Name }} flag: %s", err)
+				}
+			}
+				{{- else if .Pointer }}
+			if cmd.Flags().Changed({{ printf "%q" .Name }}) {
+				{{ $target }} = &{{ .VarName }}
+			}
+				{{- else }}
+			{{ $target }} = {{ .VarName }}
+				{{- end }}
+			{{- end }}
+		{{- end }}
+			scheme, host, doer, err := c.connect()
+			if err != nil {
+				return err
+			}
+			client := {{ $.Service.ClientPkg }}.NewClient(scheme, host, doer, c.enc, c.dec, c.restore{{ if $.Service.NeedStream }}, nil, nil{{ end }})
+			return c.run(cmd, client.{{ .VarName }}(), {{ if .PayloadRef }}p{{ else }}nil{{ end }})
+		},
+	}
+	{{- range .Flags }}
+	cmd.Flags().{{ .Setter }}(&{{ .VarName }}, {{ printf "%q" .Name }}, {{ .Default }}, {{ printf "%q" .Usage }})
+		{{- if .Required }}
+	cmd.MarkFlagRequired({{ printf "%q" .Name }})
+		{{- end }}
+		{{- if .Values }}
+	cmd.RegisterFlagCompletionFunc({{ printf "%q" .Name }}, completeValues({{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}))
+		{{- end }}
+	{{- end }}
+	return cmd
+}
+{{ end }}`
+
+// input: rootData
+const helpersT = `// commandConfig holds the settings shared by the commands.
+type commandConfig struct {
+	url     string
+	timeout int
+	verbose bool
+	enc     func(*http.Request) goahttp.Encoder
+	dec     func(*http.Response) goahttp.Decoder
+	restore bool
+}
+
+// connect parses the URL flag and returns the scheme and host of the server
+// as well as the HTTP client used to send the requests.
+func (c *commandConfig) connect() (string, string, goahttp.Doer, error) {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid URL %#v: %s", c.url, err)
+	}
+	var doer goahttp.Doer = &http.Client{Timeout: time.Duration(c.timeout) * time.Second}
+	if c.verbose {
+		doer = goahttp.NewDebugDoer(doer)
+	}
+	return u.Scheme, u.Host, doer, nil
+}
+
+// run sends the request using the given endpoint and prints the JSON
+// representation of the result.
+func (c *commandConfig) run(cmd *cobra.Command, endpoint goa.Endpoint, p interface{}) error {
+	res, err := endpoint(cmd.Context(), p)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return nil
+	}
+	m, err := json.MarshalIndent(res, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(m))
+	return nil
+}
+{{- if .HasEnums }}
+
+// completeValues returns a flag completion function which suggests the given
+// values.
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+{{- end }}
+`
+
+// input: none
+const mainT = `func main() {
+	root := cli.NewRootCommand(goahttp.RequestEncoder, goahttp.ResponseDecoder, false)
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+`
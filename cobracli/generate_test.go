@@ -0,0 +1,59 @@
+package cobracli_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/cobracli"
+	"goa.design/plugins/v3/cobracli/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.RunHTTPDSL(t, testdata.CommandsDSL)
+	fs, err := cobracli.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/http/cli/calc/cobra.go" {
+		t.Errorf("got path %q, expected gen/http/cli/calc/cobra.go", fs[0].Path)
+	}
+	var sections []string
+	for _, s := range fs[0].SectionTemplates[1:] {
+		sections = append(sections, codegen.SectionCode(t, s))
+	}
+	code := strings.Join(sections, "\n")
+	if code != testdata.CommandsCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.CommandsCode))
+	}
+}
+
+func TestExample(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.RunHTTPDSL(t, testdata.CommandsDSL)
+	main := &codegen.File{
+		Path:             "cmd/calc-cli/main.go",
+		SectionTemplates: []*codegen.SectionTemplate{{Name: "cli-main-start"}},
+	}
+	fs, err := cobracli.Example("gen", []eval.Root{goaexpr.Root}, []*codegen.File{main})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if len(main.Section("cli-main-start")) != 0 {
+		t.Error("the example main function was not removed")
+	}
+	if len(main.Section("cobracli-main")) != 1 {
+		t.Error("the cobra main function was not added")
+	}
+}
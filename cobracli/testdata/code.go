@@ -0,0 +1,179 @@
+package testdata
+
+var CommandsCode = `// NewRootCommand returns the root command of the calc-cli command line
+// interface. The command groups one subcommand per service which groups one
+// subcommand per method. The requests are encoded and the responses decoded
+// using the given encoder and decoder. The root command also defines the
+// "completion" command which generates the bash, zsh and fish completion
+// scripts.
+func NewRootCommand(enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, restore bool) *cobra.Command {
+	c := &commandConfig{enc: enc, dec: dec, restore: restore}
+	root := &cobra.Command{
+		Use:          "calc-cli",
+		Short:        "Command line interface of the calc server",
+		SilenceUsage: true,
+	}
+	root.PersistentFlags().StringVar(&c.url, "url", "http://localhost:8088", "URL to service host")
+	root.PersistentFlags().IntVar(&c.timeout, "timeout", 30, "Maximum number of seconds to wait for response")
+	root.PersistentFlags().BoolVarP(&c.verbose, "verbose", "v", false, "Print request and response details")
+	root.AddCommand(
+		newCalcCommand(c),
+	)
+	return root
+}
+
+// newCalcCommand returns the command grouping the commands of the "calc"
+// service.
+func newCalcCommand(c *commandConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "calc",
+		Short: "The calc service performs operations on numbers.",
+	}
+	cmd.AddCommand(
+		newCalcAddCommand(c),
+		newCalcEchoCommand(c),
+		newCalcResetCommand(c),
+	)
+	return cmd
+}
+
+// newCalcAddCommand returns the command sending requests to the "add" endpoint
+// of the "calc" service.
+func newCalcAddCommand(c *commandConfig) *cobra.Command {
+	var (
+		aFlag       int
+		bFlag       int
+		modeFlag    string
+		tagsFlag    []string
+		operandFlag string
+	)
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add adds up the two integer parameters.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := &calc.AddPayload{}
+			p.A = aFlag
+			p.B = bFlag
+			if cmd.Flags().Changed("mode") {
+				p.Mode = &modeFlag
+			}
+			p.Tags = tagsFlag
+			if operandFlag != "" {
+				if err := json.Unmarshal([]byte(operandFlag), &p.Operand); err != nil {
+					return fmt.Errorf("invalid JSON for --operand flag: %s", err)
+				}
+			}
+			scheme, host, doer, err := c.connect()
+			if err != nil {
+				return err
+			}
+			client := calcc.NewClient(scheme, host, doer, c.enc, c.dec, c.restore)
+			return c.run(cmd, client.Add(), p)
+		},
+	}
+	cmd.Flags().IntVar(&aFlag, "a", 0, "Left operand")
+	cmd.MarkFlagRequired("a")
+	cmd.Flags().IntVar(&bFlag, "b", 1, "Right operand")
+	cmd.Flags().StringVar(&modeFlag, "mode", "", "Rounding mode")
+	cmd.RegisterFlagCompletionFunc("mode", completeValues("up", "down"))
+	cmd.Flags().StringSliceVar(&tagsFlag, "tags", nil, "Tags")
+	cmd.Flags().StringVar(&operandFlag, "operand", "", "Extra operand (JSON encoded)")
+	return cmd
+}
+
+// newCalcEchoCommand returns the command sending requests to the "echo"
+// endpoint of the "calc" service.
+func newCalcEchoCommand(c *commandConfig) *cobra.Command {
+	var (
+		payloadFlag string
+	)
+	cmd := &cobra.Command{
+		Use:   "echo",
+		Short: "Echo implements echo.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var p string
+			p = payloadFlag
+			scheme, host, doer, err := c.connect()
+			if err != nil {
+				return err
+			}
+			client := calcc.NewClient(scheme, host, doer, c.enc, c.dec, c.restore)
+			return c.run(cmd, client.Echo(), p)
+		},
+	}
+	cmd.Flags().StringVar(&payloadFlag, "payload", "", "Request payload")
+	cmd.MarkFlagRequired("payload")
+	return cmd
+}
+
+// newCalcResetCommand returns the command sending requests to the "reset"
+// endpoint of the "calc" service.
+func newCalcResetCommand(c *commandConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Reset implements reset.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheme, host, doer, err := c.connect()
+			if err != nil {
+				return err
+			}
+			client := calcc.NewClient(scheme, host, doer, c.enc, c.dec, c.restore)
+			return c.run(cmd, client.Reset(), nil)
+		},
+	}
+	return cmd
+}
+
+// commandConfig holds the settings shared by the commands.
+type commandConfig struct {
+	url     string
+	timeout int
+	verbose bool
+	enc     func(*http.Request) goahttp.Encoder
+	dec     func(*http.Response) goahttp.Decoder
+	restore bool
+}
+
+// connect parses the URL flag and returns the scheme and host of the server
+// as well as the HTTP client used to send the requests.
+func (c *commandConfig) connect() (string, string, goahttp.Doer, error) {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid URL %#v: %s", c.url, err)
+	}
+	var doer goahttp.Doer = &http.Client{Timeout: time.Duration(c.timeout) * time.Second}
+	if c.verbose {
+		doer = goahttp.NewDebugDoer(doer)
+	}
+	return u.Scheme, u.Host, doer, nil
+}
+
+// run sends the request using the given endpoint and prints the JSON
+// representation of the result.
+func (c *commandConfig) run(cmd *cobra.Command, endpoint goa.Endpoint, p interface{}) error {
+	res, err := endpoint(cmd.Context(), p)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return nil
+	}
+	m, err := json.MarshalIndent(res, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(m))
+	return nil
+}
+
+// completeValues returns a flag completion function which suggests the given
+// values.
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+`
@@ -0,0 +1,54 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CommandsDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			Host("development", func() {
+				URI("http://localhost:8088")
+			})
+		})
+	})
+	var Operand = Type("Operand", func() {
+		Attribute("value", Int)
+		Attribute("unit", String)
+	})
+	Service("calc", func() {
+		Description("The calc service performs operations on numbers.")
+		Method("add", func() {
+			Description("Add adds up the two integer parameters.")
+			Payload(func() {
+				Attribute("a", Int, "Left operand")
+				Attribute("b", Int, "Right operand", func() {
+					Default(1)
+				})
+				Attribute("mode", String, "Rounding mode", func() {
+					Enum("up", "down")
+				})
+				Attribute("tags", ArrayOf(String), "Tags")
+				Attribute("operand", Operand, "Extra operand")
+				Required("a")
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/add/{a}")
+				Param("mode")
+			})
+		})
+		Method("echo", func() {
+			Payload(String)
+			Result(String)
+			HTTP(func() {
+				POST("/echo")
+			})
+		})
+		Method("reset", func() {
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+	})
+}
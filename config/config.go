@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// FileName is the name of the configuration file looked up in the
+	// working directory of the gen command.
+	FileName = "goa-plugins.yaml"
+	// EnvVar is the name of the environment variable holding the path to
+	// the configuration file. It overrides the default location.
+	EnvVar = "GOA_PLUGINS_CONFIG"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the plugin settings defined in the design.
+	RootExpr struct {
+		// File is the path to the configuration file set in the design,
+		// empty to use the default location.
+		File string
+		// Plugins lists the plugin settings defined in the design in
+		// the order they appear.
+		Plugins []*PluginExpr
+	}
+
+	// PluginExpr describes the settings of a plugin defined in the design.
+	// The settings override the ones read from the configuration file.
+	PluginExpr struct {
+		// Name is the name of the plugin.
+		Name string
+		// Settings lists the settings indexed by key.
+		Settings map[string]interface{}
+	}
+
+	// Validator is the interface implemented by the plugin configurations
+	// which validate their settings.
+	Validator interface {
+		// Validate returns an error if the configuration is invalid.
+		Validate() error
+	}
+)
+
+// registry lists the functions returning the default configuration of the
+// registered plugins indexed by plugin name.
+var registry = make(map[string]func() interface{})
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// Register registers the configuration of the given plugin. defaults returns a
+// pointer to a struct holding the default configuration. The struct fields are
+// set from the plugin settings using their yaml tags. Register is meant to be
+// called by the plugins in their init function.
+func Register(plugin string, defaults func() interface{}) {
+	registry[plugin] = defaults
+}
+
+// Get returns the configuration of the given plugin: the default configuration
+// overridden by the plugin section of the configuration file and by the
+// settings defined in the design. It returns an error if the settings cannot
+// be decoded, contain unknown keys or if the configuration does not validate.
+// The returned value has the type of the value returned by the function given
+// to Register.
+func Get(plugin string) (interface{}, error) {
+	defaults, ok := registry[plugin]
+	if !ok {
+		return nil, fmt.Errorf("no configuration registered for plugin %q", plugin)
+	}
+	sections, path, err := Root.load()
+	if err != nil {
+		return nil, err
+	}
+	c := defaults()
+	if s, ok := sections[plugin]; ok {
+		if err := decode(s, c); err != nil {
+			return nil, fmt.Errorf("invalid %q section in %s: %s", plugin, path, err)
+		}
+	}
+	for _, p := range Root.Plugins {
+		if p.Name != plugin {
+			continue
+		}
+		if err := decode(p.Settings, c); err != nil {
+			return nil, fmt.Errorf("invalid %q settings in design: %s", plugin, err)
+		}
+	}
+	if v, ok := c.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %q configuration: %s", plugin, err)
+		}
+	}
+	return c, nil
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "plugin configuration"
+}
+
+// WalkSets iterates over the plugin settings.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	pexps := make(eval.ExpressionSet, len(r.Plugins))
+	for i, p := range r.Plugins {
+		pexps[i] = p
+	}
+	walk(pexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/config/dsl"}
+}
+
+// Validate makes sure the configuration file can be read, that the
+// configuration of each registered plugin is valid and that the plugin meta
+// are set to "on" or "off".
+func (r *RootExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	validateMeta(verr)
+	if _, _, err := r.load(); err != nil {
+		verr.Add(r, err.Error())
+	} else {
+		plugins := make([]string, 0, len(registry))
+		for p := range registry {
+			plugins = append(plugins, p)
+		}
+		sort.Strings(plugins)
+		for _, p := range plugins {
+			if _, err := Get(p); err != nil {
+				verr.Add(r, err.Error())
+			}
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// load reads the configuration file. It returns the plugin sections indexed by
+// plugin name and the path to the file. A missing file is not an error unless
+// its path is set in the design or with the environment variable.
+func (r *RootExpr) load() (map[string]map[string]interface{}, string, error) {
+	path, explicit := r.File, true
+	if path == "" {
+		path = os.Getenv(EnvVar)
+	}
+	if path == "" {
+		path, explicit = FileName, false
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return nil, path, nil
+		}
+		return nil, path, fmt.Errorf("failed to read plugin configuration: %s", err)
+	}
+	var sections map[string]map[string]interface{}
+	if err := yaml.Unmarshal(b, &sections); err != nil {
+		return nil, path, fmt.Errorf("failed to parse plugin configuration %s: %s", path, err)
+	}
+	return sections, path, nil
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PluginExpr) EvalName() string {
+	return fmt.Sprintf("configuration of plugin %q", p.Name)
+}
+
+// Validate makes sure the plugin is registered.
+func (p *PluginExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if _, ok := registry[p.Name]; !ok {
+		verr.Add(p, "unknown plugin %q, make sure the plugin is imported", p.Name)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// decode sets the fields of the struct pointed to by v from the given
+// settings. It returns an error if the settings contain unknown keys.
+func decode(settings map[string]interface{}, v interface{}) error {
+	b, err := yaml.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return yaml.UnmarshalStrict(b, v)
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type exampleConfig struct {
+	Name  string   `yaml:"name"`
+	Count int      `yaml:"count"`
+	Tags  []string `yaml:"tags"`
+}
+
+func (c *exampleConfig) Validate() error {
+	if c.Count < 0 {
+		return errors.New("count must be positive")
+	}
+	return nil
+}
+
+func init() {
+	Register("example", func() interface{} {
+		return &exampleConfig{Name: "default", Tags: []string{"a"}}
+	})
+}
+
+func TestGet(t *testing.T) {
+	cases := []struct {
+		Name     string
+		File     string
+		Env      string
+		Settings map[string]interface{}
+		Expected *exampleConfig
+		Error    string
+	}{
+		{"defaults", "", "", nil, &exampleConfig{Name: "default", Tags: []string{"a"}}, ""},
+		{"file", "testdata/goa-plugins.yaml", "", nil, &exampleConfig{Name: "from-file", Count: 2, Tags: []string{"a"}}, ""},
+		{"env", "", "testdata/goa-plugins.yaml", nil, &exampleConfig{Name: "from-file", Count: 2, Tags: []string{"a"}}, ""},
+		{"design", "testdata/goa-plugins.yaml", "", map[string]interface{}{"count": 3, "tags": []string{"b", "c"}}, &exampleConfig{Name: "from-file", Count: 3, Tags: []string{"b", "c"}}, ""},
+		{"missing-file", "testdata/missing.yaml", "", nil, nil, "failed to read plugin configuration"},
+		{"unknown-key", "testdata/invalid.yaml", "", nil, nil, `invalid "example" section in testdata/invalid.yaml`},
+		{"invalid-setting", "", "", map[string]interface{}{"count": "many"}, nil, `invalid "example" settings in design`},
+		{"validation", "", "", map[string]interface{}{"count": -1}, nil, "count must be positive"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			defer func() { Root = &RootExpr{} }()
+			Root = &RootExpr{File: c.File}
+			if c.Settings != nil {
+				Root.Plugins = []*PluginExpr{{Name: "example", Settings: c.Settings}}
+			}
+			if c.Env != "" {
+				defer setenv(t, EnvVar, c.Env)()
+			}
+			got, err := Get("example")
+			if c.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), c.Error) {
+					t.Fatalf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.Expected) {
+				t.Errorf("got %+v, expected %+v", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestGetUnregistered(t *testing.T) {
+	if _, err := Get("unknown"); err == nil {
+		t.Error("expected an error for an unregistered plugin")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer func() { Root = &RootExpr{} }()
+	Root = &RootExpr{Plugins: []*PluginExpr{{Name: "example", Settings: map[string]interface{}{"count": -1}}}}
+	if err := Root.Validate(); err == nil || !strings.Contains(err.Error(), "count must be positive") {
+		t.Errorf("got error %v, expected validation error", err)
+	}
+	p := &PluginExpr{Name: "unknown"}
+	if err := p.Validate(); err == nil {
+		t.Error("expected an error for an unknown plugin")
+	}
+}
+
+// setenv sets the given environment variable and returns a function restoring
+// its previous value.
+func setenv(t *testing.T, key, value string) func() {
+	prev, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
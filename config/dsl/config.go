@@ -0,0 +1,106 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+)
+
+// Configure defines the settings of the plugins in the design. The settings
+// override the ones read from the goa-plugins.yaml configuration file.
+//
+// Configure must appear in an API expression.
+//
+// Configure takes a DSL function as argument which may use File and Plugin.
+//
+// Example:
+//
+//    import config "goa.design/plugins/v3/config/dsl"
+//
+//    var _ = API("calc", func() {
+//        config.Configure(func() {
+//            config.File("design/goa-plugins.yaml")
+//            config.Plugin("lint", func() {
+//                config.Set("warnings", []string{"type-descriptions"})
+//            })
+//        })
+//    })
+//
+func Configure(fn func()) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	eval.Execute(fn, config.Root)
+}
+
+// File sets the path to the configuration file, relative to the working
+// directory of the gen command. The default is the goa-plugins.yaml file of
+// the working directory or the file whose path is set in the
+// GOA_PLUGINS_CONFIG environment variable. The gen command fails if the file
+// set with File does not exist.
+//
+// File must appear in a Configure expression.
+//
+// Example:
+//
+//     Configure(func() {
+//         File("design/goa-plugins.yaml")
+//     })
+//
+func File(path string) {
+	switch r := eval.Current().(type) {
+	case *config.RootExpr:
+		r.File = path
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Plugin defines the settings of the given plugin.
+//
+// Plugin must appear in a Configure expression.
+//
+// Plugin takes the name of the plugin and a DSL function which uses Set to
+// define the settings.
+//
+// Example:
+//
+//     Configure(func() {
+//         Plugin("lint", func() {
+//             Set("verbs", []string{"compute"})
+//         })
+//     })
+//
+func Plugin(name string, fn func()) {
+	r, ok := eval.Current().(*config.RootExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	p := &config.PluginExpr{Name: name, Settings: make(map[string]interface{})}
+	if !eval.Execute(fn, p) {
+		return
+	}
+	r.Plugins = append(r.Plugins, p)
+}
+
+// Set sets the value of a plugin setting. The key is the name of the setting
+// as it appears in the configuration file.
+//
+// Set must appear in a Plugin expression.
+//
+// Example:
+//
+//     Plugin("lint", func() {
+//         Set("disabled", []string{"plural-resources"})
+//     })
+//
+func Set(key string, value interface{}) {
+	switch p := eval.Current().(type) {
+	case *config.PluginExpr:
+		p.Settings[key] = value
+	default:
+		eval.IncompatibleDSL()
+	}
+}
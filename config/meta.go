@@ -0,0 +1,140 @@
+package config
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+// MetaPrefix is the prefix of the meta keys turning plugins on or off, e.g.
+//
+//    Meta("plugin:cors", "off")
+//
+// The meta may be set on the API to change the default for all the services
+// and on the services, in which case it overrides the API setting.
+const MetaPrefix = "plugin:"
+
+const (
+	// On is the meta value enabling a plugin.
+	On = "on"
+	// Off is the meta value disabling a plugin.
+	Off = "off"
+)
+
+// Enabled returns true unless the given plugin is turned off for the service
+// with the given name. An empty service name refers to the API as a whole.
+// Plugins are enabled by default.
+func Enabled(plugin, service string) bool {
+	if expr.Root == nil || expr.Root.API == nil {
+		return true
+	}
+	key := MetaPrefix + plugin
+	if service != "" {
+		if svc := expr.Root.Service(service); svc != nil {
+			if v, ok := last(svc.Meta, key); ok {
+				return v != Off
+			}
+		}
+	}
+	if v, ok := last(expr.Root.API.Meta, key); ok {
+		return v != Off
+	}
+	return true
+}
+
+// EnabledFile returns true unless the given generated file belongs to a service
+// for which the plugin is turned off. The files that do not belong to a
+// service, e.g. the OpenAPI specification, follow the API setting.
+func EnabledFile(plugin string, f *codegen.File) bool {
+	return Enabled(plugin, FileService(f))
+}
+
+// EnabledOperation returns true unless the given OpenAPI operation belongs to a
+// service for which the plugin is turned off.
+func EnabledOperation(plugin string, op *openapi.Operation) bool {
+	return Enabled(plugin, strings.SplitN(op.OperationID, "#", 2)[0])
+}
+
+// EnabledAny returns true if the given plugin is enabled for the API or for at
+// least one of its services. The generators whose work may concern any
+// service test it before testing each service.
+func EnabledAny(plugin string) bool {
+	if Enabled(plugin, "") {
+		return true
+	}
+	for _, svc := range expr.Root.Services {
+		if Enabled(plugin, svc.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileService returns the name of the service the given generated file belongs
+// to, e.g. "calc" for gen/calc/service.go or gen/http/calc/server/server.go. It
+// returns an empty string if the file does not belong to a service.
+func FileService(f *codegen.File) string {
+	if expr.Root == nil {
+		return ""
+	}
+	var segs []string
+	for i, s := range strings.Split(filepath.ToSlash(f.Path), "/") {
+		if s == codegen.Gendir {
+			segs = strings.Split(filepath.ToSlash(f.Path), "/")[i+1:]
+			break
+		}
+	}
+	if len(segs) > 1 && (segs[0] == "http" || segs[0] == "grpc") {
+		segs = segs[1:]
+	}
+	if len(segs) < 2 {
+		return ""
+	}
+	for _, svc := range expr.Root.Services {
+		if codegen.SnakeCase(codegen.Goify(svc.Name, true)) == segs[0] {
+			return svc.Name
+		}
+	}
+	return ""
+}
+
+// last returns the last value of the given meta key.
+func last(meta expr.MetaExpr, key string) (string, bool) {
+	vals := meta[key]
+	if len(vals) == 0 {
+		return "", false
+	}
+	return vals[len(vals)-1], true
+}
+
+// validateMeta reports the plugin meta whose value is neither "on" nor "off".
+func validateMeta(verr *eval.ValidationErrors) {
+	if expr.Root == nil || expr.Root.API == nil {
+		return
+	}
+	check := func(e eval.Expression, meta expr.MetaExpr) {
+		keys := make([]string, 0, len(meta))
+		for k := range meta {
+			if strings.HasPrefix(k, MetaPrefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range meta[k] {
+				if v != On && v != Off {
+					verr.Add(e, "invalid value %q for meta %q, must be %q or %q", v, k, On, Off)
+				}
+			}
+		}
+	}
+	check(expr.Root.API, expr.Root.API.Meta)
+	for _, svc := range expr.Root.Services {
+		check(svc, svc.Meta)
+	}
+}
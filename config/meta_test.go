@@ -0,0 +1,90 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config/testdata"
+)
+
+func TestEnabled(t *testing.T) {
+	codegen.RunDSL(t, testdata.PluginMetaDSL)
+	cases := []struct {
+		Name     string
+		Plugin   string
+		Service  string
+		Expected bool
+	}{
+		{"default", "docs", "orders", true},
+		{"service-off", "mocks", "orders", false},
+		{"other-service", "mocks", "order_items", true},
+		{"api-off", "cors", "orders", false},
+		{"service-on", "cors", "order_items", true},
+		{"api", "cors", "", false},
+		{"unknown-service", "mocks", "unknown", true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if got := Enabled(c.Plugin, c.Service); got != c.Expected {
+				t.Errorf("got %v, expected %v", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestEnabledAny(t *testing.T) {
+	codegen.RunDSL(t, testdata.PluginMetaDSL)
+	cases := map[string]bool{
+		"docs": true,
+		"cors": true,
+		"lint": false,
+	}
+	for plugin, expected := range cases {
+		if got := EnabledAny(plugin); got != expected {
+			t.Errorf("%s: got %v, expected %v", plugin, got, expected)
+		}
+	}
+}
+
+func TestFileService(t *testing.T) {
+	codegen.RunDSL(t, testdata.PluginMetaDSL)
+	cases := map[string]string{
+		"gen/orders/service.go":                 "orders",
+		"gen/http/order_items/server/server.go": "order_items",
+		"gen/grpc/orders/client/client.go":      "orders",
+		"gen/http/openapi.json":                 "",
+		"gen/http/cli/test/cli.go":              "",
+		"cmd/orders/main.go":                    "",
+	}
+	for path, expected := range cases {
+		if got := FileService(&codegen.File{Path: path}); got != expected {
+			t.Errorf("%s: got %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestEnabledOperation(t *testing.T) {
+	codegen.RunDSL(t, testdata.PluginMetaDSL)
+	if EnabledOperation("mocks", &openapi.Operation{OperationID: "orders#list"}) {
+		t.Error("expected mocks to be disabled for orders#list")
+	}
+	if !EnabledOperation("mocks", &openapi.Operation{OperationID: "order_items#list"}) {
+		t.Error("expected mocks to be enabled for order_items#list")
+	}
+}
+
+func TestValidateMeta(t *testing.T) {
+	codegen.RunDSL(t, testdata.InvalidPluginMetaDSL)
+	verr := new(eval.ValidationErrors)
+	validateMeta(verr)
+	if len(verr.Errors) != 2 {
+		t.Fatalf("got %d errors, expected 2: %v", len(verr.Errors), verr)
+	}
+	if msg := verr.Error(); !strings.Contains(msg, `invalid value "disabled" for meta "plugin:cors"`) ||
+		!strings.Contains(msg, `invalid value "false" for meta "plugin:mocks"`) {
+		t.Errorf("got %s, expected invalid meta errors", msg)
+	}
+}
@@ -0,0 +1,38 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var PluginMetaDSL = func() {
+	API("test", func() {
+		Meta("plugin:cors", "off")
+		Meta("plugin:lint", "off")
+	})
+	Service("orders", func() {
+		Meta("plugin:mocks", "off")
+		Method("list", func() {
+			HTTP(func() {
+				GET("/orders")
+			})
+		})
+	})
+	Service("order_items", func() {
+		Meta("plugin:cors", "on")
+		Method("list", func() {
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
+
+var InvalidPluginMetaDSL = func() {
+	API("test", func() {
+		Meta("plugin:cors", "disabled")
+	})
+	Service("orders", func() {
+		Meta("plugin:mocks", "false")
+		Method("list", func() {})
+	})
+}
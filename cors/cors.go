@@ -1,10 +1,23 @@
 package cors
 
 import (
+	"net/http"
 	"regexp"
 	"strings"
 )
 
+// safelisted lists the CORS-safelisted response headers which the browsers
+// expose to the clients without Access-Control-Expose-Headers.
+var safelisted = map[string]bool{
+	"Cache-Control":    true,
+	"Content-Language": true,
+	"Content-Length":   true,
+	"Content-Type":     true,
+	"Expires":          true,
+	"Last-Modified":    true,
+	"Pragma":           true,
+}
+
 // MatchOrigin returns true if the given Origin header value matches the
 // origin specification.
 // Spec can be one of:
@@ -42,3 +55,42 @@ func MatchOrigin(origin, spec string) bool {
 func MatchOriginRegexp(origin string, spec *regexp.Regexp) bool {
 	return spec.Match([]byte(origin))
 }
+
+// AddVary adds the given header names to the Vary header of h unless they are
+// already listed. The names set by the handlers and middlewares are kept so
+// that the caches take all of them into account.
+func AddVary(h http.Header, names ...string) {
+	listed := make(map[string]bool)
+	for _, v := range h["Vary"] {
+		for _, n := range strings.Split(v, ",") {
+			listed[http.CanonicalHeaderKey(strings.TrimSpace(n))] = true
+		}
+	}
+	if listed["*"] {
+		return
+	}
+	for _, n := range names {
+		if k := http.CanonicalHeaderKey(n); !listed[k] {
+			listed[k] = true
+			h.Add("Vary", k)
+		}
+	}
+}
+
+// ExposeHeaders returns the value of the Access-Control-Expose-Headers header
+// exposing the given response headers to the clients, the empty string if
+// there is none. The duplicates and the CORS-safelisted response headers are
+// omitted.
+func ExposeHeaders(headers ...string) string {
+	var exposed []string
+	seen := make(map[string]bool)
+	for _, h := range headers {
+		k := http.CanonicalHeaderKey(h)
+		if seen[k] || safelisted[k] {
+			continue
+		}
+		seen[k] = true
+		exposed = append(exposed, k)
+	}
+	return strings.Join(exposed, ", ")
+}
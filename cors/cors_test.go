@@ -1,6 +1,8 @@
 package cors
 
 import (
+	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -46,3 +48,50 @@ func TestMatchOrigin(t *testing.T) {
 		})
 	}
 }
+
+func TestAddVary(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Vary     []string
+		Names    []string
+		Expected []string
+	}{
+		{"empty", nil, []string{"Origin"}, []string{"Origin"}},
+		{"existing", []string{"Accept-Encoding"}, []string{"Origin"}, []string{"Accept-Encoding", "Origin"}},
+		{"listed", []string{"accept-encoding, origin"}, []string{"Origin"}, []string{"accept-encoding, origin"}},
+		{"wildcard", []string{"*"}, []string{"Origin"}, []string{"*"}},
+		{"many", nil, []string{"Origin", "origin", "Access-Control-Request-Method"}, []string{"Origin", "Access-Control-Request-Method"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			h := http.Header{}
+			if c.Vary != nil {
+				h["Vary"] = c.Vary
+			}
+			AddVary(h, c.Names...)
+			if !reflect.DeepEqual(h["Vary"], c.Expected) {
+				t.Errorf("got Vary %q, expected %q", h["Vary"], c.Expected)
+			}
+		})
+	}
+}
+
+func TestExposeHeaders(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Headers  []string
+		Expected string
+	}{
+		{"none", nil, ""},
+		{"canonical", []string{"x-time", "Location"}, "X-Time, Location"},
+		{"duplicates", []string{"X-Time", "x-time"}, "X-Time"},
+		{"safelisted", []string{"Content-Type", "ETag", "cache-control"}, "Etag"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if got := ExposeHeaders(c.Headers...); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
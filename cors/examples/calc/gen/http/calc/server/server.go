@@ -149,11 +149,18 @@ func NewCORSHandler() http.Handler {
 }
 
 // handleCalcOrigin applies the CORS response headers corresponding to the
-// origin for the service calc.
-func handleCalcOrigin(h http.Handler) http.Handler {
+// origin for the service calc. exposed lists the response headers of the
+// endpoint exposed to the clients in addition to the headers exposed by the
+// policy.
+func handleCalcOrigin(h http.Handler, exposed ...string) http.Handler {
 	spec0 := regexp.MustCompile(".*localhost.*")
+	expose0 := cors.ExposeHeaders(append([]string{"X-Time", "X-Api-Version"}, exposed...)...)
+	expose1 := cors.ExposeHeaders(append([]string{"X-Time"}, exposed...)...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -162,27 +169,27 @@ func handleCalcOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOriginRegexp(origin, spec0) {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
-			w.Header().Set("Access-Control-Expose-Headers", "X-Time, X-Api-Version")
-			w.Header().Set("Access-Control-Max-Age", "100")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+				w.Header().Set("Access-Control-Max-Age", "100")
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
 		}
 		if cors.MatchOrigin(origin, "http://127.0.0.1") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
-			w.Header().Set("Access-Control-Expose-Headers", "X-Time")
-			w.Header().Set("Access-Control-Max-Age", "600")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
 				w.Header().Set("Access-Control-Allow-Headers", "X-Shared-Secret")
+				w.Header().Set("Access-Control-Max-Age", "600")
+			} else if expose1 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose1)
 			}
 			origHndlr(w, r)
 			return
@@ -1,6 +1,8 @@
 package cors
 
 import (
+	"fmt"
+	"net/http"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -8,8 +10,11 @@ import (
 	"goa.design/goa/v3/codegen"
 	"goa.design/goa/v3/codegen/service"
 	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
 	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
 	"goa.design/plugins/v3/cors/expr"
+	"goa.design/plugins/v3/registry"
 )
 
 // ServicesData holds the all the ServiceData indexed by service name.
@@ -33,14 +38,27 @@ type (
 
 // Register the plugin Generator functions.
 func init() {
-	codegen.RegisterPlugin("cors", "gen", nil, Generate)
-	codegen.RegisterPlugin("cors-example", "example", nil, TweakExample)
+	registry.Register(&registry.Plugin{
+		Name:     "cors",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+	registry.Register(&registry.Plugin{
+		Name:     "cors-example",
+		Cmd:      "example",
+		Generate: TweakExample,
+	})
 }
 
 // Generate produces server code that handle preflight requests and updates
-// the HTTP responses with the appropriate CORS headers.
+// the HTTP responses with the appropriate CORS headers. The responses vary by
+// origin and expose the response headers declared by the endpoints in addition
+// to the headers exposed by the policies.
 func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
 	for _, f := range files {
+		if !config.EnabledFile("cors", f) {
+			continue
+		}
 		serverCORS(f)
 	}
 	return files, nil
@@ -51,13 +69,28 @@ func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codeg
 // does not take a second argument but this plugin generates one that does. The
 // second argument is the actual HTTP server which is needed so it can be
 // configured with the CORS endpoint. So this method simply removes the special
-// case from the Goa template generating the example.
+// case from the Goa template generating the example for the services for which
+// the plugin is enabled.
 func TweakExample(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	var names []string
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if config.Enabled("cors", svc.Name) {
+					names = append(names, fmt.Sprintf("%q", svc.Name))
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		return files, nil
+	}
 	re := regexp.MustCompile("{{ if .Endpoints }}(.+){{ end }}")
+	repl := "{{ if or .Endpoints (eq .Service.Name " + strings.Join(names, " ") + ") }}$1{{ end }}"
 	for _, f := range files {
 		for _, t := range f.SectionTemplates {
 			if t.Name == "server-http-init" {
-				t.Source = re.ReplaceAllString(t.Source, "$1")
+				t.Source = re.ReplaceAllString(t.Source, repl)
 			}
 		}
 	}
@@ -148,13 +181,51 @@ func serverCORS(f *codegen.File) {
 			-1)
 	}
 	for _, s := range f.Section("server-handler") {
-		s.Source = strings.Replace(s.Source, "h.(http.HandlerFunc)", svcData.OriginHandler+"(h).(http.HandlerFunc)", -1)
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["corsExposed"] = responseHeaders
+		s.Source = strings.Replace(s.Source, "h.(http.HandlerFunc)",
+			svcData.OriginHandler+`(h{{ range corsExposed .ServiceName .Method.Name }}, {{ printf "%q" . }}{{ end }}).(http.HandlerFunc)`, -1)
 	}
 	for _, s := range f.Section("server-files") {
 		s.Source = strings.Replace(s.Source, "h.ServeHTTP", svcData.OriginHandler+"(h).ServeHTTP", -1)
 	}
 }
 
+// responseHeaders returns the names of the headers declared by the responses of
+// the HTTP endpoint of the given method which the browsers do not expose to
+// the clients by default.
+func responseHeaders(svc, method string) []string {
+	s := goaexpr.Root.API.HTTP.Service(svc)
+	if s == nil {
+		return nil
+	}
+	e := s.Endpoint(method)
+	if e == nil {
+		return nil
+	}
+	resps := e.Responses
+	for _, herr := range e.HTTPErrors {
+		resps = append(resps, herr.Response)
+	}
+	var headers []string
+	seen := make(map[string]bool)
+	for _, r := range resps {
+		if r == nil || r.Headers == nil || goaexpr.AsObject(r.Headers.Type) == nil {
+			continue
+		}
+		goaexpr.WalkMappedAttr(r.Headers, func(_, elem string, _ *goaexpr.AttributeExpr) error {
+			if k := http.CanonicalHeaderKey(elem); !seen[k] && !safelisted[k] {
+				seen[k] = true
+				headers = append(headers, k)
+			}
+			return nil
+		})
+	}
+	return headers
+}
+
 // Data: ServiceData
 var corsHandlerInitT = `{{ printf "%s creates a HTTP handler which returns a simple 200 response." .Endpoint.HandlerInit | comment }}
 func {{ .Endpoint.HandlerInit }}() http.Handler {
@@ -181,53 +252,59 @@ func {{ .Endpoint.MountHandler }}(mux goahttp.Muxer, h http.Handler) {
 `
 
 // Data: ServiceData
-var handleCORST = `{{ printf "%s applies the CORS response headers corresponding to the origin for the service %s." .OriginHandler .Name | comment }}
-func {{ .OriginHandler }}(h http.Handler) http.Handler {
+var handleCORST = `{{ printf "%s applies the CORS response headers corresponding to the origin for the service %s. exposed lists the response headers of the endpoint exposed to the clients in addition to the headers exposed by the policy." .OriginHandler .Name | comment }}
+func {{ .OriginHandler }}(h http.Handler, exposed ...string) http.Handler {
 {{- range $i, $policy := .Origins }}
 	{{- if $policy.Regexp }}
 	spec{{$i}} := regexp.MustCompile({{ printf "%q" $policy.Origin }})
 	{{- end }}
+	{{- if $policy.Exposed }}
+	expose{{$i}} := cors.ExposeHeaders(append([]string{ {{- range $j, $e := $policy.Exposed }}{{ if $j }}, {{ end }}{{ printf "%q" $e }}{{ end }} }, exposed...)...)
+	{{- else }}
+	expose{{$i}} := cors.ExposeHeaders(exposed...)
+	{{- end }}
 {{- end }}
 	origHndlr := h.(http.HandlerFunc)
-  return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    origin := r.Header.Get("Origin")
-    if origin == "" {
-      // Not a CORS request
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	{{- if .Origins }}
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
+	{{- end }}
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Not a CORS request
 			origHndlr(w, r)
 			return
-    }
+		}
 	{{- range $i, $policy := .Origins }}
 		{{- if $policy.Regexp }}
 		if cors.MatchOriginRegexp(origin, spec{{$i}}) {
 		{{- else }}
 		if cors.MatchOrigin(origin, {{ printf "%q" $policy.Origin }}) {
 		{{- end }}
-      w.Header().Set("Access-Control-Allow-Origin", origin)
-			{{- if not (eq $policy.Origin "*") }}
-			w.Header().Set("Vary", "Origin")
-			{{- end }}
-			{{- if $policy.Exposed }}
-			w.Header().Set("Access-Control-Expose-Headers", "{{ join $policy.Exposed ", " }}")
-			{{- end }}
-			{{- if gt $policy.MaxAge 0 }}
-			w.Header().Set("Access-Control-Max-Age", "{{ $policy.MaxAge }}")
-			{{- end }}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Credentials", "{{ $policy.Credentials }}")
-      if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
-        // We are handling a preflight request
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+				// We are handling a preflight request
 				{{- if $policy.Methods }}
 				w.Header().Set("Access-Control-Allow-Methods", "{{ join $policy.Methods ", " }}")
 				{{- end }}
 				{{- if $policy.Headers }}
 				w.Header().Set("Access-Control-Allow-Headers", "{{ join $policy.Headers ", " }}")
 				{{- end }}
+				{{- if gt $policy.MaxAge 0 }}
+				w.Header().Set("Access-Control-Max-Age", "{{ $policy.MaxAge }}")
+				{{- end }}
+			} else if expose{{$i}} != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose{{$i}})
 			}
 			origHndlr(w, r)
 			return
-    }
+		}
 	{{- end }}
 		origHndlr(w, r)
 		return
-  })
+	})
 }
 `
@@ -1,6 +1,7 @@
 package cors_test
 
 import (
+	"bytes"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -74,6 +75,28 @@ func NewCORSHandler() http.Handler {
 	}
 }
 
+func TestGenerateResponseHeaders(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.ResponseHeadersDSL)
+	fs := httpcodegen.ServerFiles("", expr.Root)
+	cors.Generate("", []eval.Root{expr.Root}, fs)
+	var handlers []*codegen.SectionTemplate
+	for _, f := range fs {
+		if filepath.Base(f.Path) == "server.go" {
+			handlers = f.Section("server-handler")
+		}
+	}
+	expected := []string{testdata.ResponseHeadersMethodMountCode, testdata.NoHeadersMethodMountCode}
+	if len(handlers) != len(expected) {
+		t.Fatalf("got %d server-handler sections, expected %d", len(handlers), len(expected))
+	}
+	for i, s := range handlers {
+		code := codegen.SectionCode(t, s)
+		if code != expected[i] {
+			t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, expected[i]))
+		}
+	}
+}
+
 func testCode(t *testing.T, file *codegen.File, section, expCode string) {
 	sections := file.Section(section)
 	if len(sections) < 1 {
@@ -84,3 +107,27 @@ func testCode(t *testing.T, file *codegen.File, section, expCode string) {
 		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, expCode))
 	}
 }
+
+func TestTweakExample(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.FileServersPluginMetaDSL)
+	fs := httpcodegen.ExampleServerFiles("", expr.Root)
+	if _, err := cors.TweakExample("", []eval.Root{expr.Root}, fs); err != nil {
+		t.Fatal(err)
+	}
+	var code string
+	for _, f := range fs {
+		for _, s := range f.Section("server-http-init") {
+			var buf bytes.Buffer
+			if err := s.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			code = buf.String()
+		}
+	}
+	if !strings.Contains(code, "assetssvr.Mount(mux, assetsServer)") {
+		t.Errorf("expected the server of the Assets service to be given to Mount, got:\n%s", code)
+	}
+	if !strings.Contains(code, "docssvr.Mount(mux)\n") {
+		t.Errorf("expected the Docs service to be mounted without its server, got:\n%s", code)
+	}
+}
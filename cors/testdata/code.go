@@ -1,10 +1,16 @@
 package testdata
 
 var SimpleOriginHandleCode = `// handleSimpleOriginOrigin applies the CORS response headers corresponding to
-// the origin for the service SimpleOrigin.
-func handleSimpleOriginOrigin(h http.Handler) http.Handler {
+// the origin for the service SimpleOrigin. exposed lists the response headers
+// of the endpoint exposed to the clients in addition to the headers exposed by
+// the policy.
+func handleSimpleOriginOrigin(h http.Handler, exposed ...string) http.Handler {
+	expose0 := cors.ExposeHeaders(exposed...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -13,10 +19,11 @@ func handleSimpleOriginOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOrigin(origin, "SimpleOrigin") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
@@ -28,11 +35,17 @@ func handleSimpleOriginOrigin(h http.Handler) http.Handler {
 `
 
 var RegexpOriginHandleCode = `// handleRegexpOriginOrigin applies the CORS response headers corresponding to
-// the origin for the service RegexpOrigin.
-func handleRegexpOriginOrigin(h http.Handler) http.Handler {
+// the origin for the service RegexpOrigin. exposed lists the response headers
+// of the endpoint exposed to the clients in addition to the headers exposed by
+// the policy.
+func handleRegexpOriginOrigin(h http.Handler, exposed ...string) http.Handler {
 	spec0 := regexp.MustCompile(".*RegexpOrigin.*")
+	expose0 := cors.ExposeHeaders(exposed...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -41,10 +54,11 @@ func handleRegexpOriginOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOriginRegexp(origin, spec0) {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
@@ -56,11 +70,18 @@ func handleRegexpOriginOrigin(h http.Handler) http.Handler {
 `
 
 var MultiOriginHandleCode = `// handleMultiOriginOrigin applies the CORS response headers corresponding to
-// the origin for the service MultiOrigin.
-func handleMultiOriginOrigin(h http.Handler) http.Handler {
+// the origin for the service MultiOrigin. exposed lists the response headers
+// of the endpoint exposed to the clients in addition to the headers exposed by
+// the policy.
+func handleMultiOriginOrigin(h http.Handler, exposed ...string) http.Handler {
 	spec0 := regexp.MustCompile(".*MultiOrigin2.*")
+	expose0 := cors.ExposeHeaders(append([]string{"X-Time", "X-Api-Version"}, exposed...)...)
+	expose1 := cors.ExposeHeaders(append([]string{"X-Time"}, exposed...)...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -69,27 +90,27 @@ func handleMultiOriginOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOriginRegexp(origin, spec0) {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
-			w.Header().Set("Access-Control-Expose-Headers", "X-Time, X-Api-Version")
-			w.Header().Set("Access-Control-Max-Age", "100")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+				w.Header().Set("Access-Control-Max-Age", "100")
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
 		}
 		if cors.MatchOrigin(origin, "MultiOrigin1") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
-			w.Header().Set("Access-Control-Expose-Headers", "X-Time")
-			w.Header().Set("Access-Control-Max-Age", "600")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
 				w.Header().Set("Access-Control-Allow-Headers", "X-Shared-Secret")
+				w.Header().Set("Access-Control-Max-Age", "600")
+			} else if expose1 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose1)
 			}
 			origHndlr(w, r)
 			return
@@ -101,10 +122,16 @@ func handleMultiOriginOrigin(h http.Handler) http.Handler {
 `
 
 var OriginFileServerHandleCode = `// handleOriginFileServerOrigin applies the CORS response headers corresponding
-// to the origin for the service OriginFileServer.
-func handleOriginFileServerOrigin(h http.Handler) http.Handler {
+// to the origin for the service OriginFileServer. exposed lists the response
+// headers of the endpoint exposed to the clients in addition to the headers
+// exposed by the policy.
+func handleOriginFileServerOrigin(h http.Handler, exposed ...string) http.Handler {
+	expose0 := cors.ExposeHeaders(exposed...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -113,10 +140,11 @@ func handleOriginFileServerOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOrigin(origin, "OriginFileServer") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
@@ -128,10 +156,16 @@ func handleOriginFileServerOrigin(h http.Handler) http.Handler {
 `
 
 var OriginMultiEndpointHandleCode = `// handleOriginMultiEndpointOrigin applies the CORS response headers
-// corresponding to the origin for the service OriginMultiEndpoint.
-func handleOriginMultiEndpointOrigin(h http.Handler) http.Handler {
+// corresponding to the origin for the service OriginMultiEndpoint. exposed
+// lists the response headers of the endpoint exposed to the clients in
+// addition to the headers exposed by the policy.
+func handleOriginMultiEndpointOrigin(h http.Handler, exposed ...string) http.Handler {
+	expose0 := cors.ExposeHeaders(exposed...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -140,10 +174,11 @@ func handleOriginMultiEndpointOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOrigin(origin, "OriginMultiEndpoint") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
@@ -155,10 +190,16 @@ func handleOriginMultiEndpointOrigin(h http.Handler) http.Handler {
 `
 
 var MultiServiceSameOriginFirstServiceHandleCode = `// handleFirstServiceOrigin applies the CORS response headers corresponding to
-// the origin for the service FirstService.
-func handleFirstServiceOrigin(h http.Handler) http.Handler {
+// the origin for the service FirstService. exposed lists the response headers
+// of the endpoint exposed to the clients in addition to the headers exposed by
+// the policy.
+func handleFirstServiceOrigin(h http.Handler, exposed ...string) http.Handler {
+	expose0 := cors.ExposeHeaders(exposed...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -167,10 +208,11 @@ func handleFirstServiceOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOrigin(origin, "SimpleOrigin") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
@@ -181,10 +223,16 @@ func handleFirstServiceOrigin(h http.Handler) http.Handler {
 }
 `
 var MultiServiceSameOriginSecondServiceHandleCode = `// handleSecondServiceOrigin applies the CORS response headers corresponding to
-// the origin for the service SecondService.
-func handleSecondServiceOrigin(h http.Handler) http.Handler {
+// the origin for the service SecondService. exposed lists the response headers
+// of the endpoint exposed to the clients in addition to the headers exposed by
+// the policy.
+func handleSecondServiceOrigin(h http.Handler, exposed ...string) http.Handler {
+	expose0 := cors.ExposeHeaders(exposed...)
 	origHndlr := h.(http.HandlerFunc)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response depends on the origin even if the request is not a CORS
+		// request so that the caches do not reuse it for another origin.
+		cors.AddVary(w.Header(), "Origin")
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			// Not a CORS request
@@ -193,10 +241,11 @@ func handleSecondServiceOrigin(h http.Handler) http.Handler {
 		}
 		if cors.MatchOrigin(origin, "SimpleOrigin") {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "false")
-			if acrm := r.Header.Get("Access-Control-Request-Method"); acrm != "" {
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				// We are handling a preflight request
+			} else if expose0 != "" {
+				w.Header().Set("Access-Control-Expose-Headers", expose0)
 			}
 			origHndlr(w, r)
 			return
@@ -442,3 +491,29 @@ func New(
 	}
 }
 `
+
+var ResponseHeadersMethodMountCode = `// MountResponseHeadersMethodHandler configures the mux to serve the
+// "ResponseHeaders" service "ResponseHeadersMethod" endpoint.
+func MountResponseHeadersMethodHandler(mux goahttp.Muxer, h http.Handler) {
+	f, ok := handleResponseHeadersOrigin(h, "Location", "X-Api-Version", "Retry-After").(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("POST", "/", f)
+}
+`
+
+var NoHeadersMethodMountCode = `// MountNoHeadersMethodHandler configures the mux to serve the
+// "ResponseHeaders" service "NoHeadersMethod" endpoint.
+func MountNoHeadersMethodHandler(mux goahttp.Muxer, h http.Handler) {
+	f, ok := handleResponseHeadersOrigin(h).(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/", f)
+}
+`
@@ -56,6 +56,20 @@ var OriginFileServerDSL = func() {
 	})
 }
 
+var FileServersPluginMetaDSL = func() {
+	API("FileServers", func() {
+		Meta("plugin:cors", "off")
+	})
+	Service("Assets", func() {
+		Meta("plugin:cors", "on")
+		cors.Origin("Assets")
+		Files("/assets.json", "./assets.json")
+	})
+	Service("Docs", func() {
+		Files("/docs.json", "./docs.json")
+	})
+}
+
 var OriginMultiEndpointDSL = func() {
 	Service("OriginMultiEndpoint", func() {
 		cors.Origin("OriginMultiEndpoint")
@@ -95,3 +109,37 @@ var MultiServiceSameOriginDSL = func() {
 		})
 	})
 }
+
+var ResponseHeadersDSL = func() {
+	Service("ResponseHeaders", func() {
+		cors.Origin("ResponseHeaders", func() {
+			cors.Expose("X-Time")
+		})
+		Method("ResponseHeadersMethod", func() {
+			Result(func() {
+				Attribute("id", String)
+				Attribute("version", String)
+				Attribute("type", String)
+			})
+			Error("not_found", func() {
+				Attribute("retry", Int)
+			})
+			HTTP(func() {
+				POST("/")
+				Response(StatusCreated, func() {
+					Header("id:Location")
+					Header("version:x-api-version")
+					Header("type:Content-Type")
+				})
+				Response("not_found", StatusNotFound, func() {
+					Header("retry:Retry-After")
+				})
+			})
+		})
+		Method("NoHeadersMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
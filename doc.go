@@ -56,6 +56,20 @@ specifies the "goa" command that triggers the call to the plugin functions. The
 second argument is the Prepare function if any, nil otherwise. The last argument
 is the code generator function if any, nil otherwise.
 
+The plugins of this repository register with the "registry" package instead
+which makes it possible to declare the plugins that must run before or after
+the plugin as well as the plugins it requires, for example:
+
+	// Register the plugin.
+	func init() {
+		registry.Register(&registry.Plugin{
+			Name:     "docsite",
+			Cmd:      "gen",
+			Generate: Generate,
+			After:    []string{"encoding"},
+		})
+	}
+
 Extending The DSL
 
 A plugin may introduce new DSL "keywords" (typically Go package functions). The
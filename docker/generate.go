@@ -0,0 +1,230 @@
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+// goVersion is the version of the Go image used to build the servers.
+const goVersion = "1.12"
+
+// defaultPorts lists the default ports of the server URI schemes used by the
+// example servers.
+var defaultPorts = map[string]string{"http": "80", "https": "443", "grpc": "8080", "grpcs": "8443"}
+
+type (
+	// serverData contains the data necessary to render the Dockerfile and
+	// compose service of a server.
+	serverData struct {
+		// Name is the compose service name.
+		Name string
+		// Dir is the directory of the example server main package.
+		Dir string
+		// GoVersion is the version of the Go build image.
+		GoVersion string
+		// Ports lists the ports exposed by the server.
+		Ports []*portData
+		// Env lists the environment variables holding the security
+		// secrets.
+		Env []string
+	}
+
+	// portData describes the port of a server transport.
+	portData struct {
+		// Transport is "http" or "grpc".
+		Transport string
+		// Number is the port number.
+		Number string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "docker",
+		Cmd:      "example",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces a multi-stage Dockerfile for each example server and the
+// docker-compose.yaml file which runs them.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("docker", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			var svrs []*serverData
+			for _, s := range r.API.Servers {
+				data := &serverData{
+					Name:      codegen.KebabCase(s.Name),
+					Dir:       codegen.SnakeCase(codegen.Goify(s.Name, true)),
+					GoVersion: goVersion,
+					Ports:     ports(s),
+					Env:       secrets(r, s),
+				}
+				svrs = append(svrs, data)
+				files = append(files, dockerfile(data))
+			}
+			if len(svrs) > 0 {
+				files = append(files, composeFile(svrs))
+			}
+		}
+	}
+	return files, nil
+}
+
+// dockerfile returns the Dockerfile which builds and runs the server.
+func dockerfile(data *serverData) *codegen.File {
+	return &codegen.File{
+		Path:      filepath.Join("cmd", data.Dir, "Dockerfile"),
+		SkipExist: true,
+		SectionTemplates: []*codegen.SectionTemplate{
+			{Name: "docker-dockerfile", Source: dockerfileT, Data: data},
+		},
+	}
+}
+
+// composeFile returns the docker-compose.yaml file which runs the servers.
+func composeFile(svrs []*serverData) *codegen.File {
+	return &codegen.File{
+		Path:      "docker-compose.yaml",
+		SkipExist: true,
+		SectionTemplates: []*codegen.SectionTemplate{
+			{Name: "docker-compose", Source: composeT, Data: svrs},
+		},
+	}
+}
+
+// ports returns the ports of the transports of the server default host, that
+// is the host used by the example server when no host is given on the command
+// line. The port of a transport is the port of its first URI. The host
+// variables are replaced with their default values.
+func ports(s *expr.ServerExpr) []*portData {
+	if len(s.Hosts) == 0 {
+		return nil
+	}
+	h := s.Hosts[0]
+	var ps []*portData
+	for _, uri := range h.URIs {
+		u := string(uri)
+		for _, v := range *expr.AsObject(h.Attribute().Type) {
+			if v.Attribute.DefaultValue != nil {
+				u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+			}
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			continue
+		}
+		transport := "http"
+		if strings.HasPrefix(parsed.Scheme, "grpc") {
+			transport = "grpc"
+		}
+		var found bool
+		for _, existing := range ps {
+			if existing.Transport == transport {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		p := parsed.Port()
+		if p == "" {
+			p = defaultPorts[parsed.Scheme]
+		}
+		ps = append(ps, &portData{Transport: transport, Number: p})
+	}
+	return ps
+}
+
+// secrets returns the names of the environment variables holding the secrets
+// of the security schemes used by the methods of the server services.
+func secrets(r *expr.RootExpr, svr *expr.ServerExpr) []string {
+	var schemes []*expr.SchemeExpr
+	seen := make(map[string]struct{})
+	for _, name := range svr.Services {
+		svc := r.Service(name)
+		if svc == nil {
+			continue
+		}
+		for _, m := range svc.Methods {
+			for _, req := range m.Requirements {
+				for _, s := range req.Schemes {
+					if _, ok := seen[s.SchemeName]; !ok {
+						seen[s.SchemeName] = struct{}{}
+						schemes = append(schemes, s)
+					}
+				}
+			}
+		}
+	}
+	var env []string
+	for _, s := range schemes {
+		prefix := strings.ToUpper(codegen.SnakeCase(codegen.Goify(s.SchemeName, false)))
+		switch s.Kind {
+		case expr.BasicAuthKind:
+			env = append(env, prefix+"_USERNAME", prefix+"_PASSWORD")
+		case expr.APIKeyKind:
+			env = append(env, prefix+"_KEY")
+		case expr.JWTKind:
+			env = append(env, prefix+"_SECRET")
+		case expr.OAuth2Kind:
+			env = append(env, prefix+"_CLIENT_ID", prefix+"_CLIENT_SECRET")
+		}
+	}
+	return env
+}
+
+// input: serverData
+const dockerfileT = `# Build stage
+FROM golang:{{ .GoVersion }} AS build
+WORKDIR /src
+COPY go.mod go.sum ./
+RUN go mod download
+COPY . .
+RUN CGO_ENABLED=0 go build -o /bin/{{ .Name }} ./cmd/{{ .Dir }}
+
+# Runtime stage
+FROM gcr.io/distroless/static
+COPY --from=build /bin/{{ .Name }} /{{ .Name }}
+{{- if .Ports }}
+EXPOSE{{ range .Ports }} {{ .Number }}{{ end }}
+{{- end }}
+ENTRYPOINT ["/{{ .Name }}", "-domain", "0.0.0.0"{{ range .Ports }}, "-{{ .Transport }}-port", "{{ .Number }}"{{ end }}]
+`
+
+// input: []*serverData
+const composeT = `version: "3.7"
+services:
+{{- range . }}
+  {{ .Name }}:
+    build:
+      context: .
+      dockerfile: cmd/{{ .Dir }}/Dockerfile
+	{{- if .Ports }}
+    ports:
+		{{- range .Ports }}
+      - "{{ .Number }}:{{ .Number }}"
+		{{- end }}
+	{{- end }}
+	{{- if .Env }}
+    environment:
+		{{- range .Env }}
+      {{ . }}: ${{ printf "{%s}" . }}
+		{{- end }}
+	{{- end }}
+{{- end }}
+`
@@ -0,0 +1,46 @@
+package docker_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/docker"
+	"goa.design/plugins/v3/docker/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name  string
+		DSL   func()
+		Files []string
+	}{
+		{"single-server", testdata.SingleServerDSL, []string{"cmd/calc/Dockerfile", "docker-compose.yaml"}},
+		{"secrets", testdata.SecretsDSL, []string{"cmd/calc/Dockerfile", "cmd/admin/Dockerfile", "docker-compose.yaml"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			root := codegen.RunDSL(t, c.DSL)
+			fs, err := docker.Generate("", []eval.Root{root}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != len(c.Files) {
+				t.Fatalf("got %d files, expected %d", len(fs), len(c.Files))
+			}
+			for i, f := range fs {
+				if f.Path != c.Files[i] {
+					t.Errorf("got file %q, expected %q", f.Path, c.Files[i])
+				}
+				var buf bytes.Buffer
+				if err := f.SectionTemplates[0].Write(&buf); err != nil {
+					t.Fatal(err)
+				}
+				plugintest.Golden(t, c.Name+"-"+strings.Replace(f.Path, "/", "-", -1), buf.String())
+			}
+		})
+	}
+}
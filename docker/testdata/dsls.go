@@ -0,0 +1,74 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var SingleServerDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			Host("local", func() {
+				URI("http://localhost:8000")
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			HTTP(func() {
+				GET("/add")
+			})
+		})
+	})
+}
+
+var SecretsDSL = func() {
+	var JWTAuth = JWTSecurity("jwt")
+	var APIKeyAuth = APIKeySecurity("partner")
+	var BasicAuth = BasicAuthSecurity("admin")
+	API("calc", func() {
+		Server("calc", func() {
+			Services("calc")
+			Host("development", func() {
+				URI("http://localhost:8000")
+				URI("grpc://localhost:{port}")
+				Variable("port", String, func() {
+					Default("8080")
+				})
+			})
+			Host("production", func() {
+				URI("https://calc.example.com")
+			})
+		})
+		Server("admin", func() {
+			Services("admin")
+			Host("development", func() {
+				URI("http://localhost:8088")
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Security(JWTAuth, APIKeyAuth)
+			Payload(func() {
+				Token("token", String)
+				APIKey("partner", "key", String)
+			})
+			HTTP(func() {
+				GET("/add")
+			})
+			GRPC(func() {})
+		})
+	})
+	Service("admin", func() {
+		Method("reset", func() {
+			Security(BasicAuth)
+			Payload(func() {
+				Username("user", String)
+				Password("pass", String)
+			})
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+	})
+}
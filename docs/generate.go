@@ -1,29 +1,35 @@
 package docs
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
-	"text/template"
 
 	"goa.design/goa/v3/codegen"
 	"goa.design/goa/v3/codegen/service"
 	"goa.design/goa/v3/eval"
 	"goa.design/goa/v3/expr"
 	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
 )
 
 // init registers the plugin generator function.
 func init() {
-	codegen.RegisterPlugin("docs", "gen", nil, Generate)
+	registry.Register(&registry.Plugin{
+		Name:     "docs",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
 }
 
-// Generate produces the documentation JSON file.
+// Generate produces the documentation JSON file and the Markdown reference of
+// each service.
 func Generate(_ string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
 	for _, root := range roots {
 		if r, ok := root.(*expr.RootExpr); ok {
 			files = append(files, docsFile(r))
+			files = append(files, markdownFiles(r)...)
 		}
 	}
 	return files, nil
@@ -35,26 +41,10 @@ func docsFile(r *expr.RootExpr) *codegen.File {
 		Services:    servicesDocs(r),
 		Definitions: openapi.Definitions,
 	}
-	jsonPath := filepath.Join(codegen.Gendir, "docs.json")
-	if _, err := os.Stat(jsonPath); !os.IsNotExist(err) {
-		// goa does not delete files in the top-level gen folder.
-		// https://github.com/goadesign/goa/pull/2194
-		// The plugin must delete docs.json so that the generator does not append
-		// to the existing docs.json.
-		if err := os.Remove(jsonPath); err != nil {
-			panic(err)
-		}
-	}
-	jsonSection := &codegen.SectionTemplate{
-		Name:    "docs",
-		FuncMap: template.FuncMap{"toJSON": toJSON},
-		Source:  "{{ toJSON .}}",
-		Data:    docs,
-	}
-	return &codegen.File{
-		Path:             jsonPath,
-		SectionTemplates: []*codegen.SectionTemplate{jsonSection},
-	}
+	// The file finalizer truncates docs.json so that the content does not get
+	// appended to the file generated previously: goa does not delete the
+	// files in the top-level gen folder.
+	return stream.File(filepath.Join(codegen.Gendir, "docs.json"), "docs", docs, stream.JSON)
 }
 
 func apiDocs(api *expr.APIExpr) *apiData {
@@ -91,6 +81,9 @@ func apiDocs(api *expr.APIExpr) *apiData {
 func servicesDocs(r *expr.RootExpr) map[string]*serviceData {
 	svcs := make(map[string]*serviceData, len(r.Services))
 	for _, svc := range r.Services {
+		if !config.Enabled("docs", svc.Name) {
+			continue
+		}
 		n := svc.Name
 		svcs[n] = &serviceData{
 			Name:        n,
@@ -231,11 +224,3 @@ func generateScheme(sch *service.SchemeData) *schemeData {
 	}
 	return s
 }
-
-func toJSON(d interface{}) string {
-	b, err := json.Marshal(d)
-	if err != nil {
-		panic("openapi: " + err.Error()) // bug
-	}
-	return string(b)
-}
@@ -2,20 +2,16 @@ package docs_test
 
 import (
 	"bytes"
-	"flag"
 	"fmt"
-	"io/ioutil"
-	"path/filepath"
 	"testing"
 
 	"goa.design/goa/v3/codegen"
 	"goa.design/goa/v3/eval"
 	"goa.design/plugins/v3/docs"
 	"goa.design/plugins/v3/docs/testdata"
+	"goa.design/plugins/v3/plugintest"
 )
 
-var update = flag.Bool("update", false, "update golden files")
-
 func TestDocs(t *testing.T) {
 	cases := []struct {
 		Name string
@@ -45,19 +41,28 @@ func TestDocs(t *testing.T) {
 			if len(fs[0].SectionTemplates) == 0 {
 				t.Fatalf("got 0 sections, expected 1")
 			}
-			var buf bytes.Buffer
-			if err := fs[0].SectionTemplates[0].Write(&buf); err != nil {
-				t.Fatal(err)
-			}
-			golden := filepath.Join("testdata", fmt.Sprintf("%s.json", c.Name))
-			if *update {
-				ioutil.WriteFile(golden, buf.Bytes(), 0644)
-			}
-			expected, _ := ioutil.ReadFile(golden)
-			if buf.String() != string(expected) {
-				t.Errorf("invalid content for %s: got\n%s\ngot vs. expected:\n%s",
-					fs[0].Path, buf.String(), codegen.Diff(t, buf.String(), string(expected)))
-			}
+			plugintest.Golden(t, fmt.Sprintf("%s.json", c.Name), plugintest.Render(t, fs[0]))
 		})
 	}
 }
+
+func TestMarkdown(t *testing.T) {
+	root := codegen.RunDSL(t, testdata.CatalogReference)
+	fs, err := docs.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	if fs[1].Path != "gen/docs/catalog.md" {
+		t.Errorf("got path %q, expected %q", fs[1].Path, "gen/docs/catalog.md")
+	}
+	var buf bytes.Buffer
+	for _, s := range fs[1].SectionTemplates {
+		if err := s.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plugintest.Golden(t, "catalog-reference.md", buf.String())
+}
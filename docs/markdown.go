@@ -0,0 +1,507 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+)
+
+type (
+	// markdownService is the data used to render the Markdown reference of
+	// a service.
+	markdownService struct {
+		Name         string
+		Description  string
+		Requirements []*markdownRequirement
+		Methods      []*markdownMethod
+		Types        []*markdownType
+	}
+
+	// markdownMethod describes a method, its transports and its types.
+	markdownMethod struct {
+		Name         string
+		Anchor       string
+		Description  string
+		HTTPRoutes   []string
+		GRPCName     string
+		Requirements []*markdownRequirement
+		Params       []*markdownField
+		Payload      *markdownPayload
+		Result       *markdownPayload
+		Responses    []*markdownResponse
+		Errors       []*markdownError
+	}
+
+	// markdownPayload describes a method payload or result.
+	markdownPayload struct {
+		Type       string
+		Streaming  bool
+		Attributes []*markdownField
+		Example    string
+	}
+
+	// markdownField describes an attribute or a HTTP parameter.
+	markdownField struct {
+		Name        string
+		In          string
+		Type        string
+		Required    bool
+		Description string
+	}
+
+	// markdownResponse describes a HTTP response.
+	markdownResponse struct {
+		StatusCode  int
+		Description string
+	}
+
+	// markdownError describes a method error.
+	markdownError struct {
+		Name        string
+		Description string
+		Type        string
+		StatusCode  int
+	}
+
+	// markdownRequirement describes a security requirement.
+	markdownRequirement struct {
+		Schemes []string
+		Scopes  []string
+	}
+
+	// markdownType describes a user type referenced by the methods.
+	markdownType struct {
+		Name        string
+		Anchor      string
+		Description string
+		Type        string
+		Attributes  []*markdownField
+	}
+
+	// markdownBuilder builds the Markdown reference data of a service. It
+	// keeps track of the user types referenced by the methods and of the
+	// heading anchors.
+	markdownBuilder struct {
+		root    *expr.RootExpr
+		types   map[string]*markdownType
+		order   []string
+		anchors map[string]int
+	}
+)
+
+// markdownFiles returns the Markdown reference files of the services, one per
+// service.
+func markdownFiles(r *expr.RootExpr) []*codegen.File {
+	var fs []*codegen.File
+	for _, svc := range r.Services {
+		if !config.Enabled("docs", svc.Name) {
+			continue
+		}
+		fs = append(fs, &codegen.File{
+			Path: filepath.Join(codegen.Gendir, "docs", codegen.SnakeCase(svc.Name)+".md"),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:    "docs-markdown",
+				FuncMap: template.FuncMap{"cell": markdownCell, "join": strings.Join},
+				Source:  markdownT,
+				Data:    markdownServiceDocs(r, svc),
+			}},
+		})
+	}
+	return fs
+}
+
+// markdownServiceDocs returns the Markdown reference data of the given
+// service.
+func markdownServiceDocs(r *expr.RootExpr, svc *expr.ServiceExpr) *markdownService {
+	reqs := svc.Requirements
+	if len(reqs) == 0 {
+		reqs = r.API.Requirements
+	}
+	data := &markdownService{
+		Name:         svc.Name,
+		Description:  svc.Description,
+		Requirements: markdownRequirements(reqs),
+	}
+	b := &markdownBuilder{
+		root:    r,
+		types:   make(map[string]*markdownType),
+		anchors: make(map[string]int),
+	}
+	methodAnchors := b.headings(data, svc)
+	for _, m := range svc.Methods {
+		data.Methods = append(data.Methods, b.method(svc, m, methodAnchors[m.Name]))
+	}
+	for _, name := range b.order {
+		data.Types = append(data.Types, b.types[name])
+	}
+	return data
+}
+
+// headings computes the anchors of the headings that precede the type
+// headings in the reference of the given service so that the anchors of the
+// types may be computed as they are referenced. It returns the anchors of the
+// method headings indexed by method name.
+func (b *markdownBuilder) headings(data *markdownService, svc *expr.ServiceExpr) map[string]string {
+	b.anchor(svc.Name + " Service")
+	if len(data.Requirements) > 0 {
+		b.anchor("Security")
+	}
+	b.anchor("Methods")
+	anchors := make(map[string]string, len(svc.Methods))
+	for _, m := range svc.Methods {
+		anchors[m.Name] = b.anchor(m.Name)
+		he := b.httpEndpoint(svc, m)
+		if len(m.Requirements) > 0 {
+			b.anchor("Security")
+		}
+		if he != nil && hasHTTPParams(he) {
+			b.anchor("HTTP Parameters")
+		}
+		if m.Payload != nil && m.Payload.Type != expr.Empty {
+			b.anchor("Payload")
+		}
+		if m.Result != nil && m.Result.Type != expr.Empty || he != nil && len(he.Responses) > 0 {
+			b.anchor("Result")
+		}
+		if len(m.Errors) > 0 {
+			b.anchor("Errors")
+		}
+	}
+	b.anchor("Types")
+	return anchors
+}
+
+// method returns the Markdown reference data of the given method.
+func (b *markdownBuilder) method(svc *expr.ServiceExpr, m *expr.MethodExpr, anchor string) *markdownMethod {
+	md := &markdownMethod{
+		Name:         m.Name,
+		Anchor:       anchor,
+		Description:  m.Description,
+		Requirements: markdownRequirements(m.Requirements),
+		Payload:      b.payload(m.Payload, m.IsPayloadStreaming()),
+		Result:       b.payload(m.Result, m.Stream == expr.ServerStreamKind || m.Stream == expr.BidirectionalStreamKind),
+	}
+	he := b.httpEndpoint(svc, m)
+	if he != nil {
+		for _, r := range he.Routes {
+			for _, p := range r.FullPaths() {
+				md.HTTPRoutes = append(md.HTTPRoutes, r.Method+" "+p)
+			}
+		}
+		md.Params = append(md.Params, b.params(he.PathParams(), "path")...)
+		md.Params = append(md.Params, b.params(he.QueryParams(), "query")...)
+		md.Params = append(md.Params, b.params(he.Headers, "header")...)
+		md.Params = append(md.Params, b.body(he, m.Payload)...)
+		for _, resp := range he.Responses {
+			md.Responses = append(md.Responses, &markdownResponse{resp.StatusCode, resp.Description})
+		}
+	}
+	if b.root.API.GRPC != nil {
+		if gs := b.root.API.GRPC.Service(svc.Name); gs != nil && gs.Endpoint(m.Name) != nil {
+			name := codegen.Goify(svc.Name, true)
+			pkg := codegen.SnakeCase(codegen.Goify(codegen.SnakeCase(name), false))
+			md.GRPCName = fmt.Sprintf("/%s.%s/%s", pkg, name, codegen.Goify(m.Name, true))
+		}
+	}
+	for _, er := range m.Errors {
+		e := &markdownError{
+			Name:        er.Name,
+			Description: er.Description,
+			Type:        b.typeName(er.AttributeExpr),
+		}
+		if he != nil {
+			for _, herr := range he.HTTPErrors {
+				if herr.Name == er.Name {
+					e.StatusCode = herr.Response.StatusCode
+				}
+			}
+		}
+		md.Errors = append(md.Errors, e)
+	}
+	return md
+}
+
+// httpEndpoint returns the HTTP endpoint of the given method, nil if the
+// method has no HTTP transport.
+func (b *markdownBuilder) httpEndpoint(svc *expr.ServiceExpr, m *expr.MethodExpr) *expr.HTTPEndpointExpr {
+	if b.root.API.HTTP == nil {
+		return nil
+	}
+	hs := b.root.API.HTTP.Service(svc.Name)
+	if hs == nil {
+		return nil
+	}
+	return hs.Endpoint(m.Name)
+}
+
+// body returns the Markdown reference data of the HTTP request body of the
+// given endpoint. The body fields use the types of the corresponding payload
+// attributes rather than the HTTP body types generated by goa.
+func (b *markdownBuilder) body(he *expr.HTTPEndpointExpr, payload *expr.AttributeExpr) []*markdownField {
+	if he.Body == nil || he.Body.Type == expr.Empty {
+		return nil
+	}
+	if origin, ok := he.Body.Meta["origin:attribute"]; ok && len(origin) > 0 {
+		if att := payload.Find(origin[0]); att != nil {
+			return []*markdownField{{
+				Name:        origin[0],
+				In:          "body",
+				Type:        b.typeName(att),
+				Required:    payload.IsRequired(origin[0]),
+				Description: att.Description,
+			}}
+		}
+	}
+	obj := expr.AsObject(he.Body.Type)
+	if obj == nil || !expr.IsObject(payload.Type) {
+		return []*markdownField{{Name: "body", In: "body", Type: b.typeName(payload), Required: true}}
+	}
+	fields := make([]*markdownField, 0, len(*obj))
+	for _, nat := range *obj {
+		att := payload.Find(nat.Name)
+		if att == nil {
+			att = nat.Attribute
+		}
+		fields = append(fields, &markdownField{
+			Name:        nat.Name,
+			In:          "body",
+			Type:        b.typeName(att),
+			Required:    payload.IsRequired(nat.Name),
+			Description: att.Description,
+		})
+	}
+	return fields
+}
+
+// payload returns the Markdown reference data of the given payload or result
+// attribute, nil if the type is empty.
+func (b *markdownBuilder) payload(att *expr.AttributeExpr, streaming bool) *markdownPayload {
+	if att == nil || att.Type == expr.Empty {
+		return nil
+	}
+	p := &markdownPayload{
+		Type:      b.typeName(att),
+		Streaming: streaming,
+		Example:   exampleJSON(att.Example(b.root.API.Random())),
+	}
+	if _, ok := att.Type.(*expr.Object); ok {
+		p.Attributes = b.attributes(att)
+	}
+	return p
+}
+
+// params returns the Markdown reference data of the HTTP parameters mapped
+// by ma.
+func (b *markdownBuilder) params(ma *expr.MappedAttributeExpr, in string) []*markdownField {
+	if ma == nil {
+		return nil
+	}
+	obj := expr.AsObject(ma.Type)
+	if obj == nil {
+		return nil
+	}
+	fields := make([]*markdownField, len(*obj))
+	for i, nat := range *obj {
+		fields[i] = &markdownField{
+			Name:        ma.ElemName(nat.Name),
+			In:          in,
+			Type:        b.typeName(nat.Attribute),
+			Required:    ma.IsRequired(nat.Name),
+			Description: nat.Attribute.Description,
+		}
+	}
+	return fields
+}
+
+// attributes returns the Markdown reference data of the attributes of the
+// given object attribute.
+func (b *markdownBuilder) attributes(att *expr.AttributeExpr) []*markdownField {
+	obj := expr.AsObject(att.Type)
+	if obj == nil {
+		return nil
+	}
+	fields := make([]*markdownField, len(*obj))
+	for i, nat := range *obj {
+		fields[i] = &markdownField{
+			Name:        nat.Name,
+			Type:        b.typeName(nat.Attribute),
+			Required:    att.IsRequired(nat.Name),
+			Description: nat.Attribute.Description,
+		}
+	}
+	return fields
+}
+
+// typeName returns the Markdown representation of the type of att. User
+// types link to their description which is added to the types of the
+// reference the first time they are referenced.
+func (b *markdownBuilder) typeName(att *expr.AttributeExpr) string {
+	switch t := att.Type.(type) {
+	case expr.UserType:
+		if t == expr.Empty {
+			return "empty"
+		}
+		name := t.Name()
+		mt, ok := b.types[name]
+		if !ok {
+			mt = &markdownType{
+				Name:        name,
+				Anchor:      b.anchor(name),
+				Description: t.Attribute().Description,
+			}
+			b.types[name] = mt
+			b.order = append(b.order, name)
+			mt.Type = b.typeName(t.Attribute())
+			if expr.AsObject(t) != nil {
+				mt.Attributes = b.attributes(t.Attribute())
+			}
+		}
+		return fmt.Sprintf("[%s](#%s)", name, mt.Anchor)
+	case *expr.Array:
+		return "array of " + b.typeName(t.ElemType)
+	case *expr.Map:
+		return fmt.Sprintf("map of %s to %s", b.typeName(t.KeyType), b.typeName(t.ElemType))
+	case *expr.Object:
+		for _, nat := range *t {
+			b.typeName(nat.Attribute)
+		}
+		return "object"
+	}
+	return att.Type.Name()
+}
+
+// anchor returns the anchor of the heading with the given text, suffixed like
+// GitHub does when an earlier heading uses the same anchor.
+func (b *markdownBuilder) anchor(heading string) string {
+	a := headingAnchor(heading)
+	n := b.anchors[a]
+	b.anchors[a] = n + 1
+	if n > 0 {
+		return fmt.Sprintf("%s-%d", a, n)
+	}
+	return a
+}
+
+// hasHTTPParams returns true if the given endpoint defines path or query
+// parameters, headers or a body.
+func hasHTTPParams(he *expr.HTTPEndpointExpr) bool {
+	for _, ma := range []*expr.MappedAttributeExpr{he.Params, he.Headers} {
+		if ma == nil {
+			continue
+		}
+		if obj := expr.AsObject(ma.Type); obj != nil && len(*obj) > 0 {
+			return true
+		}
+	}
+	return he.Body != nil && he.Body.Type != expr.Empty
+}
+
+// markdownRequirements returns the Markdown reference data of the given
+// security requirements.
+func markdownRequirements(reqs []*expr.SecurityExpr) []*markdownRequirement {
+	res := make([]*markdownRequirement, len(reqs))
+	for i, req := range reqs {
+		r := &markdownRequirement{Scopes: req.Scopes}
+		for _, s := range req.Schemes {
+			desc := fmt.Sprintf("`%s` (%s", s.SchemeName, s.Type())
+			if (s.Kind == expr.APIKeyKind || s.Kind == expr.JWTKind) && s.Name != "" {
+				desc += fmt.Sprintf(" in %s `%s`", s.In, s.Name)
+			}
+			r.Schemes = append(r.Schemes, desc+")")
+		}
+		res[i] = r
+	}
+	return res
+}
+
+// headingAnchor returns the anchor GitHub generates for a heading with the
+// given text.
+func headingAnchor(heading string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '-'
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, heading)
+}
+
+// markdownCell escapes the characters of s that would break a Markdown table
+// cell.
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// exampleJSON returns the indented JSON representation of the given example,
+// the empty string if it cannot be represented with JSON.
+func exampleJSON(v interface{}) string {
+	b, err := json.MarshalIndent(jsonCompatible(v), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// jsonCompatible converts the maps with non string keys contained in v to maps
+// with string keys so that v may be marshaled with JSON.
+func jsonCompatible(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			m[fmt.Sprintf("%v", k)] = jsonCompatible(e)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			m[k] = jsonCompatible(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(actual))
+		for i, e := range actual {
+			s[i] = jsonCompatible(e)
+		}
+		return s
+	}
+	return v
+}
+
+// input: markdownService
+const markdownT = "# {{ .Name }} Service\n" +
+	"{{ if .Description }}\n{{ .Description }}\n{{ end }}" +
+	"{{ if .Requirements }}\n## Security\n\n{{ template \"requirements\" .Requirements }}{{ end }}" +
+	"{{ if .Methods }}\n## Methods\n\n" +
+	"{{ range .Methods }}* [{{ .Name }}](#{{ .Anchor }})\n{{ end }}" +
+	"{{ range .Methods }}\n### {{ .Name }}\n" +
+	"{{ if .Description }}\n{{ .Description }}\n{{ end }}" +
+	"{{ if or .HTTPRoutes .GRPCName }}\n{{ range .HTTPRoutes }}* HTTP: `{{ . }}`\n{{ end }}{{ if .GRPCName }}* gRPC: `{{ .GRPCName }}`\n{{ end }}{{ end }}" +
+	"{{ if .Requirements }}\n#### Security\n\n{{ template \"requirements\" .Requirements }}{{ end }}" +
+	"{{ if .Params }}\n#### HTTP Parameters\n\n| Name | In | Type | Required | Description |\n| --- | --- | --- | --- | --- |\n" +
+	"{{ range .Params }}| `{{ .Name }}` | {{ .In }} | {{ .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Description }} |\n{{ end }}{{ end }}" +
+	"{{ with .Payload }}\n#### Payload\n\n{{ template \"payload\" . }}{{ end }}" +
+	"{{ if or .Result .Responses }}\n#### Result\n\n{{ with .Result }}{{ template \"payload\" . }}{{ end }}" +
+	"{{ if .Responses }}{{ if .Result }}\n{{ end }}| HTTP Status | Description |\n| --- | --- |\n{{ range .Responses }}| {{ .StatusCode }} | {{ cell .Description }} |\n{{ end }}{{ end }}{{ end }}" +
+	"{{ if .Errors }}\n#### Errors\n\n| Name | Type | HTTP Status | Description |\n| --- | --- | --- | --- |\n" +
+	"{{ range .Errors }}| `{{ .Name }}` | {{ .Type }} | {{ if .StatusCode }}{{ .StatusCode }}{{ end }} | {{ cell .Description }} |\n{{ end }}{{ end }}" +
+	"{{ end }}{{ end }}" +
+	"{{ if .Types }}\n## Types\n" +
+	"{{ range .Types }}\n### {{ .Name }}\n{{ if .Description }}\n{{ .Description }}\n{{ end }}" +
+	"{{ if .Attributes }}\n{{ template \"attributes\" .Attributes }}{{ else }}\nType: {{ .Type }}\n{{ end }}{{ end }}{{ end }}" +
+	"{{ define \"requirements\" }}{{ range . }}* {{ join .Schemes \" and \" }}{{ if .Scopes }} with scopes {{ range $i, $s := .Scopes }}{{ if $i }}, {{ end }}`{{ $s }}`{{ end }}{{ end }}\n{{ end }}{{ end }}" +
+	"{{ define \"attributes\" }}| Name | Type | Required | Description |\n| --- | --- | --- | --- |\n" +
+	"{{ range . }}| `{{ .Name }}` | {{ .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Description }} |\n{{ end }}{{ end }}" +
+	"{{ define \"payload\" }}Type: {{ .Type }}{{ if .Streaming }} (streamed){{ end }}\n" +
+	"{{ if .Attributes }}\n{{ template \"attributes\" .Attributes }}{{ end }}" +
+	"{{ if .Example }}\nExample:\n\n```json\n{{ .Example }}\n```\n{{ end }}{{ end }}"
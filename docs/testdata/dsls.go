@@ -150,3 +150,88 @@ var NoPayloadUserReturn = func() {
 		})
 	})
 }
+
+var CatalogReference = func() {
+	var JWTAuth = JWTSecurity("jwt", func() {
+		Scope("api:read", "Read access")
+		Scope("api:write", "Write access")
+	})
+	var Item = Type("Item", func() {
+		Description("Item is a catalog item.")
+		Attribute("id", String, "Item identifier", func() {
+			Example("42")
+		})
+		Attribute("name", String, "Item name | label", func() {
+			Example("pen")
+		})
+		Attribute("tags", ArrayOf(String), "Item tags", func() {
+			Example([]string{"office"})
+		})
+		Attribute("owner", "User")
+		Required("id", "name")
+	})
+	Type("User", func() {
+		Attribute("email", String, "User email", func() {
+			Example("ann@example.com")
+		})
+		Required("email")
+	})
+	API("Test API", func() {})
+	Service("Catalog", func() {
+		Description("Catalog manages the items.")
+		Security(JWTAuth, func() {
+			Scope("api:read")
+		})
+		Error("unauthorized", String, "Invalid token")
+		HTTP(func() {
+			Path("/catalog")
+			Response("unauthorized", StatusUnauthorized)
+		})
+		Method("Show", func() {
+			Description("Show returns the item with the given ID.")
+			Payload(func() {
+				Token("token", String)
+				Attribute("id", String, "Item identifier", func() {
+					Example("42")
+				})
+				Attribute("view", String, "View to render", func() {
+					Example("full")
+				})
+				Required("id")
+			})
+			Result(Item)
+			Error("not_found", func() {
+				Description("Item not found")
+			})
+			HTTP(func() {
+				GET("/items/{id}")
+				Param("view")
+				Response(StatusOK)
+				Response("not_found", StatusNotFound)
+			})
+			GRPC(func() {
+				Response(CodeOK)
+				Response("not_found", CodeNotFound)
+				Response("unauthorized", CodeUnauthenticated)
+			})
+		})
+		Method("Create", func() {
+			Description("Create adds an item to the catalog.")
+			Security(JWTAuth, func() {
+				Scope("api:write")
+			})
+			Payload(func() {
+				Token("token", String)
+				Attribute("item", Item)
+				Required("item")
+			})
+			HTTP(func() {
+				POST("/items")
+				Body("item")
+				Response(StatusCreated, func() {
+					Description("The item was created.")
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,238 @@
+package docsite
+
+// siteCSS is the style sheet of the site.
+const siteCSS = `body {
+  display: flex;
+  margin: 0;
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif;
+  color: #24292e;
+}
+
+nav {
+  position: sticky;
+  top: 0;
+  flex: 0 0 18rem;
+  height: 100vh;
+  overflow-y: auto;
+  padding: 1rem;
+  box-sizing: border-box;
+  background: #f6f8fa;
+  border-right: 1px solid #e1e4e8;
+  font-size: 0.9rem;
+}
+
+nav ul {
+  list-style: none;
+  padding-left: 0.75rem;
+}
+
+nav li {
+  margin: 0.25rem 0;
+}
+
+nav .home {
+  font-weight: bold;
+  font-size: 1.1rem;
+}
+
+main {
+  flex: 1;
+  max-width: 60rem;
+  padding: 1rem 2rem;
+}
+
+a {
+  color: #0366d6;
+  text-decoration: none;
+}
+
+table {
+  border-collapse: collapse;
+  margin: 0.5rem 0;
+}
+
+th, td {
+  border: 1px solid #e1e4e8;
+  padding: 0.3rem 0.6rem;
+  text-align: left;
+  vertical-align: top;
+}
+
+pre {
+  background: #f6f8fa;
+  padding: 0.75rem;
+  overflow-x: auto;
+}
+
+section {
+  border-bottom: 1px solid #e1e4e8;
+  padding-bottom: 1rem;
+}
+
+.method {
+  display: inline-block;
+  min-width: 3.5rem;
+  font-size: 0.75rem;
+  font-weight: bold;
+  text-transform: uppercase;
+}
+
+.method.get { color: #22863a; }
+.method.post { color: #005cc5; }
+.method.put, .method.patch { color: #b08800; }
+.method.delete { color: #cb2431; }
+
+.description {
+  white-space: pre-line;
+}
+
+.version, .media-types {
+  color: #586069;
+}
+
+form.try label {
+  display: block;
+  margin: 0.5rem 0 0.2rem;
+  font-size: 0.85rem;
+}
+
+form.try input, form.try textarea {
+  width: 100%;
+  box-sizing: border-box;
+  font-family: monospace;
+}
+
+form.try button {
+  margin-top: 0.5rem;
+}
+`
+
+// siteJS builds the try it out forms of the operations from the OpenAPI
+// specification loaded by spec.js and sends the requests.
+const siteJS = `(function () {
+  "use strict";
+
+  var spec = window.SPEC || { paths: {}, definitions: {} };
+
+  function baseURL() {
+    var scheme = (spec.schemes && spec.schemes[0]) || location.protocol.replace(":", "");
+    return scheme + "://" + (spec.host || location.host) + (spec.basePath || "");
+  }
+
+  function resolve(schema) {
+    while (schema && schema.$ref) {
+      schema = spec.definitions[schema.$ref.replace("#/definitions/", "")];
+    }
+    return schema;
+  }
+
+  function example(schema) {
+    schema = resolve(schema);
+    if (!schema) {
+      return undefined;
+    }
+    if (schema.example !== undefined) {
+      return schema.example;
+    }
+    if (schema.type === "array") {
+      var item = example(schema.items);
+      return item === undefined ? [] : [item];
+    }
+    if (schema.type === "object") {
+      var obj = {};
+      Object.keys(schema.properties || {}).forEach(function (name) {
+        obj[name] = example(schema.properties[name]);
+      });
+      return obj;
+    }
+    return undefined;
+  }
+
+  function field(form, label, name, value, multiline) {
+    var l = document.createElement("label");
+    l.textContent = label;
+    var input = document.createElement(multiline ? "textarea" : "input");
+    input.name = name;
+    if (multiline) {
+      input.rows = 8;
+    }
+    if (value !== undefined) {
+      input.value = value;
+    }
+    form.appendChild(l);
+    form.appendChild(input);
+  }
+
+  function build(form) {
+    var path = spec.paths[form.dataset.path];
+    var op = path && path[form.dataset.method];
+    if (!op) {
+      return;
+    }
+    field(form, "Base URL", "base", baseURL());
+    (op.parameters || []).forEach(function (p) {
+      var label = p.name + " (" + p.in + (p.required ? ", required" : "") + ")";
+      if (p.in === "body") {
+        var ex = example(p.schema);
+        field(form, label, "body:" + p.name, ex === undefined ? "" : JSON.stringify(ex, null, 2), true);
+      } else {
+        field(form, label, p.in + ":" + p.name, p.default);
+      }
+    });
+    var button = document.createElement("button");
+    button.type = "submit";
+    button.textContent = "Send";
+    form.appendChild(button);
+    var output = document.createElement("pre");
+    form.appendChild(output);
+    form.addEventListener("submit", function (e) {
+      e.preventDefault();
+      send(form, op, output);
+    });
+  }
+
+  function send(form, op, output) {
+    var path = form.dataset.path, query = [], headers = {}, body;
+    (op.parameters || []).forEach(function (p) {
+      var value = form.elements[p.in + ":" + p.name].value;
+      if (value === "") {
+        return;
+      }
+      switch (p.in) {
+      case "path":
+        path = path.replace("{" + p.name + "}", encodeURIComponent(value));
+        break;
+      case "query":
+        var values = p.type === "array" ? value.split(",") : [value];
+        values.forEach(function (v) {
+          query.push(encodeURIComponent(p.name) + "=" + encodeURIComponent(v.trim()));
+        });
+        break;
+      case "header":
+        headers[p.name] = value;
+        break;
+      case "body":
+        body = value;
+        headers["Content-Type"] = (op.consumes || spec.consumes || ["application/json"])[0];
+        break;
+      }
+    });
+    var url = form.elements.base.value.replace(/\/$/, "") + path;
+    if (query.length > 0) {
+      url += "?" + query.join("&");
+    }
+    output.textContent = "Sending...";
+    fetch(url, { method: form.dataset.method.toUpperCase(), headers: headers, body: body })
+      .then(function (resp) {
+        return resp.text().then(function (text) {
+          output.textContent = resp.status + " " + resp.statusText + "\n\n" + text;
+        });
+      })
+      .catch(function (err) {
+        output.textContent = String(err);
+      });
+  }
+
+  Array.prototype.forEach.call(document.querySelectorAll("form.try"), build);
+})();
+`
@@ -0,0 +1,602 @@
+package docsite
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// siteData contains the data shared by the pages of the site.
+	siteData struct {
+		// Title is the title of the API.
+		Title string
+		// Description is the description of the API.
+		Description string
+		// Version is the version of the API.
+		Version string
+		// Tags lists the tags grouping the operations, one page is
+		// generated per tag.
+		Tags []*tagData
+		// Schemas lists the schemas of the spec definitions.
+		Schemas []*schemaData
+	}
+
+	// pageData is the data used to render a page of the site.
+	pageData struct {
+		// Site is the site data.
+		Site *siteData
+		// Title is the title of the page.
+		Title string
+		// Tag is the tag listed by the page, nil for the index and schemas
+		// pages.
+		Tag *tagData
+	}
+
+	// tagData describes a tag and the operations it groups.
+	tagData struct {
+		// Name is the name of the tag, the name of the service unless
+		// the design defines custom tags.
+		Name string
+		// Description is the description of the tag.
+		Description string
+		// Page is the name of the page file.
+		Page string
+		// Operations lists the operations tagged with the tag.
+		Operations []*operationData
+	}
+
+	// operationData describes an operation.
+	operationData struct {
+		// Anchor is the HTML anchor of the operation.
+		Anchor string
+		// Method is the HTTP method in lower case as used in the spec.
+		Method string
+		// Path is the operation path as used in the spec.
+		Path string
+		// Summary is the operation summary.
+		Summary string
+		// Description is the operation description.
+		Description string
+		// Parameters lists the non body parameters.
+		Parameters []*parameterData
+		// Body is the HTML rendering of the request body schema if any.
+		Body string
+		// Consumes lists the media types of the request body.
+		Consumes []string
+		// Responses lists the responses sorted by status code.
+		Responses []*responseData
+		// Security lists the security requirements.
+		Security []string
+	}
+
+	// parameterData describes a non body parameter.
+	parameterData struct {
+		Name        string
+		In          string
+		Type        string
+		Required    bool
+		Description string
+	}
+
+	// responseData describes a response.
+	responseData struct {
+		Status      string
+		Description string
+		Schema      string
+		Headers     []string
+	}
+
+	// schemaData describes a spec definition.
+	schemaData struct {
+		Name        string
+		Anchor      string
+		Description string
+		Schema      string
+		Example     string
+	}
+)
+
+// methods lists the HTTP methods in the order the operations of a path are
+// listed.
+var methods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "docsite",
+		Cmd:      "gen",
+		Generate: Generate,
+		// The site is built from the OpenAPI specification, run once
+		// the plugins documenting their features in it have run.
+		After: []string{
+			"async",
+			"bodylimit",
+			"bulk",
+			"bulkhead",
+			"cachecontrol",
+			"cbor",
+			"encoding",
+			"errorcatalog",
+			"etag",
+			"export",
+			"feature",
+			"healthcheck",
+			"jsonapi",
+			"msgpack",
+			"pagination",
+			"protobuf",
+			"provisioning",
+			"requestid",
+			"timeout",
+			"validation",
+			"webhooks",
+			"xml",
+		},
+	})
+}
+
+// Generate produces the static HTML documentation site from the OpenAPI
+// specification. The site is generated in the gen/docs directory.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("docsite", "") {
+		return files, nil
+	}
+	for _, f := range files {
+		for _, s := range f.Section("openapi") {
+			if spec, ok := s.Data.(*openapi.V2); ok {
+				return append(files, siteFiles(spec)...), nil
+			}
+		}
+	}
+	return files, nil
+}
+
+// siteFiles returns the files of the site documenting the given spec.
+func siteFiles(spec *openapi.V2) []*codegen.File {
+	site := siteDocs(spec)
+	fs := []*codegen.File{
+		pageFile("index.html", &pageData{Site: site, Title: site.Title}),
+		pageFile("schemas.html", &pageData{Site: site, Title: "Schemas"}),
+	}
+	for _, t := range site.Tags {
+		fs = append(fs, pageFile(t.Page, &pageData{Site: site, Title: t.Name, Tag: t}))
+	}
+	fs = append(fs,
+		assetFile("site.css", siteCSS),
+		assetFile("site.js", siteJS),
+		&codegen.File{
+			Path: filepath.Join(codegen.Gendir, "docs", "spec.js"),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:    "docsite-spec",
+				FuncMap: template.FuncMap{"toJSON": toJSON},
+				Source:  "window.SPEC = {{ toJSON . }};\n",
+				Data:    spec,
+			}},
+		},
+	)
+	return fs
+}
+
+// pageFile returns the file rendering the given page.
+func pageFile(name string, data *pageData) *codegen.File {
+	var source string
+	switch {
+	case data.Tag != nil:
+		source = tagT
+	case name == "schemas.html":
+		source = schemasT
+	default:
+		source = indexT
+	}
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "docs", name),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:    "docsite-page",
+			FuncMap: template.FuncMap{"join": strings.Join},
+			Source:  layoutStartT + source + layoutEndT,
+			Data:    data,
+		}},
+	}
+}
+
+// assetFile returns the file containing the given static asset.
+func assetFile(name, content string) *codegen.File {
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "docs", name),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:   "docsite-asset",
+			Source: "{{ . }}",
+			Data:   content,
+		}},
+	}
+}
+
+// siteDocs returns the site data documenting the given spec.
+func siteDocs(spec *openapi.V2) *siteData {
+	site := &siteData{Title: "API"}
+	if spec.Info != nil {
+		if spec.Info.Title != "" {
+			site.Title = spec.Info.Title
+		}
+		site.Description = spec.Info.Description
+		site.Version = spec.Info.Version
+	}
+	tags := make(map[string]*tagData)
+	pages := map[string]bool{"index.html": true, "schemas.html": true}
+	tag := func(name string) *tagData {
+		if t, ok := tags[name]; ok {
+			return t
+		}
+		page := anchor(name) + ".html"
+		for i := 2; pages[page]; i++ {
+			page = fmt.Sprintf("%s-%d.html", anchor(name), i)
+		}
+		pages[page] = true
+		t := &tagData{Name: name, Page: page}
+		tags[name] = t
+		site.Tags = append(site.Tags, t)
+		return t
+	}
+	for _, t := range spec.Tags {
+		tag(t.Name).Description = t.Description
+	}
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		path, ok := spec.Paths[p].(*openapi.Path)
+		if !ok {
+			continue
+		}
+		ops := map[string]*openapi.Operation{
+			"get": path.Get, "post": path.Post, "put": path.Put, "patch": path.Patch,
+			"delete": path.Delete, "head": path.Head, "options": path.Options,
+		}
+		for _, m := range methods {
+			op := ops[m]
+			if op == nil {
+				continue
+			}
+			od := operationDocs(spec, m, p, op)
+			names := op.Tags
+			if len(names) == 0 {
+				names = []string{"default"}
+			}
+			for _, n := range names {
+				t := tag(n)
+				t.Operations = append(t.Operations, od)
+			}
+		}
+	}
+	names := make([]string, 0, len(spec.Definitions))
+	for n := range spec.Definitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		def := spec.Definitions[n]
+		site.Schemas = append(site.Schemas, &schemaData{
+			Name:        n,
+			Anchor:      anchor(n),
+			Description: def.Description,
+			Schema:      schemaHTML(def),
+			Example:     exampleJSON(def.Example),
+		})
+	}
+	return site
+}
+
+// operationDocs returns the data describing the given operation.
+func operationDocs(spec *openapi.V2, method, path string, op *openapi.Operation) *operationData {
+	id := op.OperationID
+	if id == "" {
+		id = method + " " + path
+	}
+	od := &operationData{
+		Anchor:      anchor(id),
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+	}
+	for _, p := range op.Parameters {
+		if p.In == "body" {
+			od.Body = schemaHTML(p.Schema)
+			od.Consumes = op.Consumes
+			if len(od.Consumes) == 0 {
+				od.Consumes = spec.Consumes
+			}
+			continue
+		}
+		od.Parameters = append(od.Parameters, &parameterData{
+			Name:        p.Name,
+			In:          p.In,
+			Type:        itemsType(p.Type, p.Format, p.Items),
+			Required:    p.Required,
+			Description: p.Description,
+		})
+	}
+	codes := make([]string, 0, len(op.Responses))
+	for c := range op.Responses {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		resp := op.Responses[c]
+		rd := &responseData{
+			Status:      c,
+			Description: resp.Description,
+			Schema:      schemaHTML(resp.Schema),
+		}
+		for h := range resp.Headers {
+			rd.Headers = append(rd.Headers, h)
+		}
+		sort.Strings(rd.Headers)
+		od.Responses = append(od.Responses, rd)
+	}
+	for _, req := range op.Security {
+		names := make([]string, 0, len(req))
+		for n, scopes := range req {
+			if len(scopes) > 0 {
+				n += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		od.Security = append(od.Security, strings.Join(names, " and "))
+	}
+	return od
+}
+
+// schemaHTML returns the HTML rendering of the given schema: its type
+// followed by the table of its properties if it is an inline object.
+func schemaHTML(s *openapi.Schema) string {
+	if s == nil {
+		return ""
+	}
+	res := schemaType(s)
+	if s.Ref == "" && len(s.Properties) > 0 {
+		res += propertiesHTML(s)
+	}
+	return res
+}
+
+// schemaType returns the HTML rendering of the type of the given schema.
+// References link to the definitions of the schemas page.
+func schemaType(s *openapi.Schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		return fmt.Sprintf(`<a href="schemas.html#%s">%s</a>`, anchor(name), html.EscapeString(name))
+	}
+	if len(s.AnyOf) > 0 {
+		types := make([]string, len(s.AnyOf))
+		for i, o := range s.AnyOf {
+			types[i] = schemaType(o)
+		}
+		return "any of " + strings.Join(types, ", ")
+	}
+	switch s.Type {
+	case openapi.Array:
+		return "array of " + schemaType(s.Items)
+	case openapi.Object:
+		if len(s.Properties) == 0 && s.AdditionalProperties {
+			return "map"
+		}
+		return "object"
+	case "":
+		return "any"
+	}
+	if s.Format != "" {
+		return html.EscapeString(fmt.Sprintf("%s (%s)", s.Type, s.Format))
+	}
+	return html.EscapeString(string(s.Type))
+}
+
+// propertiesHTML returns the HTML table listing the properties of the given
+// object schema.
+func propertiesHTML(s *openapi.Schema) string {
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	required := make(map[string]bool, len(s.Required))
+	for _, n := range s.Required {
+		required[n] = true
+	}
+	var b strings.Builder
+	b.WriteString("\n<table class=\"properties\">\n<tr><th>Name</th><th>Type</th><th>Required</th><th>Description</th></tr>\n")
+	for _, n := range names {
+		p := s.Properties[n]
+		req := "no"
+		if required[n] {
+			req = "yes"
+		}
+		fmt.Fprintf(&b, "<tr><td><code>%s</code></td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(n), schemaHTML(p), req, html.EscapeString(p.Description))
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
+
+// itemsType returns the HTML rendering of the type of a non body parameter.
+func itemsType(typ, format string, items *openapi.Items) string {
+	if typ == "array" && items != nil {
+		return "array of " + itemsType(items.Type, items.Format, items.Items)
+	}
+	if format != "" {
+		return html.EscapeString(fmt.Sprintf("%s (%s)", typ, format))
+	}
+	return html.EscapeString(typ)
+}
+
+// anchor returns the HTML anchor or file name derived from the given name.
+func anchor(name string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, name), "-")
+}
+
+// exampleJSON returns the indented JSON representation of the given example,
+// the empty string if there is none.
+func exampleJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// toJSON returns the JSON representation of the given spec.
+func toJSON(spec *openapi.V2) string {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		panic("docsite: " + err.Error()) // bug
+	}
+	return string(b)
+}
+
+// input: pageData
+const layoutStartT = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ if ne .Title .Site.Title }}{{ html .Title }} - {{ end }}{{ html .Site.Title }}</title>
+<link rel="stylesheet" href="site.css">
+</head>
+<body>
+<nav>
+<a class="home" href="index.html">{{ html .Site.Title }}</a>{{ if .Site.Version }} <span class="version">{{ html .Site.Version }}</span>{{ end }}
+<ul>
+{{- range $tag := .Site.Tags }}
+<li><a href="{{ $tag.Page }}">{{ html $tag.Name }}</a>
+<ul>
+{{- range $tag.Operations }}
+<li><a href="{{ $tag.Page }}#{{ .Anchor }}"><span class="method {{ .Method }}">{{ .Method }}</span> {{ html .Path }}</a></li>
+{{- end }}
+</ul>
+</li>
+{{- end }}
+<li><a href="schemas.html">Schemas</a></li>
+</ul>
+</nav>
+<main>
+`
+
+// input: pageData
+const layoutEndT = `</main>
+<script src="spec.js"></script>
+<script src="site.js"></script>
+</body>
+</html>
+`
+
+// input: pageData
+const indexT = `<h1>{{ html .Site.Title }}</h1>
+{{- if .Site.Description }}
+<p>{{ html .Site.Description }}</p>
+{{- end }}
+<h2>Services</h2>
+<dl>
+{{- range .Site.Tags }}
+<dt><a href="{{ .Page }}">{{ html .Name }}</a></dt>
+{{- if .Description }}
+<dd>{{ html .Description }}</dd>
+{{- end }}
+{{- end }}
+</dl>
+`
+
+// input: pageData
+const tagT = `<h1>{{ html .Tag.Name }}</h1>
+{{- if .Tag.Description }}
+<p>{{ html .Tag.Description }}</p>
+{{- end }}
+{{- range .Tag.Operations }}
+<section class="operation" id="{{ .Anchor }}">
+<h2><span class="method {{ .Method }}">{{ .Method }}</span> <code>{{ html .Path }}</code></h2>
+{{- if .Summary }}
+<p class="summary">{{ html .Summary }}</p>
+{{- end }}
+{{- if and .Description (ne .Description .Summary) }}
+<p class="description">{{ html .Description }}</p>
+{{- end }}
+{{- if .Security }}
+<h3>Security</h3>
+<ul>
+{{- range .Security }}
+<li>{{ html . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if .Parameters }}
+<h3>Parameters</h3>
+<table>
+<tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{- range .Parameters }}
+<tr><td><code>{{ html .Name }}</code></td><td>{{ .In }}</td><td>{{ .Type }}</td><td>{{ if .Required }}yes{{ else }}no{{ end }}</td><td>{{ html .Description }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Body }}
+<h3>Request Body</h3>
+{{- if .Consumes }}
+<p class="media-types">{{ html (join .Consumes ", ") }}</p>
+{{- end }}
+<div class="schema">{{ .Body }}</div>
+{{- end }}
+<h3>Responses</h3>
+<table>
+<tr><th>Status</th><th>Description</th><th>Schema</th></tr>
+{{- range .Responses }}
+<tr><td>{{ .Status }}</td><td>{{ html .Description }}{{ if .Headers }}<br>Headers: {{ html (join .Headers ", ") }}{{ end }}</td><td>{{ .Schema }}</td></tr>
+{{- end }}
+</table>
+<details class="try-it">
+<summary>Try it out</summary>
+<form class="try" data-method="{{ .Method }}" data-path="{{ html .Path }}"></form>
+</details>
+</section>
+{{- end }}
+`
+
+// input: pageData
+const schemasT = `<h1>Schemas</h1>
+{{- range .Site.Schemas }}
+<section class="schema" id="{{ .Anchor }}">
+<h2>{{ html .Name }}</h2>
+{{- if .Description }}
+<p class="description">{{ html .Description }}</p>
+{{- end }}
+<div>{{ .Schema }}</div>
+{{- if .Example }}
+<h3>Example</h3>
+<pre>{{ html .Example }}</pre>
+{{- end }}
+</section>
+{{- end }}
+`
@@ -0,0 +1,63 @@
+package docsite_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/docsite"
+	"goa.design/plugins/v3/docsite/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := docsite.Generate("", []eval.Root{goaexpr.Root}, ofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs = fs[len(ofs):]
+	expected := []string{"index.html", "schemas.html", "catalog.html", "admin.html", "site.css", "site.js", "spec.js"}
+	if len(fs) != len(expected) {
+		t.Fatalf("got %d files, expected %d", len(fs), len(expected))
+	}
+	for i, f := range fs {
+		if f.Path != filepath.Join("gen", "docs", expected[i]) {
+			t.Errorf("got file %q, expected %q", f.Path, filepath.Join("gen", "docs", expected[i]))
+		}
+	}
+	for _, f := range fs {
+		var buf bytes.Buffer
+		for _, s := range f.SectionTemplates {
+			if err := s.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		switch filepath.Ext(f.Path) {
+		case ".html":
+			plugintest.Golden(t, filepath.Base(f.Path), buf.String())
+		case ".js":
+			if filepath.Base(f.Path) == "spec.js" && !strings.HasPrefix(buf.String(), `window.SPEC = {"swagger":"2.0"`) {
+				t.Errorf("invalid spec script:\n%s", buf.String())
+			}
+		}
+	}
+}
+
+func TestGenerateNoSpec(t *testing.T) {
+	fs, err := docsite.Generate("", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 0 {
+		t.Errorf("got %d files, expected 0", len(fs))
+	}
+}
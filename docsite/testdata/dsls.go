@@ -0,0 +1,74 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CatalogDSL = func() {
+	API("catalog", func() {
+		Title("Catalog API")
+		Description("The catalog API manages the items of the catalog.")
+		Version("1.0")
+	})
+	var JWTAuth = JWTSecurity("jwt", func() {
+		Scope("api:read", "Read access")
+		Scope("api:write", "Write access")
+	})
+	var Item = Type("Item", func() {
+		Description("Item is a catalog item.")
+		Attribute("id", String, "Item identifier", func() {
+			Example("42")
+		})
+		Attribute("name", String, "Item <name>", func() {
+			Example("pen")
+		})
+		Attribute("tags", ArrayOf(String), "Item tags", func() {
+			Example([]string{"office"})
+		})
+		Required("id", "name")
+	})
+	Service("Catalog", func() {
+		Description("Catalog manages the items.")
+		Security(JWTAuth, func() {
+			Scope("api:read")
+		})
+		Method("List", func() {
+			Description("List the items matching the given tags.")
+			Payload(func() {
+				Token("token", String)
+				Attribute("tags", ArrayOf(String), "Tags to match")
+				Attribute("limit", Int, "Maximum number of items", func() {
+					Default(10)
+				})
+			})
+			Result(ArrayOf(Item))
+			HTTP(func() {
+				GET("/items")
+				Param("tags")
+				Param("limit")
+				Response(StatusOK)
+			})
+		})
+		Method("Create", func() {
+			Description("Create adds an item to the catalog.")
+			Security(JWTAuth, func() {
+				Scope("api:write")
+			})
+			Payload(func() {
+				Token("token", String)
+				Attribute("item", Item)
+				Required("item")
+			})
+			Result(Item)
+			Error("invalid", String, "Invalid item")
+			HTTP(func() {
+				POST("/items")
+				Body("item")
+				Response(StatusCreated)
+				Response("invalid", StatusBadRequest)
+				Meta("swagger:tag:Admin")
+				Meta("swagger:tag:Admin:desc", "Administrative operations")
+			})
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/encoding/expr"
+
+	// Register code generators for the encoding plugin
+	_ "goa.design/plugins/v3/encoding"
+)
+
+// Encoding registers a custom encoder and decoder for the given media type on
+// the HTTP endpoints of the API or service. constructor is the name of the
+// function creating the codec qualified with the import path of its package,
+// for example "github.com/acme/codec.NewFooCodec". The function must accept
+// no argument and return a value implementing the Codec interface of the
+// goa.design/plugins/v3/encoding package.
+//
+// The generated HTTP servers decode the request bodies whose Content-Type is
+// the media type and encode the responses with the codec when the response
+// content type set in the design or the first media type of the request
+// Accept header is the media type. The generated HTTP clients decode the
+// response bodies whose Content-Type is the media type. A service encoding
+// overrides the API encoding of the same media type.
+//
+// Encoding must appear in an API or Service expression.
+//
+// Example:
+//
+//    import encoding "goa.design/plugins/v3/encoding/dsl"
+//
+//    var _ = API("inventory", func() {
+//        encoding.Encoding("application/vnd.acme+foo", "github.com/acme/codec.NewFooCodec")
+//    })
+//
+func Encoding(mediaType, constructor string) {
+	e := &expr.EncodingExpr{MediaType: mediaType, Constructor: constructor}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		e.Service = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Encodings = append(expr.Root.Encodings, e)
+}
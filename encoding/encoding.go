@@ -0,0 +1,207 @@
+package encoding
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type (
+	// Codec creates the encoders and decoders of a custom encoding. The
+	// constructors given to the Encoding DSL return a Codec.
+	Codec interface {
+		// NewEncoder returns an encoder writing to w.
+		NewEncoder(w io.Writer) goahttp.Encoder
+		// NewDecoder returns a decoder reading from r.
+		NewDecoder(r io.Reader) goahttp.Decoder
+	}
+
+	// Format is an encoding recognized by its media types. The plugins
+	// implementing a single encoding, e.g. cbor or xml, wrap the HTTP
+	// encoders and decoders with a Format.
+	Format struct {
+		// MediaType is the media type of the requests encoded with the
+		// format.
+		MediaType string
+		// Match returns true if the given media type, without
+		// parameters, is a media type of the format. Only MediaType
+		// matches if nil.
+		Match func(mt string) bool
+		// Codec creates the encoders and decoders of the format.
+		Codec Codec
+	}
+
+	// codec is a Codec built from constructor functions.
+	codec struct {
+		enc func(io.Writer) goahttp.Encoder
+		dec func(io.Reader) goahttp.Decoder
+	}
+)
+
+// NewCodec returns a Codec creating the encoders and decoders with the given
+// constructors.
+func NewCodec(enc func(w io.Writer) goahttp.Encoder, dec func(r io.Reader) goahttp.Decoder) Codec {
+	return &codec{enc: enc, dec: dec}
+}
+
+// NewEncoder calls the encoder constructor of the codec.
+func (c *codec) NewEncoder(w io.Writer) goahttp.Encoder { return c.enc(w) }
+
+// NewDecoder calls the decoder constructor of the codec.
+func (c *codec) NewDecoder(r io.Reader) goahttp.Decoder { return c.dec(r) }
+
+// WrapRequestDecoder returns a HTTP request decoder that decodes the bodies of
+// the requests whose Content-Type is one of the media types of codecs with the
+// corresponding codec and uses dec for the other requests.
+func WrapRequestDecoder(dec func(*http.Request) goahttp.Decoder, codecs map[string]Codec) func(*http.Request) goahttp.Decoder {
+	return wrapRequestDecoder(dec, lookup(codecs))
+}
+
+// WrapResponseEncoder returns a HTTP response encoder that encodes the
+// responses with the codec of the response content type set in the design or
+// of the first media type of the request Accept header and uses enc if codecs
+// has no such codec.
+func WrapResponseEncoder(enc func(context.Context, http.ResponseWriter) goahttp.Encoder, codecs map[string]Codec) func(context.Context, http.ResponseWriter) goahttp.Encoder {
+	return wrapResponseEncoder(enc, lookup(codecs))
+}
+
+// WrapResponseDecoder returns a HTTP response decoder that decodes the bodies
+// of the responses whose Content-Type is one of the media types of codecs with
+// the corresponding codec and uses dec for the other responses.
+func WrapResponseDecoder(dec func(*http.Response) goahttp.Decoder, codecs map[string]Codec) func(*http.Response) goahttp.Decoder {
+	return wrapResponseDecoder(dec, lookup(codecs))
+}
+
+// RequestEncoder returns a HTTP request encoder that encodes the request
+// bodies with the given codec. It also sets the Accept header so that the
+// servers generated with the plugin respond with the same media type.
+func RequestEncoder(mt string, c Codec) func(*http.Request) goahttp.Encoder {
+	return func(r *http.Request) goahttp.Encoder {
+		var buf bytes.Buffer
+		r.Body = ioutil.NopCloser(&buf)
+		r.Header.Set("Content-Type", mt)
+		r.Header.Set("Accept", mt)
+		return c.NewEncoder(&buf)
+	}
+}
+
+// Is returns true if the given Content-Type or Accept header value is a media
+// type of the format.
+func (f *Format) Is(ct string) bool {
+	mt := MediaType(ct)
+	if f.Match == nil {
+		return mt == f.MediaType
+	}
+	return f.Match(mt)
+}
+
+// WrapRequestDecoder returns a HTTP request decoder that decodes the bodies of
+// the requests whose Content-Type is a media type of the format and uses dec
+// for the other requests.
+func (f *Format) WrapRequestDecoder(dec func(*http.Request) goahttp.Decoder) func(*http.Request) goahttp.Decoder {
+	return wrapRequestDecoder(dec, f.lookup)
+}
+
+// WrapResponseEncoder returns a HTTP response encoder that encodes the
+// responses with the format if the response content type set in the design or
+// the first media type of the request Accept header is a media type of the
+// format and uses enc otherwise.
+func (f *Format) WrapResponseEncoder(enc func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter) goahttp.Encoder {
+	return wrapResponseEncoder(enc, f.lookup)
+}
+
+// WrapResponseDecoder returns a HTTP response decoder that decodes the bodies
+// of the responses whose Content-Type is a media type of the format and uses
+// dec for the other responses.
+func (f *Format) WrapResponseDecoder(dec func(*http.Response) goahttp.Decoder) func(*http.Response) goahttp.Decoder {
+	return wrapResponseDecoder(dec, f.lookup)
+}
+
+// RequestEncoder is a HTTP request encoder that encodes the request bodies with
+// the format. It also sets the Accept header so that the servers generated
+// with the plugins respond with the same media type.
+func (f *Format) RequestEncoder(r *http.Request) goahttp.Encoder {
+	return RequestEncoder(f.MediaType, f.Codec)(r)
+}
+
+// lookup returns the codec of the format if mt is one of its media types, nil
+// otherwise.
+func (f *Format) lookup(mt string) Codec {
+	if f.Is(mt) {
+		return f.Codec
+	}
+	return nil
+}
+
+// MediaType returns the media type of the given Content-Type or Accept header
+// value without its parameters.
+func MediaType(ct string) string {
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	return strings.TrimSpace(ct)
+}
+
+// lookup returns a function returning the codec of codecs indexed by the
+// given media type, nil if there is none.
+func lookup(codecs map[string]Codec) func(string) Codec {
+	return func(mt string) Codec {
+		return codecs[mt]
+	}
+}
+
+// wrapRequestDecoder returns a HTTP request decoder that decodes the bodies of
+// the requests with the codec returned by lookup for their Content-Type and
+// uses dec if there is none.
+func wrapRequestDecoder(dec func(*http.Request) goahttp.Decoder, lookup func(string) Codec) func(*http.Request) goahttp.Decoder {
+	return func(r *http.Request) goahttp.Decoder {
+		if c := lookup(MediaType(r.Header.Get("Content-Type"))); c != nil {
+			return c.NewDecoder(r.Body)
+		}
+		return dec(r)
+	}
+}
+
+// wrapResponseEncoder returns a HTTP response encoder that encodes the
+// responses with the codec returned by lookup for the negotiated media type
+// and uses enc if there is none.
+func wrapResponseEncoder(enc func(context.Context, http.ResponseWriter) goahttp.Encoder, lookup func(string) Codec) func(context.Context, http.ResponseWriter) goahttp.Encoder {
+	return func(ctx context.Context, w http.ResponseWriter) goahttp.Encoder {
+		mt := negotiate(ctx)
+		c := lookup(mt)
+		if c == nil {
+			return enc(ctx, w)
+		}
+		goahttp.SetContentType(w, mt)
+		return c.NewEncoder(w)
+	}
+}
+
+// wrapResponseDecoder returns a HTTP response decoder that decodes the bodies
+// of the responses with the codec returned by lookup for their Content-Type
+// and uses dec if there is none.
+func wrapResponseDecoder(dec func(*http.Response) goahttp.Decoder, lookup func(string) Codec) func(*http.Response) goahttp.Decoder {
+	return func(resp *http.Response) goahttp.Decoder {
+		if c := lookup(MediaType(resp.Header.Get("Content-Type"))); c != nil {
+			return c.NewDecoder(resp.Body)
+		}
+		return dec(resp)
+	}
+}
+
+// negotiate returns the media type of the response: the content type set in
+// the design if any, the first media type of the request Accept header
+// otherwise.
+func negotiate(ctx context.Context) string {
+	if ct, ok := ctx.Value(goahttp.ContentTypeKey).(string); ok && ct != "" {
+		return MediaType(ct)
+	}
+	accept, _ := ctx.Value(goahttp.AcceptTypeKey).(string)
+	return MediaType(strings.Split(accept, ",")[0])
+}
@@ -0,0 +1,130 @@
+package encoding
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type (
+	// textCodec encodes strings as "text:" followed by the string.
+	textCodec struct{}
+
+	textEncoder struct{ w io.Writer }
+
+	textDecoder struct{ r io.Reader }
+)
+
+const textMediaType = "application/vnd.test.text"
+
+var codecs = map[string]Codec{textMediaType: textCodec{}}
+
+func (textCodec) NewEncoder(w io.Writer) goahttp.Encoder { return &textEncoder{w} }
+func (textCodec) NewDecoder(r io.Reader) goahttp.Decoder { return &textDecoder{r} }
+
+func (e *textEncoder) Encode(v interface{}) error {
+	_, err := fmt.Fprintf(e.w, "text:%s", v)
+	return err
+}
+
+func (d *textDecoder) Decode(v interface{}) error {
+	b, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	*(v.(*string)) = strings.TrimPrefix(string(b), "text:")
+	return nil
+}
+
+func TestWrapRequestDecoder(t *testing.T) {
+	cases := map[string]struct {
+		ContentType string
+		Body        string
+		Expected    string
+	}{
+		"custom":     {textMediaType, "text:foo", "foo"},
+		"parameters": {textMediaType + "; charset=utf-8", "text:foo", "foo"},
+		"json":       {"application/json", `"foo"`, "foo"},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", strings.NewReader(c.Body))
+			r.Header.Set("Content-Type", c.ContentType)
+			var v string
+			if err := WrapRequestDecoder(goahttp.RequestDecoder, codecs)(r).Decode(&v); err != nil {
+				t.Fatal(err)
+			}
+			if v != c.Expected {
+				t.Errorf("got %q, expected %q", v, c.Expected)
+			}
+		})
+	}
+}
+
+func TestWrapResponseEncoder(t *testing.T) {
+	cases := map[string]struct {
+		ContentType string
+		Accept      string
+		Expected    string
+		ExpectedCT  string
+	}{
+		"accept":       {"", textMediaType + ", application/json", "text:foo", textMediaType},
+		"content type": {textMediaType, "application/json", "text:foo", textMediaType},
+		"json":         {"", "application/json", "\"foo\"\n", "application/json"},
+		"second":       {"", "application/json, " + textMediaType, "\"foo\"\n", "application/json"},
+	}
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), goahttp.AcceptTypeKey, c.Accept)
+			if c.ContentType != "" {
+				ctx = context.WithValue(ctx, goahttp.ContentTypeKey, c.ContentType)
+			}
+			w := httptest.NewRecorder()
+			if err := WrapResponseEncoder(goahttp.ResponseEncoder, codecs)(ctx, w).Encode("foo"); err != nil {
+				t.Fatal(err)
+			}
+			if actual := w.Body.String(); actual != c.Expected {
+				t.Errorf("got %q, expected %q", actual, c.Expected)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != c.ExpectedCT {
+				t.Errorf("got content type %q, expected %q", ct, c.ExpectedCT)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	r, _ := http.NewRequest("POST", "/", nil)
+	if err := RequestEncoder(textMediaType, textCodec{})(r).Encode("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if ct := r.Header.Get("Content-Type"); ct != textMediaType {
+		t.Errorf("got content type %q, expected %q", ct, textMediaType)
+	}
+	if accept := r.Header.Get("Accept"); accept != textMediaType {
+		t.Errorf("got accept %q, expected %q", accept, textMediaType)
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	if string(body) != "text:foo" {
+		t.Errorf("got body %q, expected %q", body, "text:foo")
+	}
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": {textMediaType}},
+		Body:   ioutil.NopCloser(bytes.NewBufferString("text:bar")),
+	}
+	var v string
+	if err := WrapResponseDecoder(goahttp.ResponseDecoder, codecs)(resp).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v != "bar" {
+		t.Errorf("got %q, expected %q", v, "bar")
+	}
+}
@@ -0,0 +1,75 @@
+package expr
+
+import (
+	"fmt"
+	"go/token"
+	"mime"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// EncodingExpr describes a custom encoding of the HTTP request and
+	// response bodies of an API or service.
+	EncodingExpr struct {
+		// MediaType is the media type of the bodies.
+		MediaType string
+		// Constructor is the fully qualified name of the function
+		// returning the codec, for example
+		// "github.com/acme/codec.NewCodec".
+		Constructor string
+		// Service is the service the encoding applies to, nil for API
+		// encodings.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (e *EncodingExpr) EvalName() string {
+	if e.Service != nil {
+		return fmt.Sprintf("encoding %q of %s", e.MediaType, e.Service.EvalName())
+	}
+	return fmt.Sprintf("encoding %q", e.MediaType)
+}
+
+// Validate makes sure the media type is valid and that the constructor is a
+// fully qualified function name.
+func (e *EncodingExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if mt, params, err := mime.ParseMediaType(e.MediaType); err != nil || mt != e.MediaType || len(params) > 0 {
+		verr.Add(e, "invalid media type %q", e.MediaType)
+	}
+	if pkg, fn := e.Package(), e.Func(); pkg == "" || !token.IsIdentifier(fn) || !token.IsExported(fn) {
+		verr.Add(e, "constructor must be an exported function name qualified with its package import path, got %q", e.Constructor)
+	}
+	for _, o := range Root.Encodings {
+		if o != e && o.MediaType == e.MediaType && o.Service == e.Service {
+			verr.Add(e, "encoding defined twice")
+			break
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Package returns the import path of the package defining the constructor.
+func (e *EncodingExpr) Package() string {
+	i := strings.LastIndex(e.Constructor, ".")
+	if i <= strings.LastIndex(e.Constructor, "/") {
+		return ""
+	}
+	return e.Constructor[:i]
+}
+
+// Func returns the name of the constructor function.
+func (e *EncodingExpr) Func() string {
+	i := strings.LastIndex(e.Constructor, ".")
+	if i <= strings.LastIndex(e.Constructor, "/") {
+		return ""
+	}
+	return e.Constructor[i+1:]
+}
@@ -0,0 +1,71 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the custom encodings defined in the design.
+	RootExpr struct {
+		// Encodings lists the API and service encodings.
+		Encodings []*EncodingExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "encoding plugin"
+}
+
+// WalkSets iterates over the encodings.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	exps := make(eval.ExpressionSet, len(r.Encodings))
+	for i, e := range r.Encodings {
+		exps[i] = e
+	}
+	walk(exps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/encoding/dsl"}
+}
+
+// ServiceEncodings returns the encodings of the given service: the API
+// encodings followed by the service encodings. A service encoding overrides
+// the API encoding of the same media type.
+func (r *RootExpr) ServiceEncodings(svc string) []*EncodingExpr {
+	var res []*EncodingExpr
+	for _, e := range r.Encodings {
+		if e.Service != nil && e.Service.Name != svc {
+			continue
+		}
+		replaced := false
+		for i, o := range res {
+			if o.MediaType == e.MediaType {
+				res[i] = e
+				replaced = true
+			}
+		}
+		if !replaced {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,214 @@
+package encoding
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/encoding/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// codecData contains the data necessary to render the initialization of a
+// custom encoding codec.
+type codecData struct {
+	// MediaType is the media type of the encoding.
+	MediaType string
+	// Constructor is the reference to the constructor function.
+	Constructor string
+}
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "encoding",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate makes the HTTP servers and clients use the custom encodings of
+// their service and documents the media types in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Encodings) == 0 {
+		return files, nil
+	}
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok {
+			continue
+		}
+		for _, f := range files {
+			if !config.EnabledFile("encoding", f) {
+				continue
+			}
+			switch filepath.Base(f.Path) {
+			case "server.go":
+				serverEncodings(f)
+			case "client.go":
+				clientEncodings(f)
+			default:
+				documentEncodings(f, r)
+			}
+		}
+	}
+	return files, nil
+}
+
+// serverEncodings defines the codecs of the service in the HTTP server file and
+// wraps the request decoder and response encoder given to the HTTP server
+// constructor so that they use the codecs.
+func serverEncodings(f *codegen.File) {
+	for _, s := range f.Section("server-init") {
+		data, ok := s.Data.(*httpcodegen.ServiceData)
+		if !ok || !addCodecs(f, data.Service.Name) {
+			continue
+		}
+		s.Source = strings.Replace(s.Source, "return &{{ .ServerStruct }}{",
+			"dec = encoding.WrapRequestDecoder(dec, Encodings)\n\tenc = encoding.WrapResponseEncoder(enc, Encodings)\n\treturn &{{ .ServerStruct }}{", 1)
+	}
+}
+
+// clientEncodings defines the codecs of the service in the HTTP client file
+// and wraps the response decoder given to the HTTP client constructor so that
+// it uses the codecs.
+func clientEncodings(f *codegen.File) {
+	for _, s := range f.Section("client-init") {
+		data, ok := s.Data.(*httpcodegen.ServiceData)
+		if !ok || !addCodecs(f, data.Service.Name) {
+			continue
+		}
+		s.Source = strings.Replace(s.Source, "return &{{ .ClientStruct }}{",
+			"dec = encoding.WrapResponseDecoder(dec, Encodings)\n\treturn &{{ .ClientStruct }}{", 1)
+	}
+}
+
+// addCodecs adds the section defining the codecs of the given service to f
+// and imports the packages of their constructors. It returns false if the
+// service has no custom encoding.
+func addCodecs(f *codegen.File, svc string) bool {
+	encs := expr.Root.ServiceEncodings(svc)
+	if len(encs) == 0 {
+		return false
+	}
+	taken := importedNames(f.SectionTemplates[0])
+	taken["encoding"] = true
+	imports := []*codegen.ImportSpec{{Path: "goa.design/plugins/v3/encoding"}}
+	aliases := make(map[string]string)
+	codecs := make([]*codecData, len(encs))
+	for i, e := range encs {
+		alias, ok := aliases[e.Package()]
+		if !ok {
+			alias = importAlias(e.Package(), taken)
+			taken[alias] = true
+			aliases[e.Package()] = alias
+			imports = append(imports, &codegen.ImportSpec{Path: e.Package(), Name: alias})
+		}
+		codecs[i] = &codecData{MediaType: e.MediaType, Constructor: alias + "." + e.Func()}
+	}
+	codegen.AddImport(f.SectionTemplates[0], imports...)
+	f.SectionTemplates = append(f.SectionTemplates, &codegen.SectionTemplate{
+		Name:   "encoding-codecs",
+		Source: codecsT,
+		Data:   map[string]interface{}{"Service": svc, "Codecs": codecs},
+	})
+	return true
+}
+
+// importedNames returns the names of the packages imported by the given file
+// header section.
+func importedNames(header *codegen.SectionTemplate) map[string]bool {
+	names := make(map[string]bool)
+	data, ok := header.Data.(map[string]interface{})
+	if !ok {
+		return names
+	}
+	imports, _ := data["Imports"].([]*codegen.ImportSpec)
+	for _, imp := range imports {
+		if imp.Name != "" {
+			names[imp.Name] = true
+		} else {
+			names[imp.Path[strings.LastIndex(imp.Path, "/")+1:]] = true
+		}
+	}
+	return names
+}
+
+// importAlias returns the name used to import the package with the given
+// path: the last element of the path stripped of the characters that are not
+// valid in identifiers, suffixed with a number if the name is taken.
+func importAlias(path string, taken map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r - 'A' + 'a'
+		}
+		return -1
+	}, path[strings.LastIndex(path, "/")+1:])
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "codec" + name
+	}
+	alias := name
+	for i := 2; taken[alias]; i++ {
+		alias = fmt.Sprintf("%s%d", name, i)
+	}
+	return alias
+}
+
+// documentEncodings adds the media types of the custom encodings to the media
+// types consumed and produced by the operations of the services if f is an
+// OpenAPI file.
+func documentEncodings(f *codegen.File, r *goaexpr.RootExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("encoding", op) {
+				return nil
+			}
+			svc := operationService(r, op)
+			if svc == "" {
+				return nil
+			}
+			for _, e := range expr.Root.ServiceEncodings(svc) {
+				if walk.HasBody(op) {
+					if len(op.Consumes) == 0 {
+						op.Consumes = append([]string{}, spec.Consumes...)
+					}
+					op.Consumes = walk.AppendMediaType(op.Consumes, e.MediaType)
+				}
+				if len(op.Produces) == 0 {
+					op.Produces = append([]string{}, spec.Produces...)
+				}
+				op.Produces = walk.AppendMediaType(op.Produces, e.MediaType)
+			}
+			return nil
+		})
+	})
+}
+
+// operationService returns the name of the service of the given operation,
+// the empty string if there is none.
+func operationService(r *goaexpr.RootExpr, op *openapi.Operation) string {
+	m := walk.OperationMethod(r, op)
+	if m == nil {
+		return ""
+	}
+	return m.Service.Name
+}
+
+// input: map[string]interface{}{"Service": string, "Codecs": []*codecData}
+const codecsT = `{{ printf "Encodings lists the codecs of the custom encodings of the %q service indexed by media type." .Service | comment }}
+var Encodings = map[string]encoding.Codec{
+{{- range .Codecs }}
+	{{ printf "%q" .MediaType }}: {{ .Constructor }}(),
+{{- end }}
+}
+`
@@ -0,0 +1,112 @@
+package encoding_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/encoding"
+	"goa.design/plugins/v3/encoding/expr"
+	"goa.design/plugins/v3/encoding/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Encodings = nil
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	roots := []eval.Root{goaexpr.Root}
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := append(httpcodegen.ServerFiles("", goaexpr.Root), httpcodegen.ClientFiles("", goaexpr.Root)...)
+	fs, err = encoding.Generate("", roots, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections := map[string]map[string]string{
+		"gen/http/catalog/server/server.go": {
+			"encoding-codecs": testdata.CatalogCodecsCode,
+			"server-init":     testdata.ServerInitCode,
+		},
+		"gen/http/admin/server/server.go": {
+			"encoding-codecs": testdata.AdminCodecsCode,
+		},
+		"gen/http/catalog/client/client.go": {
+			"encoding-codecs": testdata.CatalogCodecsCode,
+			"client-init":     testdata.ClientInitCode,
+		},
+	}
+	imports := map[string]map[string]string{
+		"gen/http/catalog/server/server.go": {"github.com/acme/codec": "codec", "github.com/acme/fast-codec": "fastcodec"},
+		"gen/http/admin/server/server.go":   {"github.com/acme/codec": "codec"},
+	}
+	for _, f := range fs {
+		for name, expected := range sections[f.Path] {
+			ss := f.Section(name)
+			if len(ss) != 1 {
+				t.Fatalf("%s: got %d %q sections, expected 1", f.Path, len(ss), name)
+			}
+			code := codegen.SectionCode(t, ss[0])
+			if code != expected {
+				t.Errorf("%s: invalid %q code, got:\n%s\ngot vs. expected:\n%s", f.Path, name, code, codegen.Diff(t, code, expected))
+			}
+		}
+		if expected, ok := imports[f.Path]; ok {
+			actual := make(map[string]string)
+			specs := f.SectionTemplates[0].Data.(map[string]interface{})["Imports"].([]*codegen.ImportSpec)
+			for _, imp := range specs {
+				if _, ok := expected[imp.Path]; ok {
+					if _, dup := actual[imp.Path]; dup {
+						t.Errorf("%s: %q imported twice", f.Path, imp.Path)
+					}
+					actual[imp.Path] = imp.Name
+				}
+			}
+			for path, name := range expected {
+				if actual[path] != name {
+					t.Errorf("%s: got %q imported as %q, expected %q", f.Path, path, actual[path], name)
+				}
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	cases := map[string]struct {
+		Op       *openapi.Operation
+		Consumes []string
+		Produces []string
+	}{
+		"show":   {spec.Paths["/items/{id}"].(*openapi.Path).Get, nil, []string{"application/vnd.acme.foo", "application/vnd.acme.bar"}},
+		"create": {spec.Paths["/items"].(*openapi.Path).Post, []string{"application/vnd.acme.foo", "application/vnd.acme.bar"}, []string{"application/vnd.acme.foo", "application/vnd.acme.bar"}},
+		"purge":  {spec.Paths["/purge"].(*openapi.Path).Post, []string{"application/vnd.acme.foo"}, []string{"application/vnd.acme.foo"}},
+	}
+	for k, c := range cases {
+		for _, mt := range c.Consumes {
+			if !contains(c.Op.Consumes, mt) {
+				t.Errorf("%s: %q not consumed", k, mt)
+			}
+		}
+		if c.Consumes == nil && contains(c.Op.Consumes, "application/vnd.acme.foo") {
+			t.Errorf("%s: unexpected custom media type consumed", k)
+		}
+		for _, mt := range c.Produces {
+			if !contains(c.Op.Produces, mt) {
+				t.Errorf("%s: %q not produced", k, mt)
+			}
+		}
+	}
+	if contains(spec.Paths["/purge"].(*openapi.Path).Post.Produces, "application/vnd.acme.bar") {
+		t.Errorf("purge: unexpected Catalog service media type produced")
+	}
+}
+
+func contains(mts []string, mt string) bool {
+	for _, m := range mts {
+		if m == mt {
+			return true
+		}
+	}
+	return false
+}
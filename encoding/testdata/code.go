@@ -0,0 +1,59 @@
+package testdata
+
+const CatalogCodecsCode = `// Encodings lists the codecs of the custom encodings of the "Catalog" service
+// indexed by media type.
+var Encodings = map[string]encoding.Codec{
+	"application/vnd.acme.foo": fastcodec.NewFooCodec(),
+	"application/vnd.acme.bar": codec.NewBarCodec(),
+}
+`
+
+const AdminCodecsCode = `// Encodings lists the codecs of the custom encodings of the "Admin" service
+// indexed by media type.
+var Encodings = map[string]encoding.Codec{
+	"application/vnd.acme.foo": codec.NewFooCodec(),
+}
+`
+
+const ServerInitCode = `// New instantiates HTTP handlers for all the Catalog service endpoints.
+func New(
+	e *catalog.Endpoints,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) *Server {
+	dec = encoding.WrapRequestDecoder(dec, Encodings)
+	enc = encoding.WrapResponseEncoder(enc, Encodings)
+	return &Server{
+		Mounts: []*MountPoint{
+			{"Show", "GET", "/items/{id}"},
+			{"Create", "POST", "/items"},
+		},
+		Show:   NewShowHandler(e.Show, mux, dec, enc, eh),
+		Create: NewCreateHandler(e.Create, mux, dec, enc, eh),
+	}
+}
+`
+
+const ClientInitCode = `// NewClient instantiates HTTP clients for all the Catalog service servers.
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+) *Client {
+	dec = encoding.WrapResponseDecoder(dec, Encodings)
+	return &Client{
+		ShowDoer:            doer,
+		CreateDoer:          doer,
+		RestoreResponseBody: restoreBody,
+		scheme:              scheme,
+		host:                host,
+		decoder:             dec,
+		encoder:             enc,
+	}
+}
+`
@@ -0,0 +1,45 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	encoding "goa.design/plugins/v3/encoding/dsl"
+)
+
+var CatalogDSL = func() {
+	API("catalog", func() {
+		encoding.Encoding("application/vnd.acme.foo", "github.com/acme/codec.NewFooCodec")
+	})
+	var Item = Type("Item", func() {
+		Attribute("id", String)
+		Attribute("name", String)
+		Required("id", "name")
+	})
+	Service("Catalog", func() {
+		encoding.Encoding("application/vnd.acme.bar", "github.com/acme/codec.NewBarCodec")
+		encoding.Encoding("application/vnd.acme.foo", "github.com/acme/fast-codec.NewFooCodec")
+		Method("Show", func() {
+			Payload(String)
+			Result(Item)
+			HTTP(func() {
+				GET("/items/{id}")
+				Response(StatusOK)
+			})
+		})
+		Method("Create", func() {
+			Payload(Item)
+			Result(Item)
+			HTTP(func() {
+				POST("/items")
+				Response(StatusCreated)
+			})
+		})
+	})
+	Service("Admin", func() {
+		Method("Purge", func() {
+			Payload(Item)
+			HTTP(func() {
+				POST("/purge")
+			})
+		})
+	})
+}
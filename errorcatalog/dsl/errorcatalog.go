@@ -0,0 +1,44 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/errorcatalog/expr"
+
+	// Register code generators for the error catalog plugin
+	_ "goa.design/plugins/v3/errorcatalog"
+)
+
+// ErrorCode sets the stable code of the error. The code is listed in the
+// generated error registry, errors.json and Markdown reference and is set in
+// the X-Error-Code header of the HTTP error responses. Codes must be unique in
+// the design.
+//
+// ErrorCode must appear in an Error expression.
+//
+// Example:
+//
+//    import errorcatalog "goa.design/plugins/v3/errorcatalog/dsl"
+//
+//    var _ = Service("orders", func() {
+//        Error("not_found", func() {
+//            Description("Order not found.")
+//            errorcatalog.ErrorCode("ORD-404-001")
+//        })
+//        HTTP(func() {
+//            Response("not_found", StatusNotFound)
+//        })
+//    })
+//
+func ErrorCode(code string) {
+	att, ok := eval.Current().(*goaexpr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	att.Meta[expr.CodeKey] = []string{code}
+	expr.Root.Codes = append(expr.Root.Codes, &expr.CodeExpr{Code: code, Attribute: att})
+}
@@ -0,0 +1,66 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// codeRegex matches valid error codes, for example "ORD-404-001".
+var codeRegex = regexp.MustCompile(`^[A-Za-z0-9]+([-_.][A-Za-z0-9]+)*$`)
+
+// CodeExpr describes the stable code of an error.
+type CodeExpr struct {
+	// Code is the error code.
+	Code string
+	// Attribute is the attribute of the error.
+	Attribute *expr.AttributeExpr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (c *CodeExpr) EvalName() string {
+	return fmt.Sprintf("error code %q", c.Code)
+}
+
+// Validate makes sure the code is valid, unique and applies to an error.
+func (c *CodeExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if !codeRegex.MatchString(c.Code) {
+		verr.Add(c, "invalid error code, codes consist of letters and digits separated with '-', '_' or '.'")
+	}
+	for _, other := range Root.Codes {
+		if other != c && other.Code == c.Code {
+			verr.Add(c, "error code is used more than once")
+			break
+		}
+	}
+	if !isError(c.Attribute) {
+		verr.Add(c, "ErrorCode must appear in an Error expression")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// isError returns true if the given attribute is the attribute of a service
+// or method error.
+func isError(att *expr.AttributeExpr) bool {
+	for _, svc := range expr.Root.Services {
+		for _, e := range svc.Errors {
+			if e.AttributeExpr == att {
+				return true
+			}
+		}
+		for _, m := range svc.Methods {
+			for _, e := range m.Errors {
+				if e.AttributeExpr == att {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// CodeKey is the meta key recording the code of an error. The meta is set on
+// the error attribute as the Error DSL does not make the error expression
+// available to the functions it executes.
+const CodeKey = "errorcatalog:code"
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the error codes defined in the design.
+	RootExpr struct {
+		// Codes lists the error codes in the order they appear in the
+		// design.
+		Codes []*CodeExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "error catalog plugin"
+}
+
+// WalkSets iterates over the error codes.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	cexps := make(eval.ExpressionSet, len(r.Codes))
+	for i, c := range r.Codes {
+		cexps[i] = c
+	}
+	walk(cexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/errorcatalog/dsl"}
+}
+
+// Code returns the code of the given error, the empty string if the error
+// has no code.
+func Code(e *expr.ErrorExpr) string {
+	if c, ok := e.Meta[CodeKey]; ok && len(c) > 0 {
+		return c[0]
+	}
+	return ""
+}
@@ -0,0 +1,412 @@
+package errorcatalog
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/errorcatalog/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+	"goa.design/plugins/v3/walk"
+)
+
+// header is the name of the HTTP response header holding the error code.
+const header = "X-Error-Code"
+
+type (
+	// errorData describes an error of the catalog.
+	errorData struct {
+		// Code is the error code.
+		Code string `json:"code"`
+		// Const is the name of the Go constant holding the code.
+		Const string `json:"-"`
+		// Name is the name of the error.
+		Name string `json:"name"`
+		// Service is the name of the service returning the error.
+		Service string `json:"service"`
+		// Method is the name of the method returning the error, empty
+		// if the error is defined by the service.
+		Method string `json:"method,omitempty"`
+		// Description is the error description.
+		Description string `json:"description,omitempty"`
+		// Status is the HTTP status code of the error response, 0 if the
+		// error is not mapped to a HTTP response.
+		Status int `json:"status,omitempty"`
+		// Temporary is true if the error is temporary.
+		Temporary bool `json:"temporary,omitempty"`
+		// Timeout is true if the error is a timeout.
+		Timeout bool `json:"timeout,omitempty"`
+		// Fault is true if the error is a server-side fault.
+		Fault bool `json:"fault,omitempty"`
+	}
+
+	// serviceData groups the errors of a service in the Markdown reference.
+	serviceData struct {
+		// Name is the name of the service.
+		Name string
+		// Errors lists the errors of the service and of its methods.
+		Errors []*errorData
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "errorcatalog",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the error registry package, the errors.json file and the
+// Markdown error reference. It also sets the code of the errors in the HTTP
+// error responses and documents the response header in the OpenAPI
+// specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Codes) == 0 {
+		return files, nil
+	}
+	var errs []*errorData
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			errs = append(errs, buildErrors(r)...)
+		}
+	}
+	if len(errs) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("errorcatalog", f) {
+			continue
+		}
+		encodeCodes(f, genpkg, errs)
+		documentCodes(f)
+	}
+	return append(files, registryFile(errs), jsonFile(errs), markdownFile(errs)), nil
+}
+
+// buildErrors returns the errors of the catalog in the order they appear in
+// the design.
+func buildErrors(r *goaexpr.RootExpr) []*errorData {
+	var errs []*errorData
+	for _, svc := range r.Services {
+		if !config.Enabled("errorcatalog", svc.Name) {
+			continue
+		}
+		var hsvc *goaexpr.HTTPServiceExpr
+		if r.API != nil && r.API.HTTP != nil {
+			hsvc = r.API.HTTP.Service(svc.Name)
+		}
+		for _, e := range svc.Errors {
+			if code := expr.Code(e); code != "" {
+				var herrs []*goaexpr.HTTPErrorExpr
+				if hsvc != nil {
+					herrs = hsvc.HTTPErrors
+				}
+				errs = append(errs, buildError(code, svc, nil, e, herrs))
+			}
+		}
+		for _, m := range svc.Methods {
+			for _, e := range m.Errors {
+				if code := expr.Code(e); code != "" {
+					var herrs []*goaexpr.HTTPErrorExpr
+					if hsvc != nil {
+						if he := hsvc.Endpoint(m.Name); he != nil {
+							herrs = he.HTTPErrors
+						}
+					}
+					errs = append(errs, buildError(code, svc, m, e, herrs))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// buildError returns the catalog data of the given error. m is nil if the
+// error is defined by the service.
+func buildError(code string, svc *goaexpr.ServiceExpr, m *goaexpr.MethodExpr, e *goaexpr.ErrorExpr, herrs []*goaexpr.HTTPErrorExpr) *errorData {
+	name := "Code" + codegen.Goify(svc.Name, true)
+	data := &errorData{
+		Code:        code,
+		Name:        e.Name,
+		Service:     svc.Name,
+		Description: e.Description,
+	}
+	if m != nil {
+		name += codegen.Goify(m.Name, true)
+		data.Method = m.Name
+	}
+	data.Const = name + codegen.Goify(e.Name, true)
+	_, data.Temporary = e.Meta["goa:error:temporary"]
+	_, data.Timeout = e.Meta["goa:error:timeout"]
+	_, data.Fault = e.Meta["goa:error:fault"]
+	for _, he := range herrs {
+		if he.Name == e.Name {
+			data.Status = he.Response.StatusCode
+			break
+		}
+	}
+	return data
+}
+
+// registryFile returns the file defining the error code constants and the
+// error registry.
+func registryFile(errs []*errorData) *codegen.File {
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "errorcatalog", "errorcatalog.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header("Error catalog", "errorcatalog", nil),
+			{Name: "errorcatalog-registry", Source: registryT, Data: errs},
+		},
+	}
+}
+
+// jsonFile returns the errors.json file listing the errors of the catalog.
+func jsonFile(errs []*errorData) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "errorcatalog", "errors.json")
+	return stream.File(path, "errorcatalog-json", errs, stream.IndentedJSON)
+}
+
+// markdownFile returns the Markdown error reference.
+func markdownFile(errs []*errorData) *codegen.File {
+	var svcs []*serviceData
+	for _, e := range errs {
+		if len(svcs) == 0 || svcs[len(svcs)-1].Name != e.Service {
+			svcs = append(svcs, &serviceData{Name: e.Service})
+		}
+		svc := svcs[len(svcs)-1]
+		svc.Errors = append(svc.Errors, e)
+	}
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "errorcatalog", "errors.md"),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:    "errorcatalog-markdown",
+			FuncMap: template.FuncMap{"flags": flags, "status": status},
+			Source:  markdownT,
+			Data:    svcs,
+		}},
+	}
+}
+
+// encodeCodes sets the error code header in the HTTP error encoders if f is
+// a HTTP server encode_decode.go file.
+func encodeCodes(f *codegen.File, genpkg string, errs []*errorData) {
+	if filepath.Base(f.Path) != "encode_decode.go" {
+		return
+	}
+	for _, s := range f.Section("error-encoder") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok {
+			continue
+		}
+		var conds []string
+		for _, g := range ed.Errors {
+			for _, er := range g.Errors {
+				if e := lookup(errs, ed.ServiceName, ed.Method.Name, er.Name); e != nil {
+					conds = append(conds, fmt.Sprintf("if eq .Name %q }}\n\t\t\tw.Header().Set(%q, errorcatalog.%s)",
+						er.Name, header, e.Const))
+				}
+			}
+		}
+		if len(conds) == 0 {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Path: genpkg + "/errorcatalog"})
+		set := "{{- " + strings.Join(conds, "\n\t\t{{- else ") + "\n\t\t{{- end }}"
+		s.Source = strings.Replace(s.Source, "res := v.({{ $err.Ref }})",
+			"res := v.({{ $err.Ref }})\n\t\t"+set, 1)
+	}
+}
+
+// documentCodes documents the error code header of the error responses if f
+// is an OpenAPI file.
+func documentCodes(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if config.EnabledOperation("errorcatalog", op) {
+				documentOperation(op)
+			}
+			return nil
+		})
+	})
+}
+
+// documentOperation adds the error code header to the error responses of the
+// given operation. The JSON and YAML OpenAPI files share the same
+// specification so the operations may be visited twice.
+func documentOperation(op *openapi.Operation) {
+	e := walk.OperationEndpoint(goaexpr.Root, op)
+	if e == nil {
+		return
+	}
+	for _, he := range e.HTTPErrors {
+		erro := e.MethodExpr.Error(he.Name)
+		if erro == nil {
+			continue
+		}
+		code := expr.Code(erro)
+		if code == "" {
+			continue
+		}
+		resp, ok := op.Responses[strconv.Itoa(he.Response.StatusCode)]
+		if !ok {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]*openapi.Header)
+		}
+		h, ok := resp.Headers[header]
+		if !ok {
+			h = &openapi.Header{Description: "Stable code of the error.", Type: "string"}
+			resp.Headers[header] = h
+		}
+		var found bool
+		for _, v := range h.Enum {
+			if v == code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			h.Enum = append(h.Enum, code)
+		}
+	}
+}
+
+// lookup returns the catalog error returned by the given method with the given
+// name, nil if there is none. Method errors take precedence over the service
+// errors with the same name.
+func lookup(errs []*errorData, svc, method, name string) *errorData {
+	var found *errorData
+	for _, e := range errs {
+		if e.Service != svc || e.Name != name {
+			continue
+		}
+		if e.Method == method {
+			return e
+		}
+		if e.Method == "" {
+			found = e
+		}
+	}
+	return found
+}
+
+// flags returns the comma separated list of the error qualifiers.
+func flags(e *errorData) string {
+	var fs []string
+	if e.Temporary {
+		fs = append(fs, "temporary")
+	}
+	if e.Timeout {
+		fs = append(fs, "timeout")
+	}
+	if e.Fault {
+		fs = append(fs, "fault")
+	}
+	return strings.Join(fs, ", ")
+}
+
+// status returns the HTTP status code of the error response, the empty string
+// if the error is not mapped to a HTTP response.
+func status(e *errorData) string {
+	if e.Status == 0 {
+		return ""
+	}
+	return strconv.Itoa(e.Status)
+}
+
+// input: []*errorData
+const registryT = `// Error codes
+const (
+{{- range . }}
+	{{- if .Method }}
+	{{ printf "%s is the code of the %q error of the %q method of the %q service." .Const .Name .Method .Service | comment }}
+	{{- else }}
+	{{ printf "%s is the code of the %q error of the %q service." .Const .Name .Service | comment }}
+	{{- end }}
+	{{ .Const }} = {{ printf "%q" .Code }}
+{{- end }}
+)
+
+// Error describes an error of the catalog.
+type Error struct {
+	// Code is the stable error code.
+	Code string
+	// Name is the name of the error in the design.
+	Name string
+	// Service is the name of the service returning the error.
+	Service string
+	// Method is the name of the method returning the error, empty if the
+	// error is defined by the service.
+	Method string
+	// Description is the error description.
+	Description string
+	// Status is the HTTP status code of the error response, 0 if the
+	// error is not mapped to a HTTP response.
+	Status int
+	// Temporary is true if the error is temporary.
+	Temporary bool
+	// Timeout is true if the error is a timeout.
+	Timeout bool
+	// Fault is true if the error is a server-side fault.
+	Fault bool
+}
+
+// Errors is the error registry indexed by code.
+var Errors = map[string]*Error{
+{{- range . }}
+	{{ .Const }}: {
+		Code:    {{ .Const }},
+		Name:    {{ printf "%q" .Name }},
+		Service: {{ printf "%q" .Service }},
+		{{- if .Method }}
+		Method:  {{ printf "%q" .Method }},
+		{{- end }}
+		{{- if .Description }}
+		Description: {{ printf "%q" .Description }},
+		{{- end }}
+		{{- if .Status }}
+		Status:  {{ .Status }},
+		{{- end }}
+		{{- if .Temporary }}
+		Temporary: true,
+		{{- end }}
+		{{- if .Timeout }}
+		Timeout: true,
+		{{- end }}
+		{{- if .Fault }}
+		Fault:   true,
+		{{- end }}
+	},
+{{- end }}
+}
+
+// Lookup returns the error with the given code, nil if there is none.
+func Lookup(code string) *Error {
+	return Errors[code]
+}
+`
+
+// input: []*serviceData
+const markdownT = `# Error Reference
+{{ range . }}
+## {{ .Name }}
+
+| Code | Error | Method | HTTP Status | Qualifiers | Description |
+|------|-------|--------|-------------|------------|-------------|
+{{- range .Errors }}
+| ` + "`{{ .Code }}`" + ` | {{ .Name }} | {{ .Method }} | {{ status . }} | {{ flags . }} | {{ .Description }} |
+{{- end }}
+{{ end -}}
+`
@@ -0,0 +1,93 @@
+package errorcatalog_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/errorcatalog"
+	"goa.design/plugins/v3/errorcatalog/expr"
+	"goa.design/plugins/v3/errorcatalog/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Codes = nil
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	fs := httpcodegen.ServerFiles("gen", goaexpr.Root)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = errorcatalog.Generate("gen", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := fs[len(fs)-3:]
+	for _, f := range generated {
+		var content string
+		switch filepath.Ext(f.Path) {
+		case ".go":
+			content = codegen.SectionCode(t, f.SectionTemplates[1])
+		case ".json":
+			content = plugintest.Render(t, f)
+		default:
+			var buf bytes.Buffer
+			if err := f.SectionTemplates[0].Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			content = buf.String()
+		}
+		plugintest.Golden(t, filepath.Base(f.Path)+".golden", content)
+	}
+	var encoders int
+	for _, f := range fs {
+		if filepath.Base(f.Path) != "encode_decode.go" {
+			continue
+		}
+		for _, s := range f.Section("error-encoder") {
+			ed := s.Data.(*httpcodegen.EndpointData)
+			if ed.ServiceName != "Orders" || ed.Method.Name != "Create" {
+				continue
+			}
+			encoders++
+			code := codegen.SectionCode(t, s)
+			if code != testdata.CreateErrorEncoderCode {
+				t.Errorf("invalid error encoder code, got:\n%s\ngot vs. expected:\n%s",
+					code, codegen.Diff(t, code, testdata.CreateErrorEncoderCode))
+			}
+		}
+	}
+	if encoders != 1 {
+		t.Errorf("got %d error encoders for Orders Create, expected 1", encoders)
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	resp := spec.Paths["/orders"].(*openapi.Path).Post.Responses["409"]
+	h, ok := resp.Headers["X-Error-Code"]
+	if !ok {
+		t.Fatal("X-Error-Code header not found in OpenAPI spec")
+	}
+	if len(h.Enum) != 1 || h.Enum[0] != "ORD-409-001" {
+		t.Errorf("got X-Error-Code enum %v, expected [ORD-409-001]", h.Enum)
+	}
+	if _, ok := spec.Paths["/orders"].(*openapi.Path).Post.Responses["400"].Headers["X-Error-Code"]; ok {
+		t.Error("X-Error-Code header found in response of error without code")
+	}
+}
+
+func TestGenerateNoCode(t *testing.T) {
+	expr.Root.Codes = nil
+	httpcodegen.RunHTTPDSL(t, testdata.NoCodeDSL)
+	fs, err := errorcatalog.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 0 {
+		t.Errorf("got %d files, expected 0", len(fs))
+	}
+}
@@ -0,0 +1,41 @@
+package testdata
+
+var CreateErrorEncoderCode = `// EncodeCreateError returns an encoder for errors returned by the Create
+// Orders endpoint.
+func EncodeCreateError(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, error) error {
+	encodeError := goahttp.ErrorEncoder(encoder)
+	return func(ctx context.Context, w http.ResponseWriter, v error) error {
+		en, ok := v.(ErrorNamer)
+		if !ok {
+			return encodeError(ctx, w, v)
+		}
+		switch en.ErrorName() {
+		case "conflict":
+			res := v.(*goa.ServiceError)
+			w.Header().Set("X-Error-Code", errorcatalog.CodeOrdersCreateConflict)
+			enc := encoder(ctx, w)
+			body := NewCreateConflictResponseBody(res)
+			w.Header().Set("goa-error", "conflict")
+			w.WriteHeader(http.StatusConflict)
+			return enc.Encode(body)
+		case "invalid":
+			res := v.(*goa.ServiceError)
+			enc := encoder(ctx, w)
+			body := NewCreateInvalidResponseBody(res)
+			w.Header().Set("goa-error", "invalid")
+			w.WriteHeader(http.StatusBadRequest)
+			return enc.Encode(body)
+		case "not_found":
+			res := v.(*goa.ServiceError)
+			w.Header().Set("X-Error-Code", errorcatalog.CodeOrdersNotFound)
+			enc := encoder(ctx, w)
+			body := NewCreateNotFoundResponseBody(res)
+			w.Header().Set("goa-error", "not_found")
+			w.WriteHeader(http.StatusNotFound)
+			return enc.Encode(body)
+		default:
+			return encodeError(ctx, w, v)
+		}
+	}
+}
+`
@@ -0,0 +1,69 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	errorcatalog "goa.design/plugins/v3/errorcatalog/dsl"
+)
+
+var CatalogDSL = func() {
+	Service("Orders", func() {
+		Error("not_found", func() {
+			Description("Order not found.")
+			errorcatalog.ErrorCode("ORD-404-001")
+		})
+		HTTP(func() {
+			Response("not_found", StatusNotFound)
+		})
+		Method("Show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			HTTP(func() {
+				GET("/orders/{id}")
+			})
+		})
+		Method("Create", func() {
+			Error("conflict", func() {
+				Description("Order already exists.")
+				Temporary()
+				errorcatalog.ErrorCode("ORD-409-001")
+			})
+			Error("invalid", func() {
+				Description("Invalid order.")
+			})
+			Payload(func() {
+				Attribute("id", String)
+			})
+			HTTP(func() {
+				POST("/orders")
+				Response("conflict", StatusConflict)
+				Response("invalid", StatusBadRequest)
+			})
+		})
+	})
+	Service("Payments", func() {
+		Method("Pay", func() {
+			Error("declined", func() {
+				Description("Payment declined by the issuer.")
+				Fault()
+				errorcatalog.ErrorCode("PAY-502-001")
+			})
+			HTTP(func() {
+				POST("/payments")
+				Response("declined", StatusBadGateway)
+			})
+		})
+	})
+}
+
+var NoCodeDSL = func() {
+	Service("Orders", func() {
+		Error("not_found")
+		Method("Show", func() {
+			HTTP(func() {
+				GET("/orders")
+				Response("not_found", StatusNotFound)
+			})
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/etag/expr"
+
+	// Register code generators for the etag plugin
+	_ "goa.design/plugins/v3/etag"
+)
+
+// Cacheable marks the method results as cacheable. The HTTP responses to the
+// method GET requests carry an ETag header computed from the response body
+// and optionally a Last-Modified header. Conditional requests using the
+// If-None-Match or If-Modified-Since headers are answered with 304 Not
+// Modified when the validators match.
+//
+// Cacheable must appear in a Method expression.
+//
+// Cacheable accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import etag "goa.design/plugins/v3/etag/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        Method("show", func() {
+//            etag.Cacheable(func() {
+//                etag.Weak()
+//                etag.LastModified("updated_at")
+//            })
+//            Payload(func() {
+//                Attribute("id", String)
+//            })
+//            Result(Item)
+//            HTTP(func() {
+//                GET("/items/{id}")
+//            })
+//        })
+//    })
+//
+func Cacheable(args ...interface{}) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(args) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	c := &expr.CacheableExpr{Method: m}
+	if len(args) == 1 {
+		dsl, ok := args[0].(func())
+		if !ok {
+			eval.InvalidArgError("function", args[0])
+			return
+		}
+		if !eval.Execute(dsl, c) {
+			return
+		}
+	}
+	expr.Root.Cacheables = append(expr.Root.Cacheables, c)
+}
+
+// Weak makes the generated ETags weak validators, use it when responses that
+// are semantically equivalent may differ byte for byte.
+//
+// Weak must appear in a Cacheable expression.
+//
+// Example:
+//
+//     Cacheable(func() {
+//         Weak()
+//     })
+//
+func Weak() {
+	switch c := eval.Current().(type) {
+	case *expr.CacheableExpr:
+		c.Weak = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// LastModified sets the name of the result attribute holding the last
+// modification time of the resource. The attribute value is used to set the
+// Last-Modified response header and to handle If-Modified-Since requests. The
+// attribute must be a string using the date-time format.
+//
+// LastModified must appear in a Cacheable expression.
+//
+// Example:
+//
+//     Cacheable(func() {
+//         LastModified("updated_at")
+//     })
+//
+func LastModified(name string) {
+	switch c := eval.Current().(type) {
+	case *expr.CacheableExpr:
+		c.LastModified = name
+	default:
+		eval.IncompatibleDSL()
+	}
+}
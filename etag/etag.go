@@ -0,0 +1,129 @@
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Handler returns a HTTP handler which sets the ETag header of the successful
+// responses to GET and HEAD requests made to h and responds with 304 Not
+// Modified instead when the request validators match. The ETag is computed
+// from the response body unless h sets the header explicitly.
+func Handler(h http.Handler, weak bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if rec.status != http.StatusOK {
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+			return
+		}
+		if w.Header().Get("ETag") == "" {
+			w.Header().Set("ETag", Compute(rec.body.Bytes(), weak))
+		}
+		if NotModified(r, w.Header().Get("ETag"), w.Header().Get("Last-Modified")) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// Compute returns the ETag of the given response body.
+func Compute(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	tag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	if weak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// NotModified returns true if the request validators match the given ETag
+// and Last-Modified response header values. If-Modified-Since is ignored when
+// the request specifies If-None-Match as mandated by RFC 7232.
+func NotModified(r *http.Request, etag, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return MatchETag(inm, etag)
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// MatchETag returns true if the given If-None-Match header value matches the
+// ETag using the weak comparison function defined in RFC 7232.
+func MatchETag(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLastModified sets the Last-Modified response header from the given
+// RFC 3339 date time value. v must be a string or a pointer to a string, the
+// header is not set if v is nil or invalid.
+func SetLastModified(w http.ResponseWriter, v interface{}) {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case *string:
+		if val == nil {
+			return
+		}
+		s = *val
+	default:
+		return
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return
+	}
+	w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// recorder buffers the response body and status code so that the ETag can be
+// computed before the response is written.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code.
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// Write buffers the response body.
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
@@ -0,0 +1,75 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchETag(t *testing.T) {
+	cases := []struct {
+		ifNoneMatch string
+		etag        string
+		output      bool
+	}{
+		{`"abc"`, `"abc"`, true},
+		{`"abc"`, `"def"`, false},
+		{`"def", "abc"`, `"abc"`, true},
+		{`W/"abc"`, `"abc"`, true},
+		{`"abc"`, `W/"abc"`, true},
+		{`*`, `"abc"`, true},
+		{`*`, ``, false},
+	}
+	for _, c := range cases {
+		if output := MatchETag(c.ifNoneMatch, c.etag); output != c.output {
+			t.Errorf("MatchETag(%q, %q): got %t, expected %t", c.ifNoneMatch, c.etag, output, c.output)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetLastModified(w, "2006-01-02T15:04:05Z")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name":"item"}`))
+	})
+	tag := Compute([]byte(`{"name":"item"}`), false)
+	cases := []struct {
+		Name    string
+		Method  string
+		Headers map[string]string
+		Status  int
+	}{
+		{"no-validator", "GET", nil, http.StatusOK},
+		{"matching-etag", "GET", map[string]string{"If-None-Match": tag}, http.StatusNotModified},
+		{"other-etag", "GET", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"not-modified-since", "GET", map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified},
+		{"modified-since", "GET", map[string]string{"If-Modified-Since": "Sun, 01 Jan 2006 15:04:05 GMT"}, http.StatusOK},
+		{"etag-precedence", "GET", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": lastModified}, http.StatusOK},
+		{"not-get", "POST", map[string]string{"If-None-Match": tag}, http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r := httptest.NewRequest(c.Method, "/", nil)
+			for k, v := range c.Headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			Handler(h, false).ServeHTTP(w, r)
+			if w.Code != c.Status {
+				t.Errorf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if c.Method == "GET" && w.Header().Get("ETag") != tag {
+				t.Errorf("got ETag %q, expected %q", w.Header().Get("ETag"), tag)
+			}
+			if w.Header().Get("Last-Modified") != lastModified {
+				t.Errorf("got Last-Modified %q, expected %q", w.Header().Get("Last-Modified"), lastModified)
+			}
+			if c.Status == http.StatusNotModified && w.Body.Len() > 0 {
+				t.Errorf("got body %q for not modified response", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// CacheableExpr describes a method whose HTTP responses carry
+	// validators used to serve conditional requests.
+	CacheableExpr struct {
+		// Weak is true if the generated ETags are weak validators.
+		Weak bool
+		// LastModified is the name of the result attribute holding the
+		// last modification time of the resource if any.
+		LastModified string
+		// Method is the cacheable method.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (c *CacheableExpr) EvalName() string {
+	return fmt.Sprintf("Cacheable of %s", c.Method.EvalName())
+}
+
+// Validate ensures the cacheable expression is valid.
+func (c *CacheableExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if c.Method.IsStreaming() {
+		verr.Add(c, "streaming methods cannot be cacheable")
+	}
+	if c.Method.Result.Type == expr.Empty {
+		verr.Add(c, "method must define a result to be cacheable")
+	}
+	if c.LastModified != "" {
+		if att := c.Method.Result.Find(c.LastModified); att == nil {
+			verr.Add(c, "last modified attribute %q not found in result", c.LastModified)
+		} else if att.Type != expr.String {
+			verr.Add(c, "last modified attribute %q must be a string", c.LastModified)
+		} else if att.Validation == nil || att.Validation.Format != expr.FormatDateTime {
+			verr.Add(c, "last modified attribute %q must use the %s format", c.LastModified, expr.FormatDateTime)
+		}
+	}
+	for _, svc := range expr.Root.API.HTTP.Services {
+		for _, e := range svc.HTTPEndpoints {
+			if e.MethodExpr != c.Method {
+				continue
+			}
+			var get bool
+			for _, r := range e.Routes {
+				if r.Method == "GET" {
+					get = true
+				}
+			}
+			if !get {
+				verr.Add(c, "HTTP endpoint must define a GET route to be cacheable")
+			}
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the cacheable methods defined in the design.
+	RootExpr struct {
+		// Cacheables lists the cacheable definitions in the order they
+		// appear in the design.
+		Cacheables []*CacheableExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "ETag plugin"
+}
+
+// WalkSets iterates over the cacheable method definitions.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	cexps := make(eval.ExpressionSet, len(r.Cacheables))
+	for i, c := range r.Cacheables {
+		cexps[i] = c
+	}
+	walk(cexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/etag/dsl"}
+}
+
+// Cacheable returns the cacheable definition of the given method, nil if
+// the method is not cacheable.
+func (r *RootExpr) Cacheable(svc, method string) *CacheableExpr {
+	for _, c := range r.Cacheables {
+		if c.Method.Service.Name == svc && c.Method.Name == method {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package etag
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/etag/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "etag",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate wraps the HTTP handlers of the cacheable endpoints with the
+// conditional request handler, sets the Last-Modified header in the response
+// encoders and documents the validator headers in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Cacheables) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("etag", f) {
+			continue
+		}
+		serverETag(f)
+		documentETag(f)
+	}
+	return files, nil
+}
+
+// serverETag modifies the HTTP server mount functions and response encoders
+// of the cacheable endpoints.
+func serverETag(f *codegen.File) {
+	base := filepath.Base(f.Path)
+	if base != "server.go" && base != "encode_decode.go" {
+		return
+	}
+	for _, s := range f.SectionTemplates {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok {
+			continue
+		}
+		c := expr.Root.Cacheable(ed.ServiceName, ed.Method.Name)
+		if c == nil {
+			continue
+		}
+		switch s.Name {
+		case "server-handler":
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: "goa.design/plugins/v3/etag"})
+			s.Source = strings.Replace(s.Source, "f, ok := h.(http.HandlerFunc)",
+				fmt.Sprintf("h = etag.Handler(h, %t)\n\tf, ok := h.(http.HandlerFunc)", c.Weak), 1)
+		case "response-encoder":
+			if c.LastModified == "" {
+				continue
+			}
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: "goa.design/plugins/v3/etag"})
+			set := fmt.Sprintf("etag.SetLastModified(w, res.{{ if .Method.ViewedResult }}Projected.{{ end }}%s)",
+				codegen.Goify(c.LastModified, true))
+			s.Source = strings.Replace(s.Source, "{{- range .Result.Responses }}",
+				set+"\n\t\t{{- range .Result.Responses }}", 1)
+		}
+	}
+}
+
+// documentETag documents the conditional request headers and the 304
+// response of the cacheable operations if f is an OpenAPI file.
+func documentETag(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, method string, op *openapi.Operation) error {
+			if method != "get" {
+				return nil
+			}
+			c := cacheable(op)
+			if c == nil {
+				return nil
+			}
+			addParameter(op, &openapi.Parameter{
+				Name:        "If-None-Match",
+				In:          "header",
+				Description: "ETags of the representations cached by the client.",
+				Type:        "string",
+			})
+			headers := map[string]*openapi.Header{
+				"ETag": {Description: "Validator of the returned representation.", Type: "string"},
+			}
+			if c.LastModified != "" {
+				addParameter(op, &openapi.Parameter{
+					Name:        "If-Modified-Since",
+					In:          "header",
+					Description: "Last modification time of the representation cached by the client.",
+					Type:        "string",
+				})
+				headers["Last-Modified"] = &openapi.Header{Description: "Last modification time of the returned representation.", Type: "string"}
+			}
+			if resp, ok := op.Responses["200"]; ok {
+				if resp.Headers == nil {
+					resp.Headers = make(map[string]*openapi.Header)
+				}
+				for n, h := range headers {
+					resp.Headers[n] = h
+				}
+			}
+			op.Responses["304"] = &openapi.Response{
+				Description: "Not Modified response.",
+				Headers:     headers,
+			}
+			return nil
+		})
+	})
+}
+
+// addParameter adds the parameter to the operation unless it is already
+// defined. The JSON and YAML OpenAPI files share the same specification so the
+// operations may be visited twice.
+func addParameter(op *openapi.Operation, param *openapi.Parameter) {
+	for _, p := range op.Parameters {
+		if p.Name == param.Name && p.In == param.In {
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, param)
+}
+
+// cacheable returns the cacheable definition corresponding to the given
+// operation, nil if the operation is not cacheable.
+func cacheable(op *openapi.Operation) *expr.CacheableExpr {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	for _, c := range expr.Root.Cacheables {
+		if m != nil && c.Method == m {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package etag_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/etag"
+	"goa.design/plugins/v3/etag/expr"
+	"goa.design/plugins/v3/etag/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name        string
+		DSL         func()
+		MountCode   string
+		EncoderCode string
+		Headers     []string
+	}{
+		{"strong", testdata.StrongDSL, testdata.StrongMountCode, testdata.StrongResponseEncoderCode, []string{"If-None-Match"}},
+		{"weak-last-modified", testdata.WeakLastModifiedDSL, testdata.WeakLastModifiedMountCode, testdata.WeakLastModifiedResponseEncoderCode, []string{"If-None-Match", "If-Modified-Since"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Cacheables = nil
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs := httpcodegen.ServerFiles("", goaexpr.Root)
+			ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err = etag.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range fs {
+				var name, expected string
+				switch filepath.Base(f.Path) {
+				case "server.go":
+					name, expected = "server-handler", c.MountCode
+				case "encode_decode.go":
+					name, expected = "response-encoder", c.EncoderCode
+				default:
+					continue
+				}
+				sections := f.Section(name)
+				if len(sections) != 1 {
+					t.Fatalf("got %d %s sections, expected 1", len(sections), name)
+				}
+				code := codegen.SectionCode(t, sections[0])
+				if code != expected {
+					t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, expected))
+				}
+			}
+			spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+			op := spec.Paths["/items/{id}"].(*openapi.Path).Get
+			for _, h := range c.Headers {
+				var count int
+				for _, p := range op.Parameters {
+					if p.Name == h && p.In == "header" {
+						count++
+					}
+				}
+				if count != 1 {
+					t.Errorf("got %d header parameters %q in OpenAPI spec, expected 1", count, h)
+				}
+			}
+			if _, ok := op.Responses["304"]; !ok {
+				t.Error("304 response not found in OpenAPI spec")
+			}
+			if _, ok := op.Responses["200"].Headers["ETag"]; !ok {
+				t.Error("ETag header not found in OpenAPI spec")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package testdata
+
+const StrongMountCode = `// MountShowHandler configures the mux to serve the "Catalog" service "Show"
+// endpoint.
+func MountShowHandler(mux goahttp.Muxer, h http.Handler) {
+	h = etag.Handler(h, false)
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/items/{id}", f)
+}
+`
+
+const StrongResponseEncoderCode = `// EncodeShowResponse returns an encoder for responses returned by the Catalog
+// Show endpoint.
+func EncodeShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		res := v.(*catalog.ShowResult)
+		enc := encoder(ctx, w)
+		body := NewShowResponseBody(res)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+`
+
+const WeakLastModifiedMountCode = `// MountShowHandler configures the mux to serve the "Catalog" service "Show"
+// endpoint.
+func MountShowHandler(mux goahttp.Muxer, h http.Handler) {
+	h = etag.Handler(h, true)
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/items/{id}", f)
+}
+`
+
+const WeakLastModifiedResponseEncoderCode = `// EncodeShowResponse returns an encoder for responses returned by the Catalog
+// Show endpoint.
+func EncodeShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		res := v.(*catalog.ShowResult)
+		etag.SetLastModified(w, res.UpdatedAt)
+		enc := encoder(ctx, w)
+		body := NewShowResponseBody(res)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+`
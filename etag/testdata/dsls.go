@@ -0,0 +1,46 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	etag "goa.design/plugins/v3/etag/dsl"
+)
+
+var StrongDSL = func() {
+	Service("Catalog", func() {
+		Method("Show", func() {
+			etag.Cacheable()
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(func() {
+				Attribute("name", String)
+			})
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+	})
+}
+
+var WeakLastModifiedDSL = func() {
+	Service("Catalog", func() {
+		Method("Show", func() {
+			etag.Cacheable(func() {
+				etag.Weak()
+				etag.LastModified("updated_at")
+			})
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(func() {
+				Attribute("name", String)
+				Attribute("updated_at", String, func() {
+					Format(FormatDateTime)
+				})
+			})
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/export/expr"
+
+	// Register code generators for the export plugin
+	_ "goa.design/plugins/v3/export"
+)
+
+// CSV makes the HTTP endpoint of the enclosing method write its result items
+// as CSV rows when the first media type of the request Accept header is
+// text/csv. The method must either return an array of objects or stream its
+// results, the attributes of the objects must be primitives. The columns are
+// named after the attributes.
+//
+// The generated HTTP handlers write and flush the rows one at a time so that
+// large exports are sent to the clients as they are produced.
+//
+// CSV must appear in a Method expression.
+//
+// Example:
+//
+//    import export "goa.design/plugins/v3/export/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        Method("list", func() {
+//            export.CSV()
+//            Result(ArrayOf(Item))
+//            HTTP(func() {
+//                GET("/items")
+//                Response(StatusOK)
+//            })
+//        })
+//    })
+//
+func CSV() {
+	addMediaType(expr.CSVMediaType)
+}
+
+// NDJSON makes the HTTP endpoint of the enclosing method write its result
+// items as newline delimited JSON when the first media type of the request
+// Accept header is application/x-ndjson. The method must either return an
+// array of objects or stream its results. Each line is the JSON encoding of
+// an item as it appears in the regular responses.
+//
+// NDJSON must appear in a Method expression.
+//
+// Example:
+//
+//    import export "goa.design/plugins/v3/export/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        Method("watch", func() {
+//            export.NDJSON()
+//            StreamingResult(Item)
+//            HTTP(func() {
+//                GET("/items/watch")
+//            })
+//        })
+//    })
+//
+func NDJSON() {
+	addMediaType(expr.NDJSONMediaType)
+}
+
+// addMediaType adds the given media type to the export of the current method.
+func addMediaType(mt string) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	e := expr.Root.MethodExport(m)
+	if !e.Has(mt) {
+		e.MediaTypes = append(e.MediaTypes, mt)
+	}
+}
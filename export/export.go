@@ -0,0 +1,208 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CSVMediaType is the media type of the CSV exports.
+	CSVMediaType = "text/csv"
+	// NDJSONMediaType is the media type of the newline delimited JSON
+	// exports.
+	NDJSONMediaType = "application/x-ndjson"
+)
+
+type (
+	// Writer writes the items of an export to the HTTP response one at a
+	// time and flushes them so that the clients receive the items as they
+	// are produced.
+	Writer interface {
+		// Write writes the given item.
+		Write(item interface{}) error
+		// Close writes the response headers if no item was written and
+		// flushes the response.
+		Close() error
+		// Fail returns err if no item was written so that it may be
+		// encoded as an error response, a *StreamError wrapping err
+		// otherwise.
+		Fail(err error) error
+	}
+
+	// StreamError is the error returned by the writers once the response
+	// headers have been written. Such errors cannot be written to the
+	// response anymore.
+	StreamError struct {
+		// Err is the underlying error.
+		Err error
+	}
+
+	// writer implements the behavior shared by the CSV and NDJSON writers.
+	writer struct {
+		w       http.ResponseWriter
+		mt      string
+		status  int
+		started bool
+	}
+
+	// csvWriter writes the items as CSV rows.
+	csvWriter struct {
+		*writer
+		csv     *csv.Writer
+		columns []string
+		row     func(interface{}) []string
+	}
+
+	// ndjsonWriter writes the items as JSON lines.
+	ndjsonWriter struct {
+		*writer
+		enc *json.Encoder
+	}
+)
+
+// Negotiate returns the first media type of the request Accept header if it
+// is one of mts, the empty string otherwise.
+func Negotiate(r *http.Request, mts ...string) string {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return ""
+	}
+	first := strings.Split(accept, ",")[0]
+	if mt, _, err := mime.ParseMediaType(first); err == nil {
+		first = mt
+	}
+	for _, mt := range mts {
+		if mt == strings.TrimSpace(first) {
+			return mt
+		}
+	}
+	return ""
+}
+
+// NewWriter returns a writer writing the items with the media type mt and the
+// given response status code. columns lists the CSV header and row returns
+// the CSV row of an item, both are only used for CSV exports.
+func NewWriter(w http.ResponseWriter, mt string, status int, columns []string, row func(interface{}) []string) Writer {
+	ew := &writer{w: w, mt: mt, status: status}
+	if mt == CSVMediaType {
+		return &csvWriter{writer: ew, csv: csv.NewWriter(w), columns: columns, row: row}
+	}
+	return &ndjsonWriter{writer: ew, enc: json.NewEncoder(w)}
+}
+
+// Format returns the CSV representation of the given field value. Nil
+// pointers are represented with an empty string.
+func Format(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return ""
+	case reflect.String:
+		return rv.String()
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// Error returns the underlying error message.
+func (e *StreamError) Error() string {
+	return e.Err.Error()
+}
+
+// Write writes the CSV header before the first row and the row of the given
+// item.
+func (w *csvWriter) Write(item interface{}) error {
+	if !w.started {
+		w.start()
+		if err := w.csv.Write(w.columns); err != nil {
+			return w.Fail(err)
+		}
+	}
+	if err := w.csv.Write(w.row(item)); err != nil {
+		return w.Fail(err)
+	}
+	return w.flush()
+}
+
+// Close writes the CSV header if no row was written and flushes the response.
+func (w *csvWriter) Close() error {
+	if !w.started {
+		w.start()
+		if err := w.csv.Write(w.columns); err != nil {
+			return w.Fail(err)
+		}
+	}
+	return w.flush()
+}
+
+// flush flushes the CSV writer and the response.
+func (w *csvWriter) flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return w.Fail(err)
+	}
+	w.writer.flush()
+	return nil
+}
+
+// Write writes the JSON encoding of the item followed by a newline.
+func (w *ndjsonWriter) Write(item interface{}) error {
+	w.start()
+	if err := w.enc.Encode(item); err != nil {
+		return w.Fail(err)
+	}
+	w.flush()
+	return nil
+}
+
+// Close writes the response headers if no item was written and flushes the
+// response.
+func (w *ndjsonWriter) Close() error {
+	w.start()
+	w.flush()
+	return nil
+}
+
+// Fail returns err if the response headers have not been written, a
+// *StreamError wrapping err otherwise.
+func (w *writer) Fail(err error) error {
+	if !w.started {
+		return err
+	}
+	if _, ok := err.(*StreamError); ok {
+		return err
+	}
+	return &StreamError{Err: err}
+}
+
+// start writes the response headers once.
+func (w *writer) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.w.Header().Set("Content-Type", w.mt)
+	w.w.WriteHeader(w.status)
+}
+
+// flush sends the data written so far to the client.
+func (w *writer) flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
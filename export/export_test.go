@@ -0,0 +1,88 @@
+package export
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type item struct {
+	ID    string   `json:"id"`
+	Price *float64 `json:"price,omitempty"`
+}
+
+func itemRow(v interface{}) []string {
+	i := v.(*item)
+	return []string{Format(i.ID), Format(i.Price)}
+}
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"text/csv":                     CSVMediaType,
+		"text/csv; charset=utf-8, */*": CSVMediaType,
+		"application/x-ndjson":         NDJSONMediaType,
+		"application/json, text/csv":   "",
+		"":                             "",
+	}
+	for accept, expected := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", accept)
+		if actual := Negotiate(r, CSVMediaType, NDJSONMediaType); actual != expected {
+			t.Errorf("%q: got %q, expected %q", accept, actual, expected)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	price := 2.5
+	items := []*item{{ID: "a,b", Price: &price}, {ID: "c"}}
+	cases := map[string]struct {
+		MediaType string
+		Items     []*item
+		Expected  string
+	}{
+		"csv":          {CSVMediaType, items, "id,price\n\"a,b\",2.5\nc,\n"},
+		"csv empty":    {CSVMediaType, nil, "id,price\n"},
+		"ndjson":       {NDJSONMediaType, items, "{\"id\":\"a,b\",\"price\":2.5}\n{\"id\":\"c\"}\n"},
+		"ndjson empty": {NDJSONMediaType, nil, ""},
+	}
+	for k, c := range cases {
+		w := httptest.NewRecorder()
+		ew := NewWriter(w, c.MediaType, 201, []string{"id", "price"}, itemRow)
+		for _, i := range c.Items {
+			if err := ew.Write(i); err != nil {
+				t.Fatalf("%s: %s", k, err)
+			}
+		}
+		if err := ew.Close(); err != nil {
+			t.Fatalf("%s: %s", k, err)
+		}
+		if w.Code != 201 {
+			t.Errorf("%s: got status %d, expected 201", k, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != c.MediaType {
+			t.Errorf("%s: got Content-Type %q, expected %q", k, ct, c.MediaType)
+		}
+		if w.Body.String() != c.Expected {
+			t.Errorf("%s: got body %q, expected %q", k, w.Body.String(), c.Expected)
+		}
+		if !w.Flushed && len(c.Items) > 0 {
+			t.Errorf("%s: response not flushed", k)
+		}
+	}
+}
+
+func TestFail(t *testing.T) {
+	err := errors.New("boom")
+	ew := NewWriter(httptest.NewRecorder(), NDJSONMediaType, 200, nil, nil)
+	if actual := ew.Fail(err); actual != err {
+		t.Errorf("got %v before writing, expected the error unchanged", actual)
+	}
+	if err := ew.Write(&item{ID: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	se, ok := ew.Fail(err).(*StreamError)
+	if !ok || se.Err != err {
+		t.Errorf("got %v after writing, expected a stream error", ew.Fail(err))
+	}
+}
@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// CSVMediaType is the media type of the CSV exports.
+	CSVMediaType = "text/csv"
+	// NDJSONMediaType is the media type of the newline delimited JSON
+	// exports.
+	NDJSONMediaType = "application/x-ndjson"
+)
+
+type (
+	// ExportExpr describes the media types used to export the result items
+	// of a list or server streaming method one at a time.
+	ExportExpr struct {
+		// Method is the exported method.
+		Method *expr.MethodExpr
+		// MediaTypes lists the export media types in order of definition.
+		MediaTypes []string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (e *ExportExpr) EvalName() string {
+	return fmt.Sprintf("export of %s", e.Method.EvalName())
+}
+
+// Validate makes sure the method returns a list of objects or streams objects
+// and that the attributes of the objects are primitives if the items are
+// exported as CSV.
+func (e *ExportExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	item := e.Item()
+	switch {
+	case e.Method.Stream == expr.ClientStreamKind || e.Method.Stream == expr.BidirectionalStreamKind:
+		verr.Add(e, "exports are not supported by methods that stream payloads")
+	case item == nil:
+		verr.Add(e, "method result must be an array or the method must stream its results")
+	case expr.AsObject(item.Type) == nil:
+		verr.Add(e, "exported items must be objects")
+	default:
+		if _, ok := item.Type.(*expr.ResultTypeExpr); ok {
+			verr.Add(e, "exported items cannot be result types, use a user type instead")
+		}
+		if e.Has(CSVMediaType) {
+			for _, nat := range *expr.AsObject(item.Type) {
+				if !expr.IsPrimitive(nat.Attribute.Type) {
+					verr.Add(e, "CSV exports require primitive attributes but %q is a %s", nat.Name, nat.Attribute.Type.Name())
+				}
+			}
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Item returns the attribute describing the exported items: the element of
+// the result array or the streamed result. It returns nil if the method
+// neither returns an array nor streams its results.
+func (e *ExportExpr) Item() *expr.AttributeExpr {
+	if e.Method.Stream == expr.ServerStreamKind {
+		return e.Method.Result
+	}
+	if arr := expr.AsArray(e.Method.Result.Type); arr != nil {
+		return arr.ElemType
+	}
+	return nil
+}
+
+// Has returns true if the items may be exported with the given media type.
+func (e *ExportExpr) Has(mt string) bool {
+	for _, m := range e.MediaTypes {
+		if m == mt {
+			return true
+		}
+	}
+	return false
+}
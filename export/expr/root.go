@@ -0,0 +1,69 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the result exports defined in the design.
+	RootExpr struct {
+		// Exports lists the method exports.
+		Exports []*ExportExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "export plugin"
+}
+
+// WalkSets iterates over the exports.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	exps := make(eval.ExpressionSet, len(r.Exports))
+	for i, e := range r.Exports {
+		exps[i] = e
+	}
+	walk(exps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/export/dsl"}
+}
+
+// Export returns the export of the given method, nil if the method results
+// cannot be exported.
+func (r *RootExpr) Export(svc, method string) *ExportExpr {
+	for _, e := range r.Exports {
+		if e.Method.Service.Name == svc && e.Method.Name == method {
+			return e
+		}
+	}
+	return nil
+}
+
+// MethodExport returns the export of the given method creating it if needed.
+func (r *RootExpr) MethodExport(m *expr.MethodExpr) *ExportExpr {
+	if e := r.Export(m.Service.Name, m.Name); e != nil {
+		return e
+	}
+	e := &ExportExpr{Method: m}
+	r.Exports = append(r.Exports, e)
+	return e
+}
@@ -0,0 +1,323 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/export/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// exportData contains the data necessary to render the function serving the
+// export requests of an endpoint.
+type exportData struct {
+	// ServiceName is the name of the service.
+	ServiceName string
+	// MethodName is the name of the method.
+	MethodName string
+	// VarName is the Go name of the method.
+	VarName string
+	// MediaTypes lists the export media types.
+	MediaTypes []string
+	// StatusCode is the status code of the export responses.
+	StatusCode int
+	// Columns lists the names of the CSV columns, nil if the items are not
+	// exported as CSV.
+	Columns []string
+	// RowFunc is the name of the function returning the CSV row of an item.
+	RowFunc string
+	// Fields lists the Go names of the fields of the item body type
+	// corresponding to the CSV columns.
+	Fields []string
+	// ItemRef is the reference to the body type of the items.
+	ItemRef string
+	// ResultRef is the reference to the result type of list methods.
+	ResultRef string
+	// BodyInit is the name of the function building the body of the
+	// result of list methods or of the streamed items.
+	BodyInit string
+	// Stream contains the data specific to the server streaming methods,
+	// nil for list methods.
+	Stream *streamData
+}
+
+// streamData contains the data necessary to render the stream writing the
+// items sent by a server streaming method.
+type streamData struct {
+	// VarName is the name of the stream struct.
+	VarName string
+	// Interface is the server stream interface implemented by the struct.
+	Interface string
+	// EndpointStruct is the reference to the endpoint input struct.
+	EndpointStruct string
+	// SendName is the name of the send function.
+	SendName string
+	// SendTypeRef is the reference to the type of the sent items.
+	SendTypeRef string
+	// PayloadRef is the reference to the method payload type if any.
+	PayloadRef string
+}
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "export",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the functions serving the export requests of the HTTP
+// endpoints, makes the HTTP handlers call them and documents the export
+// media types in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Exports) == 0 {
+		return files, nil
+	}
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok {
+			continue
+		}
+		for _, f := range files {
+			if !config.EnabledFile("export", f) {
+				continue
+			}
+			if filepath.Base(f.Path) == "server.go" {
+				serverExport(f)
+			} else {
+				documentExport(f, r)
+			}
+		}
+		for _, svc := range r.API.HTTP.Services {
+			if !config.Enabled("export", svc.Name()) {
+				continue
+			}
+			if f := exportFile(genpkg, svc); f != nil {
+				files = append(files, f)
+			}
+		}
+	}
+	return files, nil
+}
+
+// exportFile returns the file defining the functions serving the export
+// requests of the given HTTP service, nil if the service exports nothing.
+func exportFile(genpkg string, svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	sd := httpcodegen.HTTPServices.Get(svc.Name())
+	var sections []*codegen.SectionTemplate
+	for _, ed := range sd.Endpoints {
+		if d := endpointExport(svc, ed); d != nil {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:   "export-serve",
+				Source: serveExportT,
+				Data:   d,
+			})
+		}
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	header := codegen.Header(svc.Name()+" HTTP server result exports", "server", []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "net/http"},
+		codegen.GoaImport(""),
+		{Path: "goa.design/plugins/v3/export"},
+		{Path: genpkg + "/" + codegen.SnakeCase(sd.Service.VarName), Name: sd.Service.PkgName},
+	})
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(sd.Service.VarName), "server", "export.go"),
+		SectionTemplates: append([]*codegen.SectionTemplate{header}, sections...),
+	}
+}
+
+// endpointExport returns the export data of the given endpoint, nil if the
+// endpoint exports nothing.
+func endpointExport(svc *goaexpr.HTTPServiceExpr, ed *httpcodegen.EndpointData) *exportData {
+	ex := expr.Root.Export(svc.Name(), ed.Method.Name)
+	e := svc.Endpoint(ed.Method.Name)
+	if ex == nil || e == nil {
+		return nil
+	}
+	d := &exportData{
+		ServiceName: svc.Name(),
+		MethodName:  ed.Method.Name,
+		VarName:     ed.Method.VarName,
+		MediaTypes:  ex.MediaTypes,
+		StatusCode:  goaexpr.StatusOK,
+	}
+	if ed.ServerStream != nil {
+		body := ed.ServerStream.Response.ServerBody
+		if len(body) == 0 || body[0].Init == nil {
+			return nil
+		}
+		d.ItemRef = body[0].Ref
+		d.BodyInit = body[0].Init.Name
+		d.Stream = &streamData{
+			VarName:        codegen.Goify(ed.Method.VarName, false) + "ExportStream",
+			Interface:      ed.ServerStream.Interface,
+			EndpointStruct: ed.ServicePkgName + "." + ed.Method.ServerStream.EndpointStruct,
+			SendName:       ed.ServerStream.SendName,
+			SendTypeRef:    ed.ServerStream.SendTypeRef,
+			PayloadRef:     ed.Payload.Ref,
+		}
+	} else {
+		if len(ed.Result.Responses) == 0 {
+			return nil
+		}
+		body := ed.Result.Responses[0].ServerBody
+		if len(body) == 0 || body[0].Init == nil || !strings.HasPrefix(body[0].Def, "[]") {
+			return nil
+		}
+		d.ItemRef = strings.TrimPrefix(body[0].Def, "[]")
+		d.ResultRef = ed.Result.Ref
+		d.BodyInit = body[0].Init.Name
+		if s := e.Responses[0].StatusCode; s != goaexpr.StatusNoContent {
+			d.StatusCode = s
+		}
+	}
+	if ex.Has(expr.CSVMediaType) {
+		for _, nat := range *goaexpr.AsObject(ex.Item().Type) {
+			d.Columns = append(d.Columns, nat.Name)
+			d.Fields = append(d.Fields, codegen.GoifyAtt(nat.Attribute, nat.Name, true))
+		}
+		d.RowFunc = codegen.Goify(ed.Method.VarName, false) + "ExportRow"
+	}
+	return d
+}
+
+// serverExport makes the HTTP handlers of the exported endpoints serve the
+// requests that accept an export media type with the export functions.
+func serverExport(f *codegen.File) {
+	for _, s := range f.Section("server-handler-init") {
+		ed := s.Data.(*httpcodegen.EndpointData)
+		ex := expr.Root.Export(ed.ServiceName, ed.Method.Name)
+		if ex == nil {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/export"})
+		mts := make([]string, len(ex.MediaTypes))
+		for i, mt := range ex.MediaTypes {
+			mts[i] = fmt.Sprintf("%q", mt)
+		}
+		payload := "nil"
+		if ed.Payload.Ref != "" {
+			payload = "payload"
+		}
+		code := fmt.Sprintf(`		if mt := export.Negotiate(r, %s); mt != "" {
+			if err := serve%sExport(ctx, w, mt, endpoint, %s); err != nil {
+				if _, ok := err.(*export.StreamError); ok {
+					eh(ctx, w, err)
+				} else if err := encodeError(ctx, w, err); err != nil {
+					eh(ctx, w, err)
+				}
+			}
+			return
+		}
+`, strings.Join(mts, ", "), ed.Method.VarName, payload)
+		s.Source = strings.Replace(s.Source, "\t{{ if .ServerStream }}\n\t\tvar cancel", code+"\t{{ if .ServerStream }}\n\t\tvar cancel", 1)
+	}
+}
+
+// documentExport adds the export media types to the media types produced by
+// the operations of the exported endpoints if f is an OpenAPI file.
+func documentExport(f *codegen.File, r *goaexpr.RootExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("export", op) {
+				return nil
+			}
+			ex := operationExport(r, op)
+			if ex == nil {
+				return nil
+			}
+			if len(op.Produces) == 0 {
+				op.Produces = append([]string{}, spec.Produces...)
+			}
+			for _, mt := range ex.MediaTypes {
+				op.Produces = walk.AppendMediaType(op.Produces, mt)
+			}
+			return nil
+		})
+	})
+}
+
+// operationExport returns the export of the method corresponding to the given
+// operation, nil if there is none.
+func operationExport(r *goaexpr.RootExpr, op *openapi.Operation) *expr.ExportExpr {
+	m := walk.OperationMethod(r, op)
+	if m == nil {
+		return nil
+	}
+	return expr.Root.Export(m.Service.Name, m.Name)
+}
+
+// input: exportData
+const serveExportT = `{{ printf "serve%sExport serves the %q service %q endpoint requests that accept an export media type. It writes the result items one at a time." .VarName .ServiceName .MethodName | comment }}
+func serve{{ .VarName }}Export(ctx context.Context, w http.ResponseWriter, mt string, endpoint goa.Endpoint, payload interface{}) error {
+	ew := export.NewWriter(w, mt, {{ .StatusCode }}, {{ if .Columns }}{{ .VarName }}ExportColumns, {{ .RowFunc }}{{ else }}nil, nil{{ end }})
+{{- if .Stream }}
+	v := &{{ .Stream.EndpointStruct }}{
+		Stream: &{{ .Stream.VarName }}{w: ew},
+	{{- if .Stream.PayloadRef }}
+		Payload: payload.({{ .Stream.PayloadRef }}),
+	{{- end }}
+	}
+	if _, err := endpoint(ctx, v); err != nil {
+		return ew.Fail(err)
+	}
+{{- else }}
+	res, err := endpoint(ctx, payload)
+	if err != nil {
+		return err
+	}
+	for _, item := range {{ .BodyInit }}(res.({{ .ResultRef }})) {
+		if err := ew.Write(item); err != nil {
+			return err
+		}
+	}
+{{- end }}
+	return ew.Close()
+}
+{{- if .Columns }}
+
+{{ printf "%sExportColumns lists the names of the CSV columns of the %q service %q endpoint exports." .VarName .ServiceName .MethodName | comment }}
+var {{ .VarName }}ExportColumns = []string{ {{- range $i, $c := .Columns }}{{ if $i }}, {{ end }}{{ printf "%q" $c }}{{ end -}} }
+
+{{ printf "%s returns the CSV row of the given %q service %q endpoint result item." .RowFunc .ServiceName .MethodName | comment }}
+func {{ .RowFunc }}(v interface{}) []string {
+	item := v.({{ .ItemRef }})
+	return []string{
+	{{- range .Fields }}
+		export.Format(item.{{ . }}),
+	{{- end }}
+	}
+}
+{{- end }}
+{{- if .Stream }}
+
+{{ printf "%s implements the %s interface by writing the sent items to the export." .Stream.VarName .Stream.Interface | comment }}
+type {{ .Stream.VarName }} struct {
+	w export.Writer
+}
+
+{{ printf "%s writes the given item to the export." .Stream.SendName | comment }}
+func (s *{{ .Stream.VarName }}) {{ .Stream.SendName }}(v {{ .Stream.SendTypeRef }}) error {
+	return s.w.Write({{ .BodyInit }}(v))
+}
+
+// Close flushes the export.
+func (s *{{ .Stream.VarName }}) Close() error {
+	return s.w.Close()
+}
+{{- end }}
+`
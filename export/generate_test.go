@@ -0,0 +1,80 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/export"
+	"goa.design/plugins/v3/export/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := export.Generate("catalog/gen", []eval.Root{goaexpr.Root}, append(httpcodegen.ServerFiles("catalog/gen", goaexpr.Root), ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var serves []string
+	for _, f := range fs {
+		for _, s := range f.Section("export-serve") {
+			serves = append(serves, codegen.SectionCode(t, s))
+		}
+		for _, s := range f.Section("server-handler-init") {
+			ed := s.Data.(*httpcodegen.EndpointData)
+			code := codegen.SectionCode(t, s)
+			switch ed.Method.Name {
+			case "List":
+				if code != testdata.ListHandlerCode {
+					t.Errorf("invalid handler code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ListHandlerCode))
+				}
+			case "Show":
+				if strings.Contains(code, "export") {
+					t.Errorf("unexpected export in method without export")
+				}
+			}
+		}
+	}
+	if len(serves) != 2 {
+		t.Fatalf("got %d export functions, expected 2", len(serves))
+	}
+	for i, expected := range []string{testdata.ListExportCode, testdata.WatchExportCode} {
+		if serves[i] != expected {
+			t.Errorf("invalid export code, got:\n%s\ngot vs. expected:\n%s", serves[i], codegen.Diff(t, serves[i], expected))
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	cases := map[string]struct {
+		Path     string
+		Expected []string
+	}{
+		"list":  {"/items", []string{export.CSVMediaType, export.NDJSONMediaType}},
+		"watch": {"/items/watch", []string{export.NDJSONMediaType}},
+		"show":  {"/items/{id}", nil},
+	}
+	for k, c := range cases {
+		op := spec.Paths[c.Path].(*openapi.Path).Get
+		for _, mt := range []string{export.CSVMediaType, export.NDJSONMediaType} {
+			if actual, expected := contains(op.Produces, mt), contains(c.Expected, mt); actual != expected {
+				t.Errorf("%s: got %s produced %v, expected %v", k, mt, actual, expected)
+			}
+		}
+	}
+}
+
+func contains(mts []string, mt string) bool {
+	for _, m := range mts {
+		if m == mt {
+			return true
+		}
+	}
+	return false
+}
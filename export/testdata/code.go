@@ -0,0 +1,116 @@
+package testdata
+
+var ListExportCode = `// serveListExport serves the "Catalog" service "List" endpoint requests that
+// accept an export media type. It writes the result items one at a time.
+func serveListExport(ctx context.Context, w http.ResponseWriter, mt string, endpoint goa.Endpoint, payload interface{}) error {
+	ew := export.NewWriter(w, mt, 200, ListExportColumns, listExportRow)
+	res, err := endpoint(ctx, payload)
+	if err != nil {
+		return err
+	}
+	for _, item := range NewItemResponse(res.([]*catalog.Item)) {
+		if err := ew.Write(item); err != nil {
+			return err
+		}
+	}
+	return ew.Close()
+}
+
+// ListExportColumns lists the names of the CSV columns of the "Catalog"
+// service "List" endpoint exports.
+var ListExportColumns = []string{"id", "name", "price"}
+
+// listExportRow returns the CSV row of the given "Catalog" service "List"
+// endpoint result item.
+func listExportRow(v interface{}) []string {
+	item := v.(*ItemResponse)
+	return []string{
+		export.Format(item.ID),
+		export.Format(item.Name),
+		export.Format(item.Price),
+	}
+}
+`
+
+var WatchExportCode = `// serveWatchExport serves the "Catalog" service "Watch" endpoint requests that
+// accept an export media type. It writes the result items one at a time.
+func serveWatchExport(ctx context.Context, w http.ResponseWriter, mt string, endpoint goa.Endpoint, payload interface{}) error {
+	ew := export.NewWriter(w, mt, 200, nil, nil)
+	v := &catalog.WatchEndpointInput{
+		Stream:  &watchExportStream{w: ew},
+		Payload: payload.(*catalog.WatchPayload),
+	}
+	if _, err := endpoint(ctx, v); err != nil {
+		return ew.Fail(err)
+	}
+	return ew.Close()
+}
+
+// watchExportStream implements the catalog.WatchServerStream interface by
+// writing the sent items to the export.
+type watchExportStream struct {
+	w export.Writer
+}
+
+// Send writes the given item to the export.
+func (s *watchExportStream) Send(v *catalog.Item) error {
+	return s.w.Write(NewWatchResponseBody(v))
+}
+
+// Close flushes the export.
+func (s *watchExportStream) Close() error {
+	return s.w.Close()
+}
+`
+
+var ListHandlerCode = `// NewListHandler creates a HTTP handler which loads the HTTP request and calls
+// the "Catalog" service "List" endpoint.
+func NewListHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		decodeRequest  = DecodeListRequest(mux, dec)
+		encodeResponse = EncodeListResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "List")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Catalog")
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		if mt := export.Negotiate(r, "text/csv", "application/x-ndjson"); mt != "" {
+			if err := serveListExport(ctx, w, mt, endpoint, payload); err != nil {
+				if _, ok := err.(*export.StreamError); ok {
+					eh(ctx, w, err)
+				} else if err := encodeError(ctx, w, err); err != nil {
+					eh(ctx, w, err)
+				}
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, payload)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
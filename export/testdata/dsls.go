@@ -0,0 +1,48 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	export "goa.design/plugins/v3/export/dsl"
+)
+
+var CatalogDSL = func() {
+	var Item = Type("Item", func() {
+		Attribute("id", String)
+		Attribute("name", String)
+		Attribute("price", Float64)
+		Required("id", "name")
+	})
+	Service("Catalog", func() {
+		Method("List", func() {
+			export.CSV()
+			export.NDJSON()
+			Payload(func() {
+				Attribute("category", String)
+			})
+			Result(ArrayOf(Item))
+			HTTP(func() {
+				GET("/items")
+				Param("category")
+				Response(StatusOK)
+			})
+		})
+		Method("Watch", func() {
+			export.NDJSON()
+			Payload(func() {
+				Attribute("category", String)
+			})
+			StreamingResult(Item)
+			HTTP(func() {
+				GET("/items/watch")
+				Param("category")
+			})
+		})
+		Method("Show", func() {
+			Payload(String)
+			Result(Item)
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+	})
+}
@@ -0,0 +1,58 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/faker/expr"
+
+	// Enable the faker plugin
+	_ "goa.design/plugins/v3/faker"
+)
+
+// Seed sets the seed of the generated examples. The same seed always produces
+// the same examples. The seed defaults to the API name.
+//
+// Seed must appear in an API expression.
+//
+// Example:
+//
+//    import faker "goa.design/plugins/v3/faker/dsl"
+//
+//    var _ = API("bookstore", func() {
+//        faker.Seed("v1")
+//    })
+//
+func Seed(seed string) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Seed = seed
+}
+
+// Fake sets the name of the generator producing the attribute example. By
+// default the generator is inferred from the attribute format or name. The
+// generator is not used if the attribute defines an example.
+//
+// Fake must appear in an Attribute expression describing a primitive.
+//
+// Example:
+//
+//    var Author = Type("Author", func() {
+//        Attribute("contact", String, func() {
+//            faker.Fake("email")
+//        })
+//    })
+//
+func Fake(name string) {
+	att, ok := eval.Current().(*goaexpr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	att.Meta[expr.GeneratorKey] = []string{name}
+	expr.Root.Fakes = append(expr.Root.Fakes, &expr.FakeExpr{Generator: name, Attribute: att})
+}
@@ -0,0 +1,37 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// FakeExpr describes the use of an explicit generator by an attribute.
+type FakeExpr struct {
+	// Generator is the name of the generator.
+	Generator string
+	// Attribute is the attribute using the generator.
+	Attribute *expr.AttributeExpr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (f *FakeExpr) EvalName() string {
+	return fmt.Sprintf("fake %q", f.Generator)
+}
+
+// Validate makes sure the generator exists and that the attribute is a
+// primitive.
+func (f *FakeExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if Lookup(f.Generator) == nil {
+		verr.Add(f, "unknown generator %q", f.Generator)
+	}
+	if _, ok := f.Attribute.Type.(expr.Primitive); !ok {
+		verr.Add(f, "generators apply to primitive attributes only")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,103 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manveru/faker"
+)
+
+// Generator produces an example value using the given faker. The faker is
+// seeded so that generators produce the same values for a given seed.
+type Generator func(f *faker.Faker) interface{}
+
+var (
+	// generators maps the generator names to the generators.
+	generators = make(map[string]Generator)
+	// aliases maps normalized attribute names to generator names.
+	aliases = make(map[string]string)
+	// mu protects generators and aliases.
+	mu sync.RWMutex
+)
+
+// Register the built-in generators.
+func init() {
+	Register("email", func(f *faker.Faker) interface{} { return f.SafeEmail() }, "emailaddress")
+	Register("uuid", func(f *faker.Faker) interface{} { return uuid(f) }, "id", "guid")
+	Register("uri", func(f *faker.Faker) interface{} { return f.URL() }, "url", "website", "homepage", "link")
+	Register("hostname", func(f *faker.Faker) interface{} { return f.DomainName() }, "host", "domain")
+	Register("ipv4", func(f *faker.Faker) interface{} { return f.IPv4Address().String() }, "ip", "ipaddress")
+	Register("ipv6", func(f *faker.Faker) interface{} { return f.IPv6Address().String() })
+	Register("date-time", func(f *faker.Faker) interface{} { return timestamp(f).Format(time.RFC3339) },
+		"createdat", "updatedat", "deletedat", "timestamp")
+	Register("date", func(f *faker.Faker) interface{} { return timestamp(f).Format("2006-01-02") }, "birthdate", "birthday")
+	Register("name", func(f *faker.Faker) interface{} { return f.Name() }, "fullname")
+	Register("first_name", func(f *faker.Faker) interface{} { return f.FirstName() }, "firstname", "givenname")
+	Register("last_name", func(f *faker.Faker) interface{} { return f.LastName() }, "lastname", "surname", "familyname")
+	Register("username", func(f *faker.Faker) interface{} { return f.UserName() }, "login", "handle")
+	Register("phone", func(f *faker.Faker) interface{} { return f.PhoneNumber() }, "phonenumber", "telephone", "mobile")
+	Register("street_address", func(f *faker.Faker) interface{} { return f.StreetAddress() }, "address", "street", "streetaddress")
+	Register("city", func(f *faker.Faker) interface{} { return f.City() })
+	Register("state", func(f *faker.Faker) interface{} { return f.State() }, "province", "region")
+	Register("country", func(f *faker.Faker) interface{} { return f.Country() })
+	Register("postcode", func(f *faker.Faker) interface{} { return f.PostCode() }, "zip", "zipcode", "postalcode")
+	Register("company", func(f *faker.Faker) interface{} { return f.CompanyName() }, "companyname", "organization")
+	Register("job_title", func(f *faker.Faker) interface{} { return f.JobTitle() }, "jobtitle")
+	Register("latitude", func(f *faker.Faker) interface{} { return f.Latitude() }, "lat")
+	Register("longitude", func(f *faker.Faker) interface{} { return f.Longitude() }, "lng", "lon")
+}
+
+// Register registers the generator with the given name. The generator is used
+// by the attributes that explicitly use it, by the attributes whose format is
+// the generator name and by the attributes whose name matches the generator
+// name or one of the aliases. Attribute names are matched ignoring case,
+// underscores and dashes. Registering a generator with an existing name
+// overrides the existing generator.
+func Register(name string, g Generator, alias ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	generators[name] = g
+	for _, n := range append([]string{name}, alias...) {
+		aliases[normalize(n)] = name
+	}
+}
+
+// Lookup returns the generator with the given name, nil if there is none.
+func Lookup(name string) Generator {
+	mu.RLock()
+	defer mu.RUnlock()
+	return generators[name]
+}
+
+// Match returns the name of the generator matching the given attribute name,
+// the empty string if there is none.
+func Match(att string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return aliases[normalize(att)]
+}
+
+// normalize returns the lowercase version of the name without underscores
+// and dashes.
+func normalize(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
+
+// uuid returns a version 4 UUID built from the faker random numbers.
+func uuid(f *faker.Faker) string {
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = byte(f.Rand.Intn(256))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// timestamp returns a time between 2000 and 2030.
+func timestamp(f *faker.Faker) time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	return time.Unix(start+f.Rand.Int63n(30*365*24*3600), 0).UTC()
+}
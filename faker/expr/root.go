@@ -0,0 +1,175 @@
+package expr
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/manveru/faker"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// GeneratorKey is the meta key recording the name of the generator explicitly
+// used by an attribute.
+const GeneratorKey = "faker:generator"
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the faker settings defined in the design.
+	RootExpr struct {
+		// Seed is the seed of the generated values, defaults to the API
+		// name.
+		Seed string
+		// Fakes lists the attributes using an explicit generator in the
+		// order they appear in the design.
+		Fakes []*FakeExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "faker plugin"
+}
+
+// WalkSets iterates over the attributes using an explicit generator.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	exps := make(eval.ExpressionSet, len(r.Fakes))
+	for i, f := range r.Fakes {
+		exps[i] = f
+	}
+	walk(exps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/faker/dsl"}
+}
+
+// Prepare sets the examples of the attributes that do not define one and that
+// match a generator. It runs before goa finalizes the design so that the
+// examples are copied together with the attributes into the transport types.
+func (r *RootExpr) Prepare() {
+	seed := r.Seed
+	if seed == "" && expr.Root.API != nil {
+		seed = expr.Root.API.Name
+	}
+	f := &filler{seed: seed, seen: make(map[*expr.AttributeExpr]struct{})}
+	for _, ut := range expr.Root.Types {
+		f.walk(ut.Attribute(), ut.Name())
+	}
+	for _, rt := range expr.Root.ResultTypes {
+		f.walk(rt.Attribute(), rt.Name())
+	}
+	for _, svc := range expr.Root.Services {
+		for _, m := range svc.Methods {
+			path := svc.Name + "." + m.Name
+			f.walk(m.Payload, path+".payload")
+			f.walk(m.StreamingPayload, path+".streaming_payload")
+			f.walk(m.Result, path+".result")
+			for _, e := range m.Errors {
+				f.walk(e.AttributeExpr, path+"."+e.Name)
+			}
+		}
+	}
+}
+
+// filler sets the examples of the attributes matching a generator.
+type filler struct {
+	// seed is the seed of the generated values.
+	seed string
+	// seen records the visited attributes.
+	seen map[*expr.AttributeExpr]struct{}
+}
+
+// walk sets the examples of the attributes of the given inline object and of
+// the inline objects it contains recursively. User types are walked
+// separately.
+func (f *filler) walk(att *expr.AttributeExpr, path string) {
+	if att == nil {
+		return
+	}
+	if _, ok := f.seen[att]; ok {
+		return
+	}
+	f.seen[att] = struct{}{}
+	switch t := att.Type.(type) {
+	case *expr.Object:
+		for _, nat := range *t {
+			p := path + "." + nat.Name
+			f.fill(nat.Attribute, nat.Name, p)
+			if _, ok := nat.Attribute.Type.(expr.UserType); !ok {
+				f.walk(nat.Attribute, p)
+			}
+		}
+	case *expr.Array:
+		if _, ok := t.ElemType.Type.(expr.UserType); !ok {
+			f.walk(t.ElemType, path+"[]")
+		}
+	}
+}
+
+// fill sets the example of the given primitive attribute using the generator
+// explicitly used by the attribute, the generator of its format or the
+// generator matching its name in this order. The attributes which already
+// define examples or which have validations other than a format are left
+// untouched.
+func (f *filler) fill(att *expr.AttributeExpr, name, path string) {
+	if len(att.UserExamples) > 0 {
+		return
+	}
+	if _, ok := att.Type.(expr.Primitive); !ok {
+		return
+	}
+	var gen string
+	if v := att.Validation; v != nil {
+		if len(v.Values) > 0 || v.Pattern != "" || v.Minimum != nil || v.Maximum != nil || v.MinLength != nil || v.MaxLength != nil {
+			return
+		}
+		if v.Format != "" {
+			if Lookup(string(v.Format)) == nil {
+				return
+			}
+			gen = string(v.Format)
+		}
+	}
+	if g, ok := att.Meta[GeneratorKey]; ok && len(g) > 0 {
+		gen = g[0]
+	}
+	if gen == "" {
+		gen = Match(name)
+	}
+	g := Lookup(gen)
+	if g == nil {
+		return
+	}
+	val := g(newFaker(f.seed + ":" + path))
+	if !att.Type.IsCompatible(val) {
+		return
+	}
+	att.UserExamples = append(att.UserExamples, &expr.ExampleExpr{Summary: "faker", Value: val})
+}
+
+// newFaker returns a faker seeded from the given string.
+func newFaker(seed string) *faker.Faker {
+	sum := md5.Sum([]byte(seed))
+	return &faker.Faker{
+		Language: "en",
+		Dict:     faker.Dict["en"],
+		Rand:     rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:])))),
+	}
+}
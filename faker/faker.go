@@ -0,0 +1,22 @@
+package faker
+
+import (
+	"goa.design/plugins/v3/faker/expr"
+)
+
+// Generator produces an example value using the given faker. The faker is
+// seeded so that generators produce the same values for a given seed.
+type Generator = expr.Generator
+
+// Register registers a generator so that it can be used by the design. The
+// generator is used by the attributes that explicitly use it via the Fake DSL,
+// by the attributes whose format is the generator name and by the attributes
+// whose name matches the generator name or one of the aliases. Attribute names
+// are matched ignoring case, underscores and dashes. Registering a generator
+// with the name of a built-in generator overrides it.
+//
+// Register must be called before the design is evaluated, typically from an
+// init function of the design package.
+func Register(name string, g Generator, alias ...string) {
+	expr.Register(name, g, alias...)
+}
@@ -0,0 +1,83 @@
+package faker_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/manveru/faker"
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	fakerplugin "goa.design/plugins/v3/faker"
+	"goa.design/plugins/v3/faker/expr"
+	"goa.design/plugins/v3/faker/testdata"
+)
+
+func init() {
+	fakerplugin.Register("sku", func(f *faker.Faker) interface{} { return "SKU-42" })
+}
+
+func TestFill(t *testing.T) {
+	examples := run(t)
+	cases := []struct {
+		Name    string
+		Pattern string
+	}{
+		{"id", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"email", `^\S+@example\.(com|org|net)$`},
+		{"first_name", `^[A-Z][a-z']+$`},
+		{"contact", `^\S+@example\.(com|org|net)$`},
+		{"sku", `^SKU-42$`},
+		{"city", `^Paris$`},
+		{"website", `^http://\S+$`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			ex, ok := examples[c.Name]
+			if !ok {
+				t.Fatalf("no example for %q", c.Name)
+			}
+			s, ok := ex.(string)
+			if !ok {
+				t.Fatalf("got example %v of type %T, expected a string", ex, ex)
+			}
+			if !regexp.MustCompile(c.Pattern).MatchString(s) {
+				t.Errorf("got example %q, expected to match %s", s, c.Pattern)
+			}
+		})
+	}
+	for _, name := range []string{"country", "zip", "note"} {
+		if ex, ok := examples[name]; ok {
+			t.Errorf("got example %v for %q, expected none", ex, name)
+		}
+	}
+}
+
+func TestFillDeterministic(t *testing.T) {
+	first, second := run(t), run(t)
+	for name, ex := range first {
+		if second[name] != ex {
+			t.Errorf("got example %v for %q on second run, expected %v", second[name], name, ex)
+		}
+	}
+}
+
+// run evaluates the test design and returns the examples of the Author type
+// and of the create payload attributes indexed by attribute name.
+func run(t *testing.T) map[string]interface{} {
+	expr.Root.Seed, expr.Root.Fakes = "", nil
+	codegen.RunDSLWithFunc(t, testdata.FakerDSL, func() {
+		eval.Register(expr.Root)
+	})
+	examples := make(map[string]interface{})
+	collect := func(att *goaexpr.AttributeExpr) {
+		for _, nat := range *goaexpr.AsObject(att.Type) {
+			if l := len(nat.Attribute.UserExamples); l > 0 {
+				examples[nat.Name] = nat.Attribute.UserExamples[l-1].Value
+			}
+		}
+	}
+	collect(goaexpr.Root.UserType("Author").Attribute())
+	collect(goaexpr.Root.Service("books").Method("create").Payload)
+	return examples
+}
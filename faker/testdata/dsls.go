@@ -0,0 +1,39 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	faker "goa.design/plugins/v3/faker/dsl"
+)
+
+var FakerDSL = func() {
+	API("bookstore", func() {
+		faker.Seed("test")
+	})
+	var Author = Type("Author", func() {
+		Attribute("id", String)
+		Attribute("email", String)
+		Attribute("first_name", String)
+		Attribute("contact", String, func() {
+			Format(FormatEmail)
+		})
+		Attribute("sku", String, func() {
+			faker.Fake("sku")
+		})
+		Attribute("city", String, func() {
+			Example("Paris")
+		})
+		Attribute("country", String, func() {
+			Enum("France", "Spain")
+		})
+		Attribute("zip", Int)
+		Attribute("note", String)
+	})
+	Service("books", func() {
+		Method("create", func() {
+			Payload(func() {
+				Attribute("author", Author)
+				Attribute("website", String)
+			})
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package dsl
+
+import (
+	"net/http"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/feature/expr"
+
+	// Register code generators for the feature plugin
+	_ "goa.design/plugins/v3/feature"
+)
+
+// Feature gates the method behind the named feature flag. The HTTP requests
+// made to the method while the feature is disabled are answered with 404 Not
+// Found so that the endpoint appears not to exist. Use Forbidden to respond
+// with 403 Forbidden instead.
+//
+// Feature must appear in a Method expression.
+//
+// Feature accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import feature "goa.design/plugins/v3/feature/dsl"
+//
+//    var _ = Service("orders", func() {
+//        Method("checkout", func() {
+//            feature.Feature("new-checkout", func() {
+//                feature.Forbidden()
+//            })
+//            Payload(Cart)
+//            HTTP(func() {
+//                POST("/checkout")
+//            })
+//        })
+//    })
+//
+func Feature(name string, fn ...func()) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	if expr.Root.Feature(m.Service.Name, m.Name) != nil {
+		eval.ReportError("method %q is gated by more than one feature", m.Name)
+		return
+	}
+	f := &expr.FeatureExpr{Name: name, Status: http.StatusNotFound, Method: m}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], f) {
+			return
+		}
+	}
+	expr.Root.Features = append(expr.Root.Features, f)
+}
+
+// Forbidden makes the HTTP requests made to the method while the feature is
+// disabled respond with 403 Forbidden instead of 404 Not Found.
+//
+// Forbidden must appear in a Feature expression.
+func Forbidden() {
+	if f, ok := eval.Current().(*expr.FeatureExpr); ok {
+		f.Status = http.StatusForbidden
+		return
+	}
+	eval.IncompatibleDSL()
+}
@@ -0,0 +1,42 @@
+package expr
+
+import (
+	"fmt"
+	"net/http"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// FeatureExpr describes a method gated by a feature flag.
+	FeatureExpr struct {
+		// Name is the name of the feature flag.
+		Name string
+		// Status is the HTTP status code of the responses to the
+		// requests made while the feature is disabled.
+		Status int
+		// Method is the gated method.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (f *FeatureExpr) EvalName() string {
+	return fmt.Sprintf("feature %q of %s", f.Name, f.Method.EvalName())
+}
+
+// Validate ensures the feature expression is valid.
+func (f *FeatureExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if f.Name == "" {
+		verr.Add(f, "feature name cannot be empty")
+	}
+	if f.Status != http.StatusNotFound && f.Status != http.StatusForbidden {
+		verr.Add(f, "status must be %d or %d", http.StatusNotFound, http.StatusForbidden)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the methods gated by feature flags.
+	RootExpr struct {
+		// Features lists the feature definitions in the order they
+		// appear in the design.
+		Features []*FeatureExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "feature plugin"
+}
+
+// WalkSets iterates over the feature definitions.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	fexps := make(eval.ExpressionSet, len(r.Features))
+	for i, f := range r.Features {
+		fexps[i] = f
+	}
+	walk(fexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/feature/dsl"}
+}
+
+// Feature returns the feature definition of the given method, nil if the
+// method is not gated by a feature flag.
+func (r *RootExpr) Feature(svc, method string) *FeatureExpr {
+	for _, f := range r.Features {
+		if f.Method.Service.Name == svc && f.Method.Name == method {
+			return f
+		}
+	}
+	return nil
+}
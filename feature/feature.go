@@ -0,0 +1,36 @@
+package feature
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	// Provider reports whether feature flags are enabled.
+	Provider interface {
+		// Enabled returns true if the feature with the given name is
+		// enabled for the request with the given context.
+		Enabled(ctx context.Context, name string) bool
+	}
+
+	// Flags is a Provider backed by a static set of flags. Features missing
+	// from the map are disabled.
+	Flags map[string]bool
+)
+
+// Enabled returns true if the feature is enabled in f.
+func (f Flags) Enabled(_ context.Context, name string) bool {
+	return f[name]
+}
+
+// Handler returns a HTTP handler which responds with the given status code
+// and no body when the feature is disabled and calls h otherwise.
+func Handler(h http.Handler, p Provider, name string, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.Enabled(r.Context(), name) {
+			w.WriteHeader(status)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
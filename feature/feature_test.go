@@ -0,0 +1,32 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	cases := []struct {
+		Name   string
+		Flags  Flags
+		Status int
+		Output int
+	}{
+		{"enabled", Flags{"checkout": true}, http.StatusNotFound, http.StatusOK},
+		{"disabled", Flags{"checkout": false}, http.StatusNotFound, http.StatusNotFound},
+		{"missing", Flags{}, http.StatusForbidden, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handler(h, c.Flags, "checkout", c.Status).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if w.Code != c.Output {
+				t.Errorf("got status %d, expected %d", w.Code, c.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package feature
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/feature/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// serverData contains the data necessary to render the function gating
+	// the handlers of a HTTP server.
+	serverData struct {
+		// ServerStruct is the name of the HTTP server struct.
+		ServerStruct string
+		// Gates lists the gated handlers.
+		Gates []*gateData
+	}
+
+	// gateData describes a handler gated by a feature flag.
+	gateData struct {
+		// VarName is the name of the handler field of the server struct.
+		VarName string
+		// Feature is the name of the feature flag.
+		Feature string
+		// Status is the Go constant of the HTTP status code returned
+		// when the feature is disabled.
+		Status string
+	}
+
+	// extensionData is the value of the x-feature OpenAPI extension.
+	extensionData struct {
+		// Name is the name of the feature flag.
+		Name string `json:"name" yaml:"name"`
+		// Status is the HTTP status code returned when the feature is
+		// disabled.
+		Status int `json:"status" yaml:"status"`
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "feature",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the functions gating the HTTP handlers of the methods
+// that depend on feature flags and documents the flags in the OpenAPI
+// specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Features) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("feature", f) {
+			continue
+		}
+		documentFeatures(f)
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.API.HTTP.Services {
+				if !config.Enabled("feature", svc.Name()) {
+					continue
+				}
+				if f := featuresFile(svc); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// featuresFile returns the file defining the function gating the handlers of
+// the given HTTP service, nil if no method of the service is gated.
+func featuresFile(svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	sd := &serverData{ServerStruct: data.ServerStruct}
+	for _, ed := range data.Endpoints {
+		f := expr.Root.Feature(svc.Name(), ed.Method.Name)
+		if f == nil {
+			continue
+		}
+		sd.Gates = append(sd.Gates, &gateData{
+			VarName: ed.Method.VarName,
+			Feature: f.Name,
+			Status:  "http.Status" + codegen.Goify(http.StatusText(f.Status), true),
+		})
+	}
+	if len(sd.Gates) == 0 {
+		return nil
+	}
+	svcName := codegen.SnakeCase(data.Service.VarName)
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "http", svcName, "server", "features.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name()+" HTTP server feature flags", "server", []*codegen.ImportSpec{
+				{Path: "net/http"},
+				{Path: "goa.design/plugins/v3/feature"},
+			}),
+			{Name: "feature-use", Source: useFeaturesT, Data: sd},
+		},
+	}
+}
+
+// documentFeatures adds the x-feature extension and the response returned
+// when the feature is disabled to the gated operations if f is an OpenAPI
+// file.
+func documentFeatures(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("feature", op) {
+				return nil
+			}
+			feat := feature(op)
+			if feat == nil {
+				return nil
+			}
+			if op.Extensions == nil {
+				op.Extensions = make(map[string]interface{})
+			}
+			op.Extensions["x-feature"] = &extensionData{Name: feat.Name, Status: feat.Status}
+			status := strconv.Itoa(feat.Status)
+			if _, ok := op.Responses[status]; !ok {
+				op.Responses[status] = &openapi.Response{
+					Description: fmt.Sprintf("%s response returned when the %q feature is disabled.", http.StatusText(feat.Status), feat.Name),
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// feature returns the feature definition corresponding to the given
+// operation, nil if the operation is not gated.
+func feature(op *openapi.Operation) *expr.FeatureExpr {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	for _, f := range expr.Root.Features {
+		if m != nil && f.Method == m {
+			return f
+		}
+	}
+	return nil
+}
+
+// input: serverData
+const useFeaturesT = `// UseFeatures gates the handlers of the methods that depend on feature flags.
+// The requests made while a feature is disabled are answered without calling
+// the handler. UseFeatures must be called before Mount.
+func UseFeatures(s *{{ .ServerStruct }}, p feature.Provider) {
+{{- range .Gates }}
+	s.{{ .VarName }} = feature.Handler(s.{{ .VarName }}, p, {{ printf "%q" .Feature }}, {{ .Status }})
+{{- end }}
+}
+`
@@ -0,0 +1,55 @@
+package feature_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/feature"
+	"goa.design/plugins/v3/feature/expr"
+	"goa.design/plugins/v3/feature/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Features = nil
+	httpcodegen.RunHTTPDSL(t, testdata.FeatureDSL)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := feature.Generate("", []eval.Root{goaexpr.Root}, ofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := fs[len(fs)-1]
+	if f.Path != "gen/http/orders/server/features.go" {
+		t.Fatalf("got file %q, expected gen/http/orders/server/features.go", f.Path)
+	}
+	code := codegen.SectionCode(t, f.SectionTemplates[1])
+	if code != testdata.UseFeaturesCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.UseFeaturesCode))
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	cases := []struct {
+		Path   string
+		Status string
+	}{
+		{"/checkout", "404"},
+		{"/refunds", "403"},
+	}
+	for _, c := range cases {
+		op := spec.Paths[c.Path].(*openapi.Path).Post
+		if _, ok := op.Extensions["x-feature"]; !ok {
+			t.Errorf("x-feature extension of %s not found in OpenAPI spec", c.Path)
+		}
+		if _, ok := op.Responses[c.Status]; !ok {
+			t.Errorf("%s response of %s not found in OpenAPI spec", c.Status, c.Path)
+		}
+	}
+	if _, ok := spec.Paths["/orders"].(*openapi.Path).Get.Extensions["x-feature"]; ok {
+		t.Error("x-feature extension found in operation without feature")
+	}
+}
@@ -0,0 +1,10 @@
+package testdata
+
+var UseFeaturesCode = `// UseFeatures gates the handlers of the methods that depend on feature flags.
+// The requests made while a feature is disabled are answered without calling
+// the handler. UseFeatures must be called before Mount.
+func UseFeatures(s *Server, p feature.Provider) {
+	s.Checkout = feature.Handler(s.Checkout, p, "new-checkout", http.StatusNotFound)
+	s.Refund = feature.Handler(s.Refund, p, "refunds", http.StatusForbidden)
+}
+`
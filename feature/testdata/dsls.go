@@ -0,0 +1,30 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	feature "goa.design/plugins/v3/feature/dsl"
+)
+
+var FeatureDSL = func() {
+	Service("Orders", func() {
+		Method("Checkout", func() {
+			feature.Feature("new-checkout")
+			HTTP(func() {
+				POST("/checkout")
+			})
+		})
+		Method("Refund", func() {
+			feature.Feature("refunds", func() {
+				feature.Forbidden()
+			})
+			HTTP(func() {
+				POST("/refunds")
+			})
+		})
+		Method("Show", func() {
+			HTTP(func() {
+				GET("/orders")
+			})
+		})
+	})
+}
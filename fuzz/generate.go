@@ -0,0 +1,176 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/registry"
+)
+
+// targetData contains the data necessary to render the fuzz target of an
+// endpoint request decoder.
+type targetData struct {
+	// Name is the name of the method.
+	Name string
+	// VarName is the Go name of the method.
+	VarName string
+	// Decoder is the name of the request decoder function.
+	Decoder string
+	// Verb is the HTTP method of the request.
+	Verb string
+	// Pattern is the route pattern mounted on the muxer.
+	Pattern string
+	// Path is the request path built from the path parameter examples.
+	Path string
+	// Body is the seed request body built from the body example.
+	Body string
+	// Query is the seed query string built from the query parameter
+	// examples.
+	Query string
+}
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "fuzz",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces a fuzz test file for each HTTP server which defines one
+// fuzz target per endpoint request decoder.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			for _, svc := range r.API.HTTP.Services {
+				if !config.Enabled("fuzz", svc.Name()) {
+					continue
+				}
+				if f := fuzzFile(r, svc); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// fuzzFile returns the fuzz test file of the given HTTP service, nil if no
+// endpoint decodes requests.
+func fuzzFile(r *expr.RootExpr, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var sections []*codegen.SectionTemplate
+	for _, ed := range data.Endpoints {
+		if ed.Payload.Ref == "" || ed.MultipartRequestDecoder != nil || len(ed.Routes) == 0 {
+			continue
+		}
+		e := svc.Endpoint(ed.Method.Name)
+		route := ed.Routes[0]
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "fuzz-target",
+			Source: targetT,
+			Data: &targetData{
+				Name:    ed.Method.Name,
+				VarName: ed.Method.VarName,
+				Decoder: ed.RequestDecoder,
+				Verb:    route.Verb,
+				Pattern: route.Path,
+				Path:    genutil.ExamplePath(r, e, route.Path),
+				Body:    exampleBody(r, e),
+				Query:   exampleQuery(r, e),
+			},
+		})
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	header := codegen.Header(svc.Name()+" HTTP server request decoder fuzz tests", "server", []*codegen.ImportSpec{
+		{Path: "bytes"},
+		{Path: "net/http"},
+		{Path: "net/http/httptest"},
+		{Path: "testing"},
+		{Path: "goa.design/goa/v3/http", Name: "goahttp"},
+		codegen.GoaImport(""),
+	})
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(data.Service.VarName), "server", "fuzz_test.go"),
+		SectionTemplates: append([]*codegen.SectionTemplate{header}, sections...),
+	}
+}
+
+// exampleBody returns the JSON representation of the request body example,
+// the empty string if the request has no body.
+func exampleBody(r *expr.RootExpr, e *expr.HTTPEndpointExpr) string {
+	if e.Body == nil || e.Body.Type == expr.Empty {
+		return ""
+	}
+	b, err := json.Marshal(e.Body.Example(r.API.Random()))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// exampleQuery returns the query string built from the query parameter
+// examples.
+func exampleQuery(r *expr.RootExpr, e *expr.HTTPEndpointExpr) string {
+	params := e.QueryParams()
+	if expr.AsObject(params.Type) == nil {
+		return ""
+	}
+	values := url.Values{}
+	expr.WalkMappedAttr(params, func(name, elem string, att *expr.AttributeExpr) error {
+		ex := reflect.ValueOf(att.Example(r.API.Random()))
+		if ex.Kind() == reflect.Slice {
+			for i := 0; i < ex.Len(); i++ {
+				values.Add(elem, fmt.Sprint(ex.Index(i).Interface()))
+			}
+			return nil
+		}
+		values.Add(elem, fmt.Sprint(ex.Interface()))
+		return nil
+	})
+	return values.Encode()
+}
+
+// input: targetData
+const targetT = `{{ printf "Fuzz%sRequest checks that the %q endpoint request decoder does not panic and returns structured errors when given arbitrary bodies and query strings." .VarName .Name | comment }}
+func Fuzz{{ .VarName }}Request(f *testing.F) {
+	f.Add([]byte({{ printf "%q" .Body }}), {{ printf "%q" .Query }})
+	f.Fuzz(func(t *testing.T, body []byte, query string) {
+		var (
+			err     error
+			decoded bool
+			mux     = goahttp.NewMuxer()
+		)
+		mux.Handle({{ printf "%q" .Verb }}, {{ printf "%q" .Pattern }}, func(w http.ResponseWriter, r *http.Request) {
+			_, err = {{ .Decoder }}(mux, goahttp.RequestDecoder)(r)
+			decoded = true
+		})
+		r := httptest.NewRequest({{ printf "%q" .Verb }}, {{ printf "%q" .Path }}, bytes.NewReader(body))
+		r.URL.RawQuery = query
+		r.RequestURI = r.URL.RequestURI()
+		r.Header.Set("Content-Type", "application/json")
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+		if !decoded {
+			t.Fatal("request was not routed to the decoder")
+		}
+		if err == nil {
+			return
+		}
+		if _, ok := err.(*goa.ServiceError); !ok {
+			t.Errorf("decoder returned unstructured error %T: %v", err, err)
+		}
+	})
+}
+`
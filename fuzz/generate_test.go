@@ -0,0 +1,34 @@
+package fuzz_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/fuzz"
+	"goa.design/plugins/v3/fuzz/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.FuzzDSL)
+	fs, err := fuzz.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/http/calc/server/fuzz_test.go" {
+		t.Errorf("got path %q, expected gen/http/calc/server/fuzz_test.go", fs[0].Path)
+	}
+	// The Reset method has no payload and thus no request decoder.
+	if len(fs[0].SectionTemplates) != 2 {
+		t.Fatalf("got %d sections, expected 2", len(fs[0].SectionTemplates))
+	}
+	code := codegen.SectionCode(t, fs[0].SectionTemplates[1])
+	if code != testdata.AddTargetCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.AddTargetCode))
+	}
+}
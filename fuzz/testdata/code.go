@@ -0,0 +1,33 @@
+package testdata
+
+var AddTargetCode = `// FuzzAddRequest checks that the "Add" endpoint request decoder does not panic
+// and returns structured errors when given arbitrary bodies and query strings.
+func FuzzAddRequest(f *testing.F) {
+	f.Add([]byte("{\"b\":2,\"note\":\"hello world\"}"), "tags=x&tags=y")
+	f.Fuzz(func(t *testing.T, body []byte, query string) {
+		var (
+			err     error
+			decoded bool
+			mux     = goahttp.NewMuxer()
+		)
+		mux.Handle("POST", "/add/{a}", func(w http.ResponseWriter, r *http.Request) {
+			_, err = DecodeAddRequest(mux, goahttp.RequestDecoder)(r)
+			decoded = true
+		})
+		r := httptest.NewRequest("POST", "/add/1", bytes.NewReader(body))
+		r.URL.RawQuery = query
+		r.RequestURI = r.URL.RequestURI()
+		r.Header.Set("Content-Type", "application/json")
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+		if !decoded {
+			t.Fatal("request was not routed to the decoder")
+		}
+		if err == nil {
+			return
+		}
+		if _, ok := err.(*goa.ServiceError); !ok {
+			t.Errorf("decoder returned unstructured error %T: %v", err, err)
+		}
+	})
+}
+`
@@ -0,0 +1,37 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var FuzzDSL = func() {
+	Service("Calc", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Attribute("a", Int, func() {
+					Example(1)
+				})
+				Attribute("b", Int, func() {
+					Example(2)
+				})
+				Attribute("tags", ArrayOf(String), func() {
+					Example([]string{"x", "y"})
+				})
+				Attribute("note", String, func() {
+					Example("hello world")
+				})
+				Required("a", "b")
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/add/{a}")
+				Param("tags")
+			})
+		})
+		Method("Reset", func() {
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+	})
+}
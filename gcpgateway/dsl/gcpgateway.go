@@ -0,0 +1,187 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/gcpgateway/expr"
+
+	// Register code generators for the Google Cloud API Gateway plugin
+	_ "goa.design/plugins/v3/gcpgateway"
+)
+
+// Backend forwards the requests to the backend with the given URL, e.g. the
+// URL of a Cloud Run service. The request path is appended to the URL by
+// default.
+//
+// Backend may appear in an API, Service or Method expression. Method level
+// backends override service level backends which override the API level
+// backend.
+//
+// Backend accepts an optional DSL function as last argument.
+//
+// Example:
+//
+//    import gcpgateway "goa.design/plugins/v3/gcpgateway/dsl"
+//
+//    var _ = API("calc", func() {
+//        gcpgateway.Backend("https://calc-abcdef-ew.a.run.app", func() {
+//            gcpgateway.Deadline(30)
+//        })
+//    })
+//
+func Backend(address string, fn ...func()) {
+	var parent eval.Expression
+	switch e := eval.Current().(type) {
+	case *goaexpr.APIExpr, *goaexpr.ServiceExpr, *goaexpr.MethodExpr:
+		parent = e
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	for _, b := range expr.Root.Backends {
+		if b.Parent == parent {
+			eval.ReportError("backend defined twice")
+			return
+		}
+	}
+	b := &expr.BackendExpr{
+		Address:         address,
+		PathTranslation: expr.AppendPathToAddress,
+		Parent:          parent,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], b) {
+			return
+		}
+	}
+	expr.Root.Backends = append(expr.Root.Backends, b)
+}
+
+// ConstantAddress sends all the requests to the backend URL instead of
+// appending the request path to it. The gateway adds the path parameters to
+// the query string.
+//
+// ConstantAddress must appear in a Backend expression.
+//
+// Example:
+//
+//    Backend("https://us-central1-calc.cloudfunctions.net/add", func() {
+//        ConstantAddress()
+//    })
+//
+func ConstantAddress() {
+	switch b := eval.Current().(type) {
+	case *expr.BackendExpr:
+		b.PathTranslation = expr.ConstantAddress
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Deadline sets the number of seconds the gateway waits for the backend
+// response. The gateway uses 15 seconds by default.
+//
+// Deadline must appear in a Backend expression.
+func Deadline(seconds float64) {
+	switch b := eval.Current().(type) {
+	case *expr.BackendExpr:
+		b.Deadline = seconds
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// JWTAudience sets the audience of the Google-signed ID token the gateway
+// sends to the backend, the backend URL by default.
+//
+// JWTAudience must appear in a Backend expression.
+func JWTAudience(audience string) {
+	switch b := eval.Current().(type) {
+	case *expr.BackendExpr:
+		b.JWTAudience = audience
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// DisableAuth prevents the gateway from sending an ID token to the backend.
+// The backend receives the Authorization header sent by the client as is.
+//
+// DisableAuth must appear in a Backend expression.
+func DisableAuth() {
+	switch b := eval.Current().(type) {
+	case *expr.BackendExpr:
+		b.DisableAuth = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Issuer makes the gateway validate the tokens of the given JWT or OAuth2
+// security scheme. The tokens must be issued by the given issuer and signed
+// with one of the keys of the JSON Web Key Set at the given URL. scheme is the
+// security scheme expression returned by the goa security DSL (e.g.
+// JWTSecurity) or its name.
+//
+// Issuer must appear in an API expression.
+//
+// Issuer accepts an optional DSL function as last argument.
+//
+// Example:
+//
+//    var JWTAuth = JWTSecurity("jwt")
+//
+//    var _ = API("calc", func() {
+//        gcpgateway.Issuer(JWTAuth, "https://auth.example.com", "https://auth.example.com/.well-known/jwks.json", func() {
+//            gcpgateway.Audiences("calc")
+//        })
+//    })
+//
+func Issuer(scheme interface{}, issuer, jwksURI string, fn ...func()) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	var name string
+	switch s := scheme.(type) {
+	case string:
+		name = s
+	case *goaexpr.SchemeExpr:
+		name = s.SchemeName
+	default:
+		eval.InvalidArgError("security scheme or name", scheme)
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	if expr.Root.Issuer(name) != nil {
+		eval.ReportError("issuer of security scheme %q defined twice", name)
+		return
+	}
+	i := &expr.IssuerExpr{Scheme: name, Issuer: issuer, JWKSURI: jwksURI}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], i) {
+			return
+		}
+	}
+	expr.Root.Issuers = append(expr.Root.Issuers, i)
+}
+
+// Audiences sets the accepted values of the aud claim of the tokens. The
+// gateway accepts the tokens issued for the service name by default.
+//
+// Audiences must appear in an Issuer expression.
+func Audiences(audiences ...string) {
+	switch i := eval.Current().(type) {
+	case *expr.IssuerExpr:
+		i.Audiences = append(i.Audiences, audiences...)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
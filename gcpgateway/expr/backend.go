@@ -0,0 +1,118 @@
+package expr
+
+import (
+	"fmt"
+	"net/url"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// AppendPathToAddress appends the request path to the backend address.
+	AppendPathToAddress = "APPEND_PATH_TO_ADDRESS"
+	// ConstantAddress sends all the requests to the backend address, the
+	// path parameters are given in the query string.
+	ConstantAddress = "CONSTANT_ADDRESS"
+)
+
+type (
+	// BackendExpr describes the backend the gateway forwards the requests
+	// made to the methods of the API, a service or a single method to.
+	BackendExpr struct {
+		// Address is the URL of the backend.
+		Address string
+		// PathTranslation is the path translation strategy,
+		// AppendPathToAddress or ConstantAddress.
+		PathTranslation string
+		// Deadline is the request deadline in seconds, 0 for the
+		// gateway default.
+		Deadline float64
+		// JWTAudience is the audience of the ID token sent by the
+		// gateway to the backend, empty for the backend address.
+		JWTAudience string
+		// DisableAuth is true if the gateway does not authenticate with
+		// the backend.
+		DisableAuth bool
+		// Parent is the API, service or method expression.
+		Parent eval.Expression
+	}
+
+	// IssuerExpr describes the issuer of the JWTs validated by the gateway
+	// for a security scheme.
+	IssuerExpr struct {
+		// Scheme is the name of the JWT or OAuth2 security scheme.
+		Scheme string
+		// Issuer is the value of the iss claim of the tokens.
+		Issuer string
+		// JWKSURI is the URL of the JSON Web Key Set holding the keys
+		// used to verify the token signatures.
+		JWKSURI string
+		// Audiences lists the accepted values of the aud claim of the
+		// tokens.
+		Audiences []string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (b *BackendExpr) EvalName() string {
+	var suffix string
+	if b.Parent != nil {
+		suffix = " of " + b.Parent.EvalName()
+	}
+	return "Google Cloud API Gateway backend" + suffix
+}
+
+// Validate ensures the backend expression is valid.
+func (b *BackendExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if u, err := url.Parse(b.Address); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		verr.Add(b, "invalid backend address %q", b.Address)
+	}
+	if b.PathTranslation != AppendPathToAddress && b.PathTranslation != ConstantAddress {
+		verr.Add(b, "invalid path translation %q", b.PathTranslation)
+	}
+	if b.Deadline < 0 {
+		verr.Add(b, "deadline must be positive")
+	}
+	if b.DisableAuth && b.JWTAudience != "" {
+		verr.Add(b, "JWT audience cannot be set when authentication is disabled")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (i *IssuerExpr) EvalName() string {
+	return fmt.Sprintf("Google Cloud API Gateway issuer of security scheme %q", i.Scheme)
+}
+
+// Validate ensures the issuer expression is valid.
+func (i *IssuerExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	var scheme *expr.SchemeExpr
+	for _, s := range expr.Root.Schemes {
+		if s.SchemeName == i.Scheme {
+			scheme = s
+			break
+		}
+	}
+	switch {
+	case scheme == nil:
+		verr.Add(i, "security scheme %q is not defined", i.Scheme)
+	case scheme.Kind != expr.JWTKind && scheme.Kind != expr.OAuth2Kind:
+		verr.Add(i, "security scheme %q must be a JWT or OAuth2 scheme", i.Scheme)
+	}
+	if i.Issuer == "" {
+		verr.Add(i, "issuer cannot be empty")
+	}
+	if u, err := url.Parse(i.JWKSURI); err != nil || u.Scheme != "https" || u.Host == "" {
+		verr.Add(i, "invalid JWKS URI %q, must be an HTTPS URL", i.JWKSURI)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
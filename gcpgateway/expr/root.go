@@ -0,0 +1,94 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the Google Cloud API Gateway backends and
+	// issuers defined in the design.
+	RootExpr struct {
+		// Backends lists the backends in the order they appear in the
+		// design.
+		Backends []*BackendExpr
+		// Issuers lists the JWT issuers in the order they appear in the
+		// design.
+		Issuers []*IssuerExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "Google Cloud API Gateway plugin"
+}
+
+// WalkSets iterates over the backends and issuers.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	bexps := make(eval.ExpressionSet, len(r.Backends))
+	for i, b := range r.Backends {
+		bexps[i] = b
+	}
+	walk(bexps)
+	iexps := make(eval.ExpressionSet, len(r.Issuers))
+	for i, is := range r.Issuers {
+		iexps[i] = is
+	}
+	walk(iexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/gcpgateway/dsl"}
+}
+
+// Backend returns the backend of the given method. Method level backends
+// override service level backends which override the API level backend.
+// Backend returns nil if the method has no backend.
+func (r *RootExpr) Backend(m *expr.MethodExpr) *BackendExpr {
+	var svc, api *BackendExpr
+	for _, b := range r.Backends {
+		switch p := b.Parent.(type) {
+		case *expr.MethodExpr:
+			if p == m {
+				return b
+			}
+		case *expr.ServiceExpr:
+			if p == m.Service {
+				svc = b
+			}
+		case *expr.APIExpr:
+			api = b
+		}
+	}
+	if svc != nil {
+		return svc
+	}
+	return api
+}
+
+// Issuer returns the issuer of the given security scheme, nil if there isn't
+// one.
+func (r *RootExpr) Issuer(scheme string) *IssuerExpr {
+	for _, is := range r.Issuers {
+		if is.Scheme == scheme {
+			return is
+		}
+	}
+	return nil
+}
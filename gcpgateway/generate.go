@@ -0,0 +1,179 @@
+package gcpgateway
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/gcpgateway/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+	"goa.design/plugins/v3/walk"
+)
+
+const (
+	// backendExt is the name of the OpenAPI extension describing the
+	// backend of an operation.
+	backendExt = "x-google-backend"
+	// issuerExt is the name of the OpenAPI extension holding the issuer of
+	// the tokens of a security definition.
+	issuerExt = "x-google-issuer"
+	// jwksURIExt is the name of the OpenAPI extension holding the URL of
+	// the keys used to verify the tokens of a security definition.
+	jwksURIExt = "x-google-jwks_uri"
+	// audiencesExt is the name of the OpenAPI extension listing the
+	// audiences of the tokens of a security definition.
+	audiencesExt = "x-google-audiences"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "gcpgateway",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the OpenAPI specification enriched with the Google Cloud
+// API Gateway extensions describing the backends and the JWT issuers.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("gcpgateway", "") {
+		return files, nil
+	}
+	if len(expr.Root.Backends) == 0 {
+		return files, nil
+	}
+	root := goaexpr.Root
+	if len(root.API.HTTP.Services) == 0 {
+		return files, nil
+	}
+	// Build a new specification so that the extensions do not leak into
+	// the OpenAPI files generated by goa.
+	spec, err := openapi.NewV2(root, root.API.Servers[0].Hosts[0])
+	if err != nil {
+		return nil, err
+	}
+	addBackends(spec)
+	addIssuers(spec)
+	path := filepath.Join(codegen.Gendir, "http", "openapi_gcpgateway.json")
+	return append(files, stream.File(path, "gcpgateway-openapi", spec, stream.JSON)), nil
+}
+
+// addBackends sets the backend extension of the operations whose method has a
+// backend.
+func addBackends(spec *openapi.V2) {
+	walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+		if !config.EnabledOperation("gcpgateway", op) {
+			return nil
+		}
+		m := walk.OperationMethod(goaexpr.Root, op)
+		if m == nil {
+			return nil
+		}
+		b := expr.Root.Backend(m)
+		if b == nil {
+			return nil
+		}
+		if op.Extensions == nil {
+			op.Extensions = make(map[string]interface{})
+		}
+		op.Extensions[backendExt] = backend(b)
+		return nil
+	})
+}
+
+// backend returns the backend extension of an operation.
+func backend(b *expr.BackendExpr) map[string]interface{} {
+	ext := map[string]interface{}{
+		"address":          b.Address,
+		"path_translation": b.PathTranslation,
+	}
+	if b.Deadline > 0 {
+		ext["deadline"] = b.Deadline
+	}
+	if b.JWTAudience != "" {
+		ext["jwt_audience"] = b.JWTAudience
+	}
+	if b.DisableAuth {
+		ext["disable_auth"] = true
+	}
+	return ext
+}
+
+// addIssuers turns the security definitions of the JWT and OAuth2 schemes with
+// an issuer into the OAuth2 definitions validated by the gateway. The gateway
+// only validates JWTs so the other security definitions are removed. The
+// operations that accept requests secured with these definitions are not
+// secured by the gateway, the backend authenticates the requests.
+func addIssuers(spec *openapi.V2) {
+	removed := make(map[string]bool)
+	for key, sd := range spec.SecurityDefinitions {
+		// goa uses the scheme name, location and parameter name as key.
+		var is *expr.IssuerExpr
+		for _, i := range expr.Root.Issuers {
+			if strings.HasPrefix(key, i.Scheme+"_") {
+				is = i
+				break
+			}
+		}
+		if is == nil {
+			removed[key] = true
+			delete(spec.SecurityDefinitions, key)
+			continue
+		}
+		issue(sd, is)
+	}
+	walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+		var reqs []map[string][]string
+		for _, req := range op.Security {
+			r := make(map[string][]string)
+			for key := range req {
+				if !removed[key] {
+					// The gateway does not check the scopes,
+					// the backend does.
+					r[key] = []string{}
+				}
+			}
+			if len(r) == 0 {
+				// The requests satisfying this requirement
+				// must reach the backend unauthenticated.
+				reqs = nil
+				break
+			}
+			reqs = append(reqs, r)
+		}
+		op.Security = reqs
+		return nil
+	})
+	if len(spec.SecurityDefinitions) == 0 {
+		spec.SecurityDefinitions = nil
+	}
+}
+
+// issue turns the security definition into an OAuth2 definition validating the
+// tokens of the given issuer.
+func issue(sd *openapi.SecurityDefinition, is *expr.IssuerExpr) {
+	ext := sd.Extensions
+	if ext == nil {
+		ext = make(map[string]interface{})
+	}
+	*sd = openapi.SecurityDefinition{
+		Type:        "oauth2",
+		Description: sd.Description,
+		Flow:        "implicit",
+		Extensions:  ext,
+	}
+	// The gateway requires the authorization URL of the implicit flow
+	// even though it does not use it.
+	ext["authorizationUrl"] = ""
+	ext[issuerExt] = is.Issuer
+	ext[jwksURIExt] = is.JWKSURI
+	if len(is.Audiences) > 0 {
+		ext[audiencesExt] = strings.Join(is.Audiences, ",")
+	}
+}
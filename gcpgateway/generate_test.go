@@ -0,0 +1,119 @@
+package gcpgateway_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/gcpgateway"
+	"goa.design/plugins/v3/gcpgateway/expr"
+	"goa.design/plugins/v3/gcpgateway/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	expr.Root.Backends = nil
+	expr.Root.Issuers = nil
+	codegen.RunDSLWithFunc(t, testdata.BackendsDSL, func() {
+		eval.Register(expr.Root)
+	})
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := gcpgateway.Generate("", []eval.Root{goaexpr.Root}, ofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 3 {
+		t.Fatalf("got %d files, expected 3", len(fs))
+	}
+	if _, ok := ofs[0].SectionTemplates[0].Data.(*openapi.V2).Paths["/add/{a}/{b}"].(*openapi.Path).Get.Extensions["x-google-backend"]; ok {
+		t.Error("backend extension leaked into the goa OpenAPI specification")
+	}
+	spec := fs[2].SectionTemplates[0].Data.(*openapi.V2)
+
+	add := spec.Paths["/add/{a}/{b}"].(*openapi.Path).Get
+	assertJSON(t, "add backend", add.Extensions["x-google-backend"], `{
+		"address": "https://calc-abcdef-ew.a.run.app",
+		"path_translation": "APPEND_PATH_TO_ADDRESS"
+	}`)
+	assertJSON(t, "add security", add.Security, `[{"jwt_header_Authorization": []}]`)
+	reset := spec.Paths["/reset"].(*openapi.Path).Post
+	assertJSON(t, "reset backend", reset.Extensions["x-google-backend"], `{
+		"address": "https://europe-west1-calc.cloudfunctions.net/reset",
+		"path_translation": "CONSTANT_ADDRESS",
+		"deadline": 5,
+		"disable_auth": true
+	}`)
+	if len(reset.Security) != 0 {
+		t.Errorf("got reset security %v, expected none", reset.Security)
+	}
+
+	if len(spec.SecurityDefinitions) != 1 {
+		t.Fatalf("got %d security definitions, expected 1", len(spec.SecurityDefinitions))
+	}
+	assertJSON(t, "jwt security definition", spec.SecurityDefinitions["jwt_header_Authorization"], `{
+		"type": "oauth2",
+		"description": "\n**Security Scopes**:\n  * `+"`calc:write`"+`: no description",
+		"flow": "implicit",
+		"authorizationUrl": "",
+		"x-google-issuer": "https://auth.example.com",
+		"x-google-jwks_uri": "https://auth.example.com/.well-known/jwks.json",
+		"x-google-audiences": "calc,calc-admin"
+	}`)
+}
+
+func TestInvalidDSL(t *testing.T) {
+	expr.Root.Backends = nil
+	expr.Root.Issuers = nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.GeneratedTypes = &goaexpr.GeneratedRoot{}
+	eval.Register(goaexpr.Root)
+	eval.Register(goaexpr.Root.GeneratedTypes)
+	eval.Register(expr.Root)
+	var err error
+	if eval.Execute(testdata.InvalidIssuerDSL, nil) {
+		err = eval.RunDSL()
+	} else {
+		err = eval.Context.Errors
+	}
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, msg := range []string{
+		`invalid backend address "calc.example.com"`,
+		`security scheme "basic" must be a JWT or OAuth2 scheme`,
+		`invalid JWKS URI "http://auth.example.com/jwks.json"`,
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("got error %q, expected it to contain %q", err, msg)
+		}
+	}
+}
+
+// assertJSON compares the JSON representation of v with expected.
+func assertJSON(t *testing.T, name string, v interface{}, expected string) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, exp interface{}
+	json.Unmarshal(b, &got)
+	if err := json.Unmarshal([]byte(expected), &exp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("invalid %s, got %s", name, b)
+	}
+}
@@ -0,0 +1,60 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	gcpgateway "goa.design/plugins/v3/gcpgateway/dsl"
+)
+
+var BackendsDSL = func() {
+	var JWTAuth = JWTSecurity("jwt", func() {
+		Scope("calc:write")
+	})
+	var BasicAuth = BasicAuthSecurity("basic")
+	API("calc", func() {
+		gcpgateway.Backend("https://calc-abcdef-ew.a.run.app")
+		gcpgateway.Issuer(JWTAuth, "https://auth.example.com", "https://auth.example.com/.well-known/jwks.json", func() {
+			gcpgateway.Audiences("calc", "calc-admin")
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Security(JWTAuth, func() {
+				Scope("calc:write")
+			})
+			Payload(func() {
+				Token("token", String)
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+		Method("reset", func() {
+			Security(JWTAuth)
+			Security(BasicAuth)
+			Payload(func() {
+				Token("token", String)
+				Username("user", String)
+				Password("pass", String)
+			})
+			gcpgateway.Backend("https://europe-west1-calc.cloudfunctions.net/reset", func() {
+				gcpgateway.ConstantAddress()
+				gcpgateway.Deadline(5)
+				gcpgateway.DisableAuth()
+			})
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+	})
+}
+
+var InvalidIssuerDSL = func() {
+	var BasicAuth = BasicAuthSecurity("basic")
+	API("calc", func() {
+		gcpgateway.Backend("calc.example.com")
+		gcpgateway.Issuer(BasicAuth, "https://auth.example.com", "http://auth.example.com/jwks.json")
+	})
+}
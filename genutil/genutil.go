@@ -0,0 +1,79 @@
+package genutil
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// DurationCode returns the Go expression of the given duration using the
+// largest unit of the time package that divides it, e.g. "5 * time.Second" or
+// "time.Hour".
+func DurationCode(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	units := []struct {
+		Unit time.Duration
+		Name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.Unit == 0 {
+			if d == u.Unit {
+				return u.Name
+			}
+			return fmt.Sprintf("%d * %s", d/u.Unit, u.Name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
+// ExamplePath returns the request path of the given route pattern of the
+// given HTTP endpoint with the wildcards replaced by the examples of the path
+// parameters.
+func ExamplePath(r *expr.RootExpr, e *expr.HTTPEndpointExpr, pattern string) string {
+	values := make(map[string]string)
+	params := e.PathParams()
+	if expr.AsObject(params.Type) != nil {
+		expr.WalkMappedAttr(params, func(name, elem string, att *expr.AttributeExpr) error {
+			values[elem] = fmt.Sprint(att.Example(r.API.Random()))
+			return nil
+		})
+	}
+	return expr.HTTPWildcardRegex.ReplaceAllStringFunc(pattern, func(w string) string {
+		name := expr.HTTPWildcardRegex.FindStringSubmatch(w)[1]
+		return "/" + url.PathEscape(values[name])
+	})
+}
+
+// UserType returns the user or result type whose DSL is being executed given
+// the current expression, nil if the expression does not define a type. The
+// goa DSL runs the Type functions with the type attribute as current
+// expression.
+func UserType(current eval.Expression) expr.UserType {
+	switch actual := current.(type) {
+	case *expr.ResultTypeExpr:
+		return actual
+	case *expr.AttributeExpr:
+		for _, ut := range expr.Root.Types {
+			if ut.Attribute() == actual {
+				return ut
+			}
+		}
+		for _, rt := range expr.Root.ResultTypes {
+			if rt.Attribute() == actual {
+				return rt
+			}
+		}
+	}
+	return nil
+}
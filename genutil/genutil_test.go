@@ -0,0 +1,54 @@
+package genutil_test
+
+import (
+	"testing"
+	"time"
+
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/genutil/testdata"
+)
+
+func TestDurationCode(t *testing.T) {
+	cases := []struct {
+		Duration time.Duration
+		Code     string
+	}{
+		{0, "0"},
+		{time.Hour, "time.Hour"},
+		{2 * time.Hour, "2 * time.Hour"},
+		{90 * time.Second, "90 * time.Second"},
+		{1500 * time.Millisecond, "1500 * time.Millisecond"},
+		{3 * time.Microsecond, "3 * time.Microsecond"},
+		{5, "time.Duration(5)"},
+	}
+	for _, c := range cases {
+		if got := genutil.DurationCode(c.Duration); got != c.Code {
+			t.Errorf("%s: got %q, expected %q", c.Duration, got, c.Code)
+		}
+	}
+}
+
+func TestExamplePath(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.GenutilDSL)
+	e := expr.Root.API.HTTP.Service("pets").Endpoint("show")
+	if got := genutil.ExamplePath(expr.Root, e, e.Routes[0].FullPaths()[0]); got != "/owners/ann%20lee/pets/42" {
+		t.Errorf("got path %q, expected /owners/ann%%20lee/pets/42", got)
+	}
+}
+
+func TestUserType(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.GenutilDSL)
+	pet := expr.Root.UserType("Pet")
+	if got := genutil.UserType(pet.Attribute()); got != pet {
+		t.Errorf("got %v, expected the Pet type", got)
+	}
+	rt := expr.Root.UserType("PetResult")
+	if got := genutil.UserType(rt); got != rt {
+		t.Errorf("got %v, expected the PetResult result type", got)
+	}
+	if got := genutil.UserType(&expr.AttributeExpr{Type: expr.String}); got != nil {
+		t.Errorf("got %v, expected no type", got)
+	}
+}
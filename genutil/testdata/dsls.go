@@ -0,0 +1,36 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var GenutilDSL = func() {
+	var Pet = Type("Pet", func() {
+		Attribute("name", String)
+	})
+	var PetResult = ResultType("application/vnd.pet", func() {
+		Attribute("name", String)
+	})
+	Service("pets", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("owner", String, func() {
+					Example("ann lee")
+				})
+				Attribute("id", Int, func() {
+					Example(42)
+				})
+			})
+			Result(PetResult)
+			HTTP(func() {
+				GET("/owners/{owner}/pets/{id}")
+			})
+		})
+		Method("create", func() {
+			Payload(Pet)
+			HTTP(func() {
+				POST("/pets")
+			})
+		})
+	})
+}
@@ -1,14 +1,48 @@
 module goa.design/plugins/v3
 
-go 1.12
+go 1.21
 
 require (
+	github.com/fxamacker/cbor/v2 v2.5.0
+	github.com/getkin/kin-openapi v0.120.0
 	github.com/go-kit/kit v0.8.0
+	github.com/go-webauthn/webauthn v0.9.4
+	github.com/golang/protobuf v1.5.2
+	github.com/manveru/faker v0.0.0-20171103152722-9fbc68a78c4d
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	go.uber.org/zap v1.10.0
+	goa.design/goa/v3 v3.0.2
+	golang.org/x/crypto v0.16.0
+	gopkg.in/yaml.v2 v2.2.2
+)
+
+require (
+	github.com/dimfeld/httppath v0.0.0-20170720192232-ee938bf73598 // indirect
+	github.com/dimfeld/httptreemux v5.0.1+incompatible // indirect
 	github.com/go-logfmt/logfmt v0.4.0 // indirect
+	github.com/go-openapi/jsonpointer v0.19.6 // indirect
+	github.com/go-openapi/swag v0.22.4 // indirect
 	github.com/go-stack/stack v1.8.0 // indirect
+	github.com/go-webauthn/x v0.1.5 // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.0 // indirect
+	github.com/google/go-tpm v0.9.0 // indirect
+	github.com/google/uuid v1.4.0 // indirect
+	github.com/gorilla/mux v1.8.0 // indirect
+	github.com/gorilla/websocket v1.4.0 // indirect
+	github.com/invopop/yaml v0.2.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
+	github.com/perimeterx/marshmallow v1.1.5 // indirect
+	github.com/sergi/go-diff v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	github.com/zach-klippenstein/goregen v0.0.0-20160303162051-795b5e3961ea // indirect
 	go.uber.org/atomic v1.4.0 // indirect
 	go.uber.org/multierr v1.1.0 // indirect
-	go.uber.org/zap v1.10.0
-	goa.design/goa/v3 v3.0.2
+	golang.org/x/sys v0.15.0 // indirect
 	golang.org/x/tools v0.0.0-20190523174634-38d8bcfa38af // indirect
+	google.golang.org/protobuf v1.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -9,6 +9,7 @@ import (
 	"goa.design/goa/v3/eval"
 	"goa.design/goa/v3/expr"
 	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
 )
 
 // Register the plugin Generator functions.
@@ -18,12 +19,17 @@ func init() {
 	codegen.RegisterPluginLast("goakit-goakitify-example", "example", nil, GoakitifyExample)
 }
 
-// Generate generates go-kit specific decoders and encoders.
+// Generate generates go-kit specific decoders, encoders and endpoint
+// middlewares.
 func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("goakit", "") {
+		return files, nil
+	}
 	for _, root := range roots {
 		if r, ok := root.(*expr.RootExpr); ok {
 			files = append(files, EncodeDecodeFiles(genpkg, r)...)
 			files = append(files, MountFiles(r)...)
+			files = append(files, MiddlewareFiles(r)...)
 		}
 	}
 	return files, nil
@@ -35,6 +41,9 @@ func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codeg
 // * "log.Logger" with "github.com/go-kit/kit/log".Logger
 // and adding the corresponding imports.
 func Goakitify(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("goakit", "") {
+		return files, nil
+	}
 	for _, f := range files {
 		goakitify(f)
 	}
@@ -44,6 +53,9 @@ func Goakitify(genpkg string, roots []eval.Root, files []*codegen.File) ([]*code
 // GoakitifyExample  modifies all the previously generated example files by
 // adding go-kit imports.
 func GoakitifyExample(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("goakit", "") {
+		return files, nil
+	}
 	for _, f := range files {
 		gokitifyExampleServer(genpkg, f)
 	}
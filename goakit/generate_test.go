@@ -18,8 +18,8 @@ func TestGenerate(t *testing.T) {
 		DSL      func()
 		ExpFiles int
 	}{
-		"multi-endpoints": {testdata.MultiEndpointDSL, 3},
-		"multi-services":  {testdata.MultiServiceDSL, 6},
+		"multi-endpoints": {testdata.MultiEndpointDSL, 4},
+		"multi-services":  {testdata.MultiServiceDSL, 8},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -0,0 +1,110 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/expr"
+)
+
+// MiddlewareFiles produces the files containing the go-kit logging and
+// instrumentation endpoint middlewares of the services.
+func MiddlewareFiles(root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.Services {
+		if len(svc.Methods) == 0 {
+			continue
+		}
+		fw = append(fw, middlewareFile(svc))
+	}
+	return fw
+}
+
+// middlewareFile returns the file defining the go-kit endpoint middlewares for
+// the given service.
+func middlewareFile(svc *expr.ServiceExpr) *codegen.File {
+	data := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(data.VarName), "kitmiddleware.go")
+	title := fmt.Sprintf("%s go-kit endpoint middlewares", svc.Name)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, data.PkgName, []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "fmt"},
+			{Path: "time"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/metrics"},
+		}),
+		{
+			Name:   "goakit-logging-middleware",
+			Source: loggingMiddlewareT,
+			Data:   data,
+		},
+		{
+			Name:   "goakit-instrumenting-middleware",
+			Source: instrumentingMiddlewareT,
+			Data:   data,
+		},
+		{
+			Name:   "goakit-log-endpoints",
+			Source: logEndpointsT,
+			Data:   data,
+		},
+		{
+			Name:   "goakit-instrument-endpoints",
+			Source: instrumentEndpointsT,
+			Data:   data,
+		},
+	}
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: service.Data
+const loggingMiddlewareT = `{{ printf "LoggingMiddleware returns a go-kit endpoint middleware that logs the name of the %q service method, the duration of the request and the error if any." .Name | comment }}
+func LoggingMiddleware(logger log.Logger, method string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				logger.Log("service", {{ printf "%q" .Name }}, "method", method, "took", time.Since(begin), "err", err)
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+// input: service.Data
+const instrumentingMiddlewareT = `{{ printf "InstrumentingMiddleware returns a go-kit endpoint middleware that counts the requests made to the %q service method and records their duration in seconds. The metrics are labeled with the method name and whether the request succeeded." .Name | comment }}
+func InstrumentingMiddleware(requests metrics.Counter, duration metrics.Histogram, method string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				lvs := []string{"method", method, "success", fmt.Sprint(err == nil)}
+				requests.With(lvs...).Add(1)
+				duration.With(lvs...).Observe(time.Since(begin).Seconds())
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+// input: service.Data
+const logEndpointsT = `{{ printf "LogEndpoints wraps the endpoints of the %q service with the logging middleware." .Name | comment }}
+func LogEndpoints(e *Endpoints, logger log.Logger) {
+{{- range .Methods }}
+	e.{{ .VarName }} = LoggingMiddleware(logger, {{ printf "%q" .Name }})(e.{{ .VarName }})
+{{- end }}
+}
+`
+
+// input: service.Data
+const instrumentEndpointsT = `{{ printf "InstrumentEndpoints wraps the endpoints of the %q service with the instrumenting middleware." .Name | comment }}
+func InstrumentEndpoints(e *Endpoints, requests metrics.Counter, duration metrics.Histogram) {
+{{- range .Methods }}
+	e.{{ .VarName }} = InstrumentingMiddleware(requests, duration, {{ printf "%q" .Name }})(e.{{ .VarName }})
+{{- end }}
+}
+`
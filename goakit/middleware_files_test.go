@@ -0,0 +1,45 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/goakit/testdata"
+)
+
+func TestMiddlewareFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL  func()
+		Code map[string][]string
+	}{
+		"multi-endpoints": {
+			DSL: testdata.MultiEndpointDSL,
+			Code: map[string][]string{
+				"goakit-logging-middleware":       []string{testdata.MultiEndpointLoggingMiddlewareCode},
+				"goakit-instrumenting-middleware": []string{testdata.MultiEndpointInstrumentingMiddlewareCode},
+				"goakit-log-endpoints":            []string{testdata.MultiEndpointLogEndpointsCode},
+				"goakit-instrument-endpoints":     []string{testdata.MultiEndpointInstrumentEndpointsCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			codegen.RunDSL(t, c.DSL)
+			fs := MiddlewareFiles(expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			for sec, secCode := range c.Code {
+				testCode(t, fs[0], sec, secCode)
+			}
+		})
+	}
+}
+
+func TestMiddlewareFilesNoMethod(t *testing.T) {
+	codegen.RunDSL(t, testdata.FileServerDSL)
+	if fs := MiddlewareFiles(expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
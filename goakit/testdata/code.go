@@ -329,3 +329,52 @@ var WithErrorServerInitCode = `func example() {
 	witherrorservicekitsvr.MountWithErrorMethodHandler(mux, withErrorServiceWithErrorMethodHandler)
 }
 `
+
+const MultiEndpointLoggingMiddlewareCode = `// LoggingMiddleware returns a go-kit endpoint middleware that logs the name of
+// the "MultiEndpointService" service method, the duration of the request and
+// the error if any.
+func LoggingMiddleware(logger log.Logger, method string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				logger.Log("service", "MultiEndpointService", "method", method, "took", time.Since(begin), "err", err)
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+const MultiEndpointInstrumentingMiddlewareCode = `// InstrumentingMiddleware returns a go-kit endpoint middleware that counts the
+// requests made to the "MultiEndpointService" service method and records their
+// duration in seconds. The metrics are labeled with the method name and
+// whether the request succeeded.
+func InstrumentingMiddleware(requests metrics.Counter, duration metrics.Histogram, method string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				lvs := []string{"method", method, "success", fmt.Sprint(err == nil)}
+				requests.With(lvs...).Add(1)
+				duration.With(lvs...).Observe(time.Since(begin).Seconds())
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+const MultiEndpointLogEndpointsCode = `// LogEndpoints wraps the endpoints of the "MultiEndpointService" service with
+// the logging middleware.
+func LogEndpoints(e *Endpoints, logger log.Logger) {
+	e.Endpoint1 = LoggingMiddleware(logger, "Endpoint1")(e.Endpoint1)
+	e.Endpoint2 = LoggingMiddleware(logger, "Endpoint2")(e.Endpoint2)
+}
+`
+
+const MultiEndpointInstrumentEndpointsCode = `// InstrumentEndpoints wraps the endpoints of the "MultiEndpointService"
+// service with the instrumenting middleware.
+func InstrumentEndpoints(e *Endpoints, requests metrics.Counter, duration metrics.Histogram) {
+	e.Endpoint1 = InstrumentingMiddleware(requests, duration, "Endpoint1")(e.Endpoint1)
+	e.Endpoint2 = InstrumentingMiddleware(requests, duration, "Endpoint2")(e.Endpoint2)
+}
+`
@@ -0,0 +1,155 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/gorm/expr"
+
+	// Register code generators for the GORM plugin
+	_ "goa.design/plugins/v3/gorm"
+)
+
+// Persist generates the GORM storage model of the type, the functions
+// converting the service type into the model and back and the SQL statements
+// creating the table. The table name
+// defaults to the snake case type name followed by "s" and the primary key
+// defaults to the "id" attribute.
+//
+// Persist must appear in a Type or ResultType expression.
+//
+// Persist accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import gorm "goa.design/plugins/v3/gorm/dsl"
+//
+//    var User = Type("User", func() {
+//        Attribute("id", String)
+//        Attribute("email", String)
+//        Attribute("name", String)
+//        gorm.Persist(func() {
+//            gorm.Table("accounts")
+//            gorm.UniqueIndex("idx_account_email", "email")
+//        })
+//    })
+//
+func Persist(fn ...func()) {
+	ut := genutil.UserType(eval.Current())
+	if ut == nil {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	if expr.Root.Model(ut.Name()) != nil {
+		eval.ReportError("type %q persisted twice", ut.Name())
+		return
+	}
+	m := &expr.ModelExpr{Type: ut}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], m) {
+			return
+		}
+	}
+	expr.Root.Models = append(expr.Root.Models, m)
+}
+
+// Table sets the name of the table storing the records.
+//
+// Table must appear in a Persist expression.
+func Table(name string) {
+	if m, ok := eval.Current().(*expr.ModelExpr); ok {
+		m.Table = name
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Key sets the attributes making up the primary key.
+//
+// Key must appear in a Persist expression.
+//
+// Example:
+//
+//    gorm.Persist(func() {
+//        gorm.Key("tenant_id", "id")
+//    })
+//
+func Key(attributes ...string) {
+	if m, ok := eval.Current().(*expr.ModelExpr); ok {
+		m.Keys = attributes
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Index adds an index on the given attributes.
+//
+// Index must appear in a Persist expression.
+func Index(name string, attributes ...string) {
+	index(name, attributes, false)
+}
+
+// UniqueIndex adds a unique index on the given attributes.
+//
+// UniqueIndex must appear in a Persist expression.
+func UniqueIndex(name string, attributes ...string) {
+	index(name, attributes, true)
+}
+
+// ForeignKey adds a foreign key constraint: the given attributes reference the
+// primary key of the persisted type ref. The constraint appears in the
+// generated SQL migrations.
+//
+// ForeignKey must appear in a Persist expression.
+//
+// Example:
+//
+//    var Order = Type("Order", func() {
+//        Attribute("id", String)
+//        Attribute("user_id", String)
+//        gorm.Persist(func() {
+//            gorm.ForeignKey(User, "user_id")
+//        })
+//    })
+//
+func ForeignKey(ref goaexpr.UserType, attributes ...string) {
+	m, ok := eval.Current().(*expr.ModelExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if ref == nil {
+		eval.ReportError("foreign key references no type")
+		return
+	}
+	if len(attributes) == 0 {
+		eval.ReportError("foreign key referencing %q has no attribute", ref.Name())
+		return
+	}
+	m.ForeignKeys = append(m.ForeignKeys, &expr.ForeignKeyExpr{
+		Fields:    attributes,
+		Reference: ref,
+	})
+}
+
+// index adds an index to the current storage model.
+func index(name string, attributes []string, unique bool) {
+	m, ok := eval.Current().(*expr.ModelExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(attributes) == 0 {
+		eval.ReportError("index %q has no attribute", name)
+		return
+	}
+	m.Indexes = append(m.Indexes, &expr.IndexExpr{
+		Name:   name,
+		Fields: attributes,
+		Unique: unique,
+	})
+}
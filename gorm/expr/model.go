@@ -0,0 +1,110 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// ModelExpr describes the storage model of a user type.
+	ModelExpr struct {
+		// Type is the persisted user type.
+		Type expr.UserType
+		// Table is the name of the table storing the records.
+		Table string
+		// Keys lists the names of the attributes making up the primary
+		// key.
+		Keys []string
+		// Indexes lists the table indexes.
+		Indexes []*IndexExpr
+		// ForeignKeys lists the foreign key constraints.
+		ForeignKeys []*ForeignKeyExpr
+	}
+
+	// IndexExpr describes a table index.
+	IndexExpr struct {
+		// Name is the index name.
+		Name string
+		// Fields lists the names of the indexed attributes.
+		Fields []string
+		// Unique is true if the index is a unique index.
+		Unique bool
+	}
+
+	// ForeignKeyExpr describes a foreign key constraint.
+	ForeignKeyExpr struct {
+		// Fields lists the names of the referencing attributes.
+		Fields []string
+		// Reference is the persisted type whose primary key the
+		// attributes reference.
+		Reference expr.UserType
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (m *ModelExpr) EvalName() string {
+	return fmt.Sprintf("storage model of type %q", m.Type.Name())
+}
+
+// Prepare sets the default table name and primary key.
+func (m *ModelExpr) Prepare() {
+	if m.Table == "" {
+		m.Table = codegen.SnakeCase(m.Type.Name()) + "s"
+	}
+	if len(m.Keys) == 0 {
+		if o := expr.AsObject(m.Type.Attribute().Type); o != nil && o.Attribute("id") != nil {
+			m.Keys = []string{"id"}
+		}
+	}
+}
+
+// Validate ensures the storage model is valid.
+func (m *ModelExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	o := expr.AsObject(m.Type.Attribute().Type)
+	if o == nil {
+		verr.Add(m, "persisted type must be an object")
+		return verr
+	}
+	if len(m.Keys) == 0 {
+		verr.Add(m, "primary key is not defined and type has no \"id\" attribute")
+	}
+	check := func(kind, name string) {
+		att := o.Attribute(name)
+		if att == nil {
+			verr.Add(m, "%s attribute %q is not defined", kind, name)
+			return
+		}
+		if _, ok := att.Type.(expr.Primitive); !ok {
+			verr.Add(m, "%s attribute %q must be a primitive", kind, name)
+		}
+	}
+	for _, k := range m.Keys {
+		check("key", k)
+	}
+	for _, idx := range m.Indexes {
+		for _, f := range idx.Fields {
+			check(fmt.Sprintf("index %q", idx.Name), f)
+		}
+	}
+	for _, fk := range m.ForeignKeys {
+		for _, f := range fk.Fields {
+			check("foreign key", f)
+		}
+		ref := Root.Model(fk.Reference.Name())
+		if ref == nil {
+			verr.Add(m, "foreign key references type %q which is not persisted", fk.Reference.Name())
+			continue
+		}
+		if len(ref.Keys) != len(fk.Fields) {
+			verr.Add(m, "foreign key has %d attributes but the primary key of type %q has %d", len(fk.Fields), fk.Reference.Name(), len(ref.Keys))
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the persisted types defined in the design.
+	RootExpr struct {
+		// Models lists the storage models in the order they appear in
+		// the design.
+		Models []*ModelExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "GORM plugin"
+}
+
+// WalkSets iterates over the storage models.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	mexps := make(eval.ExpressionSet, len(r.Models))
+	for i, m := range r.Models {
+		mexps[i] = m
+	}
+	walk(mexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/gorm/dsl"}
+}
+
+// Model returns the storage model of the type with the given name, nil if the
+// type is not persisted.
+func (r *RootExpr) Model(typeName string) *ModelExpr {
+	for _, m := range r.Models {
+		if m.Type.Name() == typeName {
+			return m
+		}
+	}
+	return nil
+}
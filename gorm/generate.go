@@ -0,0 +1,201 @@
+package gorm
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/gorm/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// modelData contains the data necessary to render a storage model.
+	modelData struct {
+		// Name is the name of the model struct.
+		Name string
+		// TypeName is the name of the design type.
+		TypeName string
+		// Table is the table name.
+		Table string
+		// Fields lists the model fields.
+		Fields []*fieldData
+		// ServiceRef is the reference to the service type the model
+		// converts from and to, empty if no service uses the type.
+		ServiceRef string
+		// ServiceName is the name of the service type, empty if no
+		// service uses the type.
+		ServiceName string
+	}
+
+	// fieldData describes a model field.
+	fieldData struct {
+		// Name is the field name.
+		Name string
+		// Type is the field type.
+		Type string
+		// Tag is the GORM struct tag value.
+		Tag string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "gorm",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the GORM storage models of the persisted types, the
+// functions converting the service types into the models and back and the SQL
+// migrations creating the tables.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("gorm", "") {
+		return files, nil
+	}
+	if len(expr.Root.Models) == 0 {
+		return files, nil
+	}
+	var (
+		imports  []*codegen.ImportSpec
+		sections []*codegen.SectionTemplate
+		seen     = make(map[string]struct{})
+	)
+	for _, m := range expr.Root.Models {
+		data := buildModelData(m)
+		if sd := serviceUsing(m.Type); sd != nil {
+			data.ServiceRef = sd.Scope.GoFullTypeRef(&goaexpr.AttributeExpr{Type: m.Type}, sd.PkgName)
+			data.ServiceName = sd.Scope.GoFullTypeName(&goaexpr.AttributeExpr{Type: m.Type}, sd.PkgName)
+			if _, ok := seen[sd.Name]; !ok {
+				seen[sd.Name] = struct{}{}
+				imports = append(imports, &codegen.ImportSpec{
+					Path: genpkg + "/" + codegen.SnakeCase(sd.VarName),
+					Name: sd.PkgName,
+				})
+			}
+		}
+		sections = append(sections, &codegen.SectionTemplate{Name: "gorm-model", Source: modelT, Data: data})
+		if data.ServiceRef != "" {
+			sections = append(sections, &codegen.SectionTemplate{Name: "gorm-converters", Source: convertersT, Data: data})
+		}
+	}
+	sections = append([]*codegen.SectionTemplate{codegen.Header("GORM storage models", "models", imports)}, sections...)
+	files = append(files, &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "models", "models.go"),
+		SectionTemplates: sections,
+	})
+	return append(files, sqlFiles(expr.Root.Models)...), nil
+}
+
+// buildModelData computes the data necessary to render the given storage
+// model. Only the primitive attributes of the type are persisted.
+func buildModelData(m *expr.ModelExpr) *modelData {
+	att := m.Type.Attribute()
+	data := &modelData{
+		Name:     codegen.Goify(m.Type.Name(), true),
+		TypeName: m.Type.Name(),
+		Table:    m.Table,
+	}
+	for _, nat := range *goaexpr.AsObject(att.Type) {
+		if _, ok := nat.Attribute.Type.(goaexpr.Primitive); !ok {
+			continue
+		}
+		typ := codegen.GoNativeTypeName(nat.Attribute.Type)
+		if att.IsPrimitivePointer(nat.Name, true) {
+			typ = "*" + typ
+		}
+		tags := []string{"column:" + nat.Name}
+		if contains(m.Keys, nat.Name) {
+			tags = append(tags, "primaryKey")
+		}
+		for _, idx := range m.Indexes {
+			for _, f := range idx.Fields {
+				if f != nat.Name {
+					continue
+				}
+				if idx.Unique {
+					tags = append(tags, "uniqueIndex:"+idx.Name)
+				} else {
+					tags = append(tags, "index:"+idx.Name)
+				}
+			}
+		}
+		if att.IsRequired(nat.Name) {
+			tags = append(tags, "not null")
+		}
+		data.Fields = append(data.Fields, &fieldData{
+			Name: codegen.GoifyAtt(nat.Attribute, nat.Name, true),
+			Type: typ,
+			Tag:  fmt.Sprintf("`gorm:%q`", strings.Join(tags, ";")),
+		})
+	}
+	return data
+}
+
+// serviceUsing returns the data of the first service whose methods use the
+// given type, nil if there is none.
+func serviceUsing(ut goaexpr.UserType) *service.Data {
+	for _, svc := range goaexpr.Root.Services {
+		for _, m := range svc.Methods {
+			for _, att := range []*goaexpr.AttributeExpr{m.Payload, m.StreamingPayload, m.Result} {
+				if att != nil && uses(att, ut) {
+					return service.Services.Get(svc.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// uses returns true if the given attribute uses the user type.
+func uses(att *goaexpr.AttributeExpr, ut goaexpr.UserType) bool {
+	errFound := fmt.Errorf("found")
+	err := codegen.Walk(att, func(a *goaexpr.AttributeExpr) error {
+		if u, ok := a.Type.(goaexpr.UserType); ok && u.Name() == ut.Name() {
+			return errFound
+		}
+		return nil
+	})
+	return err == errFound
+}
+
+// input: modelData
+const modelT = `{{ printf "%s is the storage model of the %q type." .Name .TypeName | comment }}
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} {{ .Tag }}
+{{- end }}
+}
+
+// TableName returns the name of the table storing the records.
+func ({{ .Name }}) TableName() string {
+	return {{ printf "%q" .Table }}
+}
+`
+
+// input: modelData
+const convertersT = `{{ printf "New%s builds the storage model of the given service type." .Name | comment }}
+func New{{ .Name }}(v {{ .ServiceRef }}) *{{ .Name }} {
+	return &{{ .Name }}{
+	{{- range .Fields }}
+		{{ .Name }}: v.{{ .Name }},
+	{{- end }}
+	}
+}
+
+// ToService builds the service type of the storage model.
+func (m *{{ .Name }}) ToService() {{ .ServiceRef }} {
+	return &{{ .ServiceName }}{
+	{{- range .Fields }}
+		{{ .Name }}: m.{{ .Name }},
+	{{- end }}
+	}
+}
+`
@@ -0,0 +1,102 @@
+package gorm_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/gorm"
+	"goa.design/plugins/v3/gorm/expr"
+	"goa.design/plugins/v3/gorm/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code string
+	}{
+		{"default-model", testdata.DefaultModelDSL, testdata.DefaultModelCode},
+		{"custom-model", testdata.CustomModelDSL, testdata.CustomModelCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			service.Services = make(service.ServicesData)
+			expr.Root.Models = nil
+			root := codegen.RunDSLWithFunc(t, c.DSL, func() {
+				eval.Register(expr.Root)
+			})
+			fs, err := gorm.Generate("goa.design/plugins/v3/gorm/gen", []eval.Root{root}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != 3 {
+				t.Fatalf("got %d files, expected 3", len(fs))
+			}
+			var parts []string
+			for _, s := range fs[0].SectionTemplates[1:] {
+				parts = append(parts, codegen.SectionCode(t, s))
+			}
+			code := strings.Join(parts, "\n")
+			if code != c.Code {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code))
+			}
+		})
+	}
+}
+
+func TestGenerateSQL(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	expr.Root.Models = nil
+	root := codegen.RunDSLWithFunc(t, testdata.SQLDSL, func() {
+		eval.Register(expr.Root)
+	})
+	fs, err := gorm.Generate("goa.design/plugins/v3/gorm/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 3 {
+		t.Fatalf("got %d files, expected 3", len(fs))
+	}
+	for _, f := range fs[1:] {
+		plugintest.Golden(t, filepath.Base(f.Path), plugintest.Render(t, f))
+	}
+}
+
+func TestInvalidForeignKey(t *testing.T) {
+	expr.Root.Models = nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.GeneratedTypes = &goaexpr.GeneratedRoot{}
+	eval.Register(goaexpr.Root)
+	eval.Register(goaexpr.Root.GeneratedTypes)
+	eval.Register(expr.Root)
+	var err error
+	if eval.Execute(testdata.InvalidForeignKeyDSL, nil) {
+		err = eval.RunDSL()
+	} else {
+		err = eval.Context.Errors
+	}
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), `foreign key references type "Tenant" which is not persisted`) {
+		t.Errorf("got error %q, expected foreign key error", err)
+	}
+}
+
+func TestGenerateNoModel(t *testing.T) {
+	expr.Root.Models = nil
+	fs, err := gorm.Generate("", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
@@ -0,0 +1,319 @@
+package gorm
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/gorm/expr"
+)
+
+const (
+	// postgres is the name of the PostgreSQL dialect.
+	postgres = "postgres"
+	// mysql is the name of the MySQL dialect.
+	mysql = "mysql"
+)
+
+type (
+	// schemaData contains the data necessary to render the SQL statements
+	// creating the tables in a dialect.
+	schemaData struct {
+		// Tables lists the tables.
+		Tables []*tableData
+		// ForeignKeys lists the foreign key constraints, they are added
+		// once all the tables are created.
+		ForeignKeys []*foreignKeyData
+	}
+
+	// tableData describes a table.
+	tableData struct {
+		// Name is the quoted table name.
+		Name string
+		// Enums lists the enum types used by the columns.
+		Enums []*enumData
+		// Columns lists the table columns.
+		Columns []*columnData
+		// PrimaryKey is the comma separated list of the quoted primary
+		// key columns.
+		PrimaryKey string
+		// Indexes lists the table indexes.
+		Indexes []*sqlIndexData
+	}
+
+	// enumData describes an enum type.
+	enumData struct {
+		// Name is the quoted type name.
+		Name string
+		// Values is the comma separated list of the quoted values.
+		Values string
+	}
+
+	// columnData describes a column.
+	columnData struct {
+		// Name is the quoted column name.
+		Name string
+		// Type is the SQL type.
+		Type string
+		// Constraints lists the column constraints, e.g. " NOT NULL".
+		Constraints string
+	}
+
+	// sqlIndexData describes an index.
+	sqlIndexData struct {
+		// Name is the quoted index name.
+		Name string
+		// Unique is true if the index is a unique index.
+		Unique bool
+		// Columns is the comma separated list of the quoted columns.
+		Columns string
+	}
+
+	// foreignKeyData describes a foreign key constraint.
+	foreignKeyData struct {
+		// Table is the quoted name of the referencing table.
+		Table string
+		// Name is the quoted constraint name.
+		Name string
+		// Columns is the comma separated list of the quoted referencing
+		// columns.
+		Columns string
+		// Reference is the quoted name of the referenced table.
+		Reference string
+		// ReferenceColumns is the comma separated list of the quoted
+		// referenced columns.
+		ReferenceColumns string
+	}
+)
+
+// sqlFiles returns the files containing the SQL statements creating the tables
+// of the storage models, one per dialect.
+func sqlFiles(models []*expr.ModelExpr) []*codegen.File {
+	fs := make([]*codegen.File, 0, 2)
+	for _, d := range []string{postgres, mysql} {
+		fs = append(fs, &codegen.File{
+			Path: filepath.Join(codegen.Gendir, "models", "migrations", d+".sql"),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:   "gorm-sql-" + d,
+				Source: schemaT,
+				Data:   buildSchemaData(d, models),
+			}},
+		})
+	}
+	return fs
+}
+
+// buildSchemaData computes the data necessary to render the SQL statements
+// creating the tables of the given storage models in the given dialect.
+func buildSchemaData(dialect string, models []*expr.ModelExpr) *schemaData {
+	data := &schemaData{}
+	for _, m := range models {
+		att := m.Type.Attribute()
+		t := &tableData{
+			Name:       quoteIdent(dialect, m.Table),
+			PrimaryKey: quoteIdents(dialect, m.Keys),
+		}
+		for _, nat := range *goaexpr.AsObject(att.Type) {
+			if _, ok := nat.Attribute.Type.(goaexpr.Primitive); !ok {
+				continue
+			}
+			col := &columnData{
+				Name: quoteIdent(dialect, nat.Name),
+				Type: columnType(dialect, nat.Attribute),
+			}
+			if values := enumValues(nat.Attribute); len(values) > 0 {
+				switch dialect {
+				case postgres:
+					name := quoteIdent(dialect, m.Table+"_"+nat.Name)
+					t.Enums = append(t.Enums, &enumData{Name: name, Values: strings.Join(values, ", ")})
+					col.Type = name
+				case mysql:
+					col.Type = fmt.Sprintf("ENUM(%s)", strings.Join(values, ", "))
+				}
+			}
+			if att.IsRequired(nat.Name) || contains(m.Keys, nat.Name) {
+				col.Constraints += " NOT NULL"
+			}
+			if def := defaultValue(nat.Attribute); def != "" {
+				col.Constraints += " DEFAULT " + def
+			}
+			t.Columns = append(t.Columns, col)
+		}
+		for _, idx := range m.Indexes {
+			t.Indexes = append(t.Indexes, &sqlIndexData{
+				Name:    quoteIdent(dialect, idx.Name),
+				Unique:  idx.Unique,
+				Columns: quoteIdents(dialect, idx.Fields),
+			})
+		}
+		data.Tables = append(data.Tables, t)
+		for _, fk := range m.ForeignKeys {
+			ref := expr.Root.Model(fk.Reference.Name())
+			if ref == nil {
+				continue
+			}
+			data.ForeignKeys = append(data.ForeignKeys, &foreignKeyData{
+				Table:            t.Name,
+				Name:             quoteIdent(dialect, "fk_"+m.Table+"_"+strings.Join(fk.Fields, "_")),
+				Columns:          quoteIdents(dialect, fk.Fields),
+				Reference:        quoteIdent(dialect, ref.Table),
+				ReferenceColumns: quoteIdents(dialect, ref.Keys),
+			})
+		}
+	}
+	return data
+}
+
+// columnType returns the SQL type of the column storing the given primitive
+// attribute in the given dialect.
+func columnType(dialect string, att *goaexpr.AttributeExpr) string {
+	pg := dialect == postgres
+	switch att.Type.Kind() {
+	case goaexpr.BooleanKind:
+		return "BOOLEAN"
+	case goaexpr.Int32Kind:
+		return "INTEGER"
+	case goaexpr.IntKind, goaexpr.Int64Kind:
+		return "BIGINT"
+	case goaexpr.UInt32Kind:
+		if pg {
+			return "BIGINT"
+		}
+		return "INT UNSIGNED"
+	case goaexpr.UIntKind, goaexpr.UInt64Kind:
+		if pg {
+			return "NUMERIC(20)"
+		}
+		return "BIGINT UNSIGNED"
+	case goaexpr.Float32Kind:
+		if pg {
+			return "REAL"
+		}
+		return "FLOAT"
+	case goaexpr.Float64Kind:
+		if pg {
+			return "DOUBLE PRECISION"
+		}
+		return "DOUBLE"
+	case goaexpr.StringKind:
+		if v := att.Validation; v != nil {
+			if v.Format == goaexpr.FormatUUID {
+				if pg {
+					return "UUID"
+				}
+				return "CHAR(36)"
+			}
+			if v.MaxLength != nil {
+				return fmt.Sprintf("VARCHAR(%d)", *v.MaxLength)
+			}
+		}
+		if pg {
+			return "TEXT"
+		}
+		// MySQL cannot index TEXT columns without a prefix length.
+		return "VARCHAR(255)"
+	case goaexpr.BytesKind:
+		if pg {
+			return "BYTEA"
+		}
+		return "BLOB"
+	default:
+		if pg {
+			return "JSONB"
+		}
+		return "JSON"
+	}
+}
+
+// enumValues returns the quoted values of the Enum validation of the given
+// string attribute, nil if the attribute is not a string or has no such
+// validation.
+func enumValues(att *goaexpr.AttributeExpr) []string {
+	if att.Type.Kind() != goaexpr.StringKind || att.Validation == nil {
+		return nil
+	}
+	var values []string
+	for _, v := range att.Validation.Values {
+		if s, ok := v.(string); ok {
+			values = append(values, quoteString(s))
+		}
+	}
+	return values
+}
+
+// defaultValue returns the SQL literal of the default value of the given
+// attribute, the empty string if it has none.
+func defaultValue(att *goaexpr.AttributeExpr) string {
+	switch v := att.DefaultValue.(type) {
+	case nil:
+		return ""
+	case string:
+		return quoteString(v)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return ""
+	}
+}
+
+// quoteIdent returns the given identifier quoted for the given dialect.
+func quoteIdent(dialect, name string) string {
+	if dialect == mysql {
+		return "`" + strings.Replace(name, "`", "``", -1) + "`"
+	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// quoteIdents returns the comma separated list of the given identifiers quoted
+// for the given dialect.
+func quoteIdents(dialect string, names []string) string {
+	qs := make([]string, len(names))
+	for i, n := range names {
+		qs[i] = quoteIdent(dialect, n)
+	}
+	return strings.Join(qs, ", ")
+}
+
+// quoteString returns the SQL string literal of s.
+func quoteString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// contains returns true if names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// input: schemaData
+const schemaT = `{{ range $i, $t := .Tables }}{{ if $i }}
+{{ end }}
+{{- range .Enums }}CREATE TYPE {{ .Name }} AS ENUM ({{ .Values }});
+
+{{ end -}}
+CREATE TABLE {{ .Name }} (
+{{- range .Columns }}
+	{{ .Name }} {{ .Type }}{{ .Constraints }},
+{{- end }}
+	PRIMARY KEY ({{ .PrimaryKey }})
+);
+{{- range .Indexes }}
+
+CREATE {{ if .Unique }}UNIQUE {{ end }}INDEX {{ .Name }} ON {{ $t.Name }} ({{ .Columns }});
+{{- end }}
+{{ end }}
+{{- range .ForeignKeys }}
+ALTER TABLE {{ .Table }} ADD CONSTRAINT {{ .Name }} FOREIGN KEY ({{ .Columns }}) REFERENCES {{ .Reference }} ({{ .ReferenceColumns }});
+{{- end }}
+`
@@ -0,0 +1,74 @@
+package testdata
+
+var DefaultModelCode = `// User is the storage model of the "User" type.
+type User struct {
+	ID   string  ` + "`" + `gorm:"column:id;primaryKey;not null"` + "`" + `
+	Name *string ` + "`" + `gorm:"column:name"` + "`" + `
+	Age  int     ` + "`" + `gorm:"column:age"` + "`" + `
+}
+
+// TableName returns the name of the table storing the records.
+func (User) TableName() string {
+	return "users"
+}
+
+// NewUser builds the storage model of the given service type.
+func NewUser(v *users.User) *User {
+	return &User{
+		ID:   v.ID,
+		Name: v.Name,
+		Age:  v.Age,
+	}
+}
+
+// ToService builds the service type of the storage model.
+func (m *User) ToService() *users.User {
+	return &users.User{
+		ID:   m.ID,
+		Name: m.Name,
+		Age:  m.Age,
+	}
+}
+`
+
+var CustomModelCode = `// Audit is the storage model of the "Audit" type.
+type Audit struct {
+	ID     *string ` + "`" + `gorm:"column:id;primaryKey"` + "`" + `
+	Action *string ` + "`" + `gorm:"column:action;index:idx_audit_action"` + "`" + `
+}
+
+// TableName returns the name of the table storing the records.
+func (Audit) TableName() string {
+	return "audits"
+}
+
+// Account is the storage model of the "Account" type.
+type Account struct {
+	TenantID string ` + "`" + `gorm:"column:tenant_id;primaryKey;uniqueIndex:idx_account_email;not null"` + "`" + `
+	ID       int64  ` + "`" + `gorm:"column:id;primaryKey;not null"` + "`" + `
+	Email    string ` + "`" + `gorm:"column:email;uniqueIndex:idx_account_email;not null"` + "`" + `
+}
+
+// TableName returns the name of the table storing the records.
+func (Account) TableName() string {
+	return "accounts"
+}
+
+// NewAccount builds the storage model of the given service type.
+func NewAccount(v *accounts.Account) *Account {
+	return &Account{
+		TenantID: v.TenantID,
+		ID:       v.ID,
+		Email:    v.Email,
+	}
+}
+
+// ToService builds the service type of the storage model.
+func (m *Account) ToService() *accounts.Account {
+	return &accounts.Account{
+		TenantID: m.TenantID,
+		ID:       m.ID,
+		Email:    m.Email,
+	}
+}
+`
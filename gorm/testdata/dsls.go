@@ -0,0 +1,108 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	gorm "goa.design/plugins/v3/gorm/dsl"
+)
+
+var DefaultModelDSL = func() {
+	var User = Type("User", func() {
+		Attribute("id", String)
+		Attribute("name", String)
+		Attribute("age", Int, func() {
+			Default(18)
+		})
+		Required("id")
+		gorm.Persist()
+	})
+	Service("users", func() {
+		Method("create", func() {
+			Payload(User)
+		})
+	})
+}
+
+var CustomModelDSL = func() {
+	var Account = ResultType("application/vnd.account", func() {
+		TypeName("Account")
+		Attributes(func() {
+			Attribute("tenant_id", String)
+			Attribute("id", Int64)
+			Attribute("email", String)
+			Attribute("tags", ArrayOf(String))
+			Required("tenant_id", "id", "email")
+		})
+		gorm.Persist(func() {
+			gorm.Table("accounts")
+			gorm.Key("tenant_id", "id")
+			gorm.UniqueIndex("idx_account_email", "tenant_id", "email")
+		})
+	})
+	var Audit = Type("Audit", func() {
+		Attribute("id", String)
+		Attribute("action", String)
+		gorm.Persist(func() {
+			gorm.Index("idx_audit_action", "action")
+		})
+	})
+	_ = Audit
+	Service("accounts", func() {
+		Method("show", func() {
+			Payload(String)
+			Result(Account)
+		})
+	})
+}
+
+var SQLDSL = func() {
+	var User = Type("User", func() {
+		Attribute("id", String, func() {
+			Format(FormatUUID)
+		})
+		Attribute("email", String, func() {
+			MaxLength(320)
+		})
+		Attribute("role", String, func() {
+			Enum("admin", "member")
+			Default("member")
+		})
+		Attribute("active", Boolean, func() {
+			Default(true)
+		})
+		Required("id", "email")
+		gorm.Persist(func() {
+			gorm.UniqueIndex("idx_user_email", "email")
+		})
+	})
+	Type("Order", func() {
+		Attribute("id", Int64)
+		Attribute("user_id", String, func() {
+			Format(FormatUUID)
+		})
+		Attribute("total", Float64)
+		Attribute("quantity", UInt32)
+		Attribute("receipt", Bytes)
+		Attribute("status", String, func() {
+			Enum("pending", "shipped", "canceled")
+		})
+		Attribute("items", ArrayOf(String))
+		Required("user_id", "status")
+		gorm.Persist(func() {
+			gorm.Index("idx_order_user", "user_id", "status")
+			gorm.ForeignKey(User, "user_id")
+		})
+	})
+}
+
+var InvalidForeignKeyDSL = func() {
+	var Tenant = Type("Tenant", func() {
+		Attribute("id", String)
+	})
+	Type("Project", func() {
+		Attribute("id", String)
+		Attribute("tenant_id", String)
+		gorm.Persist(func() {
+			gorm.ForeignKey(Tenant, "tenant_id")
+		})
+	})
+}
@@ -0,0 +1,23 @@
+CREATE TABLE `users` (
+	`id` CHAR(36) NOT NULL,
+	`email` VARCHAR(320) NOT NULL,
+	`role` ENUM('admin', 'member') DEFAULT 'member',
+	`active` BOOLEAN DEFAULT TRUE,
+	PRIMARY KEY (`id`)
+);
+
+CREATE UNIQUE INDEX `idx_user_email` ON `users` (`email`);
+
+CREATE TABLE `orders` (
+	`id` BIGINT NOT NULL,
+	`user_id` CHAR(36) NOT NULL,
+	`total` DOUBLE,
+	`quantity` INT UNSIGNED,
+	`receipt` BLOB,
+	`status` ENUM('pending', 'shipped', 'canceled') NOT NULL,
+	PRIMARY KEY (`id`)
+);
+
+CREATE INDEX `idx_order_user` ON `orders` (`user_id`, `status`);
+
+ALTER TABLE `orders` ADD CONSTRAINT `fk_orders_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);
@@ -0,0 +1,27 @@
+CREATE TYPE "users_role" AS ENUM ('admin', 'member');
+
+CREATE TABLE "users" (
+	"id" UUID NOT NULL,
+	"email" VARCHAR(320) NOT NULL,
+	"role" "users_role" DEFAULT 'member',
+	"active" BOOLEAN DEFAULT TRUE,
+	PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "idx_user_email" ON "users" ("email");
+
+CREATE TYPE "orders_status" AS ENUM ('pending', 'shipped', 'canceled');
+
+CREATE TABLE "orders" (
+	"id" BIGINT NOT NULL,
+	"user_id" UUID NOT NULL,
+	"total" DOUBLE PRECISION,
+	"quantity" BIGINT,
+	"receipt" BYTEA,
+	"status" "orders_status" NOT NULL,
+	PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_order_user" ON "orders" ("user_id", "status");
+
+ALTER TABLE "orders" ADD CONSTRAINT "fk_orders_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
@@ -0,0 +1,62 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/graphql/expr"
+
+	// Register code generators for the GraphQL plugin
+	_ "goa.design/plugins/v3/graphql"
+)
+
+// Federation generates the gen/graphql/subgraph.graphql schema which makes the
+// services a subgraph of an Apollo Federation (version 2) supergraph. The
+// subgraph schema is the GraphQL schema of the services in which the types
+// declared as entities with Key have @key directives.
+//
+// Federation must appear in an API expression.
+//
+// Example:
+//
+//    import graphql "goa.design/plugins/v3/graphql/dsl"
+//
+//    var _ = API("catalog", func() {
+//        graphql.Federation()
+//    })
+//
+func Federation() {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Federation = true
+}
+
+// Key declares the enclosing type as an Apollo Federation entity identified by
+// the given attributes. The other subgraphs reference the entity using these
+// attributes. Key may be used multiple times to declare alternative keys.
+//
+// Key must appear in a Type or ResultType expression and requires Federation.
+//
+// Example:
+//
+//    var Product = ResultType("application/vnd.product", func() {
+//        graphql.Key("upc")
+//        graphql.Key("sku", "vendor")
+//        Attributes(func() {
+//            Attribute("upc", String)
+//            Attribute("sku", String)
+//            Attribute("vendor", String)
+//            Attribute("name", String)
+//        })
+//    })
+//
+func Key(fields ...string) {
+	ut := genutil.UserType(eval.Current())
+	if ut == nil {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Keys = append(expr.Root.Keys, &expr.KeyExpr{Fields: fields, UserType: ut})
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// KeyExpr describes a key identifying the instances of an Apollo
+	// Federation entity.
+	KeyExpr struct {
+		// Fields lists the names of the attributes making up the key.
+		Fields []string
+		// UserType is the user type describing the entity.
+		UserType expr.UserType
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (k *KeyExpr) EvalName() string {
+	return fmt.Sprintf("key %q of type %q", k.FieldSet(), k.UserType.Name())
+}
+
+// Validate makes sure the design enables federation and that the key
+// attributes exist.
+func (k *KeyExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if !Root.Federation {
+		verr.Add(k, "keys require Federation to be used in the API expression")
+	}
+	if len(k.Fields) == 0 {
+		verr.Add(k, "key must list at least one attribute")
+	}
+	obj := expr.AsObject(k.UserType.Attribute().Type)
+	if obj == nil {
+		verr.Add(k, "entity type must be an object")
+		return verr
+	}
+	for _, f := range k.Fields {
+		if obj.Attribute(f) == nil {
+			verr.Add(k, "key attribute %q not found", f)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// FieldSet returns the space separated list of the key attribute names.
+func (k *KeyExpr) FieldSet() string {
+	return strings.Join(k.Fields, " ")
+}
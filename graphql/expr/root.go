@@ -0,0 +1,64 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the Apollo Federation settings of the design.
+	RootExpr struct {
+		// Federation is true if the plugin generates the Apollo
+		// Federation subgraph schema.
+		Federation bool
+		// Keys lists the entity keys in the order they appear in the
+		// design.
+		Keys []*KeyExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "GraphQL plugin"
+}
+
+// WalkSets iterates over the entity keys.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	kexps := make(eval.ExpressionSet, len(r.Keys))
+	for i, k := range r.Keys {
+		kexps[i] = k
+	}
+	walk(kexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/graphql/dsl"}
+}
+
+// EntityKeys returns the keys of the entity described by the given user type,
+// nil if the type is not an entity.
+func (r *RootExpr) EntityKeys(ut expr.UserType) []*KeyExpr {
+	var keys []*KeyExpr
+	for _, k := range r.Keys {
+		if k.UserType.Name() == ut.Name() {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,347 @@
+package graphql
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	gqlexpr "goa.design/plugins/v3/graphql/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// resolverData contains the data necessary to render the resolver.
+	resolverData struct {
+		// Services lists the services exposed by the resolver.
+		Services []*serviceData
+	}
+
+	// serviceData describes the endpoints of a service.
+	serviceData struct {
+		// Name is the service name.
+		Name string
+		// VarName is the name of the resolver field holding the service
+		// endpoints.
+		VarName string
+		// PkgName is the name of the service package.
+		PkgName string
+		// Fields lists the fields resolved by the service methods.
+		Fields []*resolverFieldData
+	}
+
+	// resolverFieldData describes the resolution of a query or mutation
+	// field.
+	resolverFieldData struct {
+		// Name is the name of the resolver method.
+		Name string
+		// Field is the name of the GraphQL field.
+		Field string
+		// Root is "query" or "mutation".
+		Root string
+		// Description is the method description.
+		Description string
+		// Endpoint is the name of the endpoint in the service Endpoints
+		// struct.
+		Endpoint string
+		// PayloadRef is the reference to the payload type, empty if the
+		// method has no payload.
+		PayloadRef string
+		// ResultRef is the reference to the result type, empty if the
+		// method has no result.
+		ResultRef string
+		// ViewedResultRef is the reference to the viewed result type
+		// returned by the endpoint if the result is a result type.
+		ViewedResultRef string
+		// ResultInit is the name of the function that initializes the
+		// result from the viewed result.
+		ResultInit string
+	}
+)
+
+// init registers the plugin generator function.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "graphql",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the GraphQL schema describing the services and the
+// resolver which implements the queries and mutations by calling the service
+// endpoints. It also produces the Apollo Federation subgraph schema if the
+// design uses Federation.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			if len(r.Services) == 0 {
+				continue
+			}
+			schema, resolver := build(r, nil)
+			files = append(files, schemaFile(schema), resolverFile(genpkg, resolver))
+			if gqlexpr.Root.Federation {
+				subgraph, _ := build(r, entityKeys())
+				files = append(files, subgraphFile(subgraph))
+			}
+		}
+	}
+	return files, nil
+}
+
+// build computes the schema and resolver data from the design. keys maps the
+// names of the entity types to their @key directives.
+func build(r *expr.RootExpr, keys map[string][]string) (*schemaData, *resolverData) {
+	var (
+		b        = newSchemaBuilder(keys)
+		query    = &typeData{Kind: "type", Name: "Query"}
+		mutation = &typeData{Kind: "type", Name: "Mutation"}
+		resolver = &resolverData{}
+	)
+	for _, svc := range r.Services {
+		if !config.Enabled("graphql", svc.Name) {
+			continue
+		}
+		sd := service.Services.Get(svc.Name)
+		s := &serviceData{
+			Name:    svc.Name,
+			VarName: codegen.Goify(svc.Name, false) + "Endpoints",
+			PkgName: sd.PkgName,
+		}
+		for _, m := range svc.Methods {
+			if m.IsStreaming() {
+				continue // GraphQL subscriptions are not supported
+			}
+			md := sd.Method(m.Name)
+			prefix := codegen.Goify(svc.Name, true) + codegen.Goify(m.Name, true)
+			f := &fieldData{
+				Name:        codegen.Goify(svc.Name, false) + codegen.Goify(m.Name, true),
+				Description: m.Description,
+				Type:        "Boolean!",
+			}
+			rf := &resolverFieldData{
+				Name:        prefix,
+				Field:       f.Name,
+				Root:        "mutation",
+				Description: m.Description,
+				Endpoint:    md.VarName,
+			}
+			if m.Payload.Type != expr.Empty {
+				f.Args = []*fieldData{{
+					Name: "payload",
+					Type: b.typeRef(m.Payload, prefix+"Payload", true, true),
+				}}
+				rf.PayloadRef = sd.Scope.GoFullTypeRef(m.Payload, sd.PkgName)
+			}
+			if m.Result.Type != expr.Empty {
+				f.Type = b.typeRef(m.Result, prefix+"Result", false, true)
+				rf.ResultRef = sd.Scope.GoFullTypeRef(m.Result, sd.PkgName)
+				if vr := md.ViewedResult; vr != nil {
+					rf.ViewedResultRef = vr.FullRef
+					rf.ResultInit = sd.PkgName + "." + vr.ResultInit.Name
+				}
+			}
+			if isQuery(r, m) {
+				rf.Root = "query"
+				query.Fields = append(query.Fields, f)
+			} else {
+				mutation.Fields = append(mutation.Fields, f)
+			}
+			s.Fields = append(s.Fields, rf)
+		}
+		if len(s.Fields) > 0 {
+			resolver.Services = append(resolver.Services, s)
+		}
+	}
+	return b.schema(query, mutation), resolver
+}
+
+// isQuery returns true if the method is exposed as a GraphQL query, that is
+// if it is mapped to a GET HTTP request. All other methods are exposed as
+// mutations.
+func isQuery(r *expr.RootExpr, m *expr.MethodExpr) bool {
+	if r.API == nil || r.API.HTTP == nil {
+		return false
+	}
+	svc := r.API.HTTP.Service(m.Service.Name)
+	if svc == nil {
+		return false
+	}
+	e := svc.Endpoint(m.Name)
+	return e != nil && len(e.Routes) > 0 && e.Routes[0].Method == "GET"
+}
+
+// schemaFile returns the file containing the GraphQL schema.
+func schemaFile(data *schemaData) *codegen.File {
+	section := &codegen.SectionTemplate{
+		Name:    "graphql-schema",
+		FuncMap: map[string]interface{}{"description": description, "args": args},
+		Source:  schemaT,
+		Data:    data,
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "graphql", "schema.graphql"),
+		SectionTemplates: []*codegen.SectionTemplate{section},
+	}
+}
+
+// subgraphFile returns the file containing the Apollo Federation subgraph
+// schema.
+func subgraphFile(data *schemaData) *codegen.File {
+	section := &codegen.SectionTemplate{
+		Name:    "graphql-subgraph",
+		FuncMap: map[string]interface{}{"description": description, "args": args},
+		Source:  subgraphT + schemaT,
+		Data:    data,
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "graphql", "subgraph.graphql"),
+		SectionTemplates: []*codegen.SectionTemplate{section},
+	}
+}
+
+// entityKeys returns the @key directives of the entity types indexed by the
+// names of the GraphQL object types.
+func entityKeys() map[string][]string {
+	keys := make(map[string][]string)
+	for _, k := range gqlexpr.Root.Keys {
+		fields := make([]string, len(k.Fields))
+		for i, f := range k.Fields {
+			fields[i] = codegen.Goify(f, false)
+		}
+		name := codegen.Goify(k.UserType.Name(), true)
+		keys[name] = append(keys[name], fmt.Sprintf("@key(fields: %q)", strings.Join(fields, " ")))
+	}
+	return keys
+}
+
+// resolverFile returns the file implementing the resolver.
+func resolverFile(genpkg string, data *resolverData) *codegen.File {
+	imports := []*codegen.ImportSpec{{Path: "context"}}
+	for _, s := range data.Services {
+		sd := service.Services.Get(s.Name)
+		svcName := codegen.SnakeCase(sd.VarName)
+		imports = append(imports, &codegen.ImportSpec{Path: genpkg + "/" + svcName, Name: sd.PkgName})
+		for _, f := range s.Fields {
+			if f.ViewedResultRef != "" {
+				imports = append(imports, &codegen.ImportSpec{Path: genpkg + "/" + svcName + "/views", Name: sd.ViewsPkg})
+				break
+			}
+		}
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("GraphQL resolver", "graphql", imports),
+		{Name: "graphql-resolver", Source: resolverT, Data: data},
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "graphql", "resolver.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// description returns the GraphQL description block of the given text
+// indented with the given prefix, an empty string if text is empty.
+func description(text, indent string) string {
+	if text == "" {
+		return ""
+	}
+	text = strings.Replace(text, `"""`, `\"""`, -1)
+	if !strings.Contains(text, "\n") {
+		return fmt.Sprintf("%s\"\"\"%s\"\"\"\n", indent, text)
+	}
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = indent + l
+		}
+	}
+	return fmt.Sprintf("%s\"\"\"\n%s\n%s\"\"\"\n", indent, strings.Join(lines, "\n"), indent)
+}
+
+// args returns the GraphQL field arguments definition.
+func args(fs []*fieldData) string {
+	if len(fs) == 0 {
+		return ""
+	}
+	as := make([]string, len(fs))
+	for i, f := range fs {
+		as[i] = f.Name + ": " + f.Type
+	}
+	return "(" + strings.Join(as, ", ") + ")"
+}
+
+// input: schemaData
+const schemaT = `{{ range .Scalars }}scalar {{ . }}
+
+{{ end }}
+{{- define "type" }}{{ description .Description "" }}{{ .Kind }} {{ .Name }}{{ range .Directives }} {{ . }}{{ end }} {
+{{- range .Fields }}
+{{ description .Description "  " }}  {{ .Name }}{{ args .Args }}: {{ .Type }}
+{{- end }}
+}
+{{ end }}
+{{- with .Query }}{{ template "type" . }}
+{{ end }}
+{{- with .Mutation }}{{ template "type" . }}
+{{ end }}
+{{- range $i, $t := .Types }}{{ if $i }}
+{{ end }}{{ template "type" $t }}{{ end }}`
+
+// input: schemaData
+const subgraphT = `extend schema
+  @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])
+
+`
+
+// input: resolverData
+const resolverT = `// Resolver resolves the GraphQL queries and mutations by calling the service
+// endpoints.
+type Resolver struct {
+{{- range .Services }}
+	{{ .VarName }} *{{ .PkgName }}.Endpoints
+{{- end }}
+}
+
+// NewResolver returns a resolver which calls the given service endpoints.
+func NewResolver({{ range $i, $s := .Services }}{{ if $i }}, {{ end }}{{ .VarName }} *{{ .PkgName }}.Endpoints{{ end }}) *Resolver {
+	return &Resolver{
+	{{- range .Services }}
+		{{ .VarName }}: {{ .VarName }},
+	{{- end }}
+	}
+}
+{{- range $svc := .Services }}
+{{- range .Fields }}
+
+{{ printf "%s resolves the %q %s by calling the %q endpoint of the %q service." .Name .Field .Root .Endpoint $svc.Name | comment }}
+{{- if .Description }}
+//
+{{ comment .Description }}
+{{- end }}
+func (r *Resolver) {{ .Name }}(ctx context.Context{{ if .PayloadRef }}, p {{ .PayloadRef }}{{ end }}) ({{ if .ResultRef }}res {{ .ResultRef }}, err error{{ else }}bool, error{{ end }}) {
+{{- if .ResultRef }}
+	v, err := r.{{ $svc.VarName }}.{{ .Endpoint }}(ctx, {{ if .PayloadRef }}p{{ else }}nil{{ end }})
+	if err != nil {
+		return
+	}
+	{{- if .ViewedResultRef }}
+	return {{ .ResultInit }}(v.({{ .ViewedResultRef }})), nil
+	{{- else if eq .ResultRef "interface{}" }}
+	return v, nil
+	{{- else }}
+	return v.({{ .ResultRef }}), nil
+	{{- end }}
+{{- else }}
+	if _, err := r.{{ $svc.VarName }}.{{ .Endpoint }}(ctx, {{ if .PayloadRef }}p{{ else }}nil{{ end }}); err != nil {
+		return false, err
+	}
+	return true, nil
+{{- end }}
+}
+{{- end }}
+{{- end }}
+`
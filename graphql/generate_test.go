@@ -0,0 +1,92 @@
+package graphql_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/graphql"
+	"goa.design/plugins/v3/graphql/expr"
+	"goa.design/plugins/v3/graphql/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name         string
+		DSL          func()
+		ResolverCode string
+	}{
+		{"queries", testdata.QueriesDSL, testdata.QueriesResolverCode},
+		{"mutations-only", testdata.MutationsOnlyDSL, testdata.MutationsOnlyResolverCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Federation, expr.Root.Keys = false, nil
+			service.Services = make(service.ServicesData)
+			root := codegen.RunDSL(t, c.DSL)
+			fs, err := graphql.Generate("goa.design/plugins/v3/graphql/gen", []eval.Root{root}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != 2 {
+				t.Fatalf("got %d files, expected 2", len(fs))
+			}
+			var buf bytes.Buffer
+			if err := fs[0].SectionTemplates[0].Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			plugintest.Golden(t, fmt.Sprintf("%s.graphql", c.Name), buf.String())
+			sections := fs[1].Section("graphql-resolver")
+			if len(sections) != 1 {
+				t.Fatalf("got %d resolver sections, expected 1", len(sections))
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != c.ResolverCode {
+				t.Errorf("invalid resolver code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.ResolverCode))
+			}
+		})
+	}
+}
+
+func TestGenerateFederation(t *testing.T) {
+	expr.Root.Federation, expr.Root.Keys = false, nil
+	root := plugintest.RunDSL(t, testdata.FederationDSL, expr.Root)
+	fs, err := graphql.Generate("goa.design/plugins/v3/graphql/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 3 {
+		t.Fatalf("got %d files, expected 3", len(fs))
+	}
+	plugintest.Golden(t, "subgraph.graphql", plugintest.Render(t, plugintest.File(t, fs, "gen/graphql/subgraph.graphql")))
+	if schema := plugintest.Render(t, plugintest.File(t, fs, "gen/graphql/schema.graphql")); strings.Contains(schema, "@key") {
+		t.Errorf("got @key directive in schema, expected only in subgraph schema:\n%s", schema)
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	expr.Root.Federation, expr.Root.Keys = false, nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.GeneratedTypes = &goaexpr.GeneratedRoot{}
+	goaexpr.Root.API = goaexpr.NewAPIExpr("test api", func() {})
+	goaexpr.Root.API.Servers = []*goaexpr.ServerExpr{goaexpr.Root.API.DefaultServer()}
+	eval.Register(goaexpr.Root)
+	eval.Register(goaexpr.Root.GeneratedTypes)
+	eval.Register(expr.Root)
+	if !eval.Execute(testdata.InvalidKeyDSL, nil) {
+		t.Fatal(eval.Context.Errors)
+	}
+	err := eval.RunDSL()
+	for _, msg := range []string{"keys require Federation", `key attribute "uuid" not found`} {
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("got error %v, expected %q", err, msg)
+		}
+	}
+}
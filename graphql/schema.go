@@ -0,0 +1,165 @@
+package graphql
+
+import (
+	"sort"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// schemaData contains the data necessary to render the GraphQL schema.
+	schemaData struct {
+		// Scalars lists the custom scalars used by the schema.
+		Scalars []string
+		// Query is the root query type, nil if there is no query.
+		Query *typeData
+		// Mutation is the root mutation type, nil if there is no
+		// mutation.
+		Mutation *typeData
+		// Types lists the object and input types sorted by name.
+		Types []*typeData
+	}
+
+	// typeData describes a GraphQL object or input type.
+	typeData struct {
+		// Kind is "type" or "input".
+		Kind string
+		// Name is the type name.
+		Name string
+		// Description is the type description.
+		Description string
+		// Directives lists the directives applied to the type.
+		Directives []string
+		// Fields lists the type fields.
+		Fields []*fieldData
+	}
+
+	// fieldData describes a field or an argument.
+	fieldData struct {
+		// Name is the field name.
+		Name string
+		// Description is the field description.
+		Description string
+		// Type is the reference to the field type.
+		Type string
+		// Args lists the field arguments.
+		Args []*fieldData
+	}
+
+	// schemaBuilder maps the design types to GraphQL types.
+	schemaBuilder struct {
+		types   map[string]*typeData
+		scalars map[string]bool
+		// keys maps the names of the entity types to their @key
+		// directives.
+		keys map[string][]string
+	}
+)
+
+// jsonScalar is the custom scalar used to represent maps and values of type
+// Any.
+const jsonScalar = "JSON"
+
+// newSchemaBuilder returns an empty schema builder. keys maps the names of the
+// entity types to their @key directives, it is nil if the schema is not a
+// federation subgraph schema.
+func newSchemaBuilder(keys map[string][]string) *schemaBuilder {
+	return &schemaBuilder{
+		types:   make(map[string]*typeData),
+		scalars: make(map[string]bool),
+		keys:    keys,
+	}
+}
+
+// schema returns the schema data given the root types.
+func (b *schemaBuilder) schema(query, mutation *typeData) *schemaData {
+	s := &schemaData{}
+	if len(query.Fields) > 0 {
+		s.Query = query
+	}
+	if len(mutation.Fields) > 0 {
+		s.Mutation = mutation
+	}
+	for n := range b.scalars {
+		s.Scalars = append(s.Scalars, n)
+	}
+	sort.Strings(s.Scalars)
+	for _, t := range b.types {
+		s.Types = append(s.Types, t)
+	}
+	sort.Slice(s.Types, func(i, j int) bool { return s.Types[i].Name < s.Types[j].Name })
+	return s
+}
+
+// typeRef returns the reference to the GraphQL type of the given attribute
+// defining the type if needed. name is the name of the type used when the
+// attribute is an inline object. input indicates whether the type is used as
+// argument in which case it is defined as an input type.
+func (b *schemaBuilder) typeRef(att *expr.AttributeExpr, name string, input, required bool) string {
+	var ref string
+	switch t := att.Type.(type) {
+	case expr.UserType:
+		if expr.IsObject(t) {
+			ref = b.object(t.Attribute(), codegen.Goify(t.Name(), true), input)
+		} else {
+			ref = b.typeRef(t.Attribute(), codegen.Goify(t.Name(), true), input, false)
+		}
+	case *expr.Object:
+		ref = b.object(att, name, input)
+	case *expr.Array:
+		ref = "[" + b.typeRef(t.ElemType, name+"Item", input, true) + "]"
+	case *expr.Map:
+		ref = jsonScalar
+	case expr.Primitive:
+		ref = scalar(t)
+	}
+	if ref == jsonScalar {
+		b.scalars[jsonScalar] = true
+	}
+	if required {
+		ref += "!"
+	}
+	return ref
+}
+
+// object defines the GraphQL type corresponding to the given object attribute
+// and returns its name.
+func (b *schemaBuilder) object(att *expr.AttributeExpr, name string, input bool) string {
+	kind, tname := "type", name
+	if input {
+		kind, tname = "input", name+"Input"
+	}
+	if _, ok := b.types[tname]; ok {
+		return tname
+	}
+	t := &typeData{Kind: kind, Name: tname, Description: att.Description}
+	if !input {
+		t.Directives = b.keys[tname]
+	}
+	b.types[tname] = t // define before recursing to handle recursive types
+	for _, nat := range *expr.AsObject(att.Type) {
+		t.Fields = append(t.Fields, &fieldData{
+			Name:        codegen.Goify(nat.Name, false),
+			Description: nat.Attribute.Description,
+			Type:        b.typeRef(nat.Attribute, name+codegen.Goify(nat.Name, true), input, att.IsRequired(nat.Name)),
+		})
+	}
+	return tname
+}
+
+// scalar returns the GraphQL scalar corresponding to the given primitive.
+func scalar(p expr.Primitive) string {
+	switch p.Kind() {
+	case expr.BooleanKind:
+		return "Boolean"
+	case expr.IntKind, expr.Int32Kind, expr.Int64Kind, expr.UIntKind, expr.UInt32Kind, expr.UInt64Kind:
+		return "Int"
+	case expr.Float32Kind, expr.Float64Kind:
+		return "Float"
+	case expr.StringKind, expr.BytesKind:
+		return "String"
+	default:
+		return jsonScalar
+	}
+}
@@ -0,0 +1,91 @@
+package testdata
+
+const QueriesResolverCode = `// Resolver resolves the GraphQL queries and mutations by calling the service
+// endpoints.
+type Resolver struct {
+	catalogEndpoints *catalog.Endpoints
+}
+
+// NewResolver returns a resolver which calls the given service endpoints.
+func NewResolver(catalogEndpoints *catalog.Endpoints) *Resolver {
+	return &Resolver{
+		catalogEndpoints: catalogEndpoints,
+	}
+}
+
+// CatalogShow resolves the "catalogShow" query by calling the "Show" endpoint
+// of the "Catalog" service.
+//
+// Show an item by ID.
+func (r *Resolver) CatalogShow(ctx context.Context, p *catalog.ShowPayload) (res *catalog.StoredItem, err error) {
+	v, err := r.catalogEndpoints.Show(ctx, p)
+	if err != nil {
+		return
+	}
+	return catalog.NewStoredItem(v.(*catalogviews.StoredItem)), nil
+}
+
+// CatalogList resolves the "catalogList" query by calling the "List" endpoint
+// of the "Catalog" service.
+func (r *Resolver) CatalogList(ctx context.Context) (res []*catalog.Item, err error) {
+	v, err := r.catalogEndpoints.List(ctx, nil)
+	if err != nil {
+		return
+	}
+	return v.([]*catalog.Item), nil
+}
+
+// CatalogCreate resolves the "catalogCreate" mutation by calling the "Create"
+// endpoint of the "Catalog" service.
+func (r *Resolver) CatalogCreate(ctx context.Context, p *catalog.Item) (res string, err error) {
+	v, err := r.catalogEndpoints.Create(ctx, p)
+	if err != nil {
+		return
+	}
+	return v.(string), nil
+}
+
+// CatalogDelete resolves the "catalogDelete" mutation by calling the "Delete"
+// endpoint of the "Catalog" service.
+func (r *Resolver) CatalogDelete(ctx context.Context, p string) (bool, error) {
+	if _, err := r.catalogEndpoints.Delete(ctx, p); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+`
+
+const MutationsOnlyResolverCode = `// Resolver resolves the GraphQL queries and mutations by calling the service
+// endpoints.
+type Resolver struct {
+	healthEndpoints *health.Endpoints
+}
+
+// NewResolver returns a resolver which calls the given service endpoints.
+func NewResolver(healthEndpoints *health.Endpoints) *Resolver {
+	return &Resolver{
+		healthEndpoints: healthEndpoints,
+	}
+}
+
+// HealthPing resolves the "healthPing" mutation by calling the "Ping" endpoint
+// of the "Health" service.
+//
+// Ping the service.
+func (r *Resolver) HealthPing(ctx context.Context) (bool, error) {
+	if _, err := r.healthEndpoints.Ping(ctx, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HealthEcho resolves the "healthEcho" mutation by calling the "Echo" endpoint
+// of the "Health" service.
+func (r *Resolver) HealthEcho(ctx context.Context, p interface{}) (res interface{}, err error) {
+	v, err := r.healthEndpoints.Echo(ctx, p)
+	if err != nil {
+		return
+	}
+	return v, nil
+}
+`
@@ -0,0 +1,110 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	graphql "goa.design/plugins/v3/graphql/dsl"
+)
+
+var QueriesDSL = func() {
+	var Item = Type("Item", func() {
+		Description("Item of the catalog.")
+		Attribute("name", String, "Name of the item")
+		Attribute("price", Float64)
+		Attribute("tags", ArrayOf(String))
+		Attribute("attributes", MapOf(String, String))
+		Required("name")
+	})
+	var StoredItem = ResultType("application/vnd.stored-item", func() {
+		Attribute("id", String)
+		Attribute("item", Item)
+		Required("id", "item")
+	})
+	Service("Catalog", func() {
+		Method("show", func() {
+			Description("Show an item by ID.")
+			Payload(func() {
+				Attribute("id", String)
+				Required("id")
+			})
+			Result(StoredItem)
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+		Method("list", func() {
+			Result(ArrayOf(Item))
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+		Method("create", func() {
+			Payload(Item)
+			Result(String)
+			HTTP(func() {
+				POST("/items")
+			})
+		})
+		Method("delete", func() {
+			Payload(String)
+			HTTP(func() {
+				DELETE("/items/{id}")
+			})
+		})
+	})
+}
+
+var MutationsOnlyDSL = func() {
+	Service("Health", func() {
+		Method("ping", func() {
+			Description("Ping the service.")
+		})
+		Method("echo", func() {
+			Payload(Any)
+			Result(Any)
+		})
+	})
+}
+
+var FederationDSL = func() {
+	var Review = Type("Review", func() {
+		graphql.Key("id")
+		Attribute("id", String)
+		Attribute("body", String)
+		Required("id")
+	})
+	var Product = ResultType("application/vnd.product", func() {
+		Description("Product of the catalog.")
+		graphql.Key("upc")
+		graphql.Key("sku", "vendor_id")
+		Attributes(func() {
+			Attribute("upc", String)
+			Attribute("sku", String)
+			Attribute("vendor_id", String)
+			Attribute("name", String)
+			Attribute("reviews", ArrayOf(Review))
+			Required("upc", "sku", "vendor_id")
+		})
+	})
+	API("catalog", func() {
+		graphql.Federation()
+	})
+	Service("Catalog", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("upc", String)
+				Required("upc")
+			})
+			Result(Product)
+			HTTP(func() {
+				GET("/products/{upc}")
+			})
+		})
+	})
+}
+
+var InvalidKeyDSL = func() {
+	var _ = Type("Review", func() {
+		graphql.Key("uuid")
+		Attribute("id", String)
+	})
+}
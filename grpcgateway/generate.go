@@ -0,0 +1,197 @@
+package grpcgateway
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	grpccodegen "goa.design/goa/v3/grpc/codegen"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+// init registers the plugin generator function.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "grpcgateway",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces a copy of the protocol buffer definition of each gRPC
+// service which annotates the RPCs with the google.api.http options
+// corresponding to the HTTP mappings of the design.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	var gateways []*codegen.File
+	for _, f := range files {
+		if !config.EnabledFile("grpcgateway", f) {
+			continue
+		}
+		if g := gatewayFile(f); g != nil {
+			gateways = append(gateways, g)
+		}
+	}
+	return append(files, gateways...), nil
+}
+
+// gatewayFile returns the annotated protocol buffer definition corresponding
+// to f if f is a protocol buffer definition file, nil otherwise.
+func gatewayFile(f *codegen.File) *codegen.File {
+	svcs := f.Section("grpc-service")
+	if len(svcs) == 0 {
+		return nil
+	}
+	data, ok := svcs[0].Data.(*grpccodegen.ServiceData)
+	if !ok || !hasHTTPRule(data) {
+		return nil
+	}
+	sections := make([]*codegen.SectionTemplate, len(f.SectionTemplates))
+	for i, s := range f.SectionTemplates {
+		c := *s
+		switch s.Name {
+		case "proto-start":
+			c.Source = strings.Replace(s.Source, "\npackage", "\nimport \"google/api/annotations.proto\";\n\npackage", 1)
+		case "grpc-service":
+			c.Name = "grpc-gateway-service"
+			c.Source = serviceT
+			c.FuncMap = map[string]interface{}{"httpRule": httpRule, "indent": indent}
+		}
+		sections[i] = &c
+	}
+	svcName := filepath.Base(f.Path)
+	return &codegen.File{
+		Path:             filepath.Join(filepath.Dir(filepath.Dir(f.Path)), "gateway", svcName),
+		SectionTemplates: sections,
+	}
+}
+
+// hasHTTPRule returns true if at least one of the service methods is mapped to
+// HTTP.
+func hasHTTPRule(data *grpccodegen.ServiceData) bool {
+	for _, e := range data.Endpoints {
+		if httpEndpoint(data.Service.Name, e.Method.Name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// httpEndpoint returns the HTTP endpoint of the given method, nil if the
+// method is not mapped to HTTP.
+func httpEndpoint(svc, method string) *expr.HTTPEndpointExpr {
+	if expr.Root.API == nil || expr.Root.API.HTTP == nil {
+		return nil
+	}
+	s := expr.Root.API.HTTP.Service(svc)
+	if s == nil {
+		return nil
+	}
+	return s.Endpoint(method)
+}
+
+// httpRule returns the google.api.http option of the given method, an empty
+// string if the method is not mapped to HTTP. The first route defines the
+// main binding and the others are listed as additional bindings.
+func httpRule(svc, method string) string {
+	e := httpEndpoint(svc, method)
+	if e == nil || len(e.Routes) == 0 {
+		return ""
+	}
+	var bindings []string
+	for _, r := range e.Routes {
+		for _, p := range r.FullPaths() {
+			bindings = append(bindings, binding(e, r.Method, p))
+		}
+	}
+	rule := bindings[0]
+	for _, b := range bindings[1:] {
+		rule += "\nadditional_bindings {\n" + indent(b, "\t") + "\n}"
+	}
+	return "option (google.api.http) = {\n" + indent(rule, "\t") + "\n};"
+}
+
+// binding returns the HTTP rule fields of a single route.
+func binding(e *expr.HTTPEndpointExpr, method, path string) string {
+	path = expr.HTTPWildcardRegex.ReplaceAllStringFunc(path, func(w string) string {
+		name := expr.HTTPWildcardRegex.FindStringSubmatch(w)[1]
+		if strings.HasPrefix(w, "/{*") {
+			return fmt.Sprintf("/{%s=**}", field(e, name))
+		}
+		return fmt.Sprintf("/{%s}", field(e, name))
+	})
+	var lines []string
+	switch method {
+	case "GET", "PUT", "POST", "DELETE", "PATCH":
+		lines = append(lines, fmt.Sprintf("%s: %q", strings.ToLower(method), path))
+	default:
+		lines = append(lines, fmt.Sprintf("custom {\n\tkind: %q\n\tpath: %q\n}", method, path))
+	}
+	if b := body(e); b != "" {
+		lines = append(lines, fmt.Sprintf("body: %q", b))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// body returns the name of the request message field mapped to the HTTP
+// request body, "*" if the body maps to the remaining fields and an empty
+// string if there is no body.
+func body(e *expr.HTTPEndpointExpr) string {
+	if e.Body == nil || e.Body.Type == expr.Empty {
+		return ""
+	}
+	if att, ok := e.Body.Meta["origin:attribute"]; ok && len(att) > 0 {
+		return protoName(att[0])
+	}
+	return "*"
+}
+
+// field returns the name of the request message field corresponding to the
+// given path wildcard.
+func field(e *expr.HTTPEndpointExpr, wildcard string) string {
+	if !expr.IsObject(e.MethodExpr.Payload.Type) {
+		return "field" // goa wraps non-object payloads in a message
+	}
+	if obj := expr.AsObject(e.Params.Type); obj != nil && obj.Attribute(wildcard) == nil {
+		for _, nat := range *obj {
+			if e.Params.ElemName(nat.Name) == wildcard {
+				return protoName(nat.Name)
+			}
+		}
+	}
+	return protoName(wildcard)
+}
+
+// protoName returns the name of the protocol buffer message field
+// corresponding to the given attribute name.
+func protoName(att string) string {
+	return codegen.SnakeCase(codegen.CamelCase(att, false, false))
+}
+
+// indent prefixes each line of s with prefix.
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// input: grpccodegen.ServiceData
+const serviceT = `
+{{ .Description | comment }}
+service {{ .Name }} {
+	{{- range .Endpoints }}
+	{{ if .Method.Description }}{{ .Method.Description | comment }}{{ end }}
+	{{- $serverStream := or (eq .Method.StreamKind 3) (eq .Method.StreamKind 4) }}
+	{{- $clientStream := or (eq .Method.StreamKind 2) (eq .Method.StreamKind 4) }}
+	{{- $rule := httpRule $.Service.Name .Method.Name }}
+	rpc {{ .Method.VarName }} ({{ if $clientStream }}stream {{ end }}{{ .Request.Message.VarName }}) returns ({{ if $serverStream }}stream {{ end }}{{ .Response.Message.VarName }}){{ if $rule }} {
+{{ indent $rule "\t\t" }}
+	}{{ else }};{{ end }}
+	{{- end }}
+}
+`
@@ -0,0 +1,49 @@
+package grpcgateway_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	grpccodegen "goa.design/goa/v3/grpc/codegen"
+	"goa.design/plugins/v3/grpcgateway"
+	"goa.design/plugins/v3/grpcgateway/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	grpccodegen.RunGRPCDSL(t, testdata.GatewayDSL)
+	fs := grpccodegen.ProtoFiles("", goaexpr.Root)
+	fs, err := grpcgateway.Generate("", []eval.Root{goaexpr.Root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	f := fs[1]
+	if expected := filepath.Join("gen", "grpc", "catalog", "gateway", "catalog.proto"); f.Path != expected {
+		t.Errorf("got path %q, expected %q", f.Path, expected)
+	}
+	var buf bytes.Buffer
+	for _, s := range f.SectionTemplates[1:] {
+		if err := s.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plugintest.Golden(t, "gateway.proto", buf.String())
+}
+
+func TestGenerateGRPCOnly(t *testing.T) {
+	grpccodegen.RunGRPCDSL(t, testdata.GRPCOnlyDSL)
+	fs := grpccodegen.ProtoFiles("", goaexpr.Root)
+	fs, err := grpcgateway.Generate("", []eval.Root{goaexpr.Root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Errorf("got %d files, expected 1", len(fs))
+	}
+}
@@ -0,0 +1,73 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var GatewayDSL = func() {
+	var Item = Type("Item", func() {
+		Field(1, "name", String)
+		Field(2, "price", Float64)
+	})
+	Service("Catalog", func() {
+		HTTP(func() {
+			Path("/v1")
+		})
+		Method("show", func() {
+			Payload(func() {
+				Field(1, "item_id", String)
+			})
+			Result(Item)
+			HTTP(func() {
+				GET("/items/{item_id}")
+			})
+			GRPC(func() {})
+		})
+		Method("list", func() {
+			Result(ArrayOf(Item))
+			HTTP(func() {
+				GET("/items")
+				GET("/catalog")
+			})
+			GRPC(func() {})
+		})
+		Method("create", func() {
+			Payload(Item)
+			HTTP(func() {
+				POST("/items")
+			})
+			GRPC(func() {})
+		})
+		Method("update", func() {
+			Payload(func() {
+				Field(1, "id", String)
+				Field(2, "item", Item)
+			})
+			HTTP(func() {
+				PUT("/items/{id}")
+				Body("item")
+			})
+			GRPC(func() {})
+		})
+		Method("download", func() {
+			Payload(func() {
+				Field(1, "file_path", String)
+			})
+			HTTP(func() {
+				GET("/files/{*file_path}")
+			})
+			GRPC(func() {})
+		})
+		Method("ping", func() {
+			GRPC(func() {})
+		})
+	})
+}
+
+var GRPCOnlyDSL = func() {
+	Service("Health", func() {
+		Method("ping", func() {
+			GRPC(func() {})
+		})
+	})
+}
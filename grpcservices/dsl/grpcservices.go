@@ -0,0 +1,54 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/grpcservices/expr"
+
+	// Register code generators for the gRPC services plugin
+	_ "goa.design/plugins/v3/grpcservices"
+)
+
+// Health registers the standard gRPC health service
+// (grpc.health.v1.Health) with the example gRPC servers. The health service
+// reports all the services registered with the server as serving until the
+// server shuts down.
+//
+// Health must appear in an API expression.
+//
+// Example:
+//
+//    import grpcservices "goa.design/plugins/v3/grpcservices/dsl"
+//
+//    var _ = API("calc", func() {
+//        grpcservices.Health()
+//        grpcservices.Reflection()
+//    })
+//
+func Health() {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Health = true
+}
+
+// Reflection registers the gRPC server reflection service with the example
+// gRPC servers so that tools such as grpcurl can list and call the services
+// without the protocol buffer files.
+//
+// Reflection must appear in an API expression.
+//
+// Example:
+//
+//    var _ = API("calc", func() {
+//        grpcservices.Reflection()
+//    })
+//
+func Reflection() {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Reflection = true
+}
@@ -0,0 +1,47 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the standard gRPC services registered with the
+	// example gRPC servers.
+	RootExpr struct {
+		// Health is true if the example servers register the standard
+		// gRPC health service.
+		Health bool
+		// Reflection is true if the example servers register the gRPC
+		// server reflection service.
+		Reflection bool
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "gRPC services plugin"
+}
+
+// WalkSets is a no-op, the toggles require no validation.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/grpcservices/dsl"}
+}
@@ -0,0 +1,86 @@
+package grpcservices
+
+import (
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/grpcservices/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "grpcservices",
+		Cmd:      "example",
+		Generate: Example,
+	})
+}
+
+// Example registers the standard gRPC health service and the server reflection
+// service with the example gRPC servers as enabled by the design.
+func Example(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("grpcservices", "") {
+		return files, nil
+	}
+	if !expr.Root.Health && !expr.Root.Reflection {
+		return files, nil
+	}
+	for _, f := range files {
+		registerServices(f)
+	}
+	return files, nil
+}
+
+// registerServices adds the code that registers the standard services to the
+// example gRPC server file.
+func registerServices(f *codegen.File) {
+	for i, s := range f.SectionTemplates {
+		if s.Name != "server-grpc-register" {
+			continue
+		}
+		var specs []*codegen.ImportSpec
+		if expr.Root.Health {
+			specs = append(specs,
+				&codegen.ImportSpec{Path: "google.golang.org/grpc/health"},
+				&codegen.ImportSpec{Name: "healthpb", Path: "google.golang.org/grpc/health/grpc_health_v1"})
+		}
+		if expr.Root.Reflection {
+			specs = append(specs, &codegen.ImportSpec{Path: "google.golang.org/grpc/reflection"})
+		}
+		codegen.AddImport(f.SectionTemplates[0], specs...)
+		register := &codegen.SectionTemplate{Name: "grpcservices-register", Source: registerT, Data: expr.Root}
+		rest := append([]*codegen.SectionTemplate{register}, f.SectionTemplates[i+1:]...)
+		f.SectionTemplates = append(f.SectionTemplates[:i+1], rest...)
+		if expr.Root.Health {
+			// Report the services as not serving before stopping the
+			// server so that the clients stop sending requests.
+			for _, end := range f.Section("server-grpc-end") {
+				end.Source = strings.Replace(end.Source, "\t\tsrv.Stop()", "\t\thealthSvr.Shutdown()\n\t\tsrv.Stop()", 1)
+			}
+		}
+		return
+	}
+}
+
+// input: *expr.RootExpr
+const registerT = `
+{{- if .Health }}
+
+	// Register the standard gRPC health service and report the registered
+	// services as serving.
+	healthSvr := health.NewServer()
+	for svc := range srv.GetServiceInfo() {
+		healthSvr.SetServingStatus(svc, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(srv, healthSvr)
+{{- end }}
+{{- if .Reflection }}
+
+	// Register the server reflection service so that tools such as grpcurl
+	// can discover the services.
+	reflection.Register(srv)
+{{- end }}
+`
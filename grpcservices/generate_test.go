@@ -0,0 +1,37 @@
+package grpcservices_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	grpccodegen "goa.design/goa/v3/grpc/codegen"
+	"goa.design/plugins/v3/grpcservices"
+	"goa.design/plugins/v3/grpcservices/expr"
+	"goa.design/plugins/v3/grpcservices/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestExample(t *testing.T) {
+	cases := []struct {
+		Name   string
+		DSL    func()
+		Golden string
+	}{
+		{"health-reflection", testdata.HealthReflectionDSL, "health-reflection.golden"},
+		{"reflection", testdata.ReflectionDSL, "reflection.golden"},
+		{"none", testdata.NoServicesDSL, "none.golden"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Health, expr.Root.Reflection = false, false
+			root := plugintest.RunDSL(t, c.DSL, expr.Root)
+			fs := grpccodegen.ExampleServerFiles("gen", root)
+			fs, err := grpcservices.Example("gen", []eval.Root{root}, fs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := plugintest.File(t, fs, "cmd/calc/grpc.go")
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, f))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	grpcservices "goa.design/plugins/v3/grpcservices/dsl"
+)
+
+var HealthReflectionDSL = func() {
+	API("calc", func() {
+		grpcservices.Health()
+		grpcservices.Reflection()
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(func() {
+				Field(1, "a", Int)
+				Field(2, "b", Int)
+			})
+			Result(Int)
+			GRPC(func() {})
+		})
+	})
+}
+
+var ReflectionDSL = func() {
+	API("calc", func() {
+		grpcservices.Reflection()
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(func() {
+				Field(1, "a", Int)
+				Field(2, "b", Int)
+			})
+			Result(Int)
+			GRPC(func() {})
+		})
+	})
+}
+
+var NoServicesDSL = func() {
+	API("calc", func() {})
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(Int)
+			Result(Int)
+			GRPC(func() {})
+		})
+	})
+}
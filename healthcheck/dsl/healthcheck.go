@@ -0,0 +1,144 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/healthcheck/expr"
+
+	// Register code generators for the health check plugin
+	_ "goa.design/plugins/v3/healthcheck"
+)
+
+// HealthCheck enables the generation of the liveness and readiness HTTP
+// endpoints. The liveness endpoint ("/livez" by default) always responds with
+// 200 while the server is running. The readiness endpoint ("/readyz" by
+// default) runs the checks registered with the generated health check handler
+// and responds with 503 if any of them fails.
+//
+// The endpoints are not part of any service so that they are not subject to
+// the design security requirements. They are also excluded from the OpenAPI
+// specification unless Documented is used.
+//
+// HealthCheck must appear in an API expression.
+//
+// HealthCheck accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import healthcheck "goa.design/plugins/v3/healthcheck/dsl"
+//
+//    var _ = API("calc", func() {
+//        healthcheck.HealthCheck(func() {
+//            healthcheck.Liveness("/healthz")  // Overrides the default "/livez" path
+//            healthcheck.Readiness("/ready")   // Overrides the default "/readyz" path
+//            healthcheck.Timeout(2)            // Maximum duration of checks in seconds
+//            healthcheck.Documented()          // Lists the endpoints in the OpenAPI spec
+//        })
+//    })
+//
+func HealthCheck(args ...interface{}) {
+	api, ok := eval.Current().(*goaexpr.APIExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(args) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	h := &expr.HealthCheckExpr{
+		LivenessPath:  expr.DefaultLivenessPath,
+		ReadinessPath: expr.DefaultReadinessPath,
+		Timeout:       expr.DefaultTimeout,
+		Parent:        api,
+	}
+	if len(args) == 1 {
+		dsl, ok := args[0].(func())
+		if !ok {
+			eval.InvalidArgError("function", args[0])
+			return
+		}
+		if !eval.Execute(dsl, h) {
+			return
+		}
+	}
+	expr.Root.HealthCheck = h
+}
+
+// Liveness sets the path of the liveness endpoint.
+//
+// Liveness must appear in a HealthCheck expression.
+//
+// Example:
+//
+//     HealthCheck(func() {
+//         Liveness("/healthz")
+//     })
+//
+func Liveness(path string) {
+	switch h := eval.Current().(type) {
+	case *expr.HealthCheckExpr:
+		h.LivenessPath = path
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Readiness sets the path of the readiness endpoint.
+//
+// Readiness must appear in a HealthCheck expression.
+//
+// Example:
+//
+//     HealthCheck(func() {
+//         Readiness("/ready")
+//     })
+//
+func Readiness(path string) {
+	switch h := eval.Current().(type) {
+	case *expr.HealthCheckExpr:
+		h.ReadinessPath = path
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Timeout sets the maximum duration in seconds of the dependency checks run by
+// the readiness endpoint. The default is 5 seconds.
+//
+// Timeout must appear in a HealthCheck expression.
+//
+// Example:
+//
+//     HealthCheck(func() {
+//         Timeout(2)
+//     })
+//
+func Timeout(seconds uint) {
+	switch h := eval.Current().(type) {
+	case *expr.HealthCheckExpr:
+		h.Timeout = seconds
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Documented lists the liveness and readiness endpoints in the generated
+// OpenAPI specification.
+//
+// Documented must appear in a HealthCheck expression.
+//
+// Example:
+//
+//     HealthCheck(func() {
+//         Documented()
+//     })
+//
+func Documented() {
+	switch h := eval.Current().(type) {
+	case *expr.HealthCheckExpr:
+		h.Documented = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,66 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+)
+
+const (
+	// DefaultLivenessPath is the default path of the liveness endpoint.
+	DefaultLivenessPath = "/livez"
+	// DefaultReadinessPath is the default path of the readiness endpoint.
+	DefaultReadinessPath = "/readyz"
+	// DefaultTimeout is the default maximum duration in seconds of the
+	// dependency checks run by the readiness endpoint.
+	DefaultTimeout = 5
+)
+
+type (
+	// HealthCheckExpr describes the liveness and readiness endpoints.
+	HealthCheckExpr struct {
+		// LivenessPath is the path of the liveness endpoint.
+		LivenessPath string
+		// ReadinessPath is the path of the readiness endpoint.
+		ReadinessPath string
+		// Timeout is the maximum duration in seconds of the dependency
+		// checks run by the readiness endpoint.
+		Timeout uint
+		// Documented is true if the endpoints must be listed in the
+		// OpenAPI specification.
+		Documented bool
+		// Parent expression, APIExpr.
+		Parent eval.Expression
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (h *HealthCheckExpr) EvalName() string {
+	var suffix string
+	if h.Parent != nil {
+		suffix = fmt.Sprintf(" of %s", h.Parent.EvalName())
+	}
+	return "HealthCheck" + suffix
+}
+
+// Validate ensures the health check expression is valid.
+func (h *HealthCheckExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if !strings.HasPrefix(h.LivenessPath, "/") {
+		verr.Add(h, "invalid liveness path %q, must start with /", h.LivenessPath)
+	}
+	if !strings.HasPrefix(h.ReadinessPath, "/") {
+		verr.Add(h, "invalid readiness path %q, must start with /", h.ReadinessPath)
+	}
+	if h.LivenessPath == h.ReadinessPath {
+		verr.Add(h, "liveness and readiness endpoints cannot share the path %q", h.LivenessPath)
+	}
+	if h.Timeout == 0 {
+		verr.Add(h, "timeout must be greater than 0")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,48 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the health check definition of the design.
+	RootExpr struct {
+		// HealthCheck is the health check definition, nil if the design
+		// does not use the HealthCheck DSL.
+		HealthCheck *HealthCheckExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "health check plugin"
+}
+
+// WalkSets iterates over the health check definition.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	if r.HealthCheck == nil {
+		return
+	}
+	walk(eval.ExpressionSet{r.HealthCheck})
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/healthcheck/dsl"}
+}
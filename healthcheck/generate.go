@@ -0,0 +1,257 @@
+package healthcheck
+
+import (
+	"path"
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/healthcheck/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "healthcheck",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+	registry.Register(&registry.Plugin{
+		Name:     "healthcheck-example",
+		Cmd:      "example",
+		Generate: Example,
+	})
+}
+
+// Generate produces the health check package which implements the liveness
+// and readiness HTTP handlers. It also lists the endpoints in the OpenAPI
+// specification if the design says so.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("healthcheck", "") {
+		return files, nil
+	}
+	h := expr.Root.HealthCheck
+	if h == nil {
+		return files, nil
+	}
+	if h.Documented {
+		for _, f := range files {
+			documentHealthCheck(f, h)
+		}
+	}
+	return append(files, healthCheckFile(h)), nil
+}
+
+// Example mounts the liveness and readiness handlers in the example HTTP
+// servers.
+func Example(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("healthcheck", "") {
+		return files, nil
+	}
+	if expr.Root.HealthCheck == nil {
+		return files, nil
+	}
+	for _, f := range files {
+		mountHealthCheck(genpkg, f)
+	}
+	return files, nil
+}
+
+// healthCheckFile returns the file implementing the health check handlers.
+func healthCheckFile(h *expr.HealthCheckExpr) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "healthcheck", "healthcheck.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Health check HTTP handlers", "healthcheck", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/json"},
+			{Path: "net/http"},
+			{Path: "sync"},
+			{Path: "time"},
+			{Path: "goa.design/goa/v3/http", Name: "goahttp"},
+		}),
+		{Name: "healthcheck-checker", Source: checkerT},
+		{Name: "healthcheck-handler", Source: handlerT, Data: h},
+		{Name: "healthcheck-mount", Source: mountT, Data: h},
+	}
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// mountHealthCheck adds the code that mounts the health check handlers to the
+// example HTTP server file.
+func mountHealthCheck(genpkg string, f *codegen.File) {
+	for i, s := range f.SectionTemplates {
+		if s.Name != "server-http-init" {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: path.Join(genpkg, "healthcheck")})
+		mount := &codegen.SectionTemplate{Name: "healthcheck-mount-example", Source: exampleMountT}
+		rest := append([]*codegen.SectionTemplate{mount}, f.SectionTemplates[i+1:]...)
+		f.SectionTemplates = append(f.SectionTemplates[:i+1], rest...)
+		return
+	}
+}
+
+// documentHealthCheck adds the liveness and readiness endpoints to the OpenAPI
+// specification if f is an OpenAPI file.
+func documentHealthCheck(f *codegen.File, h *expr.HealthCheckExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		spec.Paths[h.LivenessPath] = &openapi.Path{Get: &openapi.Operation{
+			Tags:        []string{"healthcheck"},
+			Summary:     "liveness healthcheck",
+			Description: "Responds with 200 as long as the server is running.",
+			OperationID: "healthcheck#liveness",
+			Produces:    []string{"application/json"},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "OK response."},
+			},
+		}}
+		spec.Paths[h.ReadinessPath] = &openapi.Path{Get: &openapi.Operation{
+			Tags:        []string{"healthcheck"},
+			Summary:     "readiness healthcheck",
+			Description: "Responds with 200 if all the service dependencies are available, 503 otherwise.",
+			OperationID: "healthcheck#readiness",
+			Produces:    []string{"application/json"},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "OK response."},
+				"503": {Description: "Service Unavailable response."},
+			},
+		}}
+		return nil
+	})
+}
+
+// input: none
+const checkerT = `// Checker is the interface implemented by the service dependencies (database,
+// cache etc.) whose availability is reported by the readiness endpoint.
+type Checker interface {
+	// Name returns the name of the dependency used in the readiness report.
+	Name() string
+	// Check returns a non-nil error if the dependency is unavailable.
+	Check(context.Context) error
+}
+
+// checker implements Checker with a function.
+type checker struct {
+	name  string
+	check func(context.Context) error
+}
+
+// NewChecker returns a Checker with the given name which calls check to verify
+// the availability of the dependency.
+func NewChecker(name string, check func(context.Context) error) Checker {
+	return &checker{name: name, check: check}
+}
+
+// Name returns the name of the dependency.
+func (c *checker) Name() string { return c.name }
+
+// Check calls the check function.
+func (c *checker) Check(ctx context.Context) error { return c.check(ctx) }
+`
+
+// input: HealthCheckExpr
+const handlerT = `// Handler serves the liveness and readiness endpoints.
+type Handler struct {
+	mu       sync.RWMutex
+	checkers []Checker
+}
+
+// Status is the body of the responses sent by the health check endpoints.
+type Status struct {
+	// Status is "OK" if the server is healthy, "Unavailable" otherwise.
+	Status string ` + "`" + `json:"status"` + "`" + `
+	// Checks lists the result of each dependency check indexed by
+	// dependency name.
+	Checks map[string]string ` + "`" + `json:"checks,omitempty"` + "`" + `
+}
+
+// Timeout is the maximum duration of the dependency checks run by the
+// readiness endpoint.
+const Timeout = {{ .Timeout }} * time.Second
+
+// NewHandler returns a health check handler which verifies the availability of
+// the given dependencies when serving readiness requests.
+func NewHandler(checkers ...Checker) *Handler {
+	return &Handler{checkers: checkers}
+}
+
+// Register adds dependencies to the list of dependencies checked by the
+// readiness endpoint.
+func (h *Handler) Register(checkers ...Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers = append(h.checkers, checkers...)
+}
+
+// Live handles the liveness requests.
+func (h *Handler) Live(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, &Status{Status: "OK"})
+}
+
+// Ready handles the readiness requests, it runs all the dependency checks
+// concurrently and responds with 503 if any of them fails.
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	checkers := make([]Checker, len(h.checkers))
+	copy(checkers, h.checkers)
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), Timeout)
+	defer cancel()
+	errs := make([]error, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			errs[i] = c.Check(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	status := &Status{Status: "OK", Checks: make(map[string]string, len(checkers))}
+	for i, c := range checkers {
+		if errs[i] != nil {
+			code = http.StatusServiceUnavailable
+			status.Status = "Unavailable"
+			status.Checks[c.Name()] = errs[i].Error()
+			continue
+		}
+		status.Checks[c.Name()] = "OK"
+	}
+	writeStatus(w, code, status)
+}
+
+// writeStatus writes the health check response.
+func writeStatus(w http.ResponseWriter, code int, status *Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
+`
+
+// input: HealthCheckExpr
+const mountT = `{{ printf "Mount configures the mux to serve the liveness requests made to %q and the readiness requests made to %q." .LivenessPath .ReadinessPath | comment }}
+func Mount(mux goahttp.Muxer, h *Handler) {
+	mux.Handle("GET", {{ printf "%q" .LivenessPath }}, h.Live)
+	mux.Handle("GET", {{ printf "%q" .ReadinessPath }}, h.Ready)
+}
+`
+
+// input: none
+const exampleMountT = `
+	// Mount the liveness and readiness endpoints. Register the service
+	// dependencies (database, cache etc.) with the health check handler so
+	// that the readiness endpoint reports their availability.
+	{
+		healthHandler := healthcheck.NewHandler()
+		healthcheck.Mount(mux, healthHandler)
+	}
+`
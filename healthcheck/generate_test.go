@@ -0,0 +1,94 @@
+package healthcheck_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/healthcheck"
+	"goa.design/plugins/v3/healthcheck/expr"
+	"goa.design/plugins/v3/healthcheck/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name       string
+		DSL        func()
+		MountCode  string
+		Documented []string
+	}{
+		{"default", testdata.DefaultHealthCheckDSL, testdata.DefaultHealthCheckMountCode, nil},
+		{"custom", testdata.CustomHealthCheckDSL, testdata.CustomHealthCheckMountCode, []string{"/healthz", "/ready"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.HealthCheck = nil
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err = healthcheck.Generate("", []eval.Root{goaexpr.Root}, fs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var file *codegen.File
+			for _, f := range fs {
+				if f.Path == filepath.Join("gen", "healthcheck", "healthcheck.go") {
+					file = f
+				}
+			}
+			if file == nil {
+				t.Fatal("health check file not generated")
+			}
+			sections := file.Section("healthcheck-mount")
+			if len(sections) != 1 {
+				t.Fatalf("got %d mount sections, expected 1", len(sections))
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != c.MountCode {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.MountCode))
+			}
+			codegen.SectionCode(t, file.Section("healthcheck-checker")[0])
+			codegen.SectionCode(t, file.Section("healthcheck-handler")[0])
+			spec := fs[0].SectionTemplates[0].Data.(*openapi.V2)
+			if len(spec.Paths) != 1+len(c.Documented) {
+				t.Errorf("got %d paths in OpenAPI spec, expected %d", len(spec.Paths), 1+len(c.Documented))
+			}
+			for _, p := range c.Documented {
+				if _, ok := spec.Paths[p]; !ok {
+					t.Errorf("path %q not found in OpenAPI spec", p)
+				}
+			}
+		})
+	}
+}
+
+func TestExample(t *testing.T) {
+	expr.Root.HealthCheck = nil
+	httpcodegen.RunHTTPDSL(t, testdata.DefaultHealthCheckDSL)
+	fs := httpcodegen.ExampleServerFiles("gen", goaexpr.Root)
+	fs, err := healthcheck.Example("gen", []eval.Root{goaexpr.Root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, f := range fs {
+		for i, s := range f.SectionTemplates {
+			if s.Name != "healthcheck-mount-example" {
+				continue
+			}
+			found = true
+			if f.SectionTemplates[i-1].Name != "server-http-init" {
+				t.Errorf("got health check mount after %q, expected after %q", f.SectionTemplates[i-1].Name, "server-http-init")
+			}
+		}
+	}
+	if !found {
+		t.Error("health check handlers not mounted in example server")
+	}
+}
@@ -0,0 +1,17 @@
+package testdata
+
+var DefaultHealthCheckMountCode = `// Mount configures the mux to serve the liveness requests made to "/livez" and
+// the readiness requests made to "/readyz".
+func Mount(mux goahttp.Muxer, h *Handler) {
+	mux.Handle("GET", "/livez", h.Live)
+	mux.Handle("GET", "/readyz", h.Ready)
+}
+`
+
+var CustomHealthCheckMountCode = `// Mount configures the mux to serve the liveness requests made to "/healthz"
+// and the readiness requests made to "/ready".
+func Mount(mux goahttp.Muxer, h *Handler) {
+	mux.Handle("GET", "/healthz", h.Live)
+	mux.Handle("GET", "/ready", h.Ready)
+}
+`
@@ -0,0 +1,37 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	healthcheck "goa.design/plugins/v3/healthcheck/dsl"
+)
+
+var DefaultHealthCheckDSL = func() {
+	API("DefaultHealthCheck", func() {
+		healthcheck.HealthCheck()
+	})
+	Service("DefaultHealthCheck", func() {
+		Method("DefaultHealthCheckMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var CustomHealthCheckDSL = func() {
+	API("CustomHealthCheck", func() {
+		healthcheck.HealthCheck(func() {
+			healthcheck.Liveness("/healthz")
+			healthcheck.Readiness("/ready")
+			healthcheck.Timeout(2)
+			healthcheck.Documented()
+		})
+	})
+	Service("CustomHealthCheck", func() {
+		Method("CustomHealthCheckMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
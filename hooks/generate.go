@@ -0,0 +1,158 @@
+package hooks
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "hooks",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate defines the Hooks struct of each HTTP service and makes the HTTP
+// handlers invoke the hooks.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	var hooks []*codegen.File
+	for _, f := range files {
+		if !config.EnabledFile("hooks", f) {
+			continue
+		}
+		if filepath.Base(f.Path) != "server.go" {
+			continue
+		}
+		if h := serverHooks(f); h != nil {
+			hooks = append(hooks, h)
+		}
+	}
+	return append(files, hooks...), nil
+}
+
+// errorCode is the code encoding the errors returned by the hooks.
+const errorCode = `		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+`
+
+// serverHooks makes the HTTP server constructor of the given server file
+// initialize the hooks and pass them to the handlers and makes the handlers
+// invoke them. It returns the file defining the Hooks struct, nil if f does
+// not define an HTTP server.
+func serverHooks(f *codegen.File) *codegen.File {
+	var data *httpcodegen.ServiceData
+	for _, s := range f.Section("server-struct") {
+		if d, ok := s.Data.(*httpcodegen.ServiceData); ok {
+			data = d
+			s.Source = strings.Replace(s.Source, "\tMounts []*{{ .MountPointStruct }}\n",
+				"\tMounts []*{{ .MountPointStruct }}\n\t// Hooks lists the functions invoked by the handlers.\n\tHooks *Hooks\n", 1)
+		}
+	}
+	if data == nil {
+		return nil
+	}
+	for _, s := range f.Section("server-init") {
+		s.Source = strings.Replace(s.Source, "return &{{ .ServerStruct }}{",
+			"hooks := &Hooks{}\n\treturn &{{ .ServerStruct }}{\n\t\tHooks: hooks,", 1)
+		// The cors plugin skips the arguments of the CORS handler.
+		if strings.Contains(s.Source, "enc, eh{{ end }}{{ if .ServerStream }}") {
+			s.Source = strings.Replace(s.Source, "enc, eh{{ end }}{{ if .ServerStream }}", "enc, eh, hooks{{ end }}{{ if .ServerStream }}", 1)
+		} else {
+			s.Source = strings.Replace(s.Source, "enc, eh{{ if .ServerStream }}", "enc, eh, hooks{{ if .ServerStream }}", 1)
+		}
+	}
+	for _, s := range f.Section("server-handler-init") {
+		s.Source = strings.Replace(s.Source, "\teh func(context.Context, http.ResponseWriter, error),\n",
+			"\teh func(context.Context, http.ResponseWriter, error),\n\thooks *Hooks,\n", 1)
+		s.Source = strings.Replace(s.Source, "\t\t\tif err := encodeError(ctx, w, err); err != nil {",
+			"\t\t\terr = hooks.onError(ctx, r, err)\n\t\t\tif err := encodeError(ctx, w, err); err != nil {", -1)
+		svc := `ctx = context.WithValue(ctx, goa.ServiceKey, {{ printf "%q" .ServiceName }})`
+		s.Source = strings.Replace(s.Source, svc,
+			svc+"\n\t\tctx, err := hooks.beforeDecode(ctx, r)\n"+strings.TrimSuffix(errorCode, "\n"), 1)
+		decoded := "\t\t\treturn\n\t\t}\n\t{{- end }}\n"
+		s.Source = strings.Replace(s.Source, decoded,
+			"\t\t\treturn\n\t\t}\n\t\tctx, err = hooks.afterDecode(ctx, r, payload)\n"+errorCode+"\t{{- end }}\n", 1)
+		encode := "\t\tif err := encodeResponse(ctx, w, res); err != nil {"
+		s.Source = strings.Replace(s.Source, encode,
+			"\t\tctx, err = hooks.beforeEncode(ctx, w, res)\n"+errorCode+encode, 1)
+	}
+	return &codegen.File{
+		Path: filepath.Join(filepath.Dir(f.Path), "hooks.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(data.Service.Name+" HTTP server hooks", "server", []*codegen.ImportSpec{
+				{Path: "context"},
+				{Path: "net/http"},
+			}),
+			{Name: "hooks", Source: hooksT, Data: data},
+		},
+	}
+}
+
+// input: ServiceData
+const hooksT = `{{ printf "Hooks lists the functions invoked by the %q service HTTP handlers at well-defined points of the request processing. The nil functions are skipped. The hooks must be set before the server handles requests." .Service.Name | comment }}
+type Hooks struct {
+	// BeforeDecode is invoked before the request is decoded. The returned
+	// context is used to process the rest of the request. An error aborts
+	// the request.
+	BeforeDecode func(ctx context.Context, r *http.Request) (context.Context, error)
+	// AfterDecode is invoked with the decoded payload before the endpoint
+	// is called. It is not invoked for the endpoints without payload. The
+	// returned context is used to process the rest of the request. An error
+	// aborts the request.
+	AfterDecode func(ctx context.Context, r *http.Request, payload interface{}) (context.Context, error)
+	// BeforeEncode is invoked with the endpoint result before it is encoded
+	// in the response. It is not invoked for the streaming endpoints. The
+	// returned context is used to encode the response. An error aborts the
+	// request.
+	BeforeEncode func(ctx context.Context, w http.ResponseWriter, res interface{}) (context.Context, error)
+	// OnError is invoked with the errors returned by the request decoder,
+	// the endpoint and the other hooks. The returned error is encoded in
+	// the response.
+	OnError func(ctx context.Context, r *http.Request, err error) error
+}
+
+// beforeDecode invokes the BeforeDecode hook if set.
+func (h *Hooks) beforeDecode(ctx context.Context, r *http.Request) (context.Context, error) {
+	if h == nil || h.BeforeDecode == nil {
+		return ctx, nil
+	}
+	return h.BeforeDecode(ctx, r)
+}
+
+// afterDecode invokes the AfterDecode hook if set.
+func (h *Hooks) afterDecode(ctx context.Context, r *http.Request, payload interface{}) (context.Context, error) {
+	if h == nil || h.AfterDecode == nil {
+		return ctx, nil
+	}
+	return h.AfterDecode(ctx, r, payload)
+}
+
+// beforeEncode invokes the BeforeEncode hook if set.
+func (h *Hooks) beforeEncode(ctx context.Context, w http.ResponseWriter, res interface{}) (context.Context, error) {
+	if h == nil || h.BeforeEncode == nil {
+		return ctx, nil
+	}
+	return h.BeforeEncode(ctx, w, res)
+}
+
+// onError invokes the OnError hook if set and returns the error to encode.
+func (h *Hooks) onError(ctx context.Context, r *http.Request, err error) error {
+	if h == nil || h.OnError == nil {
+		return err
+	}
+	return h.OnError(ctx, r, err)
+}
+`
@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/generator"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/hooks/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.HooksDSL)
+	roots := []eval.Root{expr.Root}
+	files, err := generator.Transport("", roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := len(files)
+	files, err = Generate("", roots, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != count+1 {
+		t.Fatalf("got %d new files, expected 1", len(files)-count)
+	}
+	hooks := files[len(files)-1]
+	if hooks.Path != filepath.Join("gen", "http", "catalog", "server", "hooks.go") {
+		t.Errorf("got path %q, expected gen/http/catalog/server/hooks.go", hooks.Path)
+	}
+	testCode(t, hooks, "hooks", []string{testdata.HooksCode})
+	var server *codegen.File
+	for _, f := range files {
+		if filepath.Base(f.Path) == "server.go" {
+			server = f
+		}
+	}
+	testCode(t, server, "server-struct", []string{testdata.ServerStructCode})
+	testCode(t, server, "server-init", []string{testdata.ServerInitCode})
+	testCode(t, server, "server-handler-init", []string{testdata.CreateHandlerCode, testdata.ListHandlerCode, testdata.WatchHandlerCode})
+}
+
+func testCode(t *testing.T, file *codegen.File, section string, expCode []string) {
+	sections := file.Section(section)
+	if len(sections) != len(expCode) {
+		t.Fatalf("%s: got %d sections, expected %d", section, len(sections), len(expCode))
+	}
+	for i, c := range expCode {
+		code := codegen.SectionCode(t, sections[i])
+		if code != c {
+			t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c))
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package testdata
+
+const CreateHandlerCode = `// NewCreateHandler creates a HTTP handler which loads the HTTP request and
+// calls the "Catalog" service "Create" endpoint.
+func NewCreateHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+	hooks *Hooks,
+) http.Handler {
+	var (
+		decodeRequest  = DecodeCreateRequest(mux, dec)
+		encodeResponse = EncodeCreateResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Create")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Catalog")
+		ctx, err := hooks.beforeDecode(ctx, r)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		ctx, err = hooks.afterDecode(ctx, r, payload)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, payload)
+
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		ctx, err = hooks.beforeEncode(ctx, w, res)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
+
+const ListHandlerCode = `// NewListHandler creates a HTTP handler which loads the HTTP request and calls
+// the "Catalog" service "List" endpoint.
+func NewListHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+	hooks *Hooks,
+) http.Handler {
+	var (
+		encodeResponse = EncodeListResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "List")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Catalog")
+		ctx, err := hooks.beforeDecode(ctx, r)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, nil)
+
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		ctx, err = hooks.beforeEncode(ctx, w, res)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
+
+const WatchHandlerCode = `// NewWatchHandler creates a HTTP handler which loads the HTTP request and
+// calls the "Catalog" service "Watch" endpoint.
+func NewWatchHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+	hooks *Hooks,
+	up goahttp.Upgrader,
+	connConfigFn goahttp.ConnConfigureFunc,
+) http.Handler {
+	var (
+		decodeRequest = DecodeWatchRequest(mux, dec)
+		encodeError   = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Watch")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Catalog")
+		ctx, err := hooks.beforeDecode(ctx, r)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		ctx, err = hooks.afterDecode(ctx, r, payload)
+		if err != nil {
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		var cancel context.CancelFunc
+		{
+			ctx, cancel = context.WithCancel(ctx)
+		}
+		v := &catalog.WatchEndpointInput{
+			Stream: &WatchServerStream{
+				upgrader:     up,
+				connConfigFn: connConfigFn,
+				cancel:       cancel,
+				w:            w,
+				r:            r,
+			},
+			Payload: payload.(string),
+		}
+		_, err = endpoint(ctx, v)
+
+		if err != nil {
+			if _, ok := err.(websocket.HandshakeError); ok {
+				return
+			}
+			err = hooks.onError(ctx, r, err)
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+	})
+}
+`
+
+const ServerInitCode = `// New instantiates HTTP handlers for all the Catalog service endpoints.
+func New(
+	e *catalog.Endpoints,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+	up goahttp.Upgrader,
+	cfn *ConnConfigurer,
+) *Server {
+	if cfn == nil {
+		cfn = &ConnConfigurer{}
+	}
+	hooks := &Hooks{}
+	return &Server{
+		Hooks: hooks,
+		Mounts: []*MountPoint{
+			{"Create", "POST", "/items"},
+			{"List", "GET", "/items"},
+			{"Watch", "GET", "/watch/{*path}"},
+		},
+		Create: NewCreateHandler(e.Create, mux, dec, enc, eh, hooks),
+		List:   NewListHandler(e.List, mux, dec, enc, eh, hooks),
+		Watch:  NewWatchHandler(e.Watch, mux, dec, enc, eh, hooks, up, cfn.WatchFn),
+	}
+}
+`
+
+const ServerStructCode = `// Server lists the Catalog service endpoint HTTP handlers.
+type Server struct {
+	Mounts []*MountPoint
+	// Hooks lists the functions invoked by the handlers.
+	Hooks  *Hooks
+	Create http.Handler
+	List   http.Handler
+	Watch  http.Handler
+}
+
+// ErrorNamer is an interface implemented by generated error structs that
+// exposes the name of the error as defined in the design.
+type ErrorNamer interface {
+	ErrorName() string
+}
+`
+
+const HooksCode = `// Hooks lists the functions invoked by the "Catalog" service HTTP handlers at
+// well-defined points of the request processing. The nil functions are
+// skipped. The hooks must be set before the server handles requests.
+type Hooks struct {
+	// BeforeDecode is invoked before the request is decoded. The returned
+	// context is used to process the rest of the request. An error aborts
+	// the request.
+	BeforeDecode func(ctx context.Context, r *http.Request) (context.Context, error)
+	// AfterDecode is invoked with the decoded payload before the endpoint
+	// is called. It is not invoked for the endpoints without payload. The
+	// returned context is used to process the rest of the request. An error
+	// aborts the request.
+	AfterDecode func(ctx context.Context, r *http.Request, payload interface{}) (context.Context, error)
+	// BeforeEncode is invoked with the endpoint result before it is encoded
+	// in the response. It is not invoked for the streaming endpoints. The
+	// returned context is used to encode the response. An error aborts the
+	// request.
+	BeforeEncode func(ctx context.Context, w http.ResponseWriter, res interface{}) (context.Context, error)
+	// OnError is invoked with the errors returned by the request decoder,
+	// the endpoint and the other hooks. The returned error is encoded in
+	// the response.
+	OnError func(ctx context.Context, r *http.Request, err error) error
+}
+
+// beforeDecode invokes the BeforeDecode hook if set.
+func (h *Hooks) beforeDecode(ctx context.Context, r *http.Request) (context.Context, error) {
+	if h == nil || h.BeforeDecode == nil {
+		return ctx, nil
+	}
+	return h.BeforeDecode(ctx, r)
+}
+
+// afterDecode invokes the AfterDecode hook if set.
+func (h *Hooks) afterDecode(ctx context.Context, r *http.Request, payload interface{}) (context.Context, error) {
+	if h == nil || h.AfterDecode == nil {
+		return ctx, nil
+	}
+	return h.AfterDecode(ctx, r, payload)
+}
+
+// beforeEncode invokes the BeforeEncode hook if set.
+func (h *Hooks) beforeEncode(ctx context.Context, w http.ResponseWriter, res interface{}) (context.Context, error) {
+	if h == nil || h.BeforeEncode == nil {
+		return ctx, nil
+	}
+	return h.BeforeEncode(ctx, w, res)
+}
+
+// onError invokes the OnError hook if set and returns the error to encode.
+func (h *Hooks) onError(ctx context.Context, r *http.Request, err error) error {
+	if h == nil || h.OnError == nil {
+		return err
+	}
+	return h.OnError(ctx, r, err)
+}
+`
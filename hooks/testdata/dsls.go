@@ -0,0 +1,32 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var HooksDSL = func() {
+	Service("Catalog", func() {
+		Method("Create", func() {
+			Payload(func() {
+				Attribute("name", String)
+			})
+			Result(String)
+			HTTP(func() {
+				POST("/items")
+			})
+		})
+		Method("List", func() {
+			Result(ArrayOf(String))
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+		Method("Watch", func() {
+			Payload(String)
+			StreamingResult(String)
+			HTTP(func() {
+				GET("/watch/{*path}")
+			})
+		})
+	})
+}
@@ -11,11 +11,18 @@ import (
 
 	goaexpr "goa.design/goa/v3/expr"
 	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
 	"goa.design/plugins/v3/i18n/expr"
+	"goa.design/plugins/v3/registry"
 )
 
 func init() {
-	codegen.RegisterPlugin("i18n", "gen", Prepare, Generate)
+	registry.Register(&registry.Plugin{
+		Name:     "i18n",
+		Cmd:      "gen",
+		Prepare:  Prepare,
+		Generate: Generate,
+	})
 }
 
 // ENVKEY is the key used to lookup locales to use when producing translation openapi specs
@@ -33,6 +40,9 @@ func getLocales() ([]string, error) {
 
 // Prepare executes all translations with the default language
 func Prepare(genpkg string, roots []eval.Root) error {
+	if !config.Enabled("i18n", "") {
+		return nil
+	}
 	locales, error := getLocales()
 
 	if error != nil {
@@ -86,6 +96,9 @@ func handleTitleTranslation(p eval.Expression, e []string) {
 // Generate produces additional openapi files for locales configured via
 // the system environment variable GOA_I18N
 func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("i18n", "") {
+		return files, nil
+	}
 	locales, _ := getLocales()
 
 	if len(locales) <= 1 {
@@ -0,0 +1,84 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/jsonapi/expr"
+	"goa.design/plugins/v3/genutil"
+
+	// Register code generators for the JSON:API plugin
+	_ "goa.design/plugins/v3/jsonapi"
+)
+
+// Resource declares the JSON:API representation of the enclosing type. The
+// HTTP responses of the methods whose result is the type, or a collection of
+// the type, are rendered as JSON:API documents with the
+// "application/vnd.api+json" content type. The ID attribute and the
+// relationship attributes are moved out of the "attributes" member of the
+// resource objects and the related resources are listed in the "included"
+// member of the documents.
+//
+// Resource must appear in a Type or ResultType expression.
+//
+// Resource accepts the JSON:API resource type as first argument and an
+// optional DSL function as second argument.
+//
+// Example:
+//
+//    import jsonapi "goa.design/plugins/v3/jsonapi/dsl"
+//
+//    var Article = ResultType("application/vnd.article", func() {
+//        jsonapi.Resource("articles", func() {
+//            jsonapi.ID("slug")              // Defaults to "id"
+//            jsonapi.Relationship("author")
+//            jsonapi.Relationship("comments")
+//        })
+//        Attributes(func() {
+//            Attribute("slug", String)
+//            Attribute("title", String)
+//            Attribute("author", Person)        // Person is also a resource
+//            Attribute("comments", ArrayOf(Comment))
+//        })
+//    })
+//
+func Resource(typ string, fn ...func()) {
+	ut := genutil.UserType(eval.Current())
+	if ut == nil {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	r := &expr.ResourceExpr{Type: typ, ID: "id", UserType: ut}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], r) {
+			return
+		}
+	}
+	expr.Root.Resources = append(expr.Root.Resources, r)
+}
+
+// ID sets the name of the attribute holding the resource ID, "id" by default.
+//
+// ID must appear in a Resource expression.
+func ID(name string) {
+	if r, ok := eval.Current().(*expr.ResourceExpr); ok {
+		r.ID = name
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Relationship declares an attribute holding a related resource or an array of
+// related resources. The type of the related resources must also be declared
+// with Resource.
+//
+// Relationship must appear in a Resource expression.
+func Relationship(name string) {
+	if r, ok := eval.Current().(*expr.ResourceExpr); ok {
+		r.Relationships = append(r.Relationships, name)
+		return
+	}
+	eval.IncompatibleDSL()
+}
@@ -0,0 +1,118 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// MediaType is the JSON:API media type.
+const MediaType = "application/vnd.api+json"
+
+type (
+	// ResourceExpr describes the JSON:API representation of a user type.
+	ResourceExpr struct {
+		// Type is the JSON:API resource type, e.g. "articles".
+		Type string
+		// ID is the name of the attribute holding the resource ID.
+		ID string
+		// Relationships lists the names of the attributes holding
+		// related resources.
+		Relationships []string
+		// UserType is the user type describing the resource.
+		UserType expr.UserType
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *ResourceExpr) EvalName() string {
+	return fmt.Sprintf("JSON:API resource %q of type %q", r.Type, r.UserType.Name())
+}
+
+// Prepare sets the content type of the successful HTTP responses of the
+// methods returning the resource.
+func (r *ResourceExpr) Prepare() {
+	for _, svc := range expr.Root.API.HTTP.Services {
+		for _, e := range svc.HTTPEndpoints {
+			if res, _ := Root.MethodResource(e.MethodExpr); res != r {
+				continue
+			}
+			for _, resp := range e.Responses {
+				if resp.StatusCode < 300 && resp.ContentType == "" {
+					resp.ContentType = MediaType
+				}
+			}
+		}
+	}
+}
+
+// Validate makes sure the ID and relationship attributes exist and that the
+// relationships hold resources.
+func (r *ResourceExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if r.Type == "" {
+		verr.Add(r, "resource type cannot be empty")
+	}
+	for _, other := range Root.Resources {
+		if other != r && other.Type == r.Type {
+			verr.Add(r, "resource type %q is also used by type %q", r.Type, other.UserType.Name())
+		}
+	}
+	obj := expr.AsObject(r.UserType.Attribute().Type)
+	if obj == nil {
+		verr.Add(r, "resource type must be an object")
+		return verr
+	}
+	if att := obj.Attribute(r.ID); att == nil {
+		verr.Add(r, "ID attribute %q not found", r.ID)
+	} else if !isPrimitive(att.Type) {
+		verr.Add(r, "ID attribute %q must be a primitive", r.ID)
+	}
+	for _, name := range r.Relationships {
+		att := obj.Attribute(name)
+		if att == nil {
+			verr.Add(r, "relationship attribute %q not found", name)
+			continue
+		}
+		if _, _, ok := r.Related(name); !ok {
+			verr.Add(r, "relationship attribute %q must be a resource or an array of resources", name)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Related returns the resource held by the given relationship attribute and
+// true if the relationship is to-many. The last value is false if the
+// attribute does not hold resources.
+func (r *ResourceExpr) Related(name string) (*ResourceExpr, bool, bool) {
+	obj := expr.AsObject(r.UserType.Attribute().Type)
+	if obj == nil {
+		return nil, false, false
+	}
+	att := obj.Attribute(name)
+	if att == nil {
+		return nil, false, false
+	}
+	many := false
+	t := att.Type
+	if arr := expr.AsArray(t); arr != nil {
+		many = true
+		t = arr.ElemType.Type
+	}
+	ut, _ := t.(expr.UserType)
+	res := Root.Resource(ut)
+	return res, many, res != nil
+}
+
+// isPrimitive returns true if t is a primitive type or an alias of one.
+func isPrimitive(t expr.DataType) bool {
+	if ut, ok := t.(expr.UserType); ok {
+		return isPrimitive(ut.Attribute().Type)
+	}
+	_, ok := t.(expr.Primitive)
+	return ok
+}
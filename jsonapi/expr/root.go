@@ -0,0 +1,78 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the JSON:API resources of the design.
+	RootExpr struct {
+		// Resources lists the resources in the order they appear in
+		// the design.
+		Resources []*ResourceExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "JSON:API plugin"
+}
+
+// WalkSets iterates over the resources.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	rexps := make(eval.ExpressionSet, len(r.Resources))
+	for i, res := range r.Resources {
+		rexps[i] = res
+	}
+	walk(rexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/jsonapi/dsl"}
+}
+
+// Resource returns the resource described by the given user type, nil if the
+// type is not a resource.
+func (r *RootExpr) Resource(ut expr.UserType) *ResourceExpr {
+	if ut == nil {
+		return nil
+	}
+	for _, res := range r.Resources {
+		if res.UserType.Name() == ut.Name() {
+			return res
+		}
+	}
+	return nil
+}
+
+// MethodResource returns the resource returned by the given method and true if
+// the method returns a collection of resources. It returns nil if the method
+// result is not a resource.
+func (r *RootExpr) MethodResource(m *expr.MethodExpr) (*ResourceExpr, bool) {
+	if m.Result == nil {
+		return nil, false
+	}
+	if arr := expr.AsArray(m.Result.Type); arr != nil {
+		ut, _ := arr.ElemType.Type.(expr.UserType)
+		return r.Resource(ut), true
+	}
+	ut, _ := m.Result.Type.(expr.UserType)
+	return r.Resource(ut), false
+}
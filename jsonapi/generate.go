@@ -0,0 +1,362 @@
+package jsonapi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/jsonapi/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// resourceData contains the data necessary to render the descriptor of
+	// a JSON:API resource.
+	resourceData struct {
+		// VarName is the name of the descriptor variable.
+		VarName string
+		// TypeName is the name of the user type.
+		TypeName string
+		// Type is the JSON:API resource type.
+		Type string
+		// ID is the name of the attribute holding the resource ID.
+		ID string
+		// NumericID is true if the ID attribute is a number.
+		NumericID bool
+		// Relationships lists the relationships of the resource.
+		Relationships []*relatedData
+	}
+
+	// relatedData describes a relationship.
+	relatedData struct {
+		// Name is the name of the attribute holding the related
+		// resources.
+		Name string
+		// VarName is the name of the descriptor variable of the related
+		// resource.
+		VarName string
+		// Many is true if the relationship is to-many.
+		Many bool
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "jsonapi",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the JSON:API resource descriptors of each HTTP server and
+// client, renders the responses of the methods returning resources as JSON:API
+// documents and documents them in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Resources) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("jsonapi", f) {
+			continue
+		}
+		transportJSONAPI(f)
+		documentJSONAPI(f)
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.API.HTTP.Services {
+				if !config.Enabled("jsonapi", svc.Name()) {
+					continue
+				}
+				data := resources(svc)
+				if len(data) == 0 {
+					continue
+				}
+				files = append(files, resourcesFile(svc, "server", data), resourcesFile(svc, "client", data))
+			}
+		}
+	}
+	return files, nil
+}
+
+// resources returns the descriptors of the resources returned by the methods
+// of the given HTTP service together with their related resources.
+func resources(svc *goaexpr.HTTPServiceExpr) []*resourceData {
+	var (
+		data []*resourceData
+		seen = make(map[*expr.ResourceExpr]bool)
+		add  func(*expr.ResourceExpr)
+	)
+	add = func(r *expr.ResourceExpr) {
+		if seen[r] {
+			return
+		}
+		seen[r] = true
+		att := goaexpr.AsObject(r.UserType.Attribute().Type).Attribute(r.ID)
+		rd := &resourceData{
+			VarName:   varName(r),
+			TypeName:  r.UserType.Name(),
+			Type:      r.Type,
+			ID:        r.ID,
+			NumericID: att != nil && isNumber(att.Type),
+		}
+		data = append(data, rd)
+		for _, name := range r.Relationships {
+			related, many, ok := r.Related(name)
+			if !ok {
+				continue
+			}
+			rd.Relationships = append(rd.Relationships, &relatedData{Name: name, VarName: varName(related), Many: many})
+			add(related)
+		}
+	}
+	for _, e := range svc.HTTPEndpoints {
+		if r, _ := expr.Root.MethodResource(e.MethodExpr); r != nil {
+			add(r)
+		}
+	}
+	return data
+}
+
+// resourcesFile returns the file defining the resource descriptors in the
+// given HTTP server or client package.
+func resourcesFile(svc *goaexpr.HTTPServiceExpr, pkg string, data []*resourceData) *codegen.File {
+	sd := httpcodegen.HTTPServices.Get(svc.Name())
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(sd.Service.VarName), pkg, "jsonapi.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name()+" HTTP "+pkg+" JSON:API resources", pkg, []*codegen.ImportSpec{
+				{Path: "goa.design/plugins/v3/jsonapi"},
+			}),
+			{Name: "jsonapi-resources", Source: resourcesT, Data: data},
+		},
+	}
+}
+
+// transportJSONAPI renders the responses of the methods returning resources
+// as JSON:API documents in the HTTP servers and decodes them in the HTTP
+// clients.
+func transportJSONAPI(f *codegen.File) {
+	if filepath.Base(f.Path) != "encode_decode.go" {
+		return
+	}
+	server := filepath.Base(filepath.Dir(f.Path)) == "server"
+	for _, s := range f.SectionTemplates {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok {
+			continue
+		}
+		svc := goaexpr.Root.Service(ed.ServiceName)
+		if svc == nil {
+			continue
+		}
+		m := svc.Method(ed.Method.Name)
+		if m == nil {
+			continue
+		}
+		r, _ := expr.Root.MethodResource(m)
+		if r == nil {
+			continue
+		}
+		switch {
+		case server && s.Name == "response-encoder":
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: "goa.design/plugins/v3/jsonapi"})
+			s.Source = strings.Replace(s.Source, "return enc.Encode(body)",
+				fmt.Sprintf("return jsonapi.Encode(enc, body, %s)", varName(r)), 1)
+		case !server && s.Name == "response-decoder":
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: "goa.design/plugins/v3/jsonapi"})
+			s.Source = strings.Replace(s.Source, "err = decoder(resp).Decode(&body)",
+				fmt.Sprintf("err = jsonapi.NewDecoder(decoder(resp), %s).Decode(&body)", varName(r)), -1)
+		}
+	}
+}
+
+// documentJSONAPI describes the successful responses of the operations
+// returning resources as JSON:API documents if f is an OpenAPI file.
+func documentJSONAPI(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("jsonapi", op) {
+				return nil
+			}
+			r, many := operationResource(op)
+			if r == nil {
+				return nil
+			}
+			op.Produces = []string{expr.MediaType}
+			for code, resp := range op.Responses {
+				if !strings.HasPrefix(code, "2") || resp.Schema == nil {
+					continue
+				}
+				// The JSON and YAML OpenAPI files share the same
+				// specification, the responses are only modified
+				// the first time.
+				if _, ok := resp.Schema.Properties["data"]; ok && resp.Schema.Ref == "" {
+					continue
+				}
+				resp.Schema = documentSchema(spec, r, many, resp.Schema)
+			}
+			return nil
+		})
+	})
+}
+
+// operationResource returns the resource returned by the method corresponding
+// to the given operation and true if the method returns a collection.
+func operationResource(op *openapi.Operation) (*expr.ResourceExpr, bool) {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	if m == nil {
+		return nil, false
+	}
+	return expr.Root.MethodResource(m)
+}
+
+// documentSchema returns the schema of the JSON:API document whose primary
+// data is described by the given body schema.
+func documentSchema(spec *openapi.V2, r *expr.ResourceExpr, many bool, body *openapi.Schema) *openapi.Schema {
+	attrs := definition(spec, body)
+	if many && attrs != nil {
+		attrs = definition(spec, attrs.Items)
+	}
+	data := objectSchema(r, attributesSchema(r, attrs, body))
+	if many {
+		data = &openapi.Schema{Type: openapi.Array, Items: data}
+	}
+	return &openapi.Schema{
+		Type: openapi.Object,
+		Properties: map[string]*openapi.Schema{
+			"data": data,
+			"included": {
+				Type:        openapi.Array,
+				Description: "Related resource objects.",
+				Items:       &openapi.Schema{Type: openapi.Object},
+			},
+		},
+		Required: []string{"data"},
+	}
+}
+
+// objectSchema returns the schema of the resource objects of r.
+func objectSchema(r *expr.ResourceExpr, attrs *openapi.Schema) *openapi.Schema {
+	props := map[string]*openapi.Schema{
+		"type":       {Type: openapi.String, Enum: []interface{}{r.Type}},
+		"id":         {Type: openapi.String},
+		"attributes": attrs,
+	}
+	if len(r.Relationships) > 0 {
+		rels := &openapi.Schema{Type: openapi.Object, Properties: make(map[string]*openapi.Schema)}
+		for _, name := range r.Relationships {
+			related, many, ok := r.Related(name)
+			if !ok {
+				continue
+			}
+			linkage := &openapi.Schema{
+				Type: openapi.Object,
+				Properties: map[string]*openapi.Schema{
+					"type": {Type: openapi.String, Enum: []interface{}{related.Type}},
+					"id":   {Type: openapi.String},
+				},
+				Required: []string{"type", "id"},
+			}
+			if many {
+				linkage = &openapi.Schema{Type: openapi.Array, Items: linkage}
+			}
+			rels.Properties[name] = &openapi.Schema{
+				Type:       openapi.Object,
+				Properties: map[string]*openapi.Schema{"data": linkage},
+			}
+		}
+		props["relationships"] = rels
+	}
+	return &openapi.Schema{
+		Type:       openapi.Object,
+		Properties: props,
+		Required:   []string{"type", "id"},
+	}
+}
+
+// attributesSchema returns the schema of the attributes member of the
+// resource objects of r given the definition of the plain resource. It
+// returns the original body schema if the definition could not be resolved.
+func attributesSchema(r *expr.ResourceExpr, def, body *openapi.Schema) *openapi.Schema {
+	if def == nil || def.Properties == nil {
+		return body
+	}
+	skip := map[string]bool{r.ID: true}
+	for _, name := range r.Relationships {
+		skip[name] = true
+	}
+	attrs := &openapi.Schema{Type: openapi.Object, Properties: make(map[string]*openapi.Schema)}
+	for n, p := range def.Properties {
+		if !skip[n] {
+			attrs.Properties[n] = p
+		}
+	}
+	for _, n := range def.Required {
+		if !skip[n] {
+			attrs.Required = append(attrs.Required, n)
+		}
+	}
+	return attrs
+}
+
+// definition returns the schema referenced by s or s itself if it is not a
+// reference.
+func definition(spec *openapi.V2, s *openapi.Schema) *openapi.Schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	return spec.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+}
+
+// varName returns the name of the descriptor variable of the given resource.
+func varName(r *expr.ResourceExpr) string {
+	return codegen.Goify(r.UserType.Name(), false) + "Resource"
+}
+
+// isNumber returns true if t is a numeric primitive or an alias of one.
+func isNumber(t goaexpr.DataType) bool {
+	if ut, ok := t.(goaexpr.UserType); ok {
+		return isNumber(ut.Attribute().Type)
+	}
+	switch t.Kind() {
+	case goaexpr.IntKind, goaexpr.Int32Kind, goaexpr.Int64Kind, goaexpr.UIntKind,
+		goaexpr.UInt32Kind, goaexpr.UInt64Kind, goaexpr.Float32Kind, goaexpr.Float64Kind:
+		return true
+	}
+	return false
+}
+
+// input: []*resourceData
+const resourcesT = `var (
+{{- range . }}
+	{{ printf "%s describes the JSON:API representation of the %s type." .VarName .TypeName | comment }}
+	{{ .VarName }} = &jsonapi.Resource{Type: {{ printf "%q" .Type }}, ID: {{ printf "%q" .ID }}{{ if .NumericID }}, NumericID: true{{ end }}}
+{{- end }}
+)
+{{- $rels := false }}{{ range . }}{{ if .Relationships }}{{ $rels = true }}{{ end }}{{ end }}
+{{- if $rels }}
+
+func init() {
+	{{- range . }}
+		{{- if .Relationships }}
+	{{ .VarName }}.Relationships = []*jsonapi.Related{
+			{{- range .Relationships }}
+		{Name: {{ printf "%q" .Name }}, Resource: {{ .VarName }}{{ if .Many }}, Many: true{{ end }}},
+			{{- end }}
+	}
+		{{- end }}
+	{{- end }}
+}
+{{- end }}
+`
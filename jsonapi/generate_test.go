@@ -0,0 +1,84 @@
+package jsonapi_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/jsonapi"
+	"goa.design/plugins/v3/jsonapi/expr"
+	"goa.design/plugins/v3/jsonapi/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	expr.Root.Resources = nil
+	codegen.RunDSLWithFunc(t, testdata.ArticleDSL, func() {
+		eval.Register(expr.Root)
+	})
+	fs := append(httpcodegen.ServerFiles("", goaexpr.Root), httpcodegen.ClientFiles("", goaexpr.Root)...)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = jsonapi.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resources int
+	for _, f := range fs {
+		pkg := filepath.Base(filepath.Dir(f.Path))
+		switch filepath.Base(f.Path) {
+		case "encode_decode.go":
+			if pkg == "server" {
+				var code string
+				for _, s := range f.Section("response-encoder") {
+					if s.Data.(*httpcodegen.EndpointData).Method.Name == "Show" {
+						code = codegen.SectionCode(t, s)
+					}
+				}
+				if code != testdata.ShowResponseEncoderCode {
+					t.Errorf("invalid response encoder code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ShowResponseEncoderCode))
+				}
+				continue
+			}
+			for _, s := range f.Section("response-decoder") {
+				code := codegen.SectionCode(t, s)
+				if !strings.Contains(code, "jsonapi.NewDecoder(decoder(resp), articleResource).Decode(&body)") {
+					t.Errorf("response decoder does not decode JSON:API documents:\n%s", code)
+				}
+			}
+		case "jsonapi.go":
+			resources++
+			sections := f.Section("jsonapi-resources")
+			if len(sections) != 1 {
+				t.Fatalf("got %d resources sections in %s, expected 1", len(sections), pkg)
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != testdata.ResourcesCode {
+				t.Errorf("invalid %s resources code, got:\n%s\ngot vs. expected:\n%s", pkg, code, codegen.Diff(t, code, testdata.ResourcesCode))
+			}
+		}
+	}
+	if resources != 2 {
+		t.Errorf("got %d jsonapi.go files, expected 2", resources)
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	for _, path := range []string{"/articles", "/articles/{slug}"} {
+		op := spec.Paths[path].(*openapi.Path).Get
+		if len(op.Produces) != 1 || op.Produces[0] != expr.MediaType {
+			t.Errorf("%s: got produces %v, expected %q", path, op.Produces, expr.MediaType)
+		}
+		schema := op.Responses["200"].Schema
+		if _, ok := schema.Properties["data"]; !ok {
+			t.Errorf("%s: response schema is not a JSON:API document", path)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// MediaType is the JSON:API media type.
+const MediaType = "application/vnd.api+json"
+
+type (
+	// Resource describes the JSON:API representation of a type.
+	Resource struct {
+		// Type is the JSON:API resource type.
+		Type string
+		// ID is the name of the JSON member holding the resource ID.
+		ID string
+		// NumericID is true if the JSON member holding the resource ID
+		// is a number. JSON:API IDs are always strings.
+		NumericID bool
+		// Relationships lists the JSON members holding related
+		// resources.
+		Relationships []*Related
+	}
+
+	// Related describes a JSON member holding related resources.
+	Related struct {
+		// Name is the name of the JSON member.
+		Name string
+		// Resource describes the related resources.
+		Resource *Resource
+		// Many is true if the member holds an array of resources.
+		Many bool
+	}
+
+	// Document is a JSON:API top-level document.
+	Document struct {
+		// Data is the primary data, a resource object or an array of
+		// resource objects.
+		Data interface{} `json:"data"`
+		// Included lists the related resource objects.
+		Included []*Object `json:"included,omitempty"`
+	}
+
+	// Object is a JSON:API resource object.
+	Object struct {
+		// Type is the resource type.
+		Type string `json:"type"`
+		// ID is the resource ID.
+		ID string `json:"id"`
+		// Attributes contains the resource attributes.
+		Attributes map[string]interface{} `json:"attributes,omitempty"`
+		// Relationships contains the resource relationships.
+		Relationships map[string]*Relationship `json:"relationships,omitempty"`
+	}
+
+	// Relationship is a JSON:API relationship object.
+	Relationship struct {
+		// Data is the resource linkage, an identifier, an array of
+		// identifiers or nil.
+		Data interface{} `json:"data"`
+	}
+
+	// Identifier is a JSON:API resource identifier object.
+	Identifier struct {
+		// Type is the resource type.
+		Type string `json:"type"`
+		// ID is the resource ID.
+		ID string `json:"id"`
+	}
+
+	// decoder decodes JSON:API documents into the plain representation of
+	// the resources.
+	decoder struct {
+		dec goahttp.Decoder
+		r   *Resource
+	}
+
+	// document is the representation of a JSON:API document used to
+	// decode it.
+	document struct {
+		Data     json.RawMessage `json:"data"`
+		Included []*Object       `json:"included"`
+	}
+
+	// flattener builds the plain representation of the resource objects
+	// of a document.
+	flattener struct {
+		included map[Identifier]*Object
+		visiting map[Identifier]bool
+	}
+
+	// builder builds the resource objects of a document.
+	builder struct {
+		included []*Object
+		seen     map[Identifier]bool
+	}
+)
+
+// Encode encodes v as a JSON:API document with enc. v must marshal to a JSON
+// object describing a resource or to an array of such objects.
+func Encode(enc goahttp.Encoder, v interface{}, r *Resource) error {
+	doc, err := NewDocument(v, r)
+	if err != nil {
+		return err
+	}
+	return enc.Encode(doc)
+}
+
+// NewDocument returns the JSON:API document whose primary data is v. v must
+// marshal to a JSON object describing a resource or to an array of such
+// objects.
+func NewDocument(v interface{}, r *Resource) (*Document, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var val interface{}
+	if err := unmarshal(b, &val); err != nil {
+		return nil, err
+	}
+	bld := &builder{seen: make(map[Identifier]bool)}
+	doc := &Document{}
+	switch actual := val.(type) {
+	case map[string]interface{}:
+		bld.seen[identifier(actual, r)] = true
+		doc.Data = bld.object(actual, r)
+	case []interface{}:
+		members := make([]map[string]interface{}, 0, len(actual))
+		for _, elem := range actual {
+			m, ok := elem.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("jsonapi: invalid %s resource, got %T", r.Type, elem)
+			}
+			bld.seen[identifier(m, r)] = true
+			members = append(members, m)
+		}
+		objs := make([]*Object, len(members))
+		for i, m := range members {
+			objs[i] = bld.object(m, r)
+		}
+		doc.Data = objs
+	case nil:
+	default:
+		return nil, fmt.Errorf("jsonapi: invalid %s resource, got %T", r.Type, val)
+	}
+	doc.Included = bld.included
+	return doc, nil
+}
+
+// object returns the resource object built from the JSON members m and adds
+// the related resources to the included objects.
+func (b *builder) object(m map[string]interface{}, r *Resource) *Object {
+	obj := &Object{Type: r.Type, ID: identifier(m, r).ID, Attributes: make(map[string]interface{})}
+	for k, v := range m {
+		obj.Attributes[k] = v
+	}
+	delete(obj.Attributes, r.ID)
+	for _, rel := range r.Relationships {
+		v := obj.Attributes[rel.Name]
+		delete(obj.Attributes, rel.Name)
+		if obj.Relationships == nil {
+			obj.Relationships = make(map[string]*Relationship)
+		}
+		if !rel.Many {
+			var data interface{}
+			if rm, ok := v.(map[string]interface{}); ok {
+				data = b.include(rm, rel.Resource)
+			}
+			obj.Relationships[rel.Name] = &Relationship{Data: data}
+			continue
+		}
+		ids := []*Identifier{}
+		if elems, ok := v.([]interface{}); ok {
+			for _, elem := range elems {
+				if rm, ok := elem.(map[string]interface{}); ok {
+					ids = append(ids, b.include(rm, rel.Resource))
+				}
+			}
+		}
+		obj.Relationships[rel.Name] = &Relationship{Data: ids}
+	}
+	return obj
+}
+
+// include adds the related resource to the included objects unless it is
+// already part of the document and returns its identifier.
+func (b *builder) include(m map[string]interface{}, r *Resource) *Identifier {
+	id := identifier(m, r)
+	if !b.seen[id] {
+		b.seen[id] = true
+		b.included = append(b.included, b.object(m, r))
+	}
+	return &id
+}
+
+// identifier returns the identifier of the resource described by m.
+func identifier(m map[string]interface{}, r *Resource) Identifier {
+	id := Identifier{Type: r.Type}
+	switch v := m[r.ID].(type) {
+	case nil:
+	case string:
+		id.ID = v
+	default:
+		id.ID = fmt.Sprint(v)
+	}
+	return id
+}
+
+// NewDecoder returns a decoder that decodes the JSON:API documents read by dec
+// into the plain representation of the resources, inlining the included
+// related resources. Values that are not JSON:API documents, e.g. errors, are
+// decoded as is.
+func NewDecoder(dec goahttp.Decoder, r *Resource) goahttp.Decoder {
+	return &decoder{dec: dec, r: r}
+}
+
+// Decode decodes the next value into v.
+func (d *decoder) Decode(v interface{}) error {
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return err
+	}
+	var doc document
+	if err := unmarshal(raw, &doc); err != nil || len(doc.Data) == 0 {
+		return json.Unmarshal(raw, v)
+	}
+	f := &flattener{included: make(map[Identifier]*Object), visiting: make(map[Identifier]bool)}
+	for _, obj := range doc.Included {
+		f.included[Identifier{Type: obj.Type, ID: obj.ID}] = obj
+	}
+	var plain interface{}
+	if bytes.HasPrefix(bytes.TrimSpace(doc.Data), []byte("[")) {
+		var objs []*Object
+		if err := unmarshal(doc.Data, &objs); err != nil {
+			return err
+		}
+		elems := make([]interface{}, len(objs))
+		for i, obj := range objs {
+			elems[i] = f.plain(obj, d.r)
+		}
+		plain = elems
+	} else {
+		var obj *Object
+		if err := unmarshal(doc.Data, &obj); err != nil {
+			return err
+		}
+		if obj != nil {
+			plain = f.plain(obj, d.r)
+		}
+	}
+	b, err := json.Marshal(plain)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// plain returns the plain representation of the resource object.
+func (f *flattener) plain(obj *Object, r *Resource) map[string]interface{} {
+	m := make(map[string]interface{}, len(obj.Attributes)+len(r.Relationships)+1)
+	for k, v := range obj.Attributes {
+		m[k] = v
+	}
+	if r.NumericID {
+		m[r.ID] = json.Number(obj.ID)
+	} else {
+		m[r.ID] = obj.ID
+	}
+	id := Identifier{Type: obj.Type, ID: obj.ID}
+	f.visiting[id] = true
+	defer delete(f.visiting, id)
+	for _, rel := range r.Relationships {
+		ro, ok := obj.Relationships[rel.Name]
+		if !ok || ro.Data == nil {
+			continue
+		}
+		if !rel.Many {
+			if rm, ok := ro.Data.(map[string]interface{}); ok {
+				m[rel.Name] = f.related(rm, rel.Resource)
+			}
+			continue
+		}
+		elems, _ := ro.Data.([]interface{})
+		related := make([]interface{}, 0, len(elems))
+		for _, elem := range elems {
+			if rm, ok := elem.(map[string]interface{}); ok {
+				related = append(related, f.related(rm, rel.Resource))
+			}
+		}
+		m[rel.Name] = related
+	}
+	return m
+}
+
+// related returns the plain representation of the related resource with the
+// given identifier. Only the ID is set if the resource is not included or if
+// it is being flattened already.
+func (f *flattener) related(rm map[string]interface{}, r *Resource) map[string]interface{} {
+	id := Identifier{Type: fmt.Sprint(rm["type"]), ID: fmt.Sprint(rm["id"])}
+	if obj, ok := f.included[id]; ok && !f.visiting[id] {
+		return f.plain(obj, r)
+	}
+	return f.plain(&Object{Type: id.Type, ID: id.ID}, &Resource{Type: r.Type, ID: r.ID, NumericID: r.NumericID})
+}
+
+// unmarshal decodes the JSON data into v, decoding numbers as json.Number to
+// preserve them.
+func unmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
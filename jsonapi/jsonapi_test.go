@@ -0,0 +1,115 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type (
+	article struct {
+		Slug     string     `json:"slug"`
+		Title    string     `json:"title"`
+		Author   *person    `json:"author,omitempty"`
+		Comments []*comment `json:"comments,omitempty"`
+	}
+
+	person struct {
+		ID   string `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+
+	comment struct {
+		ID   int    `json:"id"`
+		Body string `json:"body,omitempty"`
+	}
+)
+
+var (
+	personResource  = &Resource{Type: "people", ID: "id"}
+	commentResource = &Resource{Type: "comments", ID: "id", NumericID: true}
+	articleResource = &Resource{Type: "articles", ID: "slug", Relationships: []*Related{
+		{Name: "author", Resource: personResource},
+		{Name: "comments", Resource: commentResource, Many: true},
+	}}
+)
+
+func TestNewDocument(t *testing.T) {
+	ann := &person{ID: "p1", Name: "Ann"}
+	cases := []struct {
+		Name     string
+		Value    interface{}
+		Expected string
+	}{
+		{"single", &article{Slug: "a", Title: "A", Author: ann, Comments: []*comment{{ID: 1, Body: "hi"}}},
+			`{"data":{"type":"articles","id":"a","attributes":{"title":"A"},"relationships":{"author":{"data":{"type":"people","id":"p1"}},"comments":{"data":[{"type":"comments","id":"1"}]}}},` +
+				`"included":[{"type":"people","id":"p1","attributes":{"name":"Ann"}},{"type":"comments","id":"1","attributes":{"body":"hi"}}]}`},
+		{"collection", []*article{{Slug: "a", Title: "A", Author: ann}, {Slug: "b", Title: "B", Author: ann}},
+			`{"data":[{"type":"articles","id":"a","attributes":{"title":"A"},"relationships":{"author":{"data":{"type":"people","id":"p1"}},"comments":{"data":[]}}},` +
+				`{"type":"articles","id":"b","attributes":{"title":"B"},"relationships":{"author":{"data":{"type":"people","id":"p1"}},"comments":{"data":[]}}}],` +
+				`"included":[{"type":"people","id":"p1","attributes":{"name":"Ann"}}]}`},
+		{"no-relationship", &article{Slug: "a", Title: "A"},
+			`{"data":{"type":"articles","id":"a","attributes":{"title":"A"},"relationships":{"author":{"data":null},"comments":{"data":[]}}}}`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			doc, err := NewDocument(c.Value, articleResource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != c.Expected {
+				t.Errorf("got:\n%s\nexpected:\n%s", b, c.Expected)
+			}
+		})
+	}
+}
+
+func TestNewDecoder(t *testing.T) {
+	ann := &person{ID: "p1", Name: "Ann"}
+	cases := []struct {
+		Name  string
+		Value interface{}
+		Into  interface{}
+	}{
+		{"single", &article{Slug: "a", Title: "A", Author: ann, Comments: []*comment{{ID: 1, Body: "hi"}}}, &article{}},
+		{"collection", []*article{{Slug: "a", Title: "A", Author: ann}, {Slug: "b", Title: "B", Author: ann}}, &[]*article{}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			doc, err := NewDocument(c.Value, articleResource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := NewDecoder(json.NewDecoder(bytes.NewReader(b)), articleResource).Decode(c.Into); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(c.Into)
+			expected, _ := json.Marshal(c.Value)
+			if string(got) != string(expected) {
+				t.Errorf("got %s, expected %s", got, expected)
+			}
+		})
+	}
+}
+
+func TestNewDecoderPlain(t *testing.T) {
+	var v struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}
+	body := `{"name":"not_found","message":"article not found"}`
+	if err := NewDecoder(json.NewDecoder(bytes.NewReader([]byte(body))), articleResource).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "not_found" || v.Message != "article not found" {
+		t.Errorf("got %+v, expected error body to be decoded as is", v)
+	}
+}
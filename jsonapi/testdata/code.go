@@ -0,0 +1,32 @@
+package testdata
+
+var ShowResponseEncoderCode = `// EncodeShowResponse returns an encoder for responses returned by the Blog
+// Show endpoint.
+func EncodeShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		res := v.(*blogviews.Article)
+		ctx = context.WithValue(ctx, goahttp.ContentTypeKey, "application/vnd.api+json")
+		enc := encoder(ctx, w)
+		body := NewShowResponseBody(res.Projected)
+		w.WriteHeader(http.StatusOK)
+		return jsonapi.Encode(enc, body, articleResource)
+	}
+}
+`
+
+var ResourcesCode = `var (
+	// articleResource describes the JSON:API representation of the Article type.
+	articleResource = &jsonapi.Resource{Type: "articles", ID: "slug"}
+	// personResource describes the JSON:API representation of the Person type.
+	personResource = &jsonapi.Resource{Type: "people", ID: "id"}
+	// commentResource describes the JSON:API representation of the Comment type.
+	commentResource = &jsonapi.Resource{Type: "comments", ID: "id", NumericID: true}
+)
+
+func init() {
+	articleResource.Relationships = []*jsonapi.Related{
+		{Name: "author", Resource: personResource},
+		{Name: "comments", Resource: commentResource, Many: true},
+	}
+}
+`
@@ -0,0 +1,55 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	jsonapi "goa.design/plugins/v3/jsonapi/dsl"
+)
+
+var ArticleDSL = func() {
+	var Person = ResultType("application/vnd.person", func() {
+		jsonapi.Resource("people")
+		Attributes(func() {
+			Attribute("id", String)
+			Attribute("name", String)
+			Required("id", "name")
+		})
+	})
+	var Comment = Type("Comment", func() {
+		jsonapi.Resource("comments")
+		Attribute("id", Int)
+		Attribute("body", String)
+		Required("id")
+	})
+	var Article = ResultType("application/vnd.article", func() {
+		jsonapi.Resource("articles", func() {
+			jsonapi.ID("slug")
+			jsonapi.Relationship("author")
+			jsonapi.Relationship("comments")
+		})
+		Attributes(func() {
+			Attribute("slug", String)
+			Attribute("title", String)
+			Attribute("author", Person)
+			Attribute("comments", ArrayOf(Comment))
+			Required("slug", "title")
+		})
+	})
+	Service("Blog", func() {
+		Method("Show", func() {
+			Payload(func() {
+				Attribute("slug", String)
+			})
+			Result(Article)
+			HTTP(func() {
+				GET("/articles/{slug}")
+			})
+		})
+		Method("List", func() {
+			Result(CollectionOf(Article))
+			HTTP(func() {
+				GET("/articles")
+				Response(StatusOK)
+			})
+		})
+	})
+}
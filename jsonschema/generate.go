@@ -0,0 +1,49 @@
+package jsonschema
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "jsonschema",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces one JSON schema file per user type defined in the design.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("jsonschema", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, ut := range userTypes(r) {
+				path := filepath.Join(codegen.Gendir, "schemas", fileName(ut))
+				files = append(files, stream.File(path, "jsonschema", typeSchema(ut), stream.IndentedJSON))
+			}
+		}
+	}
+	return files, nil
+}
+
+// userTypes returns the user and result types defined in the design in the
+// order they appear.
+func userTypes(r *goaexpr.RootExpr) []goaexpr.UserType {
+	uts := make([]goaexpr.UserType, 0, len(r.Types)+len(r.ResultTypes))
+	uts = append(uts, r.Types...)
+	for _, rt := range r.ResultTypes {
+		uts = append(uts, rt)
+	}
+	return uts
+}
@@ -0,0 +1,30 @@
+package jsonschema_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/jsonschema"
+	"goa.design/plugins/v3/jsonschema/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	root := codegen.RunDSL(t, testdata.TypesDSL)
+	fs, err := jsonschema.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range fs {
+		names = append(names, filepath.Base(f.Path))
+	}
+	if len(fs) != 3 {
+		t.Fatalf("got files %v, expected Bottle.json, Winery.json and StoredBottle.json", names)
+	}
+	for _, f := range fs {
+		plugintest.Golden(t, filepath.Base(f.Path), plugintest.Render(t, f))
+	}
+}
@@ -0,0 +1,157 @@
+package jsonschema
+
+import (
+	goaexpr "goa.design/goa/v3/expr"
+)
+
+// dialect is the URI of the JSON Schema dialect of the generated schemas.
+const dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is the subset of JSON Schema used to describe the design types.
+type schema struct {
+	// Schema is the URI of the JSON Schema dialect, only set on the root
+	// schema of a file.
+	Schema string `json:"$schema,omitempty"`
+	// ID is the identifier of the schema, only set on the root schema of a
+	// file.
+	ID string `json:"$id,omitempty"`
+	// Ref is the reference to the schema of another design type.
+	Ref string `json:"$ref,omitempty"`
+	// Title is the name of the design type.
+	Title string `json:"title,omitempty"`
+	// Description describes the value.
+	Description string `json:"description,omitempty"`
+	// Type is the JSON type of the value.
+	Type string `json:"type,omitempty"`
+	// Format is the format of string values.
+	Format string `json:"format,omitempty"`
+	// ContentEncoding is the encoding of binary values.
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	// Enum lists the valid values.
+	Enum []interface{} `json:"enum,omitempty"`
+	// Default is the default value.
+	Default interface{} `json:"default,omitempty"`
+	// Pattern is the regular expression string values must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Minimum is the minimum value of numbers.
+	Minimum *float64 `json:"minimum,omitempty"`
+	// Maximum is the maximum value of numbers.
+	Maximum *float64 `json:"maximum,omitempty"`
+	// MinLength is the minimum length of strings.
+	MinLength *int `json:"minLength,omitempty"`
+	// MaxLength is the maximum length of strings.
+	MaxLength *int `json:"maxLength,omitempty"`
+	// MinItems is the minimum number of array items.
+	MinItems *int `json:"minItems,omitempty"`
+	// MaxItems is the maximum number of array items.
+	MaxItems *int `json:"maxItems,omitempty"`
+	// MinProperties is the minimum number of map entries.
+	MinProperties *int `json:"minProperties,omitempty"`
+	// MaxProperties is the maximum number of map entries.
+	MaxProperties *int `json:"maxProperties,omitempty"`
+	// Items is the schema of the array items.
+	Items *schema `json:"items,omitempty"`
+	// Properties contains the schemas of the object properties.
+	Properties map[string]*schema `json:"properties,omitempty"`
+	// AdditionalProperties is the schema of the map values.
+	AdditionalProperties *schema `json:"additionalProperties,omitempty"`
+	// Required lists the required object properties.
+	Required []string `json:"required,omitempty"`
+}
+
+// formats maps the goa validation formats to the JSON Schema formats. The goa
+// formats without equivalent are not described.
+var formats = map[goaexpr.ValidationFormat]string{
+	goaexpr.FormatDate:     "date",
+	goaexpr.FormatDateTime: "date-time",
+	goaexpr.FormatUUID:     "uuid",
+	goaexpr.FormatEmail:    "email",
+	goaexpr.FormatHostname: "hostname",
+	goaexpr.FormatIPv4:     "ipv4",
+	goaexpr.FormatIPv6:     "ipv6",
+	goaexpr.FormatURI:      "uri",
+	goaexpr.FormatRegexp:   "regex",
+}
+
+// typeSchema returns the JSON schema of the given user type. The schemas of
+// the other user types are referenced using their file name.
+func typeSchema(ut goaexpr.UserType) *schema {
+	s := attributeSchema(&goaexpr.AttributeExpr{
+		Type:        ut.Attribute().Type,
+		Description: ut.Attribute().Description,
+		Validation:  ut.Attribute().Validation,
+	})
+	s.Schema = dialect
+	s.ID = fileName(ut)
+	s.Title = ut.Name()
+	return s
+}
+
+// attributeSchema returns the JSON schema of the given attribute.
+func attributeSchema(att *goaexpr.AttributeExpr) *schema {
+	s := &schema{Description: att.Description, Default: att.DefaultValue}
+	switch t := att.Type.(type) {
+	case goaexpr.UserType:
+		s.Ref = fileName(t)
+		return s
+	case *goaexpr.Array:
+		s.Type = "array"
+		s.Items = attributeSchema(t.ElemType)
+	case *goaexpr.Map:
+		s.Type = "object"
+		s.AdditionalProperties = attributeSchema(t.ElemType)
+	case *goaexpr.Object:
+		s.Type = "object"
+		s.Properties = make(map[string]*schema, len(*t))
+		for _, nat := range *t {
+			s.Properties[nat.Name] = attributeSchema(nat.Attribute)
+		}
+	case goaexpr.Primitive:
+		switch t.Kind() {
+		case goaexpr.BooleanKind:
+			s.Type = "boolean"
+		case goaexpr.IntKind, goaexpr.Int32Kind, goaexpr.Int64Kind,
+			goaexpr.UIntKind, goaexpr.UInt32Kind, goaexpr.UInt64Kind:
+			s.Type = "integer"
+		case goaexpr.Float32Kind, goaexpr.Float64Kind:
+			s.Type = "number"
+		case goaexpr.StringKind:
+			s.Type = "string"
+		case goaexpr.BytesKind:
+			s.Type = "string"
+			s.ContentEncoding = "base64"
+		}
+	}
+	validate(s, att)
+	return s
+}
+
+// validate adds the validations of the given attribute to s.
+func validate(s *schema, att *goaexpr.AttributeExpr) {
+	v := att.Validation
+	if v == nil {
+		return
+	}
+	s.Enum = v.Values
+	s.Format = formats[v.Format]
+	s.Pattern = v.Pattern
+	s.Minimum = v.Minimum
+	s.Maximum = v.Maximum
+	switch s.Type {
+	case "array":
+		s.MinItems, s.MaxItems = v.MinLength, v.MaxLength
+	case "object":
+		if s.Properties == nil {
+			s.MinProperties, s.MaxProperties = v.MinLength, v.MaxLength
+		}
+		s.Required = v.Required
+	default:
+		s.MinLength, s.MaxLength = v.MinLength, v.MaxLength
+	}
+}
+
+// fileName returns the name of the file containing the schema of the given
+// user type.
+func fileName(ut goaexpr.UserType) string {
+	return ut.Name() + ".json"
+}
@@ -0,0 +1,59 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var TypesDSL = func() {
+	var Winery = Type("Winery", func() {
+		Description("Winery producing wines")
+		Attribute("name", String, "Name of the winery")
+		Attribute("url", String, func() {
+			Format(FormatURI)
+		})
+		Required("name")
+	})
+	var Bottle = Type("Bottle", func() {
+		Description("Bottle of wine")
+		Attribute("id", String, func() {
+			Format(FormatUUID)
+		})
+		Attribute("name", String, func() {
+			Pattern("^[A-Za-z ]+$")
+			MaxLength(100)
+		})
+		Attribute("vintage", Int, func() {
+			Minimum(1900)
+			Maximum(2100)
+		})
+		Attribute("color", String, func() {
+			Enum("red", "white", "rose")
+			Default("red")
+		})
+		Attribute("tags", ArrayOf(String), func() {
+			MinLength(1)
+		})
+		Attribute("ratings", MapOf(String, Float64))
+		Attribute("label", Bytes)
+		Attribute("winery", Winery)
+		Required("id", "name")
+	})
+	var StoredBottle = ResultType("application/vnd.stored-bottle", func() {
+		TypeName("StoredBottle")
+		Attributes(func() {
+			Attribute("bottle", Bottle)
+			Attribute("stored_at", String, func() {
+				Format(FormatDateTime)
+			})
+		})
+	})
+	Service("cellar", func() {
+		Method("show", func() {
+			Payload(Winery)
+			Result(StoredBottle)
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
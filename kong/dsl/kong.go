@@ -0,0 +1,65 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/kong/expr"
+
+	// Register code generators for the Kong plugin
+	_ "goa.design/plugins/v3/kong"
+)
+
+// RateLimit limits the number of requests made to the API or to the service
+// endpoints during the given period using the Kong rate-limiting plugin.
+// period is one of "second", "minute", "hour", "day", "month" or "year".
+// RateLimit may be called multiple times with different periods.
+//
+// RateLimit may appear in an API or Service expression. Service level rate
+// limits override the API level rate limit.
+//
+// Example:
+//
+//    import kong "goa.design/plugins/v3/kong/dsl"
+//
+//    var _ = API("calc", func() {
+//        kong.RateLimit(10, "second")
+//        kong.RateLimit(1000, "hour")
+//    })
+//
+func RateLimit(limit int, period string) {
+	var parent eval.Expression
+	switch e := eval.Current().(type) {
+	case *goaexpr.APIExpr, *goaexpr.ServiceExpr:
+		parent = e
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	var valid bool
+	for _, p := range expr.Periods {
+		if p == period {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		eval.InvalidArgError("second, minute, hour, day, month or year", period)
+		return
+	}
+	var rl *expr.RateLimitExpr
+	for _, r := range expr.Root.RateLimits {
+		if r.Parent == parent {
+			rl = r
+			break
+		}
+	}
+	if rl == nil {
+		rl = &expr.RateLimitExpr{Limits: make(map[string]int), Parent: parent}
+		expr.Root.RateLimits = append(expr.Root.RateLimits, rl)
+	}
+	if _, ok := rl.Limits[period]; ok {
+		eval.ReportError("rate limit per %s defined twice", period)
+		return
+	}
+	rl.Limits[period] = limit
+}
@@ -0,0 +1,42 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+)
+
+// Periods lists the rate limit periods supported by Kong.
+var Periods = []string{"second", "minute", "hour", "day", "month", "year"}
+
+type (
+	// RateLimitExpr describes the rate limit of the API or of a service.
+	RateLimitExpr struct {
+		// Limits contains the maximum number of requests indexed by
+		// period.
+		Limits map[string]int
+		// Parent is the API or service expression.
+		Parent eval.Expression
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *RateLimitExpr) EvalName() string {
+	var suffix string
+	if r.Parent != nil {
+		suffix = " of " + r.Parent.EvalName()
+	}
+	return "Kong rate limit" + suffix
+}
+
+// Validate ensures the rate limit expression is valid.
+func (r *RateLimitExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	for _, p := range Periods {
+		if l, ok := r.Limits[p]; ok && l <= 0 {
+			verr.Add(r, "limit per %s must be strictly positive", p)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
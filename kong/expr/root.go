@@ -0,0 +1,67 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the Kong rate limits defined in the design.
+	RootExpr struct {
+		// RateLimits lists the rate limits in the order they appear in
+		// the design.
+		RateLimits []*RateLimitExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "Kong plugin"
+}
+
+// WalkSets iterates over the rate limits.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	rexps := make(eval.ExpressionSet, len(r.RateLimits))
+	for i, rl := range r.RateLimits {
+		rexps[i] = rl
+	}
+	walk(rexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/kong/dsl"}
+}
+
+// RateLimit returns the rate limit of the given service. Service level rate
+// limits override the API level rate limit. RateLimit returns nil if the
+// service is not rate limited.
+func (r *RootExpr) RateLimit(svc string) *RateLimitExpr {
+	var api *RateLimitExpr
+	for _, rl := range r.RateLimits {
+		switch p := rl.Parent.(type) {
+		case *expr.ServiceExpr:
+			if p.Name == svc {
+				return rl
+			}
+		case *expr.APIExpr:
+			api = rl
+		}
+	}
+	return api
+}
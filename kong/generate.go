@@ -0,0 +1,290 @@
+package kong
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	plugincfg "goa.design/plugins/v3/config"
+	corsexpr "goa.design/plugins/v3/cors/expr"
+	"goa.design/plugins/v3/kong/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+// formatVersion is the version of the decK declarative configuration format.
+const formatVersion = "1.1"
+
+type (
+	// config is the decK declarative configuration.
+	config struct {
+		// FormatVersion is the version of the configuration format.
+		FormatVersion string `yaml:"_format_version"`
+		// Services lists the Kong services.
+		Services []*kongService `yaml:"services"`
+	}
+
+	// kongService is a Kong service, that is an upstream API.
+	kongService struct {
+		// Name is the service name.
+		Name string `yaml:"name"`
+		// URL is the URL of the upstream API.
+		URL string `yaml:"url"`
+		// Routes lists the routes of the service.
+		Routes []*route `yaml:"routes,omitempty"`
+		// Plugins lists the plugins applied to all the service routes.
+		Plugins []*plugin `yaml:"plugins,omitempty"`
+	}
+
+	// route is a Kong route which matches the requests made to an endpoint.
+	route struct {
+		// Name is the route name.
+		Name string `yaml:"name"`
+		// Methods lists the HTTP methods matched by the route.
+		Methods []string `yaml:"methods"`
+		// Paths lists the paths matched by the route.
+		Paths []string `yaml:"paths"`
+		// StripPath is always false so that the upstream API receives
+		// the request paths unchanged.
+		StripPath bool `yaml:"strip_path"`
+		// Plugins lists the plugins applied to the route.
+		Plugins []*plugin `yaml:"plugins,omitempty"`
+	}
+
+	// plugin is a Kong plugin configuration.
+	plugin struct {
+		// Name is the plugin name.
+		Name string `yaml:"name"`
+		// Config is the plugin configuration.
+		Config map[string]interface{} `yaml:"config,omitempty"`
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "kong",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the decK declarative configuration of the Kong services
+// and routes which proxy the requests made to the HTTP endpoints.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			if r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+				continue
+			}
+			path := filepath.Join(codegen.Gendir, "kong", "kong.yaml")
+			files = append(files, stream.File(path, "kong-config", build(r), stream.YAML))
+		}
+	}
+	return files, nil
+}
+
+// build computes the declarative configuration from the design.
+func build(r *goaexpr.RootExpr) *config {
+	cfg := &config{FormatVersion: formatVersion}
+	for _, svc := range r.API.HTTP.Services {
+		if !plugincfg.Enabled("kong", svc.Name()) {
+			continue
+		}
+		ks := &kongService{
+			Name: codegen.KebabCase(svc.Name()),
+			URL:  upstream(r, svc.Name()),
+		}
+		for _, e := range svc.HTTPEndpoints {
+			for i, rt := range e.Routes {
+				name := ks.Name + "-" + codegen.KebabCase(e.Name())
+				if i > 0 {
+					name = fmt.Sprintf("%s-%d", name, i)
+				}
+				paths := rt.FullPaths()
+				for j, p := range paths {
+					paths[j] = routePath(p)
+				}
+				ks.Routes = append(ks.Routes, &route{
+					Name:    name,
+					Methods: []string{rt.Method},
+					Paths:   paths,
+					Plugins: authPlugins(e),
+				})
+			}
+		}
+		if p := corsPlugin(svc.Name()); p != nil {
+			ks.Plugins = append(ks.Plugins, p)
+		}
+		if rl := expr.Root.RateLimit(svc.Name()); rl != nil {
+			limits := make(map[string]interface{}, len(rl.Limits))
+			for p, l := range rl.Limits {
+				limits[p] = l
+			}
+			ks.Plugins = append(ks.Plugins, &plugin{Name: "rate-limiting", Config: limits})
+		}
+		cfg.Services = append(cfg.Services, ks)
+	}
+	return cfg
+}
+
+// upstream returns the URL of the first HTTP host of the first server which
+// exposes the given service. The host variables are replaced with their
+// default values.
+func upstream(r *goaexpr.RootExpr, svc string) string {
+	for _, s := range r.API.Servers {
+		var found bool
+		for _, name := range s.Services {
+			if name == svc {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, h := range s.Hosts {
+			for _, u := range h.URIs {
+				uri := string(u)
+				if !strings.HasPrefix(uri, "http") {
+					continue
+				}
+				for _, v := range *goaexpr.AsObject(h.Attribute().Type) {
+					if v.Attribute.DefaultValue != nil {
+						uri = strings.Replace(uri, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+					}
+				}
+				return uri
+			}
+		}
+	}
+	return "http://localhost:80"
+}
+
+// routePath returns the Kong path of the given endpoint path. The paths with
+// wildcards are turned into anchored regular expressions which capture the
+// path parameters.
+func routePath(p string) string {
+	if !goaexpr.HTTPWildcardRegex.MatchString(p) {
+		return p
+	}
+	var (
+		re   strings.Builder
+		last int
+	)
+	for _, m := range goaexpr.HTTPWildcardRegex.FindAllStringSubmatchIndex(p, -1) {
+		re.WriteString(regexp.QuoteMeta(p[last:m[0]]))
+		name := p[m[2]:m[3]]
+		if strings.HasPrefix(p[m[0]:], "/{*") {
+			re.WriteString("/(?<" + name + ">.*)")
+		} else {
+			re.WriteString("/(?<" + name + ">[^/]+)")
+		}
+		last = m[1]
+	}
+	re.WriteString(regexp.QuoteMeta(p[last:]))
+	return re.String() + "$"
+}
+
+// authPlugins returns the authentication plugins of the given endpoint. Kong
+// requires all the authentication plugins of a route to succeed so only the
+// schemes of the first security requirement are used. OAuth2 schemes are
+// ignored as the Kong OAuth2 plugin implements an authorization server.
+func authPlugins(e *goaexpr.HTTPEndpointExpr) []*plugin {
+	if len(e.Requirements) == 0 {
+		return nil
+	}
+	var plugins []*plugin
+	for _, s := range e.Requirements[0].Schemes {
+		switch s.Kind {
+		case goaexpr.APIKeyKind:
+			plugins = append(plugins, &plugin{
+				Name:   "key-auth",
+				Config: map[string]interface{}{"key_names": []string{s.Name}},
+			})
+		case goaexpr.JWTKind:
+			key := "header_names"
+			if s.In == "query" {
+				key = "uri_param_names"
+			}
+			plugins = append(plugins, &plugin{
+				Name:   "jwt",
+				Config: map[string]interface{}{key: []string{s.Name}},
+			})
+		case goaexpr.BasicAuthKind:
+			plugins = append(plugins, &plugin{Name: "basic-auth"})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// corsPlugin returns the CORS plugin of the given service computed from the
+// origins defined with the CORS plugin DSL, nil if there is none. Kong
+// applies the same policy to all origins so the methods and headers of all
+// the origins are merged.
+func corsPlugin(svc string) *plugin {
+	origins := corsexpr.Origins(svc)
+	if len(origins) == 0 {
+		return nil
+	}
+	var (
+		names, methods, headers, exposed []string
+		maxAge                           uint
+		credentials                      bool
+	)
+	for _, o := range origins {
+		origin := o.Origin
+		if !o.Regexp && origin != "*" && strings.Contains(origin, "*") {
+			origin = strings.Replace(regexp.QuoteMeta(origin), `\*`, ".*", 1)
+		}
+		names = append(names, origin)
+		methods = merge(methods, o.Methods)
+		headers = merge(headers, o.Headers)
+		exposed = merge(exposed, o.Exposed)
+		if o.MaxAge > maxAge {
+			maxAge = o.MaxAge
+		}
+		credentials = credentials || o.Credentials
+	}
+	cfg := map[string]interface{}{"origins": names}
+	if len(methods) > 0 {
+		cfg["methods"] = methods
+	}
+	if len(headers) > 0 {
+		cfg["headers"] = headers
+	}
+	if len(exposed) > 0 {
+		cfg["exposed_headers"] = exposed
+	}
+	if maxAge > 0 {
+		cfg["max_age"] = maxAge
+	}
+	if credentials {
+		cfg["credentials"] = true
+	}
+	return &plugin{Name: "cors", Config: cfg}
+}
+
+// merge appends the elements of vals missing from vs to vs.
+func merge(vs, vals []string) []string {
+	for _, v := range vals {
+		var found bool
+		for _, existing := range vs {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
@@ -0,0 +1,31 @@
+package kong_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	corsexpr "goa.design/plugins/v3/cors/expr"
+	"goa.design/plugins/v3/kong"
+	"goa.design/plugins/v3/kong/expr"
+	"goa.design/plugins/v3/kong/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.RateLimits = nil
+	corsexpr.Root.APIOrigins = map[string]*corsexpr.OriginExpr{}
+	corsexpr.Root.ServiceOrigins = map[string]map[string]*corsexpr.OriginExpr{}
+	root := codegen.RunDSLWithFunc(t, testdata.ConfigDSL, func() {
+		eval.Register(corsexpr.Root)
+		eval.Register(expr.Root)
+	})
+	fs, err := kong.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	plugintest.Golden(t, "kong.yaml", plugintest.Render(t, fs[0]))
+}
@@ -0,0 +1,78 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	cors "goa.design/plugins/v3/cors/dsl"
+	kong "goa.design/plugins/v3/kong/dsl"
+)
+
+var ConfigDSL = func() {
+	var APIKeyAuth = APIKeySecurity("api_key")
+	var JWTAuth = JWTSecurity("jwt")
+	API("calc", func() {
+		Server("calc", func() {
+			Host("production", func() {
+				URI("https://{region}.calc.example.com")
+				Variable("region", String, func() {
+					Default("us-east-1")
+				})
+			})
+		})
+		cors.Origin("*.example.com", func() {
+			cors.Methods("GET", "POST")
+			cors.Headers("X-Api-Key")
+			cors.MaxAge(600)
+		})
+		kong.RateLimit(10, "second")
+		kong.RateLimit(1000, "hour")
+	})
+	Service("calc", func() {
+		cors.Origin("https://admin.example.com", func() {
+			cors.Methods("DELETE")
+			cors.Expose("X-Time")
+			cors.Credentials()
+		})
+		kong.RateLimit(100, "minute")
+		HTTP(func() {
+			Path("/calc")
+		})
+		Method("add", func() {
+			Security(APIKeyAuth)
+			Payload(func() {
+				APIKey("api_key", "key", String)
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+				GET("/sum/{a}/{b}")
+				Header("key:X-Api-Key")
+			})
+		})
+		Method("reset", func() {
+			Security(JWTAuth)
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+		Method("download", func() {
+			Payload(func() {
+				Attribute("path", String)
+			})
+			HTTP(func() {
+				GET("/files/{*path}")
+			})
+		})
+	})
+	Service("health", func() {
+		Method("show", func() {
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+}
@@ -0,0 +1,129 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/kubernetes/expr"
+
+	// Register code generators for the Kubernetes plugin
+	_ "goa.design/plugins/v3/kubernetes"
+)
+
+// Deployment describes the Kubernetes deployment of the server. The servers
+// without a deployment are deployed using one replica of the image named
+// after the server.
+//
+// Deployment must appear in a Server expression.
+//
+// Deployment accepts a DSL function as argument.
+//
+// Example:
+//
+//    import kubernetes "goa.design/plugins/v3/kubernetes/dsl"
+//
+//    var _ = API("calc", func() {
+//        Server("calc", func() {
+//            kubernetes.Deployment(func() {
+//                kubernetes.Image("registry.example.com/calc:1.2.0")
+//                kubernetes.Requests("100m", "128Mi")
+//                kubernetes.Limits("500m", "256Mi")
+//                kubernetes.Autoscale(2, 10, 80)
+//            })
+//        })
+//    })
+//
+func Deployment(fn func()) {
+	s, ok := eval.Current().(*goaexpr.ServerExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if expr.Root.Deployment(s.Name) != nil {
+		eval.ReportError("deployment of server %q defined twice", s.Name)
+		return
+	}
+	d := &expr.DeploymentExpr{Replicas: 1, Server: s}
+	if !eval.Execute(fn, d) {
+		return
+	}
+	expr.Root.Deployments = append(expr.Root.Deployments, d)
+}
+
+// Image sets the container image, the server name followed by the "latest"
+// tag by default.
+//
+// Image must appear in a Deployment expression.
+func Image(image string) {
+	if d, ok := eval.Current().(*expr.DeploymentExpr); ok {
+		d.Image = image
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Replicas sets the number of replicas, 1 by default. Replicas is ignored if
+// autoscaling is enabled.
+//
+// Replicas must appear in a Deployment expression.
+func Replicas(n int) {
+	if d, ok := eval.Current().(*expr.DeploymentExpr); ok {
+		d.Replicas = n
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Requests sets the CPU and memory requested by the container using the
+// Kubernetes quantity syntax (e.g. "100m" and "128Mi"). An empty string
+// leaves the corresponding resource unspecified.
+//
+// Requests must appear in a Deployment expression.
+func Requests(cpu, memory string) {
+	if d, ok := eval.Current().(*expr.DeploymentExpr); ok {
+		d.Requests = resources(cpu, memory)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Limits sets the CPU and memory limits of the container using the
+// Kubernetes quantity syntax. An empty string leaves the corresponding
+// resource unspecified.
+//
+// Limits must appear in a Deployment expression.
+func Limits(cpu, memory string) {
+	if d, ok := eval.Current().(*expr.DeploymentExpr); ok {
+		d.Limits = resources(cpu, memory)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Autoscale enables the horizontal pod autoscaler which scales the deployment
+// between min and max replicas targeting the given average CPU utilization in
+// percent of the requested CPU.
+//
+// Autoscale must appear in a Deployment expression.
+func Autoscale(min, max, cpuUtilization int) {
+	if d, ok := eval.Current().(*expr.DeploymentExpr); ok {
+		d.Autoscale = &expr.AutoscaleExpr{
+			MinReplicas:    min,
+			MaxReplicas:    max,
+			CPUUtilization: cpuUtilization,
+		}
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// resources returns the resources map of the given CPU and memory quantities.
+func resources(cpu, memory string) map[string]string {
+	res := make(map[string]string)
+	if cpu != "" {
+		res["cpu"] = cpu
+	}
+	if memory != "" {
+		res["memory"] = memory
+	}
+	return res
+}
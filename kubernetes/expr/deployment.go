@@ -0,0 +1,70 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// DeploymentExpr describes the Kubernetes deployment of a server.
+	DeploymentExpr struct {
+		// Image is the container image.
+		Image string
+		// Replicas is the number of replicas when autoscaling is not
+		// enabled.
+		Replicas int
+		// Requests contains the requested compute resources indexed
+		// by resource name ("cpu" or "memory").
+		Requests map[string]string
+		// Limits contains the compute resource limits indexed by
+		// resource name ("cpu" or "memory").
+		Limits map[string]string
+		// Autoscale describes the horizontal pod autoscaler, nil if
+		// autoscaling is not enabled.
+		Autoscale *AutoscaleExpr
+		// Server is the deployed server.
+		Server *expr.ServerExpr
+	}
+
+	// AutoscaleExpr describes the horizontal pod autoscaler of a
+	// deployment.
+	AutoscaleExpr struct {
+		// MinReplicas is the minimum number of replicas.
+		MinReplicas int
+		// MaxReplicas is the maximum number of replicas.
+		MaxReplicas int
+		// CPUUtilization is the target average CPU utilization in
+		// percent of the requested CPU.
+		CPUUtilization int
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (d *DeploymentExpr) EvalName() string {
+	return fmt.Sprintf("Kubernetes deployment of %s", d.Server.EvalName())
+}
+
+// Validate ensures the deployment expression is valid.
+func (d *DeploymentExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if d.Replicas < 1 {
+		verr.Add(d, "replicas must be greater than 0")
+	}
+	if a := d.Autoscale; a != nil {
+		if a.MinReplicas < 1 || a.MaxReplicas < a.MinReplicas {
+			verr.Add(d, "invalid autoscaling replicas, minimum must be greater than 0 and lower than maximum")
+		}
+		if a.CPUUtilization < 1 || a.CPUUtilization > 100 {
+			verr.Add(d, "invalid target CPU utilization %d, must be between 1 and 100", a.CPUUtilization)
+		}
+		if _, ok := d.Requests["cpu"]; !ok {
+			verr.Add(d, "autoscaling on CPU utilization requires a CPU request")
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the deployments defined in the design.
+	RootExpr struct {
+		// Deployments lists the deployments in the order they appear
+		// in the design.
+		Deployments []*DeploymentExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "Kubernetes plugin"
+}
+
+// WalkSets iterates over the deployments.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	dexps := make(eval.ExpressionSet, len(r.Deployments))
+	for i, d := range r.Deployments {
+		dexps[i] = d
+	}
+	walk(dexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/kubernetes/dsl"}
+}
+
+// Deployment returns the deployment of the given server, nil if there isn't
+// one.
+func (r *RootExpr) Deployment(server string) *DeploymentExpr {
+	for _, d := range r.Deployments {
+		if d.Server.Name == server {
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	healthcheck "goa.design/plugins/v3/healthcheck/expr"
+	"goa.design/plugins/v3/kubernetes/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+type (
+	// resource is a Kubernetes resource.
+	resource struct {
+		APIVersion string                 `yaml:"apiVersion"`
+		Kind       string                 `yaml:"kind"`
+		Metadata   map[string]interface{} `yaml:"metadata"`
+		Spec       map[string]interface{} `yaml:"spec"`
+	}
+
+	// port is a port exposed by the server.
+	port struct {
+		// Name is the port name ("http" or "grpc").
+		Name string
+		// Number is the port number.
+		Number int
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "kubernetes",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the Deployment, Service, Ingress and HorizontalPodAutoscaler
+// manifests of each server defined in the design.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("kubernetes", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, s := range r.API.Servers {
+				files = append(files, manifestFile(s))
+			}
+		}
+	}
+	return files, nil
+}
+
+// manifestFile returns the file containing the manifests of the given server.
+func manifestFile(s *goaexpr.ServerExpr) *codegen.File {
+	name := codegen.KebabCase(s.Name)
+	d := expr.Root.Deployment(s.Name)
+	if d == nil {
+		d = &expr.DeploymentExpr{Replicas: 1, Server: s}
+	}
+	ports, hosts := portsAndHosts(s)
+	resources := []*resource{deployment(name, d, ports), service(name, ports)}
+	if ing := ingress(name, ports, hosts); ing != nil {
+		resources = append(resources, ing)
+	}
+	if d.Autoscale != nil {
+		resources = append(resources, autoscaler(name, d.Autoscale))
+	}
+	path := filepath.Join(codegen.Gendir, "kubernetes", name+".yaml")
+	return stream.File(path, "kubernetes-manifests", resources, stream.YAML)
+}
+
+// portsAndHosts returns the ports and the public host names of the server
+// URIs. The host variables are replaced with their default values.
+func portsAndHosts(s *goaexpr.ServerExpr) ([]*port, []string) {
+	var (
+		ports []*port
+		hosts []string
+	)
+	for _, h := range s.Hosts {
+		for _, uri := range h.URIs {
+			u := string(uri)
+			for _, v := range *goaexpr.AsObject(h.Attribute().Type) {
+				if v.Attribute.DefaultValue != nil {
+					u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+				}
+			}
+			parsed, err := url.Parse(u)
+			if err != nil {
+				continue
+			}
+			name := "http"
+			if strings.HasPrefix(parsed.Scheme, "grpc") {
+				name = "grpc"
+			}
+			number, err := strconv.Atoi(parsed.Port())
+			if err != nil {
+				number = 80
+				if parsed.Scheme == "https" || parsed.Scheme == "grpcs" {
+					number = 443
+				}
+			}
+			var found bool
+			for _, p := range ports {
+				if p.Name == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				ports = append(ports, &port{Name: name, Number: number})
+			}
+			if name == "http" && isPublic(parsed.Hostname()) {
+				var dup bool
+				for _, h := range hosts {
+					if h == parsed.Hostname() {
+						dup = true
+						break
+					}
+				}
+				if !dup {
+					hosts = append(hosts, parsed.Hostname())
+				}
+			}
+		}
+	}
+	return ports, hosts
+}
+
+// isPublic returns true if host is a public host name, that is neither
+// localhost nor an IP address.
+func isPublic(host string) bool {
+	return host != "" && host != "localhost" && net.ParseIP(host) == nil
+}
+
+// deployment returns the Deployment manifest.
+func deployment(name string, d *expr.DeploymentExpr, ports []*port) *resource {
+	image := d.Image
+	if image == "" {
+		image = name + ":latest"
+	}
+	var cports []map[string]interface{}
+	for _, p := range ports {
+		cports = append(cports, map[string]interface{}{"name": p.Name, "containerPort": p.Number})
+	}
+	container := map[string]interface{}{
+		"name":  name,
+		"image": image,
+	}
+	if len(cports) > 0 {
+		container["ports"] = cports
+	}
+	res := make(map[string]interface{})
+	if len(d.Requests) > 0 {
+		res["requests"] = d.Requests
+	}
+	if len(d.Limits) > 0 {
+		res["limits"] = d.Limits
+	}
+	if len(res) > 0 {
+		container["resources"] = res
+	}
+	if hc := healthcheck.Root.HealthCheck; hc != nil && hasPort(ports, "http") {
+		container["livenessProbe"] = map[string]interface{}{
+			"httpGet": map[string]interface{}{"path": hc.LivenessPath, "port": "http"},
+		}
+		container["readinessProbe"] = map[string]interface{}{
+			"httpGet":        map[string]interface{}{"path": hc.ReadinessPath, "port": "http"},
+			"timeoutSeconds": hc.Timeout,
+		}
+	}
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": labels(name)},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels(name)},
+			"spec":     map[string]interface{}{"containers": []interface{}{container}},
+		},
+	}
+	if d.Autoscale == nil {
+		spec["replicas"] = d.Replicas
+	}
+	return &resource{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Metadata:   metadata(name),
+		Spec:       spec,
+	}
+}
+
+// service returns the Service manifest.
+func service(name string, ports []*port) *resource {
+	var sports []map[string]interface{}
+	for _, p := range ports {
+		sports = append(sports, map[string]interface{}{"name": p.Name, "port": p.Number, "targetPort": p.Name})
+	}
+	spec := map[string]interface{}{"selector": labels(name)}
+	if len(sports) > 0 {
+		spec["ports"] = sports
+	}
+	return &resource{
+		APIVersion: "v1",
+		Kind:       "Service",
+		Metadata:   metadata(name),
+		Spec:       spec,
+	}
+}
+
+// ingress returns the Ingress manifest which routes the requests made to the
+// public host names to the service, nil if there is no public host name.
+func ingress(name string, ports []*port, hosts []string) *resource {
+	if len(hosts) == 0 || !hasPort(ports, "http") {
+		return nil
+	}
+	rules := make([]map[string]interface{}, len(hosts))
+	for i, h := range hosts {
+		rules[i] = map[string]interface{}{
+			"host": h,
+			"http": map[string]interface{}{
+				"paths": []map[string]interface{}{{
+					"path":     "/",
+					"pathType": "Prefix",
+					"backend": map[string]interface{}{
+						"service": map[string]interface{}{
+							"name": name,
+							"port": map[string]interface{}{"name": "http"},
+						},
+					},
+				}},
+			},
+		}
+	}
+	return &resource{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Metadata:   metadata(name),
+		Spec:       map[string]interface{}{"rules": rules},
+	}
+}
+
+// autoscaler returns the HorizontalPodAutoscaler manifest.
+func autoscaler(name string, a *expr.AutoscaleExpr) *resource {
+	return &resource{
+		APIVersion: "autoscaling/v2",
+		Kind:       "HorizontalPodAutoscaler",
+		Metadata:   metadata(name),
+		Spec: map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       name,
+			},
+			"minReplicas": a.MinReplicas,
+			"maxReplicas": a.MaxReplicas,
+			"metrics": []map[string]interface{}{{
+				"type": "Resource",
+				"resource": map[string]interface{}{
+					"name": "cpu",
+					"target": map[string]interface{}{
+						"type":               "Utilization",
+						"averageUtilization": a.CPUUtilization,
+					},
+				},
+			}},
+		},
+	}
+}
+
+// metadata returns the metadata of the resources of the given server.
+func metadata(name string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "labels": labels(name)}
+}
+
+// labels returns the labels of the resources of the given server.
+func labels(name string) map[string]string {
+	return map[string]string{"app.kubernetes.io/name": name}
+}
+
+// hasPort returns true if ports contains a port with the given name.
+func hasPort(ports []*port, name string) bool {
+	for _, p := range ports {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
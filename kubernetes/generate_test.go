@@ -0,0 +1,42 @@
+package kubernetes_test
+
+import (
+	"fmt"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	healthcheck "goa.design/plugins/v3/healthcheck/expr"
+	"goa.design/plugins/v3/kubernetes"
+	"goa.design/plugins/v3/kubernetes/expr"
+	"goa.design/plugins/v3/kubernetes/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+	}{
+		{"default", testdata.DefaultDSL},
+		{"deployment", testdata.DeploymentDSL},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Deployments = nil
+			healthcheck.Root.HealthCheck = nil
+			root := codegen.RunDSLWithFunc(t, c.DSL, func() {
+				eval.Register(healthcheck.Root)
+				eval.Register(expr.Root)
+			})
+			fs, err := kubernetes.Generate("", []eval.Root{root}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			plugintest.Golden(t, fmt.Sprintf("%s.yaml", c.Name), plugintest.Render(t, fs[0]))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	healthcheck "goa.design/plugins/v3/healthcheck/dsl"
+	kubernetes "goa.design/plugins/v3/kubernetes/dsl"
+)
+
+var DefaultDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			Host("local", func() {
+				URI("http://localhost:8000")
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			HTTP(func() {
+				GET("/add")
+			})
+		})
+	})
+}
+
+var DeploymentDSL = func() {
+	API("calc", func() {
+		healthcheck.HealthCheck()
+		Server("calc-server", func() {
+			Host("production", func() {
+				URI("https://{region}.calc.example.com:8443")
+				URI("grpcs://{region}.calc.example.com:9443")
+				Variable("region", String, func() {
+					Default("eu")
+				})
+			})
+			kubernetes.Deployment(func() {
+				kubernetes.Image("registry.example.com/calc:1.2.0")
+				kubernetes.Requests("100m", "128Mi")
+				kubernetes.Limits("", "256Mi")
+				kubernetes.Autoscale(2, 10, 80)
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			HTTP(func() {
+				GET("/add")
+			})
+			GRPC(func() {})
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/links/expr"
+	"goa.design/plugins/v3/genutil"
+
+	// Register code generators for the links plugin
+	_ "goa.design/plugins/v3/links"
+)
+
+// Links declares the links of the enclosing type to the related HTTP
+// endpoints. The type gets a "links" attribute holding the path of each link
+// and the generated service packages define a PopulateLinks method on the
+// type which sets the attribute. The generated endpoints of the methods whose
+// result is the type, or a collection of the type, call PopulateLinks before
+// returning.
+//
+// Links must appear in a Type or ResultType expression.
+//
+// Links accepts a DSL function listing the links as argument.
+//
+// Example:
+//
+//    import links "goa.design/plugins/v3/links/dsl"
+//
+//    var ArticlePage = ResultType("application/vnd.article-page", func() {
+//        links.Links(func() {
+//            links.Link("self", "blog", "list")
+//            links.Link("next", "blog", "list", func() {
+//                links.Param("cursor", "next_cursor")
+//            })
+//        })
+//        Attributes(func() {
+//            Attribute("articles", CollectionOf(Article))
+//            Attribute("next_cursor", String)
+//        })
+//    })
+//
+func Links(fn func()) {
+	ut := genutil.UserType(eval.Current())
+	if ut == nil {
+		eval.IncompatibleDSL()
+		return
+	}
+	l := expr.Root.TypeLinks(ut)
+	if l == nil {
+		l = &expr.LinksExpr{UserType: ut}
+		expr.Root.Links = append(expr.Root.Links, l)
+	}
+	eval.Execute(fn, l)
+}
+
+// Link declares a link to the HTTP endpoint of the given service method. rel
+// is the link relation, e.g. "self", "next" or "author". The path parameters
+// of the endpoint are set to the values of the attributes of the type with
+// the same names unless mapped explicitly with Param. The link is only set
+// when all the attributes holding the parameter values are set.
+//
+// Link must appear in a Links expression.
+//
+// Link accepts the link relation, the service name and the method name as
+// arguments and an optional DSL function as last argument.
+//
+// Example:
+//
+//    links.Link("author", "people", "show", func() {
+//        links.Param("id", "author_id")
+//    })
+//
+func Link(rel, svc, method string, fn ...func()) {
+	l, ok := eval.Current().(*expr.LinksExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	link := &expr.LinkExpr{Rel: rel, Service: svc, Method: method, Params: make(map[string]string), Parent: l}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], link) {
+			return
+		}
+	}
+	l.Links = append(l.Links, link)
+}
+
+// Param sets the value of the given path or query parameter of the linked
+// endpoint to the value of the given attribute of the type.
+//
+// Param must appear in a Link expression.
+//
+// Param accepts the parameter name and the attribute name as arguments.
+func Param(name, attribute string) {
+	if link, ok := eval.Current().(*expr.LinkExpr); ok {
+		link.Params[name] = attribute
+		return
+	}
+	eval.IncompatibleDSL()
+}
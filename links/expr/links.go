@@ -0,0 +1,239 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// AttributeName is the name of the attribute holding the links of a type.
+const AttributeName = "links"
+
+type (
+	// LinksExpr describes the links of a user type to the related
+	// endpoints.
+	LinksExpr struct {
+		// UserType is the type declaring the links.
+		UserType expr.UserType
+		// Links lists the links in the order they appear in the design.
+		Links []*LinkExpr
+		// Type is the type of the links attribute added to UserType.
+		Type *expr.UserTypeExpr
+	}
+
+	// LinkExpr describes a link to an HTTP endpoint.
+	LinkExpr struct {
+		// Rel is the link relation, e.g. "self" or "next".
+		Rel string
+		// Service is the name of the service of the endpoint.
+		Service string
+		// Method is the name of the method of the endpoint.
+		Method string
+		// Params maps the names of the endpoint path and query
+		// parameters to the names of the attributes holding their
+		// values.
+		Params map[string]string
+		// Parent is the links expression declaring the link.
+		Parent *LinksExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LinksExpr) EvalName() string {
+	return fmt.Sprintf("links of type %q", l.UserType.Name())
+}
+
+// Prepare adds the links attribute to the type and to its views. The links
+// that depend on optional attributes are optional.
+func (l *LinksExpr) Prepare() {
+	obj := expr.AsObject(l.UserType.Attribute().Type)
+	if obj == nil || obj.Attribute(AttributeName) != nil {
+		return
+	}
+	var (
+		links    expr.Object
+		required []string
+	)
+	for _, link := range l.Links {
+		links.Set(link.Rel, &expr.AttributeExpr{
+			Type:         expr.String,
+			Description:  fmt.Sprintf("Path to the %q endpoint of the %q service.", link.Method, link.Service),
+			UserExamples: []*expr.ExampleExpr{{Summary: "default", Value: link.Pattern()}},
+		})
+		if !link.Optional() {
+			required = append(required, link.Rel)
+		}
+	}
+	l.Type = &expr.UserTypeExpr{
+		TypeName: l.UserType.Name() + "Links",
+		AttributeExpr: &expr.AttributeExpr{
+			Type:        &links,
+			Description: "Paths of the related endpoints.",
+			Validation:  &expr.ValidationExpr{Required: required},
+		},
+	}
+	att := &expr.AttributeExpr{Type: l.Type, Description: "Links to the related endpoints."}
+	obj.Set(AttributeName, att)
+	if rt, ok := l.UserType.(*expr.ResultTypeExpr); ok {
+		for _, v := range rt.Views {
+			if vobj := expr.AsObject(v.Type); vobj != nil {
+				vobj.Set(AttributeName, expr.DupAtt(att))
+			}
+		}
+	}
+}
+
+// Validate makes sure the type is an object that does not define the links
+// attribute and that the links are valid.
+func (l *LinksExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if expr.AsObject(l.UserType.Attribute().Type) == nil {
+		verr.Add(l, "type must be an object")
+		return verr
+	}
+	if l.Type == nil {
+		verr.Add(l, "type cannot define the %q attribute", AttributeName)
+	}
+	rels := make(map[string]bool)
+	for _, link := range l.Links {
+		if rels[link.Rel] {
+			verr.Add(l, "link %q is declared more than once", link.Rel)
+		}
+		rels[link.Rel] = true
+		if err := link.Validate(); err != nil {
+			verr.Merge(err.(*eval.ValidationErrors))
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LinkExpr) EvalName() string {
+	return fmt.Sprintf("link %q of type %q", l.Rel, l.Parent.UserType.Name())
+}
+
+// Validate makes sure the endpoint exists and that the values of all its path
+// parameters and of the mapped query parameters are primitive attributes of
+// the type.
+func (l *LinkExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if l.Rel == "" {
+		verr.Add(l, "link relation cannot be empty")
+	}
+	e := l.Endpoint()
+	if e == nil {
+		verr.Add(l, "HTTP endpoint %q of service %q not found", l.Method, l.Service)
+		return verr
+	}
+	query := make(map[string]bool)
+	if expr.AsObject(e.QueryParams().Type) != nil {
+		expr.WalkMappedAttr(e.QueryParams(), func(_, elem string, _ *expr.AttributeExpr) error {
+			query[elem] = true
+			return nil
+		})
+	}
+	path := make(map[string]bool)
+	for _, w := range expr.ExtractHTTPWildcards(l.Pattern()) {
+		path[w] = true
+	}
+	for _, p := range sortedKeys(l.Params) {
+		if !path[p] && !query[p] {
+			verr.Add(l, "%q is not a path or query parameter of the endpoint", p)
+		}
+	}
+	obj := expr.AsObject(l.Parent.UserType.Attribute().Type)
+	for _, p := range append(expr.ExtractHTTPWildcards(l.Pattern()), l.QueryParams()...) {
+		if !path[p] && !query[p] {
+			continue
+		}
+		name := l.AttributeName(p)
+		att := obj.Attribute(name)
+		if att == nil {
+			verr.Add(l, "attribute %q holding the value of parameter %q not found", name, p)
+			continue
+		}
+		if !expr.IsPrimitive(att.Type) {
+			verr.Add(l, "attribute %q holding the value of parameter %q must be a primitive", name, p)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Endpoint returns the linked HTTP endpoint, nil if not found.
+func (l *LinkExpr) Endpoint() *expr.HTTPEndpointExpr {
+	svc := expr.Root.API.HTTP.Service(l.Service)
+	if svc == nil {
+		return nil
+	}
+	return svc.Endpoint(l.Method)
+}
+
+// Pattern returns the full path pattern of the first route of the linked
+// endpoint.
+func (l *LinkExpr) Pattern() string {
+	e := l.Endpoint()
+	if e == nil || len(e.Routes) == 0 {
+		return ""
+	}
+	return e.Routes[0].FullPaths()[0]
+}
+
+// QueryParams returns the names of the mapped parameters that are not path
+// parameters sorted alphabetically.
+func (l *LinkExpr) QueryParams() []string {
+	path := make(map[string]bool)
+	for _, w := range expr.ExtractHTTPWildcards(l.Pattern()) {
+		path[w] = true
+	}
+	var query []string
+	for _, p := range sortedKeys(l.Params) {
+		if !path[p] {
+			query = append(query, p)
+		}
+	}
+	return query
+}
+
+// AttributeName returns the name of the attribute holding the value of the
+// given parameter. Path parameters that are not mapped explicitly are held by
+// the attributes with the same name.
+func (l *LinkExpr) AttributeName(param string) string {
+	if name, ok := l.Params[param]; ok {
+		return name
+	}
+	return param
+}
+
+// Optional returns true if any of the attributes holding the parameter
+// values may be unset. Optional links are only set when all these attributes
+// are.
+func (l *LinkExpr) Optional() bool {
+	att := l.Parent.UserType.Attribute()
+	if expr.AsObject(att.Type) == nil {
+		return false
+	}
+	for _, p := range append(expr.ExtractHTTPWildcards(l.Pattern()), l.QueryParams()...) {
+		if att.IsPrimitivePointer(l.AttributeName(p), true) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of m sorted alphabetically.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the links declared on the types of the
+	// design.
+	RootExpr struct {
+		// Links lists the links of each type in the order they appear
+		// in the design.
+		Links []*LinksExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "links plugin"
+}
+
+// WalkSets iterates over the links.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	lexps := make(eval.ExpressionSet, len(r.Links))
+	for i, l := range r.Links {
+		lexps[i] = l
+	}
+	walk(lexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/links/dsl"}
+}
+
+// TypeLinks returns the links declared on the given user type, nil if the type
+// declares no link.
+func (r *RootExpr) TypeLinks(ut expr.UserType) *LinksExpr {
+	if ut == nil {
+		return nil
+	}
+	for _, l := range r.Links {
+		if l.UserType.Name() == ut.Name() {
+			return l
+		}
+	}
+	return nil
+}
+
+// MethodLinks returns the links of the type returned by the given method and
+// true if the method returns a collection of the type. It returns nil if the
+// method result declares no link.
+func (r *RootExpr) MethodLinks(m *expr.MethodExpr) (*LinksExpr, bool) {
+	if m.Result == nil {
+		return nil, false
+	}
+	if arr := expr.AsArray(m.Result.Type); arr != nil {
+		ut, _ := arr.ElemType.Type.(expr.UserType)
+		return r.TypeLinks(ut), true
+	}
+	ut, _ := m.Result.Type.(expr.UserType)
+	return r.TypeLinks(ut), false
+}
@@ -0,0 +1,314 @@
+package links
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/links/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// typeData contains the data necessary to render the method populating
+	// the links of a type.
+	typeData struct {
+		// TypeName is the name of the Go type.
+		TypeName string
+		// LinksTypeName is the name of the Go type of the links field.
+		LinksTypeName string
+		// Links lists the links that are always set.
+		Links []*linkData
+		// OptionalLinks lists the links set only when the attributes
+		// holding the parameter values are set.
+		OptionalLinks []*linkData
+		// Fields lists the fields holding values whose links must be
+		// populated as well.
+		Fields []*fieldData
+	}
+
+	// linkData describes a link.
+	linkData struct {
+		// FieldName is the name of the field of the links type.
+		FieldName string
+		// Path is the Go expression computing the link path.
+		Path string
+		// Conditions lists the fields that must be set for the link to
+		// be set.
+		Conditions []string
+	}
+
+	// fieldData describes a field holding a type with links.
+	fieldData struct {
+		// FieldName is the name of the field.
+		FieldName string
+		// Many is true if the field holds an array.
+		Many bool
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "links",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the methods populating the links of the types returned by
+// the service methods and calls them in the service endpoints.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Links) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("links", f) {
+			continue
+		}
+		endpointLinks(f)
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("links", svc.Name) {
+					continue
+				}
+				if f := linksFile(svc); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// linksFile returns the file defining the methods populating the links of
+// the types used by the results of the given service, nil if there is none.
+func linksFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	var (
+		sd    = service.Services.Get(svc.Name)
+		types []*expr.LinksExpr
+		seen  = make(map[*expr.LinksExpr]bool)
+	)
+	for _, m := range svc.Methods {
+		if m.Result == nil {
+			continue
+		}
+		codegen.Walk(m.Result, func(att *goaexpr.AttributeExpr) error {
+			ut, ok := att.Type.(goaexpr.UserType)
+			if !ok {
+				return nil
+			}
+			if l := expr.Root.TypeLinks(ut); l != nil && !seen[l] {
+				seen[l] = true
+				types = append(types, l)
+			}
+			return nil
+		})
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	var (
+		sections []*codegen.SectionTemplate
+		imports  = make(map[string]bool)
+	)
+	for _, l := range types {
+		data := buildTypeData(sd, l)
+		for _, ld := range append(data.Links, data.OptionalLinks...) {
+			if strings.Contains(ld.Path, "url.") {
+				imports["net/url"] = true
+			}
+			if strings.Contains(ld.Path, "fmt.") {
+				imports["fmt"] = true
+			}
+		}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "links-populate",
+			Source: populateT,
+			Data:   data,
+		})
+	}
+	var specs []*codegen.ImportSpec
+	for _, p := range []string{"fmt", "net/url"} {
+		if imports[p] {
+			specs = append(specs, &codegen.ImportSpec{Path: p})
+		}
+	}
+	header := codegen.Header(svc.Name+" service links", sd.PkgName, specs)
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, codegen.SnakeCase(sd.VarName), "links.go"),
+		SectionTemplates: append([]*codegen.SectionTemplate{header}, sections...),
+	}
+}
+
+// buildTypeData returns the data necessary to render the method populating
+// the links of the given type.
+func buildTypeData(sd *service.Data, l *expr.LinksExpr) *typeData {
+	att := l.UserType.Attribute()
+	data := &typeData{
+		TypeName:      sd.Scope.GoTypeName(&goaexpr.AttributeExpr{Type: l.UserType}),
+		LinksTypeName: sd.Scope.GoTypeName(&goaexpr.AttributeExpr{Type: l.Type}),
+	}
+	for _, link := range l.Links {
+		ld := &linkData{FieldName: codegen.Goify(link.Rel, true)}
+		ld.Path, ld.Conditions = linkPath(att, link)
+		if len(ld.Conditions) > 0 {
+			data.OptionalLinks = append(data.OptionalLinks, ld)
+		} else {
+			data.Links = append(data.Links, ld)
+		}
+	}
+	for _, nat := range *goaexpr.AsObject(att.Type) {
+		if nat.Name == expr.AttributeName {
+			continue
+		}
+		t, many := nat.Attribute.Type, false
+		if arr := goaexpr.AsArray(t); arr != nil {
+			t, many = arr.ElemType.Type, true
+		}
+		if ut, ok := t.(goaexpr.UserType); ok && expr.Root.TypeLinks(ut) != nil {
+			data.Fields = append(data.Fields, &fieldData{FieldName: codegen.Goify(nat.Name, true), Many: many})
+		}
+	}
+	return data
+}
+
+// linkPath returns the Go expression computing the path of the given link
+// from the fields of res together with the fields that must be set for the
+// expression to be valid.
+func linkPath(att *goaexpr.AttributeExpr, link *expr.LinkExpr) (string, []string) {
+	var (
+		parts      []string
+		literal    string
+		conditions []string
+		pattern    = link.Pattern()
+	)
+	value := func(param string) string {
+		name := link.AttributeName(param)
+		field := "res." + codegen.Goify(name, true)
+		if att.IsPrimitivePointer(name, true) {
+			conditions = append(conditions, field)
+			field = "*" + field
+		}
+		if a := goaexpr.AsObject(att.Type).Attribute(name); a != nil && a.Type == goaexpr.String {
+			return field
+		}
+		return fmt.Sprintf("fmt.Sprint(%s)", field)
+	}
+	idx := 0
+	for _, m := range goaexpr.HTTPWildcardRegex.FindAllStringSubmatchIndex(pattern, -1) {
+		literal += pattern[idx:m[0]] + "/"
+		parts = append(parts, strconv.Quote(literal))
+		literal = ""
+		parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", value(pattern[m[2]:m[3]])))
+		idx = m[1]
+	}
+	literal += pattern[idx:]
+	if query := link.QueryParams(); len(query) > 0 {
+		literal += "?"
+		parts = append(parts, strconv.Quote(literal))
+		literal = ""
+		values := make([]string, len(query))
+		for i, q := range query {
+			values[i] = fmt.Sprintf("%q: {%s}", q, value(q))
+		}
+		parts = append(parts, fmt.Sprintf("url.Values{%s}.Encode()", strings.Join(values, ", ")))
+	}
+	if literal != "" {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	return strings.Join(parts, " + "), conditions
+}
+
+// endpointLinks makes the service endpoints of the methods returning types
+// with links populate the links of the results.
+func endpointLinks(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	for _, s := range f.Section("endpoint-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["populateLinks"] = populateLinks
+		s.Source = strings.Replace(s.Source,
+			"\t\tvres := {{ $.ViewedResult.Init.Name }}",
+			"{{ with populateLinks .ServiceName .Name }}\t\t{{ . }}\n{{ end }}\t\tvres := {{ $.ViewedResult.Init.Name }}", 1)
+		s.Source = strings.Replace(s.Source,
+			"{{- else if .ResultRef }}\n\t\treturn s.{{ .VarName }}(ctx{{ if .PayloadRef }}, {{ $payload }}{{ end }})\n",
+			"{{- else if .ResultRef }}"+populateEndpointT, 1)
+	}
+}
+
+// populateLinks returns the statement populating the links of the result of
+// the given method, the empty string if the result has no link.
+func populateLinks(svc, method string) string {
+	s := goaexpr.Root.Service(svc)
+	if s == nil {
+		return ""
+	}
+	m := s.Method(method)
+	if m == nil {
+		return ""
+	}
+	l, many := expr.Root.MethodLinks(m)
+	switch {
+	case l == nil:
+		return ""
+	case many:
+		return "for _, r := range res {\n\t\t\tr.PopulateLinks()\n\t\t}"
+	default:
+		return "res.PopulateLinks()"
+	}
+}
+
+// input: endpointMethodData
+const populateEndpointT = `
+	{{- with populateLinks .ServiceName .Name }}
+		res, err := s.{{ $.VarName }}(ctx{{ if $.PayloadRef }}, {{ $payload }}{{ end }})
+		if err != nil {
+			return nil, err
+		}
+		{{ . }}
+		return res, nil
+	{{- else }}
+		return s.{{ .VarName }}(ctx{{ if .PayloadRef }}, {{ $payload }}{{ end }})
+	{{- end }}
+`
+
+// input: typeData
+const populateT = `{{ printf "PopulateLinks sets the links of res to the paths of the related endpoints." | comment }}
+func (res *{{ .TypeName }}) PopulateLinks() {
+	if res == nil {
+		return
+	}
+	res.Links = &{{ .LinksTypeName }}{
+	{{- range .Links }}
+		{{ .FieldName }}: {{ .Path }},
+	{{- end }}
+	}
+{{- range .OptionalLinks }}
+	if {{ range $i, $c := .Conditions }}{{ if $i }} && {{ end }}{{ $c }} != nil{{ end }} {
+		link := {{ .Path }}
+		res.Links.{{ .FieldName }} = &link
+	}
+{{- end }}
+{{- range .Fields }}
+	{{- if .Many }}
+	for _, v := range res.{{ .FieldName }} {
+		v.PopulateLinks()
+	}
+	{{- else }}
+	res.{{ .FieldName }}.PopulateLinks()
+	{{- end }}
+{{- end }}
+}
+`
@@ -0,0 +1,70 @@
+package links_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/links"
+	"goa.design/plugins/v3/links/expr"
+	"goa.design/plugins/v3/links/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	expr.Root.Links = nil
+	codegen.RunDSLWithFunc(t, testdata.BlogDSL, func() {
+		eval.Register(expr.Root)
+	})
+	var fs []*codegen.File
+	for _, svc := range goaexpr.Root.Services {
+		fs = append(fs, service.EndpointFile("", svc))
+	}
+	fs, err := links.Generate("", []eval.Root{goaexpr.Root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 3 {
+		t.Fatalf("got %d files, expected 3", len(fs))
+	}
+	endpoints := map[string]string{
+		"NewShowEndpoint":   testdata.ShowEndpointCode,
+		"NewSearchEndpoint": testdata.SearchEndpointCode,
+		"NewLatestEndpoint": testdata.LatestEndpointCode,
+	}
+	for name, expected := range endpoints {
+		var code string
+		for _, s := range fs[0].Section("endpoint-method") {
+			if c := codegen.SectionCode(t, s); containsFunc(c, name) {
+				code = c
+			}
+		}
+		if code != expected {
+			t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, expected))
+		}
+	}
+	if fs[2].Path != "gen/blog/links.go" {
+		t.Fatalf("got links file %q, expected gen/blog/links.go", fs[2].Path)
+	}
+	sections := fs[2].Section("links-populate")
+	expected := []string{testdata.ArticlePopulateLinksCode, testdata.ArticlePagePopulateLinksCode, testdata.SummaryPopulateLinksCode}
+	if len(sections) != len(expected) {
+		t.Fatalf("got %d links sections, expected %d", len(sections), len(expected))
+	}
+	for i, s := range sections {
+		code := codegen.SectionCode(t, s)
+		if code != expected[i] {
+			t.Errorf("invalid links code %d, got:\n%s\ngot vs. expected:\n%s", i, code, codegen.Diff(t, code, expected[i]))
+		}
+	}
+}
+
+// containsFunc returns true if code declares the function with the given name.
+func containsFunc(code, name string) bool {
+	return strings.Contains(code, "func "+name+"(")
+}
@@ -0,0 +1,93 @@
+package testdata
+
+var ShowEndpointCode = `// NewShowEndpoint returns an endpoint function that calls the method "Show" of
+// service "Blog".
+func NewShowEndpoint(s Service) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		p := req.(*ShowPayload)
+		res, err := s.Show(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		res.PopulateLinks()
+		vres := NewViewedArticle(res, "default")
+		return vres, nil
+	}
+}
+`
+
+var SearchEndpointCode = `// NewSearchEndpoint returns an endpoint function that calls the method
+// "Search" of service "Blog".
+func NewSearchEndpoint(s Service) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		p := req.(*SearchPayload)
+		res, err := s.Search(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res {
+			r.PopulateLinks()
+		}
+		vres := NewViewedArticleCollection(res, "default")
+		return vres, nil
+	}
+}
+`
+
+var ArticlePopulateLinksCode = `// PopulateLinks sets the links of res to the paths of the related endpoints.
+func (res *Article) PopulateLinks() {
+	if res == nil {
+		return
+	}
+	res.Links = &ArticleLinks{
+		Self: "/articles/" + url.PathEscape(res.Slug),
+	}
+	if res.AuthorID != nil {
+		link := "/people/" + url.PathEscape(fmt.Sprint(*res.AuthorID))
+		res.Links.Author = &link
+	}
+}
+`
+
+var ArticlePagePopulateLinksCode = `// PopulateLinks sets the links of res to the paths of the related endpoints.
+func (res *ArticlePage) PopulateLinks() {
+	if res == nil {
+		return
+	}
+	res.Links = &ArticlePageLinks{
+		Self: "/articles",
+	}
+	if res.NextCursor != nil {
+		link := "/articles?" + url.Values{"cursor": {*res.NextCursor}}.Encode()
+		res.Links.Next = &link
+	}
+	for _, v := range res.Articles {
+		v.PopulateLinks()
+	}
+}
+`
+
+var LatestEndpointCode = `// NewLatestEndpoint returns an endpoint function that calls the method
+// "Latest" of service "Blog".
+func NewLatestEndpoint(s Service) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		res, err := s.Latest(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.PopulateLinks()
+		return res, nil
+	}
+}
+`
+
+var SummaryPopulateLinksCode = `// PopulateLinks sets the links of res to the paths of the related endpoints.
+func (res *Summary) PopulateLinks() {
+	if res == nil {
+		return
+	}
+	res.Links = &SummaryLinks{
+		Article: "/articles/" + url.PathEscape(res.Slug),
+	}
+}
+`
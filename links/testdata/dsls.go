@@ -0,0 +1,91 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	links "goa.design/plugins/v3/links/dsl"
+)
+
+var BlogDSL = func() {
+	var Article = ResultType("application/vnd.article", func() {
+		links.Links(func() {
+			links.Link("self", "Blog", "Show")
+			links.Link("author", "People", "Show", func() {
+				links.Param("id", "author_id")
+			})
+		})
+		Attributes(func() {
+			Attribute("slug", String)
+			Attribute("title", String)
+			Attribute("author_id", Int)
+			Required("slug", "title")
+		})
+	})
+	var ArticlePage = ResultType("application/vnd.article-page", func() {
+		links.Links(func() {
+			links.Link("self", "Blog", "List")
+			links.Link("next", "Blog", "List", func() {
+				links.Param("cursor", "next_cursor")
+			})
+		})
+		Attributes(func() {
+			Attribute("articles", CollectionOf(Article))
+			Attribute("next_cursor", String)
+		})
+	})
+	var Summary = Type("Summary", func() {
+		links.Links(func() {
+			links.Link("article", "Blog", "Show")
+		})
+		Attribute("slug", String)
+		Required("slug")
+	})
+	Service("Blog", func() {
+		Method("Show", func() {
+			Payload(func() {
+				Attribute("slug", String)
+			})
+			Result(Article)
+			HTTP(func() {
+				GET("/articles/{slug}")
+			})
+		})
+		Method("List", func() {
+			Payload(func() {
+				Attribute("cursor", String)
+			})
+			Result(ArticlePage)
+			HTTP(func() {
+				GET("/articles")
+				Param("cursor")
+			})
+		})
+		Method("Search", func() {
+			Payload(func() {
+				Attribute("q", String)
+			})
+			Result(CollectionOf(Article))
+			HTTP(func() {
+				GET("/search")
+				Param("q")
+			})
+		})
+		Method("Latest", func() {
+			Result(Summary)
+			HTTP(func() {
+				GET("/latest")
+				Response(StatusOK)
+			})
+		})
+	})
+	Service("People", func() {
+		Method("Show", func() {
+			Payload(func() {
+				Attribute("id", Int)
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/people/{id}")
+			})
+		})
+	})
+}
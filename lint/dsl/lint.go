@@ -0,0 +1,132 @@
+package dsl
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/lint/expr"
+
+	// Register code generators for the lint plugin
+	_ "goa.design/plugins/v3/lint"
+)
+
+// Names of the lint rules.
+const (
+	// KebabCasePaths requires the literal segments of the HTTP paths to be
+	// kebab-case, e.g. /order-items rather than /orderItems.
+	KebabCasePaths = expr.KebabCasePaths
+	// PluralResources requires the path segments followed by a parameter
+	// to be plural, e.g. /orders/{id} rather than /order/{id}.
+	PluralResources = expr.PluralResources
+	// NoVerbsInPaths forbids the path segments starting with a verb, e.g.
+	// /get-orders.
+	NoVerbsInPaths = expr.NoVerbsInPaths
+	// TypeDescriptions requires the types declared in the design package
+	// to have a description.
+	TypeDescriptions = expr.TypeDescriptions
+)
+
+// Lint enables the design linter. The linter applies the naming and style
+// rules to the design once it is finalized and makes the gen command fail
+// with the list of violations, each prefixed with the file and line of the
+// offending DSL in the design package. All the rules are enabled by default.
+//
+// Lint must appear in an API expression. The design files are looked up in
+// the directory of the file calling Lint.
+//
+// Lint accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import lint "goa.design/plugins/v3/lint/dsl"
+//
+//    var _ = API("calc", func() {
+//        lint.Lint(func() {
+//            lint.Disable(lint.PluralResources)
+//            lint.Warn(lint.TypeDescriptions)
+//            lint.Verbs("compute")
+//        })
+//    })
+//
+func Lint(fn ...func()) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	l := &expr.LintExpr{Verbs: append([]string{}, expr.DefaultVerbs...)}
+	if _, file, _, ok := runtime.Caller(1); ok {
+		l.Dir = filepath.Dir(file)
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], l) {
+			return
+		}
+	}
+	expr.Root.Lint = l
+}
+
+// Disable disables the given rules.
+//
+// Disable must appear in a Lint expression.
+//
+// Example:
+//
+//     Lint(func() {
+//         Disable(PluralResources, NoVerbsInPaths)
+//     })
+//
+func Disable(rules ...string) {
+	switch l := eval.Current().(type) {
+	case *expr.LintExpr:
+		l.Disabled = append(l.Disabled, rules...)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Warn reports the violations of the given rules as warnings: they are
+// printed but do not make the gen command fail.
+//
+// Warn must appear in a Lint expression.
+//
+// Example:
+//
+//     Lint(func() {
+//         Warn(TypeDescriptions)
+//     })
+//
+func Warn(rules ...string) {
+	switch l := eval.Current().(type) {
+	case *expr.LintExpr:
+		l.Warnings = append(l.Warnings, rules...)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Verbs adds verbs to the list of verbs forbidden at the beginning of the path
+// segments by the NoVerbsInPaths rule. The default list contains get, list,
+// create, add, update, set, delete, remove, fetch, find, make and do.
+//
+// Verbs must appear in a Lint expression.
+//
+// Example:
+//
+//     Lint(func() {
+//         Verbs("compute", "sync")
+//     })
+//
+func Verbs(verbs ...string) {
+	switch l := eval.Current().(type) {
+	case *expr.LintExpr:
+		l.Verbs = append(l.Verbs, verbs...)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,261 @@
+package expr
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+)
+
+const (
+	// KebabCasePaths is the rule requiring the literal segments of the
+	// HTTP paths to be kebab-case.
+	KebabCasePaths = "kebab-case-paths"
+	// PluralResources is the rule requiring the path segments followed by
+	// a parameter to be plural, e.g. /orders/{id}.
+	PluralResources = "plural-resources"
+	// NoVerbsInPaths is the rule forbidding the path segments starting
+	// with a verb, e.g. /get-orders.
+	NoVerbsInPaths = "no-verbs-in-paths"
+	// TypeDescriptions is the rule requiring the types declared in the
+	// design to have a description.
+	TypeDescriptions = "type-descriptions"
+)
+
+// Rules lists the names of the lint rules.
+var Rules = []string{KebabCasePaths, PluralResources, NoVerbsInPaths, TypeDescriptions}
+
+// DefaultVerbs lists the verbs forbidden in the path segments by default.
+var DefaultVerbs = []string{"get", "list", "create", "add", "update", "set", "delete", "remove", "fetch", "find", "make", "do"}
+
+// kebabCase matches the kebab-case path segments.
+var kebabCase = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type (
+	// LintExpr describes the lint rules applied to the design.
+	LintExpr struct {
+		// Disabled lists the disabled rules.
+		Disabled []string
+		// Warnings lists the rules whose violations are reported as
+		// warnings rather than errors.
+		Warnings []string
+		// Verbs lists the verbs forbidden in the path segments.
+		Verbs []string
+		// Dir is the directory of the design package used to locate
+		// the offending DSL.
+		Dir string
+		// Violations lists the violations found when the design was
+		// finalized.
+		Violations []*Violation
+	}
+
+	// Violation describes a lint rule violation.
+	Violation struct {
+		// Rule is the name of the violated rule.
+		Rule string
+		// Message describes the violation.
+		Message string
+		// Warning is true if the violation is reported as a warning.
+		Warning bool
+		// File is the design file containing the offending DSL, empty
+		// if it could not be located.
+		File string
+		// Line is the line of the offending DSL.
+		Line int
+	}
+
+	// Config is the configuration read from the "lint" section of the
+	// plugin configuration file. The rules and verbs it lists are added
+	// to the ones defined with the Lint DSL.
+	Config struct {
+		// Disabled lists the disabled rules.
+		Disabled []string `yaml:"disabled"`
+		// Warnings lists the rules whose violations are reported as
+		// warnings.
+		Warnings []string `yaml:"warnings"`
+		// Verbs lists additional verbs forbidden in the path segments.
+		Verbs []string `yaml:"verbs"`
+	}
+)
+
+// Register the lint plugin configuration.
+func init() {
+	config.Register("lint", func() interface{} { return &Config{} })
+}
+
+// Validate ensures the configuration only refers to existing rules.
+func (c *Config) Validate() error {
+	for _, r := range append(append([]string{}, c.Disabled...), c.Warnings...) {
+		if !isRule(r) {
+			return fmt.Errorf("unknown lint rule %q, must be one of %s", r, strings.Join(Rules, ", "))
+		}
+	}
+	return nil
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LintExpr) EvalName() string {
+	return "Lint"
+}
+
+// Validate ensures the lint configuration only refers to existing rules.
+func (l *LintExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	for _, r := range append(append([]string{}, l.Disabled...), l.Warnings...) {
+		if !isRule(r) {
+			verr.Add(l, "unknown lint rule %q, must be one of %s", r, strings.Join(Rules, ", "))
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Finalize applies the enabled rules to the finalized design and records the
+// violations.
+func (l *LintExpr) Finalize() {
+	// Errors are reported when the plugin configuration is validated.
+	if c, err := config.Get("lint"); err == nil {
+		cfg := c.(*Config)
+		l.Disabled = append(l.Disabled, cfg.Disabled...)
+		l.Warnings = append(l.Warnings, cfg.Warnings...)
+		l.Verbs = append(l.Verbs, cfg.Verbs...)
+	}
+	idx := indexSource(l.Dir)
+	for _, svc := range expr.Root.API.HTTP.Services {
+		svcPos := idx.locate("Service", svc.Name(), token.Position{})
+		for _, e := range svc.HTTPEndpoints {
+			pos := idx.locate("Method", e.MethodExpr.Name, svcPos)
+			for _, r := range e.Routes {
+				rpos := idx.locate(r.Method, r.Path, pos)
+				if !rpos.IsValid() {
+					rpos = pos
+				}
+				for _, p := range r.FullPaths() {
+					l.lintPath(p, rpos)
+				}
+			}
+		}
+	}
+	if l.enabled(TypeDescriptions) {
+		for _, t := range append(append([]expr.UserType{}, expr.Root.Types...), expr.Root.ResultTypes...) {
+			var pos token.Position
+			if rt, ok := t.(*expr.ResultTypeExpr); ok {
+				pos = idx.locate("ResultType", rt.Identifier, token.Position{})
+			} else {
+				pos = idx.locate("Type", t.Name(), token.Position{})
+			}
+			// Only the types declared in the design package are
+			// linted, this excludes the generated result types.
+			if !pos.IsValid() || t.Attribute().Description != "" {
+				continue
+			}
+			l.report(TypeDescriptions, pos, "type %q has no description", t.Name())
+		}
+	}
+	sort.SliceStable(l.Violations, func(i, j int) bool {
+		vi, vj := l.Violations[i], l.Violations[j]
+		if vi.File != vj.File {
+			return vi.File < vj.File
+		}
+		return vi.Line < vj.Line
+	})
+}
+
+// lintPath applies the path rules to the given HTTP path.
+func (l *LintExpr) lintPath(path string, pos token.Position) {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segs {
+		if s == "" || isParam(s) {
+			continue
+		}
+		if l.enabled(KebabCasePaths) && !kebabCase.MatchString(s) {
+			l.report(KebabCasePaths, pos, "segment %q of path %q is not kebab-case", s, path)
+		}
+		words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == '-' || r == '_' })
+		if len(words) == 0 {
+			continue
+		}
+		if l.enabled(NoVerbsInPaths) {
+			for _, v := range l.Verbs {
+				if words[0] == strings.ToLower(v) {
+					l.report(NoVerbsInPaths, pos, "segment %q of path %q starts with the verb %q", s, path, v)
+					break
+				}
+			}
+		}
+		if l.enabled(PluralResources) && i+1 < len(segs) && isParam(segs[i+1]) && !plural(words[len(words)-1]) {
+			l.report(PluralResources, pos, "segment %q of path %q is followed by a parameter and should be plural", s, path)
+		}
+	}
+}
+
+// report records a violation of the given rule unless an identical violation
+// was already recorded, e.g. for another path of the same route.
+func (l *LintExpr) report(rule string, pos token.Position, format string, args ...interface{}) {
+	v := &Violation{
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+		Warning: contains(l.Warnings, rule),
+		File:    pos.Filename,
+		Line:    pos.Line,
+	}
+	for _, other := range l.Violations {
+		if *other == *v {
+			return
+		}
+	}
+	l.Violations = append(l.Violations, v)
+}
+
+// enabled returns true if the given rule is enabled.
+func (l *LintExpr) enabled(rule string) bool {
+	return !contains(l.Disabled, rule)
+}
+
+// Error returns the violation formatted as file:line: message (rule).
+func (v *Violation) Error() string {
+	msg := fmt.Sprintf("%s (%s)", v.Message, v.Rule)
+	if v.Warning {
+		msg = "warning: " + msg
+	}
+	if v.File == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s:%d: %s", v.File, v.Line, msg)
+}
+
+// plural returns true if the given lower case word looks plural.
+func plural(w string) bool {
+	switch w {
+	case "data", "people", "children", "media", "metadata", "info":
+		return true
+	}
+	return strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss")
+}
+
+// isParam returns true if the given path segment is a parameter or wildcard.
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{") || strings.HasPrefix(seg, "*")
+}
+
+// isRule returns true if name is the name of a lint rule.
+func isRule(name string) bool {
+	return contains(Rules, name)
+}
+
+// contains returns true if vals contains v.
+func contains(vals []string, v string) bool {
+	for _, val := range vals {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the design lint configuration.
+	RootExpr struct {
+		// Lint is the lint configuration, nil if the design does not
+		// use the Lint DSL.
+		Lint *LintExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "lint plugin"
+}
+
+// WalkSets iterates over the lint configuration.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	if r.Lint == nil {
+		return
+	}
+	walk(eval.ExpressionSet{r.Lint})
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/lint/dsl"}
+}
@@ -0,0 +1,96 @@
+package expr
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// sourceIndex records the positions of the DSL function calls whose
+	// first argument is a string literal, e.g. Service("orders").
+	sourceIndex map[string][]token.Position
+)
+
+// indexSource parses the Go files of the given directory and indexes the DSL
+// function calls. It returns an empty index if the directory cannot be read.
+func indexSource(dir string) sourceIndex {
+	idx := make(sourceIndex)
+	if dir == "" {
+		return idx
+	}
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return idx
+	}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+				var name string
+				switch fun := call.Fun.(type) {
+				case *ast.Ident:
+					name = fun.Name
+				case *ast.SelectorExpr:
+					name = fun.Sel.Name
+				default:
+					return true
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				arg, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return true
+				}
+				key := name + "\x00" + arg
+				idx[key] = append(idx[key], fset.Position(call.Pos()))
+				return true
+			})
+		}
+	}
+	for _, positions := range idx {
+		sort.Slice(positions, func(i, j int) bool {
+			if positions[i].Filename != positions[j].Filename {
+				return positions[i].Filename < positions[j].Filename
+			}
+			return positions[i].Offset < positions[j].Offset
+		})
+	}
+	return idx
+}
+
+// locate returns the position of the first call to the DSL function fn with
+// the given first argument which appears after the given position in the same
+// file, or of the first call if there is none. It returns an invalid position
+// if there is no such call.
+func (idx sourceIndex) locate(fn, arg string, after token.Position) token.Position {
+	positions := idx[fn+"\x00"+arg]
+	if len(positions) == 0 {
+		return token.Position{}
+	}
+	if after.IsValid() {
+		var best token.Position
+		for _, pos := range positions {
+			if pos.Filename == after.Filename && pos.Offset > after.Offset &&
+				(!best.IsValid() || pos.Offset < best.Offset) {
+				best = pos
+			}
+		}
+		if best.IsValid() {
+			return best
+		}
+	}
+	return positions[0]
+}
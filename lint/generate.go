@@ -0,0 +1,51 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/lint/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+// Warnings is the writer the lint warnings are printed to.
+var Warnings io.Writer = os.Stderr
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "lint",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate reports the lint violations found when the design was finalized.
+// It prints the warnings and returns an error listing the other violations if
+// any. It does not generate any file.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("lint", "") {
+		return files, nil
+	}
+	l := expr.Root.Lint
+	if l == nil {
+		return files, nil
+	}
+	var errs []string
+	for _, v := range l.Violations {
+		if v.Warning {
+			fmt.Fprintln(Warnings, v.Error())
+			continue
+		}
+		errs = append(errs, v.Error())
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("design lint failed with %d violation(s):\n%s", len(errs), strings.Join(errs, "\n"))
+	}
+	return files, nil
+}
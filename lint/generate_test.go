@@ -0,0 +1,118 @@
+package lint_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/lint"
+	"goa.design/plugins/v3/lint/expr"
+	"goa.design/plugins/v3/lint/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name     string
+		DSL      func()
+		Errors   []string
+		Warnings []string
+	}{
+		{"violations", testdata.ViolationsDSL, []string{
+			`dsls.go:32: segment "order" of path "/order/{id}" is followed by a parameter and should be plural (plural-resources)`,
+			`dsls.go:38: segment "allOrders" of path "/allOrders" is not kebab-case (kebab-case-paths)`,
+			`dsls.go:43: segment "compute-totals" of path "/compute-totals" starts with the verb "Compute" (no-verbs-in-paths)`,
+			`dsls.go:54: segment "item" of path "/item/{id}" is followed by a parameter and should be plural (plural-resources)`,
+		}, []string{
+			`dsls.go:15: warning: type "Item" has no description (type-descriptions)`,
+		}},
+		{"disabled", testdata.DisabledDSL, []string{
+			`dsls.go:81: segment "list-items" of path "/list-items" starts with the verb "list" (no-verbs-in-paths)`,
+		}, nil},
+		{"clean", testdata.CleanDSL, nil, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			var warnings bytes.Buffer
+			lint.Warnings = &warnings
+			_, err := lint.Generate("", []eval.Root{goaexpr.Root}, nil)
+			var errs []string
+			if err != nil {
+				errs = strings.Split(err.Error(), "\n")[1:]
+			}
+			if got := relative(errs); strings.Join(got, "\n") != strings.Join(c.Errors, "\n") {
+				t.Errorf("got errors:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(c.Errors, "\n"))
+			}
+			ws := strings.Split(strings.TrimSpace(warnings.String()), "\n")
+			if warnings.Len() == 0 {
+				ws = nil
+			}
+			if got := relative(ws); strings.Join(got, "\n") != strings.Join(c.Warnings, "\n") {
+				t.Errorf("got warnings:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(c.Warnings, "\n"))
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	config.Root = &config.RootExpr{File: "testdata/goa-plugins.yaml"}
+	defer func() { config.Root = &config.RootExpr{} }()
+	runDSL(t, testdata.DisabledDSL)
+	l := expr.Root.Lint
+	if len(l.Violations) != 0 {
+		t.Errorf("got %d violation(s), expected none", len(l.Violations))
+	}
+	if !contains(l.Disabled, expr.NoVerbsInPaths) || !contains(l.Warnings, expr.KebabCasePaths) {
+		t.Errorf("got disabled %v and warnings %v, expected configured rules", l.Disabled, l.Warnings)
+	}
+}
+
+func TestInvalidRule(t *testing.T) {
+	expr.Root.Lint = nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.API = goaexpr.NewAPIExpr("test api", func() {})
+	eval.Register(goaexpr.Root)
+	eval.Register(expr.Root)
+	err := eval.Context.Errors
+	if eval.Execute(testdata.InvalidRuleDSL, nil) {
+		err = eval.RunDSL()
+	}
+	if err == nil || !strings.Contains(err.Error(), `unknown lint rule "camel-case"`) {
+		t.Errorf("got error %v, expected unknown lint rule error", err)
+	}
+}
+
+// runDSL runs the given DSL with the lint plugin root registered so that the
+// lint rules are applied when the design is finalized.
+func runDSL(t *testing.T, dsl func()) {
+	expr.Root.Lint = nil
+	plugintest.RunDSL(t, dsl, expr.Root)
+}
+
+// relative strips the directory of the design files from the given messages.
+func relative(msgs []string) []string {
+	res := make([]string, len(msgs))
+	for i, m := range msgs {
+		if idx := strings.Index(m, ":"); idx > 0 {
+			m = filepath.Base(m[:idx]) + m[idx:]
+		}
+		res[i] = m
+	}
+	return res
+}
+
+// contains returns true if vals contains v.
+func contains(vals []string, v string) bool {
+	for _, val := range vals {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	lint "goa.design/plugins/v3/lint/dsl"
+)
+
+var ViolationsDSL = func() {
+	API("shop", func() {
+		lint.Lint(func() {
+			lint.Warn(lint.TypeDescriptions)
+			lint.Verbs("Compute")
+		})
+	})
+	var Item = Type("Item", func() {
+		Attribute("id", String)
+	})
+	var Order = ResultType("application/vnd.shop.order", func() {
+		Description("Order is a customer order.")
+		Attributes(func() {
+			Attribute("id", String)
+			Attribute("items", ArrayOf(Item))
+		})
+	})
+	Service("orders", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(Order)
+			HTTP(func() {
+				GET("/order/{id}")
+			})
+		})
+		Method("list", func() {
+			Result(CollectionOf(Order))
+			HTTP(func() {
+				GET("/allOrders")
+			})
+		})
+		Method("compute", func() {
+			HTTP(func() {
+				POST("/compute-totals")
+			})
+		})
+	})
+	Service("items", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(Item)
+			HTTP(func() {
+				GET("/item/{id}")
+			})
+		})
+	})
+}
+
+var DisabledDSL = func() {
+	API("shop", func() {
+		lint.Lint(func() {
+			lint.Disable(lint.PluralResources, lint.TypeDescriptions)
+		})
+	})
+	var Item = Type("Item", func() {
+		Attribute("id", String)
+	})
+	Service("items", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(Item)
+			HTTP(func() {
+				GET("/item/{id}")
+			})
+		})
+		Method("list", func() {
+			HTTP(func() {
+				GET("/list-items")
+			})
+		})
+	})
+}
+
+var CleanDSL = func() {
+	API("shop", func() {
+		lint.Lint()
+		HTTP(func() {
+			Path("/api/v1")
+		})
+	})
+	var Item = Type("Item", func() {
+		Description("Item is a catalog item.")
+		Attribute("id", String)
+	})
+	Service("items", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			Result(Item)
+			HTTP(func() {
+				GET("/items/{id}")
+			})
+		})
+		Method("search", func() {
+			Result(ArrayOf(Item))
+			HTTP(func() {
+				GET("/item-search")
+			})
+		})
+	})
+}
+
+var InvalidRuleDSL = func() {
+	API("shop", func() {
+		lint.Lint(func() {
+			lint.Disable("camel-case")
+		})
+	})
+}
@@ -0,0 +1,410 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// scenarioData contains the data necessary to render the load test
+	// scenarios of an API.
+	scenarioData struct {
+		// API is the name of the API.
+		API string
+		// BaseURL is the default URL of the server receiving the
+		// requests.
+		BaseURL string
+		// Endpoints lists the endpoints exercised by the scenarios.
+		Endpoints []*endpointData
+	}
+
+	// endpointData describes the request sent to an endpoint.
+	endpointData struct {
+		// Service is the name of the service.
+		Service string
+		// Method is the name of the method.
+		Method string
+		// Scenario is the name of the k6 scenario.
+		Scenario string
+		// FuncName is the name of the k6 function sending the request.
+		FuncName string
+		// Verb is the HTTP method of the request.
+		Verb string
+		// Path is the request path built from the path parameter examples.
+		Path string
+		// Query is the query string built from the query parameter
+		// examples.
+		Query string
+		// Headers lists the request headers built from the header
+		// examples.
+		Headers []*headerData
+		// Body is the JSON request body built from the body example,
+		// empty if the request has no body.
+		Body string
+		// Auth lists the credentials required by the endpoint.
+		Auth []*authData
+		// Status is the HTTP status code of the success response.
+		Status int
+	}
+
+	// headerData describes a request header.
+	headerData struct {
+		// Name is the header name.
+		Name string
+		// Value is the header value.
+		Value string
+	}
+
+	// authData describes the placeholder of a credential.
+	authData struct {
+		// In is the location of the credential, one of "header" or
+		// "query".
+		In string
+		// Name is the name of the header or query parameter.
+		Name string
+		// Prefix is the prefix of the header value, e.g. "Bearer ".
+		Prefix string
+		// Env is the name of the environment variable holding the
+		// credential.
+		Env string
+	}
+
+	// targetData is a vegeta target in the JSON format.
+	targetData struct {
+		// Method is the HTTP method of the request.
+		Method string `json:"method"`
+		// URL is the request URL.
+		URL string `json:"url"`
+		// Body is the request body, encoded using base64 in JSON.
+		Body []byte `json:"body,omitempty"`
+		// Header is the request header.
+		Header map[string][]string `json:"header,omitempty"`
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "loadtest",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces a k6 script and a vegeta targets file which send requests
+// built from the design examples to each HTTP endpoint.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			if r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+				continue
+			}
+			data := buildScenarios(r)
+			if len(data.Endpoints) == 0 {
+				continue
+			}
+			files = append(files, k6File(data), vegetaFile(data))
+		}
+	}
+	return files, nil
+}
+
+// k6File returns the k6 script defining one scenario per endpoint.
+func k6File(data *scenarioData) *codegen.File {
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "loadtest", "k6.js"),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:    "loadtest-k6",
+			Source:  k6T,
+			Data:    data,
+			FuncMap: template.FuncMap{"js": jsString},
+		}},
+	}
+}
+
+// vegetaFile returns the vegeta targets file listing one target per endpoint
+// in the JSON format.
+func vegetaFile(data *scenarioData) *codegen.File {
+	var lines []string
+	for _, e := range data.Endpoints {
+		u := data.BaseURL + e.Path
+		t := &targetData{Method: e.Verb, Header: make(map[string][]string)}
+		if e.Body != "" {
+			t.Body = []byte(e.Body)
+			t.Header["Content-Type"] = []string{"application/json"}
+		}
+		for _, h := range e.Headers {
+			t.Header[h.Name] = append(t.Header[h.Name], h.Value)
+		}
+		query := e.Query
+		for _, a := range e.Auth {
+			v := a.Prefix + "${" + a.Env + "}"
+			if a.In == "query" {
+				if query != "" {
+					query += "&"
+				}
+				query += url.QueryEscape(a.Name) + "=" + v
+				continue
+			}
+			t.Header[a.Name] = append(t.Header[a.Name], v)
+		}
+		if query != "" {
+			u += "?" + query
+		}
+		t.URL = u
+		if len(t.Header) == 0 {
+			t.Header = nil
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.Encode(t)
+		lines = append(lines, strings.TrimSuffix(buf.String(), "\n"))
+	}
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "loadtest", "targets.json"),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:   "loadtest-vegeta",
+			Source: "{{ range . }}{{ . }}\n{{ end }}",
+			Data:   lines,
+		}},
+	}
+}
+
+// buildScenarios computes the load test scenarios from the design. Streaming
+// and multipart endpoints are skipped.
+func buildScenarios(r *expr.RootExpr) *scenarioData {
+	data := &scenarioData{API: r.API.Name, BaseURL: baseURL(r)}
+	for _, svc := range r.API.HTTP.Services {
+		if !config.Enabled("loadtest", svc.Name()) {
+			continue
+		}
+		for _, e := range svc.HTTPEndpoints {
+			if e.MethodExpr.IsStreaming() || e.MultipartRequest || len(e.Routes) == 0 {
+				continue
+			}
+			data.Endpoints = append(data.Endpoints, buildEndpoint(r, e))
+		}
+	}
+	return data
+}
+
+// buildEndpoint returns the description of the request sent to the given
+// endpoint.
+func buildEndpoint(r *expr.RootExpr, e *expr.HTTPEndpointExpr) *endpointData {
+	var (
+		svc    = e.Service.Name()
+		method = e.MethodExpr.Name
+		route  = e.Routes[0]
+	)
+	ed := &endpointData{
+		Service:  svc,
+		Method:   method,
+		Scenario: codegen.SnakeCase(svc) + "_" + codegen.SnakeCase(method),
+		FuncName: codegen.Goify(svc, false) + codegen.Goify(method, true),
+		Verb:     route.Method,
+		Path:     genutil.ExamplePath(r, e, route.FullPaths()[0]),
+		Body:     exampleBody(r, e),
+		Auth:     auth(e),
+		Status:   200,
+	}
+	skip := map[string]map[string]struct{}{"header": {}, "query": {}}
+	for _, a := range ed.Auth {
+		skip[a.In][a.Name] = struct{}{}
+	}
+	ed.Query = exampleQuery(r, e, skip["query"])
+	ed.Headers = exampleHeaders(r, e, skip["header"])
+	if len(e.Responses) > 0 {
+		ed.Status = e.Responses[0].StatusCode
+	}
+	return ed
+}
+
+// HasHeaders returns true if the request sent to the endpoint sets headers.
+func (e *endpointData) HasHeaders() bool {
+	if e.Body != "" || len(e.Headers) > 0 {
+		return true
+	}
+	for _, a := range e.Auth {
+		if a.In == "header" {
+			return true
+		}
+	}
+	return false
+}
+
+// auth returns the placeholders of the credentials required by the first
+// security requirement of the given endpoint.
+func auth(e *expr.HTTPEndpointExpr) []*authData {
+	if len(e.Requirements) == 0 {
+		return nil
+	}
+	var as []*authData
+	for _, s := range e.Requirements[0].Schemes {
+		a := &authData{In: s.In, Name: s.Name, Env: strings.ToUpper(codegen.SnakeCase(s.SchemeName))}
+		switch s.Kind {
+		case expr.NoKind:
+			continue
+		case expr.BasicAuthKind:
+			a.Prefix = "Basic "
+		case expr.JWTKind, expr.OAuth2Kind:
+			if a.Name == "Authorization" {
+				a.Prefix = "Bearer "
+			}
+		}
+		if a.In == "" {
+			a.In = "header"
+		}
+		as = append(as, a)
+	}
+	return as
+}
+
+// baseURL returns the first HTTP URI of the API servers with the host
+// variables replaced with their default values.
+func baseURL(r *expr.RootExpr) string {
+	for _, s := range r.API.Servers {
+		for _, h := range s.Hosts {
+			for _, uri := range h.URIs {
+				u := string(uri)
+				if !strings.HasPrefix(u, "http") {
+					continue
+				}
+				for _, v := range *expr.AsObject(h.Attribute().Type) {
+					if v.Attribute.DefaultValue != nil {
+						u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+					}
+				}
+				return strings.TrimSuffix(u, "/")
+			}
+		}
+	}
+	return "http://localhost:80"
+}
+
+// exampleQuery returns the query string built from the query parameter
+// examples. The parameters carrying credentials are skipped.
+func exampleQuery(r *expr.RootExpr, e *expr.HTTPEndpointExpr, skip map[string]struct{}) string {
+	params := e.QueryParams()
+	if expr.AsObject(params.Type) == nil {
+		return ""
+	}
+	values := url.Values{}
+	expr.WalkMappedAttr(params, func(name, elem string, att *expr.AttributeExpr) error {
+		if _, ok := skip[elem]; ok {
+			return nil
+		}
+		ex := reflect.ValueOf(att.Example(r.API.Random()))
+		if ex.Kind() == reflect.Slice {
+			for i := 0; i < ex.Len(); i++ {
+				values.Add(elem, fmt.Sprint(ex.Index(i).Interface()))
+			}
+			return nil
+		}
+		values.Add(elem, fmt.Sprint(ex.Interface()))
+		return nil
+	})
+	return values.Encode()
+}
+
+// exampleHeaders returns the request headers built from the header examples
+// sorted by name. The headers carrying credentials are skipped.
+func exampleHeaders(r *expr.RootExpr, e *expr.HTTPEndpointExpr, skip map[string]struct{}) []*headerData {
+	if e.Headers == nil || expr.AsObject(e.Headers.Type) == nil {
+		return nil
+	}
+	var hs []*headerData
+	expr.WalkMappedAttr(e.Headers, func(name, elem string, att *expr.AttributeExpr) error {
+		if _, ok := skip[elem]; ok {
+			return nil
+		}
+		hs = append(hs, &headerData{Name: elem, Value: fmt.Sprint(att.Example(r.API.Random()))})
+		return nil
+	})
+	sort.Slice(hs, func(i, j int) bool { return hs[i].Name < hs[j].Name })
+	return hs
+}
+
+// exampleBody returns the JSON representation of the request body example,
+// the empty string if the request has no body.
+func exampleBody(r *expr.RootExpr, e *expr.HTTPEndpointExpr) string {
+	if e.Body == nil || e.Body.Type == expr.Empty {
+		return ""
+	}
+	b, err := json.Marshal(e.Body.Example(r.API.Random()))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// jsString returns the JavaScript string literal of s.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// input: scenarioData
+const k6T = `// {{ .API }} API load test scenarios generated from the design.
+//
+// Run with:
+//
+//     k6 run -e BASE_URL={{ .BaseURL }} k6.js
+//
+// The credentials are read from the environment variables named after the
+// security schemes.
+import http from "k6/http";
+import { check } from "k6";
+
+const BASE_URL = __ENV.BASE_URL || {{ js .BaseURL }};
+
+export const options = {
+  scenarios: {
+{{- range .Endpoints }}
+    {{ .Scenario }}: {
+      executor: "constant-vus",
+      exec: {{ js .FuncName }},
+      vus: 1,
+      duration: "30s",
+    },
+{{- end }}
+  },
+};
+{{ range .Endpoints }}
+// {{ .FuncName }} sends a request to the {{ printf "%q" .Method }} endpoint of the {{ printf "%q" .Service }} service.
+export function {{ .FuncName }}() {
+  const res = http.request({{ js .Verb }}, ` + "`" + `${BASE_URL}{{ .Path }}
+	{{- $sep := "?" }}
+	{{- if .Query }}?{{ .Query }}{{ $sep = "&" }}{{ end }}
+	{{- range .Auth }}{{ if eq .In "query" }}{{ $sep }}{{ .Name }}=${__ENV.{{ .Env }}}{{ $sep = "&" }}{{ end }}{{ end }}` + "`" + `, {{ if .Body }}{{ js .Body }}{{ else }}null{{ end }}
+	{{- if .HasHeaders }}, {
+    headers: {
+{{- if .Body }}
+      "Content-Type": "application/json",
+{{- end }}
+{{- range .Headers }}
+      {{ js .Name }}: {{ js .Value }},
+{{- end }}
+{{- range .Auth }}{{ if eq .In "header" }}
+      {{ js .Name }}: ` + "`" + `{{ .Prefix }}${__ENV.{{ .Env }}}` + "`" + `,
+{{- end }}{{ end }}
+    },
+  }
+	{{- end }});
+  check(res, { {{ js (printf "%s status is %d" .Method .Status) }}: (r) => r.status === {{ .Status }} });
+}
+{{ end }}`
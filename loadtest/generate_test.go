@@ -0,0 +1,31 @@
+package loadtest_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/loadtest"
+	"goa.design/plugins/v3/loadtest/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	root := httpcodegen.RunHTTPDSL(t, testdata.ScenarioDSL)
+	fs, err := loadtest.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	for _, f := range fs {
+		var buf bytes.Buffer
+		if err := f.SectionTemplates[0].Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		plugintest.Golden(t, filepath.Base(f.Path), buf.String())
+	}
+}
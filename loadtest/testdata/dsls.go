@@ -0,0 +1,68 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var JWTAuth = JWTSecurity("jwt")
+
+var APIKeyAuth = APIKeySecurity("api_key")
+
+var ScenarioDSL = func() {
+	API("bookstore", func() {
+		Server("bookstore", func() {
+			Host("development", func() {
+				URI("http://localhost:8000")
+			})
+		})
+	})
+	Service("books", func() {
+		HTTP(func() {
+			Path("/books")
+		})
+		Method("show", func() {
+			Security(APIKeyAuth)
+			Payload(func() {
+				APIKey("api_key", "key", String)
+				Attribute("id", Int, func() {
+					Example(42)
+				})
+				Attribute("view", String, func() {
+					Example("full")
+				})
+				Required("key", "id")
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/{id}")
+				Param("view")
+				Param("key:api_key")
+			})
+		})
+		Method("create", func() {
+			Security(JWTAuth)
+			Payload(func() {
+				Token("token", String)
+				Attribute("title", String, func() {
+					Example("Dune")
+				})
+				Attribute("tenant", String, func() {
+					Example("acme")
+				})
+				Required("token", "title")
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/")
+				Header("tenant:X-Tenant")
+				Response(StatusCreated)
+			})
+		})
+		Method("stream", func() {
+			StreamingResult(String)
+			HTTP(func() {
+				GET("/stream")
+			})
+		})
+	})
+}
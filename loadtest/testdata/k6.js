@@ -0,0 +1,47 @@
+// bookstore API load test scenarios generated from the design.
+//
+// Run with:
+//
+//     k6 run -e BASE_URL=http://localhost:8000 k6.js
+//
+// The credentials are read from the environment variables named after the
+// security schemes.
+import http from "k6/http";
+import { check } from "k6";
+
+const BASE_URL = __ENV.BASE_URL || "http://localhost:8000";
+
+export const options = {
+  scenarios: {
+    books_show: {
+      executor: "constant-vus",
+      exec: "booksShow",
+      vus: 1,
+      duration: "30s",
+    },
+    books_create: {
+      executor: "constant-vus",
+      exec: "booksCreate",
+      vus: 1,
+      duration: "30s",
+    },
+  },
+};
+
+// booksShow sends a request to the "show" endpoint of the "books" service.
+export function booksShow() {
+  const res = http.request("GET", `${BASE_URL}/books/42?view=full&api_key=${__ENV.API_KEY}`, null);
+  check(res, { "show status is 200": (r) => r.status === 200 });
+}
+
+// booksCreate sends a request to the "create" endpoint of the "books" service.
+export function booksCreate() {
+  const res = http.request("POST", `${BASE_URL}/books`, "{\"title\":\"Dune\"}", {
+    headers: {
+      "Content-Type": "application/json",
+      "X-Tenant": "acme",
+      "Authorization": `Bearer ${__ENV.JWT}`,
+    },
+  });
+  check(res, { "create status is 201": (r) => r.status === 201 });
+}
@@ -0,0 +1,89 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/meshroute/expr"
+
+	// Register code generators for the mesh route plugin
+	_ "goa.design/plugins/v3/meshroute"
+)
+
+// Gateway attaches the generated routes to the gateway with the given name
+// (the parent Gateway of the HTTPRoute resources and the gateway of the
+// VirtualService resources).
+//
+// Gateway must appear in an API expression.
+//
+// Example:
+//
+//    import meshroute "goa.design/plugins/v3/meshroute/dsl"
+//
+//    var _ = API("calc", func() {
+//        meshroute.Gateway("public")
+//    })
+//
+func Gateway(name string) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	routing(eval.Current()).Gateway = name
+}
+
+// Backend sets the name and port of the Kubernetes service which receives the
+// requests made to the service endpoints. The default backend is named after
+// the service and uses the port of the service server URI.
+//
+// Backend must appear in a Service expression.
+//
+// Example:
+//
+//    var _ = Service("calc", func() {
+//        meshroute.Backend("calc-svc", 8080)
+//    })
+//
+func Backend(host string, port int) {
+	if _, ok := eval.Current().(*goaexpr.ServiceExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	rt := routing(eval.Current())
+	rt.BackendHost = host
+	rt.BackendPort = port
+}
+
+// Timeout sets the request timeout of the service or method endpoints using
+// the Go duration syntax. Method level timeouts override service level
+// timeouts.
+//
+// Timeout must appear in a Service or Method expression.
+//
+// Example:
+//
+//    var _ = Service("calc", func() {
+//        meshroute.Timeout("10s")
+//        Method("add", func() {
+//            meshroute.Timeout("500ms")
+//        })
+//    })
+//
+func Timeout(d string) {
+	switch eval.Current().(type) {
+	case *goaexpr.ServiceExpr, *goaexpr.MethodExpr:
+		routing(eval.Current()).Timeout = d
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// routing returns the routing properties of the given expression, creating
+// them if needed.
+func routing(parent eval.Expression) *expr.RoutingExpr {
+	if rt := expr.Root.Routing(parent); rt != nil {
+		return rt
+	}
+	rt := &expr.RoutingExpr{Parent: parent}
+	expr.Root.Routings = append(expr.Root.Routings, rt)
+	return rt
+}
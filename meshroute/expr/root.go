@@ -0,0 +1,94 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the routing properties defined in the design.
+	RootExpr struct {
+		// Routings lists the routing properties of the API, services and
+		// methods in the order they appear in the design.
+		Routings []*RoutingExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "mesh route plugin"
+}
+
+// WalkSets iterates over the routing properties.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	rexps := make(eval.ExpressionSet, len(r.Routings))
+	for i, rt := range r.Routings {
+		rexps[i] = rt
+	}
+	walk(rexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/meshroute/dsl"}
+}
+
+// Routing returns the routing properties of the given API, service or method
+// expression, nil if there are none.
+func (r *RootExpr) Routing(parent eval.Expression) *RoutingExpr {
+	for _, rt := range r.Routings {
+		if rt.Parent == parent {
+			return rt
+		}
+	}
+	return nil
+}
+
+// Gateway returns the name of the gateway the routes are attached to, empty if
+// there is none.
+func (r *RootExpr) Gateway() string {
+	for _, rt := range r.Routings {
+		if _, ok := rt.Parent.(*expr.APIExpr); ok {
+			return rt.Gateway
+		}
+	}
+	return ""
+}
+
+// Backend returns the name and port of the Kubernetes service which receives
+// the requests made to the given service. The port is 0 if the backend was
+// not defined in the design.
+func (r *RootExpr) Backend(svc *expr.ServiceExpr) (string, int) {
+	if rt := r.Routing(svc); rt != nil && rt.BackendHost != "" {
+		return rt.BackendHost, rt.BackendPort
+	}
+	return "", 0
+}
+
+// Timeout returns the request timeout of the given method. Method level
+// timeouts override service level timeouts. Timeout returns an empty string
+// if the method has no timeout.
+func (r *RootExpr) Timeout(m *expr.MethodExpr) string {
+	if rt := r.Routing(m); rt != nil && rt.Timeout != "" {
+		return rt.Timeout
+	}
+	if rt := r.Routing(m.Service); rt != nil {
+		return rt.Timeout
+	}
+	return ""
+}
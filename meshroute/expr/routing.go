@@ -0,0 +1,53 @@
+package expr
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// RoutingExpr describes the routing properties of the API, a service
+	// or a method.
+	RoutingExpr struct {
+		// Gateway is the name of the gateway the routes are attached to.
+		Gateway string
+		// BackendHost is the name of the Kubernetes service which
+		// receives the requests.
+		BackendHost string
+		// BackendPort is the port of the Kubernetes service which
+		// receives the requests.
+		BackendPort int
+		// Timeout is the request timeout using the Go duration syntax
+		// (e.g. "5s").
+		Timeout string
+		// Parent is the API, service or method expression.
+		Parent eval.Expression
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *RoutingExpr) EvalName() string {
+	var suffix string
+	if r.Parent != nil {
+		suffix = " of " + r.Parent.EvalName()
+	}
+	return "mesh routing" + suffix
+}
+
+// Validate ensures the routing expression is valid.
+func (r *RoutingExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if r.Timeout != "" {
+		if d, err := time.ParseDuration(r.Timeout); err != nil || d <= 0 {
+			verr.Add(r, "invalid timeout %q, must be a positive duration such as \"5s\"", r.Timeout)
+		}
+	}
+	if r.BackendHost != "" && (r.BackendPort <= 0 || r.BackendPort > 65535) {
+		verr.Add(r, "invalid backend port %d", r.BackendPort)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
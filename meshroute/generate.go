@@ -0,0 +1,334 @@
+package meshroute
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/meshroute/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+type (
+	// serviceRoutes describes the routes of a service.
+	serviceRoutes struct {
+		// Name is the name of the Kubernetes resources.
+		Name string
+		// Hosts lists the host names of the service.
+		Hosts []string
+		// Gateway is the gateway the routes are attached to, empty if
+		// there is none.
+		Gateway string
+		// Backend is the name of the Kubernetes service which receives
+		// the requests.
+		Backend string
+		// Port is the port of the backend.
+		Port int
+		// Routes lists the service routes.
+		Routes []*routeData
+	}
+
+	// routeData describes a HTTP route.
+	routeData struct {
+		// Name is the route name.
+		Name string
+		// Method is the HTTP method.
+		Method string
+		// Paths lists the route paths.
+		Paths []string
+		// Timeout is the request timeout, empty if there is none.
+		Timeout string
+	}
+
+	// resource is a Kubernetes resource.
+	resource struct {
+		APIVersion string      `yaml:"apiVersion"`
+		Kind       string      `yaml:"kind"`
+		Metadata   metadata    `yaml:"metadata"`
+		Spec       interface{} `yaml:"spec"`
+	}
+
+	// metadata is the metadata of a Kubernetes resource.
+	metadata struct {
+		Name string `yaml:"name"`
+	}
+
+	// httpRouteSpec is the specification of a Gateway API HTTPRoute.
+	httpRouteSpec struct {
+		ParentRefs []*ref           `yaml:"parentRefs,omitempty"`
+		Hostnames  []string         `yaml:"hostnames,omitempty"`
+		Rules      []*httpRouteRule `yaml:"rules"`
+	}
+
+	// httpRouteRule is a rule of a Gateway API HTTPRoute.
+	httpRouteRule struct {
+		Matches     []*httpRouteMatch `yaml:"matches"`
+		BackendRefs []*ref            `yaml:"backendRefs"`
+		Timeouts    map[string]string `yaml:"timeouts,omitempty"`
+	}
+
+	// httpRouteMatch is a match of a Gateway API HTTPRoute rule.
+	httpRouteMatch struct {
+		Path   map[string]string `yaml:"path"`
+		Method string            `yaml:"method"`
+	}
+
+	// ref is a reference to a Gateway API resource.
+	ref struct {
+		Name string `yaml:"name"`
+		Port int    `yaml:"port,omitempty"`
+	}
+
+	// virtualServiceSpec is the specification of an Istio VirtualService.
+	virtualServiceSpec struct {
+		Hosts    []string               `yaml:"hosts"`
+		Gateways []string               `yaml:"gateways,omitempty"`
+		HTTP     []*virtualServiceRoute `yaml:"http"`
+	}
+
+	// virtualServiceRoute is a HTTP route of an Istio VirtualService.
+	virtualServiceRoute struct {
+		Name    string                   `yaml:"name"`
+		Match   []map[string]interface{} `yaml:"match"`
+		Route   []map[string]interface{} `yaml:"route"`
+		Timeout string                   `yaml:"timeout,omitempty"`
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "meshroute",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the Gateway API HTTPRoute and Istio VirtualService
+// manifests which route the requests made to the HTTP endpoints.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			if r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+				continue
+			}
+			var data []*serviceRoutes
+			for _, svc := range r.API.HTTP.Services {
+				if !config.Enabled("meshroute", svc.Name()) {
+					continue
+				}
+				data = append(data, buildRoutes(r, svc))
+			}
+			files = append(files,
+				manifestFile("httproute.yaml", "meshroute-httproute", httpRoutes(data)),
+				manifestFile("virtualservice.yaml", "meshroute-virtualservice", virtualServices(data)))
+		}
+	}
+	return files, nil
+}
+
+// buildRoutes computes the routes of the given service.
+func buildRoutes(r *goaexpr.RootExpr, svc *goaexpr.HTTPServiceExpr) *serviceRoutes {
+	name := codegen.KebabCase(svc.Name())
+	hosts, port := hostsAndPort(r, svc.Name())
+	data := &serviceRoutes{
+		Name:    name,
+		Hosts:   hosts,
+		Gateway: expr.Root.Gateway(),
+		Backend: name,
+		Port:    port,
+	}
+	if host, port := expr.Root.Backend(svc.ServiceExpr); host != "" {
+		data.Backend, data.Port = host, port
+	}
+	for _, e := range svc.HTTPEndpoints {
+		for i, rt := range e.Routes {
+			rname := name + "-" + codegen.KebabCase(e.Name())
+			if i > 0 {
+				rname = fmt.Sprintf("%s-%d", rname, i)
+			}
+			data.Routes = append(data.Routes, &routeData{
+				Name:    rname,
+				Method:  rt.Method,
+				Paths:   rt.FullPaths(),
+				Timeout: expr.Root.Timeout(e.MethodExpr),
+			})
+		}
+	}
+	return data
+}
+
+// hostsAndPort returns the host names of the HTTP URIs of the servers which
+// expose the given service and the port of the first URI. The host variables
+// are replaced with their default values.
+func hostsAndPort(r *goaexpr.RootExpr, svc string) ([]string, int) {
+	var (
+		hosts []string
+		port  int
+	)
+	for _, s := range r.API.Servers {
+		var found bool
+		for _, name := range s.Services {
+			if name == svc {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, h := range s.Hosts {
+			for _, uri := range h.URIs {
+				u := string(uri)
+				if !strings.HasPrefix(u, "http") {
+					continue
+				}
+				for _, v := range *goaexpr.AsObject(h.Attribute().Type) {
+					if v.Attribute.DefaultValue != nil {
+						u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+					}
+				}
+				parsed, err := url.Parse(u)
+				if err != nil {
+					continue
+				}
+				if port == 0 {
+					port = 80
+					if parsed.Scheme == "https" {
+						port = 443
+					}
+					if p, err := strconv.Atoi(parsed.Port()); err == nil {
+						port = p
+					}
+				}
+				hosts = appendUnique(hosts, parsed.Hostname())
+			}
+		}
+	}
+	if port == 0 {
+		port = 80
+	}
+	return hosts, port
+}
+
+// httpRoutes returns the Gateway API HTTPRoute resources.
+func httpRoutes(data []*serviceRoutes) []*resource {
+	res := make([]*resource, len(data))
+	for i, d := range data {
+		spec := &httpRouteSpec{Hostnames: d.Hosts}
+		if d.Gateway != "" {
+			spec.ParentRefs = []*ref{{Name: d.Gateway}}
+		}
+		for _, r := range d.Routes {
+			rule := &httpRouteRule{BackendRefs: []*ref{{Name: d.Backend, Port: d.Port}}}
+			for _, p := range r.Paths {
+				match := &httpRouteMatch{Method: r.Method, Path: map[string]string{"type": "Exact", "value": p}}
+				if goaexpr.HTTPWildcardRegex.MatchString(p) {
+					match.Path = map[string]string{"type": "RegularExpression", "value": pathRegex(p)}
+				}
+				rule.Matches = append(rule.Matches, match)
+			}
+			if r.Timeout != "" {
+				rule.Timeouts = map[string]string{"request": r.Timeout}
+			}
+			spec.Rules = append(spec.Rules, rule)
+		}
+		res[i] = &resource{
+			APIVersion: "gateway.networking.k8s.io/v1",
+			Kind:       "HTTPRoute",
+			Metadata:   metadata{Name: d.Name},
+			Spec:       spec,
+		}
+	}
+	return res
+}
+
+// virtualServices returns the Istio VirtualService resources.
+func virtualServices(data []*serviceRoutes) []*resource {
+	res := make([]*resource, len(data))
+	for i, d := range data {
+		spec := &virtualServiceSpec{Hosts: d.Hosts}
+		if len(spec.Hosts) == 0 {
+			spec.Hosts = []string{"*"}
+		}
+		if d.Gateway != "" {
+			spec.Gateways = []string{d.Gateway}
+		}
+		for _, r := range d.Routes {
+			route := &virtualServiceRoute{
+				Name: r.Name,
+				Route: []map[string]interface{}{{
+					"destination": map[string]interface{}{
+						"host": d.Backend,
+						"port": map[string]int{"number": d.Port},
+					},
+				}},
+				Timeout: r.Timeout,
+			}
+			for _, p := range r.Paths {
+				uri := map[string]string{"exact": p}
+				if goaexpr.HTTPWildcardRegex.MatchString(p) {
+					uri = map[string]string{"regex": pathRegex(p)}
+				}
+				route.Match = append(route.Match, map[string]interface{}{
+					"uri":    uri,
+					"method": map[string]string{"exact": r.Method},
+				})
+			}
+			spec.HTTP = append(spec.HTTP, route)
+		}
+		res[i] = &resource{
+			APIVersion: "networking.istio.io/v1beta1",
+			Kind:       "VirtualService",
+			Metadata:   metadata{Name: d.Name},
+			Spec:       spec,
+		}
+	}
+	return res
+}
+
+// manifestFile returns the file containing the given Kubernetes resources.
+func manifestFile(name, section string, resources []*resource) *codegen.File {
+	return stream.File(filepath.Join(codegen.Gendir, "meshroute", name), section, resources, stream.YAML)
+}
+
+// pathRegex returns the anchored regular expression matching the given path
+// which may contain wildcards.
+func pathRegex(p string) string {
+	var (
+		re   strings.Builder
+		last int
+	)
+	re.WriteString("^")
+	for _, m := range goaexpr.HTTPWildcardRegex.FindAllStringIndex(p, -1) {
+		re.WriteString(regexp.QuoteMeta(p[last:m[0]]))
+		if strings.HasPrefix(p[m[0]:], "/{*") {
+			re.WriteString("/.*")
+		} else {
+			re.WriteString("/[^/]+")
+		}
+		last = m[1]
+	}
+	re.WriteString(regexp.QuoteMeta(p[last:]))
+	re.WriteString("$")
+	return re.String()
+}
+
+// appendUnique appends v to vs if vs does not already contain it.
+func appendUnique(vs []string, v string) []string {
+	for _, existing := range vs {
+		if existing == v {
+			return vs
+		}
+	}
+	return append(vs, v)
+}
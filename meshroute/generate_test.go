@@ -0,0 +1,30 @@
+package meshroute_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/meshroute"
+	"goa.design/plugins/v3/meshroute/expr"
+	"goa.design/plugins/v3/meshroute/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Routings = nil
+	root := codegen.RunDSLWithFunc(t, testdata.RoutesDSL, func() {
+		eval.Register(expr.Root)
+	})
+	fs, err := meshroute.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	for _, f := range fs {
+		plugintest.Golden(t, filepath.Base(f.Path), plugintest.Render(t, f))
+	}
+}
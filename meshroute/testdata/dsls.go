@@ -0,0 +1,60 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	meshroute "goa.design/plugins/v3/meshroute/dsl"
+)
+
+var RoutesDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			Host("production", func() {
+				URI("https://{region}.calc.example.com")
+				URI("http://calc.example.com:8080")
+				Variable("region", String, func() {
+					Default("us-east-1")
+				})
+			})
+		})
+		meshroute.Gateway("public")
+	})
+	Service("calc", func() {
+		meshroute.Timeout("10s")
+		HTTP(func() {
+			Path("/calc")
+		})
+		Method("add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			meshroute.Timeout("500ms")
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+				GET("/sum/{a}/{b}")
+			})
+		})
+		Method("reset", func() {
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+		Method("download", func() {
+			Payload(func() {
+				Attribute("path", String)
+			})
+			HTTP(func() {
+				GET("/files/{*path}")
+			})
+		})
+	})
+	Service("health", func() {
+		meshroute.Backend("health-check", 9090)
+		Method("show", func() {
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+}
@@ -0,0 +1,126 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/messaging/expr"
+
+	// Register code generators for the messaging plugin
+	_ "goa.design/plugins/v3/messaging"
+)
+
+// Topic binds the method to a message topic (Kafka) or subject (NATS). The
+// generated consumers decode the messages published on the topic into the
+// method payload and call the method endpoint. The generated clients publish
+// the method payloads on the topic.
+//
+// Topic must appear in a Method expression.
+//
+// Topic takes the name of the topic as first argument and an optional DSL
+// function as second argument.
+//
+// Example:
+//
+//    import messaging "goa.design/plugins/v3/messaging/dsl"
+//
+//    var _ = Service("orders", func() {
+//        Method("process", func() {
+//            Payload(Order)
+//            messaging.Topic("orders.created", func() {
+//                messaging.ConsumerGroup("order-processors")
+//                messaging.DeadLetter("orders.created.dlq")
+//                messaging.MaxDeliveries(5)
+//            })
+//        })
+//    })
+//
+func Topic(name string, fn ...func()) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	if expr.Root.Topic(m.Service.Name, m.Name) != nil {
+		eval.ReportError("method %q is already bound to a topic", m.Name)
+		return
+	}
+	t := &expr.TopicExpr{
+		Name:          name,
+		MaxDeliveries: expr.DefaultMaxDeliveries,
+		Method:        m,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], t) {
+			return
+		}
+	}
+	expr.Root.Topics = append(expr.Root.Topics, t)
+}
+
+// ConsumerGroup sets the name of the consumer group (Kafka) or queue group
+// (NATS) used by the service consumers. The messages are load balanced
+// between the consumers of the same group. The default group name is the
+// service name.
+//
+// ConsumerGroup must appear in a Topic expression.
+//
+// Example:
+//
+//     Topic("orders.created", func() {
+//         ConsumerGroup("order-processors")
+//     })
+//
+func ConsumerGroup(name string) {
+	switch t := eval.Current().(type) {
+	case *expr.TopicExpr:
+		t.Group = name
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// DeadLetter sets the name of the topic the messages are published to when
+// they cannot be decoded or when the endpoint keeps failing after the maximum
+// number of deliveries. Without dead-letter topic the broker is notified of
+// the failure and applies its own redelivery policy.
+//
+// DeadLetter must appear in a Topic expression.
+//
+// Example:
+//
+//     Topic("orders.created", func() {
+//         DeadLetter("orders.created.dlq")
+//     })
+//
+func DeadLetter(topic string) {
+	switch t := eval.Current().(type) {
+	case *expr.TopicExpr:
+		t.DeadLetter = topic
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// MaxDeliveries sets the maximum number of attempts at processing a message
+// (3 by default).
+//
+// MaxDeliveries must appear in a Topic expression.
+//
+// Example:
+//
+//     Topic("orders.created", func() {
+//         MaxDeliveries(5)
+//     })
+//
+func MaxDeliveries(n int) {
+	switch t := eval.Current().(type) {
+	case *expr.TopicExpr:
+		t.MaxDeliveries = n
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,71 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the methods bound to message topics.
+	RootExpr struct {
+		// Topics lists the topic bindings in the order they appear in
+		// the design.
+		Topics []*TopicExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "messaging plugin"
+}
+
+// WalkSets iterates over the topic bindings.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	texps := make(eval.ExpressionSet, len(r.Topics))
+	for i, t := range r.Topics {
+		texps[i] = t
+	}
+	walk(texps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/messaging/dsl"}
+}
+
+// Topic returns the topic binding of the given method, nil if the method is
+// not bound to a topic.
+func (r *RootExpr) Topic(svc, method string) *TopicExpr {
+	for _, t := range r.Topics {
+		if t.Method.Service.Name == svc && t.Method.Name == method {
+			return t
+		}
+	}
+	return nil
+}
+
+// ServiceTopics returns the topic bindings of the given service methods.
+func (r *RootExpr) ServiceTopics(svc string) []*TopicExpr {
+	var topics []*TopicExpr
+	for _, t := range r.Topics {
+		if t.Method.Service.Name == svc {
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
@@ -0,0 +1,93 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// DefaultMaxDeliveries is the default maximum number of deliveries of a
+// message before it is sent to the dead-letter topic.
+const DefaultMaxDeliveries = 3
+
+type (
+	// TopicExpr describes the binding of a method to a message topic (Kafka)
+	// or subject (NATS).
+	TopicExpr struct {
+		// Name is the topic name.
+		Name string
+		// Group is the name of the consumer group (Kafka) or queue group
+		// (NATS) of the service consumers.
+		Group string
+		// DeadLetter is the name of the topic the messages that cannot
+		// be processed are published to, empty if there is none.
+		DeadLetter string
+		// MaxDeliveries is the maximum number of delivery attempts of a
+		// message.
+		MaxDeliveries int
+		// Method is the bound method.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (t *TopicExpr) EvalName() string {
+	return fmt.Sprintf("Topic %q of %s", t.Name, t.Method.EvalName())
+}
+
+// Validate ensures the topic expression is valid.
+func (t *TopicExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if t.Name == "" {
+		verr.Add(t, "topic name cannot be empty")
+	}
+	if t.DeadLetter != "" && t.DeadLetter == t.Name {
+		verr.Add(t, "dead-letter topic cannot be the topic itself")
+	}
+	if t.MaxDeliveries < 1 {
+		verr.Add(t, "maximum number of deliveries must be greater than 0")
+	}
+	if t.Method.IsStreaming() {
+		verr.Add(t, "streaming methods cannot be bound to a topic")
+	}
+	for _, other := range Root.Topics {
+		if other != t && other.Name == t.Name && other.Method.Service == t.Method.Service {
+			verr.Add(t, "topic %q is bound to methods %q and %q of the same service", t.Name, other.Method.Name, t.Method.Name)
+			break
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Finalize defaults the consumer group to the service name and tags the
+// payload fields so that the messages use the design attribute names.
+func (t *TopicExpr) Finalize() {
+	if t.Group == "" {
+		t.Group = t.Method.Service.Name
+	}
+	codegen.Walk(t.Method.Payload, func(att *expr.AttributeExpr) error {
+		obj, ok := att.Type.(*expr.Object)
+		if !ok {
+			return nil
+		}
+		for _, nat := range *obj {
+			if _, ok := nat.Attribute.Meta["struct:tag:json"]; ok {
+				continue
+			}
+			tag := []string{nat.Name}
+			if !att.IsRequired(nat.Name) {
+				tag = append(tag, "omitempty")
+			}
+			if nat.Attribute.Meta == nil {
+				nat.Attribute.Meta = expr.MetaExpr{}
+			}
+			nat.Attribute.Meta["struct:tag:json"] = tag
+		}
+		return nil
+	})
+}
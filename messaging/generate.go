@@ -0,0 +1,243 @@
+package messaging
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/messaging/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// serviceData contains the data necessary to render the consumers and
+	// clients of a service.
+	serviceData struct {
+		// Name is the service name.
+		Name string
+		// PkgName is the name of the service package.
+		PkgName string
+		// Topics lists the methods bound to topics.
+		Topics []*topicData
+	}
+
+	// topicData contains the data necessary to render the consumer and
+	// client of a method.
+	topicData struct {
+		// Topic is the topic name.
+		Topic string
+		// Group is the consumer group name.
+		Group string
+		// DeadLetter is the dead-letter topic name.
+		DeadLetter string
+		// MaxDeliveries is the maximum number of deliveries.
+		MaxDeliveries int
+		// Method is the method name.
+		Method string
+		// VarName is the name of the method endpoint.
+		VarName string
+		// PayloadRef is the reference to the payload type, empty if the
+		// method has no payload.
+		PayloadRef string
+		// PayloadName is the name of the payload type, empty if the
+		// method has no payload.
+		PayloadName string
+		// PayloadPointer is true if the payload is passed by pointer.
+		PayloadPointer bool
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "messaging",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the message consumers and clients of the services with
+// methods bound to topics.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, svc := range goaexpr.Root.Services {
+		if !config.Enabled("messaging", svc.Name) {
+			continue
+		}
+		topics := expr.Root.ServiceTopics(svc.Name)
+		if len(topics) == 0 {
+			continue
+		}
+		data := buildServiceData(svc, topics)
+		files = append(files, serverFile(genpkg, data), clientFile(genpkg, data))
+	}
+	return files, nil
+}
+
+// buildServiceData computes the data necessary to render the service files.
+func buildServiceData(svc *goaexpr.ServiceExpr, topics []*expr.TopicExpr) *serviceData {
+	sd := service.Services.Get(svc.Name)
+	data := &serviceData{Name: svc.Name, PkgName: sd.PkgName}
+	for _, t := range topics {
+		td := &topicData{
+			Topic:         t.Name,
+			Group:         t.Group,
+			DeadLetter:    t.DeadLetter,
+			MaxDeliveries: t.MaxDeliveries,
+			Method:        t.Method.Name,
+			VarName:       sd.Method(t.Method.Name).VarName,
+		}
+		if t.Method.Payload.Type != goaexpr.Empty {
+			td.PayloadRef = sd.Scope.GoFullTypeRef(t.Method.Payload, sd.PkgName)
+			td.PayloadName = sd.Scope.GoFullTypeName(t.Method.Payload, sd.PkgName)
+			td.PayloadPointer = strings.HasPrefix(td.PayloadRef, "*")
+		}
+		data.Topics = append(data.Topics, td)
+	}
+	return data
+}
+
+// serverFile returns the file implementing the message consumers.
+func serverFile(genpkg string, data *serviceData) *codegen.File {
+	svcName := codegen.SnakeCase(codegen.Goify(data.Name, false))
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(data.Name+" message consumers", "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/json"},
+			{Path: "fmt"},
+			codegen.GoaImport(""),
+			{Path: "goa.design/plugins/v3/messaging", Name: "goamessaging"},
+			{Path: genpkg + "/" + svcName, Name: data.PkgName},
+		}),
+		{Name: "messaging-subscribe", Source: subscribeT, Data: data},
+	}
+	for _, t := range data.Topics {
+		sections = append(sections, &codegen.SectionTemplate{Name: "messaging-handler", Source: handlerT, Data: t})
+		if t.PayloadRef != "" {
+			sections = append(sections, &codegen.SectionTemplate{Name: "messaging-decoder", Source: decoderT, Data: t})
+		}
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "messaging", svcName, "server", "server.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// clientFile returns the file implementing the message publishers.
+func clientFile(genpkg string, data *serviceData) *codegen.File {
+	svcName := codegen.SnakeCase(codegen.Goify(data.Name, false))
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(data.Name+" message publishers", "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/json"},
+			{Path: "fmt"},
+			{Path: "goa.design/plugins/v3/messaging", Name: "goamessaging"},
+			{Path: genpkg + "/" + svcName, Name: data.PkgName},
+		}),
+		{Name: "messaging-client", Source: clientT, Data: data},
+	}
+	for _, t := range data.Topics {
+		sections = append(sections,
+			&codegen.SectionTemplate{Name: "messaging-publish", Source: publishT, Data: t},
+			&codegen.SectionTemplate{Name: "messaging-encoder", Source: encoderT, Data: t})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "messaging", svcName, "client", "client.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// input: serviceData
+const subscribeT = `{{ printf "Subscribe registers the consumers of the %q service methods bound to topics with the broker." .Name | comment }}
+func Subscribe(ctx context.Context, b goamessaging.Broker, e *{{ .PkgName }}.Endpoints) error {
+{{- range .Topics }}
+	{
+		c := &goamessaging.Consumer{
+			Broker:        b,
+			{{- if .DeadLetter }}
+			DeadLetter:    {{ printf "%q" .DeadLetter }},
+			{{- end }}
+			MaxDeliveries: {{ .MaxDeliveries }},
+		}
+		if err := b.Subscribe(ctx, {{ printf "%q" .Topic }}, {{ printf "%q" .Group }}, c.Handle(New{{ .VarName }}Handler(e.{{ .VarName }}))); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %s", {{ printf "%q" .Topic }}, err)
+		}
+	}
+{{- end }}
+	return nil
+}
+`
+
+// input: topicData
+const handlerT = `{{ printf "New%sHandler returns the handler of the messages published on %q which calls the %q endpoint." .VarName .Topic .Method | comment }}
+func New{{ .VarName }}Handler(endpoint goa.Endpoint) goamessaging.Handler {
+	return func(ctx context.Context, msg *goamessaging.Message) error {
+	{{- if .PayloadRef }}
+		p, err := Decode{{ .VarName }}Payload(msg)
+		if err != nil {
+			return goamessaging.Permanent(err)
+		}
+		_, err = endpoint(ctx, p)
+	{{- else }}
+		_, err := endpoint(ctx, nil)
+	{{- end }}
+		return err
+	}
+}
+`
+
+// input: topicData
+const decoderT = `{{ printf "Decode%sPayload decodes the %q method payload from the message." .VarName .Method | comment }}
+func Decode{{ .VarName }}Payload(msg *goamessaging.Message) ({{ .PayloadRef }}, error) {
+	var p {{ .PayloadName }}
+	if err := json.Unmarshal(msg.Value, &p); err != nil {
+		return {{ if .PayloadPointer }}nil{{ else }}p{{ end }}, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+	}
+	return {{ if .PayloadPointer }}&{{ end }}p, nil
+}
+`
+
+// input: serviceData
+const clientT = `{{ printf "Client publishes the payloads of the %q service methods bound to topics." .Name | comment }}
+type Client struct {
+	broker goamessaging.Broker
+}
+
+// NewClient returns a client which publishes the messages using b.
+func NewClient(b goamessaging.Broker) *Client {
+	return &Client{broker: b}
+}
+`
+
+// input: topicData
+const publishT = `{{ printf "%s publishes the %q method payload on %q." .VarName .Method .Topic | comment }}
+func (c *Client) {{ .VarName }}(ctx context.Context{{ if .PayloadRef }}, p {{ .PayloadRef }}{{ end }}) error {
+	msg, err := Encode{{ .VarName }}Payload({{ if .PayloadRef }}p{{ end }})
+	if err != nil {
+		return err
+	}
+	return c.broker.Publish(ctx, msg)
+}
+`
+
+// input: topicData
+const encoderT = `{{ printf "Encode%sPayload encodes the %q method payload into a message." .VarName .Method | comment }}
+func Encode{{ .VarName }}Payload({{ if .PayloadRef }}p {{ .PayloadRef }}{{ end }}) (*goamessaging.Message, error) {
+{{- if .PayloadRef }}
+	v, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q message: %s", {{ printf "%q" .Topic }}, err)
+	}
+{{- end }}
+	return &goamessaging.Message{
+		Topic:   {{ printf "%q" .Topic }},
+		{{- if .PayloadRef }}
+		Value:   v,
+		{{- end }}
+		Headers: map[string]string{goamessaging.ContentTypeHeader: "application/json"},
+	}, nil
+}
+`
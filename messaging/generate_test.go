@@ -0,0 +1,85 @@
+package messaging_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/messaging"
+	"goa.design/plugins/v3/messaging/expr"
+	"goa.design/plugins/v3/messaging/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	runDSL(t, testdata.ConsumerDSL)
+	fs, err := messaging.Generate("goa.design/plugins/v3/messaging/gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	cases := []struct {
+		Path     string
+		Sections []string
+		Code     []string
+	}{
+		{
+			filepath.Join("gen", "messaging", "orders", "server", "server.go"),
+			[]string{"messaging-subscribe", "messaging-handler", "messaging-decoder"},
+			[]string{testdata.SubscribeCode, testdata.HandlerCode, testdata.DecoderCode},
+		},
+		{
+			filepath.Join("gen", "messaging", "orders", "client", "client.go"),
+			[]string{"messaging-client", "messaging-publish", "messaging-encoder"},
+			[]string{testdata.ClientCode, testdata.PublishCode, testdata.EncoderCode},
+		},
+	}
+	for i, c := range cases {
+		f := fs[i]
+		if f.Path != c.Path {
+			t.Errorf("got path %q, expected %q", f.Path, c.Path)
+		}
+		for j, name := range c.Sections {
+			code := sectionsCode(t, f.Section(name))
+			if code != c.Code[j] {
+				t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, c.Code[j]))
+			}
+		}
+	}
+}
+
+func TestJSONTags(t *testing.T) {
+	runDSL(t, testdata.ConsumerDSL)
+	order := goaexpr.AsObject(goaexpr.Root.UserType("Order"))
+	cases := map[string]string{"order_id": "order_id", "total": "total,omitempty"}
+	for name, expected := range cases {
+		got := strings.Join(order.Attribute(name).Meta["struct:tag:json"], ",")
+		if got != expected {
+			t.Errorf("got json tag %q for %q, expected %q", got, name, expected)
+		}
+	}
+}
+
+// runDSL runs the given DSL with the messaging plugin root registered so that
+// the topic expressions get finalized.
+func runDSL(t *testing.T, dsl func()) {
+	service.Services = make(service.ServicesData)
+	expr.Root.Topics = nil
+	codegen.RunDSLWithFunc(t, dsl, func() {
+		eval.Register(expr.Root)
+	})
+}
+
+// sectionsCode returns the code of the given sections separated by new lines.
+func sectionsCode(t *testing.T, sections []*codegen.SectionTemplate) string {
+	codes := make([]string, len(sections))
+	for i, s := range sections {
+		codes[i] = codegen.SectionCode(t, s)
+	}
+	return strings.Join(codes, "\n")
+}
@@ -0,0 +1,133 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	// ContentTypeHeader is the name of the message header holding the
+	// content type of the message value.
+	ContentTypeHeader = "content-type"
+	// ErrorHeader is the name of the header holding the processing error of
+	// the messages published to the dead-letter topic.
+	ErrorHeader = "x-error"
+	// OriginalTopicHeader is the name of the header holding the topic of the
+	// messages published to the dead-letter topic.
+	OriginalTopicHeader = "x-original-topic"
+	// DeliveriesHeader is the name of the header holding the number of
+	// delivery attempts of the messages published to the dead-letter topic.
+	DeliveriesHeader = "x-deliveries"
+)
+
+type (
+	// Message is a message published on or consumed from a topic.
+	Message struct {
+		// Topic is the message topic (Kafka) or subject (NATS).
+		Topic string
+		// Key is the optional message key used for partitioning.
+		Key []byte
+		// Value is the encoded message.
+		Value []byte
+		// Headers lists the message headers.
+		Headers map[string]string
+	}
+
+	// Handler processes a message. A nil error acknowledges the message.
+	Handler func(ctx context.Context, msg *Message) error
+
+	// Broker is the interface implemented by the message broker adapters
+	// (e.g. Kafka or NATS clients).
+	Broker interface {
+		// Publish publishes the message on msg.Topic.
+		Publish(ctx context.Context, msg *Message) error
+		// Subscribe registers the handler of the messages published on
+		// the topic. The messages are load balanced between the
+		// handlers registered with the same group. The message is
+		// acknowledged if the handler returns nil.
+		Subscribe(ctx context.Context, topic, group string, h Handler) error
+	}
+
+	// Consumer retries the failed messages and publishes the messages that
+	// cannot be processed to the dead-letter topic.
+	Consumer struct {
+		// Broker is used to publish to the dead-letter topic.
+		Broker Broker
+		// DeadLetter is the dead-letter topic, empty if there is none.
+		DeadLetter string
+		// MaxDeliveries is the maximum number of attempts at processing
+		// a message.
+		MaxDeliveries int
+		// Backoff is the delay before the first retry, the delay doubles
+		// with each retry.
+		Backoff time.Duration
+	}
+
+	// permanentError wraps the errors that should not be retried.
+	permanentError struct {
+		error
+	}
+)
+
+// Permanent wraps err so that the consumer does not retry the message, for
+// example because it cannot be decoded.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsPermanent returns true if err was created with Permanent.
+func IsPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// Unwrap returns the wrapped error.
+func (e *permanentError) Unwrap() error { return e.error }
+
+// Handle returns a handler which calls h until it succeeds, returns a
+// permanent error or MaxDeliveries is reached. The messages that could not be
+// processed are published to the dead-letter topic if any and acknowledged.
+func (c *Consumer) Handle(h Handler) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		var (
+			err        error
+			deliveries int
+			backoff    = c.Backoff
+		)
+		for deliveries < c.MaxDeliveries || deliveries == 0 {
+			if deliveries > 0 && backoff > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(backoff):
+				}
+				backoff *= 2
+			}
+			deliveries++
+			if err = h(ctx, msg); err == nil || IsPermanent(err) {
+				break
+			}
+		}
+		if err == nil || c.DeadLetter == "" {
+			return err
+		}
+		headers := make(map[string]string, len(msg.Headers)+3)
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		headers[ErrorHeader] = err.Error()
+		headers[OriginalTopicHeader] = msg.Topic
+		headers[DeliveriesHeader] = strconv.Itoa(deliveries)
+		return c.Broker.Publish(ctx, &Message{
+			Topic:   c.DeadLetter,
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: headers,
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type broker struct {
+	published []*Message
+}
+
+func (b *broker) Publish(ctx context.Context, msg *Message) error {
+	b.published = append(b.published, msg)
+	return nil
+}
+
+func (b *broker) Subscribe(ctx context.Context, topic, group string, h Handler) error {
+	return nil
+}
+
+func TestConsumerHandle(t *testing.T) {
+	errFailed := errors.New("failed")
+	cases := []struct {
+		Name       string
+		DeadLetter string
+		Failures   int
+		Permanent  bool
+		Calls      int
+		Error      bool
+		Published  bool
+	}{
+		{"success", "dlq", 0, false, 1, false, false},
+		{"retried", "dlq", 2, false, 3, false, false},
+		{"dead-letter", "dlq", 5, false, 3, false, true},
+		{"permanent", "dlq", 5, true, 1, false, true},
+		{"no-dead-letter", "", 5, false, 3, true, false},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				b     = &broker{}
+				calls int
+			)
+			h := func(ctx context.Context, msg *Message) error {
+				calls++
+				if calls > c.Failures {
+					return nil
+				}
+				if c.Permanent {
+					return Permanent(errFailed)
+				}
+				return errFailed
+			}
+			cons := &Consumer{Broker: b, DeadLetter: c.DeadLetter, MaxDeliveries: 3}
+			msg := &Message{Topic: "orders", Value: []byte("{}"), Headers: map[string]string{ContentTypeHeader: "application/json"}}
+			err := cons.Handle(h)(context.Background(), msg)
+			if c.Error && err == nil {
+				t.Error("expected an error")
+			}
+			if !c.Error && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if calls != c.Calls {
+				t.Errorf("got %d calls, expected %d", calls, c.Calls)
+			}
+			if !c.Published {
+				if len(b.published) != 0 {
+					t.Errorf("got %d published messages, expected 0", len(b.published))
+				}
+				return
+			}
+			if len(b.published) != 1 {
+				t.Fatalf("got %d published messages, expected 1", len(b.published))
+			}
+			dl := b.published[0]
+			if dl.Topic != c.DeadLetter {
+				t.Errorf("got topic %q, expected %q", dl.Topic, c.DeadLetter)
+			}
+			if dl.Headers[OriginalTopicHeader] != "orders" {
+				t.Errorf("got original topic %q, expected %q", dl.Headers[OriginalTopicHeader], "orders")
+			}
+			if dl.Headers[ErrorHeader] != "failed" {
+				t.Errorf("got error header %q, expected %q", dl.Headers[ErrorHeader], "failed")
+			}
+			if dl.Headers[ContentTypeHeader] != "application/json" {
+				t.Errorf("content type header not copied")
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package testdata
+
+const SubscribeCode = `// Subscribe registers the consumers of the "Orders" service methods bound to
+// topics with the broker.
+func Subscribe(ctx context.Context, b goamessaging.Broker, e *orders.Endpoints) error {
+	{
+		c := &goamessaging.Consumer{
+			Broker:        b,
+			DeadLetter:    "orders.created.dlq",
+			MaxDeliveries: 5,
+		}
+		if err := b.Subscribe(ctx, "orders.created", "order-processors", c.Handle(NewProcessHandler(e.Process))); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %s", "orders.created", err)
+		}
+	}
+	{
+		c := &goamessaging.Consumer{
+			Broker:        b,
+			MaxDeliveries: 3,
+		}
+		if err := b.Subscribe(ctx, "orders.purge", "Orders", c.Handle(NewPurgeHandler(e.Purge))); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %s", "orders.purge", err)
+		}
+	}
+	{
+		c := &goamessaging.Consumer{
+			Broker:        b,
+			MaxDeliveries: 3,
+		}
+		if err := b.Subscribe(ctx, "orders.cancel", "Orders", c.Handle(NewCancelHandler(e.Cancel))); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %s", "orders.cancel", err)
+		}
+	}
+	return nil
+}
+`
+
+const HandlerCode = `// NewProcessHandler returns the handler of the messages published on
+// "orders.created" which calls the "process" endpoint.
+func NewProcessHandler(endpoint goa.Endpoint) goamessaging.Handler {
+	return func(ctx context.Context, msg *goamessaging.Message) error {
+		p, err := DecodeProcessPayload(msg)
+		if err != nil {
+			return goamessaging.Permanent(err)
+		}
+		_, err = endpoint(ctx, p)
+		return err
+	}
+}
+
+// NewPurgeHandler returns the handler of the messages published on
+// "orders.purge" which calls the "purge" endpoint.
+func NewPurgeHandler(endpoint goa.Endpoint) goamessaging.Handler {
+	return func(ctx context.Context, msg *goamessaging.Message) error {
+		_, err := endpoint(ctx, nil)
+		return err
+	}
+}
+
+// NewCancelHandler returns the handler of the messages published on
+// "orders.cancel" which calls the "cancel" endpoint.
+func NewCancelHandler(endpoint goa.Endpoint) goamessaging.Handler {
+	return func(ctx context.Context, msg *goamessaging.Message) error {
+		p, err := DecodeCancelPayload(msg)
+		if err != nil {
+			return goamessaging.Permanent(err)
+		}
+		_, err = endpoint(ctx, p)
+		return err
+	}
+}
+`
+
+const DecoderCode = `// DecodeProcessPayload decodes the "process" method payload from the message.
+func DecodeProcessPayload(msg *goamessaging.Message) (*orders.Order, error) {
+	var p orders.Order
+	if err := json.Unmarshal(msg.Value, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+	}
+	return &p, nil
+}
+
+// DecodeCancelPayload decodes the "cancel" method payload from the message.
+func DecodeCancelPayload(msg *goamessaging.Message) (string, error) {
+	var p string
+	if err := json.Unmarshal(msg.Value, &p); err != nil {
+		return p, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+	}
+	return p, nil
+}
+`
+
+const ClientCode = `// Client publishes the payloads of the "Orders" service methods bound to
+// topics.
+type Client struct {
+	broker goamessaging.Broker
+}
+
+// NewClient returns a client which publishes the messages using b.
+func NewClient(b goamessaging.Broker) *Client {
+	return &Client{broker: b}
+}
+`
+
+const PublishCode = `// Process publishes the "process" method payload on "orders.created".
+func (c *Client) Process(ctx context.Context, p *orders.Order) error {
+	msg, err := EncodeProcessPayload(p)
+	if err != nil {
+		return err
+	}
+	return c.broker.Publish(ctx, msg)
+}
+
+// Purge publishes the "purge" method payload on "orders.purge".
+func (c *Client) Purge(ctx context.Context) error {
+	msg, err := EncodePurgePayload()
+	if err != nil {
+		return err
+	}
+	return c.broker.Publish(ctx, msg)
+}
+
+// Cancel publishes the "cancel" method payload on "orders.cancel".
+func (c *Client) Cancel(ctx context.Context, p string) error {
+	msg, err := EncodeCancelPayload(p)
+	if err != nil {
+		return err
+	}
+	return c.broker.Publish(ctx, msg)
+}
+`
+
+const EncoderCode = `// EncodeProcessPayload encodes the "process" method payload into a message.
+func EncodeProcessPayload(p *orders.Order) (*goamessaging.Message, error) {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q message: %s", "orders.created", err)
+	}
+	return &goamessaging.Message{
+		Topic:   "orders.created",
+		Value:   v,
+		Headers: map[string]string{goamessaging.ContentTypeHeader: "application/json"},
+	}, nil
+}
+
+// EncodePurgePayload encodes the "purge" method payload into a message.
+func EncodePurgePayload() (*goamessaging.Message, error) {
+	return &goamessaging.Message{
+		Topic:   "orders.purge",
+		Headers: map[string]string{goamessaging.ContentTypeHeader: "application/json"},
+	}, nil
+}
+
+// EncodeCancelPayload encodes the "cancel" method payload into a message.
+func EncodeCancelPayload(p string) (*goamessaging.Message, error) {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q message: %s", "orders.cancel", err)
+	}
+	return &goamessaging.Message{
+		Topic:   "orders.cancel",
+		Value:   v,
+		Headers: map[string]string{goamessaging.ContentTypeHeader: "application/json"},
+	}, nil
+}
+`
@@ -0,0 +1,31 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	messaging "goa.design/plugins/v3/messaging/dsl"
+)
+
+var ConsumerDSL = func() {
+	var Order = Type("Order", func() {
+		Attribute("order_id", String)
+		Attribute("total", Float64)
+		Required("order_id")
+	})
+	Service("Orders", func() {
+		Method("process", func() {
+			Payload(Order)
+			messaging.Topic("orders.created", func() {
+				messaging.ConsumerGroup("order-processors")
+				messaging.DeadLetter("orders.created.dlq")
+				messaging.MaxDeliveries(5)
+			})
+		})
+		Method("purge", func() {
+			messaging.Topic("orders.purge")
+		})
+		Method("cancel", func() {
+			Payload(String)
+			messaging.Topic("orders.cancel")
+		})
+	})
+}
@@ -0,0 +1,352 @@
+package mocks
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+// pkgPath is the import path of the package implementing the in-memory client
+// helpers.
+const pkgPath = "goa.design/plugins/v3/mocks"
+
+type (
+	// mockData contains the data necessary to render a mock type.
+	mockData struct {
+		// Name is the name of the mock type.
+		Name string
+		// Description is the mock type documentation.
+		Description string
+		// Interface is the reference to the interface implemented by the
+		// mock type if any.
+		Interface string
+		// Methods lists the mocked methods.
+		Methods []*methodData
+	}
+
+	// methodData describes a mocked method.
+	methodData struct {
+		// Description is the method documentation.
+		Description string
+		// VarName is the Go method name.
+		VarName string
+		// Params lists the method parameters.
+		Params []*varData
+		// Results lists the method named results. The last result is
+		// always the error.
+		Results []*varData
+	}
+
+	// inMemoryData contains the data necessary to render the constructor
+	// of the in-memory client.
+	inMemoryData struct {
+		// PkgName is the name of the service package.
+		PkgName string
+		// Description is the constructor documentation.
+		Description string
+		// Secured is true if the service uses security schemes.
+		Secured bool
+		// Viewed lists the endpoints whose viewed results are converted
+		// into results as the transport clients do.
+		Viewed []*viewedData
+		// Endpoints lists the expressions of the client endpoints.
+		Endpoints []string
+	}
+
+	// viewedData describes an endpoint returning a viewed result.
+	viewedData struct {
+		// VarName is the name of the endpoint field.
+		VarName string
+		// VarRef is the name of the variable holding the copying
+		// endpoint.
+		VarRef string
+		// Ref is the reference to the viewed result type.
+		Ref string
+		// Validate is the reference to the function validating the
+		// viewed result.
+		Validate string
+		// Init is the reference to the function converting the viewed
+		// result into the result.
+		Init string
+	}
+
+	// varData describes a method parameter or result.
+	varData struct {
+		// Name is the variable name.
+		Name string
+		// Type is the reference to the variable type.
+		Type string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "mocks",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces a package for each service which contains testify mocks
+// of the service interface and of the service client and a constructor of
+// in-memory clients calling a service implementation directly.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("mocks", svc.Name) {
+					continue
+				}
+				files = append(files, mocksFile(genpkg, svc))
+			}
+		}
+	}
+	return files, nil
+}
+
+// mocksFile returns the file defining the mocks of the given service.
+func mocksFile(genpkg string, svc *expr.ServiceExpr) *codegen.File {
+	sd := service.Services.Get(svc.Name)
+	svcPath := codegen.SnakeCase(sd.VarName)
+	imports := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "github.com/stretchr/testify/mock"},
+		{Path: genpkg + "/" + svcPath, Name: sd.PkgName},
+		{Path: pkgPath, Name: "wire"},
+	}
+	if len(sd.Schemes) > 0 {
+		imports = append(imports, &codegen.ImportSpec{Path: "goa.design/goa/v3/security"})
+	}
+	client := inMemoryClient(sd)
+	if len(client.Viewed) > 0 {
+		imports = append(imports, &codegen.ImportSpec{Path: genpkg + "/" + svcPath + "/views", Name: sd.ViewsPkg})
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name+" service mocks", "mocks", imports),
+	}
+	for _, m := range []*mockData{serviceMock(svc, sd), clientMock(svc, sd)} {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:    "mocks-type",
+			Source:  mockT,
+			Data:    m,
+			FuncMap: template.FuncMap{"join": join},
+		})
+	}
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:   "mocks-in-memory-client",
+		Source: inMemoryT,
+		Data:   client,
+	})
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, svcPath, "mocks", "mocks.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// serviceMock returns the data of the mock implementing the service
+// interface. The mock also implements the Auther interface if the service
+// uses security schemes.
+func serviceMock(svc *expr.ServiceExpr, sd *service.Data) *mockData {
+	mock := &mockData{
+		Name:        "Service",
+		Description: "Service is a mock of the " + sd.PkgName + ".Service interface.",
+		Interface:   sd.PkgName + ".Service",
+	}
+	for _, md := range sd.Methods {
+		m := svc.Method(md.Name)
+		params := []*varData{{Name: "ctx", Type: "context.Context"}}
+		if md.PayloadRef != "" {
+			params = append(params, &varData{Name: "p", Type: sd.Scope.GoFullTypeRef(m.Payload, sd.PkgName)})
+		}
+		var results []*varData
+		if md.ServerStream != nil {
+			params = append(params, &varData{Name: "stream", Type: sd.PkgName + "." + md.ServerStream.Interface})
+		} else if md.ResultRef != "" {
+			results = append(results, &varData{Name: "res", Type: sd.Scope.GoFullTypeRef(m.Result, sd.PkgName)})
+			if md.ViewedResult != nil && md.ViewedResult.ViewName == "" {
+				results = append(results, &varData{Name: "view", Type: "string"})
+			}
+		}
+		results = append(results, &varData{Name: "err", Type: "error"})
+		mock.Methods = append(mock.Methods, &methodData{
+			Description: fmt.Sprintf("%s provides a mock function for the %q method.", md.VarName, md.Name),
+			VarName:     md.VarName,
+			Params:      params,
+			Results:     results,
+		})
+	}
+	seen := make(map[string]struct{})
+	for _, s := range sd.Schemes {
+		if _, ok := seen[s.Type]; ok {
+			continue
+		}
+		seen[s.Type] = struct{}{}
+		params := []*varData{{Name: "ctx", Type: "context.Context"}}
+		switch s.Type {
+		case "Basic":
+			params = append(params, &varData{Name: "user", Type: "string"}, &varData{Name: "pass", Type: "string"})
+		case "APIKey":
+			params = append(params, &varData{Name: "key", Type: "string"})
+		default:
+			params = append(params, &varData{Name: "token", Type: "string"})
+		}
+		params = append(params, &varData{Name: "schema", Type: "*security." + s.Type + "Scheme"})
+		mock.Methods = append(mock.Methods, &methodData{
+			Description: fmt.Sprintf("%sAuth provides a mock function for the %s authorization.", s.Type, s.Type),
+			VarName:     s.Type + "Auth",
+			Params:      params,
+			Results:     []*varData{{Name: "res", Type: "context.Context"}, {Name: "err", Type: "error"}},
+		})
+	}
+	return mock
+}
+
+// clientMock returns the data of the mock exposing the same methods as the
+// service client.
+func clientMock(svc *expr.ServiceExpr, sd *service.Data) *mockData {
+	mock := &mockData{
+		Name:        "Client",
+		Description: "Client is a mock of the " + sd.PkgName + ".Client struct.",
+	}
+	for _, md := range sd.Methods {
+		m := svc.Method(md.Name)
+		params := []*varData{{Name: "ctx", Type: "context.Context"}}
+		if md.PayloadRef != "" {
+			params = append(params, &varData{Name: "p", Type: sd.Scope.GoFullTypeRef(m.Payload, sd.PkgName)})
+		}
+		var results []*varData
+		if md.ClientStream != nil {
+			results = append(results, &varData{Name: "res", Type: sd.PkgName + "." + md.ClientStream.Interface})
+		} else if md.ResultRef != "" {
+			results = append(results, &varData{Name: "res", Type: sd.Scope.GoFullTypeRef(m.Result, sd.PkgName)})
+		}
+		results = append(results, &varData{Name: "err", Type: "error"})
+		mock.Methods = append(mock.Methods, &methodData{
+			Description: fmt.Sprintf("%s provides a mock function for the %q method.", md.VarName, md.Name),
+			VarName:     md.VarName,
+			Params:      params,
+			Results:     results,
+		})
+	}
+	return mock
+}
+
+// inMemoryClient returns the data of the constructor of the in-memory client.
+// The streaming endpoints always fail.
+func inMemoryClient(sd *service.Data) *inMemoryData {
+	data := &inMemoryData{
+		PkgName:     sd.PkgName,
+		Description: fmt.Sprintf("NewInMemoryClient returns a %s.Client whose endpoints call the methods of svc directly instead of sending requests. The payloads and results are copied through their JSON representation as they would be by a transport.", sd.PkgName),
+		Secured:     len(sd.Schemes) > 0,
+	}
+	if data.Secured {
+		data.Description += " The security requirements are enforced with the Auther methods of svc."
+	}
+	streaming := false
+	for _, md := range sd.Methods {
+		if md.ServerStream != nil {
+			streaming = true
+			data.Endpoints = append(data.Endpoints, fmt.Sprintf("wire.Unsupported(%q, %q)", sd.Name, md.Name))
+			continue
+		}
+		if vr := md.ViewedResult; vr != nil {
+			data.Viewed = append(data.Viewed, &viewedData{
+				VarName:  md.VarName,
+				VarRef:   codegen.Goify(md.VarName, false) + "Endpoint",
+				Ref:      vr.FullRef,
+				Validate: sd.ViewsPkg + "." + vr.Validate.Name,
+				Init:     sd.PkgName + "." + vr.ResultInit.Name,
+			})
+			data.Endpoints = append(data.Endpoints, "e."+md.VarName)
+			continue
+		}
+		data.Endpoints = append(data.Endpoints, "wire.Copy(e."+md.VarName+")")
+	}
+	if streaming {
+		data.Description += " The streaming endpoints are not supported and always fail."
+	}
+	return data
+}
+
+// join returns the comma separated list of the variable names, or of the
+// variable declarations if decl is true.
+func join(vars []*varData, decl bool) string {
+	var s string
+	for i, v := range vars {
+		if i > 0 {
+			s += ", "
+		}
+		s += v.Name
+		if decl {
+			s += " " + v.Type
+		}
+	}
+	return s
+}
+
+// input: mockData
+const mockT = `{{ comment .Description }}
+type {{ .Name }} struct {
+	mock.Mock
+}
+{{- if .Interface }}
+
+var _ {{ .Interface }} = (*{{ .Name }})(nil)
+{{- end }}
+{{ range .Methods }}
+{{ comment .Description }}
+func (_m *{{ $.Name }}) {{ .VarName }}({{ join .Params true }}) ({{ join .Results true }}) {
+	ret := _m.Called({{ join .Params false }})
+	{{- range $i, $r := .Results }}
+		{{- if eq $r.Type "error" }}
+	err = ret.Error({{ $i }})
+		{{- else }}
+	if v := ret.Get({{ $i }}); v != nil {
+		{{ $r.Name }} = v.({{ $r.Type }})
+	}
+		{{- end }}
+	{{- end }}
+	return
+}
+{{ end }}`
+
+// input: inMemoryData
+const inMemoryT = `{{ comment .Description }}
+{{- if .Secured }}
+func NewInMemoryClient(svc interface {
+	{{ .PkgName }}.Service
+	{{ .PkgName }}.Auther
+}) *{{ .PkgName }}.Client {
+{{- else }}
+func NewInMemoryClient(svc {{ .PkgName }}.Service) *{{ .PkgName }}.Client {
+{{- end }}
+	e := {{ .PkgName }}.NewEndpoints(svc)
+{{- range .Viewed }}
+	{{ .VarRef }} := wire.Copy(e.{{ .VarName }})
+	e.{{ .VarName }} = func(ctx context.Context, req interface{}) (interface{}, error) {
+		res, err := {{ .VarRef }}(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		vres := res.({{ .Ref }})
+		if err := {{ .Validate }}(vres); err != nil {
+			return nil, err
+		}
+		return {{ .Init }}(vres), nil
+	}
+{{- end }}
+	return {{ .PkgName }}.NewClient(
+{{- range .Endpoints }}
+		{{ . }},
+{{- end }}
+	)
+}
+`
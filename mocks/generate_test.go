@@ -0,0 +1,59 @@
+package mocks_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/mocks"
+	"goa.design/plugins/v3/mocks/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	codegen.RunDSL(t, testdata.CalcDSL)
+	fs, err := mocks.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/calc/mocks/mocks.go" {
+		t.Errorf("got path %q, expected gen/calc/mocks/mocks.go", fs[0].Path)
+	}
+	cases := []struct {
+		Name     string
+		Section  int
+		Expected string
+	}{
+		{"service", 1, testdata.ServiceMockCode},
+		{"client", 2, testdata.ClientMockCode},
+		{"in-memory-client", 3, testdata.InMemoryClientCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			code := codegen.SectionCode(t, fs[0].SectionTemplates[c.Section])
+			if code != c.Expected {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Expected))
+			}
+		})
+	}
+}
+
+func TestGenerateDisabled(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	codegen.RunDSL(t, testdata.DisabledDSL)
+	fs, err := mocks.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/calc/mocks/mocks.go" {
+		t.Errorf("got path %q, expected gen/calc/mocks/mocks.go", fs[0].Path)
+	}
+}
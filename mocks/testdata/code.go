@@ -0,0 +1,109 @@
+package testdata
+
+var ServiceMockCode = `// Service is a mock of the calc.Service interface.
+type Service struct {
+	mock.Mock
+}
+
+var _ calc.Service = (*Service)(nil)
+
+// Add provides a mock function for the "Add" method.
+func (_m *Service) Add(ctx context.Context, p *calc.AddPayload) (res *calc.Sum, view string, err error) {
+	ret := _m.Called(ctx, p)
+	if v := ret.Get(0); v != nil {
+		res = v.(*calc.Sum)
+	}
+	if v := ret.Get(1); v != nil {
+		view = v.(string)
+	}
+	err = ret.Error(2)
+	return
+}
+
+// Reset provides a mock function for the "Reset" method.
+func (_m *Service) Reset(ctx context.Context) (err error) {
+	ret := _m.Called(ctx)
+	err = ret.Error(0)
+	return
+}
+
+// Watch provides a mock function for the "Watch" method.
+func (_m *Service) Watch(ctx context.Context, p int, stream calc.WatchServerStream) (err error) {
+	ret := _m.Called(ctx, p, stream)
+	err = ret.Error(0)
+	return
+}
+
+// BasicAuth provides a mock function for the Basic authorization.
+func (_m *Service) BasicAuth(ctx context.Context, user string, pass string, schema *security.BasicScheme) (res context.Context, err error) {
+	ret := _m.Called(ctx, user, pass, schema)
+	if v := ret.Get(0); v != nil {
+		res = v.(context.Context)
+	}
+	err = ret.Error(1)
+	return
+}
+`
+
+var ClientMockCode = `// Client is a mock of the calc.Client struct.
+type Client struct {
+	mock.Mock
+}
+
+// Add provides a mock function for the "Add" method.
+func (_m *Client) Add(ctx context.Context, p *calc.AddPayload) (res *calc.Sum, err error) {
+	ret := _m.Called(ctx, p)
+	if v := ret.Get(0); v != nil {
+		res = v.(*calc.Sum)
+	}
+	err = ret.Error(1)
+	return
+}
+
+// Reset provides a mock function for the "Reset" method.
+func (_m *Client) Reset(ctx context.Context) (err error) {
+	ret := _m.Called(ctx)
+	err = ret.Error(0)
+	return
+}
+
+// Watch provides a mock function for the "Watch" method.
+func (_m *Client) Watch(ctx context.Context, p int) (res calc.WatchClientStream, err error) {
+	ret := _m.Called(ctx, p)
+	if v := ret.Get(0); v != nil {
+		res = v.(calc.WatchClientStream)
+	}
+	err = ret.Error(1)
+	return
+}
+`
+
+var InMemoryClientCode = `// NewInMemoryClient returns a calc.Client whose endpoints call the methods of
+// svc directly instead of sending requests. The payloads and results are
+// copied through their JSON representation as they would be by a transport.
+// The security requirements are enforced with the Auther methods of svc. The
+// streaming endpoints are not supported and always fail.
+func NewInMemoryClient(svc interface {
+	calc.Service
+	calc.Auther
+}) *calc.Client {
+	e := calc.NewEndpoints(svc)
+	addEndpoint := wire.Copy(e.Add)
+	e.Add = func(ctx context.Context, req interface{}) (interface{}, error) {
+		res, err := addEndpoint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		vres := res.(*calcviews.Sum)
+		if err := calcviews.ValidateSum(vres); err != nil {
+			return nil, err
+		}
+		return calc.NewSum(vres), nil
+	}
+	return calc.NewClient(
+		e.Add,
+		wire.Copy(e.Reset),
+		wire.Unsupported("Calc", "Watch"),
+	)
+}
+`
@@ -0,0 +1,47 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CalcDSL = func() {
+	var BasicAuth = BasicAuthSecurity("basic")
+	var Sum = ResultType("application/vnd.sum", func() {
+		Attribute("value", Int)
+		Attribute("operands", ArrayOf(Int))
+		View("default", func() {
+			Attribute("value")
+			Attribute("operands")
+		})
+		View("tiny", func() {
+			Attribute("value")
+		})
+	})
+	Service("Calc", func() {
+		Method("Add", func() {
+			Security(BasicAuth)
+			Payload(func() {
+				Username("user")
+				Password("pass")
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Sum)
+		})
+		Method("Reset", func() {})
+		Method("Watch", func() {
+			Payload(Int)
+			StreamingResult(Int)
+		})
+	})
+}
+
+var DisabledDSL = func() {
+	Service("calc", func() {
+		Method("reset", func() {})
+	})
+	Service("admin", func() {
+		Meta("plugin:mocks", "off")
+		Method("reset", func() {})
+	})
+}
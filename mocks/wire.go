@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Copy returns an endpoint which calls e with a copy of the request and
+// returns a copy of the response. The values are copied through their JSON
+// representation as a transport would so that the caller and the service do
+// not share memory and that the values which cannot be sent over the wire are
+// reported. The errors returned by e are returned as is.
+func Copy(e goa.Endpoint) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		req, err := copyValue(req)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: %s", err)
+		}
+		res, err := e(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		res, err = copyValue(res)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response: %s", err)
+		}
+		return res, nil
+	}
+}
+
+// Unsupported returns an endpoint which always fails. It stands for the
+// streaming endpoints which cannot be called in memory.
+func Unsupported(service, method string) goa.Endpoint {
+	return func(context.Context, interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("%s.%s: streaming endpoints are not supported in memory", service, method)
+	}
+}
+
+// copyValue returns a copy of v of the same type made by decoding its JSON
+// representation.
+func copyValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	c := reflect.New(reflect.TypeOf(v))
+	if err := json.Unmarshal(b, c.Interface()); err != nil {
+		return nil, err
+	}
+	return c.Elem().Interface(), nil
+}
@@ -0,0 +1,62 @@
+package mocks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"goa.design/plugins/v3/mocks"
+)
+
+type payload struct {
+	Name *string
+	Tags []string
+}
+
+func TestCopy(t *testing.T) {
+	name := "a"
+	p := &payload{Name: &name, Tags: []string{"x"}}
+	var received *payload
+	e := mocks.Copy(func(ctx context.Context, req interface{}) (interface{}, error) {
+		received = req.(*payload)
+		received.Tags[0] = "y"
+		return received, nil
+	})
+	res, err := e(context.Background(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received == p || received.Name == p.Name {
+		t.Error("request not copied")
+	}
+	if p.Tags[0] != "x" {
+		t.Errorf("request modified by endpoint, got tag %q", p.Tags[0])
+	}
+	if got := res.(*payload); got == received || *got.Name != "a" || got.Tags[0] != "y" {
+		t.Errorf("invalid response %#v", got)
+	}
+
+	e = mocks.Copy(func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil })
+	res, err = e(context.Background(), (*payload)(nil))
+	if err != nil || res.(*payload) != nil {
+		t.Errorf("got %#v, %v, expected nil payload", res, err)
+	}
+
+	failed := errors.New("failed")
+	e = mocks.Copy(func(context.Context, interface{}) (interface{}, error) { return nil, failed })
+	if _, err := e(context.Background(), nil); err != failed {
+		t.Errorf("got error %v, expected %v", err, failed)
+	}
+
+	e = mocks.Copy(func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	if _, err := e(context.Background(), make(chan int)); err == nil {
+		t.Error("expected error copying a channel")
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	_, err := mocks.Unsupported("calc", "watch")(context.Background(), nil)
+	if err == nil || err.Error() != "calc.watch: streaming endpoints are not supported in memory" {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/mqtt/expr"
+
+	// Register code generators for the mqtt plugin
+	_ "goa.design/plugins/v3/mqtt"
+)
+
+// Topic binds the method to a MQTT topic. The generated server subscribes to
+// the topic, decodes the messages into the method payload and calls the method
+// endpoint. The generated client publishes the method payloads on the topic.
+//
+// The levels of the topic name of the form {name} are mapped to the payload
+// attribute with the same name which must be a required String. The server
+// subscribes to the topic with a single-level wildcard in place of each
+// parameter and sets the attributes from the topic of the received messages.
+// The client builds the topic from the payload attributes. The mapped
+// attributes are not encoded in the messages.
+//
+// Topic must appear in a Method expression.
+//
+// Topic takes the name of the topic as first argument and an optional DSL
+// function as second argument.
+//
+// Example:
+//
+//    import mqtt "goa.design/plugins/v3/mqtt/dsl"
+//
+//    var _ = Service("devices", func() {
+//        Method("report", func() {
+//            Payload(func() {
+//                Attribute("device_id", String)
+//                Attribute("temperature", Float64)
+//                Required("device_id")
+//            })
+//            mqtt.Topic("devices/{device_id}/telemetry", func() {
+//                mqtt.QoS(0)
+//                mqtt.SharedGroup("ingesters")
+//            })
+//        })
+//    })
+//
+func Topic(pattern string, fn ...func()) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	if expr.Root.Topic(m.Service.Name, m.Name) != nil {
+		eval.ReportError("method %q is already bound to a MQTT topic", m.Name)
+		return
+	}
+	t := &expr.TopicExpr{
+		Pattern: pattern,
+		QoS:     expr.DefaultQoS,
+		Method:  m,
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], t) {
+			return
+		}
+	}
+	expr.Root.Topics = append(expr.Root.Topics, t)
+}
+
+// QoS sets the quality of service level used to subscribe to the topic and to
+// publish the messages: 0 (at most once), 1 (at least once, the default) or 2
+// (exactly once).
+//
+// QoS must appear in a Topic expression.
+//
+// Example:
+//
+//     Topic("devices/{device_id}/commands", func() {
+//         QoS(2)
+//     })
+//
+func QoS(level int) {
+	switch t := eval.Current().(type) {
+	case *expr.TopicExpr:
+		t.QoS = level
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Retain sets the retain flag of the published messages so that the broker
+// delivers the last message to the new subscribers of the topic.
+//
+// Retain must appear in a Topic expression.
+//
+// Example:
+//
+//     Topic("devices/{device_id}/config", func() {
+//         Retain()
+//     })
+//
+func Retain() {
+	switch t := eval.Current().(type) {
+	case *expr.TopicExpr:
+		t.Retain = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// SharedGroup makes the server use a MQTT 5 shared subscription so that the
+// messages are load balanced between the servers subscribing with the same
+// group. The subscriptions are not shared by default.
+//
+// SharedGroup must appear in a Topic expression.
+//
+// Example:
+//
+//     Topic("devices/{device_id}/telemetry", func() {
+//         SharedGroup("ingesters")
+//     })
+//
+func SharedGroup(name string) {
+	switch t := eval.Current().(type) {
+	case *expr.TopicExpr:
+		t.SharedGroup = name
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,162 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// DefaultQoS is the default quality of service level of the subscriptions and
+// published messages (at least once).
+const DefaultQoS = 1
+
+type (
+	// TopicExpr describes the binding of a method to a MQTT topic.
+	TopicExpr struct {
+		// Pattern is the topic name. The levels of the form {name} are
+		// mapped to the payload attribute with the same name.
+		Pattern string
+		// QoS is the quality of service level (0, 1 or 2).
+		QoS int
+		// Retain is true if the published messages are retained by the
+		// broker.
+		Retain bool
+		// SharedGroup is the name of the group of the MQTT 5 shared
+		// subscription, empty if the subscription is not shared.
+		SharedGroup string
+		// Method is the bound method.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (t *TopicExpr) EvalName() string {
+	return fmt.Sprintf("MQTT topic %q of %s", t.Pattern, t.Method.EvalName())
+}
+
+// Params returns the names of the topic parameters in the order they appear
+// in the pattern.
+func (t *TopicExpr) Params() []string {
+	var params []string
+	for _, l := range strings.Split(t.Pattern, "/") {
+		if isParam(l) {
+			params = append(params, l[1:len(l)-1])
+		}
+	}
+	return params
+}
+
+// Filter returns the topic filter used to subscribe to the topic: the
+// parameters are replaced with single-level wildcards and the shared group
+// prefix is added if any.
+func (t *TopicExpr) Filter() string {
+	levels := strings.Split(t.Pattern, "/")
+	for i, l := range levels {
+		if isParam(l) {
+			levels[i] = "+"
+		}
+	}
+	filter := strings.Join(levels, "/")
+	if t.SharedGroup != "" {
+		filter = "$share/" + t.SharedGroup + "/" + filter
+	}
+	return filter
+}
+
+// Validate ensures the topic expression is valid.
+func (t *TopicExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if t.Pattern == "" {
+		verr.Add(t, "topic name cannot be empty")
+	}
+	if strings.ContainsAny(t.Pattern, "+#") {
+		verr.Add(t, "topic name cannot contain wildcards, use {name} levels to map payload attributes")
+	}
+	for _, l := range strings.Split(t.Pattern, "/") {
+		if strings.ContainsAny(l, "{}") && !isParam(l) {
+			verr.Add(t, "invalid topic level %q, parameters must span whole levels", l)
+		}
+	}
+	if t.QoS < 0 || t.QoS > 2 {
+		verr.Add(t, "QoS must be 0, 1 or 2")
+	}
+	if strings.ContainsAny(t.SharedGroup, "/+#") {
+		verr.Add(t, "shared group %q cannot contain '/', '+' or '#'", t.SharedGroup)
+	}
+	if t.Method.IsStreaming() {
+		verr.Add(t, "streaming methods cannot be bound to a MQTT topic")
+	}
+	if params := t.Params(); len(params) > 0 {
+		obj := expr.AsObject(t.Method.Payload.Type)
+		if obj == nil {
+			verr.Add(t, "topic parameters require an object payload")
+		} else {
+			seen := make(map[string]bool)
+			for _, p := range params {
+				if seen[p] {
+					verr.Add(t, "topic parameter %q appears more than once", p)
+				}
+				seen[p] = true
+				att := obj.Attribute(p)
+				if att == nil {
+					verr.Add(t, "topic parameter %q is not an attribute of the payload", p)
+				} else if att.Type != expr.String || !t.Method.Payload.IsRequired(p) {
+					verr.Add(t, "topic parameter %q must be a required String attribute of the payload", p)
+				}
+			}
+		}
+	}
+	for _, other := range Root.Topics {
+		if other != t && other.Pattern == t.Pattern && other.Method.Service == t.Method.Service {
+			verr.Add(t, "topic %q is bound to methods %q and %q of the same service", t.Pattern, other.Method.Name, t.Method.Name)
+			break
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Finalize tags the payload fields so that the messages use the design
+// attribute names and so that the topic parameters are not encoded in the
+// messages.
+func (t *TopicExpr) Finalize() {
+	params := make(map[string]bool)
+	for _, p := range t.Params() {
+		params[p] = true
+	}
+	top := true
+	codegen.Walk(t.Method.Payload, func(att *expr.AttributeExpr) error {
+		obj, ok := att.Type.(*expr.Object)
+		if !ok {
+			return nil
+		}
+		for _, nat := range *obj {
+			if _, ok := nat.Attribute.Meta["struct:tag:json"]; ok {
+				continue
+			}
+			tag := []string{nat.Name}
+			if top && params[nat.Name] {
+				tag = []string{"-"}
+			} else if !att.IsRequired(nat.Name) {
+				tag = append(tag, "omitempty")
+			}
+			if nat.Attribute.Meta == nil {
+				nat.Attribute.Meta = expr.MetaExpr{}
+			}
+			nat.Attribute.Meta["struct:tag:json"] = tag
+		}
+		top = false
+		return nil
+	})
+}
+
+// isParam returns true if the given topic level is a parameter.
+func isParam(level string) bool {
+	return len(level) > 2 && level[0] == '{' && level[len(level)-1] == '}' &&
+		!strings.ContainsAny(level[1:len(level)-1], "{}")
+}
@@ -0,0 +1,288 @@
+package mqtt
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/mqtt/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// serviceData contains the data necessary to render the MQTT server
+	// and client of a service.
+	serviceData struct {
+		// Name is the service name.
+		Name string
+		// PkgName is the name of the service package.
+		PkgName string
+		// Topics lists the methods bound to topics.
+		Topics []*topicData
+	}
+
+	// topicData contains the data necessary to render the handler and
+	// publisher of a method.
+	topicData struct {
+		// Pattern is the topic name with the {name} parameters.
+		Pattern string
+		// Filter is the topic filter used to subscribe.
+		Filter string
+		// QoS is the quality of service level.
+		QoS int
+		// Retain is true if the published messages are retained.
+		Retain bool
+		// Method is the method name.
+		Method string
+		// VarName is the name of the method endpoint.
+		VarName string
+		// PayloadRef is the reference to the payload type, empty if the
+		// method has no payload.
+		PayloadRef string
+		// PayloadName is the name of the payload type, empty if the
+		// method has no payload.
+		PayloadName string
+		// PayloadPointer is true if the payload is passed by pointer.
+		PayloadPointer bool
+		// Params lists the topic parameters.
+		Params []*paramData
+	}
+
+	// paramData contains the data necessary to map a topic parameter to
+	// a payload field.
+	paramData struct {
+		// Name is the parameter name.
+		Name string
+		// FieldName is the name of the payload field.
+		FieldName string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "mqtt",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the MQTT servers and clients of the services with methods
+// bound to MQTT topics.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, svc := range goaexpr.Root.Services {
+		if !config.Enabled("mqtt", svc.Name) {
+			continue
+		}
+		topics := expr.Root.ServiceTopics(svc.Name)
+		if len(topics) == 0 {
+			continue
+		}
+		data := buildServiceData(svc, topics)
+		files = append(files, serverFile(genpkg, data), clientFile(genpkg, data))
+	}
+	return files, nil
+}
+
+// buildServiceData computes the data necessary to render the service files.
+func buildServiceData(svc *goaexpr.ServiceExpr, topics []*expr.TopicExpr) *serviceData {
+	sd := service.Services.Get(svc.Name)
+	data := &serviceData{Name: svc.Name, PkgName: sd.PkgName}
+	for _, t := range topics {
+		td := &topicData{
+			Pattern: t.Pattern,
+			Filter:  t.Filter(),
+			QoS:     t.QoS,
+			Retain:  t.Retain,
+			Method:  t.Method.Name,
+			VarName: sd.Method(t.Method.Name).VarName,
+		}
+		if t.Method.Payload.Type != goaexpr.Empty {
+			td.PayloadRef = sd.Scope.GoFullTypeRef(t.Method.Payload, sd.PkgName)
+			td.PayloadName = sd.Scope.GoFullTypeName(t.Method.Payload, sd.PkgName)
+			td.PayloadPointer = strings.HasPrefix(td.PayloadRef, "*")
+		}
+		if params := t.Params(); len(params) > 0 {
+			obj := goaexpr.AsObject(t.Method.Payload.Type)
+			for _, p := range params {
+				td.Params = append(td.Params, &paramData{
+					Name:      p,
+					FieldName: codegen.GoifyAtt(obj.Attribute(p), p, true),
+				})
+			}
+		}
+		data.Topics = append(data.Topics, td)
+	}
+	return data
+}
+
+// serverFile returns the file implementing the MQTT handlers.
+func serverFile(genpkg string, data *serviceData) *codegen.File {
+	svcName := codegen.SnakeCase(codegen.Goify(data.Name, false))
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(data.Name+" MQTT server", "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "fmt"},
+			codegen.GoaImport(""),
+			{Path: "goa.design/plugins/v3/mqtt", Name: "goamqtt"},
+			{Path: genpkg + "/" + svcName, Name: data.PkgName},
+		}),
+		{Name: "mqtt-subscribe", Source: subscribeT, Data: data},
+	}
+	for _, t := range data.Topics {
+		sections = append(sections, &codegen.SectionTemplate{Name: "mqtt-handler", Source: handlerT, Data: t})
+		if t.PayloadRef != "" {
+			sections = append(sections, &codegen.SectionTemplate{Name: "mqtt-decoder", Source: decoderT, Data: t})
+		}
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "mqtt", svcName, "server", "server.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// clientFile returns the file implementing the MQTT publishers.
+func clientFile(genpkg string, data *serviceData) *codegen.File {
+	svcName := codegen.SnakeCase(codegen.Goify(data.Name, false))
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(data.Name+" MQTT client", "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "fmt"},
+			{Path: "goa.design/plugins/v3/mqtt", Name: "goamqtt"},
+			{Path: genpkg + "/" + svcName, Name: data.PkgName},
+		}),
+		{Name: "mqtt-client", Source: clientT, Data: data},
+	}
+	for _, t := range data.Topics {
+		sections = append(sections,
+			&codegen.SectionTemplate{Name: "mqtt-publish", Source: publishT, Data: t},
+			&codegen.SectionTemplate{Name: "mqtt-encoder", Source: encoderT, Data: t})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "mqtt", svcName, "client", "client.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// input: serviceData
+const subscribeT = `{{ printf "Subscribe subscribes the handlers of the %q service methods bound to MQTT topics with the client. The message payloads are decoded with codec, JSON if nil." .Name | comment }}
+func Subscribe(ctx context.Context, c goamqtt.Client, e *{{ .PkgName }}.Endpoints, codec goamqtt.Codec) error {
+	if codec == nil {
+		codec = goamqtt.JSON
+	}
+{{- range .Topics }}
+	if err := c.Subscribe(ctx, {{ printf "%q" .Filter }}, {{ .QoS }}, New{{ .VarName }}Handler(e.{{ .VarName }}, codec)); err != nil {
+		return fmt.Errorf("failed to subscribe to %q: %s", {{ printf "%q" .Filter }}, err)
+	}
+{{- end }}
+	return nil
+}
+`
+
+// input: topicData
+const handlerT = `{{ printf "New%sHandler returns the handler of the messages published on %q which calls the %q endpoint." .VarName .Pattern .Method | comment }}
+func New{{ .VarName }}Handler(endpoint goa.Endpoint, codec goamqtt.Codec) goamqtt.Handler {
+	return func(ctx context.Context, msg *goamqtt.Message) error {
+	{{- if .PayloadRef }}
+		p, err := Decode{{ .VarName }}Payload(msg, codec)
+		if err != nil {
+			return err
+		}
+		_, err = endpoint(ctx, p)
+	{{- else }}
+		_, err := endpoint(ctx, nil)
+	{{- end }}
+		return err
+	}
+}
+`
+
+// input: topicData
+const decoderT = `{{ printf "Decode%sPayload decodes the %q method payload from the message." .VarName .Method | comment }}
+func Decode{{ .VarName }}Payload(msg *goamqtt.Message, codec goamqtt.Codec) ({{ .PayloadRef }}, error) {
+	var p {{ .PayloadName }}
+{{- if .Params }}
+	params, ok := goamqtt.MatchTopic({{ printf "%q" .Pattern }}, msg.Topic)
+	if !ok {
+		return {{ if .PayloadPointer }}nil{{ else }}p{{ end }}, fmt.Errorf("topic %q does not match %q", msg.Topic, {{ printf "%q" .Pattern }})
+	}
+	if len(msg.Payload) > 0 {
+		if err := codec.Unmarshal(msg.Payload, &p); err != nil {
+			return {{ if .PayloadPointer }}nil{{ else }}p{{ end }}, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+		}
+	}
+	{{- range .Params }}
+	p.{{ .FieldName }} = params[{{ printf "%q" .Name }}]
+	{{- end }}
+{{- else }}
+	if err := codec.Unmarshal(msg.Payload, &p); err != nil {
+		return {{ if .PayloadPointer }}nil{{ else }}p{{ end }}, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+	}
+{{- end }}
+	return {{ if .PayloadPointer }}&{{ end }}p, nil
+}
+`
+
+// input: serviceData
+const clientT = `{{ printf "Client publishes the payloads of the %q service methods bound to MQTT topics." .Name | comment }}
+type Client struct {
+	client goamqtt.Client
+	codec  goamqtt.Codec
+}
+
+// NewClient returns a client which publishes the messages using c. The
+// message payloads are encoded with codec, JSON if nil.
+func NewClient(c goamqtt.Client, codec goamqtt.Codec) *Client {
+	if codec == nil {
+		codec = goamqtt.JSON
+	}
+	return &Client{client: c, codec: codec}
+}
+`
+
+// input: topicData
+const publishT = `{{ printf "%s publishes the %q method payload on %q." .VarName .Method .Pattern | comment }}
+func (c *Client) {{ .VarName }}(ctx context.Context{{ if .PayloadRef }}, p {{ .PayloadRef }}{{ end }}) error {
+	msg, err := Encode{{ .VarName }}Payload({{ if .PayloadRef }}p, {{ end }}c.codec)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, msg)
+}
+`
+
+// input: topicData
+const encoderT = `{{ printf "Encode%sPayload encodes the %q method payload into a message." .VarName .Method | comment }}
+func Encode{{ .VarName }}Payload({{ if .PayloadRef }}p {{ .PayloadRef }}, {{ end }}codec goamqtt.Codec) (*goamqtt.Message, error) {
+{{- if .Params }}
+	topic, err := goamqtt.BuildTopic({{ printf "%q" .Pattern }}, map[string]string{
+	{{- range .Params }}
+		{{ printf "%q" .Name }}: p.{{ .FieldName }},
+	{{- end }}
+	})
+	if err != nil {
+		return nil, err
+	}
+{{- end }}
+{{- if .PayloadRef }}
+	v, err := codec.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q message: %s", {{ printf "%q" .Pattern }}, err)
+	}
+{{- end }}
+	return &goamqtt.Message{
+		Topic:   {{ if .Params }}topic{{ else }}{{ printf "%q" .Pattern }}{{ end }},
+		{{- if .PayloadRef }}
+		Payload: v,
+		{{- end }}
+		QoS:     {{ .QoS }},
+		{{- if .Retain }}
+		Retain:  true,
+		{{- end }}
+	}, nil
+}
+`
@@ -0,0 +1,99 @@
+package mqtt_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/mqtt"
+	"goa.design/plugins/v3/mqtt/expr"
+	"goa.design/plugins/v3/mqtt/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	runDSL(t, testdata.DevicesDSL)
+	fs, err := mqtt.Generate("goa.design/plugins/v3/mqtt/gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	cases := []struct {
+		Path     string
+		Sections []string
+		Code     []string
+	}{
+		{
+			filepath.Join("gen", "mqtt", "devices", "server", "server.go"),
+			[]string{"mqtt-subscribe", "mqtt-handler", "mqtt-decoder"},
+			[]string{testdata.SubscribeCode, testdata.HandlerCode, testdata.DecoderCode},
+		},
+		{
+			filepath.Join("gen", "mqtt", "devices", "client", "client.go"),
+			[]string{"mqtt-client", "mqtt-publish", "mqtt-encoder"},
+			[]string{testdata.ClientCode, testdata.PublishCode, testdata.EncoderCode},
+		},
+	}
+	for i, c := range cases {
+		f := fs[i]
+		if f.Path != c.Path {
+			t.Errorf("got path %q, expected %q", f.Path, c.Path)
+		}
+		for j, name := range c.Sections {
+			code := sectionsCode(t, f.Section(name))
+			if code != c.Code[j] {
+				t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, c.Code[j]))
+			}
+		}
+	}
+}
+
+func TestJSONTags(t *testing.T) {
+	runDSL(t, testdata.DevicesDSL)
+	reading := goaexpr.AsObject(goaexpr.Root.UserType("Reading"))
+	cases := map[string]string{"device_id": "-", "temperature": "temperature,omitempty"}
+	for name, expected := range cases {
+		got := strings.Join(reading.Attribute(name).Meta["struct:tag:json"], ",")
+		if got != expected {
+			t.Errorf("got json tag %q for %q, expected %q", got, name, expected)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	runDSL(t, testdata.DevicesDSL)
+	cases := map[string]string{
+		"devices/{device_id}/telemetry": "$share/ingesters/devices/+/telemetry",
+		"devices/config":                "devices/config",
+	}
+	for _, topic := range expr.Root.Topics {
+		expected, ok := cases[topic.Pattern]
+		if !ok {
+			continue
+		}
+		if got := topic.Filter(); got != expected {
+			t.Errorf("got filter %q for %q, expected %q", got, topic.Pattern, expected)
+		}
+	}
+}
+
+// runDSL runs the given DSL with the mqtt plugin root registered so that
+// the topic expressions get finalized.
+func runDSL(t *testing.T, dsl func()) {
+	expr.Root.Topics = nil
+	plugintest.RunDSL(t, dsl, expr.Root)
+}
+
+// sectionsCode returns the code of the given sections separated by new lines.
+func sectionsCode(t *testing.T, sections []*codegen.SectionTemplate) string {
+	codes := make([]string, len(sections))
+	for i, s := range sections {
+		codes[i] = codegen.SectionCode(t, s)
+	}
+	return strings.Join(codes, "\n")
+}
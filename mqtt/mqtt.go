@@ -0,0 +1,109 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Message is a message published on or received from a MQTT topic.
+	Message struct {
+		// Topic is the topic name.
+		Topic string
+		// Payload is the encoded message.
+		Payload []byte
+		// QoS is the quality of service level (0, 1 or 2).
+		QoS byte
+		// Retain is true if the broker retains the message.
+		Retain bool
+	}
+
+	// Handler processes a message received from a topic.
+	Handler func(ctx context.Context, msg *Message) error
+
+	// Client is the interface implemented by the adapters of the MQTT client
+	// libraries (e.g. paho.mqtt.golang or paho.golang).
+	Client interface {
+		// Publish publishes the message on msg.Topic.
+		Publish(ctx context.Context, msg *Message) error
+		// Subscribe registers the handler of the messages published on
+		// the topics matching the filter.
+		Subscribe(ctx context.Context, filter string, qos byte, h Handler) error
+	}
+
+	// Codec encodes and decodes the message payloads.
+	Codec interface {
+		// Marshal encodes v.
+		Marshal(v interface{}) ([]byte, error)
+		// Unmarshal decodes data into v.
+		Unmarshal(data []byte, v interface{}) error
+	}
+
+	// jsonCodec is the JSON codec.
+	jsonCodec struct{}
+)
+
+// JSON is the codec encoding the payloads in JSON, the default.
+var JSON Codec = jsonCodec{}
+
+// Marshal encodes v in JSON.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the JSON data into v.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// BuildTopic returns the topic name obtained by replacing the {name} levels of
+// pattern with the corresponding params. It returns an error if a parameter is
+// missing, empty or contains '/', '+' or '#'.
+func BuildTopic(pattern string, params map[string]string) (string, error) {
+	levels := strings.Split(pattern, "/")
+	for i, l := range levels {
+		name, ok := param(l)
+		if !ok {
+			continue
+		}
+		v := params[name]
+		if v == "" {
+			return "", fmt.Errorf("missing topic parameter %q", name)
+		}
+		if strings.ContainsAny(v, "/+#") {
+			return "", fmt.Errorf("invalid topic parameter %q: %q contains '/', '+' or '#'", name, v)
+		}
+		levels[i] = v
+	}
+	return strings.Join(levels, "/"), nil
+}
+
+// MatchTopic returns the parameters of pattern extracted from topic and true
+// if topic matches pattern, false otherwise.
+func MatchTopic(pattern, topic string) (map[string]string, bool) {
+	plevels := strings.Split(pattern, "/")
+	tlevels := strings.Split(topic, "/")
+	if len(plevels) != len(tlevels) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, l := range plevels {
+		if name, ok := param(l); ok {
+			params[name] = tlevels[i]
+		} else if l != tlevels[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// param returns the name of the parameter defined by the given topic level
+// and true if the level is a parameter, false otherwise.
+func param(level string) (string, bool) {
+	if len(level) < 3 || level[0] != '{' || level[len(level)-1] != '}' {
+		return "", false
+	}
+	return level[1 : len(level)-1], true
+}
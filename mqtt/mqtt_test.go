@@ -0,0 +1,73 @@
+package mqtt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildTopic(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Pattern  string
+		Params   map[string]string
+		Expected string
+		Error    bool
+	}{
+		{"no-param", "devices/config", nil, "devices/config", false},
+		{"params", "devices/{id}/{kind}", map[string]string{"id": "d1", "kind": "temp"}, "devices/d1/temp", false},
+		{"missing", "devices/{id}", nil, "", true},
+		{"slash", "devices/{id}", map[string]string{"id": "a/b"}, "", true},
+		{"wildcard", "devices/{id}", map[string]string{"id": "+"}, "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			got, err := BuildTopic(c.Pattern, c.Params)
+			if (err != nil) != c.Error {
+				t.Fatalf("got error %v, expected error: %v", err, c.Error)
+			}
+			if got != c.Expected {
+				t.Errorf("got topic %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Pattern  string
+		Topic    string
+		Expected map[string]string
+		Match    bool
+	}{
+		{"no-param", "devices/config", "devices/config", map[string]string{}, true},
+		{"params", "devices/{id}/{kind}", "devices/d1/temp", map[string]string{"id": "d1", "kind": "temp"}, true},
+		{"mismatch", "devices/{id}/telemetry", "devices/d1/status", nil, false},
+		{"levels", "devices/{id}", "devices/d1/temp", nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			got, ok := MatchTopic(c.Pattern, c.Topic)
+			if ok != c.Match {
+				t.Fatalf("got match %v, expected %v", ok, c.Match)
+			}
+			if !reflect.DeepEqual(got, c.Expected) {
+				t.Errorf("got params %v, expected %v", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	b, err := JSON.Marshal(map[string]int{"a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]int
+	if err := JSON.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["a"] != 1 {
+		t.Errorf("got %v, expected a=1", v)
+	}
+}
@@ -0,0 +1,172 @@
+package testdata
+
+const SubscribeCode = `// Subscribe subscribes the handlers of the "Devices" service methods bound to
+// MQTT topics with the client. The message payloads are decoded with codec,
+// JSON if nil.
+func Subscribe(ctx context.Context, c goamqtt.Client, e *devices.Endpoints, codec goamqtt.Codec) error {
+	if codec == nil {
+		codec = goamqtt.JSON
+	}
+	if err := c.Subscribe(ctx, "$share/ingesters/devices/+/telemetry", 0, NewReportHandler(e.Report, codec)); err != nil {
+		return fmt.Errorf("failed to subscribe to %q: %s", "$share/ingesters/devices/+/telemetry", err)
+	}
+	if err := c.Subscribe(ctx, "devices/config", 2, NewConfigureHandler(e.Configure, codec)); err != nil {
+		return fmt.Errorf("failed to subscribe to %q: %s", "devices/config", err)
+	}
+	if err := c.Subscribe(ctx, "devices/reset", 1, NewResetHandler(e.Reset, codec)); err != nil {
+		return fmt.Errorf("failed to subscribe to %q: %s", "devices/reset", err)
+	}
+	return nil
+}
+`
+
+const HandlerCode = `// NewReportHandler returns the handler of the messages published on
+// "devices/{device_id}/telemetry" which calls the "report" endpoint.
+func NewReportHandler(endpoint goa.Endpoint, codec goamqtt.Codec) goamqtt.Handler {
+	return func(ctx context.Context, msg *goamqtt.Message) error {
+		p, err := DecodeReportPayload(msg, codec)
+		if err != nil {
+			return err
+		}
+		_, err = endpoint(ctx, p)
+		return err
+	}
+}
+
+// NewConfigureHandler returns the handler of the messages published on
+// "devices/config" which calls the "configure" endpoint.
+func NewConfigureHandler(endpoint goa.Endpoint, codec goamqtt.Codec) goamqtt.Handler {
+	return func(ctx context.Context, msg *goamqtt.Message) error {
+		p, err := DecodeConfigurePayload(msg, codec)
+		if err != nil {
+			return err
+		}
+		_, err = endpoint(ctx, p)
+		return err
+	}
+}
+
+// NewResetHandler returns the handler of the messages published on
+// "devices/reset" which calls the "reset" endpoint.
+func NewResetHandler(endpoint goa.Endpoint, codec goamqtt.Codec) goamqtt.Handler {
+	return func(ctx context.Context, msg *goamqtt.Message) error {
+		_, err := endpoint(ctx, nil)
+		return err
+	}
+}
+`
+
+const DecoderCode = `// DecodeReportPayload decodes the "report" method payload from the message.
+func DecodeReportPayload(msg *goamqtt.Message, codec goamqtt.Codec) (*devices.Reading, error) {
+	var p devices.Reading
+	params, ok := goamqtt.MatchTopic("devices/{device_id}/telemetry", msg.Topic)
+	if !ok {
+		return nil, fmt.Errorf("topic %q does not match %q", msg.Topic, "devices/{device_id}/telemetry")
+	}
+	if len(msg.Payload) > 0 {
+		if err := codec.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+		}
+	}
+	p.DeviceID = params["device_id"]
+	return &p, nil
+}
+
+// DecodeConfigurePayload decodes the "configure" method payload from the
+// message.
+func DecodeConfigurePayload(msg *goamqtt.Message, codec goamqtt.Codec) (string, error) {
+	var p string
+	if err := codec.Unmarshal(msg.Payload, &p); err != nil {
+		return p, fmt.Errorf("failed to decode %q message: %s", msg.Topic, err)
+	}
+	return p, nil
+}
+`
+
+const ClientCode = `// Client publishes the payloads of the "Devices" service methods bound to MQTT
+// topics.
+type Client struct {
+	client goamqtt.Client
+	codec  goamqtt.Codec
+}
+
+// NewClient returns a client which publishes the messages using c. The
+// message payloads are encoded with codec, JSON if nil.
+func NewClient(c goamqtt.Client, codec goamqtt.Codec) *Client {
+	if codec == nil {
+		codec = goamqtt.JSON
+	}
+	return &Client{client: c, codec: codec}
+}
+`
+
+const PublishCode = `// Report publishes the "report" method payload on
+// "devices/{device_id}/telemetry".
+func (c *Client) Report(ctx context.Context, p *devices.Reading) error {
+	msg, err := EncodeReportPayload(p, c.codec)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, msg)
+}
+
+// Configure publishes the "configure" method payload on "devices/config".
+func (c *Client) Configure(ctx context.Context, p string) error {
+	msg, err := EncodeConfigurePayload(p, c.codec)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, msg)
+}
+
+// Reset publishes the "reset" method payload on "devices/reset".
+func (c *Client) Reset(ctx context.Context) error {
+	msg, err := EncodeResetPayload(c.codec)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, msg)
+}
+`
+
+const EncoderCode = `// EncodeReportPayload encodes the "report" method payload into a message.
+func EncodeReportPayload(p *devices.Reading, codec goamqtt.Codec) (*goamqtt.Message, error) {
+	topic, err := goamqtt.BuildTopic("devices/{device_id}/telemetry", map[string]string{
+		"device_id": p.DeviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	v, err := codec.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q message: %s", "devices/{device_id}/telemetry", err)
+	}
+	return &goamqtt.Message{
+		Topic:   topic,
+		Payload: v,
+		QoS:     0,
+	}, nil
+}
+
+// EncodeConfigurePayload encodes the "configure" method payload into a message.
+func EncodeConfigurePayload(p string, codec goamqtt.Codec) (*goamqtt.Message, error) {
+	v, err := codec.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q message: %s", "devices/config", err)
+	}
+	return &goamqtt.Message{
+		Topic:   "devices/config",
+		Payload: v,
+		QoS:     2,
+		Retain:  true,
+	}, nil
+}
+
+// EncodeResetPayload encodes the "reset" method payload into a message.
+func EncodeResetPayload(codec goamqtt.Codec) (*goamqtt.Message, error) {
+	return &goamqtt.Message{
+		Topic: "devices/reset",
+		QoS:   1,
+	}, nil
+}
+`
@@ -0,0 +1,33 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	mqtt "goa.design/plugins/v3/mqtt/dsl"
+)
+
+var DevicesDSL = func() {
+	var Reading = Type("Reading", func() {
+		Attribute("device_id", String)
+		Attribute("temperature", Float64)
+		Required("device_id")
+	})
+	Service("Devices", func() {
+		Method("report", func() {
+			Payload(Reading)
+			mqtt.Topic("devices/{device_id}/telemetry", func() {
+				mqtt.QoS(0)
+				mqtt.SharedGroup("ingesters")
+			})
+		})
+		Method("configure", func() {
+			Payload(String)
+			mqtt.Topic("devices/config", func() {
+				mqtt.QoS(2)
+				mqtt.Retain()
+			})
+		})
+		Method("reset", func() {
+			mqtt.Topic("devices/reset")
+		})
+	})
+}
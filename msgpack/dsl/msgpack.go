@@ -0,0 +1,35 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/msgpack/expr"
+
+	// Register code generators for the MessagePack plugin
+	_ "goa.design/plugins/v3/msgpack"
+)
+
+// MessagePack enables the application/msgpack media type on all the HTTP
+// endpoints of the API. The generated HTTP servers decode the MessagePack
+// request bodies and encode the responses with MessagePack when the first
+// media type of the request Accept header is application/msgpack. The
+// generated HTTP clients decode the MessagePack response bodies. The other
+// requests and responses keep using the default encoders.
+//
+// MessagePack must appear in an API expression.
+//
+// Example:
+//
+//    import msgpack "goa.design/plugins/v3/msgpack/dsl"
+//
+//    var _ = API("inventory", func() {
+//        msgpack.MessagePack()
+//    })
+//
+func MessagePack() {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Enabled = true
+}
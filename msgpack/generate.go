@@ -0,0 +1,77 @@
+package msgpack
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/msgpack/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "msgpack",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate makes the HTTP servers and clients support MessagePack bodies and
+// documents the media type in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !expr.Root.Enabled {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("msgpack", f) {
+			continue
+		}
+		switch filepath.Base(f.Path) {
+		case "server.go":
+			serverMsgPack(f)
+		case "client.go":
+			clientMsgPack(f)
+		default:
+			documentMsgPack(f)
+		}
+	}
+	return files, nil
+}
+
+// serverMsgPack wraps the request decoder and response encoder given to the
+// HTTP server constructor with their MessagePack counterparts.
+func serverMsgPack(f *codegen.File) {
+	for _, s := range f.Section("server-init") {
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/msgpack"})
+		s.Source = strings.Replace(s.Source, "return &{{ .ServerStruct }}{",
+			"dec = msgpack.WrapRequestDecoder(dec)\n\tenc = msgpack.WrapResponseEncoder(enc)\n\treturn &{{ .ServerStruct }}{", 1)
+	}
+}
+
+// clientMsgPack wraps the response decoder given to the HTTP client
+// constructor with its MessagePack counterpart.
+func clientMsgPack(f *codegen.File) {
+	for _, s := range f.Section("client-init") {
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/msgpack"})
+		s.Source = strings.Replace(s.Source, "return &{{ .ClientStruct }}{",
+			"dec = msgpack.WrapResponseDecoder(dec)\n\treturn &{{ .ClientStruct }}{", 1)
+	}
+}
+
+// documentMsgPack adds the MessagePack media type to the media types consumed
+// and produced by the API if f is an OpenAPI file.
+func documentMsgPack(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		spec.Consumes = walk.AppendMediaType(spec.Consumes, MediaType)
+		spec.Produces = walk.AppendMediaType(spec.Produces, MediaType)
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package msgpack_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/msgpack"
+	"goa.design/plugins/v3/msgpack/expr"
+	"goa.design/plugins/v3/msgpack/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Enabled = false
+	httpcodegen.RunHTTPDSL(t, testdata.InventoryDSL)
+	fs := append(httpcodegen.ServerFiles("", goaexpr.Root), httpcodegen.ClientFiles("", goaexpr.Root)...)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = msgpack.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections := map[string]string{
+		"server-init": testdata.ServerInitCode,
+		"client-init": testdata.ClientInitCode,
+	}
+	for _, f := range fs {
+		for name, expected := range sections {
+			for _, s := range f.Section(name) {
+				code := codegen.SectionCode(t, s)
+				if code != expected {
+					t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, expected))
+				}
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	for name, mts := range map[string][]string{"consumes": spec.Consumes, "produces": spec.Produces} {
+		var found bool
+		for _, mt := range mts {
+			if mt == msgpack.MediaType {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: %s not documented in %v", name, msgpack.MediaType, mts)
+		}
+	}
+}
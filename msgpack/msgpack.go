@@ -0,0 +1,75 @@
+package msgpack
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	mp "github.com/vmihailenco/msgpack/v5"
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/plugins/v3/encoding"
+)
+
+// MediaType is the MessagePack media type.
+const MediaType = "application/msgpack"
+
+// mediaTypes lists the media types recognized as MessagePack.
+var mediaTypes = map[string]bool{
+	MediaType:                 true,
+	"application/x-msgpack":   true,
+	"application/vnd.msgpack": true,
+}
+
+// Format is the MessagePack format: application/msgpack and its
+// application/x-msgpack and application/vnd.msgpack aliases.
+var Format = &encoding.Format{
+	MediaType: MediaType,
+	Match:     func(mt string) bool { return mediaTypes[mt] },
+	Codec:     encoding.NewCodec(NewEncoder, NewDecoder),
+}
+
+// WrapRequestDecoder returns a HTTP request decoder that decodes the bodies of
+// the requests whose Content-Type is MessagePack and uses dec for the other
+// requests.
+func WrapRequestDecoder(dec func(*http.Request) goahttp.Decoder) func(*http.Request) goahttp.Decoder {
+	return Format.WrapRequestDecoder(dec)
+}
+
+// WrapResponseEncoder returns a HTTP response encoder that encodes the
+// responses with MessagePack if the response content type set in the design
+// or the first media type of the request Accept header is MessagePack and uses
+// enc otherwise.
+func WrapResponseEncoder(enc func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter) goahttp.Encoder {
+	return Format.WrapResponseEncoder(enc)
+}
+
+// WrapResponseDecoder returns a HTTP response decoder that decodes the bodies
+// of the responses whose Content-Type is MessagePack and uses dec for the
+// other responses.
+func WrapResponseDecoder(dec func(*http.Response) goahttp.Decoder) func(*http.Response) goahttp.Decoder {
+	return Format.WrapResponseDecoder(dec)
+}
+
+// RequestEncoder is a HTTP request encoder that encodes the request bodies
+// with MessagePack. It also sets the Accept header so that the servers
+// generated with the plugin respond with MessagePack.
+func RequestEncoder(r *http.Request) goahttp.Encoder {
+	return Format.RequestEncoder(r)
+}
+
+// NewEncoder returns a MessagePack encoder writing to w. The encoder uses the
+// names of the json struct tags of the generated types so that the fields
+// are named as in the design.
+func NewEncoder(w io.Writer) goahttp.Encoder {
+	enc := mp.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc
+}
+
+// NewDecoder returns a MessagePack decoder reading from r which uses the
+// names of the json struct tags of the generated types.
+func NewDecoder(r io.Reader) goahttp.Decoder {
+	dec := mp.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec
+}
@@ -0,0 +1,81 @@
+package msgpack
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type item struct {
+	SKU      *string `json:"sku,omitempty"`
+	Quantity *int    `json:"quantity,omitempty"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	sku, qty := "A-1", 3
+	r := httptest.NewRequest("POST", "/items", nil)
+	if err := RequestEncoder(r).Encode(&item{SKU: &sku, Quantity: &qty}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded item
+	if err := WrapRequestDecoder(goahttp.RequestDecoder)(r).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SKU == nil || *decoded.SKU != sku || decoded.Quantity == nil || *decoded.Quantity != qty {
+		t.Errorf("got %+v, expected SKU %q and quantity %d", decoded, sku, qty)
+	}
+
+	ctx := context.WithValue(context.Background(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+	w := httptest.NewRecorder()
+	if err := WrapResponseEncoder(goahttp.ResponseEncoder)(ctx, w).Encode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != MediaType {
+		t.Errorf("got Content-Type %q, expected %q", ct, MediaType)
+	}
+	resp := w.Result()
+	var res item
+	if err := WrapResponseDecoder(goahttp.ResponseDecoder)(resp).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.SKU == nil || *res.SKU != sku {
+		t.Errorf("got response %+v, expected SKU %q", res, sku)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	cases := map[string]struct {
+		Accept      string
+		ContentType string
+		Expected    string
+	}{
+		"no accept":           {"", "", "application/json"},
+		"json first":          {"application/json, application/msgpack", "", "application/json"},
+		"msgpack first":       {"application/x-msgpack;q=1, application/json", "", "application/x-msgpack"},
+		"design content type": {"application/json", "application/msgpack", "application/msgpack"},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), goahttp.AcceptTypeKey, tc.Accept)
+			if tc.ContentType != "" {
+				ctx = context.WithValue(ctx, goahttp.ContentTypeKey, tc.ContentType)
+			}
+			w := httptest.NewRecorder()
+			if err := WrapResponseEncoder(goahttp.ResponseEncoder)(ctx, w).Encode("ok"); err != nil {
+				t.Fatal(err)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tc.Expected {
+				t.Errorf("got Content-Type %q, expected %q", ct, tc.Expected)
+			}
+		})
+	}
+
+	r := httptest.NewRequest("POST", "/items", bytes.NewBufferString(`"json"`))
+	var s string
+	if err := WrapRequestDecoder(goahttp.RequestDecoder)(r).Decode(&s); err != nil || s != "json" {
+		t.Errorf("got %q (%v), expected JSON body to be decoded", s, err)
+	}
+}
@@ -0,0 +1,41 @@
+package testdata
+
+var ServerInitCode = `// New instantiates HTTP handlers for all the Inventory service endpoints.
+func New(
+	e *inventory.Endpoints,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) *Server {
+	dec = msgpack.WrapRequestDecoder(dec)
+	enc = msgpack.WrapResponseEncoder(enc)
+	return &Server{
+		Mounts: []*MountPoint{
+			{"Add", "POST", "/items"},
+		},
+		Add: NewAddHandler(e.Add, mux, dec, enc, eh),
+	}
+}
+`
+
+var ClientInitCode = `// NewClient instantiates HTTP clients for all the Inventory service servers.
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+) *Client {
+	dec = msgpack.WrapResponseDecoder(dec)
+	return &Client{
+		AddDoer:             doer,
+		RestoreResponseBody: restoreBody,
+		scheme:              scheme,
+		host:                host,
+		decoder:             dec,
+		encoder:             enc,
+	}
+}
+`
@@ -0,0 +1,24 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	msgpack "goa.design/plugins/v3/msgpack/dsl"
+)
+
+var InventoryDSL = func() {
+	API("inventory", func() {
+		msgpack.MessagePack()
+	})
+	Service("Inventory", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Attribute("sku", String)
+				Attribute("quantity", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/items")
+			})
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/nginx/expr"
+
+	// Register code generators for the nginx plugin
+	_ "goa.design/plugins/v3/nginx"
+)
+
+// MaxBodySize sets the maximum size of the request bodies using the nginx
+// size syntax (e.g. "10m"). Method level sizes override service level sizes
+// which override the API level size.
+//
+// MaxBodySize may appear in an API, Service or Method expression.
+//
+// Example:
+//
+//    import nginx "goa.design/plugins/v3/nginx/dsl"
+//
+//    var _ = Service("storage", func() {
+//        nginx.MaxBodySize("1m")
+//        Method("upload", func() {
+//            nginx.MaxBodySize("100m")
+//        })
+//    })
+//
+func MaxBodySize(size string) {
+	var parent eval.Expression
+	switch e := eval.Current().(type) {
+	case *goaexpr.APIExpr, *goaexpr.ServiceExpr, *goaexpr.MethodExpr:
+		parent = e
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, b := range expr.Root.BodySizes {
+		if b.Parent == parent {
+			eval.ReportError("maximum body size defined twice")
+			return
+		}
+	}
+	expr.Root.BodySizes = append(expr.Root.BodySizes, &expr.BodySizeExpr{Size: size, Parent: parent})
+}
@@ -0,0 +1,42 @@
+package expr
+
+import (
+	"regexp"
+
+	"goa.design/goa/v3/eval"
+)
+
+// sizeRegex matches the nginx size syntax.
+var sizeRegex = regexp.MustCompile(`^[0-9]+[kKmMgG]?$`)
+
+type (
+	// BodySizeExpr describes the maximum request body size of the API, a
+	// service or a method.
+	BodySizeExpr struct {
+		// Size is the maximum size using the nginx syntax (e.g. "10m").
+		Size string
+		// Parent is the API, service or method expression.
+		Parent eval.Expression
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (b *BodySizeExpr) EvalName() string {
+	var suffix string
+	if b.Parent != nil {
+		suffix = " of " + b.Parent.EvalName()
+	}
+	return "nginx maximum body size" + suffix
+}
+
+// Validate ensures the body size expression is valid.
+func (b *BodySizeExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if !sizeRegex.MatchString(b.Size) {
+		verr.Add(b, "invalid size %q, must be a number optionally followed by k, m or g", b.Size)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
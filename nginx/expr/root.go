@@ -0,0 +1,75 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the nginx properties defined in the design.
+	RootExpr struct {
+		// BodySizes lists the maximum request body sizes in the order
+		// they appear in the design.
+		BodySizes []*BodySizeExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "nginx plugin"
+}
+
+// WalkSets iterates over the maximum body sizes.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	bexps := make(eval.ExpressionSet, len(r.BodySizes))
+	for i, b := range r.BodySizes {
+		bexps[i] = b
+	}
+	walk(bexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/nginx/dsl"}
+}
+
+// BodySize returns the maximum request body size of the given method. Method
+// level sizes override service level sizes which override the API level
+// size. BodySize returns an empty string if the method body size is not
+// limited in the design.
+func (r *RootExpr) BodySize(m *expr.MethodExpr) string {
+	var svc, api string
+	for _, b := range r.BodySizes {
+		switch p := b.Parent.(type) {
+		case *expr.MethodExpr:
+			if p == m {
+				return b.Size
+			}
+		case *expr.ServiceExpr:
+			if p == m.Service {
+				svc = b.Size
+			}
+		case *expr.APIExpr:
+			api = b.Size
+		}
+	}
+	if svc != "" {
+		return svc
+	}
+	return api
+}
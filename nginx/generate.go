@@ -0,0 +1,254 @@
+package nginx
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/nginx/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// configData contains the data necessary to render the nginx
+	// configuration.
+	configData struct {
+		// ServerNames lists the host names of the HTTP servers.
+		ServerNames []string
+		// Upstreams lists the upstreams, one per service.
+		Upstreams []*upstreamData
+		// Locations lists the locations in the order the endpoints
+		// appear in the design.
+		Locations []*locationData
+	}
+
+	// upstreamData describes the upstream of a service.
+	upstreamData struct {
+		// Name is the upstream name.
+		Name string
+		// Server is the address of the upstream server.
+		Server string
+	}
+
+	// locationData describes a location.
+	locationData struct {
+		// Modifier is "=" for exact matches and "~" for regular
+		// expression matches.
+		Modifier string
+		// Path is the location path or regular expression.
+		Path string
+		// Methods lists the HTTP methods allowed by the location.
+		Methods []string
+		// MaxBodySize is the maximum request body size, empty if not
+		// limited.
+		MaxBodySize string
+		// ProxyPass is the URL of the upstream receiving the requests.
+		ProxyPass string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "nginx",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the nginx configuration snippet which proxies the
+// requests made to the HTTP endpoints.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			if r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+				continue
+			}
+			section := &codegen.SectionTemplate{
+				Name:   "nginx-config",
+				Source: configT,
+				Data:   buildConfig(r),
+			}
+			files = append(files, &codegen.File{
+				Path:             filepath.Join(codegen.Gendir, "nginx", "nginx.conf"),
+				SectionTemplates: []*codegen.SectionTemplate{section},
+			})
+		}
+	}
+	return files, nil
+}
+
+// buildConfig computes the nginx configuration from the design.
+func buildConfig(r *goaexpr.RootExpr) *configData {
+	data := &configData{}
+	locs := make(map[string]*locationData)
+	for _, svc := range r.API.HTTP.Services {
+		if !config.Enabled("nginx", svc.Name()) {
+			continue
+		}
+		upstream := codegen.SnakeCase(svc.Name())
+		scheme, addr := server(r, svc.Name(), data)
+		data.Upstreams = append(data.Upstreams, &upstreamData{Name: upstream, Server: addr})
+		for _, e := range svc.HTTPEndpoints {
+			size := expr.Root.BodySize(e.MethodExpr)
+			for _, rt := range e.Routes {
+				for _, p := range rt.FullPaths() {
+					loc, ok := locs[p]
+					if !ok {
+						loc = &locationData{Modifier: "=", Path: p, ProxyPass: scheme + "://" + upstream}
+						if goaexpr.HTTPWildcardRegex.MatchString(p) {
+							loc.Modifier, loc.Path = "~", pathRegex(p)
+						}
+						locs[p] = loc
+						data.Locations = append(data.Locations, loc)
+					}
+					loc.Methods = appendUnique(loc.Methods, rt.Method)
+					if loc.MaxBodySize == "" || sizeBytes(size) > sizeBytes(loc.MaxBodySize) {
+						loc.MaxBodySize = size
+					}
+				}
+			}
+		}
+	}
+	if len(data.ServerNames) == 0 {
+		data.ServerNames = []string{"_"}
+	}
+	return data
+}
+
+// server returns the scheme and address of the first HTTP URI of the servers
+// which expose the given service and records the host names of the URIs in data.
+// The host variables are replaced with their default values.
+func server(r *goaexpr.RootExpr, svc string, data *configData) (string, string) {
+	scheme, addr := "http", ""
+	for _, s := range r.API.Servers {
+		var found bool
+		for _, name := range s.Services {
+			if name == svc {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		for _, h := range s.Hosts {
+			for _, uri := range h.URIs {
+				u := string(uri)
+				if !strings.HasPrefix(u, "http") {
+					continue
+				}
+				for _, v := range *goaexpr.AsObject(h.Attribute().Type) {
+					if v.Attribute.DefaultValue != nil {
+						u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+					}
+				}
+				parsed, err := url.Parse(u)
+				if err != nil {
+					continue
+				}
+				data.ServerNames = appendUnique(data.ServerNames, parsed.Hostname())
+				if addr == "" {
+					port := parsed.Port()
+					if port == "" {
+						port = "80"
+						if parsed.Scheme == "https" {
+							port = "443"
+						}
+					}
+					scheme, addr = parsed.Scheme, parsed.Hostname()+":"+port
+				}
+			}
+		}
+	}
+	if addr == "" {
+		addr = "localhost:80"
+	}
+	return scheme, addr
+}
+
+// pathRegex returns the anchored regular expression matching the given path
+// which contains wildcards.
+func pathRegex(p string) string {
+	var (
+		re   strings.Builder
+		last int
+	)
+	re.WriteString("^")
+	for _, m := range goaexpr.HTTPWildcardRegex.FindAllStringIndex(p, -1) {
+		re.WriteString(regexp.QuoteMeta(p[last:m[0]]))
+		if strings.HasPrefix(p[m[0]:], "/{*") {
+			re.WriteString("/.*")
+		} else {
+			re.WriteString("/[^/]+")
+		}
+		last = m[1]
+	}
+	re.WriteString(regexp.QuoteMeta(p[last:]))
+	re.WriteString("$")
+	return re.String()
+}
+
+// sizeBytes returns the number of bytes of the given nginx size, 0 if size is
+// empty.
+func sizeBytes(size string) int64 {
+	if size == "" {
+		return 0
+	}
+	mult := int64(1)
+	switch size[len(size)-1] {
+	case 'k', 'K':
+		mult = 1 << 10
+	case 'm', 'M':
+		mult = 1 << 20
+	case 'g', 'G':
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		size = size[:len(size)-1]
+	}
+	n, _ := strconv.ParseInt(size, 10, 64)
+	return n * mult
+}
+
+// appendUnique appends v to vs if vs does not already contain it.
+func appendUnique(vs []string, v string) []string {
+	for _, existing := range vs {
+		if existing == v {
+			return vs
+		}
+	}
+	return append(vs, v)
+}
+
+// input: configData
+const configT = `{{ range .Upstreams }}upstream {{ .Name }} {
+    server {{ .Server }};
+}
+
+{{ end -}}
+server {
+    listen 80;
+    server_name {{ range $i, $n := .ServerNames }}{{ if $i }} {{ end }}{{ $n }}{{ end }};
+{{- range .Locations }}
+
+    location {{ .Modifier }} {{ .Path }} {
+        limit_except {{ range $i, $m := .Methods }}{{ if $i }} {{ end }}{{ $m }}{{ end }} {
+            deny all;
+        }
+	{{- if .MaxBodySize }}
+        client_max_body_size {{ .MaxBodySize }};
+	{{- end }}
+        proxy_pass {{ .ProxyPass }};
+    }
+{{- end }}
+}
+`
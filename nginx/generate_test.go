@@ -0,0 +1,35 @@
+package nginx_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/nginx"
+	"goa.design/plugins/v3/nginx/expr"
+	"goa.design/plugins/v3/nginx/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.BodySizes = nil
+	root := codegen.RunDSLWithFunc(t, testdata.ConfigDSL, func() {
+		eval.Register(expr.Root)
+	})
+	fs, err := nginx.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	for _, f := range fs {
+		var buf bytes.Buffer
+		if err := f.SectionTemplates[0].Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		plugintest.Golden(t, filepath.Base(f.Path), buf.String())
+	}
+}
@@ -0,0 +1,60 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	nginx "goa.design/plugins/v3/nginx/dsl"
+)
+
+var ConfigDSL = func() {
+	API("storage", func() {
+		Server("storage", func() {
+			Host("production", func() {
+				URI("https://{region}.storage.example.com")
+				URI("http://storage.example.com:8080")
+				Variable("region", String, func() {
+					Default("eu")
+				})
+			})
+		})
+		nginx.MaxBodySize("1m")
+	})
+	Service("files", func() {
+		nginx.MaxBodySize("2m")
+		HTTP(func() {
+			Path("/files")
+		})
+		Method("list", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("upload", func() {
+			nginx.MaxBodySize("100m")
+			Payload(func() {
+				Attribute("content", Bytes)
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+		Method("download", func() {
+			Payload(func() {
+				Attribute("path", String)
+			})
+			HTTP(func() {
+				GET("/{*path}")
+			})
+		})
+	})
+	Service("users", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			HTTP(func() {
+				GET("/users/{id}")
+				PUT("/users/{id}")
+			})
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package openapispec
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "openapispec",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the gen/http/openapi package which embeds the OpenAPI
+// specification of the API so that the services can serve or validate
+// against the specification without reading the generated files at runtime.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("openapispec", "") {
+		return files, nil
+	}
+	for _, f := range files {
+		if filepath.Base(f.Path) != "openapi.json" {
+			continue
+		}
+		var spec *openapi.V2
+		walk.Specs(f, func(s *openapi.V2) error {
+			spec = s
+			return walk.Stop
+		})
+		if spec != nil {
+			return append(files, specFile(spec)), nil
+		}
+	}
+	return files, nil
+}
+
+// specFile returns the file embedding the given specification. The
+// specification is serialized when the file is rendered so that it includes
+// the changes made by the other plugins.
+func specFile(spec *openapi.V2) *codegen.File {
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "http", "openapi", "spec.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header("OpenAPI specification", "openapi", []*codegen.ImportSpec{
+				{Path: "net/http"},
+				{Path: "strings"},
+			}),
+			{
+				Name:    "openapispec",
+				Source:  specT,
+				Data:    spec,
+				FuncMap: map[string]interface{}{"toJSON": toJSON, "toYAML": toYAML},
+			},
+		},
+	}
+}
+
+// toJSON returns the Go string literal of the JSON representation of spec.
+func toJSON(spec *openapi.V2) string {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		panic("openapispec: " + err.Error()) // bug
+	}
+	return strconv.Quote(string(b))
+}
+
+// toYAML returns the Go string literal of the YAML representation of spec.
+func toYAML(spec *openapi.V2) string {
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		panic("openapispec: " + err.Error()) // bug
+	}
+	return strconv.Quote(string(b))
+}
+
+// input: *openapi.V2
+const specT = `// JSON is the OpenAPI specification of the API in JSON.
+const JSON = {{ toJSON . }}
+
+// YAML is the OpenAPI specification of the API in YAML.
+const YAML = {{ toYAML . }}
+
+// Handler returns a HTTP handler serving the OpenAPI specification in YAML if
+// the request path ends with ".yaml" or ".yml" and in JSON otherwise.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".yaml") || strings.HasSuffix(r.URL.Path, ".yml") {
+			w.Header().Set("Content-Type", "application/x-yaml")
+			w.Write([]byte(YAML))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(JSON))
+	})
+}
+`
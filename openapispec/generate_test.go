@@ -0,0 +1,39 @@
+package openapispec_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/openapispec"
+	"goa.design/plugins/v3/openapispec/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	root := plugintest.RunDSL(t, testdata.CalcDSL)
+	files, err := httpcodegen.OpenAPIFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := openapispec.Generate("", []eval.Root{root}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The specification is serialized when the file is rendered.
+	plugintest.Spec(t, fs).Info.Description = "Calculator service"
+	f := plugintest.File(t, fs, "gen/http/openapi/spec.go")
+	plugintest.Golden(t, "spec.golden", plugintest.Render(t, f))
+}
+
+func TestGenerateNoHTTP(t *testing.T) {
+	root := plugintest.RunDSL(t, testdata.NoHTTPDSL)
+	fs, err := openapispec.Generate("", []eval.Root{root}, []*codegen.File{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
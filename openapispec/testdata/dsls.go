@@ -0,0 +1,32 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CalcDSL = func() {
+	API("calc", func() {
+		Title("Calculator")
+		Version("1.0")
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
+
+var NoHTTPDSL = func() {
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(Int)
+		})
+	})
+}
@@ -0,0 +1,126 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/pagination/expr"
+
+	// Register code generators for the pagination plugin
+	_ "goa.design/plugins/v3/pagination"
+)
+
+const (
+	// Cursor pages through the results using an opaque cursor returned
+	// with each page.
+	Cursor = expr.CursorStyle
+	// Offset pages through the results using the index of the first item
+	// of the page.
+	Offset = expr.OffsetStyle
+)
+
+// Paginate makes the method return its results one page at a time. The
+// plugin injects the pagination attributes in the method payload and result:
+//
+//   - With the Cursor style the payload gets the "cursor" and "limit"
+//     attributes and the result the "next_cursor" attribute.
+//   - With the Offset style the payload gets the "offset" and "limit"
+//     attributes and the result the "total" attribute.
+//
+// The payload attributes are mapped to query string parameters in the HTTP
+// endpoints and the HTTP responses contain a Link header referencing the next
+// page.
+//
+// Paginate must appear in a Method expression. The payload and result of the
+// method must be objects, note that attributes are added to the user types
+// used to define them.
+//
+// Paginate accepts the pagination style as first argument and an optional DSL
+// function as second argument.
+//
+// Example:
+//
+//    import pagination "goa.design/plugins/v3/pagination/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        Method("list", func() {
+//            pagination.Paginate(pagination.Cursor, func() {
+//                pagination.DefaultPageSize(50)
+//                pagination.MaxPageSize(500)
+//            })
+//            Payload(ListFilter)
+//            Result(func() {
+//                Attribute("items", ArrayOf(Item))
+//            })
+//            HTTP(func() {
+//                GET("/items")
+//            })
+//        })
+//    })
+//
+func Paginate(style expr.Style, args ...interface{}) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(args) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	p := &expr.PaginationExpr{
+		Style:           style,
+		DefaultPageSize: expr.DefaultPageSize,
+		MaxPageSize:     expr.DefaultMaxPageSize,
+		Method:          m,
+	}
+	if len(args) == 1 {
+		dsl, ok := args[0].(func())
+		if !ok {
+			eval.InvalidArgError("function", args[0])
+			return
+		}
+		if !eval.Execute(dsl, p) {
+			return
+		}
+	}
+	expr.Root.Paginations = append(expr.Root.Paginations, p)
+}
+
+// DefaultPageSize sets the page size used when the request does not specify
+// one. The default is 20.
+//
+// DefaultPageSize must appear in a Paginate expression.
+//
+// Example:
+//
+//     Paginate(Cursor, func() {
+//         DefaultPageSize(50)
+//     })
+//
+func DefaultPageSize(size uint) {
+	switch p := eval.Current().(type) {
+	case *expr.PaginationExpr:
+		p.DefaultPageSize = size
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// MaxPageSize sets the maximum page size. The default is 100.
+//
+// MaxPageSize must appear in a Paginate expression.
+//
+// Example:
+//
+//     Paginate(Offset, func() {
+//         MaxPageSize(500)
+//     })
+//
+func MaxPageSize(size uint) {
+	switch p := eval.Current().(type) {
+	case *expr.PaginationExpr:
+		p.MaxPageSize = size
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,182 @@
+package expr
+
+import (
+	"fmt"
+
+	goadsl "goa.design/goa/v3/dsl"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Style is the pagination style.
+type Style string
+
+const (
+	// CursorStyle pages through the results using an opaque cursor
+	// returned with each page.
+	CursorStyle Style = "cursor"
+	// OffsetStyle pages through the results using the index of the first
+	// item of the page.
+	OffsetStyle Style = "offset"
+)
+
+const (
+	// CursorAttribute is the name of the payload attribute holding the
+	// cursor of the requested page.
+	CursorAttribute = "cursor"
+	// NextCursorAttribute is the name of the result attribute holding the
+	// cursor of the next page.
+	NextCursorAttribute = "next_cursor"
+	// OffsetAttribute is the name of the payload attribute holding the
+	// index of the first item of the requested page.
+	OffsetAttribute = "offset"
+	// TotalAttribute is the name of the result attribute holding the total
+	// number of items.
+	TotalAttribute = "total"
+	// LimitAttribute is the name of the payload attribute holding the
+	// requested page size.
+	LimitAttribute = "limit"
+
+	// DefaultPageSize is the default page size.
+	DefaultPageSize = 20
+	// DefaultMaxPageSize is the default maximum page size.
+	DefaultMaxPageSize = 100
+)
+
+type (
+	// PaginationExpr describes the pagination of a list method.
+	PaginationExpr struct {
+		// Style is the pagination style.
+		Style Style
+		// DefaultPageSize is the page size used when the request does
+		// not specify one.
+		DefaultPageSize uint
+		// MaxPageSize is the maximum page size.
+		MaxPageSize uint
+		// Method is the paginated method.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PaginationExpr) EvalName() string {
+	return fmt.Sprintf("Paginate of %s", p.Method.EvalName())
+}
+
+// Prepare injects the pagination attributes in the method payload and result
+// and maps the payload attributes to HTTP query string parameters.
+func (p *PaginationExpr) Prepare() {
+	emptyPayload := p.Method.Payload.Type == expr.Empty
+	if emptyPayload {
+		p.Method.Payload = &expr.AttributeExpr{Type: &expr.Object{}}
+	}
+	if p.Method.Result.Type == expr.Empty {
+		p.Method.Result = &expr.AttributeExpr{Type: &expr.Object{}}
+	}
+	if !expr.IsObject(p.Method.Payload.Type) || !expr.IsObject(p.Method.Result.Type) {
+		return // reported by Validate
+	}
+	payload := attribute(p.Method.Payload)
+	result := attribute(p.Method.Result)
+	params := []string{LimitAttribute}
+	switch p.Style {
+	case CursorStyle:
+		params = append(params, CursorAttribute)
+		eval.Execute(func() {
+			goadsl.Attribute(CursorAttribute, goadsl.String, "Cursor of the requested page as returned by the previous page, omit to request the first page.")
+		}, payload)
+		eval.Execute(func() {
+			goadsl.Attribute(NextCursorAttribute, goadsl.String, "Cursor of the next page, not set if this is the last page.")
+		}, result)
+		addToViews(p.Method.Result, NextCursorAttribute)
+	case OffsetStyle:
+		params = append(params, OffsetAttribute)
+		eval.Execute(func() {
+			goadsl.Attribute(OffsetAttribute, goadsl.Int, "Index of the first item of the requested page.", func() {
+				goadsl.Minimum(0)
+				goadsl.Default(0)
+			})
+		}, payload)
+		eval.Execute(func() {
+			goadsl.Attribute(TotalAttribute, goadsl.Int, "Total number of items.")
+		}, result)
+		addToViews(p.Method.Result, TotalAttribute)
+	}
+	eval.Execute(func() {
+		goadsl.Attribute(LimitAttribute, goadsl.Int, "Maximum number of items in the page.", func() {
+			goadsl.Minimum(1)
+			goadsl.Maximum(int(p.MaxPageSize))
+			goadsl.Default(int(p.DefaultPageSize))
+		})
+	}, payload)
+
+	for _, svc := range expr.Root.API.HTTP.Services {
+		for _, e := range svc.HTTPEndpoints {
+			if e.MethodExpr != p.Method {
+				continue
+			}
+			for _, param := range params {
+				param := param
+				eval.Execute(func() { goadsl.Param(param) }, e)
+			}
+			// goa defaults the response status to 204 for methods without
+			// payload, the endpoint now has one.
+			if emptyPayload && len(e.Responses) == 1 && e.Responses[0].StatusCode == expr.StatusNoContent {
+				e.Responses[0].StatusCode = expr.StatusOK
+			}
+		}
+	}
+}
+
+// Validate ensures the pagination expression is valid.
+func (p *PaginationExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if p.Style != CursorStyle && p.Style != OffsetStyle {
+		verr.Add(p, "invalid pagination style %q, must be one of %q or %q", p.Style, CursorStyle, OffsetStyle)
+	}
+	if p.DefaultPageSize == 0 {
+		verr.Add(p, "default page size must be greater than 0")
+	}
+	if p.DefaultPageSize > p.MaxPageSize {
+		verr.Add(p, "default page size %d is greater than maximum page size %d", p.DefaultPageSize, p.MaxPageSize)
+	}
+	if !expr.IsObject(p.Method.Payload.Type) {
+		verr.Add(p, "payload of paginated method must be an object")
+	}
+	if !expr.IsObject(p.Method.Result.Type) {
+		verr.Add(p, "result of paginated method must be an object, use an attribute to hold the list of items")
+	}
+	if p.Method.IsStreaming() {
+		verr.Add(p, "streaming methods cannot be paginated")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// attribute returns the attribute that holds the fields of the given object
+// attribute. The attribute of user types is shared by all the methods that
+// use the type.
+func attribute(att *expr.AttributeExpr) *expr.AttributeExpr {
+	if ut, ok := att.Type.(expr.UserType); ok {
+		return ut.Attribute()
+	}
+	return att
+}
+
+// addToViews adds the attribute with the given name to all the views of the
+// result type so that it is rendered regardless of the view.
+func addToViews(att *expr.AttributeExpr, name string) {
+	rt, ok := att.Type.(*expr.ResultTypeExpr)
+	if !ok {
+		return
+	}
+	for _, v := range rt.Views {
+		obj := expr.AsObject(v.Type)
+		if obj == nil || obj.Attribute(name) != nil {
+			continue
+		}
+		obj.Set(name, rt.Attribute().Find(name))
+	}
+}
@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the paginated methods defined in the design.
+	RootExpr struct {
+		// Paginations lists the pagination definitions in the order they
+		// appear in the design.
+		Paginations []*PaginationExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "pagination plugin"
+}
+
+// WalkSets iterates over the pagination definitions.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	pexps := make(eval.ExpressionSet, len(r.Paginations))
+	for i, p := range r.Paginations {
+		pexps[i] = p
+	}
+	walk(pexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/pagination/dsl"}
+}
+
+// Pagination returns the pagination definition of the given method, nil if
+// the method is not paginated.
+func (r *RootExpr) Pagination(svc, method string) *PaginationExpr {
+	for _, p := range r.Paginations {
+		if p.Method.Service.Name == svc && p.Method.Name == method {
+			return p
+		}
+	}
+	return nil
+}
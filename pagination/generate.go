@@ -0,0 +1,284 @@
+package pagination
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/pagination/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "pagination",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the pagination helper package, makes the HTTP servers of
+// the paginated endpoints set the Link response header and documents the
+// header in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Paginations) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("pagination", f) {
+			continue
+		}
+		paginateServer(genpkg, f)
+		documentPagination(f)
+	}
+	return append(files, paginationFile()), nil
+}
+
+// paginationFile returns the file implementing the pagination helpers.
+func paginationFile() *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "pagination", "pagination.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Pagination helpers", "pagination", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/base64"},
+			{Path: "encoding/json"},
+			{Path: "fmt"},
+			{Path: "net/http"},
+			{Path: "net/url"},
+			{Path: "strconv"},
+			{Path: "strings"},
+		}),
+		{Name: "pagination-page", Source: pageT},
+		{Name: "pagination-cursor", Source: cursorT},
+		{Name: "pagination-link", Source: linkT},
+	}
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// paginateServer modifies the HTTP server handlers and response encoders of
+// the paginated endpoints so that the responses include the Link header.
+func paginateServer(genpkg string, f *codegen.File) {
+	for _, s := range f.SectionTemplates {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok {
+			continue
+		}
+		p := expr.Root.Pagination(ed.ServiceName, ed.Method.Name)
+		if p == nil {
+			continue
+		}
+		switch s.Name {
+		case "server-handler-init":
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: path.Join(genpkg, "pagination")})
+			s.Source = strings.Replace(s.Source,
+				`ctx = context.WithValue(ctx, goa.ServiceKey, {{ printf "%q" .ServiceName }})`,
+				`ctx = context.WithValue(ctx, goa.ServiceKey, {{ printf "%q" .ServiceName }})
+		ctx = pagination.WithRequestURL(ctx, r.URL)`, 1)
+		case "response-encoder":
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: path.Join(genpkg, "pagination")})
+			var link string
+			switch p.Style {
+			case expr.CursorStyle:
+				link = fmt.Sprintf("pagination.SetCursorLink(ctx, w, res.{{ if .Method.ViewedResult }}Projected.{{ end }}%s)",
+					codegen.Goify(expr.NextCursorAttribute, true))
+			case expr.OffsetStyle:
+				link = fmt.Sprintf("pagination.SetOffsetLinks(ctx, w, res.{{ if .Method.ViewedResult }}Projected.{{ end }}%s, %d)",
+					codegen.Goify(expr.TotalAttribute, true), p.DefaultPageSize)
+			}
+			s.Source = strings.Replace(s.Source, "{{- range .Result.Responses }}",
+				link+"\n\t\t{{- range .Result.Responses }}", 1)
+		}
+	}
+}
+
+// documentPagination documents the Link response header of the paginated
+// operations if f is an OpenAPI file.
+func documentPagination(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("pagination", op) || !isPaginated(op) {
+				return nil
+			}
+			for code, resp := range op.Responses {
+				if !strings.HasPrefix(code, "2") {
+					continue
+				}
+				if resp.Headers == nil {
+					resp.Headers = make(map[string]*openapi.Header)
+				}
+				resp.Headers["Link"] = &openapi.Header{
+					Description: "Links to the adjacent pages as defined by RFC 8288.",
+					Type:        "string",
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// isPaginated returns true if the operation corresponds to a paginated
+// method.
+func isPaginated(op *openapi.Operation) bool {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	for _, p := range expr.Root.Paginations {
+		if m != nil && p.Method == m {
+			return true
+		}
+	}
+	return false
+}
+
+// input: none
+const pageT = `// Page is a page of items returned by a paginated method.
+type Page[T any] struct {
+	// Items lists the items of the page.
+	Items []T
+	// NextCursor is the cursor of the next page, empty if this is the last
+	// page. It is only set with cursor pagination.
+	NextCursor string
+	// Total is the total number of items. It is only set with offset
+	// pagination.
+	Total int
+}
+
+// NewCursorPage returns the page built from items, which should be loaded
+// with a limit of one more than the page size so that the existence of a next
+// page can be detected. key returns the value encoded in the cursor of the
+// next page given the last item of the page, typically the item sort key.
+func NewCursorPage[T any](items []T, limit int, key func(T) interface{}) (*Page[T], error) {
+	if len(items) <= limit {
+		return &Page[T]{Items: items}, nil
+	}
+	items = items[:limit]
+	cursor, err := EncodeCursor(key(items[len(items)-1]))
+	if err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: items, NextCursor: cursor}, nil
+}
+
+// NewOffsetPage returns the page built from items given the total number of
+// items.
+func NewOffsetPage[T any](items []T, total int) *Page[T] {
+	return &Page[T]{Items: items, Total: total}
+}
+
+// Next returns the cursor of the next page suitable to initialize the
+// "next_cursor" result attribute, nil if this is the last page.
+func (p *Page[T]) Next() *string {
+	if p.NextCursor == "" {
+		return nil
+	}
+	return &p.NextCursor
+}
+`
+
+// input: none
+const cursorT = `// EncodeCursor returns the opaque cursor encoding v.
+func EncodeCursor(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %s", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes the cursor into v. v must be a pointer to a value of
+// the type given to EncodeCursor.
+func DecodeCursor(cursor string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("invalid cursor: %s", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("invalid cursor: %s", err)
+	}
+	return nil
+}
+`
+
+// input: none
+const linkT = `// ctxKey is the private type used to store values in the context.
+type ctxKey int
+
+// requestURLKey is the context key used to store the request URL.
+const requestURLKey ctxKey = iota + 1
+
+// WithRequestURL returns a context holding the URL of the request being
+// served. The URL is used to build the links to the adjacent pages.
+func WithRequestURL(ctx context.Context, u *url.URL) context.Context {
+	return context.WithValue(ctx, requestURLKey, u)
+}
+
+// SetCursorLink sets the Link header of the response to reference the next
+// page if next is not nil.
+func SetCursorLink(ctx context.Context, w http.ResponseWriter, next *string) {
+	if next == nil || *next == "" {
+		return
+	}
+	if u := pageURL(ctx, map[string]string{"cursor": *next}); u != "" {
+		w.Header().Set("Link", link(u, "next"))
+	}
+}
+
+// SetOffsetLinks sets the Link header of the response to reference the
+// previous and next pages given the total number of items. defaultLimit is
+// the page size used when the request does not specify one.
+func SetOffsetLinks(ctx context.Context, w http.ResponseWriter, total *int, defaultLimit int) {
+	u, ok := ctx.Value(requestURLKey).(*url.URL)
+	if !ok || total == nil {
+		return
+	}
+	q := u.Query()
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	var links []string
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link(pageURL(ctx, map[string]string{"offset": strconv.Itoa(prev), "limit": strconv.Itoa(limit)}), "prev"))
+	}
+	if offset+limit < *total {
+		links = append(links, link(pageURL(ctx, map[string]string{"offset": strconv.Itoa(offset + limit), "limit": strconv.Itoa(limit)}), "next"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageURL returns the request URL with the given query string parameters
+// overridden, empty if the context does not hold the request URL.
+func pageURL(ctx context.Context, params map[string]string) string {
+	u, ok := ctx.Value(requestURLKey).(*url.URL)
+	if !ok {
+		return ""
+	}
+	q := u.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	page := url.URL{Path: u.Path, RawQuery: q.Encode()}
+	return page.String()
+}
+
+// link returns the link header value referencing u with the given relation.
+func link(u, rel string) string {
+	return fmt.Sprintf("<%s>; rel=%q", u, rel)
+}
+`
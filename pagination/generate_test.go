@@ -0,0 +1,100 @@
+package pagination_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/pagination"
+	"goa.design/plugins/v3/pagination/expr"
+	"goa.design/plugins/v3/pagination/testdata"
+	"goa.design/plugins/v3/plugintest"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name        string
+		DSL         func()
+		HandlerCode string
+		EncoderCode string
+		Params      []string
+	}{
+		{"cursor", testdata.CursorDSL, testdata.CursorHandlerInitCode, testdata.CursorResponseEncoderCode, []string{"filter", "cursor", "limit"}},
+		{"offset", testdata.OffsetDSL, testdata.OffsetHandlerInitCode, testdata.OffsetResponseEncoderCode, []string{"offset", "limit"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := httpcodegen.ServerFiles("", goaexpr.Root)
+			ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err = pagination.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var found bool
+			for _, f := range fs {
+				switch filepath.Base(f.Path) {
+				case "server.go":
+					sections := f.Section("server-handler-init")
+					if len(sections) != 1 {
+						t.Fatalf("got %d handler init sections, expected 1", len(sections))
+					}
+					code := codegen.SectionCode(t, sections[0])
+					if code != c.HandlerCode {
+						t.Errorf("invalid handler init code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.HandlerCode))
+					}
+				case "encode_decode.go":
+					sections := f.Section("response-encoder")
+					if len(sections) != 1 {
+						t.Fatalf("got %d response encoder sections, expected 1", len(sections))
+					}
+					code := codegen.SectionCode(t, sections[0])
+					if code != c.EncoderCode {
+						t.Errorf("invalid response encoder code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.EncoderCode))
+					}
+				case "pagination.go":
+					found = true
+					for _, s := range f.SectionTemplates[1:] {
+						codegen.SectionCode(t, s)
+					}
+				}
+			}
+			if !found {
+				t.Fatal("pagination file not generated")
+			}
+			spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+			op := spec.Paths["/items"].(*openapi.Path).Get
+			if len(op.Parameters) != len(c.Params) {
+				t.Errorf("got %d parameters, expected %d", len(op.Parameters), len(c.Params))
+			}
+			for _, p := range c.Params {
+				var ok bool
+				for _, param := range op.Parameters {
+					if param.Name == p && param.In == "query" {
+						ok = true
+					}
+				}
+				if !ok {
+					t.Errorf("query parameter %q not found in OpenAPI spec", p)
+				}
+			}
+			if _, ok := op.Responses["200"].Headers["Link"]; !ok {
+				t.Error("Link header not found in OpenAPI spec")
+			}
+		})
+	}
+}
+
+// runDSL runs the given DSL with the pagination plugin root registered so
+// that the pagination attributes get injected in the design.
+func runDSL(t *testing.T, dsl func()) {
+	expr.Root.Paginations = nil
+	plugintest.RunDSL(t, dsl, expr.Root)
+}
@@ -0,0 +1,41 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	pagination "goa.design/plugins/v3/pagination/dsl"
+)
+
+var CursorDSL = func() {
+	Service("Catalog", func() {
+		Method("List", func() {
+			pagination.Paginate(pagination.Cursor)
+			Payload(func() {
+				Attribute("filter", String)
+			})
+			Result(func() {
+				Attribute("items", ArrayOf(String))
+			})
+			HTTP(func() {
+				GET("/items")
+				Param("filter")
+			})
+		})
+	})
+}
+
+var OffsetDSL = func() {
+	Service("Catalog", func() {
+		Method("List", func() {
+			pagination.Paginate(pagination.Offset, func() {
+				pagination.DefaultPageSize(50)
+				pagination.MaxPageSize(500)
+			})
+			Result(func() {
+				Attribute("items", ArrayOf(String))
+			})
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package plugintest
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/walk"
+)
+
+// Update is set with the -update test flag, Golden writes the golden files
+// instead of comparing them when it is set:
+//
+//    go test ./cors -update
+//
+var Update = flag.Bool("update", false, "update the golden files")
+
+// RunDSL resets the goa code generation data, registers the given plugin
+// design roots with the eval engine and runs the DSL. It returns the goa
+// design root. The test fails if the DSL does not run or validate.
+//
+// eval.Reset unregisters all the roots so the plugins whose DSL creates
+// expressions in their own root must give the root to RunDSL, e.g.:
+//
+//    plugintest.RunDSL(t, testdata.PaginatedDSL, expr.Root)
+//
+func RunDSL(t *testing.T, dsl func(), roots ...eval.Root) *expr.RootExpr {
+	t.Helper()
+	service.Services = make(service.ServicesData)
+	httpcodegen.HTTPServices = make(httpcodegen.ServicesData)
+	openapi.Definitions = make(map[string]*openapi.Schema)
+	return codegen.RunDSLWithFunc(t, dsl, func() {
+		for _, r := range roots {
+			eval.Register(r)
+		}
+	})
+}
+
+// File returns the file with the given path, the test fails if there is none.
+func File(t *testing.T, files []*codegen.File, path string) *codegen.File {
+	t.Helper()
+	for _, f := range files {
+		if filepath.ToSlash(f.Path) == path {
+			return f
+		}
+	}
+	t.Fatalf("file %q not generated", path)
+	return nil
+}
+
+// Render renders the given file in a temporary directory, running its
+// finalizer if any, and returns its content. Go files are formatted.
+func Render(t *testing.T, f *codegen.File) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "plugintest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path, err := f.Render(dir)
+	if err != nil {
+		t.Fatalf("%s: %s", f.Path, err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// SectionCode returns the formatted code of the sections of the file with the
+// given name separated by new lines. The test fails if there is none.
+func SectionCode(t *testing.T, f *codegen.File, section string) string {
+	t.Helper()
+	sections := f.Section(section)
+	if len(sections) == 0 {
+		t.Fatalf("%s: no %q section", f.Path, section)
+	}
+	return codegen.SectionsCode(t, sections)
+}
+
+// Golden compares got with the content of the golden file with the given name
+// in the testdata directory of the package being tested and reports the
+// differences. Golden writes the file instead when the tests run with the
+// -update flag.
+func Golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s, run the tests with -update to create the golden file", err)
+	}
+	if exp := string(b); got != exp {
+		t.Errorf("invalid content for %s: got\n%s\ngot vs. expected:\n%s", path, got, codegen.Diff(t, got, exp))
+	}
+}
+
+// Spec returns the OpenAPI specification rendered by the openapi.json file of
+// the given files. The test fails if there is none.
+func Spec(t *testing.T, files []*codegen.File) *openapi.V2 {
+	t.Helper()
+	for _, f := range files {
+		if filepath.Base(f.Path) != "openapi.json" {
+			continue
+		}
+		var spec *openapi.V2
+		walk.Specs(f, func(s *openapi.V2) error {
+			spec = s
+			return walk.Stop
+		})
+		if spec != nil {
+			return spec
+		}
+	}
+	t.Fatal("no OpenAPI specification generated")
+	return nil
+}
+
+// ValidateSpec makes sure the given OpenAPI specification is valid once
+// converted to OpenAPI 3. It reports the validation error otherwise. The test
+// designs usually omit the API title and version which are required by the
+// specification, ValidateSpec provides defaults when they are missing.
+func ValidateSpec(t *testing.T, spec *openapi.V2) {
+	t.Helper()
+	b, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed to marshal OpenAPI specification: %s", err)
+	}
+	var doc2 openapi2.T
+	if err := json.Unmarshal(b, &doc2); err != nil {
+		t.Fatalf("failed to load OpenAPI specification: %s", err)
+	}
+	if doc2.Info.Title == "" {
+		doc2.Info.Title = "test api"
+	}
+	if doc2.Info.Version == "" {
+		doc2.Info.Version = "1.0"
+	}
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		t.Fatalf("failed to convert OpenAPI specification: %s", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Errorf("invalid OpenAPI specification: %s\n%s", err, indent(b))
+	}
+}
+
+// indent returns the given JSON indented for error messages.
+func indent(b []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return string(b)
+	}
+	ib, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return string(b)
+	}
+	return strings.TrimSpace(string(ib))
+}
@@ -0,0 +1,33 @@
+package plugintest_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/plugintest/testdata"
+)
+
+func TestSpec(t *testing.T) {
+	plugintest.RunDSL(t, testdata.CalcDSL)
+	fs, err := httpcodegen.OpenAPIFiles(expr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := plugintest.Spec(t, fs)
+	plugintest.ValidateSpec(t, spec)
+	b, err := json.MarshalIndent(spec.Paths, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugintest.Golden(t, "calc-paths.json", string(b)+"\n")
+}
+
+func TestSectionCode(t *testing.T) {
+	plugintest.RunDSL(t, testdata.CalcDSL)
+	fs := httpcodegen.ServerFiles("", expr.Root)
+	f := plugintest.File(t, fs, "gen/http/calc/server/encode_decode.go")
+	plugintest.Golden(t, "calc-request-decoder.golden", plugintest.SectionCode(t, f, "request-decoder"))
+}
@@ -0,0 +1,25 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CalcDSL = func() {
+	API("calc", func() {
+		Title("Calculator")
+		Version("1.0")
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(func() {
+				Attribute("a", Int, "Left operand")
+				Attribute("b", Int, "Right operand")
+				Required("a", "b")
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
@@ -0,0 +1,238 @@
+package protobuf
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	grpccodegen "goa.design/goa/v3/grpc/codegen"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+// endpointData contains the data necessary to render the function serving the
+// protocol buffer requests of an endpoint.
+type endpointData struct {
+	// ServiceName is the name of the service.
+	ServiceName string
+	// MethodName is the name of the method.
+	MethodName string
+	// VarName is the Go name of the method.
+	VarName string
+	// Message is the reference to the gRPC request message type, empty if
+	// the payload is not read from the request message.
+	Message string
+	// Decoder is the name of the gRPC request decoder, empty if the method
+	// has no payload.
+	Decoder string
+	// Encoder is the name of the gRPC response encoder.
+	Encoder string
+	// StatusCode is the status code of the HTTP success response.
+	StatusCode int
+}
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "protobuf",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate makes the HTTP handlers of the methods that also define a gRPC
+// endpoint serve protocol buffer requests and documents the protocol buffer
+// media type in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok || r.API == nil || r.API.HTTP == nil || r.API.GRPC == nil {
+			continue
+		}
+		for _, svc := range r.API.HTTP.Services {
+			if !config.Enabled("protobuf", svc.Name()) {
+				continue
+			}
+			if f := protobufFile(genpkg, r, svc); f != nil {
+				files = append(files, f)
+			}
+		}
+		for _, f := range files {
+			if !config.EnabledFile("protobuf", f) {
+				continue
+			}
+			if filepath.Base(f.Path) == "server.go" {
+				serverProtobuf(f, r)
+			} else {
+				documentProtobuf(f, r)
+			}
+		}
+	}
+	return files, nil
+}
+
+// protobufFile returns the file defining the functions that serve the
+// protocol buffer requests of the given HTTP service, nil if no method of the
+// service defines a unary gRPC endpoint.
+func protobufFile(genpkg string, r *goaexpr.RootExpr, svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	var data []*endpointData
+	for _, e := range svc.HTTPEndpoints {
+		ed := grpcEndpoint(r, svc.Name(), e.Name())
+		if ed == nil {
+			continue
+		}
+		d := &endpointData{
+			ServiceName: svc.Name(),
+			MethodName:  e.Name(),
+			VarName:     ed.Method.VarName,
+			Encoder:     "Encode" + ed.Method.VarName + "Response",
+			StatusCode:  e.Responses[0].StatusCode,
+		}
+		if ed.PayloadRef != "" {
+			d.Decoder = "Decode" + ed.Method.VarName + "Request"
+			if c := ed.Request.ServerConvert; c != nil && c.Init != nil && len(c.Init.Args) > 0 && c.Init.Args[0].Name == "message" {
+				d.Message = strings.TrimPrefix(c.SrcRef, "*")
+			}
+		}
+		data = append(data, d)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	gsd := grpccodegen.GRPCServices.Get(svc.Name())
+	svcName := codegen.SnakeCase(gsd.Service.VarName)
+	specs := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "net/http"},
+		{Path: "google.golang.org/grpc/metadata"},
+		codegen.GoaImport(""),
+		{Path: "goa.design/plugins/v3/protobuf"},
+		{Path: path.Join(genpkg, "grpc", svcName, "server"), Name: "grpcsvr"},
+	}
+	for _, d := range data {
+		if d.Message != "" {
+			specs = append(specs, &codegen.ImportSpec{Path: path.Join(genpkg, "grpc", svcName, "pb"), Name: gsd.PkgName})
+			break
+		}
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name()+" HTTP server protocol buffer handlers", "server", specs),
+	}
+	for _, d := range data {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "protobuf-serve",
+			Source: serveProtobufT,
+			Data:   d,
+		})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", svcName, "server", "protobuf.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// serverProtobuf makes the HTTP handlers of the methods that define a unary
+// gRPC endpoint serve the requests whose body is a protocol buffer message.
+func serverProtobuf(f *codegen.File, r *goaexpr.RootExpr) {
+	for _, s := range f.Section("server-handler-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || grpcEndpoint(r, ed.ServiceName, ed.Method.Name) == nil {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/protobuf"})
+		svcKey := "ctx = context.WithValue(ctx, goa.ServiceKey, {{ printf \"%q\" .ServiceName }})"
+		s.Source = strings.Replace(s.Source, svcKey, svcKey+`
+		if protobuf.IsProtobuf(r) {
+			if err := serve{{ .Method.VarName }}Protobuf(ctx, w, r, endpoint); err != nil {
+				if err := encodeError(ctx, w, err); err != nil {
+					eh(ctx, w, err)
+				}
+			}
+			return
+		}`, 1)
+	}
+}
+
+// documentProtobuf adds the protocol buffer media type to the media types
+// consumed and produced by the operations of the methods that define a unary
+// gRPC endpoint if f is an OpenAPI file.
+func documentProtobuf(f *codegen.File, r *goaexpr.RootExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("protobuf", op) || !isProtobufOperation(r, op) {
+				return nil
+			}
+			if len(op.Consumes) == 0 {
+				op.Consumes = append([]string{}, spec.Consumes...)
+			}
+			if len(op.Produces) == 0 {
+				op.Produces = append([]string{}, spec.Produces...)
+			}
+			op.Consumes = walk.AppendMediaType(op.Consumes, MediaType)
+			op.Produces = walk.AppendMediaType(op.Produces, MediaType)
+			return nil
+		})
+	})
+}
+
+// grpcEndpoint returns the gRPC endpoint data of the given method if the
+// method defines both a HTTP and a unary gRPC endpoint, nil otherwise.
+func grpcEndpoint(r *goaexpr.RootExpr, svc, method string) *grpccodegen.EndpointData {
+	gs := r.API.GRPC.Service(svc)
+	if gs == nil || gs.Endpoint(method) == nil {
+		return nil
+	}
+	hs := r.API.HTTP.Service(svc)
+	if hs == nil {
+		return nil
+	}
+	he := hs.Endpoint(method)
+	if he == nil || he.MethodExpr.IsStreaming() || len(he.Responses) == 0 {
+		return nil
+	}
+	return grpccodegen.GRPCServices.Get(svc).Endpoint(method)
+}
+
+// isProtobufOperation returns true if the given operation corresponds to a
+// method that defines a unary gRPC endpoint.
+func isProtobufOperation(r *goaexpr.RootExpr, op *openapi.Operation) bool {
+	m := walk.OperationMethod(r, op)
+	return m != nil && grpcEndpoint(r, m.Service.Name, m.Name) != nil
+}
+
+// input: endpointData
+const serveProtobufT = `{{ printf "serve%sProtobuf serves the %q service %q endpoint requests whose body is a protocol buffer message. It builds the payload and encodes the result with the gRPC server decoder and encoder." .VarName .ServiceName .MethodName | comment }}
+func serve{{ .VarName }}Protobuf(ctx context.Context, w http.ResponseWriter, r *http.Request, endpoint goa.Endpoint) error {
+{{- if .Message }}
+	var message {{ .Message }}
+	if err := protobuf.ReadMessage(r, &message); err != nil {
+		return err
+	}
+{{- end }}
+{{- if .Decoder }}
+	payload, err := grpcsvr.{{ .Decoder }}(ctx, {{ if .Message }}&message{{ else }}nil{{ end }}, protobuf.Metadata(r))
+	if err != nil {
+		return err
+	}
+	res, err := endpoint(ctx, payload)
+{{- else }}
+	res, err := endpoint(ctx, nil)
+{{- end }}
+	if err != nil {
+		return err
+	}
+	hdr, trlr := metadata.MD{}, metadata.MD{}
+	resp, err := grpcsvr.{{ .Encoder }}(ctx, res, &hdr, &trlr)
+	if err != nil {
+		return err
+	}
+	return protobuf.WriteMessage(w, {{ .StatusCode }}, resp, hdr, trlr)
+}
+`
@@ -0,0 +1,69 @@
+package protobuf_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	grpccodegen "goa.design/goa/v3/grpc/codegen"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/protobuf"
+	"goa.design/plugins/v3/protobuf/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	grpccodegen.GRPCServices = make(grpccodegen.ServicesData)
+	httpcodegen.RunHTTPDSL(t, testdata.CalcDSL)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := protobuf.Generate("calc/gen", []eval.Root{goaexpr.Root}, append(httpcodegen.ServerFiles("calc/gen", goaexpr.Root), ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var serves []string
+	for _, f := range fs {
+		for _, s := range f.Section("protobuf-serve") {
+			serves = append(serves, codegen.SectionCode(t, s))
+		}
+		for _, s := range f.Section("server-handler-init") {
+			ed := s.Data.(*httpcodegen.EndpointData)
+			code := codegen.SectionCode(t, s)
+			switch ed.Method.Name {
+			case "Add":
+				if code != testdata.AddHandlerCode {
+					t.Errorf("invalid handler code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.AddHandlerCode))
+				}
+			case "Reset":
+				if strings.Contains(code, "protobuf") {
+					t.Errorf("unexpected protocol buffer support in HTTP only method")
+				}
+			}
+		}
+	}
+	if len(serves) != 2 {
+		t.Fatalf("got %d protocol buffer handlers, expected 2", len(serves))
+	}
+	for i, expected := range []string{testdata.ServeAddCode, testdata.ServeVersionCode} {
+		if serves[i] != expected {
+			t.Errorf("invalid serve code, got:\n%s\ngot vs. expected:\n%s", serves[i], codegen.Diff(t, serves[i], expected))
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	for p, expected := range map[string]bool{"/add": true, "/reset": false} {
+		op := spec.Paths[p].(*openapi.Path).Post
+		var found bool
+		for _, mt := range op.Consumes {
+			if mt == protobuf.MediaType {
+				found = true
+			}
+		}
+		if found != expected {
+			t.Errorf("%s: got protocol buffer media type %v, expected %v", p, found, expected)
+		}
+	}
+}
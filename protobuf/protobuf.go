@@ -0,0 +1,78 @@
+package protobuf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// MediaType is the protocol buffer media type.
+const MediaType = "application/x-protobuf"
+
+// mediaTypes lists the media types recognized as protocol buffers.
+var mediaTypes = map[string]bool{
+	MediaType:                         true,
+	"application/protobuf":            true,
+	"application/vnd.google.protobuf": true,
+}
+
+// IsProtobuf returns true if the Content-Type header of r is a protocol
+// buffer media type.
+func IsProtobuf(r *http.Request) bool {
+	ct := r.Header.Get("Content-Type")
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		ct = mt
+	}
+	return mediaTypes[ct]
+}
+
+// ReadMessage reads the body of r into msg.
+func ReadMessage(r *http.Request, msg proto.Message) error {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return goa.DecodePayloadError(err.Error())
+	}
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return goa.DecodePayloadError(err.Error())
+	}
+	return nil
+}
+
+// Metadata returns the gRPC metadata corresponding to the headers of r. The
+// metadata keys are the lower case header names.
+func Metadata(r *http.Request) map[string][]string {
+	md := make(map[string][]string, len(r.Header))
+	for k, v := range r.Header {
+		md[strings.ToLower(k)] = v
+	}
+	return md
+}
+
+// WriteMessage writes the response with the given status code and msg as
+// body. The header and trailer metadata are written as response headers.
+func WriteMessage(w http.ResponseWriter, status int, msg interface{}, hdr, trlr map[string][]string) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return fmt.Errorf("invalid protocol buffer message type %T", msg)
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	for _, md := range []map[string][]string{hdr, trlr} {
+		for k, vs := range md {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,72 @@
+package protobuf
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+func TestIsProtobuf(t *testing.T) {
+	cases := map[string]bool{
+		"application/x-protobuf":          true,
+		"application/protobuf; proto=Foo": true,
+		"application/vnd.google.protobuf": true,
+		"application/json":                false,
+		"":                                false,
+	}
+	for ct, expected := range cases {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Header.Set("Content-Type", ct)
+		if actual := IsProtobuf(r); actual != expected {
+			t.Errorf("%q: got %v, expected %v", ct, actual, expected)
+		}
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	b, err := proto.Marshal(&wrappers.StringValue{Value: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg wrappers.StringValue
+	if err := ReadMessage(httptest.NewRequest("POST", "/", bytes.NewReader(b)), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Value != "foo" {
+		t.Errorf("got %q, expected %q", msg.Value, "foo")
+	}
+	if err := ReadMessage(httptest.NewRequest("POST", "/", bytes.NewReader([]byte{0xff})), &msg); err == nil {
+		t.Error("expected an error for an invalid message")
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	w := httptest.NewRecorder()
+	hdr := map[string][]string{"x-request-id": {"42"}}
+	if err := WriteMessage(w, http.StatusCreated, &wrappers.Int64Value{Value: 3}, hdr, nil); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("got status %d, expected %d", w.Code, http.StatusCreated)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != MediaType {
+		t.Errorf("got Content-Type %q, expected %q", ct, MediaType)
+	}
+	if id := w.Header().Get("X-Request-Id"); id != "42" {
+		t.Errorf("got X-Request-Id %q, expected %q", id, "42")
+	}
+	var msg wrappers.Int64Value
+	if err := proto.Unmarshal(w.Body.Bytes(), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Value != 3 {
+		t.Errorf("got %d, expected 3", msg.Value)
+	}
+	if err := WriteMessage(httptest.NewRecorder(), http.StatusOK, "foo", nil, nil); err == nil {
+		t.Error("expected an error for a non protocol buffer message")
+	}
+}
@@ -0,0 +1,92 @@
+package testdata
+
+var ServeAddCode = `// serveAddProtobuf serves the "Calc" service "Add" endpoint requests whose
+// body is a protocol buffer message. It builds the payload and encodes the
+// result with the gRPC server decoder and encoder.
+func serveAddProtobuf(ctx context.Context, w http.ResponseWriter, r *http.Request, endpoint goa.Endpoint) error {
+	var message calcpb.AddRequest
+	if err := protobuf.ReadMessage(r, &message); err != nil {
+		return err
+	}
+	payload, err := grpcsvr.DecodeAddRequest(ctx, &message, protobuf.Metadata(r))
+	if err != nil {
+		return err
+	}
+	res, err := endpoint(ctx, payload)
+	if err != nil {
+		return err
+	}
+	hdr, trlr := metadata.MD{}, metadata.MD{}
+	resp, err := grpcsvr.EncodeAddResponse(ctx, res, &hdr, &trlr)
+	if err != nil {
+		return err
+	}
+	return protobuf.WriteMessage(w, 201, resp, hdr, trlr)
+}
+`
+
+var ServeVersionCode = `// serveVersionProtobuf serves the "Calc" service "Version" endpoint requests
+// whose body is a protocol buffer message. It builds the payload and encodes
+// the result with the gRPC server decoder and encoder.
+func serveVersionProtobuf(ctx context.Context, w http.ResponseWriter, r *http.Request, endpoint goa.Endpoint) error {
+	res, err := endpoint(ctx, nil)
+	if err != nil {
+		return err
+	}
+	hdr, trlr := metadata.MD{}, metadata.MD{}
+	resp, err := grpcsvr.EncodeVersionResponse(ctx, res, &hdr, &trlr)
+	if err != nil {
+		return err
+	}
+	return protobuf.WriteMessage(w, 200, resp, hdr, trlr)
+}
+`
+
+var AddHandlerCode = `// NewAddHandler creates a HTTP handler which loads the HTTP request and calls
+// the "Calc" service "Add" endpoint.
+func NewAddHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		decodeRequest  = DecodeAddRequest(mux, dec)
+		encodeResponse = EncodeAddResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Add")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Calc")
+		if protobuf.IsProtobuf(r) {
+			if err := serveAddProtobuf(ctx, w, r, endpoint); err != nil {
+				if err := encodeError(ctx, w, err); err != nil {
+					eh(ctx, w, err)
+				}
+			}
+			return
+		}
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, payload)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
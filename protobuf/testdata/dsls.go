@@ -0,0 +1,36 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var CalcDSL = func() {
+	Service("Calc", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Field(1, "a", Int)
+				Field(2, "b", Int)
+				Required("a", "b")
+			})
+			Result(Int)
+			HTTP(func() {
+				POST("/add")
+				Response(StatusCreated)
+			})
+			GRPC(func() {})
+		})
+		Method("Version", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/version")
+				Response(StatusOK)
+			})
+			GRPC(func() {})
+		})
+		Method("Reset", func() {
+			HTTP(func() {
+				POST("/reset")
+			})
+		})
+	})
+}
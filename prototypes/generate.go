@@ -0,0 +1,350 @@
+package prototypes
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+)
+
+type (
+	// fileData contains the data necessary to render the proto file.
+	fileData struct {
+		// APIName is the name of the API.
+		APIName string
+		// Package is the protocol buffer package.
+		Package string
+		// GoPackage is the value of the go_package option, empty if
+		// the generated package is unknown.
+		GoPackage string
+		// Imports lists the imported proto files.
+		Imports []string
+		// Messages lists the top level messages.
+		Messages []*messageData
+	}
+
+	// messageData describes a message.
+	messageData struct {
+		// Name is the message name.
+		Name string
+		// Description is the message description.
+		Description string
+		// Fields lists the message fields.
+		Fields []*fieldData
+		// Messages lists the nested messages describing the inline
+		// objects.
+		Messages []*messageData
+	}
+
+	// fieldData describes a message field.
+	fieldData struct {
+		// Name is the field name.
+		Name string
+		// Type is the field type, including the repeated label.
+		Type string
+		// Number is the field number.
+		Number uint64
+		// Description is the field description.
+		Description string
+	}
+
+	// builder computes the messages of the design types.
+	builder struct {
+		// imports lists the imported proto files.
+		imports []string
+		// wrappers lists the messages wrapping the nested arrays and
+		// maps.
+		wrappers []*messageData
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "prototypes",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the proto file describing the user types defined in the
+// design.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("prototypes", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			if f := protoFile(genpkg, r); f != nil {
+				files = append(files, f)
+			}
+		}
+	}
+	return files, nil
+}
+
+// protoFile returns the proto file describing the object user types and
+// result types of the design, nil if there is none.
+func protoFile(genpkg string, r *goaexpr.RootExpr) *codegen.File {
+	var (
+		b    = &builder{}
+		msgs []*messageData
+	)
+	uts := make([]goaexpr.UserType, 0, len(r.Types)+len(r.ResultTypes))
+	uts = append(uts, r.Types...)
+	for _, rt := range r.ResultTypes {
+		uts = append(uts, rt)
+	}
+	for _, ut := range uts {
+		if goaexpr.AsObject(ut) == nil {
+			continue
+		}
+		msgs = append(msgs, b.message(messageName(ut), ut.Attribute()))
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	pkg := codegen.SnakeCase(r.API.Name)
+	data := &fileData{
+		APIName:  r.API.Name,
+		Package:  pkg,
+		Imports:  b.imports,
+		Messages: append(msgs, b.wrappers...),
+	}
+	if genpkg != "" {
+		data.GoPackage = fmt.Sprintf("%s/proto;%s", genpkg, pkg)
+	}
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "proto", pkg+".proto"),
+		SectionTemplates: []*codegen.SectionTemplate{{
+			Name:    "prototypes",
+			Source:  protoT,
+			Data:    data,
+			FuncMap: map[string]interface{}{"message": renderMessage},
+		}},
+	}
+}
+
+// message returns the message describing the given object attribute.
+func (b *builder) message(name string, att *goaexpr.AttributeExpr) *messageData {
+	m := &messageData{Name: name, Description: att.Description}
+	obj := goaexpr.AsObject(att.Type)
+	used := make(map[uint64]bool)
+	for _, nat := range *obj {
+		if tag := rpcTag(nat.Attribute); tag > 0 {
+			used[tag] = true
+		}
+	}
+	next := uint64(1)
+	for _, nat := range *obj {
+		number := rpcTag(nat.Attribute)
+		if number == 0 {
+			for used[next] {
+				next++
+			}
+			number = next
+			used[next] = true
+		}
+		typ := b.fieldType(m, codegen.Goify(nat.Name, true), nat.Attribute)
+		m.Fields = append(m.Fields, &fieldData{
+			Name:        codegen.SnakeCase(nat.Name),
+			Type:        typ,
+			Number:      number,
+			Description: nat.Attribute.Description,
+		})
+	}
+	return m
+}
+
+// fieldType returns the type of the field holding the given attribute. The
+// inline objects are described by messages nested in parent named after the
+// field.
+func (b *builder) fieldType(parent *messageData, name string, att *goaexpr.AttributeExpr) string {
+	switch t := att.Type.(type) {
+	case *goaexpr.Array:
+		return "repeated " + b.elemType(parent, name, t.ElemType)
+	case *goaexpr.Map:
+		return fmt.Sprintf("map<%s, %s>", keyType(t.KeyType.Type), b.elemType(parent, name, t.ElemType))
+	default:
+		return b.elemType(parent, name, att)
+	}
+}
+
+// elemType returns the type of the given array element or map value. The
+// nested arrays and maps are wrapped in messages since protocol buffers do
+// not support them.
+func (b *builder) elemType(parent *messageData, name string, att *goaexpr.AttributeExpr) string {
+	switch t := att.Type.(type) {
+	case goaexpr.UserType:
+		if goaexpr.AsObject(t) != nil {
+			return messageName(t)
+		}
+		return b.elemType(parent, name, t.Attribute())
+	case *goaexpr.Object:
+		nested := b.message(name, att)
+		nested.Description = "" // documented by the field
+		parent.Messages = append(parent.Messages, nested)
+		return name
+	case *goaexpr.Array, *goaexpr.Map:
+		return b.wrapper(att)
+	case goaexpr.Primitive:
+		if t.Kind() == goaexpr.AnyKind {
+			b.addImport("google/protobuf/struct.proto")
+			return "google.protobuf.Value"
+		}
+		return nativeType(t)
+	default:
+		return "bytes"
+	}
+}
+
+// wrapper returns the name of the message wrapping the values of the given
+// array or map attribute, the message is created the first time.
+func (b *builder) wrapper(att *goaexpr.AttributeExpr) string {
+	name := wrapperName(att.Type)
+	for _, w := range b.wrappers {
+		if w.Name == name {
+			return name
+		}
+	}
+	w := &messageData{Name: name}
+	b.wrappers = append(b.wrappers, w)
+	w.Fields = []*fieldData{{Name: "field", Type: b.fieldType(w, "Field", att), Number: 1}}
+	return name
+}
+
+// addImport adds the given proto file to the imports if not already there.
+func (b *builder) addImport(path string) {
+	for _, i := range b.imports {
+		if i == path {
+			return
+		}
+	}
+	b.imports = append(b.imports, path)
+}
+
+// wrapperName returns the name of the message wrapping values of the given
+// type, e.g. ArrayOfString or MapOfStringArrayOfInt.
+func wrapperName(dt goaexpr.DataType) string {
+	switch t := dt.(type) {
+	case *goaexpr.Array:
+		return "ArrayOf" + wrapperName(t.ElemType.Type)
+	case *goaexpr.Map:
+		return "MapOf" + wrapperName(t.KeyType.Type) + wrapperName(t.ElemType.Type)
+	case goaexpr.UserType:
+		return messageName(t)
+	default:
+		return codegen.Goify(dt.Name(), true)
+	}
+}
+
+// messageName returns the name of the message describing the given user
+// type.
+func messageName(ut goaexpr.UserType) string {
+	return codegen.Goify(ut.Name(), true)
+}
+
+// keyType returns the type of the map keys of the given type. Protocol
+// buffers only support integral and string keys, the other keys are
+// described as strings.
+func keyType(dt goaexpr.DataType) string {
+	switch dt.Kind() {
+	case goaexpr.BooleanKind, goaexpr.IntKind, goaexpr.Int32Kind, goaexpr.Int64Kind,
+		goaexpr.UIntKind, goaexpr.UInt32Kind, goaexpr.UInt64Kind:
+		return nativeType(dt)
+	default:
+		return "string"
+	}
+}
+
+// nativeType returns the protocol buffer type of the given primitive type
+// using the same mapping as the goa gRPC code generator.
+func nativeType(dt goaexpr.DataType) string {
+	switch dt.Kind() {
+	case goaexpr.BooleanKind:
+		return "bool"
+	case goaexpr.IntKind, goaexpr.Int32Kind:
+		return "sint32"
+	case goaexpr.Int64Kind:
+		return "sint64"
+	case goaexpr.UIntKind, goaexpr.UInt32Kind:
+		return "uint32"
+	case goaexpr.UInt64Kind:
+		return "uint64"
+	case goaexpr.Float32Kind:
+		return "float"
+	case goaexpr.Float64Kind:
+		return "double"
+	case goaexpr.StringKind:
+		return "string"
+	default:
+		return "bytes"
+	}
+}
+
+// rpcTag returns the field number set with the "rpc:tag" meta, 0 if there is
+// none.
+func rpcTag(att *goaexpr.AttributeExpr) uint64 {
+	if t, ok := att.Meta["rpc:tag"]; ok && len(t) > 0 {
+		if n, err := strconv.ParseUint(t[0], 10, 64); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// renderMessage returns the definition of the given message indented with
+// the given prefix.
+func renderMessage(m *messageData, indent string) string {
+	var b strings.Builder
+	writeComment(&b, m.Description, indent)
+	fmt.Fprintf(&b, "%smessage %s {\n", indent, m.Name)
+	for _, nested := range m.Messages {
+		b.WriteString(renderMessage(nested, indent+"\t") + "\n")
+	}
+	for _, f := range m.Fields {
+		writeComment(&b, f.Description, indent+"\t")
+		fmt.Fprintf(&b, "%s\t%s %s = %d;\n", indent, f.Type, f.Name, f.Number)
+	}
+	fmt.Fprintf(&b, "%s}", indent)
+	return b.String()
+}
+
+// writeComment writes the given description as a comment indented with the
+// given prefix.
+func writeComment(b *strings.Builder, desc, indent string) {
+	if desc == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(desc), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// input: fileData
+const protoT = `// Types of the {{ .APIName }} API.
+
+syntax = "proto3";
+
+package {{ .Package }};
+{{- if .GoPackage }}
+
+option go_package = {{ printf "%q" .GoPackage }};
+{{- end }}
+{{- if .Imports }}
+{{ range .Imports }}
+import {{ printf "%q" . }};
+{{- end }}
+{{- end }}
+{{- range .Messages }}
+
+{{ message . "" }}
+{{- end }}
+`
@@ -0,0 +1,37 @@
+package prototypes_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/prototypes"
+	"goa.design/plugins/v3/prototypes/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	root := codegen.RunDSL(t, testdata.TypesDSL)
+	fs, err := prototypes.Generate("cellar/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/proto/cellar.proto" {
+		t.Errorf("got path %q, expected gen/proto/cellar.proto", fs[0].Path)
+	}
+	plugintest.Golden(t, "cellar.proto", plugintest.Render(t, fs[0]))
+}
+
+func TestGenerateNoType(t *testing.T) {
+	root := codegen.RunDSL(t, testdata.NoTypeDSL)
+	fs, err := prototypes.Generate("calc/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
@@ -0,0 +1,63 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var TypesDSL = func() {
+	var ID = Type("ID", String, func() {
+		Format(FormatUUID)
+	})
+	var Winery = Type("Winery", func() {
+		Description("Winery producing wines")
+		Attribute("name", String, "Name of the winery")
+		Attribute("region", String)
+	})
+	var Bottle = Type("Bottle", func() {
+		Description("Bottle of wine")
+		Attribute("id", ID, func() {
+			Meta("rpc:tag", "2")
+		})
+		Attribute("name", String, "Name of the wine")
+		Attribute("vintage", Int)
+		Attribute("rating", Float64)
+		Attribute("tags", ArrayOf(String))
+		Attribute("scores", MapOf(String, UInt64))
+		Attribute("grid", ArrayOf(ArrayOf(Int32)))
+		Attribute("pairings", MapOf(String, ArrayOf(Winery)))
+		Attribute("winery", Winery)
+		Attribute("label", func() {
+			Description("Label of the bottle")
+			Attribute("image", Bytes)
+			Attribute("extra", Any)
+		})
+	})
+	var StoredBottle = ResultType("application/vnd.stored-bottle", func() {
+		TypeName("StoredBottle")
+		Attributes(func() {
+			Attribute("bottle", Bottle)
+			Attribute("stored_at", String)
+		})
+	})
+	API("cellar", func() {})
+	Service("cellar", func() {
+		Method("show", func() {
+			Payload(Winery)
+			Result(StoredBottle)
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
+var NoTypeDSL = func() {
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(Int)
+			HTTP(func() {
+				GET("/add/{p}")
+			})
+		})
+	})
+}
@@ -0,0 +1,141 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/v3/dsl"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/provisioning/expr"
+
+	// Register code generators for the provisioning plugin
+	_ "goa.design/plugins/v3/provisioning"
+)
+
+// DeviceTokenSecurity defines a security scheme for device fleets: the devices
+// exchange their long-lived provisioning key for a short-lived device token
+// which they send in the Authorization header of the requests made to the
+// endpoints secured by the scheme. The scheme is a JWT scheme, the services
+// validate the device tokens in their JWTAuth function.
+//
+// DeviceTokenSecurity must appear at the top level of the design. It returns
+// the scheme to be used in Security expressions.
+//
+// DeviceTokenSecurity takes the name of the scheme as first argument and an
+// optional DSL function as second argument. The DSL function may use the goa
+// Description and Scope functions as well as the functions of this package.
+//
+// Example:
+//
+//    import provisioning "goa.design/plugins/v3/provisioning/dsl"
+//
+//    var DeviceAuth = provisioning.DeviceTokenSecurity("device", func() {
+//        provisioning.KeyHeader("X-Fleet-Key")
+//        provisioning.TokenTTL(15 * time.Minute)
+//        provisioning.ExchangePath("/fleet/token")
+//        Scope("telemetry:write")
+//    })
+//
+//    var _ = Service("telemetry", func() {
+//        Method("report", func() {
+//            Security(DeviceAuth, func() {
+//                Scope("telemetry:write")
+//            })
+//            Payload(func() {
+//                Token("token", String)
+//                Attribute("temperature", Float64)
+//            })
+//            HTTP(func() {
+//                POST("/readings")
+//            })
+//        })
+//    })
+//
+func DeviceTokenSecurity(name string, fn ...func()) *goaexpr.SchemeExpr {
+	if _, ok := eval.Current().(eval.TopExpr); !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return nil
+	}
+	s := &expr.SchemeExpr{
+		KeyHeader:    expr.DefaultKeyHeader,
+		TokenTTL:     expr.DefaultTokenTTL,
+		ExchangePath: "/" + name + "/token",
+	}
+	scheme := dsl.JWTSecurity(name, func() {
+		s.Scheme = eval.Current().(*goaexpr.SchemeExpr)
+		expr.Root.Schemes = append(expr.Root.Schemes, s)
+		if len(fn) == 1 {
+			fn[0]()
+		}
+		if s.Scheme.Description == "" {
+			s.Scheme.Description = s.Description()
+		}
+	})
+	return scheme
+}
+
+// KeyHeader sets the name of the header holding the provisioning key in the
+// exchange requests, "X-Provisioning-Key" by default.
+//
+// KeyHeader must appear in a DeviceTokenSecurity expression.
+//
+// Example:
+//
+//     DeviceTokenSecurity("device", func() {
+//         KeyHeader("X-Fleet-Key")
+//     })
+//
+func KeyHeader(name string) {
+	if s := scheme(); s != nil {
+		s.KeyHeader = name
+	}
+}
+
+// TokenTTL sets the lifetime of the device tokens, one hour by default.
+//
+// TokenTTL must appear in a DeviceTokenSecurity expression.
+//
+// Example:
+//
+//     DeviceTokenSecurity("device", func() {
+//         TokenTTL(15 * time.Minute)
+//     })
+//
+func TokenTTL(d time.Duration) {
+	if s := scheme(); s != nil {
+		s.TokenTTL = d
+	}
+}
+
+// ExchangePath sets the path of the endpoint exchanging the provisioning keys
+// for device tokens, "/<scheme name>/token" by default.
+//
+// ExchangePath must appear in a DeviceTokenSecurity expression.
+//
+// Example:
+//
+//     DeviceTokenSecurity("device", func() {
+//         ExchangePath("/fleet/token")
+//     })
+//
+func ExchangePath(path string) {
+	if s := scheme(); s != nil {
+		s.ExchangePath = path
+	}
+}
+
+// scheme returns the device token scheme being defined, nil and reports an
+// error if there is none.
+func scheme() *expr.SchemeExpr {
+	if se, ok := eval.Current().(*goaexpr.SchemeExpr); ok {
+		if s := expr.Root.Scheme(se); s != nil {
+			return s
+		}
+	}
+	eval.IncompatibleDSL()
+	return nil
+}
@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the device token security schemes.
+	RootExpr struct {
+		// Schemes lists the device token schemes in the order they
+		// appear in the design.
+		Schemes []*SchemeExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "provisioning plugin"
+}
+
+// WalkSets iterates over the device token schemes.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	sexps := make(eval.ExpressionSet, len(r.Schemes))
+	for i, s := range r.Schemes {
+		sexps[i] = s
+	}
+	walk(sexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/provisioning/dsl"}
+}
+
+// Scheme returns the device token scheme extending the given goa security
+// scheme, nil if there is none.
+func (r *RootExpr) Scheme(s *expr.SchemeExpr) *SchemeExpr {
+	for _, ds := range r.Schemes {
+		if ds.Scheme == s {
+			return ds
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// DefaultKeyHeader is the default name of the header holding the
+	// provisioning key in the exchange requests.
+	DefaultKeyHeader = "X-Provisioning-Key"
+	// DefaultTokenTTL is the default lifetime of the device tokens.
+	DefaultTokenTTL = time.Hour
+)
+
+type (
+	// SchemeExpr describes a device token security scheme: the devices
+	// exchange their long-lived provisioning key for a short-lived token
+	// which they use to call the endpoints secured by the scheme.
+	SchemeExpr struct {
+		// Scheme is the JWT security scheme validating the device
+		// tokens.
+		Scheme *expr.SchemeExpr
+		// KeyHeader is the name of the header holding the provisioning
+		// key in the exchange requests.
+		KeyHeader string
+		// TokenTTL is the lifetime of the device tokens.
+		TokenTTL time.Duration
+		// ExchangePath is the path of the endpoint exchanging the
+		// provisioning keys for device tokens.
+		ExchangePath string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (s *SchemeExpr) EvalName() string {
+	return fmt.Sprintf("device token scheme %q", s.Scheme.SchemeName)
+}
+
+// KeySchemeName returns the name of the scheme describing the provisioning
+// key in the OpenAPI specification.
+func (s *SchemeExpr) KeySchemeName() string {
+	return s.Scheme.SchemeName + "_provisioning_key"
+}
+
+// Validate ensures the device token scheme is valid.
+func (s *SchemeExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if s.KeyHeader == "" {
+		verr.Add(s, "provisioning key header cannot be empty")
+	}
+	if s.TokenTTL <= 0 {
+		verr.Add(s, "token lifetime must be positive")
+	}
+	if !strings.HasPrefix(s.ExchangePath, "/") {
+		verr.Add(s, "invalid exchange path %q, must start with /", s.ExchangePath)
+	}
+	for _, other := range Root.Schemes {
+		if other != s && other.ExchangePath == s.ExchangePath {
+			verr.Add(s, "exchange path %q is also used by %s", s.ExchangePath, other.EvalName())
+			break
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Description returns the default description of the JWT scheme which
+// explains how to obtain the device tokens.
+func (s *SchemeExpr) Description() string {
+	return fmt.Sprintf("Short-lived device token obtained by sending the device provisioning key in the %s header of a POST request to %s.",
+		s.KeyHeader, s.ExchangePath)
+}
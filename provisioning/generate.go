@@ -0,0 +1,148 @@
+package provisioning
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/provisioning/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// schemeData contains the data necessary to render the issuer of a
+	// device token scheme.
+	schemeData struct {
+		// Name is the scheme name.
+		Name string
+		// VarName is the Go name of the scheme.
+		VarName string
+		// KeyHeader is the name of the provisioning key header.
+		KeyHeader string
+		// TokenTTL is the Go expression of the token lifetime.
+		TokenTTL string
+		// ExchangePath is the path of the exchange endpoint.
+		ExchangePath string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "provisioning",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the file implementing the exchange endpoints of the device
+// token schemes and documents the exchanges in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("provisioning", "") {
+		return files, nil
+	}
+	if len(expr.Root.Schemes) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		documentExchanges(f)
+	}
+	return append(files, provisioningFile()), nil
+}
+
+// provisioningFile returns the file defining the device token issuers and the
+// functions mounting the exchange endpoints.
+func provisioningFile() *codegen.File {
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Device provisioning", "provisioning", []*codegen.ImportSpec{
+			{Path: "time"},
+			codegen.GoaNamedImport("http", "goahttp"),
+			{Path: "goa.design/plugins/v3/provisioning"},
+		}),
+	}
+	for _, s := range expr.Root.Schemes {
+		data := &schemeData{
+			Name:         s.Scheme.SchemeName,
+			VarName:      codegen.Goify(s.Scheme.SchemeName, true),
+			KeyHeader:    s.KeyHeader,
+			TokenTTL:     genutil.DurationCode(s.TokenTTL),
+			ExchangePath: s.ExchangePath,
+		}
+		sections = append(sections, &codegen.SectionTemplate{Name: "provisioning-issuer", Source: issuerT, Data: data})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "http", "provisioning", "provisioning.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// documentExchanges adds the provisioning key security definitions and the
+// exchange endpoints to the OpenAPI specification if f is an OpenAPI file.
+func documentExchanges(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		if spec.SecurityDefinitions == nil {
+			spec.SecurityDefinitions = make(map[string]*openapi.SecurityDefinition)
+		}
+		for _, ds := range expr.Root.Schemes {
+			spec.SecurityDefinitions[ds.KeySchemeName()] = &openapi.SecurityDefinition{
+				Type:        "apiKey",
+				Description: fmt.Sprintf("Long-lived device provisioning key exchanged for a %q device token.", ds.Scheme.SchemeName),
+				Name:        ds.KeyHeader,
+				In:          "header",
+			}
+			key := ds.ExchangePath
+			if spec.BasePath != "" && spec.BasePath != "/" && strings.HasPrefix(key, spec.BasePath+"/") {
+				key = strings.TrimPrefix(key, spec.BasePath)
+			}
+			spec.Paths[key] = &openapi.Path{Post: &openapi.Operation{
+				Tags:        []string{"provisioning"},
+				Summary:     fmt.Sprintf("exchange %s provisioning key", ds.Scheme.SchemeName),
+				Description: fmt.Sprintf("Exchanges the device provisioning key sent in the %s header for a device token valid %s.", ds.KeyHeader, ds.TokenTTL),
+				OperationID: "provisioning#" + ds.Scheme.SchemeName,
+				Produces:    []string{"application/json"},
+				Security:    []map[string][]string{{ds.KeySchemeName(): {}}},
+				Responses: map[string]*openapi.Response{
+					"200": {Description: "OK response.", Schema: tokenSchema()},
+					"401": {Description: "Unauthorized response."},
+				},
+			}}
+		}
+		return nil
+	})
+}
+
+// tokenSchema returns the schema of the exchange responses.
+func tokenSchema() *openapi.Schema {
+	return &openapi.Schema{
+		Type: openapi.Object,
+		Properties: map[string]*openapi.Schema{
+			"access_token": {Type: openapi.String, Description: "Device token to send in the Authorization header."},
+			"token_type":   {Type: openapi.String, Description: "Token type, always Bearer."},
+			"expires_in":   {Type: openapi.Integer, Description: "Lifetime of the token in seconds."},
+		},
+		Required: []string{"access_token", "token_type", "expires_in"},
+	}
+}
+
+// input: schemeData
+const issuerT = `{{ printf "New%sIssuer returns the issuer of the %q scheme device tokens. The tokens are signed with secret and the provisioning keys are verified with verify." .VarName .Name | comment }}
+func New{{ .VarName }}Issuer(secret []byte, verify provisioning.KeyVerifier) *provisioning.Issuer {
+	return &provisioning.Issuer{
+		KeyHeader: {{ printf "%q" .KeyHeader }},
+		TTL:       {{ .TokenTTL }},
+		Secret:    secret,
+		Verify:    verify,
+	}
+}
+
+{{ printf "Mount%sExchange configures the mux to serve the requests made to POST %q which exchange provisioning keys for device tokens." .VarName .ExchangePath | comment }}
+func Mount{{ .VarName }}Exchange(mux goahttp.Muxer, issuer *provisioning.Issuer) {
+	mux.Handle("POST", {{ printf "%q" .ExchangePath }}, issuer.ServeHTTP)
+}
+`
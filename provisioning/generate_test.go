@@ -0,0 +1,75 @@
+package provisioning_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/provisioning"
+	"goa.design/plugins/v3/provisioning/expr"
+	"goa.design/plugins/v3/provisioning/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name      string
+		DSL       func()
+		Code      string
+		Path      string
+		KeyHeader string
+	}{
+		{"custom", testdata.DeviceDSL, testdata.DeviceIssuerCode, "/fleet/token", "X-Fleet-Key"},
+		{"default", testdata.DefaultDSL, testdata.DefaultIssuerCode, "/device/token", "X-Provisioning-Key"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Schemes = nil
+			openapi.Definitions = make(map[string]*openapi.Schema)
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err = provisioning.Generate("", []eval.Root{goaexpr.Root}, fs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var file *codegen.File
+			for _, f := range fs {
+				if f.Path == filepath.Join("gen", "http", "provisioning", "provisioning.go") {
+					file = f
+				}
+			}
+			if file == nil {
+				t.Fatal("provisioning file not generated")
+			}
+			sections := file.Section("provisioning-issuer")
+			if len(sections) != 1 {
+				t.Fatalf("got %d issuer sections, expected 1", len(sections))
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != c.Code {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code))
+			}
+			spec := fs[0].SectionTemplates[0].Data.(*openapi.V2)
+			p, ok := spec.Paths[c.Path].(*openapi.Path)
+			if !ok || p.Post == nil {
+				t.Fatalf("exchange endpoint %q not found in OpenAPI spec", c.Path)
+			}
+			if len(p.Post.Security) != 1 || p.Post.Security[0]["device_provisioning_key"] == nil {
+				t.Errorf("got security %v, expected device_provisioning_key", p.Post.Security)
+			}
+			def := spec.SecurityDefinitions["device_provisioning_key"]
+			if def == nil || def.Type != "apiKey" || def.In != "header" || def.Name != c.KeyHeader {
+				t.Errorf("got security definition %+v, expected apiKey in header %q", def, c.KeyHeader)
+			}
+			if jwt := spec.SecurityDefinitions["device_header_Authorization"]; jwt == nil || jwt.Description == "" {
+				t.Error("missing device token scheme description")
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package provisioning
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/security"
+)
+
+type (
+	// KeyVerifier verifies a provisioning key. It returns the ID of the
+	// device the key was issued to and the scopes granted to the device, or
+	// an error if the key is unknown or revoked.
+	KeyVerifier func(ctx context.Context, key string) (device string, scopes []string, err error)
+
+	// Issuer exchanges provisioning keys for device tokens and validates
+	// the device tokens. The tokens are JWTs signed with HMAC-SHA256.
+	Issuer struct {
+		// KeyHeader is the name of the header holding the provisioning
+		// key in the exchange requests.
+		KeyHeader string
+		// TTL is the lifetime of the device tokens.
+		TTL time.Duration
+		// Secret is the key used to sign the device tokens.
+		Secret []byte
+		// Verify verifies the provisioning keys.
+		Verify KeyVerifier
+	}
+
+	// Claims are the claims of a device token.
+	Claims struct {
+		// Subject is the device ID.
+		Subject string `json:"sub"`
+		// Scopes lists the scopes granted to the device.
+		Scopes []string `json:"scopes,omitempty"`
+		// IssuedAt is the time the token was issued at in seconds since
+		// the epoch.
+		IssuedAt int64 `json:"iat"`
+		// ExpiresAt is the time the token expires at in seconds since
+		// the epoch.
+		ExpiresAt int64 `json:"exp"`
+	}
+
+	// Token is the body of the exchange responses.
+	Token struct {
+		// AccessToken is the device token.
+		AccessToken string `json:"access_token"`
+		// TokenType is always "Bearer".
+		TokenType string `json:"token_type"`
+		// ExpiresIn is the lifetime of the token in seconds.
+		ExpiresIn int64 `json:"expires_in"`
+	}
+
+	// ctxKey is the type of the context key holding the claims.
+	ctxKey struct{}
+)
+
+var (
+	// ErrInvalidToken is returned when a device token is malformed or its
+	// signature is invalid.
+	ErrInvalidToken = errors.New("invalid device token")
+	// ErrExpiredToken is returned when a device token has expired.
+	ErrExpiredToken = errors.New("expired device token")
+
+	// header is the encoded header of the device tokens.
+	header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+	// now returns the current time, it is replaced in tests.
+	now = time.Now
+)
+
+// ServeHTTP handles the exchange requests. It responds with 401 if the
+// provisioning key is missing or if it does not verify.
+func (i *Issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(i.KeyHeader)
+	if key == "" {
+		http.Error(w, "missing provisioning key", http.StatusUnauthorized)
+		return
+	}
+	device, scopes, err := i.Verify(r.Context(), key)
+	if err != nil {
+		http.Error(w, "invalid provisioning key", http.StatusUnauthorized)
+		return
+	}
+	tok, err := i.Issue(device, scopes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tok)
+}
+
+// Issue returns a device token for the given device and scopes.
+func (i *Issuer) Issue(device string, scopes []string) (*Token, error) {
+	iat := now()
+	claims, err := json.Marshal(&Claims{
+		Subject:   device,
+		Scopes:    scopes,
+		IssuedAt:  iat.Unix(),
+		ExpiresAt: iat.Add(i.TTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	payload := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return &Token{
+		AccessToken: payload + "." + i.sign(payload),
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(i.TTL / time.Second),
+	}, nil
+}
+
+// Parse validates the given device token and returns its claims.
+func (i *Issuer) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(i.sign(parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalidToken
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+// Authenticate validates the device token and the scopes required by the
+// scheme. It returns a context holding the token claims. Authenticate may be
+// used as the implementation of the service JWTAuth function.
+func (i *Issuer) Authenticate(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	claims, err := i.Parse(strings.TrimPrefix(token, "Bearer "))
+	if err != nil {
+		return ctx, err
+	}
+	if scheme != nil {
+		if err := scheme.Validate(claims.Scopes); err != nil {
+			return ctx, err
+		}
+	}
+	return WithClaims(ctx, claims), nil
+}
+
+// WithClaims returns a copy of ctx holding the given claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, ctxKey{}, claims)
+}
+
+// ContextClaims returns the device token claims held by ctx, nil if there are
+// none.
+func ContextClaims(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(ctxKey{}).(*Claims)
+	return claims
+}
+
+// sign returns the encoded signature of the given token header and payload.
+func (i *Issuer) sign(payload string) string {
+	mac := hmac.New(sha256.New, i.Secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
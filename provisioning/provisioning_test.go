@@ -0,0 +1,108 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goa.design/goa/v3/security"
+)
+
+func newIssuer() *Issuer {
+	return &Issuer{
+		KeyHeader: "X-Provisioning-Key",
+		TTL:       time.Minute,
+		Secret:    []byte("secret"),
+		Verify: func(ctx context.Context, key string) (string, []string, error) {
+			if key != "valid" {
+				return "", nil, errors.New("unknown key")
+			}
+			return "device-1", []string{"telemetry:write"}, nil
+		},
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	cases := []struct {
+		Name string
+		Key  string
+		Code int
+	}{
+		{"valid", "valid", http.StatusOK},
+		{"invalid", "invalid", http.StatusUnauthorized},
+		{"missing", "", http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			i := newIssuer()
+			req := httptest.NewRequest("POST", "/device/token", nil)
+			if c.Key != "" {
+				req.Header.Set("X-Provisioning-Key", c.Key)
+			}
+			w := httptest.NewRecorder()
+			i.ServeHTTP(w, req)
+			if w.Code != c.Code {
+				t.Fatalf("got status %d, expected %d", w.Code, c.Code)
+			}
+			if c.Code != http.StatusOK {
+				return
+			}
+			var tok Token
+			if err := json.NewDecoder(w.Body).Decode(&tok); err != nil {
+				t.Fatal(err)
+			}
+			if tok.TokenType != "Bearer" || tok.ExpiresIn != 60 {
+				t.Errorf("got token %+v, expected Bearer token expiring in 60s", tok)
+			}
+			claims, err := i.Parse(tok.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Subject != "device-1" {
+				t.Errorf("got subject %q, expected %q", claims.Subject, "device-1")
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	i := newIssuer()
+	tok, err := i.Issue("device-1", []string{"telemetry:write"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := newIssuer()
+	other.Secret = []byte("other")
+	cases := []struct {
+		Name   string
+		Issuer *Issuer
+		Token  string
+		Scopes []string
+		Delay  time.Duration
+		Error  bool
+	}{
+		{"valid", i, tok.AccessToken, []string{"telemetry:write"}, 0, false},
+		{"bearer", i, "Bearer " + tok.AccessToken, nil, 0, false},
+		{"missing-scope", i, tok.AccessToken, []string{"admin"}, 0, true},
+		{"expired", i, tok.AccessToken, nil, time.Minute, true},
+		{"signature", other, tok.AccessToken, nil, 0, true},
+		{"malformed", i, "abc", nil, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			defer func() { now = time.Now }()
+			now = func() time.Time { return time.Now().Add(c.Delay) }
+			ctx, err := c.Issuer.Authenticate(context.Background(), c.Token, &security.JWTScheme{RequiredScopes: c.Scopes})
+			if (err != nil) != c.Error {
+				t.Fatalf("got error %v, expected error: %v", err, c.Error)
+			}
+			if err == nil && ContextClaims(ctx).Subject != "device-1" {
+				t.Errorf("got claims %+v, expected subject device-1", ContextClaims(ctx))
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package testdata
+
+const DeviceIssuerCode = `// NewDeviceIssuer returns the issuer of the "device" scheme device tokens. The
+// tokens are signed with secret and the provisioning keys are verified with
+// verify.
+func NewDeviceIssuer(secret []byte, verify provisioning.KeyVerifier) *provisioning.Issuer {
+	return &provisioning.Issuer{
+		KeyHeader: "X-Fleet-Key",
+		TTL:       15 * time.Minute,
+		Secret:    secret,
+		Verify:    verify,
+	}
+}
+
+// MountDeviceExchange configures the mux to serve the requests made to POST
+// "/api/fleet/token" which exchange provisioning keys for device tokens.
+func MountDeviceExchange(mux goahttp.Muxer, issuer *provisioning.Issuer) {
+	mux.Handle("POST", "/api/fleet/token", issuer.ServeHTTP)
+}
+`
+
+const DefaultIssuerCode = `// NewDeviceIssuer returns the issuer of the "device" scheme device tokens. The
+// tokens are signed with secret and the provisioning keys are verified with
+// verify.
+func NewDeviceIssuer(secret []byte, verify provisioning.KeyVerifier) *provisioning.Issuer {
+	return &provisioning.Issuer{
+		KeyHeader: "X-Provisioning-Key",
+		TTL:       time.Hour,
+		Secret:    secret,
+		Verify:    verify,
+	}
+}
+
+// MountDeviceExchange configures the mux to serve the requests made to POST
+// "/device/token" which exchange provisioning keys for device tokens.
+func MountDeviceExchange(mux goahttp.Muxer, issuer *provisioning.Issuer) {
+	mux.Handle("POST", "/device/token", issuer.ServeHTTP)
+}
+`
@@ -0,0 +1,51 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	provisioning "goa.design/plugins/v3/provisioning/dsl"
+)
+
+var DeviceDSL = func() {
+	var DeviceAuth = provisioning.DeviceTokenSecurity("device", func() {
+		provisioning.KeyHeader("X-Fleet-Key")
+		provisioning.TokenTTL(15 * time.Minute)
+		provisioning.ExchangePath("/api/fleet/token")
+		Scope("telemetry:write")
+	})
+	API("fleet", func() {
+		HTTP(func() {
+			Path("/api")
+		})
+	})
+	Service("telemetry", func() {
+		Method("report", func() {
+			Security(DeviceAuth, func() {
+				Scope("telemetry:write")
+			})
+			Payload(func() {
+				Token("token", String)
+				Attribute("temperature", Float64)
+			})
+			HTTP(func() {
+				POST("/readings")
+			})
+		})
+	})
+}
+
+var DefaultDSL = func() {
+	var DeviceAuth = provisioning.DeviceTokenSecurity("device")
+	Service("telemetry", func() {
+		Method("report", func() {
+			Security(DeviceAuth)
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				POST("/readings")
+			})
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package dsl
+
+import (
+	goadsl "goa.design/goa/v3/dsl"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/quota/expr"
+
+	// Register code generators for the quota plugin
+	_ "goa.design/plugins/v3/quota"
+)
+
+const (
+	// Day is the period of the quotas reset every day at midnight UTC.
+	Day = expr.Day
+	// Month is the period of the quotas reset on the first day of every
+	// month at midnight UTC.
+	Month = expr.Month
+)
+
+// Plan defines a plan the API keys subscribe to and its quotas. The first plan
+// is the default plan of the API keys for which the plan lookup of the
+// generated quota managers returns no plan.
+//
+// Plan must appear in an API expression.
+//
+// Plan accepts the plan name as first argument and a DSL function listing the
+// limits of the plan as second argument.
+//
+// Example:
+//
+//    import quota "goa.design/plugins/v3/quota/dsl"
+//
+//    var _ = API("catalog", func() {
+//        quota.Plan("free", func() {
+//            quota.Limit(1000, quota.Day)
+//            quota.Limit(10000, quota.Month)
+//        })
+//        quota.Plan("pro", func() {
+//            quota.Limit(1000000, quota.Month)
+//        })
+//    })
+//
+func Plan(name string, fn func()) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if expr.Root.Plan(name) != nil {
+		eval.ReportError("plan %q is defined twice", name)
+		return
+	}
+	p := &expr.PlanExpr{Name: name}
+	if !eval.Execute(fn, p) {
+		return
+	}
+	expr.Root.Plans = append(expr.Root.Plans, p)
+}
+
+// Limit sets the maximum number of requests the API keys subscribed to the
+// enclosing plan may make to the metered methods during a period, Day or
+// Month.
+//
+// Limit must appear in a Plan expression.
+func Limit(requests int64, period string) {
+	p, ok := eval.Current().(*expr.PlanExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	p.Limits = append(p.Limits, &expr.LimitExpr{Requests: requests, Period: period})
+}
+
+// Metered makes the requests made to the methods of the API, of the enclosing
+// service or to the enclosing method count against the quotas of the plan of
+// their API key. The generated endpoints fail with the "quota_exceeded" error
+// mapped to the 429 Too Many Requests HTTP status once a quota is exhausted.
+//
+// Metered must appear in an API, Service or Method expression. In the API or a
+// Service it applies to the methods requiring an API key, a metered method
+// must require an API key. The usage report methods are not metered.
+//
+// Example:
+//
+//    var _ = Service("catalog", func() {
+//        Security(APIKeyAuth)
+//        quota.Metered()
+//    })
+//
+func Metered() {
+	m := &expr.MeteredExpr{}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		m.Service = actual
+	case *goaexpr.MethodExpr:
+		m.Method = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Metered = append(expr.Root.Metered, m)
+}
+
+// UsageReport adds the quota_usage method to the service which returns the
+// plan of the API key of the request and the usage of its quotas. The method
+// is exposed with the GET /quota/usage HTTP route and implemented by the
+// generated QuotaUsageReport function of the service package.
+//
+// UsageReport must appear in a Service expression. The method requires the
+// first API key scheme of the security requirements of the service, which
+// must be defined before UsageReport.
+//
+// Example:
+//
+//    var _ = Service("catalog", func() {
+//        Security(APIKeyAuth)
+//        quota.UsageReport()
+//    })
+//
+func UsageReport() {
+	svc, ok := eval.Current().(*goaexpr.ServiceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if expr.Root.UsageReport(svc.Name) != nil {
+		eval.ReportError("usage report is defined twice")
+		return
+	}
+	scheme := expr.APIKeyScheme(&goaexpr.MethodExpr{Service: svc})
+	if scheme == nil {
+		eval.ReportError("service does not require an API key")
+		return
+	}
+	expr.Root.UsageReports = append(expr.Root.UsageReports, &expr.UsageReportExpr{Service: svc, Scheme: scheme})
+
+	goadsl.Method(expr.UsageReportMethod, func() {
+		goadsl.Description("Returns the plan of the API key and the usage of its quotas.")
+		goadsl.Security(scheme)
+		goadsl.Payload(func() {
+			goadsl.APIKey(scheme.SchemeName, "key", goaexpr.String, "API key")
+			goadsl.Required("key")
+		})
+		goadsl.Result(func() {
+			goadsl.Attribute("plan", goaexpr.String, "Plan of the API key")
+			for _, period := range []struct{ Name, Label string }{{expr.Day, "daily"}, {expr.Month, "monthly"}} {
+				goadsl.Attribute(period.Label+"_limit", goaexpr.Int64, "Maximum number of requests per "+period.Name+", absent if the plan has no "+period.Label+" quota")
+				goadsl.Attribute(period.Label+"_used", goaexpr.Int64, "Number of requests made during the current "+period.Name)
+				goadsl.Attribute(period.Label+"_remaining", goaexpr.Int64, "Number of requests that may still be made during the current "+period.Name)
+				goadsl.Attribute(period.Label+"_reset", goaexpr.String, "Time at which the "+period.Label+" quota is reset", func() {
+					goadsl.Format(goaexpr.FormatDateTime)
+				})
+			}
+			goadsl.Required("plan")
+		})
+		goadsl.HTTP(func() {
+			goadsl.GET("/quota/usage")
+		})
+	})
+}
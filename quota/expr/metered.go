@@ -0,0 +1,147 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// QuotaExceededErrorName is the name of the error returned by the
+	// metered endpoints when a quota is exhausted.
+	QuotaExceededErrorName = "quota_exceeded"
+
+	// UsageReportMethod is the name of the method returning the usage of
+	// the quotas of the API key of the request.
+	UsageReportMethod = "quota_usage"
+)
+
+type (
+	// MeteredExpr describes the API, service or method whose requests
+	// count against the quotas of the plan of their API key.
+	MeteredExpr struct {
+		// Service is the metered service, nil if the whole API is
+		// metered.
+		Service *expr.ServiceExpr
+		// Method is the metered method, nil if all the methods of the
+		// service are metered.
+		Method *expr.MethodExpr
+	}
+
+	// UsageReportExpr describes the method returning the usage of the
+	// quotas of the API key of the request.
+	UsageReportExpr struct {
+		// Service is the service defining the method.
+		Service *expr.ServiceExpr
+		// Scheme is the API key scheme securing the method.
+		Scheme *expr.SchemeExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (m *MeteredExpr) EvalName() string {
+	switch {
+	case m.Method != nil:
+		return fmt.Sprintf("quota metering of %s", m.Method.EvalName())
+	case m.Service != nil:
+		return fmt.Sprintf("quota metering of %s", m.Service.EvalName())
+	}
+	return "API quota metering"
+}
+
+// Prepare defines the quota_exceeded error in the services of the metered
+// methods and maps it to the 429 Too Many Requests HTTP status unless the
+// design already does. It runs once the HTTP endpoints have inherited the
+// errors of their service.
+func (m *MeteredExpr) Prepare() {
+	for _, meth := range m.methods() {
+		if meth.Error(QuotaExceededErrorName) == nil {
+			meth.Service.Errors = append(meth.Service.Errors, &expr.ErrorExpr{
+				AttributeExpr: &expr.AttributeExpr{Type: expr.ErrorResult},
+				Name:          QuotaExceededErrorName,
+			})
+		}
+		if expr.Root.API == nil || expr.Root.API.HTTP == nil {
+			continue
+		}
+		svc := expr.Root.API.HTTP.Service(meth.Service.Name)
+		if svc == nil {
+			continue
+		}
+		e := svc.Endpoint(meth.Name)
+		if e == nil {
+			continue
+		}
+		mapped := false
+		for _, herr := range e.HTTPErrors {
+			if herr.Name == QuotaExceededErrorName {
+				mapped = true
+				break
+			}
+		}
+		if mapped {
+			continue
+		}
+		resp := &expr.HTTPResponseExpr{StatusCode: expr.StatusTooManyRequests, Parent: e}
+		resp.Prepare()
+		e.HTTPErrors = append(e.HTTPErrors, &expr.HTTPErrorExpr{Name: QuotaExceededErrorName, Response: resp})
+	}
+}
+
+// Validate makes sure the design defines plans and that a metered method
+// requires an API key.
+func (m *MeteredExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if len(Root.Plans) == 0 {
+		verr.Add(m, "no plan is defined")
+	}
+	if m.Method != nil && APIKeyScheme(m.Method) == nil {
+		verr.Add(m, "method does not require an API key")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Applies returns true if the given method belongs to the metered API,
+// service or method.
+func (m *MeteredExpr) Applies(meth *expr.MethodExpr) bool {
+	switch {
+	case m.Method != nil:
+		return m.Method == meth
+	case m.Service != nil:
+		return m.Service == meth.Service
+	}
+	return true
+}
+
+// methods returns the metered methods: the methods requiring an API key
+// other than the usage reports.
+func (m *MeteredExpr) methods() []*expr.MethodExpr {
+	var ms []*expr.MethodExpr
+	for _, svc := range expr.Root.Services {
+		for _, meth := range svc.Methods {
+			if m.Applies(meth) && Root.IsMetered(svc.Name, meth.Name) {
+				ms = append(ms, meth)
+			}
+		}
+	}
+	return ms
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (u *UsageReportExpr) EvalName() string {
+	return fmt.Sprintf("quota usage report of %s", u.Service.EvalName())
+}
+
+// Validate makes sure the design defines plans.
+func (u *UsageReportExpr) Validate() error {
+	if len(Root.Plans) == 0 {
+		verr := new(eval.ValidationErrors)
+		verr.Add(u, "no plan is defined")
+		return verr
+	}
+	return nil
+}
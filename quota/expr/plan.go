@@ -0,0 +1,74 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+const (
+	// Day is the period of the daily quotas.
+	Day = "day"
+	// Month is the period of the monthly quotas.
+	Month = "month"
+)
+
+type (
+	// PlanExpr describes the quotas of the API keys subscribed to a plan.
+	PlanExpr struct {
+		// Name is the plan name.
+		Name string
+		// Limits lists the quotas of the plan.
+		Limits []*LimitExpr
+	}
+
+	// LimitExpr describes the maximum number of requests made with an API
+	// key during a period.
+	LimitExpr struct {
+		// Requests is the maximum number of requests.
+		Requests int64
+		// Period is the period of the quota, Day or Month.
+		Period string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PlanExpr) EvalName() string {
+	return fmt.Sprintf("plan %q", p.Name)
+}
+
+// Validate makes sure the plan defines valid quotas.
+func (p *PlanExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if len(p.Limits) == 0 {
+		verr.Add(p, "plan defines no limit")
+	}
+	seen := make(map[string]bool)
+	for _, l := range p.Limits {
+		if l.Period != Day && l.Period != Month {
+			verr.Add(p, "invalid period %q, must be %q or %q", l.Period, Day, Month)
+			continue
+		}
+		if seen[l.Period] {
+			verr.Add(p, "%s limit is defined twice", l.Period)
+		}
+		seen[l.Period] = true
+		if l.Requests <= 0 {
+			verr.Add(p, "%s limit must be greater than 0", l.Period)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Limit returns the limit of the given period, nil if there is none.
+func (p *PlanExpr) Limit(period string) *LimitExpr {
+	for _, l := range p.Limits {
+		if l.Period == period {
+			return l
+		}
+	}
+	return nil
+}
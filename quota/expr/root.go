@@ -0,0 +1,132 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the quota plans and metered methods defined
+	// in the design.
+	RootExpr struct {
+		// Plans lists the plans in the order they are defined, the first
+		// plan is the default plan.
+		Plans []*PlanExpr
+		// Metered lists the API, services and methods whose requests
+		// count against the quotas.
+		Metered []*MeteredExpr
+		// UsageReports lists the usage report methods.
+		UsageReports []*UsageReportExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "quota plugin"
+}
+
+// WalkSets iterates over the plans, the metered expressions and the usage
+// reports.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	pexps := make(eval.ExpressionSet, len(r.Plans))
+	for i, p := range r.Plans {
+		pexps[i] = p
+	}
+	walk(pexps)
+	mexps := make(eval.ExpressionSet, len(r.Metered))
+	for i, m := range r.Metered {
+		mexps[i] = m
+	}
+	walk(mexps)
+	uexps := make(eval.ExpressionSet, len(r.UsageReports))
+	for i, u := range r.UsageReports {
+		uexps[i] = u
+	}
+	walk(uexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/quota/dsl"}
+}
+
+// Plan returns the plan with the given name, nil if there is none.
+func (r *RootExpr) Plan(name string) *PlanExpr {
+	for _, p := range r.Plans {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// IsMetered returns true if the requests made to the given method count
+// against the quotas: if the method, its service or the API is metered, the
+// method is not a usage report and it requires an API key.
+func (r *RootExpr) IsMetered(svc, method string) bool {
+	s := expr.Root.Service(svc)
+	if s == nil {
+		return false
+	}
+	m := s.Method(method)
+	if m == nil || (method == UsageReportMethod && r.UsageReport(svc) != nil) || APIKeyScheme(m) == nil {
+		return false
+	}
+	for _, me := range r.Metered {
+		if me.Applies(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// UsageReport returns the usage report of the given service, nil if there is
+// none.
+func (r *RootExpr) UsageReport(svc string) *UsageReportExpr {
+	for _, u := range r.UsageReports {
+		if u.Service.Name == svc {
+			return u
+		}
+	}
+	return nil
+}
+
+// APIKeyScheme returns the first API key scheme of the security requirements
+// of the given method, nil if there is none. The method inherits the
+// requirements of its service if it defines none.
+func APIKeyScheme(m *expr.MethodExpr) *expr.SchemeExpr {
+	reqs := m.Requirements
+	if len(reqs) == 0 && m.Service != nil {
+		reqs = m.Service.Requirements
+	}
+	for _, req := range reqs {
+		for _, s := range req.Schemes {
+			if s.Kind == expr.NoKind {
+				return nil
+			}
+		}
+	}
+	for _, req := range reqs {
+		for _, s := range req.Schemes {
+			if s.Kind == expr.APIKeyKind {
+				return s
+			}
+		}
+	}
+	return nil
+}
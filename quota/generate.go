@@ -0,0 +1,188 @@
+package quota
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/quota/expr"
+	"goa.design/plugins/v3/registry"
+)
+
+// pkgPath is the import path of the quota runtime package.
+const pkgPath = "goa.design/plugins/v3/quota"
+
+// Register the plugin Generator functions. The plugin runs after the security
+// plugin so that the quotas are consumed once the requests are authorized.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "quota",
+		Cmd:      "gen",
+		Generate: Generate,
+		After:    []string{"security"},
+	})
+}
+
+// Generate makes the endpoints of the metered methods consume the quotas of
+// the plan of the API key of the requests once they are authenticated and
+// defines the quota managers of the services. The services with a usage
+// report also get the function implementing it.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Metered) == 0 && len(expr.Root.UsageReports) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("quota", f) {
+			continue
+		}
+		endpointConsume(f)
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("quota", svc.Name) {
+					continue
+				}
+				if f := quotaFile(svc); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// quotaFile returns the file defining the quota manager of the given service,
+// nil if the service has no metered method and no usage report.
+func quotaFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	report := expr.Root.UsageReport(svc.Name)
+	if len(meteredMethods(svc)) == 0 && report == nil {
+		return nil
+	}
+	var plans []map[string]interface{}
+	for _, p := range expr.Root.Plans {
+		var limits []string
+		for _, l := range p.Limits {
+			limits = append(limits, fmt.Sprintf("{Requests: %d, Period: quota.%s}", l.Requests, codegen.Goify(l.Period, true)))
+		}
+		plans = append(plans, map[string]interface{}{"Name": p.Name, "Limits": strings.Join(limits, ", ")})
+	}
+	data := map[string]interface{}{
+		"ServiceName": svc.Name,
+		"Plans":       plans,
+		"DefaultPlan": expr.Root.Plans[0].Name,
+	}
+	sd := service.Services.Get(svc.Name)
+	imports := []*codegen.ImportSpec{{Path: pkgPath}}
+	sections := []*codegen.SectionTemplate{{Name: "quota-manager", Source: managerT, Data: data}}
+	if report != nil {
+		if m := sd.Method(expr.UsageReportMethod); m != nil {
+			imports = append([]*codegen.ImportSpec{{Path: "context"}, {Path: "time"}}, imports...)
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:   "quota-usage-report",
+				Source: usageReportT,
+				Data:   map[string]interface{}{"ServiceName": svc.Name, "Result": m.Result},
+			})
+		}
+	}
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "quota.go")
+	return &codegen.File{
+		Path:             path,
+		SectionTemplates: append([]*codegen.SectionTemplate{codegen.Header(svc.Name+" quota manager", sd.PkgName, imports)}, sections...),
+	}
+}
+
+// endpointConsume makes the service endpoints of the metered methods consume
+// the quotas of the API key of the request once it is authenticated and
+// authorized if f is the endpoints file of a service with such methods.
+func endpointConsume(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || len(meteredMethods(svc)) == 0 {
+		return
+	}
+	for _, s := range f.Section("endpoint-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["consumeQuota"] = consumeQuota
+		s.Source = strings.Replace(s.Source,
+			"{{- end }}\n{{- if .ServerStream }}",
+			"{{- with consumeQuota .ServiceName .Name $payload }}\n{{ . }}\n{{- end }}\n{{- end }}\n{{- if .ServerStream }}", 1)
+	}
+}
+
+// consumeQuota returns the statements consuming the quotas of the API key of
+// the request, the empty string if the given method is not metered. payload
+// is the name of the variable holding the payload.
+func consumeQuota(svc, method, payload string) string {
+	if !expr.Root.IsMetered(svc, method) {
+		return ""
+	}
+	scheme := expr.APIKeyScheme(goaexpr.Root.Service(svc).Method(method))
+	md := service.Services.Get(svc).Method(method)
+	for _, req := range md.Requirements {
+		for _, s := range req.Schemes {
+			if s.Type != "APIKey" || s.SchemeName != scheme.SchemeName {
+				continue
+			}
+			if s.CredPointer {
+				return fmt.Sprintf("\t\tif %[1]s.%[2]s != nil {\n\t\t\tif err = Quota.Consume(ctx, *%[1]s.%[2]s); err != nil {\n\t\t\t\treturn nil, err\n\t\t\t}\n\t\t}", payload, s.CredField)
+			}
+			return fmt.Sprintf("\t\tif err = Quota.Consume(ctx, %s.%s); err != nil {\n\t\t\treturn nil, err\n\t\t}", payload, s.CredField)
+		}
+	}
+	return ""
+}
+
+// meteredMethods returns the metered methods of the given service.
+func meteredMethods(svc *goaexpr.ServiceExpr) []*goaexpr.MethodExpr {
+	var ms []*goaexpr.MethodExpr
+	for _, m := range svc.Methods {
+		if expr.Root.IsMetered(svc.Name, m.Name) {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// input: map[string]interface{}{"ServiceName": string, "Plans": []map[string]interface{}, "DefaultPlan": string}
+const managerT = `{{ printf "Quota meters the requests made with the API keys to the %q service endpoints against the quotas of their plan. The API keys use the %q plan unless Lookup returns another plan. Set Store to a usage store shared by the service instances, the default store keeps the usage in memory." .ServiceName .DefaultPlan | comment }}
+var Quota = &quota.Manager{
+	Plans: map[string]*quota.Plan{
+	{{- range .Plans }}
+		{{ printf "%q" .Name }}: {Limits: []*quota.Limit{ {{- .Limits }}}},
+	{{- end }}
+	},
+	DefaultPlan: {{ printf "%q" .DefaultPlan }},
+	Store:       quota.NewMemoryUsageStore(),
+}
+`
+
+// input: map[string]interface{}{"ServiceName": string, "Result": string}
+const usageReportT = `{{ printf "QuotaUsageReport returns the plan of the given API key and the usage of its quotas. The quota_usage method of the %q service typically returns QuotaUsageReport(ctx, p.Key)." .ServiceName | comment }}
+func QuotaUsageReport(ctx context.Context, key string) (*{{ .Result }}, error) {
+	r, err := Quota.Report(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	res := &{{ .Result }}{Plan: r.Plan}
+	for _, u := range r.Usages {
+		limit, used, remaining, reset := u.Limit, u.Used, u.Remaining, u.Reset.Format(time.RFC3339)
+		switch u.Period {
+		case quota.Day:
+			res.DailyLimit, res.DailyUsed, res.DailyRemaining, res.DailyReset = &limit, &used, &remaining, &reset
+		case quota.Month:
+			res.MonthlyLimit, res.MonthlyUsed, res.MonthlyRemaining, res.MonthlyReset = &limit, &used, &remaining, &reset
+		}
+	}
+	return res, nil
+}
+`
@@ -0,0 +1,111 @@
+package quota_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/quota"
+	"goa.design/plugins/v3/quota/expr"
+	"goa.design/plugins/v3/quota/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Plans = nil
+	expr.Root.Metered = nil
+	expr.Root.UsageReports = nil
+	root := plugintest.RunDSL(t, testdata.CatalogDSL, expr.Root)
+	var fs []*codegen.File
+	for _, svc := range root.Services {
+		fs = append(fs, service.EndpointFile("goa.design/plugins/v3/quota/gen", svc))
+	}
+	fs, err := quota.Generate("goa.design/plugins/v3/quota/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Path, Golden string
+	}{
+		{"gen/catalog/endpoints.go", "catalog-endpoints.golden"},
+		{"gen/catalog/quota.go", "catalog-quota.golden"},
+		{"gen/admin/endpoints.go", "admin-endpoints.golden"},
+		{"gen/admin/quota.go", "admin-quota.golden"},
+	}
+	for _, c := range cases {
+		t.Run(c.Golden, func(t *testing.T) {
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, plugintest.File(t, fs, c.Path)))
+		})
+	}
+	statuses := []struct {
+		Service, Method string
+		Status          int
+	}{
+		{"catalog", "search", goaexpr.StatusTooManyRequests},
+		{"catalog", "export", goaexpr.StatusTooManyRequests},
+		{"catalog", "health", 0},
+		{"catalog", "quota_usage", 0},
+		{"admin", "purge", 0},
+		{"admin", "stats", goaexpr.StatusTooManyRequests},
+	}
+	for _, c := range statuses {
+		var status int
+		for _, herr := range root.API.HTTP.Service(c.Service).Endpoint(c.Method).HTTPErrors {
+			if herr.Name == quota.QuotaExceededErrorName {
+				status = herr.Response.StatusCode
+			}
+		}
+		if status != c.Status {
+			t.Errorf("%s.%s: got quota error status %d, expected %d", c.Service, c.Method, status, c.Status)
+		}
+	}
+	route := root.API.HTTP.Service("catalog").Endpoint("quota_usage").Routes[0]
+	if route.Method != "GET" || route.Path != "/quota/usage" {
+		t.Errorf("got usage report route %s %s, expected GET /quota/usage", route.Method, route.Path)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		Name  string
+		DSL   func()
+		Error string
+	}{
+		{"limit-requests", testdata.InvalidPlanDSL, `day limit must be greater than 0`},
+		{"limit-period", testdata.InvalidPlanDSL, `invalid period "week", must be "day" or "month"`},
+		{"limit-twice", testdata.InvalidPlanDSL, `month limit is defined twice`},
+		{"plan-limits", testdata.InvalidPlanDSL, `plan "empty": plan defines no limit`},
+		{"metered-api-key", testdata.InvalidPlanDSL, `method does not require an API key`},
+		{"no-plan", testdata.NoPlanDSL, `no plan is defined`},
+		{"plan-twice", testdata.InvalidUsageReportDSL, `plan "free" is defined twice`},
+		{"usage-report-api-key", testdata.InvalidUsageReportDSL, `service does not require an API key`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if err := runInvalidDSL(c.DSL); err == nil || !strings.Contains(err.Error(), c.Error) {
+				t.Errorf("got error %v, expected %q", err, c.Error)
+			}
+		})
+	}
+}
+
+func runInvalidDSL(dsl func()) error {
+	expr.Root.Plans = nil
+	expr.Root.Metered = nil
+	expr.Root.UsageReports = nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.GeneratedTypes = &goaexpr.GeneratedRoot{}
+	goaexpr.Root.API = goaexpr.NewAPIExpr("test api", func() {})
+	goaexpr.Root.API.Servers = []*goaexpr.ServerExpr{goaexpr.Root.API.DefaultServer()}
+	eval.Register(goaexpr.Root)
+	eval.Register(goaexpr.Root.GeneratedTypes)
+	eval.Register(expr.Root)
+	if !eval.Execute(dsl, nil) {
+		return eval.Context.Errors
+	}
+	return eval.RunDSL()
+}
@@ -0,0 +1,304 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/plugins/v3/quota/expr"
+)
+
+// QuotaExceededErrorName is the name of the error returned by the metered
+// endpoints when a quota of the plan of the API key is exhausted. The Metered
+// DSL maps it to the 429 Too Many Requests status.
+const QuotaExceededErrorName = expr.QuotaExceededErrorName
+
+// maxUsageEntries is the number of usages above which MemoryUsageStore
+// forgets the usage of the ended windows.
+const maxUsageEntries = 10000
+
+const (
+	// Day is the period of the quotas reset every day at midnight UTC.
+	Day Period = expr.Day
+	// Month is the period of the quotas reset on the first day of every
+	// month at midnight UTC.
+	Month Period = expr.Month
+)
+
+type (
+	// Period is the period of a quota, Day or Month.
+	Period string
+
+	// Window is the time window during which the usage of a quota is
+	// counted.
+	Window struct {
+		// Period is the period of the quota.
+		Period Period
+		// Start is the start of the window.
+		Start time.Time
+		// End is the end of the window, the quota is reset then.
+		End time.Time
+	}
+
+	// Limit is the maximum number of requests made with an API key during
+	// a period.
+	Limit struct {
+		// Requests is the maximum number of requests.
+		Requests int64
+		// Period is the period of the quota.
+		Period Period
+	}
+
+	// Plan lists the quotas of the API keys subscribed to a plan.
+	Plan struct {
+		// Limits lists the quotas of the plan.
+		Limits []*Limit
+	}
+
+	// PlanLookup returns the name of the plan of the API keys.
+	PlanLookup interface {
+		// Plan returns the name of the plan of the given API key, the
+		// empty string if the key has no plan.
+		Plan(ctx context.Context, key string) (string, error)
+	}
+
+	// PlanLookupFunc is a function implementing PlanLookup.
+	PlanLookupFunc func(ctx context.Context, key string) (string, error)
+
+	// UsageStore records the number of requests made with the API keys.
+	UsageStore interface {
+		// Increment adds n, which may be negative, to the usage of the
+		// given API key in the given window and returns the resulting
+		// usage. The usage may be forgotten once the window ends.
+		Increment(ctx context.Context, key string, w Window, n int64) (int64, error)
+		// Usage returns the usage of the given API key in the given
+		// window.
+		Usage(ctx context.Context, key string, w Window) (int64, error)
+	}
+
+	// MemoryUsageStore is a UsageStore keeping the usage in memory. It is
+	// not shared between the instances of a service.
+	MemoryUsageStore struct {
+		mu    sync.Mutex
+		usage map[usageKey]*usage
+		// limit is the number of usages above which the ended windows
+		// are forgotten.
+		limit int
+	}
+
+	// Manager meters the requests made with the API keys against the
+	// quotas of their plan.
+	Manager struct {
+		// Plans lists the plans indexed by name.
+		Plans map[string]*Plan
+		// DefaultPlan is the name of the plan of the API keys for which
+		// Lookup returns no plan.
+		DefaultPlan string
+		// Lookup returns the plan of the API keys. All the keys use the
+		// default plan if nil.
+		Lookup PlanLookup
+		// Store records the usage of the API keys.
+		Store UsageStore
+		// Now returns the current time, time.Now if nil.
+		Now func() time.Time
+	}
+
+	// Report describes the usage of the quotas of an API key.
+	Report struct {
+		// Plan is the name of the plan of the key.
+		Plan string
+		// Usages lists the usage of the quotas of the plan.
+		Usages []*Usage
+	}
+
+	// Usage describes the usage of a quota in the current window.
+	Usage struct {
+		// Period is the period of the quota.
+		Period Period
+		// Limit is the maximum number of requests.
+		Limit int64
+		// Used is the number of requests made in the current window.
+		Used int64
+		// Remaining is the number of requests that may still be made
+		// in the current window.
+		Remaining int64
+		// Reset is the time at which the quota is reset.
+		Reset time.Time
+	}
+
+	// usageKey identifies the usage of an API key in a window.
+	usageKey struct {
+		key    string
+		period Period
+		start  int64
+	}
+
+	// usage is the usage of an API key in a window.
+	usage struct {
+		count int64
+		end   time.Time
+	}
+)
+
+// Plan calls f(ctx, key).
+func (f PlanLookupFunc) Plan(ctx context.Context, key string) (string, error) {
+	return f(ctx, key)
+}
+
+// Window returns the window of the period containing the given time.
+func (p Period) Window(t time.Time) Window {
+	t = t.UTC()
+	w := Window{Period: p}
+	switch p {
+	case Month:
+		w.Start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		w.End = w.Start.AddDate(0, 1, 0)
+	default:
+		w.Start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		w.End = w.Start.AddDate(0, 0, 1)
+	}
+	return w
+}
+
+// NewMemoryUsageStore returns an empty usage store.
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{usage: make(map[usageKey]*usage), limit: maxUsageEntries}
+}
+
+// Increment adds n to the usage of the given API key in the given window. It
+// forgets the usage of the windows that ended before the given window started
+// once the store holds too many usages.
+func (s *MemoryUsageStore) Increment(_ context.Context, key string, w Window, n int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.usage == nil {
+		s.usage = make(map[usageKey]*usage)
+		s.limit = maxUsageEntries
+	}
+	k := usageKey{key: key, period: w.Period, start: w.Start.Unix()}
+	u, ok := s.usage[k]
+	if !ok {
+		if len(s.usage) >= s.limit {
+			s.prune(w.Start)
+		}
+		u = &usage{end: w.End}
+		s.usage[k] = u
+	}
+	u.count += n
+	return u.count, nil
+}
+
+// Usage returns the usage of the given API key in the given window.
+func (s *MemoryUsageStore) Usage(_ context.Context, key string, w Window) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.usage[usageKey{key: key, period: w.Period, start: w.Start.Unix()}]; ok {
+		return u.count, nil
+	}
+	return 0, nil
+}
+
+// prune forgets the usage of the windows that ended before start. It raises
+// the limit above the number of remaining usages so that the store does not
+// scan them again on the next increment when most windows are current.
+func (s *MemoryUsageStore) prune(start time.Time) {
+	for k, u := range s.usage {
+		if !u.end.After(start) {
+			delete(s.usage, k)
+		}
+	}
+	s.limit = 2 * len(s.usage)
+	if s.limit < maxUsageEntries {
+		s.limit = maxUsageEntries
+	}
+}
+
+// Consume records a request made with the given API key. It returns a
+// "quota_exceeded" error without recording the request if a quota of the plan
+// of the key is exhausted. The requests made without API key are not metered.
+func (m *Manager) Consume(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+	name, plan, err := m.plan(ctx, key)
+	if err != nil {
+		return err
+	}
+	now := m.now()
+	for i, l := range plan.Limits {
+		w := l.Period.Window(now)
+		used, err := m.Store.Increment(ctx, key, w, 1)
+		if err != nil {
+			m.rollback(ctx, key, plan.Limits[:i], now)
+			return goa.Fault("failed to record usage: %s", err)
+		}
+		if used > l.Requests {
+			m.rollback(ctx, key, plan.Limits[:i+1], now)
+			return goa.PermanentError(QuotaExceededErrorName, "%s quota of %d requests of plan %q exceeded, resets at %s",
+				l.Period, l.Requests, name, w.End.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// Report returns the usage of the quotas of the given API key.
+func (m *Manager) Report(ctx context.Context, key string) (*Report, error) {
+	name, plan, err := m.plan(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	r := &Report{Plan: name}
+	for _, l := range plan.Limits {
+		w := l.Period.Window(now)
+		used, err := m.Store.Usage(ctx, key, w)
+		if err != nil {
+			return nil, goa.Fault("failed to retrieve usage: %s", err)
+		}
+		remaining := l.Requests - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		r.Usages = append(r.Usages, &Usage{Period: l.Period, Limit: l.Requests, Used: used, Remaining: remaining, Reset: w.End})
+	}
+	return r, nil
+}
+
+// plan returns the name and the plan of the given API key.
+func (m *Manager) plan(ctx context.Context, key string) (string, *Plan, error) {
+	if m.Store == nil {
+		return "", nil, goa.Fault("quota manager has no usage store")
+	}
+	var name string
+	if m.Lookup != nil {
+		n, err := m.Lookup.Plan(ctx, key)
+		if err != nil {
+			return "", nil, goa.Fault("failed to look up plan: %s", err)
+		}
+		name = n
+	}
+	if name == "" {
+		name = m.DefaultPlan
+	}
+	plan, ok := m.Plans[name]
+	if !ok {
+		return "", nil, goa.Fault("unknown plan %q", name)
+	}
+	return name, plan, nil
+}
+
+// rollback removes the request recorded in the windows of the given limits.
+func (m *Manager) rollback(ctx context.Context, key string, limits []*Limit, now time.Time) {
+	for _, l := range limits {
+		m.Store.Increment(ctx, key, l.Period.Window(now), -1)
+	}
+}
+
+// now returns the current time.
+func (m *Manager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,203 @@
+package quota_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/plugins/v3/quota"
+)
+
+func TestWindow(t *testing.T) {
+	at := time.Date(2024, time.February, 29, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600))
+	cases := []struct {
+		Period     quota.Period
+		Start, End time.Time
+	}{
+		{quota.Day, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{quota.Month, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		w := c.Period.Window(at)
+		if !w.Start.Equal(c.Start) || !w.End.Equal(c.End) {
+			t.Errorf("%s: got window [%s, %s), expected [%s, %s)", c.Period, w.Start, w.End, c.Start, c.End)
+		}
+	}
+}
+
+func TestConsume(t *testing.T) {
+	now := time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC)
+	m := &quota.Manager{
+		Plans: map[string]*quota.Plan{
+			"free": {Limits: []*quota.Limit{{Requests: 2, Period: quota.Day}, {Requests: 3, Period: quota.Month}}},
+			"pro":  {Limits: []*quota.Limit{{Requests: 100, Period: quota.Month}}},
+		},
+		DefaultPlan: "free",
+		Lookup: quota.PlanLookupFunc(func(_ context.Context, key string) (string, error) {
+			if key == "pro-key" {
+				return "pro", nil
+			}
+			return "", nil
+		}),
+		Store: quota.NewMemoryUsageStore(),
+		Now:   func() time.Time { return now },
+	}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := m.Consume(ctx, "free-key"); err != nil {
+			t.Fatalf("request %d: got error %s, expected request to be allowed", i+1, err)
+		}
+	}
+	assertExceeded(t, m.Consume(ctx, "free-key"))
+
+	// The rejected request is not recorded: the monthly quota allows one
+	// more request the next day.
+	now = now.Add(24 * time.Hour)
+	if err := m.Consume(ctx, "free-key"); err != nil {
+		t.Fatalf("got error %s, expected request to be allowed the next day", err)
+	}
+	now = time.Date(2024, time.April, 30, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if err := m.Consume(ctx, "free-key"); err != nil {
+			t.Fatalf("request %d: got error %s, expected request to be allowed", i+1, err)
+		}
+	}
+	now = now.Add(24 * time.Hour)
+	if err := m.Consume(ctx, "free-key"); err != nil {
+		t.Fatalf("got error %s, expected request to be allowed", err)
+	}
+	if err := m.Consume(ctx, "pro-key"); err != nil {
+		t.Errorf("got error %s, expected pro key to use its own quota", err)
+	}
+	if err := m.Consume(ctx, ""); err != nil {
+		t.Errorf("got error %s, expected request without API key not to be metered", err)
+	}
+}
+
+func TestConsumeMonthly(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	m := &quota.Manager{
+		Plans:       map[string]*quota.Plan{"free": {Limits: []*quota.Limit{{Requests: 2, Period: quota.Day}, {Requests: 3, Period: quota.Month}}}},
+		DefaultPlan: "free",
+		Store:       quota.NewMemoryUsageStore(),
+		Now:         func() time.Time { return now },
+	}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := m.Consume(ctx, "key"); err != nil {
+			t.Fatalf("request %d: got error %s, expected request to be allowed", i+1, err)
+		}
+		now = now.Add(12 * time.Hour)
+	}
+	assertExceeded(t, m.Consume(ctx, "key"))
+	r, err := m.Report(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used := r.Usages[0].Used; used != 0 {
+		t.Errorf("got %d daily requests, expected the rejected request to be rolled back", used)
+	}
+}
+
+func TestReport(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 8, 0, 0, 0, time.UTC)
+	m := &quota.Manager{
+		Plans:       map[string]*quota.Plan{"free": {Limits: []*quota.Limit{{Requests: 2, Period: quota.Day}, {Requests: 10, Period: quota.Month}}}},
+		DefaultPlan: "free",
+		Store:       quota.NewMemoryUsageStore(),
+		Now:         func() time.Time { return now },
+	}
+	ctx := context.Background()
+	m.Consume(ctx, "key")
+	m.Consume(ctx, "key")
+	m.Consume(ctx, "key")
+	r, err := m.Report(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Plan != "free" || len(r.Usages) != 2 {
+		t.Fatalf("got plan %q with %d usages, expected plan \"free\" with 2 usages", r.Plan, len(r.Usages))
+	}
+	expected := []quota.Usage{
+		{Period: quota.Day, Limit: 2, Used: 2, Remaining: 0, Reset: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)},
+		{Period: quota.Month, Limit: 10, Used: 2, Remaining: 8, Reset: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, u := range r.Usages {
+		e := expected[i]
+		if u.Period != e.Period || u.Limit != e.Limit || u.Used != e.Used || u.Remaining != e.Remaining || !u.Reset.Equal(e.Reset) {
+			t.Errorf("got usage %+v, expected %+v", *u, e)
+		}
+	}
+}
+
+func TestManagerErrors(t *testing.T) {
+	ctx := context.Background()
+	m := &quota.Manager{
+		Plans:       map[string]*quota.Plan{"free": {Limits: []*quota.Limit{{Requests: 1, Period: quota.Day}}}},
+		DefaultPlan: "free",
+		Lookup: quota.PlanLookupFunc(func(_ context.Context, key string) (string, error) {
+			if key == "broken" {
+				return "", errors.New("lookup failed")
+			}
+			return "gold", nil
+		}),
+		Store: quota.NewMemoryUsageStore(),
+	}
+	if err := m.Consume(ctx, "key"); err == nil {
+		t.Error("got no error, expected unknown plan to fail")
+	}
+	if err := m.Consume(ctx, "broken"); err == nil {
+		t.Error("got no error, expected lookup error")
+	}
+	m.Lookup = nil
+	m.Store = nil
+	if err := m.Consume(ctx, "key"); err == nil {
+		t.Error("got no error, expected missing store to fail")
+	}
+}
+
+func TestMemoryUsageStore(t *testing.T) {
+	ctx := context.Background()
+	s := quota.NewMemoryUsageStore()
+	now := time.Now()
+	day, month := quota.Day.Window(now), quota.Month.Window(now)
+	s.Increment(ctx, "key", day, 2)
+	s.Increment(ctx, "key", month, 5)
+	s.Increment(ctx, "other", day, 1)
+	if n, _ := s.Increment(ctx, "key", day, -1); n != 1 {
+		t.Errorf("got daily usage %d, expected 1", n)
+	}
+	if n, _ := s.Usage(ctx, "key", month); n != 5 {
+		t.Errorf("got monthly usage %d, expected 5", n)
+	}
+	if n, _ := s.Usage(ctx, "unknown", day); n != 0 {
+		t.Errorf("got usage %d of unknown key, expected 0", n)
+	}
+	past := quota.Day.Window(now.AddDate(0, 0, -2))
+	s.Increment(ctx, "key", past, 3)
+	s.Increment(ctx, "key", day, 1)
+	if n, _ := s.Usage(ctx, "key", past); n != 3 {
+		t.Errorf("got usage %d of ended window, expected it to be kept until the store is full", n)
+	}
+	// Fill the store up to the number of usages that triggers the pruning.
+	for i := 0; i < 10000; i++ {
+		s.Increment(ctx, fmt.Sprintf("key-%d", i), day, 1)
+	}
+	if n, _ := s.Usage(ctx, "key", past); n != 0 {
+		t.Errorf("got usage %d of ended window, expected it to be forgotten", n)
+	}
+	if n, _ := s.Usage(ctx, "key", day); n != 2 {
+		t.Errorf("got daily usage %d, expected 2 in the current window", n)
+	}
+}
+
+func assertExceeded(t *testing.T, err error) {
+	t.Helper()
+	var serr *goa.ServiceError
+	if !errors.As(err, &serr) || serr.Name != quota.QuotaExceededErrorName {
+		t.Fatalf("got error %v, expected %q error", err, quota.QuotaExceededErrorName)
+	}
+}
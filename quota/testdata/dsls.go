@@ -0,0 +1,126 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	quota "goa.design/plugins/v3/quota/dsl"
+)
+
+var CatalogDSL = func() {
+	var APIKeyAuth = APIKeySecurity("api_key")
+	var JWT = JWTSecurity("jwt")
+	API("catalog", func() {
+		quota.Plan("free", func() {
+			quota.Limit(1000, quota.Day)
+			quota.Limit(10000, quota.Month)
+		})
+		quota.Plan("pro", func() {
+			quota.Limit(1000000, quota.Month)
+		})
+	})
+	Service("catalog", func() {
+		Security(APIKeyAuth)
+		quota.Metered()
+		quota.UsageReport()
+		Method("search", func() {
+			Payload(func() {
+				APIKey("api_key", "key", String)
+				Attribute("query", String)
+				Required("key")
+			})
+			Result(ArrayOf(String))
+			HTTP(func() {
+				GET("/search")
+				Param("query")
+			})
+		})
+		Method("export", func() {
+			Payload(func() {
+				APIKey("api_key", "key", String)
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/export")
+			})
+		})
+		Method("health", func() {
+			NoSecurity()
+			Result(String)
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+	Service("admin", func() {
+		Security(JWT)
+		Method("purge", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				DELETE("/cache")
+			})
+		})
+		Method("stats", func() {
+			Security(APIKeyAuth)
+			quota.Metered()
+			Payload(func() {
+				APIKey("api_key", "key", String)
+				Required("key")
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/stats")
+			})
+		})
+	})
+}
+
+var InvalidPlanDSL = func() {
+	API("catalog", func() {
+		quota.Plan("free", func() {
+			quota.Limit(0, quota.Day)
+			quota.Limit(100, "week")
+			quota.Limit(1000, quota.Month)
+			quota.Limit(2000, quota.Month)
+		})
+		quota.Plan("empty", func() {})
+	})
+	Service("catalog", func() {
+		Method("list", func() {
+			quota.Metered()
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
+
+var NoPlanDSL = func() {
+	var APIKeyAuth = APIKeySecurity("api_key")
+	Service("catalog", func() {
+		Security(APIKeyAuth)
+		quota.Metered()
+		Method("list", func() {
+			Payload(func() {
+				APIKey("api_key", "key", String)
+			})
+			HTTP(func() {
+				GET("/items")
+			})
+		})
+	})
+}
+
+var InvalidUsageReportDSL = func() {
+	API("catalog", func() {
+		quota.Plan("free", func() {
+			quota.Limit(1000, quota.Day)
+		})
+		quota.Plan("free", func() {
+			quota.Limit(2000, quota.Day)
+		})
+	})
+	Service("admin", func() {
+		quota.UsageReport()
+	})
+}
@@ -0,0 +1,136 @@
+package redact
+
+import (
+	"sort"
+
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/redact/expr"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// dataMap is the inventory of the personal data received and returned
+	// by the endpoints.
+	dataMap struct {
+		// API is the name of the API.
+		API string `json:"api"`
+		// Categories maps the categories of personal data to the
+		// endpoints receiving or returning them, e.g. "users.signup".
+		Categories map[string][]string `json:"categories"`
+		// Endpoints lists the endpoints handling personal data in the
+		// order they are defined.
+		Endpoints []*endpointData `json:"endpoints"`
+	}
+
+	// endpointData describes the personal data handled by an endpoint.
+	endpointData struct {
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the method.
+		Method string `json:"method"`
+		// Routes lists the HTTP routes of the endpoint if any.
+		Routes []*route `json:"routes,omitempty"`
+		// Receives lists the payload attributes holding personal data.
+		Receives []*fieldData `json:"receives,omitempty"`
+		// Returns lists the result attributes holding personal data.
+		Returns []*fieldData `json:"returns,omitempty"`
+	}
+
+	// route is a HTTP route.
+	route struct {
+		// Method is the HTTP method.
+		Method string `json:"method"`
+		// Path is the full path including the API and service base
+		// paths.
+		Path string `json:"path"`
+	}
+
+	// fieldData describes an attribute holding personal data.
+	fieldData struct {
+		// Path is the path to the attribute, e.g. "profile.phone" or
+		// "aliases[].phone".
+		Path string `json:"path"`
+		// Categories lists the categories of the personal data.
+		Categories []string `json:"categories"`
+		// Sensitive is true if the attribute is masked.
+		Sensitive bool `json:"sensitive"`
+	}
+)
+
+// buildDataMap returns the inventory of the personal data received and
+// returned by the endpoints of the services for which the plugin is enabled.
+func buildDataMap(r *goaexpr.RootExpr) *dataMap {
+	dm := &dataMap{API: r.API.Name, Categories: make(map[string][]string), Endpoints: []*endpointData{}}
+	for _, svc := range r.Services {
+		if !config.Enabled("redact", svc.Name) {
+			continue
+		}
+		for _, m := range svc.Methods {
+			e := &endpointData{
+				Service:  svc.Name,
+				Method:   m.Name,
+				Receives: personalData(m.Payload, m.StreamingPayload),
+				Returns:  personalData(m.Result),
+			}
+			if len(e.Receives) == 0 && len(e.Returns) == 0 {
+				continue
+			}
+			e.Routes = routes(r, m)
+			dm.Endpoints = append(dm.Endpoints, e)
+			name := svc.Name + "." + m.Name
+			seen := make(map[string]bool)
+			for _, f := range append(e.Receives, e.Returns...) {
+				for _, c := range f.Categories {
+					if !seen[c] {
+						seen[c] = true
+						dm.Categories[c] = append(dm.Categories[c], name)
+					}
+				}
+			}
+		}
+	}
+	return dm
+}
+
+// personalData returns the attributes of the given attributes holding
+// personal data. An attribute nested in a user type used more than once is
+// listed with each of its paths.
+func personalData(atts ...*goaexpr.AttributeExpr) []*fieldData {
+	var fields []*fieldData
+	for _, att := range atts {
+		walk.Attribute(att, func(path string, a *goaexpr.AttributeExpr) error {
+			cats := expr.Categories(a)
+			if len(cats) == 0 {
+				return nil
+			}
+			sorted := append([]string(nil), cats...)
+			sort.Strings(sorted)
+			fields = append(fields, &fieldData{Path: path, Categories: sorted, Sensitive: expr.Sensitive(a)})
+			return nil
+		})
+	}
+	return fields
+}
+
+// routes returns the HTTP routes of the given method.
+func routes(r *goaexpr.RootExpr, m *goaexpr.MethodExpr) []*route {
+	if r.API == nil || r.API.HTTP == nil {
+		return nil
+	}
+	svc := r.API.HTTP.Service(m.Service.Name)
+	if svc == nil {
+		return nil
+	}
+	e := svc.Endpoint(m.Name)
+	if e == nil {
+		return nil
+	}
+	var rts []*route
+	for _, rt := range e.Routes {
+		for _, p := range rt.FullPaths() {
+			rts = append(rts, &route{Method: rt.Method, Path: p})
+		}
+	}
+	return rts
+}
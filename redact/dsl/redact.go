@@ -0,0 +1,75 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/redact/expr"
+
+	// Register code generators for the redact plugin
+	_ "goa.design/plugins/v3/redact"
+)
+
+// Sensitive marks the attribute as holding sensitive data such as personally
+// identifiable information. The examples of the attribute are masked, the
+// validation errors do not include its value and the generated Redacted
+// methods and LogRedacted endpoint middlewares mask it.
+//
+// Sensitive must appear in an Attribute expression.
+//
+// Example:
+//
+//    import redact "goa.design/plugins/v3/redact/dsl"
+//
+//    var User = Type("User", func() {
+//        Attribute("email", String, func() {
+//            Format(FormatEmail)
+//            redact.Sensitive()
+//        })
+//    })
+//
+func Sensitive() {
+	att, ok := eval.Current().(*goaexpr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	if _, ok := att.Meta[expr.SensitiveKey]; ok {
+		return
+	}
+	att.Meta[expr.SensitiveKey] = nil
+	expr.Root.Sensitives = append(expr.Root.Sensitives, &expr.SensitiveExpr{Attribute: att})
+}
+
+// Classify sets the categories of personal data held by the attribute, e.g.
+// "contact" or "financial". The categories appear in the generated data map
+// which lists the personal data received and returned by each endpoint.
+// Classify does not mask the attribute, use Sensitive as well to do so. It is
+// equivalent to Meta("redact:categories", categories...).
+//
+// Classify must appear in an Attribute expression.
+//
+// Example:
+//
+//    Attribute("email", String, func() {
+//        redact.Classify("contact")
+//        redact.Sensitive()
+//    })
+//
+func Classify(categories ...string) {
+	att, ok := eval.Current().(*goaexpr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(categories) == 0 {
+		eval.ReportError("at least one category is required")
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	att.Meta[expr.CategoriesKey] = append(att.Meta[expr.CategoriesKey], categories...)
+}
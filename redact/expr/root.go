@@ -0,0 +1,61 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the sensitive attributes defined in the
+	// design.
+	RootExpr struct {
+		// Sensitives lists the sensitive attributes in the order they
+		// appear in the design.
+		Sensitives []*SensitiveExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "redact plugin"
+}
+
+// WalkSets iterates over the sensitive attributes.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	exps := make(eval.ExpressionSet, len(r.Sensitives))
+	for i, s := range r.Sensitives {
+		exps[i] = s
+	}
+	walk(exps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/redact/dsl"}
+}
+
+// Names returns the set of the names of the sensitive attributes.
+func (r *RootExpr) Names() map[string]bool {
+	names := make(map[string]bool, len(r.Sensitives))
+	for _, s := range r.Sensitives {
+		if s.Name != "" {
+			names[s.Name] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,142 @@
+package expr
+
+import (
+	"strings"
+
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// SensitiveKey is the meta key recording that an attribute holds
+	// sensitive data.
+	SensitiveKey = "redact:sensitive"
+
+	// CategoriesKey is the meta key listing the categories of personal
+	// data held by an attribute.
+	CategoriesKey = "redact:categories"
+
+	// Uncategorized is the category of the sensitive attributes which are
+	// not classified.
+	Uncategorized = "uncategorized"
+
+	// Mask is the character the masked examples are made of.
+	Mask = "*"
+
+	// maskLength is the length of the masked examples when the attribute
+	// length is not constrained.
+	maskLength = 8
+)
+
+type (
+	// SensitiveExpr describes an attribute holding sensitive data such as
+	// personally identifiable information.
+	SensitiveExpr struct {
+		// Attribute is the attribute.
+		Attribute *expr.AttributeExpr
+		// Name is the name of the attribute in its parent object, empty
+		// if the attribute is not the attribute of an object. It is set
+		// when the design is prepared.
+		Name string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (s *SensitiveExpr) EvalName() string {
+	if s.Name != "" {
+		return "sensitive attribute " + s.Name
+	}
+	return "sensitive attribute"
+}
+
+// Prepare records the name of the attribute and replaces the examples of the
+// string attributes with a masked value so that the sensitive data defined in
+// the design or generated from it never appears in the documentation. It runs
+// before goa finalizes the design so that the examples are copied together
+// with the attributes into the transport types. The attributes validated with
+// Enum keep their examples.
+func (s *SensitiveExpr) Prepare() {
+	att := s.Attribute
+	s.Name = attributeName(att)
+	if att.Type.Kind() != expr.StringKind {
+		return
+	}
+	n := maskLength
+	if v := att.Validation; v != nil {
+		if len(v.Values) > 0 {
+			return
+		}
+		if v.MinLength != nil && *v.MinLength > n {
+			n = *v.MinLength
+		}
+		if v.MaxLength != nil && *v.MaxLength < n {
+			n = *v.MaxLength
+		}
+	}
+	att.UserExamples = []*expr.ExampleExpr{{Summary: "redacted", Value: strings.Repeat(Mask, n)}}
+}
+
+// Sensitive returns true if the given attribute holds sensitive data.
+func Sensitive(att *expr.AttributeExpr) bool {
+	_, ok := att.Meta[SensitiveKey]
+	return ok
+}
+
+// Categories returns the categories of personal data held by the given
+// attribute: the categories listed by the redact:categories meta if any,
+// Uncategorized if the attribute is sensitive, nil otherwise.
+func Categories(att *expr.AttributeExpr) []string {
+	if cats := att.Meta[CategoriesKey]; len(cats) > 0 {
+		return cats
+	}
+	if Sensitive(att) {
+		return []string{Uncategorized}
+	}
+	return nil
+}
+
+// attributeName returns the name of the given attribute in the object of the
+// design defining it, the empty string if there is none.
+func attributeName(att *expr.AttributeExpr) string {
+	var parents []*expr.AttributeExpr
+	for _, t := range expr.Root.Types {
+		parents = append(parents, t.Attribute())
+	}
+	for _, t := range expr.Root.ResultTypes {
+		parents = append(parents, t.Attribute())
+	}
+	for _, svc := range expr.Root.Services {
+		for _, m := range svc.Methods {
+			parents = append(parents, m.Payload, m.StreamingPayload, m.Result)
+			for _, e := range m.Errors {
+				parents = append(parents, e.AttributeExpr)
+			}
+		}
+	}
+	seen := make(map[*expr.AttributeExpr]bool)
+	var find func(p *expr.AttributeExpr) string
+	find = func(p *expr.AttributeExpr) string {
+		if p == nil || seen[p] {
+			return ""
+		}
+		seen[p] = true
+		obj, ok := p.Type.(*expr.Object)
+		if !ok {
+			return ""
+		}
+		for _, nat := range *obj {
+			if nat.Attribute == att {
+				return nat.Name
+			}
+			if name := find(nat.Attribute); name != "" {
+				return name
+			}
+		}
+		return ""
+	}
+	for _, p := range parents {
+		if name := find(p); name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+package redact
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/redact/expr"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+// pkgPath is the import path of the package implementing the redaction
+// helpers.
+const pkgPath = "goa.design/plugins/v3/redact"
+
+var (
+	// valueErrorRegex matches the calls to the goa functions building the
+	// validation errors which include the invalid value. It captures the
+	// function name, the error context and the value.
+	valueErrorRegex = regexp.MustCompile(`goa\.(InvalidEnumValueError|InvalidRangeError|InvalidLengthError)\("([^"]*)", ([^,]+),`)
+
+	// validateRegex matches the calls to the goa functions validating the
+	// values whose errors include the invalid value. It captures the
+	// function name and the error context.
+	validateRegex = regexp.MustCompile(`goa\.(ValidateFormat|ValidatePattern)\("([^"]*)",`)
+
+	// redactedRegex matches the uses of the redact package in the rewritten
+	// validation code.
+	redactedRegex = regexp.MustCompile(`redact\.(Redacted\b|ValidateFormat\(|ValidatePattern\()`)
+
+	// exprPkgPath is the import path of the goa design expressions which
+	// are not walked when rewriting the section data.
+	exprPkgPath = reflect.TypeOf(goaexpr.AttributeExpr{}).PkgPath()
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "redact",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate makes the generated validation code omit the values of the
+// sensitive attributes from the validation errors. It also produces a
+// redact.go file in the package of each service whose payloads or results
+// hold sensitive attributes which defines the methods masking them and an
+// endpoint middleware logging the masked payloads and results. Finally it
+// produces the datamap.json inventory of the personal data received and
+// returned by the endpoints.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.EnabledAny("redact") {
+		return files, nil
+	}
+	if len(expr.Root.Sensitives) > 0 {
+		names := expr.Root.Names()
+		for _, f := range files {
+			if config.EnabledFile("redact", f) {
+				redactValidations(f, names)
+			}
+		}
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("redact", svc.Name) {
+					continue
+				}
+				if f := redactFile(genpkg, svc); f != nil {
+					files = append(files, f)
+				}
+			}
+			path := filepath.Join(codegen.Gendir, "redact", "datamap.json")
+			files = append(files, stream.File(path, "redact-datamap", buildDataMap(r), stream.IndentedJSON))
+		}
+	}
+	return files, nil
+}
+
+// redactValidations rewrites the validation code of the given Go file so that
+// the errors reporting invalid values of the attributes with the given names
+// do not include the values. The validation code is computed by goa when
+// building the section data so the strings of the data are rewritten. The
+// files may share the section data, e.g. the server and client types files, so
+// the redact package is imported whenever the code uses it, even if it was
+// rewritten while processing another file.
+func redactValidations(f *codegen.File, names map[string]bool) {
+	if filepath.Ext(f.Path) != ".go" || len(f.SectionTemplates) < 2 {
+		return
+	}
+	changed := false
+	rewrite := func(code string) string {
+		if !strings.Contains(code, "goa.") {
+			return code
+		}
+		res := valueErrorRegex.ReplaceAllStringFunc(code, func(call string) string {
+			m := valueErrorRegex.FindStringSubmatch(call)
+			if !names[attributeName(m[2])] {
+				return call
+			}
+			return "goa." + m[1] + "(\"" + m[2] + "\", redact.Redacted,"
+		})
+		res = validateRegex.ReplaceAllStringFunc(res, func(call string) string {
+			m := validateRegex.FindStringSubmatch(call)
+			if !names[attributeName(m[2])] {
+				return call
+			}
+			return "redact." + m[1] + "(\"" + m[2] + "\","
+		})
+		if redactedRegex.MatchString(res) {
+			changed = true
+		}
+		return res
+	}
+	for _, s := range f.SectionTemplates[1:] {
+		s.Source = rewrite(s.Source)
+		if s.Data != nil {
+			rewriteStrings(reflect.ValueOf(s.Data), rewrite, make(map[uintptr]bool))
+		}
+	}
+	if changed {
+		codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Path: pkgPath})
+	}
+}
+
+// attributeName returns the name of the attribute validated in the given
+// error context, e.g. "password" for "body.user.password" or "tags" for
+// "body.tags[0]".
+func attributeName(context string) string {
+	if i := strings.LastIndex(context, "."); i >= 0 {
+		context = context[i+1:]
+	}
+	if i := strings.Index(context, "["); i >= 0 {
+		context = context[:i]
+	}
+	return context
+}
+
+// rewriteStrings applies fn to the settable strings held by v recursively. It
+// does not walk the goa design expressions.
+func rewriteStrings(v reflect.Value, fn func(string) string, seen map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] || v.Elem().Type().PkgPath() == exprPkgPath {
+			return
+		}
+		seen[v.Pointer()] = true
+		rewriteStrings(v.Elem(), fn, seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if e := v.Elem(); e.Kind() == reflect.String {
+			if v.CanSet() {
+				v.Set(reflect.ValueOf(fn(e.String())))
+			}
+		} else {
+			rewriteStrings(e, fn, seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				rewriteStrings(f, fn, seen)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			rewriteStrings(v.Index(i), fn, seen)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			e := v.MapIndex(k)
+			if e.Kind() == reflect.Interface && !e.IsNil() {
+				e = e.Elem()
+			}
+			if e.Kind() == reflect.String {
+				v.SetMapIndex(k, reflect.ValueOf(fn(e.String())).Convert(v.Type().Elem()))
+			} else {
+				rewriteStrings(e, fn, seen)
+			}
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(fn(v.String()))
+		}
+	}
+}
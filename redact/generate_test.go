@@ -0,0 +1,194 @@
+package redact_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/redact"
+	"goa.design/plugins/v3/redact/expr"
+	"goa.design/plugins/v3/redact/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	expr.Root.Sensitives = nil
+	root := plugintest.RunDSL(t, testdata.RedactDSL, expr.Root)
+	fs := httpcodegen.ServerTypeFiles("goa.design/plugins/v3/redact/gen", root)
+	fs, err := redact.Generate("goa.design/plugins/v3/redact/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugintest.Golden(t, "users-redact.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/users/redact.go")))
+	plugintest.Golden(t, "datamap.json", plugintest.Render(t, plugintest.File(t, fs, "gen/redact/datamap.json")))
+
+	code := plugintest.Render(t, plugintest.File(t, fs, "gen/http/users/server/types.go"))
+	for _, s := range []string{
+		`"goa.design/plugins/v3/redact"`,
+		`redact.ValidateFormat("body.email", *body.Email, goa.FormatEmail)`,
+		`goa.InvalidLengthError("body.password", redact.Redacted, utf8.RuneCountInString(*body.Password), 12, true)`,
+		`goa.InvalidLengthError("body.name", *body.Name, utf8.RuneCountInString(*body.Name), 2, true)`,
+	} {
+		if !strings.Contains(code, s) {
+			t.Errorf("server types do not contain %s", s)
+		}
+	}
+}
+
+// TestGenerateCompiles type checks the generated packages. The server and
+// client types files share the section data so both must import the redact
+// package.
+func TestGenerateCompiles(t *testing.T) {
+	const genpkg = "goa.design/plugins/v3/redact/gen"
+	service.Services = make(service.ServicesData)
+	expr.Root.Sensitives = nil
+	root := plugintest.RunDSL(t, testdata.RedactDSL, expr.Root)
+	svc := root.Service("users")
+	fs := []*codegen.File{service.File(genpkg, svc), service.EndpointFile(genpkg, svc), service.ViewsFile(genpkg, svc)}
+	fs = append(fs, httpcodegen.PathFiles(root)...)
+	fs = append(fs, httpcodegen.ServerFiles(genpkg, root)...)
+	fs = append(fs, httpcodegen.ServerTypeFiles(genpkg, root)...)
+	fs = append(fs, httpcodegen.ClientFiles(genpkg, root)...)
+	fs = append(fs, httpcodegen.ClientTypeFiles(genpkg, root)...)
+	fs, err := redact.Generate(genpkg, []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	imp := &genImporter{
+		fset:  fset,
+		dir:   dir,
+		src:   importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+		files: make(map[string][]*ast.File),
+		pkgs:  make(map[string]*types.Package),
+	}
+	for _, f := range fs {
+		if filepath.Ext(f.Path) != ".go" {
+			continue
+		}
+		file, err := parser.ParseFile(fset, f.Path, plugintest.Render(t, f), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := genpkg + strings.TrimPrefix(filepath.ToSlash(filepath.Dir(f.Path)), "gen")
+		imp.files[path] = append(imp.files[path], file)
+	}
+	for _, path := range []string{genpkg + "/users", genpkg + "/http/users/server", genpkg + "/http/users/client"} {
+		if _, err := imp.Import(path); err != nil {
+			t.Errorf("%s: %s", path, err)
+		}
+	}
+}
+
+// genImporter type checks the generated packages and imports the other
+// packages from source.
+type genImporter struct {
+	fset  *token.FileSet
+	dir   string
+	src   types.ImporterFrom
+	files map[string][]*ast.File
+	pkgs  map[string]*types.Package
+}
+
+func (i *genImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.pkgs[path]; ok {
+		return pkg, nil
+	}
+	files, ok := i.files[path]
+	if !ok {
+		return i.src.ImportFrom(path, i.dir, 0)
+	}
+	conf := types.Config{Importer: i}
+	pkg, err := conf.Check(path, i.fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	i.pkgs[path] = pkg
+	return pkg, nil
+}
+
+func TestGenerateServicePluginMeta(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	expr.Root.Sensitives = nil
+	root := plugintest.RunDSL(t, testdata.ServicePluginMetaDSL, expr.Root)
+	fs := httpcodegen.ServerTypeFiles("goa.design/plugins/v3/redact/gen", root)
+	fs, err := redact.Generate("goa.design/plugins/v3/redact/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := plugintest.Render(t, plugintest.File(t, fs, "gen/http/users/server/types.go"))
+	if !strings.Contains(code, "redact.Redacted") {
+		t.Error("server types do not redact the password although the plugin is on for the service")
+	}
+}
+
+func TestDataMapReusedType(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	expr.Root.Sensitives = nil
+	root := plugintest.RunDSL(t, testdata.RedactDSL, expr.Root)
+	fs, err := redact.Generate("goa.design/plugins/v3/redact/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dm struct {
+		Endpoints []struct {
+			Method  string
+			Returns []struct{ Path string }
+		}
+	}
+	if err := json.Unmarshal([]byte(plugintest.Render(t, plugintest.File(t, fs, "gen/redact/datamap.json"))), &dm); err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]bool)
+	for _, e := range dm.Endpoints {
+		if e.Method == "signup" {
+			for _, f := range e.Returns {
+				paths[f.Path] = true
+			}
+		}
+	}
+	// The Profile type is used by the profile and aliases attributes.
+	for _, p := range []string{"profile.phone", "profile.city", "aliases[].phone", "aliases[].city"} {
+		if !paths[p] {
+			t.Errorf("%s not listed in data map", p)
+		}
+	}
+}
+
+func TestExamples(t *testing.T) {
+	service.Services = make(service.ServicesData)
+	expr.Root.Sensitives = nil
+	root := plugintest.RunDSL(t, testdata.RedactDSL, expr.Root)
+	payload := goaexpr.AsObject(root.Service("users").Method("signup").Payload.Type)
+	cases := []struct {
+		Name, Example string
+	}{
+		{"email", "********"},
+		{"password", "************"},
+	}
+	for _, c := range cases {
+		if ex := payload.Attribute(c.Name).Example(root.API.Random()); ex != c.Example {
+			t.Errorf("%s: got example %v, expected %q", c.Name, ex, c.Example)
+		}
+	}
+	if ex := payload.Attribute("name").Example(root.API.Random()); ex == "********" {
+		t.Errorf("name: got masked example, expected random example")
+	}
+}
@@ -0,0 +1,34 @@
+package redact
+
+import (
+	"fmt"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// Redacted is the value the sensitive attributes are replaced with.
+const Redacted = "[REDACTED]"
+
+// RedactedPtr returns a pointer to a new string holding Redacted.
+func RedactedPtr() *string {
+	s := Redacted
+	return &s
+}
+
+// ValidateFormat validates val against the given format like
+// goa.ValidateFormat but omits val from the error.
+func ValidateFormat(name string, val string, f goa.Format) error {
+	if err := goa.ValidateFormat(name, val, f); err != nil {
+		return goa.InvalidFormatError(name, Redacted, f, fmt.Errorf("invalid %s", f))
+	}
+	return nil
+}
+
+// ValidatePattern validates val against the given regular expression like
+// goa.ValidatePattern but omits val from the error.
+func ValidatePattern(name, val, p string) error {
+	if err := goa.ValidatePattern(name, val, p); err != nil {
+		return goa.InvalidPatternError(name, Redacted, p)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package redact_test
+
+import (
+	"strings"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/plugins/v3/redact"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Err   error
+		Value string
+	}{
+		{"format", redact.ValidateFormat("body.email", "alice@", goa.FormatEmail), "alice@"},
+		{"pattern", redact.ValidatePattern("body.ssn", "123-45-678", `^\d{3}-\d{2}-\d{4}$`), "123-45-678"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if c.Err == nil {
+				t.Fatal("expected an error")
+			}
+			if msg := c.Err.Error(); strings.Contains(msg, c.Value) || !strings.Contains(msg, redact.Redacted) {
+				t.Errorf("got error %q, expected value to be redacted", msg)
+			}
+		})
+	}
+	if err := redact.ValidateFormat("body.email", "alice@example.com", goa.FormatEmail); err != nil {
+		t.Errorf("got error %s for valid email", err)
+	}
+	if err := redact.ValidatePattern("body.ssn", "123-45-6789", `^\d{3}-\d{2}-\d{4}$`); err != nil {
+		t.Errorf("got error %s for valid value", err)
+	}
+}
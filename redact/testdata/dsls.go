@@ -0,0 +1,95 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	redact "goa.design/plugins/v3/redact/dsl"
+)
+
+var RedactDSL = func() {
+	var Profile = Type("Profile", func() {
+		Attribute("phone", String, func() {
+			redact.Classify("contact")
+			redact.Sensitive()
+		})
+		Attribute("city", String, func() {
+			Meta("redact:categories", "location")
+		})
+	})
+	var User = ResultType("application/vnd.user", func() {
+		TypeName("User")
+		Attributes(func() {
+			Attribute("id", String)
+			Attribute("email", String, func() {
+				Format(FormatEmail)
+				redact.Sensitive()
+			})
+			Attribute("ssn", String, func() {
+				Pattern(`^\d{3}-\d{2}-\d{4}$`)
+				redact.Sensitive()
+			})
+			Attribute("salary", Int, func() {
+				redact.Classify("financial", "employment")
+				redact.Sensitive()
+			})
+			Attribute("profile", Profile)
+			Attribute("aliases", ArrayOf(Profile))
+			Required("id", "email", "salary")
+		})
+	})
+	Service("users", func() {
+		Method("signup", func() {
+			Payload(func() {
+				Attribute("email", String, func() {
+					Format(FormatEmail)
+					Example("alice@example.com")
+					redact.Sensitive()
+				})
+				Attribute("password", String, func() {
+					MinLength(12)
+					redact.Sensitive()
+				})
+				Attribute("name", String, func() {
+					MinLength(2)
+					redact.Classify("identity")
+				})
+				Required("email", "password")
+			})
+			Result(User)
+			HTTP(func() {
+				POST("/signup")
+			})
+		})
+		Method("list", func() {
+			Result(CollectionOf(User))
+			HTTP(func() {
+				GET("/users")
+			})
+		})
+		Method("ping", func() {
+			Payload(String)
+			HTTP(func() {
+				GET("/ping/{p}")
+			})
+		})
+	})
+}
+
+var ServicePluginMetaDSL = func() {
+	API("test", func() {
+		Meta("plugin:redact", "off")
+	})
+	Service("users", func() {
+		Meta("plugin:redact", "on")
+		Method("signup", func() {
+			Payload(func() {
+				Attribute("password", String, func() {
+					MinLength(12)
+					redact.Sensitive()
+				})
+			})
+			HTTP(func() {
+				POST("/signup")
+			})
+		})
+	})
+}
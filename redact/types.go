@@ -0,0 +1,296 @@
+package redact
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/redact/expr"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// redactFileData contains the data necessary to render the function
+	// masking the payloads and results of a service and the logging
+	// middleware.
+	redactFileData struct {
+		// ServiceName is the name of the service.
+		ServiceName string
+		// Cases lists the types masked by the Redact function.
+		Cases []*caseData
+	}
+
+	// caseData describes a type masked by the Redact function.
+	caseData struct {
+		// Ref is the reference to the type.
+		Ref string
+		// Expr is the expression masking the value t of the type.
+		Expr string
+	}
+
+	// redactedTypeData contains the data necessary to render the Redacted
+	// method of a type.
+	redactedTypeData struct {
+		// Ref is the reference to the type.
+		Ref string
+		// Statements lists the statements masking the fields of the
+		// copy res of the value v.
+		Statements []string
+		// ElemRedacted is true if the type is an array whose elements
+		// must be masked.
+		ElemRedacted bool
+	}
+)
+
+// redactFile returns the file defining the methods masking the sensitive
+// attributes of the payloads and results of the given service and the logging
+// middleware, nil if there is none.
+func redactFile(genpkg string, svc *goaexpr.ServiceExpr) *codegen.File {
+	types := redactedTypes(svc)
+	if len(types) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	redacted := make(map[string]bool, len(types))
+	for _, ut := range types {
+		redacted[ut.ID()] = true
+	}
+	specs := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "goa.design/goa/v3/pkg", Name: "goa"},
+		{Path: pkgPath},
+	}
+	data := &redactFileData{ServiceName: svc.Name}
+	var sections []*codegen.SectionTemplate
+	for _, ut := range types {
+		ref := sd.Scope.GoTypeRef(&goaexpr.AttributeExpr{Type: ut})
+		data.Cases = append(data.Cases, &caseData{Ref: ref, Expr: "t.Redacted()"})
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "redact-type",
+			Source: redactedT,
+			Data:   buildRedactedTypeData(sd, ut, ref, redacted),
+		})
+	}
+	seen := make(map[string]bool)
+	for _, m := range svc.Methods {
+		md := sd.Method(m.Name)
+		vr := md.ViewedResult
+		if vr == nil || seen[vr.FullRef] {
+			continue
+		}
+		if ut, ok := m.Result.Type.(goaexpr.UserType); !ok || !redacted[ut.ID()] {
+			continue
+		}
+		seen[vr.FullRef] = true
+		if len(seen) == 1 {
+			specs = append(specs, &codegen.ImportSpec{
+				Path: genpkg + "/" + codegen.SnakeCase(sd.VarName) + "/views",
+				Name: sd.ViewsPkg,
+			})
+		}
+		data.Cases = append(data.Cases, &caseData{Ref: vr.FullRef, Expr: vr.ResultInit.Name + "(t).Redacted()"})
+	}
+	sections = append([]*codegen.SectionTemplate{
+		codegen.Header(svc.Name+" redaction", sd.PkgName, specs),
+		{Name: "redact-log", Source: logT, Data: data},
+	}, sections...)
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "redact.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// redactedTypes returns the user types used by the payloads and results of the
+// given service that have sensitive attributes or attributes holding such
+// types, in the order they are found.
+func redactedTypes(svc *goaexpr.ServiceExpr) []goaexpr.UserType {
+	var (
+		all  []goaexpr.UserType
+		seen = make(map[string]bool)
+	)
+	for _, m := range svc.Methods {
+		for _, att := range []*goaexpr.AttributeExpr{m.Payload, m.StreamingPayload, m.Result} {
+			walk.Attribute(att, func(_ string, a *goaexpr.AttributeExpr) error {
+				if ut, ok := a.Type.(goaexpr.UserType); ok && !seen[ut.ID()] {
+					if goaexpr.AsObject(ut) != nil || goaexpr.AsArray(ut) != nil {
+						seen[ut.ID()] = true
+						all = append(all, ut)
+					}
+				}
+				return nil
+			})
+		}
+	}
+	// Compute the types to redact until no type is added to handle the
+	// types nested in types that appear first.
+	redacted := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, ut := range all {
+			if redacted[ut.ID()] {
+				continue
+			}
+			if arr := goaexpr.AsArray(ut); arr != nil {
+				if nestedType(&goaexpr.AttributeExpr{Type: ut}, redacted) != nil {
+					redacted[ut.ID()], changed = true, true
+				}
+				continue
+			}
+			for _, nat := range *goaexpr.AsObject(ut) {
+				if expr.Sensitive(nat.Attribute) || nestedType(nat.Attribute, redacted) != nil {
+					redacted[ut.ID()], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	var types []goaexpr.UserType
+	for _, ut := range all {
+		if redacted[ut.ID()] {
+			types = append(types, ut)
+		}
+	}
+	return types
+}
+
+// nestedType returns the user type held by att, its elements or its values if
+// it is redacted, nil otherwise.
+func nestedType(att *goaexpr.AttributeExpr, redacted map[string]bool) goaexpr.UserType {
+	t := att.Type
+	if ut, ok := t.(goaexpr.UserType); ok && redacted[ut.ID()] {
+		return ut
+	}
+	if arr := goaexpr.AsArray(t); arr != nil {
+		t = arr.ElemType.Type
+	} else if m := goaexpr.AsMap(t); m != nil {
+		t = m.ElemType.Type
+	}
+	if ut, ok := t.(goaexpr.UserType); ok && redacted[ut.ID()] {
+		return ut
+	}
+	return nil
+}
+
+// buildRedactedTypeData returns the data necessary to render the Redacted
+// method of the given type.
+func buildRedactedTypeData(sd *service.Data, ut goaexpr.UserType, ref string, redacted map[string]bool) *redactedTypeData {
+	data := &redactedTypeData{Ref: ref}
+	if goaexpr.AsArray(ut) != nil {
+		data.ElemRedacted = true
+		return data
+	}
+	att := ut.Attribute()
+	for _, nat := range *goaexpr.AsObject(ut) {
+		field := codegen.GoifyAtt(nat.Attribute, nat.Name, true)
+		if expr.Sensitive(nat.Attribute) {
+			data.Statements = append(data.Statements, maskField(att, nat, field))
+			continue
+		}
+		if nestedType(nat.Attribute, redacted) == nil {
+			continue
+		}
+		switch {
+		case goaexpr.AsArray(nat.Attribute.Type) != nil && !isUserType(nat.Attribute):
+			data.Statements = append(data.Statements, fmt.Sprintf(
+				"\tif v.%[1]s != nil {\n\t\tres.%[1]s = make(%[2]s, len(v.%[1]s))\n\t\tfor i, e := range v.%[1]s {\n\t\t\tres.%[1]s[i] = e.Redacted()\n\t\t}\n\t}",
+				field, sd.Scope.GoTypeRef(nat.Attribute)))
+		case goaexpr.AsMap(nat.Attribute.Type) != nil:
+			data.Statements = append(data.Statements, fmt.Sprintf(
+				"\tif v.%[1]s != nil {\n\t\tres.%[1]s = make(%[2]s, len(v.%[1]s))\n\t\tfor k, e := range v.%[1]s {\n\t\t\tres.%[1]s[k] = e.Redacted()\n\t\t}\n\t}",
+				field, sd.Scope.GoTypeRef(nat.Attribute)))
+		default:
+			data.Statements = append(data.Statements, fmt.Sprintf("\tres.%[1]s = v.%[1]s.Redacted()", field))
+		}
+	}
+	return data
+}
+
+// maskField returns the statement masking the field of the sensitive
+// attribute nat of the object att.
+func maskField(att *goaexpr.AttributeExpr, nat *goaexpr.NamedAttributeExpr, field string) string {
+	if _, ok := nat.Attribute.Type.(goaexpr.Primitive); !ok {
+		return fmt.Sprintf("\tres.%s = nil", field)
+	}
+	ptr := att.IsPrimitivePointer(nat.Name, true)
+	switch nat.Attribute.Type.Kind() {
+	case goaexpr.StringKind:
+		if ptr {
+			return fmt.Sprintf("\tif res.%[1]s != nil {\n\t\tres.%[1]s = redact.RedactedPtr()\n\t}", field)
+		}
+		return fmt.Sprintf("\tres.%s = redact.Redacted", field)
+	case goaexpr.BytesKind, goaexpr.AnyKind:
+		return fmt.Sprintf("\tres.%s = nil", field)
+	}
+	if ptr {
+		return fmt.Sprintf("\tres.%s = nil", field)
+	}
+	if nat.Attribute.Type.Kind() == goaexpr.BooleanKind {
+		return fmt.Sprintf("\tres.%s = false", field)
+	}
+	return fmt.Sprintf("\tres.%s = 0", field)
+}
+
+// isUserType returns true if the type of the given attribute is a user type.
+func isUserType(att *goaexpr.AttributeExpr) bool {
+	_, ok := att.Type.(goaexpr.UserType)
+	return ok
+}
+
+// input: redactFileData
+const logT = `{{ printf "Redact returns a copy of v with the sensitive attributes masked if v is a payload or a result of the %q service endpoints, v otherwise." .ServiceName | comment }}
+func Redact(v interface{}) interface{} {
+	switch t := v.(type) {
+{{- range .Cases }}
+	case {{ .Ref }}:
+		return {{ .Expr }}
+{{- end }}
+	}
+	return v
+}
+
+{{ printf "LogRedacted returns an endpoint middleware which calls log with the payloads and the results of the %q service endpoints masked with Redact. Apply it with the Use method of the endpoints." .ServiceName | comment }}
+func LogRedacted(log func(ctx context.Context, keyvals ...interface{})) func(goa.Endpoint) goa.Endpoint {
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			method := ctx.Value(goa.MethodKey)
+			log(ctx, "service", {{ printf "%q" .ServiceName }}, "method", method, "payload", Redact(req))
+			res, err := e(ctx, req)
+			if err != nil {
+				log(ctx, "service", {{ printf "%q" .ServiceName }}, "method", method, "error", err)
+				return res, err
+			}
+			log(ctx, "service", {{ printf "%q" .ServiceName }}, "method", method, "result", Redact(res))
+			return res, nil
+		}
+	}
+}
+`
+
+// input: redactedTypeData
+const redactedT = `{{ printf "Redacted returns a copy of v with the sensitive attributes masked." | comment }}
+{{- if .ElemRedacted }}
+func (v {{ .Ref }}) Redacted() {{ .Ref }} {
+	if v == nil {
+		return nil
+	}
+	res := make({{ .Ref }}, len(v))
+	for i, e := range v {
+		res[i] = e.Redacted()
+	}
+	return res
+}
+{{- else }}
+func (v {{ .Ref }}) Redacted() {{ .Ref }} {
+	if v == nil {
+		return nil
+	}
+	res := *v
+{{- range .Statements }}
+{{ . }}
+{{- end }}
+	return &res
+}
+{{- end }}
+`
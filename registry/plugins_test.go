@@ -0,0 +1,92 @@
+package registry_test
+
+import (
+	"testing"
+
+	"goa.design/plugins/v3/registry"
+
+	// Register the plugins of the repository
+	_ "goa.design/plugins/v3/admin"
+	_ "goa.design/plugins/v3/apigateway"
+	_ "goa.design/plugins/v3/async"
+	_ "goa.design/plugins/v3/avro"
+	_ "goa.design/plugins/v3/bodylimit"
+	_ "goa.design/plugins/v3/breaker"
+	_ "goa.design/plugins/v3/bulk"
+	_ "goa.design/plugins/v3/bulkhead"
+	_ "goa.design/plugins/v3/cachecontrol"
+	_ "goa.design/plugins/v3/cbor"
+	_ "goa.design/plugins/v3/cobracli"
+	_ "goa.design/plugins/v3/cors"
+	_ "goa.design/plugins/v3/docker"
+	_ "goa.design/plugins/v3/docs"
+	_ "goa.design/plugins/v3/docsite"
+	_ "goa.design/plugins/v3/encoding"
+	_ "goa.design/plugins/v3/errorcatalog"
+	_ "goa.design/plugins/v3/etag"
+	_ "goa.design/plugins/v3/export"
+	_ "goa.design/plugins/v3/feature"
+	_ "goa.design/plugins/v3/fuzz"
+	_ "goa.design/plugins/v3/gcpgateway"
+	_ "goa.design/plugins/v3/gorm"
+	_ "goa.design/plugins/v3/graphql"
+	_ "goa.design/plugins/v3/grpcgateway"
+	_ "goa.design/plugins/v3/grpcservices"
+	_ "goa.design/plugins/v3/healthcheck"
+	_ "goa.design/plugins/v3/hooks"
+	_ "goa.design/plugins/v3/i18n"
+	_ "goa.design/plugins/v3/jsonapi"
+	_ "goa.design/plugins/v3/jsonschema"
+	_ "goa.design/plugins/v3/kong"
+	_ "goa.design/plugins/v3/kubernetes"
+	_ "goa.design/plugins/v3/links"
+	_ "goa.design/plugins/v3/lint"
+	_ "goa.design/plugins/v3/loadtest"
+	_ "goa.design/plugins/v3/meshroute"
+	_ "goa.design/plugins/v3/messaging"
+	_ "goa.design/plugins/v3/mocks"
+	_ "goa.design/plugins/v3/mqtt"
+	_ "goa.design/plugins/v3/msgpack"
+	_ "goa.design/plugins/v3/nginx"
+	_ "goa.design/plugins/v3/openapispec"
+	_ "goa.design/plugins/v3/pagination"
+	_ "goa.design/plugins/v3/protobuf"
+	_ "goa.design/plugins/v3/prototypes"
+	_ "goa.design/plugins/v3/provisioning"
+	_ "goa.design/plugins/v3/quota"
+	_ "goa.design/plugins/v3/redact"
+	_ "goa.design/plugins/v3/replay"
+	_ "goa.design/plugins/v3/requestid"
+	_ "goa.design/plugins/v3/secureheaders"
+	_ "goa.design/plugins/v3/security"
+	_ "goa.design/plugins/v3/static"
+	_ "goa.design/plugins/v3/terraform"
+	_ "goa.design/plugins/v3/timeout"
+	_ "goa.design/plugins/v3/validation"
+	_ "goa.design/plugins/v3/webhooks"
+	_ "goa.design/plugins/v3/xml"
+)
+
+func TestPlugins(t *testing.T) {
+	for _, cmd := range []string{"gen", "example"} {
+		ps, err := registry.Plugins(cmd)
+		if err != nil {
+			t.Fatalf("%s: %s", cmd, err)
+		}
+		if cmd != "gen" {
+			continue
+		}
+		pos := make(map[string]int, len(ps))
+		for i, p := range ps {
+			pos[p.Name] = i
+		}
+		for _, n := range []string{"encoding", "pagination", "validation", "xml"} {
+			if pos[n] > pos["docsite"] {
+				t.Errorf("got %s after docsite, expected docsite to run after it", n)
+			}
+		}
+		if pos["quota"] < pos["security"] {
+			t.Error("got quota before security, expected quota to run after it")
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package registry
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/plugins/v3/config"
+)
+
+type (
+	// Plugin describes a plugin registered with the registry together with
+	// its ordering constraints and dependencies.
+	Plugin struct {
+		// Name is the name of the plugin.
+		Name string
+		// Cmd is the goa command triggering the plugin, "gen" or
+		// "example".
+		Cmd string
+		// Prepare is the plugin preparation function if any.
+		Prepare codegen.PrepareFunc
+		// Generate is the plugin generator function if any.
+		Generate codegen.GenerateFunc
+		// After lists the plugins that must run before this plugin when
+		// they are registered for the same command.
+		After []string
+		// Before lists the plugins that must run after this plugin when
+		// they are registered for the same command.
+		Before []string
+		// Requires lists the plugins that must be registered for the
+		// same command. The gen command fails if one of them is not
+		// imported by the design. The required plugins run before this
+		// plugin.
+		Requires []string
+		// Parallel indicates that the generator function only appends
+		// new files to the files it is given: it does not modify them
+		// nor any shared state such as the goa code generation data.
+		// Consecutive plugins with Parallel set run concurrently.
+		Parallel bool
+	}
+
+	// Config is the configuration read from the "registry" section of the
+	// plugin configuration file.
+	Config struct {
+		// Workers is the maximum number of parallel plugins running
+		// concurrently, the number of CPUs by default.
+		Workers int `yaml:"workers"`
+	}
+)
+
+// plugins lists the registered plugins in registration order.
+var plugins []*Plugin
+
+// Register the registry with the goa code generator. The registry runs the
+// registered plugins in an order honoring their constraints.
+func init() {
+	for _, cmd := range []string{"gen", "example"} {
+		codegen.RegisterPlugin("registry", cmd, prepare(cmd), generate(cmd))
+	}
+	config.Register("registry", func() interface{} { return &Config{Workers: runtime.NumCPU()} })
+}
+
+// Validate makes sure the number of workers is positive.
+func (c *Config) Validate() error {
+	if c.Workers < 1 {
+		return fmt.Errorf("workers must be at least 1, got %d", c.Workers)
+	}
+	return nil
+}
+
+// Register registers the given plugin. Register is meant to be called by the
+// plugins in their init function in place of codegen.RegisterPlugin, for
+// example:
+//
+//    func init() {
+//        registry.Register(&registry.Plugin{
+//            Name:     "docsite",
+//            Cmd:      "gen",
+//            Generate: Generate,
+//            After:    []string{"encoding", "pagination"},
+//        })
+//    }
+//
+func Register(p *Plugin) {
+	plugins = append(plugins, p)
+}
+
+// Plugins returns the plugins registered for the given command in the order
+// they run: each plugin runs after the plugins it depends on, the plugins
+// that are not constrained relative to each other run in alphabetical order.
+// Plugins returns an error if a required plugin is missing, if a plugin is
+// registered twice or if the constraints are circular.
+func Plugins(cmd string) ([]*Plugin, error) {
+	return sortPlugins(cmd, plugins)
+}
+
+// sortPlugins sorts the plugins registered for the given command
+// topologically, see Plugins.
+func sortPlugins(cmd string, all []*Plugin) ([]*Plugin, error) {
+	byName := make(map[string]*Plugin)
+	var names []string
+	for _, p := range all {
+		if p.Cmd != cmd {
+			continue
+		}
+		if _, ok := byName[p.Name]; ok {
+			return nil, fmt.Errorf("plugin %q is registered more than once for the %s command", p.Name, cmd)
+		}
+		byName[p.Name] = p
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+
+	// deps maps each plugin to the plugins that must run before it.
+	deps := make(map[string]map[string]bool, len(names))
+	for _, n := range names {
+		deps[n] = make(map[string]bool)
+	}
+	for _, n := range names {
+		p := byName[n]
+		for _, r := range p.Requires {
+			if _, ok := byName[r]; !ok {
+				return nil, fmt.Errorf("plugin %q requires plugin %q, make sure it is imported by the design", n, r)
+			}
+			deps[n][r] = true
+		}
+		for _, a := range p.After {
+			if _, ok := byName[a]; ok {
+				deps[n][a] = true
+			}
+		}
+		for _, b := range p.Before {
+			if _, ok := byName[b]; ok {
+				deps[b][n] = true
+			}
+		}
+	}
+
+	var (
+		sorted = make([]*Plugin, 0, len(names))
+		done   = make(map[string]bool, len(names))
+	)
+	for len(sorted) < len(names) {
+		next := ""
+		for _, n := range names {
+			if done[n] {
+				continue
+			}
+			ready := true
+			for d := range deps[n] {
+				if !done[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = n
+				break
+			}
+		}
+		if next == "" {
+			var cycle []string
+			for _, n := range names {
+				if !done[n] {
+					cycle = append(cycle, n)
+				}
+			}
+			return nil, fmt.Errorf("circular ordering constraints between plugins %s", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		sorted = append(sorted, byName[next])
+	}
+	return sorted, nil
+}
+
+// prepare returns the preparation function running the preparation functions
+// of the plugins registered for the given command.
+func prepare(cmd string) codegen.PrepareFunc {
+	return func(genpkg string, roots []eval.Root) error {
+		ps, err := Plugins(cmd)
+		if err != nil {
+			return err
+		}
+		for _, p := range ps {
+			if p.Prepare == nil {
+				continue
+			}
+			if err := p.Prepare(genpkg, roots); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// generate returns the generator function running the generator functions of
+// the plugins registered for the given command.
+func generate(cmd string) codegen.GenerateFunc {
+	return func(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+		ps, err := Plugins(cmd)
+		if err != nil {
+			return nil, err
+		}
+		c, err := config.Get("registry")
+		if err != nil {
+			return nil, err
+		}
+		return run(ps, c.(*Config).Workers, genpkg, roots, files)
+	}
+}
+
+// run runs the generator functions of the given sorted plugins. The
+// consecutive parallel plugins run concurrently using at most workers
+// goroutines, their files are appended in plugin order so that the output
+// does not depend on scheduling.
+func run(ps []*Plugin, workers int, genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for i := 0; i < len(ps); {
+		p := ps[i]
+		if !p.Parallel {
+			if p.Generate != nil {
+				var err error
+				if files, err = p.Generate(genpkg, roots, files); err != nil {
+					return nil, err
+				}
+			}
+			i++
+			continue
+		}
+		batch := []*Plugin{p}
+		for i++; i < len(ps) && ps[i].Parallel && !dependsOn(ps[i], batch); i++ {
+			batch = append(batch, ps[i])
+		}
+		var err error
+		if files, err = runParallel(batch, workers, genpkg, roots, files); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// runParallel runs the generator functions of the given parallel plugins
+// concurrently and appends the files they produce to files in plugin order.
+// It returns the error of the first failing plugin in plugin order.
+func runParallel(batch []*Plugin, workers int, genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	var (
+		added = make([][]*codegen.File, len(batch))
+		errs  = make([]error, len(batch))
+		sem   = make(chan struct{}, workers)
+		wg    sync.WaitGroup
+	)
+	for i, p := range batch {
+		if p.Generate == nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p *Plugin) {
+			defer func() { <-sem; wg.Done() }()
+			// Cap the capacity so that the appends of the plugins
+			// do not write to the same backing array.
+			fs, err := p.Generate(genpkg, roots, files[:len(files):len(files)])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if len(fs) < len(files) {
+				errs[i] = fmt.Errorf("parallel plugin %q removed generated files", p.Name)
+				return
+			}
+			added[i] = fs[len(files):]
+		}(i, p)
+	}
+	wg.Wait()
+	for i := range batch {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+	}
+	for _, fs := range added {
+		files = append(files, fs...)
+	}
+	return files, nil
+}
+
+// dependsOn returns true if p must run after one of the given plugins.
+func dependsOn(p *Plugin, ps []*Plugin) bool {
+	for _, o := range ps {
+		for _, n := range p.After {
+			if n == o.Name {
+				return true
+			}
+		}
+		for _, n := range p.Requires {
+			if n == o.Name {
+				return true
+			}
+		}
+		for _, n := range o.Before {
+			if n == p.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
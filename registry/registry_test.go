@@ -0,0 +1,160 @@
+package registry
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+)
+
+func TestSortPlugins(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Plugins  []*Plugin
+		Expected string
+		Error    string
+	}{
+		{"alphabetical", []*Plugin{
+			{Name: "cors", Cmd: "gen"},
+			{Name: "admin", Cmd: "gen"},
+			{Name: "bulk", Cmd: "gen"},
+		}, "admin bulk cors", ""},
+		{"after", []*Plugin{
+			{Name: "docsite", Cmd: "gen", After: []string{"encoding", "missing"}},
+			{Name: "encoding", Cmd: "gen"},
+			{Name: "admin", Cmd: "gen"},
+		}, "admin encoding docsite", ""},
+		{"before", []*Plugin{
+			{Name: "zaplogger", Cmd: "gen", Before: []string{"admin"}},
+			{Name: "admin", Cmd: "gen"},
+		}, "zaplogger admin", ""},
+		{"requires", []*Plugin{
+			{Name: "a", Cmd: "gen", Requires: []string{"b"}},
+			{Name: "b", Cmd: "gen"},
+		}, "b a", ""},
+		{"other-command", []*Plugin{
+			{Name: "a", Cmd: "gen", After: []string{"b"}},
+			{Name: "b", Cmd: "example", After: []string{"a"}},
+		}, "a", ""},
+		{"missing-requirement", []*Plugin{
+			{Name: "a", Cmd: "gen", Requires: []string{"b"}},
+			{Name: "b", Cmd: "example"},
+		}, "", `plugin "a" requires plugin "b"`},
+		{"duplicate", []*Plugin{
+			{Name: "a", Cmd: "gen"},
+			{Name: "a", Cmd: "gen"},
+		}, "", `plugin "a" is registered more than once`},
+		{"cycle", []*Plugin{
+			{Name: "a", Cmd: "gen", After: []string{"b"}},
+			{Name: "b", Cmd: "gen", After: []string{"c"}},
+			{Name: "c", Cmd: "gen", Before: []string{"a"}, After: []string{"a"}},
+			{Name: "d", Cmd: "gen"},
+		}, "", "circular ordering constraints between plugins a, b, c"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			ps, err := sortPlugins("gen", c.Plugins)
+			if c.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), c.Error) {
+					t.Fatalf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, len(ps))
+			for i, p := range ps {
+				names[i] = p.Name
+			}
+			if got := strings.Join(names, " "); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	var running, max int32
+	// gen returns a generator function appending a file named after the
+	// plugin, the earlier plugins take longer to complete.
+	gen := func(name string, delay time.Duration) codegen.GenerateFunc {
+		return func(_ string, _ []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(delay)
+			return append(files, &codegen.File{Path: name}), nil
+		}
+	}
+	boom := errors.New("boom")
+	cases := []struct {
+		Name     string
+		Plugins  []*Plugin
+		Workers  int
+		Expected string
+		Max      int32
+		Error    error
+	}{
+		{"sequential", []*Plugin{
+			{Name: "a", Generate: gen("a", 0)},
+			{Name: "b", Generate: gen("b", 0)},
+		}, 4, "in a b", 1, nil},
+		{"parallel", []*Plugin{
+			{Name: "a", Generate: gen("a", 30*time.Millisecond), Parallel: true},
+			{Name: "b", Generate: gen("b", 20*time.Millisecond), Parallel: true},
+			{Name: "c", Generate: gen("c", 10*time.Millisecond), Parallel: true},
+			{Name: "d", Generate: gen("d", 0)},
+			{Name: "e", Generate: gen("e", 0), Parallel: true},
+		}, 4, "in a b c d e", 3, nil},
+		{"bounded", []*Plugin{
+			{Name: "a", Generate: gen("a", 10*time.Millisecond), Parallel: true},
+			{Name: "b", Generate: gen("b", 10*time.Millisecond), Parallel: true},
+			{Name: "c", Generate: gen("c", 10*time.Millisecond), Parallel: true},
+		}, 2, "in a b c", 2, nil},
+		{"dependency", []*Plugin{
+			{Name: "a", Generate: gen("a", 10*time.Millisecond), Parallel: true},
+			{Name: "b", Generate: gen("b", 0), Parallel: true, After: []string{"a"}},
+		}, 4, "in a b", 1, nil},
+		{"error", []*Plugin{
+			{Name: "a", Generate: gen("a", 0), Parallel: true},
+			{Name: "b", Generate: func(string, []eval.Root, []*codegen.File) ([]*codegen.File, error) { return nil, boom }, Parallel: true},
+		}, 4, "", 0, boom},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			max = 0
+			in := make([]*codegen.File, 1, 10)
+			in[0] = &codegen.File{Path: "in"}
+			fs, err := run(c.Plugins, c.Workers, "", nil, in)
+			if c.Error != nil {
+				if err != c.Error {
+					t.Fatalf("got error %v, expected %v", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths := make([]string, len(fs))
+			for i, f := range fs {
+				paths[i] = f.Path
+			}
+			if got := strings.Join(paths, " "); got != c.Expected {
+				t.Errorf("got files %q, expected %q", got, c.Expected)
+			}
+			if max != c.Max {
+				t.Errorf("got %d concurrent plugins, expected %d", max, c.Max)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/stream"
+)
+
+// pkgPath is the import path of the package implementing the replay harness.
+const pkgPath = "goa.design/plugins/v3/replay"
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "replay",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces a fixtures file listing a request built from the design
+// examples and the expected response for each HTTP endpoint, and the main
+// package of a command replaying the fixtures against a deployment.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			if r.API == nil || r.API.HTTP == nil || len(r.API.HTTP.Services) == 0 {
+				continue
+			}
+			fixtures := buildFixtures(r)
+			if len(fixtures) == 0 {
+				continue
+			}
+			path := filepath.Join(codegen.Gendir, "replay", "fixtures.json")
+			files = append(files, stream.File(path, "replay-fixtures", fixtures, stream.IndentedJSON), mainFile(r))
+		}
+	}
+	return files, nil
+}
+
+// mainFile returns the file implementing the command replaying the fixtures.
+func mainFile(r *expr.RootExpr) *codegen.File {
+	header := codegen.Header(r.API.Name+" replay", "main", []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "flag"},
+		{Path: "fmt"},
+		{Path: "net/http"},
+		{Path: "os"},
+		{Path: "time"},
+		{Path: pkgPath},
+	})
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "replay", "main.go"),
+		SectionTemplates: []*codegen.SectionTemplate{header, {
+			Name:   "replay-main",
+			Source: mainT,
+			Data:   map[string]string{"API": r.API.Name, "BaseURL": baseURL(r)},
+		}},
+	}
+}
+
+// buildFixtures returns the fixtures of the HTTP endpoints. Streaming and
+// multipart endpoints are skipped.
+func buildFixtures(r *expr.RootExpr) []*Fixture {
+	var fixtures []*Fixture
+	for _, svc := range r.API.HTTP.Services {
+		if !config.Enabled("replay", svc.Name()) {
+			continue
+		}
+		for _, e := range svc.HTTPEndpoints {
+			if e.MethodExpr.IsStreaming() || e.MultipartRequest || len(e.Routes) == 0 {
+				continue
+			}
+			fixtures = append(fixtures, buildFixture(r, e))
+		}
+	}
+	return fixtures
+}
+
+// buildFixture returns the fixture of the given endpoint.
+func buildFixture(r *expr.RootExpr, e *expr.HTTPEndpointExpr) *Fixture {
+	var (
+		svc   = e.Service.Name()
+		route = e.Routes[0]
+		req   = &Request{Method: route.Method, Path: genutil.ExamplePath(r, e, route.FullPaths()[0])}
+		skip  = map[string]map[string]struct{}{"header": {}, "query": {}}
+	)
+	header := make(map[string][]string)
+	var query []string
+	for _, a := range auth(e) {
+		skip[a.in][a.name] = struct{}{}
+		v := a.prefix + "${" + a.env + "}"
+		if a.in == "query" {
+			query = append(query, url.QueryEscape(a.name)+"="+v)
+			continue
+		}
+		header[a.name] = append(header[a.name], v)
+	}
+	if q := exampleQuery(r, e, skip["query"]); q != "" {
+		query = append([]string{q}, query...)
+	}
+	req.Query = strings.Join(query, "&")
+	exampleHeaders(r, e.Headers, skip["header"], header)
+	if len(header) > 0 {
+		req.Header = header
+	}
+	req.Body = exampleBody(r, e.Body)
+	return &Fixture{
+		Name:     svc + "." + e.MethodExpr.Name,
+		Service:  svc,
+		Method:   e.MethodExpr.Name,
+		Request:  req,
+		Response: expectedResponse(r, e),
+	}
+}
+
+// expectedResponse returns the success response of the given endpoint.
+func expectedResponse(r *expr.RootExpr, e *expr.HTTPEndpointExpr) *Response {
+	if len(e.Responses) == 0 {
+		return &Response{Status: expr.StatusOK}
+	}
+	resp := e.Responses[0]
+	res := &Response{Status: resp.StatusCode, Body: exampleBody(r, resp.Body)}
+	if resp.Body != nil && expr.AsObject(resp.Body.Type) != nil {
+		for _, nat := range *expr.AsObject(resp.Body.Type) {
+			if resp.Body.IsRequired(nat.Name) {
+				res.Required = append(res.Required, nat.Name)
+			}
+		}
+	}
+	return res
+}
+
+// authData describes the placeholder of a credential.
+type authData struct {
+	// in is the location of the credential, one of "header" or "query".
+	in string
+	// name is the name of the header or query parameter.
+	name string
+	// prefix is the prefix of the header value, e.g. "Bearer ".
+	prefix string
+	// env is the name of the environment variable holding the credential.
+	env string
+}
+
+// auth returns the placeholders of the credentials required by the first
+// security requirement of the given endpoint.
+func auth(e *expr.HTTPEndpointExpr) []*authData {
+	if len(e.Requirements) == 0 {
+		return nil
+	}
+	var as []*authData
+	for _, s := range e.Requirements[0].Schemes {
+		a := &authData{in: s.In, name: s.Name, env: strings.ToUpper(codegen.SnakeCase(s.SchemeName))}
+		switch s.Kind {
+		case expr.NoKind:
+			continue
+		case expr.BasicAuthKind:
+			a.prefix = "Basic "
+		case expr.JWTKind, expr.OAuth2Kind:
+			if a.name == "Authorization" {
+				a.prefix = "Bearer "
+			}
+		}
+		if a.in == "" {
+			a.in = "header"
+		}
+		as = append(as, a)
+	}
+	return as
+}
+
+// baseURL returns the first HTTP URI of the API servers with the host
+// variables replaced with their default values.
+func baseURL(r *expr.RootExpr) string {
+	for _, s := range r.API.Servers {
+		for _, h := range s.Hosts {
+			for _, uri := range h.URIs {
+				u := string(uri)
+				if !strings.HasPrefix(u, "http") {
+					continue
+				}
+				for _, v := range *expr.AsObject(h.Attribute().Type) {
+					if v.Attribute.DefaultValue != nil {
+						u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+					}
+				}
+				return strings.TrimSuffix(u, "/")
+			}
+		}
+	}
+	return "http://localhost:80"
+}
+
+// exampleQuery returns the query string built from the query parameter
+// examples. The parameters carrying credentials are skipped.
+func exampleQuery(r *expr.RootExpr, e *expr.HTTPEndpointExpr, skip map[string]struct{}) string {
+	params := e.QueryParams()
+	if expr.AsObject(params.Type) == nil {
+		return ""
+	}
+	values := url.Values{}
+	expr.WalkMappedAttr(params, func(name, elem string, att *expr.AttributeExpr) error {
+		if _, ok := skip[elem]; ok {
+			return nil
+		}
+		ex := reflect.ValueOf(att.Example(r.API.Random()))
+		if ex.Kind() == reflect.Slice {
+			for i := 0; i < ex.Len(); i++ {
+				values.Add(elem, fmt.Sprint(ex.Index(i).Interface()))
+			}
+			return nil
+		}
+		values.Add(elem, fmt.Sprint(ex.Interface()))
+		return nil
+	})
+	return values.Encode()
+}
+
+// exampleHeaders adds the headers built from the examples of the given header
+// attributes to header. The headers carrying credentials are skipped.
+func exampleHeaders(r *expr.RootExpr, headers *expr.MappedAttributeExpr, skip map[string]struct{}, header map[string][]string) {
+	if headers == nil || expr.AsObject(headers.Type) == nil {
+		return
+	}
+	expr.WalkMappedAttr(headers, func(name, elem string, att *expr.AttributeExpr) error {
+		if _, ok := skip[elem]; !ok {
+			header[elem] = append(header[elem], fmt.Sprint(att.Example(r.API.Random())))
+		}
+		return nil
+	})
+}
+
+// exampleBody returns the JSON representation of the example of the given
+// body, nil if there is no body or if the example cannot be represented in
+// JSON.
+func exampleBody(r *expr.RootExpr, body *expr.AttributeExpr) json.RawMessage {
+	if body == nil || body.Type == expr.Empty {
+		return nil
+	}
+	b, err := json.Marshal(body.Example(r.API.Random()))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// input: map[string]string{"API": string, "BaseURL": string}
+const mainT = `{{ printf "main sends the requests of the %s API fixtures to a deployment and checks the responses. It exits with status 1 if a response does not match its fixture. The credentials are read from the environment variables named after the security schemes." .API | comment }}
+func main() {
+	var (
+		baseURL = flag.String("url", {{ printf "%q" .BaseURL }}, "base URL of the API")
+		path    = flag.String("fixtures", "gen/replay/fixtures.json", "path to the fixtures file")
+		timeout = flag.Duration("timeout", 10*time.Second, "timeout of each request")
+	)
+	flag.Parse()
+
+	fixtures, err := replay.Load(*path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	results := replay.Run(context.Background(), &http.Client{Timeout: *timeout}, *baseURL, fixtures)
+	if !replay.Report(os.Stdout, results) {
+		os.Exit(1)
+	}
+}
+`
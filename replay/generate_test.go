@@ -0,0 +1,24 @@
+package replay_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/replay"
+	"goa.design/plugins/v3/replay/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	root := httpcodegen.RunHTTPDSL(t, testdata.ReplayDSL)
+	fs, err := replay.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	plugintest.Golden(t, "fixtures.json", plugintest.Render(t, plugintest.File(t, fs, "gen/replay/fixtures.json")))
+	plugintest.Golden(t, "main.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/replay/main.go")))
+}
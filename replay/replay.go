@@ -0,0 +1,227 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type (
+	// Fixture is a request sent to an endpoint and the response it is
+	// expected to produce.
+	Fixture struct {
+		// Name is the name of the fixture, e.g. "books.show".
+		Name string `json:"name"`
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the method.
+		Method string `json:"method"`
+		// Request is the request sent to the endpoint.
+		Request *Request `json:"request"`
+		// Response is the expected response.
+		Response *Response `json:"response"`
+	}
+
+	// Request describes a HTTP request. The path, query string and header
+	// values may contain ${VAR} placeholders replaced with the values of
+	// the environment variables when the request is sent.
+	Request struct {
+		// Method is the HTTP method.
+		Method string `json:"method"`
+		// Path is the request path.
+		Path string `json:"path"`
+		// Query is the encoded query string.
+		Query string `json:"query,omitempty"`
+		// Header lists the request headers.
+		Header map[string][]string `json:"header,omitempty"`
+		// Body is the JSON request body.
+		Body json.RawMessage `json:"body,omitempty"`
+	}
+
+	// Response describes the expected HTTP response.
+	Response struct {
+		// Status is the expected status code.
+		Status int `json:"status"`
+		// Body is an example of the JSON response body. The response
+		// body must have the same JSON type, the values may differ.
+		Body json.RawMessage `json:"body,omitempty"`
+		// Required lists the fields the response body object must
+		// contain.
+		Required []string `json:"required,omitempty"`
+	}
+
+	// Result is the outcome of replaying a fixture.
+	Result struct {
+		// Fixture is the replayed fixture.
+		Fixture *Fixture
+		// Status is the status code of the response, 0 if no response
+		// was received.
+		Status int
+		// Duration is the time taken to receive the response.
+		Duration time.Duration
+		// Err describes why the replay failed, nil if it succeeded.
+		Err error
+	}
+
+	// Doer is the interface implemented by the HTTP client sending the
+	// requests, e.g. *http.Client.
+	Doer interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+)
+
+// Load reads the fixtures from the JSON file with the given path.
+func Load(path string) ([]*Fixture, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*Fixture
+	if err := json.Unmarshal(b, &fixtures); err != nil {
+		return nil, fmt.Errorf("invalid fixtures file %s: %s", path, err)
+	}
+	return fixtures, nil
+}
+
+// Run sends the requests of the fixtures in order to the API served at baseURL
+// and checks the responses. It stops early if ctx is canceled.
+func Run(ctx context.Context, c Doer, baseURL string, fixtures []*Fixture) []*Result {
+	results := make([]*Result, 0, len(fixtures))
+	for _, f := range fixtures {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, replay(ctx, c, strings.TrimSuffix(baseURL, "/"), f))
+	}
+	return results
+}
+
+// Report writes a line per result to w followed by a summary. It returns true
+// if all the fixtures were replayed successfully.
+func Report(w io.Writer, results []*Result) bool {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL %s (%s): %s\n", r.Fixture.Name, r.Duration.Round(time.Millisecond), r.Err)
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s (%s)\n", r.Fixture.Name, r.Duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, "%d fixtures, %d failed\n", len(results), failed)
+	return failed == 0
+}
+
+// replay sends the request of the given fixture and checks the response.
+func replay(ctx context.Context, c Doer, baseURL string, f *Fixture) *Result {
+	res := &Result{Fixture: f}
+	req, err := newRequest(ctx, baseURL, f.Request)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	start := time.Now()
+	resp, err := c.Do(req)
+	if err != nil {
+		res.Duration = time.Since(start)
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	res.Duration = time.Since(start)
+	res.Status = resp.StatusCode
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Err = check(f.Response, resp.StatusCode, body)
+	return res
+}
+
+// newRequest returns the HTTP request described by r with the placeholders
+// replaced with the values of the environment variables.
+func newRequest(ctx context.Context, baseURL string, r *Request) (*http.Request, error) {
+	u := baseURL + os.Expand(r.Path, func(k string) string { return url.PathEscape(os.Getenv(k)) })
+	if r.Query != "" {
+		u += "?" + os.Expand(r.Query, func(k string) string { return url.QueryEscape(os.Getenv(k)) })
+	}
+	var body io.Reader
+	if len(r.Body) > 0 {
+		body = bytes.NewReader(r.Body)
+	}
+	req, err := http.NewRequest(r.Method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range r.Header {
+		for _, v := range values {
+			req.Header.Add(name, os.ExpandEnv(v))
+		}
+	}
+	if len(r.Body) > 0 && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req.WithContext(ctx), nil
+}
+
+// check returns an error if the response with the given status and body does
+// not match the expected response.
+func check(expected *Response, status int, body []byte) error {
+	if expected == nil {
+		return nil
+	}
+	if status != expected.Status {
+		return fmt.Errorf("got status %d, expected %d", status, expected.Status)
+	}
+	if len(expected.Body) == 0 {
+		return nil
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(expected.Body, &want); err != nil {
+		return fmt.Errorf("invalid expected body: %s", err)
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		return fmt.Errorf("invalid response body: %s", err)
+	}
+	if wt, gt := jsonType(want), jsonType(got); wt != gt {
+		return fmt.Errorf("got %s response body, expected %s", gt, wt)
+	}
+	if obj, ok := got.(map[string]interface{}); ok {
+		var missing []string
+		for _, name := range expected.Required {
+			if _, ok := obj[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("response body is missing %s", strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// jsonType returns the name of the JSON type of the given decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
@@ -0,0 +1,94 @@
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"goa.design/plugins/v3/replay"
+)
+
+func TestRun(t *testing.T) {
+	os.Setenv("REPLAY_TEST_KEY", "a&b")
+	defer os.Unsetenv("REPLAY_TEST_KEY")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/books/42":
+			if r.URL.Query().Get("key") != "a&b" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"id":7,"title":"Emma"}`))
+		case "/books/43":
+			w.Write([]byte(`{"id":7}`))
+		case "/books/44":
+			w.Write([]byte(`[]`))
+		case "/books":
+			if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Authorization") != "Bearer a&b" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`1`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	book := json.RawMessage(`{"id":42,"title":"Dune"}`)
+	show := func(name, path string) *replay.Fixture {
+		return &replay.Fixture{
+			Name:     name,
+			Request:  &replay.Request{Method: "GET", Path: path, Query: "key=${REPLAY_TEST_KEY}"},
+			Response: &replay.Response{Status: 200, Body: book, Required: []string{"id", "title"}},
+		}
+	}
+	fixtures := []*replay.Fixture{
+		show("show", "/books/42"),
+		show("missing", "/books/43"),
+		show("array", "/books/44"),
+		show("status", "/books/45"),
+		{
+			Name: "create",
+			Request: &replay.Request{
+				Method: "POST",
+				Path:   "/books",
+				Header: map[string][]string{"Authorization": {"Bearer ${REPLAY_TEST_KEY}"}},
+				Body:   json.RawMessage(`{"title":"Dune"}`),
+			},
+			Response: &replay.Response{Status: 201, Body: json.RawMessage(`42`)},
+		},
+	}
+	results := replay.Run(context.Background(), http.DefaultClient, srv.URL+"/", fixtures)
+	if len(results) != len(fixtures) {
+		t.Fatalf("got %d results, expected %d", len(results), len(fixtures))
+	}
+	errs := map[string]string{
+		"show":    "",
+		"missing": "response body is missing title",
+		"array":   "got array response body, expected object",
+		"status":  "got status 404, expected 200",
+		"create":  "",
+	}
+	for _, r := range results {
+		got := ""
+		if r.Err != nil {
+			got = r.Err.Error()
+		}
+		if got != errs[r.Fixture.Name] {
+			t.Errorf("%s: got error %q, expected %q", r.Fixture.Name, got, errs[r.Fixture.Name])
+		}
+	}
+	var buf bytes.Buffer
+	if replay.Report(&buf, results) {
+		t.Error("report succeeded, expected failure")
+	}
+	if !strings.HasSuffix(buf.String(), "5 fixtures, 3 failed\n") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+}
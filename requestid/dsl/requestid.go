@@ -0,0 +1,99 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/requestid/expr"
+
+	// Register code generators for the request ID plugin
+	_ "goa.design/plugins/v3/requestid"
+)
+
+// RequestID enables the propagation of request IDs. The generated HTTP
+// servers read the request ID from the "X-Request-Id" header or mint a new one
+// if the header is missing or invalid, store it in the request context so that
+// it gets logged and return it in the response header. The generated HTTP
+// clients set the header of outgoing requests from the context so that the ID
+// is propagated to downstream services.
+//
+// RequestID must appear in an API expression.
+//
+// RequestID accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import requestid "goa.design/plugins/v3/requestid/dsl"
+//
+//    var _ = API("calc", func() {
+//        requestid.RequestID(func() {
+//            requestid.Header("X-Correlation-Id") // Overrides the default "X-Request-Id" header
+//            requestid.MaxLength(64)              // Maximum length of IDs accepted from clients
+//        })
+//    })
+//
+func RequestID(args ...interface{}) {
+	api, ok := eval.Current().(*goaexpr.APIExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(args) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	r := &expr.RequestIDExpr{
+		Header:    expr.DefaultHeader,
+		MaxLength: expr.DefaultMaxLength,
+		Parent:    api,
+	}
+	if len(args) == 1 {
+		dsl, ok := args[0].(func())
+		if !ok {
+			eval.InvalidArgError("function", args[0])
+			return
+		}
+		if !eval.Execute(dsl, r) {
+			return
+		}
+	}
+	expr.Root.RequestID = r
+}
+
+// Header sets the name of the header holding the request ID.
+//
+// Header must appear in a RequestID expression.
+//
+// Example:
+//
+//     RequestID(func() {
+//         Header("X-Correlation-Id")
+//     })
+//
+func Header(name string) {
+	switch r := eval.Current().(type) {
+	case *expr.RequestIDExpr:
+		r.Header = name
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// MaxLength sets the maximum length of the request IDs accepted from clients.
+// Longer IDs are replaced with new ones. The default is 128.
+//
+// MaxLength must appear in a RequestID expression.
+//
+// Example:
+//
+//     RequestID(func() {
+//         MaxLength(64)
+//     })
+//
+func MaxLength(n int) {
+	switch r := eval.Current().(type) {
+	case *expr.RequestIDExpr:
+		r.MaxLength = n
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,53 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+const (
+	// DefaultHeader is the default name of the header holding the request
+	// ID.
+	DefaultHeader = "X-Request-Id"
+	// DefaultMaxLength is the default maximum length of the request IDs
+	// accepted from clients.
+	DefaultMaxLength = 128
+)
+
+type (
+	// RequestIDExpr describes how request IDs are propagated.
+	RequestIDExpr struct {
+		// Header is the name of the header holding the request ID.
+		Header string
+		// MaxLength is the maximum length of the request IDs accepted
+		// from clients, longer IDs are replaced with new ones.
+		MaxLength int
+		// Parent expression, APIExpr.
+		Parent eval.Expression
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *RequestIDExpr) EvalName() string {
+	var suffix string
+	if r.Parent != nil {
+		suffix = fmt.Sprintf(" of %s", r.Parent.EvalName())
+	}
+	return "RequestID" + suffix
+}
+
+// Validate ensures the request ID expression is valid.
+func (r *RequestIDExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if r.Header == "" {
+		verr.Add(r, "header name cannot be empty")
+	}
+	if r.MaxLength <= 0 {
+		verr.Add(r, "maximum length must be greater than 0")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,140 @@
+package requestid
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/requestid/expr"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "requestid",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+	registry.Register(&registry.Plugin{
+		Name:     "requestid-example",
+		Cmd:      "example",
+		Generate: Example,
+	})
+}
+
+// Generate wraps the HTTP server handlers with the request ID middleware,
+// makes the HTTP clients propagate the request ID and documents the request
+// ID header in every operation of the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	r := expr.Root.RequestID
+	if r == nil {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("requestid", f) {
+			continue
+		}
+		switch filepath.Base(f.Path) {
+		case "server.go":
+			serverRequestID(f, r)
+		case "client.go":
+			clientRequestID(f, r)
+		default:
+			documentRequestID(f, r)
+		}
+	}
+	return files, nil
+}
+
+// Example replaces the goa request ID middleware of the example HTTP servers
+// with the plugin middleware.
+func Example(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("requestid", "") {
+		return files, nil
+	}
+	r := expr.Root.RequestID
+	if r == nil {
+		return files, nil
+	}
+	for _, f := range files {
+		for _, s := range f.Section("server-http-middleware") {
+			codegen.AddImport(f.SectionTemplates[0],
+				&codegen.ImportSpec{Path: "goa.design/plugins/v3/requestid"})
+			s.Source = strings.Replace(s.Source, "handler = httpmdlwr.RequestID()(handler)",
+				fmt.Sprintf("handler = requestid.Handler(%q, %d)(handler)", r.Header, r.MaxLength), 1)
+		}
+	}
+	return files, nil
+}
+
+// serverRequestID wraps the handlers mounted by the HTTP server with the
+// request ID middleware.
+func serverRequestID(f *codegen.File, r *expr.RequestIDExpr) {
+	for _, s := range f.Section("server-handler") {
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/requestid"})
+		s.Source = strings.Replace(s.Source, "f, ok := h.(http.HandlerFunc)",
+			fmt.Sprintf("h = requestid.Handler(%q, %d)(h)\n\tf, ok := h.(http.HandlerFunc)", r.Header, r.MaxLength), 1)
+	}
+}
+
+// clientRequestID makes the HTTP client set the request ID header of the
+// outgoing requests.
+func clientRequestID(f *codegen.File, r *expr.RequestIDExpr) {
+	for _, s := range f.Section("client-init") {
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/requestid"})
+		s.Source = strings.Replace(s.Source, "return &{{ .ClientStruct }}{",
+			fmt.Sprintf("doer = requestid.Doer(doer, %q)\n\treturn &{{ .ClientStruct }}{", r.Header), 1)
+	}
+}
+
+// documentRequestID documents the request ID header in all the operations if
+// f is an OpenAPI file.
+func documentRequestID(f *codegen.File, r *expr.RequestIDExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("requestid", op) {
+				return nil
+			}
+			addParameter(op, &openapi.Parameter{
+				Name:        r.Header,
+				In:          "header",
+				Description: "ID used to correlate the request across services, generated by the server if missing.",
+				Type:        "string",
+				MaxLength:   &r.MaxLength,
+			})
+			for _, resp := range op.Responses {
+				if resp.Ref != "" {
+					continue
+				}
+				if resp.Headers == nil {
+					resp.Headers = make(map[string]*openapi.Header)
+				}
+				resp.Headers[r.Header] = &openapi.Header{
+					Description: "ID of the request.",
+					Type:        "string",
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// addParameter adds the parameter to the operation unless it is already
+// defined. The JSON and YAML OpenAPI files share the same specification so the
+// operations may be visited twice.
+func addParameter(op *openapi.Operation, param *openapi.Parameter) {
+	for _, p := range op.Parameters {
+		if p.Name == param.Name && p.In == param.In {
+			return
+		}
+	}
+	op.Parameters = append(op.Parameters, param)
+}
@@ -0,0 +1,102 @@
+package requestid_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/requestid"
+	"goa.design/plugins/v3/requestid/expr"
+	"goa.design/plugins/v3/requestid/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name       string
+		DSL        func()
+		MountCode  string
+		ClientCode string
+		Header     string
+	}{
+		{"default", testdata.DefaultRequestIDDSL, testdata.DefaultMountCode, testdata.DefaultClientInitCode, "X-Request-Id"},
+		{"custom", testdata.CustomRequestIDDSL, testdata.CustomMountCode, testdata.CustomClientInitCode, "X-Correlation-Id"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.RequestID = nil
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs := httpcodegen.ServerFiles("", goaexpr.Root)
+			fs = append(fs, httpcodegen.ClientFiles("", goaexpr.Root)...)
+			ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err = requestid.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range fs {
+				var name, expected string
+				switch filepath.Base(f.Path) {
+				case "server.go":
+					name, expected = "server-handler", c.MountCode
+				case "client.go":
+					name, expected = "client-init", c.ClientCode
+				default:
+					continue
+				}
+				sections := f.Section(name)
+				if len(sections) != 1 {
+					t.Fatalf("got %d %s sections, expected 1", len(sections), name)
+				}
+				code := codegen.SectionCode(t, sections[0])
+				if code != expected {
+					t.Errorf("invalid %s code, got:\n%s\ngot vs. expected:\n%s", name, code, codegen.Diff(t, code, expected))
+				}
+			}
+			spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+			op := spec.Paths["/add/{a}/{b}"].(*openapi.Path).Get
+			var count int
+			for _, p := range op.Parameters {
+				if p.Name == c.Header && p.In == "header" {
+					count++
+				}
+			}
+			if count != 1 {
+				t.Errorf("got %d header parameters %q in OpenAPI spec, expected 1", count, c.Header)
+			}
+			for code, resp := range op.Responses {
+				if _, ok := resp.Headers[c.Header]; !ok {
+					t.Errorf("header %q not found in %s response", c.Header, code)
+				}
+			}
+		})
+	}
+}
+
+func TestExample(t *testing.T) {
+	expr.Root.RequestID = nil
+	httpcodegen.RunHTTPDSL(t, testdata.CustomRequestIDDSL)
+	fs := httpcodegen.ExampleServerFiles("gen", goaexpr.Root)
+	fs, err := requestid.Example("gen", []eval.Root{goaexpr.Root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, f := range fs {
+		for _, s := range f.Section("server-http-middleware") {
+			found = true
+			if !strings.Contains(s.Source, `requestid.Handler("X-Correlation-Id", 64)`) {
+				t.Errorf("request ID middleware not mounted in example server:\n%s", s.Source)
+			}
+		}
+	}
+	if !found {
+		t.Error("middleware section not found in example server")
+	}
+}
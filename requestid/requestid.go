@@ -0,0 +1,81 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/goa/v3/middleware"
+)
+
+// Handler returns a HTTP middleware which initializes the request context
+// with the request ID read from the given header. The ID is stored under the
+// goa middleware.RequestIDKey key so that it gets logged by the goa logging
+// middleware. A new ID is minted if the header is missing, longer than
+// maxLength or contains non printable characters unless the context already
+// holds an ID. The ID is also set in the response header.
+func Handler(header string, maxLength int) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			id := r.Header.Get(header)
+			if !valid(id, maxLength) {
+				id = FromContext(ctx)
+				if id == "" {
+					id = NewID()
+				}
+			}
+			w.Header().Set(header, id)
+			h.ServeHTTP(w, r.WithContext(context.WithValue(ctx, middleware.RequestIDKey, id)))
+		})
+	}
+}
+
+// Doer returns a HTTP client doer which sets the given header of the outgoing
+// requests to the request ID held by the request context if any.
+func Doer(doer goahttp.Doer, header string) goahttp.Doer {
+	return &idDoer{Doer: doer, header: header}
+}
+
+// FromContext returns the request ID held by the context, empty if none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(middleware.RequestIDKey).(string)
+	return id
+}
+
+// NewID returns a new random request ID.
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// idDoer is the HTTP client doer returned by Doer.
+type idDoer struct {
+	goahttp.Doer
+	header string
+}
+
+// Do sets the request ID header and sends the request.
+func (d *idDoer) Do(r *http.Request) (*http.Response, error) {
+	if id := FromContext(r.Context()); id != "" && r.Header.Get(d.header) == "" {
+		r.Header.Set(d.header, id)
+	}
+	return d.Doer.Do(r)
+}
+
+// valid returns true if id is a non empty printable ASCII string no longer
+// than maxLength.
+func valid(id string, maxLength int) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/middleware"
+)
+
+func TestHandler(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Header   string
+		CtxID    string
+		Expected string
+	}{
+		{"incoming", "abc", "", "abc"},
+		{"context", "", "ctx-id", "ctx-id"},
+		{"too-long", strings.Repeat("a", 11), "", ""},
+		{"invalid", "a b", "", ""},
+		{"minted", "", "", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var id string
+			h := Handler("X-Request-Id", 10)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id = FromContext(r.Context())
+			}))
+			r := httptest.NewRequest("GET", "/", nil)
+			if c.Header != "" {
+				r.Header.Set("X-Request-Id", c.Header)
+			}
+			if c.CtxID != "" {
+				r = r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, c.CtxID))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if id == "" {
+				t.Fatal("request ID not set in context")
+			}
+			if c.Expected != "" && id != c.Expected {
+				t.Errorf("got request ID %q, expected %q", id, c.Expected)
+			}
+			if c.Expected == "" && id == c.Header {
+				t.Errorf("invalid request ID %q was not replaced", id)
+			}
+			if got := w.Header().Get("X-Request-Id"); got != id {
+				t.Errorf("got response header %q, expected %q", got, id)
+			}
+		})
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestDoer(t *testing.T) {
+	var got string
+	d := Doer(doerFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.Header.Get("X-Request-Id")
+		return nil, nil
+	}), "X-Request-Id")
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "abc")
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	d.Do(r)
+	if got != "abc" {
+		t.Errorf("got request ID header %q, expected %q", got, "abc")
+	}
+}
@@ -0,0 +1,71 @@
+package testdata
+
+const DefaultMountCode = `// MountAddHandler configures the mux to serve the "Calc" service "Add"
+// endpoint.
+func MountAddHandler(mux goahttp.Muxer, h http.Handler) {
+	h = requestid.Handler("X-Request-Id", 128)(h)
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/add/{a}/{b}", f)
+}
+`
+
+const DefaultClientInitCode = `// NewClient instantiates HTTP clients for all the Calc service servers.
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+) *Client {
+	doer = requestid.Doer(doer, "X-Request-Id")
+	return &Client{
+		AddDoer:             doer,
+		RestoreResponseBody: restoreBody,
+		scheme:              scheme,
+		host:                host,
+		decoder:             dec,
+		encoder:             enc,
+	}
+}
+`
+
+const CustomMountCode = `// MountAddHandler configures the mux to serve the "Calc" service "Add"
+// endpoint.
+func MountAddHandler(mux goahttp.Muxer, h http.Handler) {
+	h = requestid.Handler("X-Correlation-Id", 64)(h)
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/add/{a}/{b}", f)
+}
+`
+
+const CustomClientInitCode = `// NewClient instantiates HTTP clients for all the Calc service servers.
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+) *Client {
+	doer = requestid.Doer(doer, "X-Correlation-Id")
+	return &Client{
+		AddDoer:             doer,
+		RestoreResponseBody: restoreBody,
+		scheme:              scheme,
+		host:                host,
+		decoder:             dec,
+		encoder:             enc,
+	}
+}
+`
@@ -0,0 +1,45 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	requestid "goa.design/plugins/v3/requestid/dsl"
+)
+
+var DefaultRequestIDDSL = func() {
+	API("Calc", func() {
+		requestid.RequestID()
+	})
+	Service("Calc", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
+
+var CustomRequestIDDSL = func() {
+	API("Calc", func() {
+		requestid.RequestID(func() {
+			requestid.Header("X-Correlation-Id")
+			requestid.MaxLength(64)
+		})
+	})
+	Service("Calc", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
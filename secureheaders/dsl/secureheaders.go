@@ -0,0 +1,116 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/secureheaders/expr"
+
+	// Register code generators for the secure headers plugin
+	_ "goa.design/plugins/v3/secureheaders"
+)
+
+// SecureHeaders adds security headers to the responses of all the HTTP
+// endpoints of the API or of the enclosing service. A service policy overrides
+// the API policy.
+//
+// By default the responses include:
+//
+//    Strict-Transport-Security: max-age=31536000; includeSubDomains
+//    X-Content-Type-Options: nosniff
+//    Content-Security-Policy: default-src 'none'; frame-ancestors 'none'
+//    Referrer-Policy: no-referrer
+//
+// The optional DSL function overrides the defaults. Setting a header to the
+// empty string (or a zero HSTS max-age) omits it.
+//
+// SecureHeaders must appear in an API or Service expression.
+//
+// Example:
+//
+//    import secureheaders "goa.design/plugins/v3/secureheaders/dsl"
+//
+//    var _ = API("calc", func() {
+//        secureheaders.SecureHeaders()
+//    })
+//
+//    var _ = Service("docs", func() {
+//        secureheaders.SecureHeaders(func() {
+//            secureheaders.HSTS(180*24*time.Hour, false)
+//            secureheaders.ContentSecurityPolicy("default-src 'self'")
+//            secureheaders.ReferrerPolicy("strict-origin-when-cross-origin")
+//        })
+//    })
+//
+func SecureHeaders(fn ...func()) {
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	p := expr.NewPolicyExpr()
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+		p.API = actual
+	case *goaexpr.ServiceExpr:
+		p.Service = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], p) {
+			return
+		}
+	}
+	expr.Root.Policies = append(expr.Root.Policies, p)
+}
+
+// HSTS sets the max-age of the Strict-Transport-Security header and whether
+// the policy applies to the subdomains. A zero max-age omits the header.
+//
+// HSTS must appear in a SecureHeaders expression.
+func HSTS(maxAge time.Duration, includeSubdomains bool) {
+	if p, ok := eval.Current().(*expr.PolicyExpr); ok {
+		p.HSTS = maxAge
+		p.IncludeSubdomains = includeSubdomains
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ContentTypeOptions sets the value of the X-Content-Type-Options header. The
+// empty string omits the header.
+//
+// ContentTypeOptions must appear in a SecureHeaders expression.
+func ContentTypeOptions(value string) {
+	if p, ok := eval.Current().(*expr.PolicyExpr); ok {
+		p.ContentTypeOptions = value
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ContentSecurityPolicy sets the value of the Content-Security-Policy header.
+// The empty string omits the header.
+//
+// ContentSecurityPolicy must appear in a SecureHeaders expression.
+func ContentSecurityPolicy(policy string) {
+	if p, ok := eval.Current().(*expr.PolicyExpr); ok {
+		p.ContentSecurityPolicy = policy
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// ReferrerPolicy sets the value of the Referrer-Policy header, e.g.
+// "strict-origin-when-cross-origin". The empty string omits the header.
+//
+// ReferrerPolicy must appear in a SecureHeaders expression.
+func ReferrerPolicy(policy string) {
+	if p, ok := eval.Current().(*expr.PolicyExpr); ok {
+		p.ReferrerPolicy = policy
+		return
+	}
+	eval.IncompatibleDSL()
+}
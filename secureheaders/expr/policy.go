@@ -0,0 +1,112 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// PolicyExpr describes the security headers added to the responses of
+	// the HTTP endpoints of an API or of a service.
+	PolicyExpr struct {
+		// HSTS is the max-age of the Strict-Transport-Security header, 0
+		// omits the header.
+		HSTS time.Duration
+		// IncludeSubdomains adds the includeSubDomains directive to the
+		// Strict-Transport-Security header.
+		IncludeSubdomains bool
+		// ContentTypeOptions is the value of the X-Content-Type-Options
+		// header, the empty string omits the header.
+		ContentTypeOptions string
+		// ContentSecurityPolicy is the value of the Content-Security-Policy
+		// header, the empty string omits the header.
+		ContentSecurityPolicy string
+		// ReferrerPolicy is the value of the Referrer-Policy header, the
+		// empty string omits the header.
+		ReferrerPolicy string
+		// API is the API the policy applies to, nil for service policies.
+		API *expr.APIExpr
+		// Service is the service the policy applies to, nil for API
+		// policies.
+		Service *expr.ServiceExpr
+	}
+)
+
+// referrerPolicies lists the valid values of the Referrer-Policy header.
+var referrerPolicies = map[string]bool{
+	"no-referrer":                     true,
+	"no-referrer-when-downgrade":      true,
+	"origin":                          true,
+	"origin-when-cross-origin":        true,
+	"same-origin":                     true,
+	"strict-origin":                   true,
+	"strict-origin-when-cross-origin": true,
+	"unsafe-url":                      true,
+}
+
+// NewPolicyExpr returns a policy initialized with the default values: HSTS for
+// one year including the subdomains, nosniff, a Content-Security-Policy that
+// denies loading any resource and no referrer.
+func NewPolicyExpr() *PolicyExpr {
+	return &PolicyExpr{
+		HSTS:                  365 * 24 * time.Hour,
+		IncludeSubdomains:     true,
+		ContentTypeOptions:    "nosniff",
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		ReferrerPolicy:        "no-referrer",
+	}
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PolicyExpr) EvalName() string {
+	if p.Service != nil {
+		return fmt.Sprintf("secure headers of %s", p.Service.EvalName())
+	}
+	return fmt.Sprintf("secure headers of %s", p.API.EvalName())
+}
+
+// Validate makes sure the HSTS max-age is not negative and that the referrer
+// policy is valid.
+func (p *PolicyExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if p.HSTS < 0 {
+		verr.Add(p, "HSTS max-age cannot be negative")
+	}
+	if p.ReferrerPolicy != "" {
+		for _, rp := range strings.Split(p.ReferrerPolicy, ",") {
+			if !referrerPolicies[strings.TrimSpace(rp)] {
+				verr.Add(p, "invalid referrer policy %q", strings.TrimSpace(rp))
+			}
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Headers returns the security headers indexed by name.
+func (p *PolicyExpr) Headers() map[string]string {
+	headers := make(map[string]string)
+	if p.HSTS > 0 {
+		hsts := fmt.Sprintf("max-age=%d", int64(p.HSTS/time.Second))
+		if p.IncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		headers["Strict-Transport-Security"] = hsts
+	}
+	if p.ContentTypeOptions != "" {
+		headers["X-Content-Type-Options"] = p.ContentTypeOptions
+	}
+	if p.ContentSecurityPolicy != "" {
+		headers["Content-Security-Policy"] = p.ContentSecurityPolicy
+	}
+	if p.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = p.ReferrerPolicy
+	}
+	return headers
+}
@@ -0,0 +1,64 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the security header policies defined in the design.
+	RootExpr struct {
+		// Policies lists the API and service security header policies.
+		Policies []*PolicyExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "secure headers plugin"
+}
+
+// WalkSets iterates over the security header policies.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	pexps := make(eval.ExpressionSet, len(r.Policies))
+	for i, p := range r.Policies {
+		pexps[i] = p
+	}
+	walk(pexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/secureheaders/dsl"}
+}
+
+// Policy returns the security header policy of the given service: the service
+// policy if defined, the API policy otherwise. It returns nil if there is no
+// policy.
+func (r *RootExpr) Policy(svc string) *PolicyExpr {
+	var api *PolicyExpr
+	for _, p := range r.Policies {
+		switch {
+		case p.Service != nil && p.Service.Name == svc:
+			return p
+		case p.API != nil:
+			api = p
+		}
+	}
+	return api
+}
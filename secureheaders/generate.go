@@ -0,0 +1,83 @@
+package secureheaders
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/secureheaders/expr"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "secureheaders",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate adds the security headers to the responses of the HTTP endpoints
+// and file servers of the services that have a security header policy.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Policies) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("secureheaders", f) {
+			continue
+		}
+		serverHeaders(f)
+	}
+	return files, nil
+}
+
+// serverHeaders defines the security headers of the service in the HTTP
+// server file and makes the handlers add them to the responses.
+func serverHeaders(f *codegen.File) {
+	if filepath.Base(f.Path) != "server.go" {
+		return
+	}
+	var p *expr.PolicyExpr
+	for _, s := range f.Section("server-struct") {
+		data, ok := s.Data.(*httpcodegen.ServiceData)
+		if !ok {
+			continue
+		}
+		p = expr.Root.Policy(data.Service.Name)
+		if p == nil {
+			return
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/secureheaders"})
+		f.SectionTemplates = append(f.SectionTemplates, &codegen.SectionTemplate{
+			Name:   "secure-headers",
+			Source: secureHeadersT,
+			Data:   map[string]interface{}{"Service": data.Service.Name, "Headers": p.Headers()},
+		})
+	}
+	if p == nil {
+		return
+	}
+	for _, s := range f.Section("server-handler-init") {
+		handler := "return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {"
+		s.Source = strings.Replace(s.Source, handler, handler+"\n\t\tsecureheaders.Set(w, SecureHeaders)", 1)
+	}
+	for _, s := range f.Section("server-files") {
+		mount := "func {{ .MountHandler }}(mux goahttp.Muxer, h http.Handler) {"
+		s.Source = strings.Replace(s.Source, mount, mount+"\n\th = secureheaders.Handler(h, SecureHeaders)", 1)
+	}
+}
+
+// input: map[string]interface{}{"Service": string, "Headers": map[string]string}
+const secureHeadersT = `{{ printf "SecureHeaders lists the security headers added to the responses of the %q service HTTP endpoints. The headers already set by the endpoints are left unchanged." .Service | comment }}
+var SecureHeaders = map[string]string{
+{{- range $name, $value := .Headers }}
+	{{ printf "%q" $name }}: {{ printf "%q" $value }},
+{{- end }}
+}
+`
@@ -0,0 +1,66 @@
+package secureheaders_test
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/secureheaders"
+	"goa.design/plugins/v3/secureheaders/expr"
+	"goa.design/plugins/v3/secureheaders/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := map[string]struct {
+		DSL      func()
+		Sections map[string]map[string]string
+	}{
+		"service": {testdata.DocsDSL, map[string]map[string]string{
+			"docs": {
+				"secure-headers":      testdata.DocsSecureHeadersCode,
+				"server-handler-init": testdata.ShowHandlerCode,
+				"server-files":        testdata.DocsFilesCode,
+			},
+			"calc": {},
+		}},
+		"api": {testdata.APIDSL, map[string]map[string]string{
+			"calc": {"secure-headers": testdata.CalcSecureHeadersCode},
+		}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			expr.Root.Policies = nil
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			fs, err := secureheaders.Generate("", []eval.Root{goaexpr.Root}, httpcodegen.ServerFiles("", goaexpr.Root))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range fs {
+				if !strings.HasSuffix(f.Path, "server.go") {
+					continue
+				}
+				svc := strings.Split(f.Path, "/")[2]
+				expected, ok := c.Sections[svc]
+				if !ok {
+					t.Fatalf("unexpected server file %s", f.Path)
+				}
+				if len(expected) == 0 && len(f.Section("secure-headers")) > 0 {
+					t.Errorf("%s: unexpected secure headers", svc)
+				}
+				for section, code := range expected {
+					ss := f.Section(section)
+					if len(ss) != 1 {
+						t.Fatalf("%s: got %d %s sections, expected 1", svc, len(ss), section)
+					}
+					got := codegen.SectionCode(t, ss[0])
+					if got != code {
+						t.Errorf("%s: invalid %s code, got:\n%s\ngot vs. expected:\n%s", svc, section, got, codegen.Diff(t, got, code))
+					}
+				}
+			}
+		})
+	}
+}
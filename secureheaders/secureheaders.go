@@ -0,0 +1,24 @@
+package secureheaders
+
+import "net/http"
+
+// Set adds the given headers to the response unless the response already
+// defines them.
+func Set(w http.ResponseWriter, headers map[string]string) {
+	h := w.Header()
+	for name, value := range headers {
+		if _, ok := h[http.CanonicalHeaderKey(name)]; !ok {
+			h.Set(name, value)
+		}
+	}
+}
+
+// Handler returns a middleware that adds the given headers to the responses of
+// h. It can be used to apply the headers generated for a service to handlers
+// that are not generated, e.g. file servers.
+func Handler(h http.Handler, headers map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Set(w, headers)
+		h.ServeHTTP(w, r)
+	})
+}
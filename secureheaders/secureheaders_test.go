@@ -0,0 +1,37 @@
+package secureheaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	headers := map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"Content-Security-Policy": "default-src 'none'",
+	}
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+	}), headers)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("got X-Content-Type-Options %q, expected %q", got, "nosniff")
+	}
+	if got := w.Header().Get("Content-Security-Policy"); got != "default-src 'self'" {
+		t.Errorf("got Content-Security-Policy %q, expected the handler value", got)
+	}
+}
+
+func TestSetKeepsExisting(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Referrer-Policy", "same-origin")
+	Set(w, map[string]string{"Referrer-Policy": "no-referrer", "X-Content-Type-Options": "nosniff"})
+	if got := w.Header().Get("Referrer-Policy"); got != "same-origin" {
+		t.Errorf("got Referrer-Policy %q, expected %q", got, "same-origin")
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("got X-Content-Type-Options %q, expected %q", got, "nosniff")
+	}
+}
@@ -0,0 +1,73 @@
+package testdata
+
+var DocsSecureHeadersCode = `// SecureHeaders lists the security headers added to the responses of the
+// "Docs" service HTTP endpoints. The headers already set by the endpoints are
+// left unchanged.
+var SecureHeaders = map[string]string{
+	"Content-Security-Policy":   "default-src 'self'",
+	"Referrer-Policy":           "strict-origin-when-cross-origin",
+	"Strict-Transport-Security": "max-age=15552000",
+	"X-Content-Type-Options":    "nosniff",
+}
+`
+
+var ShowHandlerCode = `// NewShowHandler creates a HTTP handler which loads the HTTP request and calls
+// the "Docs" service "Show" endpoint.
+func NewShowHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		decodeRequest  = DecodeShowRequest(mux, dec)
+		encodeResponse = EncodeShowResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secureheaders.Set(w, SecureHeaders)
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "Show")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Docs")
+		payload, err := decodeRequest(r)
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+
+		res, err := endpoint(ctx, payload)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
+
+var DocsFilesCode = `// MountGenHTTPOpenapiJSON configures the mux to serve GET request made to
+// "/swagger.json".
+func MountGenHTTPOpenapiJSON(mux goahttp.Muxer, h http.Handler) {
+	h = secureheaders.Handler(h, SecureHeaders)
+	mux.Handle("GET", "/swagger.json", h.ServeHTTP)
+}
+`
+
+var CalcSecureHeadersCode = `// SecureHeaders lists the security headers added to the responses of the
+// "Calc" service HTTP endpoints. The headers already set by the endpoints are
+// left unchanged.
+var SecureHeaders = map[string]string{
+	"Content-Security-Policy":   "default-src 'none'; frame-ancestors 'none'",
+	"Referrer-Policy":           "no-referrer",
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+	"X-Content-Type-Options":    "nosniff",
+}
+`
@@ -0,0 +1,56 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	secureheaders "goa.design/plugins/v3/secureheaders/dsl"
+)
+
+var DocsDSL = func() {
+	Service("Docs", func() {
+		secureheaders.SecureHeaders(func() {
+			secureheaders.HSTS(180*24*time.Hour, false)
+			secureheaders.ContentSecurityPolicy("default-src 'self'")
+			secureheaders.ReferrerPolicy("strict-origin-when-cross-origin")
+		})
+		Method("Show", func() {
+			Payload(String)
+			Result(String)
+			HTTP(func() {
+				GET("/docs/{id}")
+			})
+		})
+		Files("/swagger.json", "gen/http/openapi.json")
+	})
+	Service("Calc", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
+
+var APIDSL = func() {
+	API("calc", func() {
+		secureheaders.SecureHeaders()
+	})
+	Service("Calc", func() {
+		Method("Add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
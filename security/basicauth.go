@@ -0,0 +1,154 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+	goasecurity "goa.design/goa/v3/security"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UnauthorizedErrorName is the name of the error returned when the basic auth
+// credentials are invalid. Map it to the 401 Unauthorized status with the goa
+// Error and Response DSL functions.
+const UnauthorizedErrorName = "unauthorized"
+
+type (
+	// UserStore gives access to the bcrypt password hashes of the users.
+	UserStore interface {
+		// PasswordHash returns the bcrypt hash of the password of the
+		// user with the given name, nil if there is no such user.
+		PasswordHash(ctx context.Context, user string) ([]byte, error)
+	}
+
+	// BasicAuthVerifier verifies basic auth credentials against the bcrypt
+	// password hashes of a user store.
+	BasicAuthVerifier struct {
+		// Store is the user store.
+		Store UserStore
+	}
+
+	// Htpasswd is a UserStore holding the bcrypt entries of a htpasswd
+	// file indexed by user name.
+	Htpasswd map[string][]byte
+
+	// HtpasswdFile is a UserStore reading a htpasswd file. The file is
+	// read again when it changes.
+	HtpasswdFile struct {
+		// Path is the path to the file.
+		Path string
+
+		mu      sync.Mutex
+		modTime time.Time
+		users   Htpasswd
+	}
+)
+
+var (
+	// dummyHash is the hash compared when the user does not exist so that
+	// the time taken to reject unknown users and invalid passwords is the
+	// same.
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+// NewBasicAuthVerifier returns a verifier using the given user store.
+func NewBasicAuthVerifier(store UserStore) *BasicAuthVerifier {
+	return &BasicAuthVerifier{Store: store}
+}
+
+// BasicAuth verifies the given credentials. It has the signature of the goa
+// basic auth functions so that the service BasicAuth methods may delegate to
+// it. It returns a copy of ctx holding the user name as principal, see
+// WithPrincipal, if the credentials are valid and an "unauthorized" error
+// otherwise. The scopes of the scheme are not checked.
+func (v *BasicAuthVerifier) BasicAuth(ctx context.Context, user, pass string, _ *goasecurity.BasicScheme) (context.Context, error) {
+	if v.Store == nil {
+		return ctx, goa.Fault("basic auth verifier has no user store")
+	}
+	hash, err := v.Store.PasswordHash(ctx, user)
+	if err != nil {
+		return ctx, goa.Fault("failed to retrieve password hash: %s", err)
+	}
+	known := hash != nil
+	if !known {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+		})
+		hash = dummyHash
+	}
+	// CompareHashAndPassword compares the hashes in constant time.
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(pass)); err != nil || !known {
+		return ctx, goa.PermanentError(UnauthorizedErrorName, "invalid username and password combination")
+	}
+	return WithPrincipal(ctx, user), nil
+}
+
+// ParseHtpasswd reads the htpasswd entries of r. The lines are of the form
+// "user:hash", the empty lines and the lines starting with "#" are ignored.
+// ParseHtpasswd returns an error if a hash is not a bcrypt hash as generated by
+// "htpasswd -B".
+func ParseHtpasswd(r io.Reader) (Htpasswd, error) {
+	users := make(Htpasswd)
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("line %d: invalid entry, must be of the form user:hash", n)
+		}
+		if _, err := bcrypt.Cost([]byte(parts[1])); err != nil {
+			return nil, fmt.Errorf("line %d: user %q: only bcrypt hashes are supported", n, parts[0])
+		}
+		users[parts[0]] = []byte(parts[1])
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// PasswordHash returns the hash of the password of the given user.
+func (h Htpasswd) PasswordHash(_ context.Context, user string) ([]byte, error) {
+	return h[user], nil
+}
+
+// NewHtpasswdFile returns a user store reading the htpasswd file with the given
+// path, see ParseHtpasswd.
+func NewHtpasswdFile(path string) *HtpasswdFile {
+	return &HtpasswdFile{Path: path}
+}
+
+// PasswordHash returns the hash of the password of the given user. It reads
+// the file if it changed since it was last read.
+func (f *HtpasswdFile) PasswordHash(ctx context.Context, user string) ([]byte, error) {
+	fi, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.users == nil || !fi.ModTime().Equal(f.modTime) {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		users, err := ParseHtpasswd(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f.Path, err)
+		}
+		f.users, f.modTime = users, fi.ModTime()
+	}
+	return f.users.PasswordHash(ctx, user)
+}
@@ -0,0 +1,335 @@
+package security
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"goa.design/goa/v3/http/middleware"
+	goa "goa.design/goa/v3/pkg"
+	goasecurity "goa.design/goa/v3/security"
+)
+
+// TooManyAttemptsErrorName is the name of the error returned when a client
+// must wait before trying to authenticate again. Map it to the 429 Too Many
+// Requests status with the goa Error and Response DSL functions.
+const TooManyAttemptsErrorName = "too_many_attempts"
+
+type (
+	// Attempts records the consecutive failed attempts of a client and its
+	// attempts in progress.
+	Attempts struct {
+		// Failures is the number of consecutive failed attempts.
+		Failures int
+		// Last is the time of the last failed attempt.
+		Last time.Time
+		// Pending is the number of attempts in progress, started with
+		// Begin and not ended yet.
+		Pending int
+	}
+
+	// AttemptStore records the attempts of the clients. The keys identify
+	// the clients, e.g. "ip:192.0.2.1" or "user:alice". Begin must be
+	// atomic so that concurrent attempts observe each other.
+	AttemptStore interface {
+		// Attempts returns the attempts recorded for the given key, nil
+		// if there is none.
+		Attempts(ctx context.Context, key string) (*Attempts, error)
+		// Begin records an attempt in progress started at the given
+		// time for the given key and returns the updated attempts,
+		// including the new pending attempt.
+		Begin(ctx context.Context, key string, at time.Time) (*Attempts, error)
+		// End ends an attempt started with Begin for the given key and
+		// records it as a failed attempt made at the given time if
+		// failed is true.
+		End(ctx context.Context, key string, at time.Time, failed bool) error
+		// Reset forgets the failed attempts recorded for the given key.
+		Reset(ctx context.Context, key string) error
+	}
+
+	// MemoryAttemptStore is an AttemptStore keeping the attempts in
+	// memory. It is not shared between the instances of a service.
+	MemoryAttemptStore struct {
+		// TTL is the duration after which the failed attempts are
+		// forgotten once the last one is made.
+		TTL time.Duration
+
+		mu       sync.Mutex
+		attempts map[string]Attempts
+	}
+
+	// BruteForceGuard protects the basic auth functions against
+	// brute-force attacks. It tracks the failed attempts per client IP and
+	// username and rejects the attempts of the clients that failed recently
+	// until an exponentially increasing delay elapses. The clients are
+	// locked out once they fail MaxAttempts times in a row.
+	BruteForceGuard struct {
+		// Store records the attempts.
+		Store AttemptStore
+		// MaxAttempts is the number of consecutive failed attempts after
+		// which the client is locked out. It must be positive.
+		MaxAttempts int
+		// Backoff is the delay imposed after the first failed attempt,
+		// it doubles with each subsequent failed attempt.
+		Backoff time.Duration
+		// Lockout is the duration of the lockout. The failed attempts
+		// are forgotten once it elapses after the last one.
+		Lockout time.Duration
+		// ClientIP returns the IP of the client making the request
+		// given its context, the empty string if unknown. The default
+		// returns the host of the remote address stored in the context
+		// by the goa PopulateRequestContext HTTP middleware. Set it to
+		// use the X-Forwarded-For header behind a trusted proxy.
+		ClientIP func(ctx context.Context) string
+	}
+)
+
+// NewMemoryAttemptStore returns an attempt store forgetting the failed
+// attempts once the given duration elapses after the last one.
+func NewMemoryAttemptStore(ttl time.Duration) *MemoryAttemptStore {
+	return &MemoryAttemptStore{TTL: ttl, attempts: make(map[string]Attempts)}
+}
+
+// Attempts returns the attempts recorded for the given key.
+func (s *MemoryAttemptStore) Attempts(_ context.Context, key string) (*Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.load(key, time.Now())
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// Begin records an attempt in progress for the given key.
+func (s *MemoryAttemptStore) Begin(_ context.Context, key string, at time.Time) (*Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attempts == nil {
+		s.attempts = make(map[string]Attempts)
+	}
+	if len(s.attempts) >= maxCacheEntries {
+		s.prune(key, at)
+	}
+	a, _ := s.load(key, at)
+	a.Pending++
+	s.attempts[key] = a
+	return &a, nil
+}
+
+// End ends an attempt in progress for the given key.
+func (s *MemoryAttemptStore) End(_ context.Context, key string, at time.Time, failed bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, _ := s.load(key, at)
+	if a.Pending > 0 {
+		a.Pending--
+	}
+	if failed {
+		a.Failures++
+		a.Last = at
+	}
+	s.store(key, a)
+	return nil
+}
+
+// Reset forgets the failed attempts recorded for the given key.
+func (s *MemoryAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.attempts[key]
+	s.store(key, Attempts{Pending: a.Pending})
+	return nil
+}
+
+// prune evicts the expired attempts. It then evicts the attempts whose last
+// failure is the oldest if the store is still full so that the clients may
+// not grow it without bounds, e.g. when TTL is 0. The attempts in progress and
+// the attempts of the given key are kept.
+func (s *MemoryAttemptStore) prune(key string, now time.Time) {
+	for k, a := range s.attempts {
+		if a.Pending == 0 && s.expired(a, now) {
+			delete(s.attempts, k)
+		}
+	}
+	if len(s.attempts) < maxCacheEntries {
+		return
+	}
+	keys := make([]string, 0, len(s.attempts))
+	for k, a := range s.attempts {
+		if a.Pending == 0 && k != key {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return s.attempts[keys[i]].Last.Before(s.attempts[keys[j]].Last)
+	})
+	n := len(s.attempts) - maxCacheEntries*3/4
+	if n > len(keys) {
+		n = len(keys)
+	}
+	for _, k := range keys[:n] {
+		delete(s.attempts, k)
+	}
+}
+
+// load returns the attempts recorded for the given key at the given time
+// without the expired failures and true if there are any.
+func (s *MemoryAttemptStore) load(key string, now time.Time) (Attempts, bool) {
+	a, ok := s.attempts[key]
+	if !ok {
+		return Attempts{}, false
+	}
+	if s.expired(a, now) {
+		a = Attempts{Pending: a.Pending}
+		s.store(key, a)
+	}
+	return a, a.Failures > 0 || a.Pending > 0
+}
+
+// store records the given attempts or forgets them if there is none.
+func (s *MemoryAttemptStore) store(key string, a Attempts) {
+	if a.Failures == 0 && a.Pending == 0 {
+		delete(s.attempts, key)
+		return
+	}
+	if s.attempts == nil {
+		s.attempts = make(map[string]Attempts)
+	}
+	s.attempts[key] = a
+}
+
+// expired returns true if the failed attempts in a must be forgotten at the
+// given time.
+func (s *MemoryAttemptStore) expired(a Attempts, now time.Time) bool {
+	return s.TTL > 0 && !now.Before(a.Last.Add(s.TTL))
+}
+
+// Protect returns a basic auth function which rejects the attempts of the
+// clients that must wait with a temporary "too_many_attempts" error and calls
+// fn otherwise. The failed attempts are recorded for both the client IP and
+// the username, a successful attempt resets the attempts of the username only
+// so that a client may not guess the passwords of many users by
+// authenticating regularly with its own credentials. The errors reporting
+// server faults are not counted as failed attempts.
+//
+// The attempts are recorded in the store before fn is called so that
+// concurrent attempts may not bypass the backoff or the lockout: once a client
+// has failed, its attempts are accepted one at a time, and the attempts in
+// progress count towards the lockout.
+func (g *BruteForceGuard) Protect(fn goasecurity.AuthBasicFunc) goasecurity.AuthBasicFunc {
+	return func(ctx context.Context, user, pass string, s *goasecurity.BasicScheme) (context.Context, error) {
+		if g.Store == nil {
+			return ctx, goa.Fault("brute-force guard has no attempt store")
+		}
+		if g.MaxAttempts <= 0 {
+			return ctx, goa.Fault("brute-force guard MaxAttempts must be positive")
+		}
+		keys := []string{"user:" + user}
+		if ip := g.clientIP(ctx); ip != "" {
+			keys = append(keys, "ip:"+ip)
+		}
+		now := time.Now()
+		var (
+			begun []string
+			wait  time.Duration
+		)
+		end := func(failed bool) error {
+			for _, k := range begun {
+				if err := g.Store.End(ctx, k, now, failed); err != nil {
+					return goa.Fault("failed to record attempt: %s", err)
+				}
+			}
+			return nil
+		}
+		for _, k := range keys {
+			a, err := g.Store.Begin(ctx, k, now)
+			if err != nil {
+				end(false)
+				return ctx, goa.Fault("failed to record attempt: %s", err)
+			}
+			begun = append(begun, k)
+			if d := g.admit(a, now); d > wait {
+				wait = d
+			}
+		}
+		if wait > 0 {
+			if err := end(false); err != nil {
+				return ctx, err
+			}
+			return ctx, goa.TemporaryError(TooManyAttemptsErrorName, "too many failed attempts, retry in %s", (wait + time.Second - 1).Truncate(time.Second))
+		}
+		newctx, err := fn(ctx, user, pass, s)
+		if err != nil {
+			if serr, ok := err.(*goa.ServiceError); ok && serr.Fault {
+				end(false)
+				return newctx, err
+			}
+			if eerr := end(true); eerr != nil {
+				return newctx, eerr
+			}
+			return newctx, err
+		}
+		if err := end(false); err != nil {
+			return newctx, err
+		}
+		if err := g.Store.Reset(ctx, keys[0]); err != nil {
+			return newctx, goa.Fault("failed to reset failed attempts: %s", err)
+		}
+		return newctx, nil
+	}
+}
+
+// admit returns the duration a client must wait before making the attempt
+// recorded in a by Begin, 0 if it may make it now. The attempt must wait if
+// the failed attempts impose a delay, if another attempt is in progress
+// while the client has failed, or if the attempts in progress could reach
+// the lockout.
+func (g *BruteForceGuard) admit(a *Attempts, now time.Time) time.Duration {
+	if d := g.Wait(a, now); d > 0 {
+		return d
+	}
+	if others := a.Pending - 1; others > 0 && (a.Failures > 0 || a.Failures+others >= g.MaxAttempts) {
+		if g.Backoff > time.Second {
+			return g.Backoff
+		}
+		return time.Second
+	}
+	return 0
+}
+
+// Wait returns the duration a client with the given failed attempts must wait
+// at the given time before trying again, 0 if it may try now.
+func (g *BruteForceGuard) Wait(a *Attempts, now time.Time) time.Duration {
+	if a == nil || a.Failures == 0 || !now.Before(a.Last.Add(g.Lockout)) {
+		return 0
+	}
+	delay := g.Lockout
+	if a.Failures < g.MaxAttempts {
+		delay = g.Backoff
+		for i := 1; i < a.Failures && delay < g.Lockout; i++ {
+			delay *= 2
+		}
+		if delay > g.Lockout {
+			delay = g.Lockout
+		}
+	}
+	if wait := a.Last.Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// clientIP returns the IP of the client making the request.
+func (g *BruteForceGuard) clientIP(ctx context.Context) string {
+	if g.ClientIP != nil {
+		return g.ClientIP(ctx)
+	}
+	addr, _ := ctx.Value(middleware.RequestRemoteAddrKey).(string)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,35 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryAttemptStoreLimit(t *testing.T) {
+	s := NewMemoryAttemptStore(0)
+	ctx := context.Background()
+	now := time.Now()
+	if _, err := s.Begin(ctx, "pending", now); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < maxCacheEntries; i++ {
+		s.store(fmt.Sprintf("ip:%d", i), Attempts{Failures: 1, Last: now.Add(time.Duration(i) * time.Second)})
+	}
+	if _, err := s.Begin(ctx, "new", now); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(s.attempts); n > maxCacheEntries*3/4+1 {
+		t.Errorf("got %d recorded attempts, expected at most %d", n, maxCacheEntries*3/4+1)
+	}
+	if a, _ := s.Attempts(ctx, "pending"); a == nil || a.Pending != 1 {
+		t.Errorf("got attempts %v, expected attempt in progress to be kept", a)
+	}
+	if a, _ := s.Attempts(ctx, "ip:1"); a != nil {
+		t.Errorf("got attempts %v, expected oldest failures to be evicted", a)
+	}
+	if a, _ := s.Attempts(ctx, fmt.Sprintf("ip:%d", maxCacheEntries-1)); a == nil {
+		t.Error("got no attempts, expected latest failures to be kept")
+	}
+}
@@ -0,0 +1,896 @@
+package dsl
+
+import (
+	"encoding/json"
+	"time"
+
+	goadsl "goa.design/goa/v3/dsl"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/security/expr"
+
+	// Register code generators for the security plugin
+	"goa.design/plugins/v3/security"
+)
+
+// SecurityGroup defines a named security requirement that the API, services
+// and methods reference with UseSecurityGroup. Groups keep the scope policy in
+// one place instead of repeating the same Security expression in many
+// services.
+//
+// SecurityGroup must appear at the top level of the design, typically as the
+// initializer of a package variable.
+//
+// SecurityGroup takes the name of the group followed by the security schemes
+// or the names of the security schemes and an optional DSL function which may
+// use Scope to list the required scopes, in the same way as Security. The
+// schemes given by name must be defined before the group.
+//
+// Example:
+//
+//    import security "goa.design/plugins/v3/security/dsl"
+//
+//    var JWT = JWTSecurity("jwt", func() {
+//        Scope("svc:internal", "Access to the internal services")
+//    })
+//
+//    var _ = security.SecurityGroup("internal", JWT, func() {
+//        Scope("svc:internal")
+//    })
+//
+func SecurityGroup(name string, args ...interface{}) *expr.SecurityGroupExpr {
+	if _, ok := eval.Current().(eval.TopExpr); !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if expr.Root.Group(name) != nil {
+		eval.ReportError("security group %q is defined twice", name)
+		return nil
+	}
+	var fn func()
+	if len(args) > 0 {
+		if d, ok := args[len(args)-1].(func()); ok {
+			args, fn = args[:len(args)-1], d
+		}
+	}
+	req := &goaexpr.SecurityExpr{}
+	for _, arg := range args {
+		switch val := arg.(type) {
+		case string:
+			s := scheme(val)
+			if s == nil {
+				eval.ReportError("security scheme %q not found", val)
+				return nil
+			}
+			req.Schemes = append(req.Schemes, s)
+		case *goaexpr.SchemeExpr:
+			req.Schemes = append(req.Schemes, val)
+		default:
+			eval.InvalidArgError("security scheme or security scheme name", val)
+			return nil
+		}
+	}
+	if fn != nil {
+		if !eval.Execute(fn, req) {
+			return nil
+		}
+	}
+	g := &expr.SecurityGroupExpr{Name: name, Requirement: req}
+	expr.Root.Groups = append(expr.Root.Groups, g)
+	return g
+}
+
+// UseSecurityGroup adds the security requirement of the group with the given
+// name to the enclosing API, service or method. It has the same effect as
+// repeating the Security expression of the group.
+//
+// UseSecurityGroup must appear in an API, Service or Method expression.
+//
+// Example:
+//
+//    var _ = Service("billing", func() {
+//        security.UseSecurityGroup("internal")
+//        Method("charge", func() {
+//            HTTP(func() {
+//                POST("/charges")
+//            })
+//        })
+//    })
+//
+func UseSecurityGroup(name string) {
+	g := expr.Root.Group(name)
+	if g == nil {
+		eval.ReportError("security group %q not found", name)
+		return
+	}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+		actual.Requirements = append(actual.Requirements, g.Copy())
+	case *goaexpr.ServiceExpr:
+		actual.Requirements = append(actual.Requirements, g.Copy())
+	case *goaexpr.MethodExpr:
+		actual.Requirements = append(actual.Requirements, g.Copy())
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// OPA makes the generated endpoints of the services query the Open Policy
+// Agent policy at the given URL once the requests are authenticated. The
+// endpoints call the service methods only if the policy allows the request.
+// The URL is the URL of the OPA data API document holding the decision, the
+// document must be a boolean.
+//
+// OPA must appear in an API or Service expression. The policy of a service
+// overrides the policy of the API. OPA takes an optional DSL function which may
+// use DecisionCache.
+//
+// Example:
+//
+//    var _ = Service("billing", func() {
+//        security.OPA("http://localhost:8181/v1/data/billing/allow", func() {
+//            security.DecisionCache(30 * time.Second)
+//        })
+//    })
+//
+func OPA(url string, fn ...func()) {
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	o := &expr.OPAExpr{URL: url}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		o.Service = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, e := range expr.Root.OPAs {
+		if e.Service == o.Service {
+			eval.ReportError("OPA policy is defined twice")
+			return
+		}
+	}
+	if len(fn) > 0 {
+		if !eval.Execute(fn[0], o) {
+			return
+		}
+	}
+	expr.Root.OPAs = append(expr.Root.OPAs, o)
+}
+
+// DecisionCache caches the policy decisions for the given duration. The
+// decisions are cached per input so that the same principal calling the same
+// endpoint with the same payload summary does not query the policy again.
+//
+// DecisionCache must appear in an OPA expression.
+//
+// Example:
+//
+//    security.OPA("http://localhost:8181/v1/data/billing/allow", func() {
+//        security.DecisionCache(time.Minute)
+//    })
+//
+func DecisionCache(ttl time.Duration) {
+	o, ok := eval.Current().(*expr.OPAExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	o.CacheTTL = ttl
+}
+
+// HtpasswdFile generates a BasicAuthVerifier variable in the packages of the
+// services which verifies the basic auth credentials against the bcrypt
+// password hashes of the htpasswd file with the given path. The file is read
+// at runtime and read again when it changes. The hashes are generated with
+// "htpasswd -B".
+//
+// HtpasswdFile must appear in an API or Service expression. The file of a
+// service overrides the file of the API.
+//
+// Example:
+//
+//    var _ = Service("billing", func() {
+//        security.HtpasswdFile("/etc/billing/users.htpasswd")
+//        Security(BasicAuth)
+//    })
+//
+func HtpasswdFile(path string) {
+	b := &expr.BasicAuthExpr{Path: path}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		b.Service = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, e := range expr.Root.BasicAuths {
+		if e.Service == b.Service {
+			eval.ReportError("htpasswd file is defined twice")
+			return
+		}
+	}
+	expr.Root.BasicAuths = append(expr.Root.BasicAuths, b)
+}
+
+// BruteForceProtection generates a BruteForceGuard variable in the packages of
+// the services which tracks the failed basic auth attempts per client IP and
+// username. The endpoints requiring basic auth reject the requests of the
+// clients that failed recently until an exponentially increasing delay elapses
+// and lock the clients out once they fail MaxAttempts times in a row. The
+// failed attempts are forgotten once the lockout duration elapses after the
+// last one. By default the clients are locked out for 15 minutes after 5
+// failed attempts and the delay starts at one second.
+//
+// BruteForceProtection must appear in an API or Service expression. The
+// protection of a service overrides the protection of the API.
+// BruteForceProtection accepts an optional DSL function which may use
+// MaxAttempts, Backoff and Lockout.
+//
+// Example:
+//
+//    var _ = Service("billing", func() {
+//        security.BruteForceProtection(func() {
+//            security.MaxAttempts(3)
+//            security.Lockout(time.Hour)
+//        })
+//        Security(BasicAuth)
+//    })
+//
+func BruteForceProtection(fn ...func()) {
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	b := &expr.BruteForceExpr{MaxAttempts: 5, Backoff: time.Second, Lockout: 15 * time.Minute}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		b.Service = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, e := range expr.Root.BruteForces {
+		if e.Service == b.Service {
+			eval.ReportError("brute-force protection is defined twice")
+			return
+		}
+	}
+	if len(fn) > 0 {
+		if !eval.Execute(fn[0], b) {
+			return
+		}
+	}
+	expr.Root.BruteForces = append(expr.Root.BruteForces, b)
+}
+
+// MaxAttempts sets the number of consecutive failed attempts after which the
+// client IP or username is locked out.
+//
+// MaxAttempts must appear in a BruteForceProtection expression.
+//
+// Example:
+//
+//    security.BruteForceProtection(func() {
+//        security.MaxAttempts(10)
+//    })
+//
+func MaxAttempts(n int) {
+	b, ok := eval.Current().(*expr.BruteForceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	b.MaxAttempts = n
+}
+
+// Backoff sets the delay imposed after the first failed attempt, the delay
+// doubles with each subsequent failed attempt. A zero delay only locks the
+// clients out after MaxAttempts failed attempts.
+//
+// Backoff must appear in a BruteForceProtection expression.
+//
+// Example:
+//
+//    security.BruteForceProtection(func() {
+//        security.Backoff(500 * time.Millisecond)
+//    })
+//
+func Backoff(d time.Duration) {
+	b, ok := eval.Current().(*expr.BruteForceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	b.Backoff = d
+}
+
+// Lockout sets the duration the clients are locked out for once they fail
+// MaxAttempts times in a row.
+//
+// Lockout must appear in a BruteForceProtection expression.
+//
+// Example:
+//
+//    security.BruteForceProtection(func() {
+//        security.Lockout(time.Hour)
+//    })
+//
+func Lockout(d time.Duration) {
+	b, ok := eval.Current().(*expr.BruteForceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	b.Lockout = d
+}
+
+// ForwardToken makes the generated clients of the services forward the token
+// of the incoming request to the endpoints secured with JWT or OAuth2 so that
+// the call chains between services preserve the identity of the caller. The
+// token is forwarded when the payload given to the client does not set it.
+//
+// ForwardToken must appear in an API or Service expression. It applies to the
+// clients of the service or of all the services, the propagation of a service
+// overrides the propagation of the API.
+//
+// Example:
+//
+//    var _ = Service("ledger", func() {
+//        security.ForwardToken()
+//        Security(JWT)
+//    })
+//
+func ForwardToken() {
+	propagate(&expr.PropagationExpr{})
+}
+
+// ExchangeToken makes the generated clients of the services exchange the token
+// of the incoming request for a token issued for the given audience using the
+// OAuth 2.0 token exchange (RFC 8693) endpoint at the given URL and send the
+// exchanged token to the endpoints secured with JWT or OAuth2. The exchanged
+// tokens are cached until they expire.
+//
+// ExchangeToken must appear in an API or Service expression. It applies to the
+// clients of the service or of all the services, the propagation of a service
+// overrides the propagation of the API.
+//
+// Example:
+//
+//    var _ = Service("ledger", func() {
+//        security.ExchangeToken("https://sts.example.com/token", "ledger")
+//        Security(JWT)
+//    })
+//
+func ExchangeToken(url, audience string) {
+	propagate(&expr.PropagationExpr{ExchangeURL: url, Audience: audience})
+}
+
+// propagate records the given token propagation of the current API or service
+// expression.
+func propagate(p *expr.PropagationExpr) {
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		p.Service = actual
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, e := range expr.Root.Propagations {
+		if e.Service == p.Service {
+			eval.ReportError("token propagation is defined twice")
+			return
+		}
+	}
+	expr.Root.Propagations = append(expr.Root.Propagations, p)
+}
+
+// RequireScopes clears the attribute from the results returned by the
+// generated endpoints unless the principal has all the given scopes. The
+// scopes must be defined by a security scheme. The OpenAPI specification
+// documents the scopes required by the attributes of the response bodies.
+//
+// RequireScopes must appear in an Attribute expression. The attribute must not
+// be required nor have a default value.
+//
+// Example:
+//
+//    var Employee = ResultType("application/vnd.employee", func() {
+//        Attribute("name", String)
+//        Attribute("salary", Int, func() {
+//            security.RequireScopes("hr:read")
+//        })
+//    })
+//
+func RequireScopes(scopes ...string) {
+	att, ok := eval.Current().(*goaexpr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(scopes) == 0 {
+		eval.ReportError("at least one scope is required")
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	att.Meta[expr.ScopesKey] = scopes
+	expr.Root.ScopedFields = append(expr.Root.ScopedFields, &expr.ScopedFieldExpr{Attribute: att, Scopes: scopes})
+}
+
+// Resource defines a type of object of the relationship based authorization
+// model exported as an OpenFGA authorization model, e.g. "document". The DSL
+// function lists the relations users may have with the objects using Relation
+// and Implies. The methods require a relation with RequireRelation.
+//
+// Resource must appear at the top level of the design, typically as the
+// initializer of a package variable.
+//
+// Example:
+//
+//    var _ = security.Resource("document", func() {
+//        security.Relation("owner", "user")
+//        security.Relation("editor", "user", "group#member")
+//        security.Relation("viewer", "user:*")
+//        security.Implies("owner", "editor")
+//        security.Implies("editor", "viewer")
+//    })
+//
+func Resource(name string, fn func()) *expr.ResourceExpr {
+	if _, ok := eval.Current().(eval.TopExpr); !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if expr.Root.Resource(name) != nil {
+		eval.ReportError("resource %q is defined twice", name)
+		return nil
+	}
+	r := &expr.ResourceExpr{Name: name}
+	if !eval.Execute(fn, r) {
+		return nil
+	}
+	expr.Root.Resources = append(expr.Root.Resources, r)
+	return r
+}
+
+// Relation defines a relation users may have with the objects of the
+// resource. The user types list the users that may be granted the relation
+// directly: the objects of a type such as "user", all the objects of a type
+// such as "user:*" or the users having a relation with an object such as
+// "group#member".
+//
+// Relation must appear in a Resource expression.
+func Relation(name string, userTypes ...string) {
+	r, ok := eval.Current().(*expr.ResourceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if r.Relation(name) != nil {
+		eval.ReportError("relation %q is defined twice", name)
+		return
+	}
+	r.Relations = append(r.Relations, &expr.RelationExpr{Name: name, UserTypes: userTypes, Resource: r})
+}
+
+// Implies makes the users having the first relation with an object have the
+// second relation with the same object as well, e.g. the owners of a document
+// are also editors. The second relation must be defined first.
+//
+// Implies must appear in a Resource expression.
+func Implies(relation, implied string) {
+	r, ok := eval.Current().(*expr.ResourceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	rel := r.Relation(implied)
+	if rel == nil {
+		eval.ReportError("relation %q is not defined", implied)
+		return
+	}
+	rel.ImpliedBy = append(rel.ImpliedBy, relation)
+}
+
+// RequireRelation generates a helper in the service package which checks that
+// the user has the given relation with the object of the resource identified
+// by the given payload attribute, e.g. "document:42". The service method calls
+// the helper to complement the scope based checks with fine-grained
+// authorization. The authorization matrix lists the required relation.
+//
+// RequireRelation must appear in a Method expression. The attribute must be a
+// required string or integer payload attribute.
+//
+// Example:
+//
+//    Method("show", func() {
+//        Payload(func() {
+//            Attribute("id", String)
+//            Required("id")
+//        })
+//        security.RequireRelation("document", "viewer", "id")
+//    })
+//
+func RequireRelation(resource, relation, attribute string) {
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, c := range expr.Root.RelationChecks {
+		if c.Method == m {
+			eval.ReportError("relation is required twice")
+			return
+		}
+	}
+	expr.Root.RelationChecks = append(expr.Root.RelationChecks, &expr.RelationCheckExpr{
+		Method:    m,
+		Resource:  resource,
+		Relation:  relation,
+		Attribute: attribute,
+	})
+}
+
+// WebAuthn adds the methods of the WebAuthn registration and authentication
+// ceremonies to the service and generates a WebAuthn variable in the service
+// package which implements them so that the users can sign in with passkeys.
+// rpID is the relying party identifier, the domain of the web application, and
+// origins lists the origins allowed to run the ceremonies. The origins default
+// to the HTTPS origin of rpID.
+//
+// WebAuthn must appear in a Service expression. The registration methods
+// require the security requirements of the service, or of the API, which must
+// be defined before WebAuthn. The authentication methods are public. The
+// methods are documented with the x-webauthn extension in the OpenAPI
+// specification.
+//
+// Example:
+//
+//    var _ = Service("users", func() {
+//        Security(JWT)
+//        security.WebAuthn("example.com", "https://login.example.com")
+//    })
+//
+func WebAuthn(rpID string, origins ...string) {
+	svc, ok := eval.Current().(*goaexpr.ServiceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if expr.Root.WebAuthn(svc.Name) != nil {
+		eval.ReportError("WebAuthn is defined twice")
+		return
+	}
+	if len(origins) == 0 {
+		origins = []string{"https://" + rpID}
+	}
+	expr.Root.WebAuthns = append(expr.Root.WebAuthns, &expr.WebAuthnExpr{RPID: rpID, Origins: origins, Service: svc})
+
+	var reqs []*goaexpr.SecurityExpr
+	if len(svc.Requirements) > 0 {
+		reqs = svc.Requirements
+	} else if goaexpr.Root.API != nil {
+		reqs = goaexpr.Root.API.Requirements
+	}
+	steps := []*webAuthnStep{
+		{
+			Name:        expr.WebAuthnRegistrationOptionsMethod,
+			Description: "Returns the options given to navigator.credentials.create to create a passkey.",
+			Path:        "/webauthn/registration/options",
+			Payload: func() {
+				goadsl.Attribute("user_id", goaexpr.String, "Identifier of the user")
+				goadsl.Attribute("user_name", goaexpr.String, "Name of the user displayed by the authenticator")
+				goadsl.Required("user_id", "user_name")
+			},
+			Requirements: reqs,
+			Result:       true,
+		},
+		{
+			Name:        expr.WebAuthnRegisterMethod,
+			Description: "Registers the passkey returned by navigator.credentials.create.",
+			Path:        "/webauthn/registration",
+			Payload: func() {
+				goadsl.Attribute("user_id", goaexpr.String, "Identifier of the user")
+				goadsl.Attribute("credential", goaexpr.Any, "PublicKeyCredential with base64url encoded buffers")
+				goadsl.Required("user_id", "credential")
+			},
+			Requirements: reqs,
+			Error:        security.InvalidCredentialErrorName,
+			Status:       goadsl.StatusBadRequest,
+		},
+		{
+			Name:        expr.WebAuthnLoginOptionsMethod,
+			Description: "Returns the options given to navigator.credentials.get to sign in with a passkey.",
+			Path:        "/webauthn/login/options",
+			Payload: func() {
+				goadsl.Attribute("user_id", goaexpr.String, "Identifier of the user, omit to use discoverable credentials")
+			},
+			Result: true,
+		},
+		{
+			Name:        expr.WebAuthnLoginMethod,
+			Description: "Verifies the assertion returned by navigator.credentials.get.",
+			Path:        "/webauthn/login",
+			Payload: func() {
+				goadsl.Attribute("credential", goaexpr.Any, "PublicKeyCredential with base64url encoded buffers")
+				goadsl.Required("credential")
+			},
+			Result: true,
+			Error:  security.UnauthorizedErrorName,
+			Status: goadsl.StatusUnauthorized,
+		},
+	}
+	for _, step := range steps {
+		step.define(rpID)
+	}
+}
+
+// webAuthnStep describes the method of a step of a WebAuthn ceremony.
+type webAuthnStep struct {
+	// Name is the method name.
+	Name string
+	// Description is the method description.
+	Description string
+	// Path is the path of the HTTP route.
+	Path string
+	// Payload defines the payload attributes other than the credentials
+	// of the security requirements.
+	Payload func()
+	// Requirements lists the security requirements of the method, the
+	// method is public if there is none.
+	Requirements []*goaexpr.SecurityExpr
+	// Result is true if the method returns a result of type Any.
+	Result bool
+	// Error is the name of the error returned by the method if any.
+	Error string
+	// Status is the HTTP status of the error.
+	Status int
+}
+
+// define defines the method of the step. The payload defines the attributes
+// holding the credentials of the security requirements, the method inherits
+// the requirements of the service or copies the requirements of the API.
+func (step *webAuthnStep) define(rpID string) {
+	ext, _ := json.Marshal(map[string]string{"rpId": rpID, "step": step.Name})
+	goadsl.Method(step.Name, func() {
+		goadsl.Description(step.Description)
+		if len(step.Requirements) == 0 {
+			goadsl.NoSecurity()
+		} else if m, ok := eval.Current().(*goaexpr.MethodExpr); ok && len(m.Service.Requirements) == 0 {
+			// The methods do not inherit the API requirements.
+			for _, req := range step.Requirements {
+				m.Requirements = append(m.Requirements, goaexpr.DupRequirement(req))
+			}
+		}
+		goadsl.Payload(func() {
+			var required []string
+			seen := make(map[string]bool)
+			add := func(name string, fn func()) {
+				if !seen[name] {
+					seen[name] = true
+					fn()
+				}
+			}
+			for _, req := range step.Requirements {
+				for _, s := range req.Schemes {
+					switch s.Kind {
+					case goaexpr.BasicAuthKind:
+						add("username", func() {
+							goadsl.Username("username", goaexpr.String)
+							goadsl.Password("password", goaexpr.String)
+							required = append(required, "username", "password")
+						})
+					case goaexpr.APIKeyKind:
+						name := s.SchemeName
+						add(name, func() {
+							goadsl.APIKey(name, name, goaexpr.String)
+							required = append(required, name)
+						})
+					case goaexpr.JWTKind:
+						add("token", func() {
+							goadsl.Token("token", goaexpr.String)
+							required = append(required, "token")
+						})
+					case goaexpr.OAuth2Kind:
+						add("access_token", func() {
+							goadsl.AccessToken("access_token", goaexpr.String)
+							required = append(required, "access_token")
+						})
+					}
+				}
+			}
+			step.Payload()
+			if len(required) > 0 {
+				goadsl.Required(required...)
+			}
+		})
+		if step.Result {
+			goadsl.Result(goaexpr.Any)
+		}
+		if step.Error != "" {
+			goadsl.Error(step.Error)
+		}
+		goadsl.HTTP(func() {
+			r := goadsl.POST(step.Path)
+			r.Meta = goaexpr.MetaExpr{"swagger:extension:x-webauthn": []string{string(ext)}}
+			if step.Error != "" {
+				goadsl.Response(step.Error, step.Status)
+			}
+		})
+	})
+}
+
+// TOTP is the time-based one-time password second factor defined by RFC 6238
+// and supported by the authenticator apps.
+const TOTP = expr.TOTPFactor
+
+// SecondFactor requires the principal to have verified a second factor of the
+// given kind in addition to satisfying the security requirements. The
+// endpoints fail with the "second_factor_required" error mapped to the 401
+// Unauthorized HTTP status when the second factor is missing so that the
+// clients can distinguish it from invalid credentials. SecondFactor also
+// generates a TOTP variable in the service package which enrolls the users and
+// verifies their codes.
+//
+// SecondFactor must appear in a Service or Method expression. In a Service it
+// applies to the methods with security requirements, a method without
+// security requirements cannot require a second factor. The second factor of
+// a method overrides the second factor of its service.
+//
+// Example:
+//
+//    var _ = Service("billing", func() {
+//        Security(JWT)
+//        Method("refund", func() {
+//            security.SecondFactor(security.TOTP)
+//        })
+//    })
+//
+func SecondFactor(kind string) {
+	s := &expr.SecondFactorExpr{Kind: kind}
+	var defined bool
+	switch actual := eval.Current().(type) {
+	case *goaexpr.ServiceExpr:
+		s.Service = actual
+		defined = actual.Error(expr.SecondFactorRequiredErrorName) != nil
+	case *goaexpr.MethodExpr:
+		s.Service, s.Method = actual.Service, actual
+		defined = actual.Error(expr.SecondFactorRequiredErrorName) != nil
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	for _, e := range expr.Root.SecondFactors {
+		if e.Service == s.Service && e.Method == s.Method {
+			eval.ReportError("second factor is defined twice")
+			return
+		}
+	}
+	if !defined {
+		goadsl.Error(expr.SecondFactorRequiredErrorName)
+	}
+	expr.Root.SecondFactors = append(expr.Root.SecondFactors, s)
+}
+
+// AllowCIDR restricts the clients allowed to call the methods to the networks
+// with the given CIDRs. The endpoints check the client IP before
+// authenticating the request and fail with the "network_denied" error mapped
+// to the 403 Forbidden HTTP status if it does not belong to one of the
+// networks. AllowCIDR generates a NetworkGuard variable in the service
+// packages.
+//
+// AllowCIDR must appear in an API, Service or Method expression. The allowed
+// networks of a method override the ones of its service which override the
+// ones of the API.
+//
+// Example:
+//
+//    var _ = Service("admin", func() {
+//        security.AllowCIDR("10.0.0.0/8", "192.168.0.0/16")
+//    })
+//
+func AllowCIDR(cidrs ...string) {
+	if len(cidrs) == 0 {
+		eval.ReportError("missing CIDR")
+		return
+	}
+	if n := network(); n != nil {
+		n.Allow = append(n.Allow, cidrs...)
+	}
+}
+
+// DenyCIDR rejects the clients calling the methods from the networks with the
+// given CIDRs. The endpoints check the client IP before authenticating the
+// request and fail with the "network_denied" error mapped to the 403 Forbidden
+// HTTP status if it belongs to one of the networks. DenyCIDR generates a
+// NetworkGuard variable in the service packages.
+//
+// DenyCIDR must appear in an API, Service or Method expression. The denied
+// networks of the API, service and method add up and take precedence over the
+// allowed networks.
+//
+// Example:
+//
+//    var _ = API("calc", func() {
+//        security.DenyCIDR("203.0.113.0/24")
+//    })
+//
+func DenyCIDR(cidrs ...string) {
+	if len(cidrs) == 0 {
+		eval.ReportError("missing CIDR")
+		return
+	}
+	if n := network(); n != nil {
+		n.Deny = append(n.Deny, cidrs...)
+	}
+}
+
+// TrustedProxy lists the CIDRs of the proxies forwarding the requests to the
+// services. The client IP checked by the network rules defined with AllowCIDR
+// and DenyCIDR is read from the X-Forwarded-For header when the request comes
+// from a trusted proxy: it is the last address of the header that is not a
+// trusted proxy.
+//
+// TrustedProxy must appear in an API expression.
+//
+// Example:
+//
+//    var _ = API("calc", func() {
+//        security.TrustedProxy("10.0.0.0/24")
+//    })
+//
+func TrustedProxy(cidrs ...string) {
+	if len(cidrs) == 0 {
+		eval.ReportError("missing CIDR")
+		return
+	}
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if n := network(); n != nil {
+		n.TrustedProxies = append(n.TrustedProxies, cidrs...)
+	}
+}
+
+// network returns the network rules of the current API, service or method
+// expression, creating them if needed. It returns nil and reports an error if
+// the current expression is none of these.
+func network() *expr.NetworkExpr {
+	n := &expr.NetworkExpr{}
+	switch actual := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+	case *goaexpr.ServiceExpr:
+		n.Service = actual
+	case *goaexpr.MethodExpr:
+		n.Service, n.Method = actual.Service, actual
+	default:
+		eval.IncompatibleDSL()
+		return nil
+	}
+	for _, e := range expr.Root.Networks {
+		if e.Service == n.Service && e.Method == n.Method {
+			return e
+		}
+	}
+	expr.Root.Networks = append(expr.Root.Networks, n)
+	return n
+}
+
+// scheme returns the security scheme with the given name, nil if there is
+// none.
+func scheme(name string) *goaexpr.SchemeExpr {
+	for _, s := range goaexpr.Root.Schemes {
+		if s.SchemeName == name {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// BasicAuthExpr describes the htpasswd file holding the bcrypt password
+	// hashes used to verify the basic auth credentials of the requests made
+	// to the endpoints of a service or of all the services.
+	BasicAuthExpr struct {
+		// Path is the path to the htpasswd file.
+		Path string
+		// Service is the service the file applies to, nil if the file
+		// applies to all the services.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (b *BasicAuthExpr) EvalName() string {
+	if b.Service != nil {
+		return fmt.Sprintf("htpasswd file of %s", b.Service.EvalName())
+	}
+	return "htpasswd file of API"
+}
+
+// Validate makes sure the path is not empty.
+func (b *BasicAuthExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if b.Path == "" {
+		verr.Add(b, "htpasswd file path must not be empty")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// BruteForceExpr describes the protection of the endpoints of a service
+	// or of all the services requiring basic auth against brute-force
+	// attacks. The failed attempts are tracked per client IP and username.
+	BruteForceExpr struct {
+		// MaxAttempts is the number of consecutive failed attempts after
+		// which the client IP or username is locked out.
+		MaxAttempts int
+		// Backoff is the delay imposed after the first failed attempt,
+		// it doubles with each subsequent failed attempt.
+		Backoff time.Duration
+		// Lockout is the duration of the lockout. The failed attempts
+		// are forgotten once it elapses after the last one.
+		Lockout time.Duration
+		// Service is the service the protection applies to, nil if the
+		// protection applies to all the services.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (b *BruteForceExpr) EvalName() string {
+	if b.Service != nil {
+		return fmt.Sprintf("brute-force protection of %s", b.Service.EvalName())
+	}
+	return "brute-force protection of API"
+}
+
+// Validate makes sure the number of attempts and the lockout duration are
+// positive and that the backoff delay is not negative.
+func (b *BruteForceExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if b.MaxAttempts <= 0 {
+		verr.Add(b, "maximum number of attempts must be positive")
+	}
+	if b.Backoff < 0 {
+		verr.Add(b, "backoff delay must not be negative")
+	}
+	if b.Lockout <= 0 {
+		verr.Add(b, "lockout duration must be positive")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
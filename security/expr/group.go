@@ -0,0 +1,72 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// SecurityGroupExpr describes a named security requirement shared by
+	// the API, services and methods that reference it.
+	SecurityGroupExpr struct {
+		// Name is the name of the group.
+		Name string
+		// Requirement is the security requirement: the schemes and the
+		// scopes required by the group.
+		Requirement *expr.SecurityExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (g *SecurityGroupExpr) EvalName() string {
+	return fmt.Sprintf("security group %q", g.Name)
+}
+
+// Validate makes sure the group defines at least one scheme and that the
+// scopes are defined by a scheme of the group.
+func (g *SecurityGroupExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if len(g.Requirement.Schemes) == 0 {
+		verr.Add(g, "security group must use at least one security scheme")
+	}
+	for _, s := range g.Requirement.Scopes {
+		if !g.hasScope(s) {
+			verr.Add(g, "scope %q is not defined by the security schemes of the group", s)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Copy returns a copy of the security requirement of the group that can
+// be added to the requirements of an API, service or method.
+func (g *SecurityGroupExpr) Copy() *expr.SecurityExpr {
+	req := &expr.SecurityExpr{
+		Schemes: make([]*expr.SchemeExpr, len(g.Requirement.Schemes)),
+		Scopes:  append([]string(nil), g.Requirement.Scopes...),
+	}
+	for i, s := range g.Requirement.Schemes {
+		req.Schemes[i] = expr.DupScheme(s)
+	}
+	return req
+}
+
+// hasScope returns true if one of the schemes of the group which support
+// scopes defines the given scope.
+func (g *SecurityGroupExpr) hasScope(scope string) bool {
+	for _, s := range g.Requirement.Schemes {
+		if s.Kind != expr.OAuth2Kind && s.Kind != expr.JWTKind {
+			continue
+		}
+		for _, sc := range s.Scopes {
+			if sc.Name == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
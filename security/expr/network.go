@@ -0,0 +1,119 @@
+package expr
+
+import (
+	"fmt"
+	"net"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// NetworkDeniedErrorName is the name of the error returned by the endpoints
+// when the client IP is not allowed to call the method.
+const NetworkDeniedErrorName = "network_denied"
+
+type (
+	// NetworkExpr describes the networks allowed or denied to call the
+	// methods of the API, of a service or a method.
+	NetworkExpr struct {
+		// Allow lists the CIDRs of the networks allowed to call the
+		// methods. All the networks are allowed if empty.
+		Allow []string
+		// Deny lists the CIDRs of the networks denied to call the
+		// methods.
+		Deny []string
+		// TrustedProxies lists the CIDRs of the proxies whose
+		// X-Forwarded-For header is trusted, only set on the API.
+		TrustedProxies []string
+		// Service is the service whose methods the rules apply to, nil
+		// if the rules apply to all the services of the API.
+		Service *expr.ServiceExpr
+		// Method is the method the rules apply to, nil if the rules
+		// apply to all the methods of the service.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (n *NetworkExpr) EvalName() string {
+	switch {
+	case n.Method != nil:
+		return fmt.Sprintf("network rules of %s", n.Method.EvalName())
+	case n.Service != nil:
+		return fmt.Sprintf("network rules of %s", n.Service.EvalName())
+	}
+	return "API network rules"
+}
+
+// Prepare defines the network_denied error in the services of the methods the
+// rules apply to and maps it to the 403 Forbidden HTTP status unless the
+// design already does. It runs once the HTTP endpoints have inherited the
+// errors of their service.
+func (n *NetworkExpr) Prepare() {
+	if len(n.Allow) == 0 && len(n.Deny) == 0 {
+		return
+	}
+	for _, m := range n.methods() {
+		if m.Error(NetworkDeniedErrorName) == nil {
+			m.Service.Errors = append(m.Service.Errors, &expr.ErrorExpr{
+				AttributeExpr: &expr.AttributeExpr{Type: expr.ErrorResult},
+				Name:          NetworkDeniedErrorName,
+			})
+		}
+		if expr.Root.API == nil || expr.Root.API.HTTP == nil {
+			continue
+		}
+		svc := expr.Root.API.HTTP.Service(m.Service.Name)
+		if svc == nil {
+			continue
+		}
+		e := svc.Endpoint(m.Name)
+		if e == nil {
+			continue
+		}
+		mapped := false
+		for _, herr := range e.HTTPErrors {
+			if herr.Name == NetworkDeniedErrorName {
+				mapped = true
+				break
+			}
+		}
+		if mapped {
+			continue
+		}
+		resp := &expr.HTTPResponseExpr{StatusCode: expr.StatusForbidden, Parent: e}
+		resp.Prepare()
+		e.HTTPErrors = append(e.HTTPErrors, &expr.HTTPErrorExpr{Name: NetworkDeniedErrorName, Response: resp})
+	}
+}
+
+// Validate makes sure the CIDRs are valid.
+func (n *NetworkExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	for _, cidrs := range [][]string{n.Allow, n.Deny, n.TrustedProxies} {
+		for _, c := range cidrs {
+			if _, _, err := net.ParseCIDR(c); err != nil {
+				verr.Add(n, "invalid CIDR %q", c)
+			}
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// methods returns the methods the rules apply to.
+func (n *NetworkExpr) methods() []*expr.MethodExpr {
+	switch {
+	case n.Method != nil:
+		return []*expr.MethodExpr{n.Method}
+	case n.Service != nil:
+		return n.Service.Methods
+	}
+	var ms []*expr.MethodExpr
+	for _, svc := range expr.Root.Services {
+		ms = append(ms, svc.Methods...)
+	}
+	return ms
+}
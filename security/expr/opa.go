@@ -0,0 +1,51 @@
+package expr
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// OPAExpr describes the Open Policy Agent policy queried to authorize
+	// the requests made to the endpoints of a service or of all the
+	// services once authenticated.
+	OPAExpr struct {
+		// URL is the URL of the OPA data API document holding the
+		// decision, e.g. http://localhost:8181/v1/data/goa/authz/allow.
+		URL string
+		// CacheTTL is the duration the decisions are cached for, 0
+		// disables caching.
+		CacheTTL time.Duration
+		// Service is the service the policy applies to, nil if the policy
+		// applies to all the services.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (o *OPAExpr) EvalName() string {
+	if o.Service != nil {
+		return fmt.Sprintf("OPA policy of %s", o.Service.EvalName())
+	}
+	return "OPA policy of API"
+}
+
+// Validate makes sure the URL is an absolute HTTP URL and that the cache
+// duration is not negative.
+func (o *OPAExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if u, err := url.Parse(o.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		verr.Add(o, "invalid URL %q, must be an absolute HTTP URL", o.URL)
+	}
+	if o.CacheTTL < 0 {
+		verr.Add(o, "decision cache duration must not be negative")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,48 @@
+package expr
+
+import (
+	"fmt"
+	"net/url"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// PropagationExpr describes how the generated clients of a service or
+	// of all the services propagate the token of the incoming request to
+	// the endpoints secured with JWT or OAuth2.
+	PropagationExpr struct {
+		// ExchangeURL is the URL of the RFC 8693 token exchange endpoint,
+		// empty if the incoming token is forwarded as is.
+		ExchangeURL string
+		// Audience is the audience of the exchanged tokens if any.
+		Audience string
+		// Service is the service whose clients propagate the tokens, nil
+		// if the propagation applies to all the services.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PropagationExpr) EvalName() string {
+	if p.Service != nil {
+		return fmt.Sprintf("token propagation of %s", p.Service.EvalName())
+	}
+	return "token propagation of API"
+}
+
+// Validate makes sure the token exchange URL is an absolute HTTP URL if set.
+func (p *PropagationExpr) Validate() error {
+	if p.ExchangeURL == "" {
+		return nil
+	}
+	verr := new(eval.ValidationErrors)
+	if u, err := url.Parse(p.ExchangeURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		verr.Add(p, "invalid token exchange URL %q, must be an absolute HTTP URL", p.ExchangeURL)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
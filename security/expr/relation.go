@@ -0,0 +1,155 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// ResourceExpr describes a type of object of the relationship based
+	// authorization model, e.g. "document".
+	ResourceExpr struct {
+		// Name is the name of the object type.
+		Name string
+		// Relations lists the relations users may have with the objects
+		// in the order they are defined.
+		Relations []*RelationExpr
+	}
+
+	// RelationExpr describes a relation users may have with the objects of
+	// a resource, e.g. "owner" or "viewer".
+	RelationExpr struct {
+		// Name is the relation name.
+		Name string
+		// UserTypes lists the types of the users that may be directly
+		// related to the objects: an object type such as "user", all
+		// the objects of a type such as "user:*" or the users having a
+		// relation with an object such as "group#member".
+		UserTypes []string
+		// ImpliedBy lists the relations of the same object which imply
+		// the relation, e.g. the owners of a document are also editors.
+		ImpliedBy []string
+		// Resource is the resource defining the relation.
+		Resource *ResourceExpr
+	}
+
+	// RelationCheckExpr describes the relation the principal must have with
+	// the object identified by a payload attribute to call a method.
+	RelationCheckExpr struct {
+		// Method is the method.
+		Method *expr.MethodExpr
+		// Resource is the name of the object type.
+		Resource string
+		// Relation is the name of the required relation.
+		Relation string
+		// Attribute is the name of the payload attribute holding the
+		// object identifier.
+		Attribute string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *ResourceExpr) EvalName() string {
+	return fmt.Sprintf("resource %q", r.Name)
+}
+
+// Validate makes sure the resource defines relations, that the relations may
+// be granted and that the implying relations and the relations of the user
+// types are defined.
+func (r *ResourceExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if len(r.Relations) == 0 {
+		verr.Add(r, "resource must define at least one relation")
+	}
+	for _, rel := range r.Relations {
+		if len(rel.UserTypes) == 0 && len(rel.ImpliedBy) == 0 {
+			verr.Add(r, "relation %q has no user type and is not implied by another relation", rel.Name)
+		}
+		for _, ut := range rel.UserTypes {
+			if !validUserType(ut) {
+				verr.Add(r, "invalid user type %q of relation %q, must be \"type\", \"type:*\" or \"type#relation\"", ut, rel.Name)
+				continue
+			}
+			if i := strings.Index(ut, "#"); i > 0 {
+				if res := Root.Resource(ut[:i]); res == nil || res.Relation(ut[i+1:]) == nil {
+					verr.Add(r, "user type %q of relation %q references a relation which is not defined", ut, rel.Name)
+				}
+			}
+		}
+		for _, i := range rel.ImpliedBy {
+			if r.Relation(i) == nil {
+				verr.Add(r, "relation %q implying %q is not defined", i, rel.Name)
+			}
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Relation returns the relation with the given name, nil if there is none.
+func (r *ResourceExpr) Relation(name string) *RelationExpr {
+	for _, rel := range r.Relations {
+		if rel.Name == name {
+			return rel
+		}
+	}
+	return nil
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (c *RelationCheckExpr) EvalName() string {
+	return fmt.Sprintf("relation check of %s", c.Method.EvalName())
+}
+
+// Validate makes sure the relation is defined by the resource and that the
+// payload attribute holding the object identifier is a required string or
+// integer.
+func (c *RelationCheckExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if r := Root.Resource(c.Resource); r == nil {
+		verr.Add(c, "resource %q is not defined", c.Resource)
+	} else if r.Relation(c.Relation) == nil {
+		verr.Add(c, "relation %q is not defined by resource %q", c.Relation, c.Resource)
+	}
+	var att *expr.AttributeExpr
+	if c.Method.Payload != nil {
+		if o := expr.AsObject(c.Method.Payload.Type); o != nil {
+			att = o.Attribute(c.Attribute)
+		}
+	}
+	if att == nil {
+		verr.Add(c, "payload attribute %q is not defined", c.Attribute)
+	} else {
+		switch att.Type.Kind() {
+		case expr.StringKind, expr.IntKind, expr.Int32Kind, expr.Int64Kind,
+			expr.UIntKind, expr.UInt32Kind, expr.UInt64Kind:
+		default:
+			verr.Add(c, "payload attribute %q must be a string or an integer", c.Attribute)
+		}
+		if !c.Method.Payload.IsRequired(c.Attribute) {
+			verr.Add(c, "payload attribute %q must be required", c.Attribute)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// validUserType returns true if the given user type is an object type, all the
+// objects of a type or the users having a relation with an object.
+func validUserType(ut string) bool {
+	name, rest := ut, ""
+	if i := strings.IndexAny(ut, ":#"); i >= 0 {
+		name, rest = ut[:i], ut[i:]
+	}
+	if name == "" {
+		return false
+	}
+	return rest == "" || rest == ":*" || (rest[0] == '#' && len(rest) > 1 && !strings.ContainsAny(rest[1:], ":#"))
+}
@@ -0,0 +1,298 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the security groups, OPA policies, htpasswd
+	// files, brute-force protections, token propagations, scoped result
+	// attributes, resources, relation checks, WebAuthn relying parties,
+	// second factors and network rules defined in the design.
+	RootExpr struct {
+		// Groups lists the security groups in the order they are
+		// defined.
+		Groups []*SecurityGroupExpr
+		// OPAs lists the API and service OPA policies.
+		OPAs []*OPAExpr
+		// BasicAuths lists the API and service htpasswd files.
+		BasicAuths []*BasicAuthExpr
+		// BruteForces lists the API and service brute-force
+		// protections.
+		BruteForces []*BruteForceExpr
+		// Propagations lists the API and service token propagations.
+		Propagations []*PropagationExpr
+		// ScopedFields lists the result attributes that require scopes.
+		ScopedFields []*ScopedFieldExpr
+		// Resources lists the resources of the relationship based
+		// authorization model in the order they are defined.
+		Resources []*ResourceExpr
+		// RelationChecks lists the relations required by the methods.
+		RelationChecks []*RelationCheckExpr
+		// WebAuthns lists the WebAuthn relying parties of the services.
+		WebAuthns []*WebAuthnExpr
+		// SecondFactors lists the second factors required by the
+		// services and methods.
+		SecondFactors []*SecondFactorExpr
+		// Networks lists the network rules of the API, services and
+		// methods.
+		Networks []*NetworkExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "security plugin"
+}
+
+// WalkSets iterates over the security groups, the OPA policies, the htpasswd
+// files, the brute-force protections, the token propagations, the scoped
+// attributes, the resources, the relation checks, the WebAuthn relying
+// parties, the second factors and the network rules.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	gexps := make(eval.ExpressionSet, len(r.Groups))
+	for i, g := range r.Groups {
+		gexps[i] = g
+	}
+	walk(gexps)
+	oexps := make(eval.ExpressionSet, len(r.OPAs))
+	for i, o := range r.OPAs {
+		oexps[i] = o
+	}
+	walk(oexps)
+	bexps := make(eval.ExpressionSet, len(r.BasicAuths))
+	for i, b := range r.BasicAuths {
+		bexps[i] = b
+	}
+	walk(bexps)
+	bfexps := make(eval.ExpressionSet, len(r.BruteForces))
+	for i, b := range r.BruteForces {
+		bfexps[i] = b
+	}
+	walk(bfexps)
+	pexps := make(eval.ExpressionSet, len(r.Propagations))
+	for i, p := range r.Propagations {
+		pexps[i] = p
+	}
+	walk(pexps)
+	fexps := make(eval.ExpressionSet, len(r.ScopedFields))
+	for i, f := range r.ScopedFields {
+		fexps[i] = f
+	}
+	walk(fexps)
+	rexps := make(eval.ExpressionSet, len(r.Resources))
+	for i, res := range r.Resources {
+		rexps[i] = res
+	}
+	walk(rexps)
+	cexps := make(eval.ExpressionSet, len(r.RelationChecks))
+	for i, c := range r.RelationChecks {
+		cexps[i] = c
+	}
+	walk(cexps)
+	wexps := make(eval.ExpressionSet, len(r.WebAuthns))
+	for i, w := range r.WebAuthns {
+		wexps[i] = w
+	}
+	walk(wexps)
+	sexps := make(eval.ExpressionSet, len(r.SecondFactors))
+	for i, s := range r.SecondFactors {
+		sexps[i] = s
+	}
+	walk(sexps)
+	nexps := make(eval.ExpressionSet, len(r.Networks))
+	for i, n := range r.Networks {
+		nexps[i] = n
+	}
+	walk(nexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/security/dsl"}
+}
+
+// Group returns the security group with the given name, nil if there is none.
+func (r *RootExpr) Group(name string) *SecurityGroupExpr {
+	for _, g := range r.Groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// OPA returns the OPA policy of the service with the given name: the service
+// policy if defined, the API policy otherwise. It returns nil if neither is
+// defined.
+func (r *RootExpr) OPA(svc string) *OPAExpr {
+	var api *OPAExpr
+	for _, o := range r.OPAs {
+		switch {
+		case o.Service == nil:
+			api = o
+		case o.Service.Name == svc:
+			return o
+		}
+	}
+	return api
+}
+
+// BasicAuth returns the htpasswd file of the service with the given name: the
+// service file if defined, the API file otherwise. It returns nil if neither
+// is defined.
+func (r *RootExpr) BasicAuth(svc string) *BasicAuthExpr {
+	var api *BasicAuthExpr
+	for _, b := range r.BasicAuths {
+		switch {
+		case b.Service == nil:
+			api = b
+		case b.Service.Name == svc:
+			return b
+		}
+	}
+	return api
+}
+
+// BruteForce returns the brute-force protection of the service with the given
+// name: the service protection if defined, the API protection otherwise. It
+// returns nil if neither is defined.
+func (r *RootExpr) BruteForce(svc string) *BruteForceExpr {
+	var api *BruteForceExpr
+	for _, b := range r.BruteForces {
+		switch {
+		case b.Service == nil:
+			api = b
+		case b.Service.Name == svc:
+			return b
+		}
+	}
+	return api
+}
+
+// Propagation returns the token propagation of the clients of the service with
+// the given name: the service propagation if defined, the API propagation
+// otherwise. It returns nil if neither is defined.
+func (r *RootExpr) Propagation(svc string) *PropagationExpr {
+	var api *PropagationExpr
+	for _, p := range r.Propagations {
+		switch {
+		case p.Service == nil:
+			api = p
+		case p.Service.Name == svc:
+			return p
+		}
+	}
+	return api
+}
+
+// Resource returns the resource with the given name, nil if there is none.
+func (r *RootExpr) Resource(name string) *ResourceExpr {
+	for _, res := range r.Resources {
+		if res.Name == name {
+			return res
+		}
+	}
+	return nil
+}
+
+// RelationCheck returns the relation required by the method of the given
+// service, nil if there is none.
+func (r *RootExpr) RelationCheck(svc, method string) *RelationCheckExpr {
+	for _, c := range r.RelationChecks {
+		if c.Method.Service.Name == svc && c.Method.Name == method {
+			return c
+		}
+	}
+	return nil
+}
+
+// WebAuthn returns the WebAuthn relying party of the service with the given
+// name, nil if there is none.
+func (r *RootExpr) WebAuthn(svc string) *WebAuthnExpr {
+	for _, w := range r.WebAuthns {
+		if w.Service.Name == svc {
+			return w
+		}
+	}
+	return nil
+}
+
+// SecondFactor returns the second factor required by the method with the given
+// name of the service with the given name: the method second factor if
+// defined, the service second factor otherwise. It returns nil if neither is
+// defined.
+func (r *RootExpr) SecondFactor(svc, method string) *SecondFactorExpr {
+	var service *SecondFactorExpr
+	for _, s := range r.SecondFactors {
+		if s.Service.Name != svc {
+			continue
+		}
+		if s.Method == nil {
+			service = s
+		} else if s.Method.Name == method {
+			return s
+		}
+	}
+	return service
+}
+
+// Network returns the CIDRs of the networks allowed and denied to call the
+// method with the given name of the service with the given name. The denied
+// networks of the API, service and method add up while the allowed networks of
+// the method override the ones of the service which override the ones of the
+// API. allow is empty if all the networks not denied are allowed.
+func (r *RootExpr) Network(svc, method string) (allow, deny []string) {
+	var api, service, meth []string
+	for _, n := range r.Networks {
+		switch {
+		case n.Service == nil:
+			api = n.Allow
+		case n.Service.Name != svc:
+			continue
+		case n.Method == nil:
+			service = n.Allow
+		case n.Method.Name == method:
+			meth = n.Allow
+		default:
+			continue
+		}
+		deny = append(deny, n.Deny...)
+	}
+	switch {
+	case len(meth) > 0:
+		allow = meth
+	case len(service) > 0:
+		allow = service
+	default:
+		allow = api
+	}
+	return
+}
+
+// TrustedProxies returns the CIDRs of the proxies whose X-Forwarded-For header
+// is trusted.
+func (r *RootExpr) TrustedProxies() []string {
+	for _, n := range r.Networks {
+		if n.Service == nil {
+			return n.TrustedProxies
+		}
+	}
+	return nil
+}
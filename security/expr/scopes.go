@@ -0,0 +1,97 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// ScopesKey is the meta key recording the scopes required to read a result
+// attribute.
+const ScopesKey = "security:scopes"
+
+type (
+	// ScopedFieldExpr describes a result attribute which is cleared from
+	// the responses unless the principal has the required scopes.
+	ScopedFieldExpr struct {
+		// Attribute is the attribute.
+		Attribute *expr.AttributeExpr
+		// Scopes lists the required scopes.
+		Scopes []string
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (f *ScopedFieldExpr) EvalName() string {
+	return "scoped attribute"
+}
+
+// Validate makes sure the scopes are defined by a security scheme and that the
+// attribute may be cleared: it must not be required nor have a default value.
+func (f *ScopedFieldExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	for _, sc := range f.Scopes {
+		if !definedScope(sc) {
+			verr.Add(f, "scope %q is not defined by any security scheme", sc)
+		}
+	}
+	if f.Attribute.DefaultValue != nil {
+		verr.Add(f, "attribute with required scopes cannot have a default value")
+	}
+	if name, ok := requiredIn(f.Attribute); ok {
+		verr.Add(f, "attribute %q with required scopes cannot be required", name)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Scopes returns the scopes required to read the given attribute, nil if
+// there are none.
+func Scopes(att *expr.AttributeExpr) []string {
+	return att.Meta[ScopesKey]
+}
+
+// definedScope returns true if a security scheme of the design defines the
+// scope with the given name.
+func definedScope(name string) bool {
+	for _, s := range expr.Root.Schemes {
+		for _, sc := range s.Scopes {
+			if sc.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requiredIn returns the name of the given attribute and true if it is a
+// required attribute of an object of the design.
+func requiredIn(att *expr.AttributeExpr) (string, bool) {
+	var parents []*expr.AttributeExpr
+	for _, t := range expr.Root.Types {
+		parents = append(parents, t.Attribute())
+	}
+	for _, t := range expr.Root.ResultTypes {
+		parents = append(parents, t.Attribute())
+	}
+	for _, svc := range expr.Root.Services {
+		for _, m := range svc.Methods {
+			if m.Result != nil {
+				parents = append(parents, m.Result)
+			}
+		}
+	}
+	for _, p := range parents {
+		obj, ok := p.Type.(*expr.Object)
+		if !ok {
+			continue
+		}
+		for _, nat := range *obj {
+			if nat.Attribute == att && p.IsRequired(nat.Name) {
+				return nat.Name, true
+			}
+		}
+	}
+	return "", false
+}
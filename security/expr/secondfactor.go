@@ -0,0 +1,122 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// TOTPFactor is the kind of the time-based one-time password second
+	// factor defined by RFC 6238.
+	TOTPFactor = "totp"
+
+	// SecondFactorRequiredErrorName is the name of the error returned by
+	// the endpoints when the principal has not verified the second
+	// factor.
+	SecondFactorRequiredErrorName = "second_factor_required"
+)
+
+type (
+	// SecondFactorExpr describes the second factor required by the secured
+	// methods of a service or by a method in addition to their security
+	// requirements.
+	SecondFactorExpr struct {
+		// Kind is the kind of second factor, TOTPFactor.
+		Kind string
+		// Service is the service whose secured methods require the
+		// second factor.
+		Service *expr.ServiceExpr
+		// Method is the method requiring the second factor, nil if the
+		// second factor applies to the service.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (s *SecondFactorExpr) EvalName() string {
+	if s.Method != nil {
+		return fmt.Sprintf("second factor of %s", s.Method.EvalName())
+	}
+	return fmt.Sprintf("second factor of %s", s.Service.EvalName())
+}
+
+// Prepare maps the second_factor_required error of the methods requiring the
+// second factor to the 401 Unauthorized HTTP status unless the design already
+// maps it. It runs once the HTTP endpoints have inherited the errors of their
+// service.
+func (s *SecondFactorExpr) Prepare() {
+	if expr.Root.API == nil || expr.Root.API.HTTP == nil {
+		return
+	}
+	svc := expr.Root.API.HTTP.Service(s.Service.Name)
+	if svc == nil {
+		return
+	}
+	for _, m := range s.methods() {
+		e := svc.Endpoint(m.Name)
+		if e == nil {
+			continue
+		}
+		mapped := false
+		for _, herr := range e.HTTPErrors {
+			if herr.Name == SecondFactorRequiredErrorName {
+				mapped = true
+				break
+			}
+		}
+		if mapped {
+			continue
+		}
+		resp := &expr.HTTPResponseExpr{StatusCode: expr.StatusUnauthorized, Parent: e}
+		resp.Prepare()
+		e.HTTPErrors = append(e.HTTPErrors, &expr.HTTPErrorExpr{Name: SecondFactorRequiredErrorName, Response: resp})
+	}
+}
+
+// Validate makes sure the kind is supported and that the method requiring the
+// second factor has security requirements.
+func (s *SecondFactorExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if s.Kind != TOTPFactor {
+		verr.Add(s, "unsupported second factor %q, must be %q", s.Kind, TOTPFactor)
+	}
+	if s.Method != nil && len(requirements(s.Method)) == 0 {
+		verr.Add(s, "method has no security requirement")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// methods returns the methods requiring the second factor.
+func (s *SecondFactorExpr) methods() []*expr.MethodExpr {
+	if s.Method != nil {
+		return []*expr.MethodExpr{s.Method}
+	}
+	var ms []*expr.MethodExpr
+	for _, m := range s.Service.Methods {
+		if len(requirements(m)) > 0 && Root.SecondFactor(s.Service.Name, m.Name) == s {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// requirements returns the security requirements of the given method before
+// it inherits the requirements of its service.
+func requirements(m *expr.MethodExpr) []*expr.SecurityExpr {
+	for _, r := range m.Requirements {
+		for _, sch := range r.Schemes {
+			if sch.Kind == expr.NoKind {
+				return nil
+			}
+		}
+	}
+	if len(m.Requirements) > 0 {
+		return m.Requirements
+	}
+	return m.Service.Requirements
+}
@@ -0,0 +1,73 @@
+package expr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// WebAuthnRegistrationOptionsMethod is the name of the method
+	// returning the options of the passkey registration ceremony.
+	WebAuthnRegistrationOptionsMethod = "webauthn_registration_options"
+	// WebAuthnRegisterMethod is the name of the method registering the
+	// passkey created by the authenticator.
+	WebAuthnRegisterMethod = "webauthn_register"
+	// WebAuthnLoginOptionsMethod is the name of the method returning the
+	// options of the authentication ceremony.
+	WebAuthnLoginOptionsMethod = "webauthn_login_options"
+	// WebAuthnLoginMethod is the name of the method verifying the
+	// assertion made by the authenticator.
+	WebAuthnLoginMethod = "webauthn_login"
+)
+
+type (
+	// WebAuthnExpr describes the WebAuthn relying party of a service which
+	// registers passkeys and authenticates the users with them.
+	WebAuthnExpr struct {
+		// RPID is the relying party identifier, the domain of the
+		// origins, e.g. "example.com".
+		RPID string
+		// Origins lists the origins allowed to run the ceremonies, e.g.
+		// "https://login.example.com".
+		Origins []string
+		// Service is the service defining the ceremony methods.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (w *WebAuthnExpr) EvalName() string {
+	return fmt.Sprintf("WebAuthn relying party of %s", w.Service.EvalName())
+}
+
+// Validate makes sure the relying party identifier is a domain and that the
+// origins are HTTPS origins of the domain or of one of its subdomains. HTTP is
+// accepted for localhost.
+func (w *WebAuthnExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if w.RPID == "" || strings.ContainsAny(w.RPID, ":/") {
+		verr.Add(w, "invalid relying party ID %q, must be a domain such as \"example.com\"", w.RPID)
+	}
+	for _, o := range w.Origins {
+		u, err := url.Parse(o)
+		if err != nil || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			verr.Add(w, "invalid origin %q, must be an absolute URL without path", o)
+			continue
+		}
+		host := u.Hostname()
+		if u.Scheme != "https" && !(u.Scheme == "http" && host == "localhost") {
+			verr.Add(w, "invalid origin %q, must use HTTPS", o)
+		}
+		if w.RPID != "" && host != w.RPID && !strings.HasSuffix(host, "."+w.RPID) {
+			verr.Add(w, "origin %q is not in the domain of relying party ID %q", o, w.RPID)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,101 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+type (
+	// RelationChecker checks the relations users have with objects, e.g.
+	// whether "user:alice" is a "viewer" of "document:42".
+	RelationChecker interface {
+		// Check returns true if the user has the relation with the
+		// object.
+		Check(ctx context.Context, user, relation, object string) (bool, error)
+	}
+
+	// OpenFGAChecker is a RelationChecker querying an OpenFGA store through
+	// the OpenFGA HTTP API.
+	OpenFGAChecker struct {
+		// URL is the URL of the OpenFGA API, e.g.
+		// http://localhost:8080.
+		URL string
+		// StoreID is the identifier of the store holding the
+		// relationship tuples.
+		StoreID string
+		// ModelID is the identifier of the authorization model used to
+		// evaluate the checks, the latest model of the store if empty.
+		ModelID string
+		// Client is the HTTP client used to query the store.
+		Client *http.Client
+	}
+)
+
+// NewOpenFGAChecker returns a relation checker querying the store with the
+// given identifier of the OpenFGA API at the given URL.
+func NewOpenFGAChecker(url, storeID string) *OpenFGAChecker {
+	return &OpenFGAChecker{URL: url, StoreID: storeID, Client: http.DefaultClient}
+}
+
+// Check queries the check endpoint of the store.
+func (c *OpenFGAChecker) Check(ctx context.Context, user, relation, object string) (bool, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"tuple_key": map[string]string{
+			"user":     user,
+			"relation": relation,
+			"object":   object,
+		},
+		"authorization_model_id": c.ModelID,
+	})
+	if err != nil {
+		return false, err
+	}
+	url := strings.TrimSuffix(c.URL, "/") + "/stores/" + c.StoreID + "/check"
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	cl := c.Client
+	if cl == nil {
+		cl = http.DefaultClient
+	}
+	resp, err := cl.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var res struct {
+		Allowed bool `json:"allowed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return false, err
+	}
+	return res.Allowed, nil
+}
+
+// CheckRelation returns a "forbidden" error if the user does not have the
+// relation with the object and an "authorization_unavailable" error if the
+// checker fails.
+func CheckRelation(ctx context.Context, c RelationChecker, user, relation, object string) error {
+	if c == nil {
+		return goa.Fault("no relation checker")
+	}
+	ok, err := c.Check(ctx, user, relation, object)
+	if err != nil {
+		return goa.TemporaryError(UnavailableErrorName, "failed to check relation: %s", err)
+	}
+	if !ok {
+		return goa.PermanentError(ForbiddenErrorName, "%s is not a %s of %s", user, relation, object)
+	}
+	return nil
+}
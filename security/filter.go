@@ -0,0 +1,324 @@
+package security
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/security/expr"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// filterData contains the data necessary to render the method clearing
+	// the scoped fields of a type.
+	filterData struct {
+		// TypeName is the name of the Go type.
+		TypeName string
+		// Fields lists the fields that require scopes.
+		Fields []*scopedFieldData
+		// Nested lists the fields holding values whose fields must be
+		// filtered as well.
+		Nested []*nestedFieldData
+	}
+
+	// scopedFieldData describes a field that requires scopes.
+	scopedFieldData struct {
+		// FieldName is the name of the field.
+		FieldName string
+		// Scopes lists the quoted required scopes.
+		Scopes string
+	}
+
+	// nestedFieldData describes a field holding a type with scoped fields.
+	nestedFieldData struct {
+		// FieldName is the name of the field.
+		FieldName string
+		// Many is true if the field holds an array or a map.
+		Many bool
+	}
+)
+
+// filterFile returns the file defining the methods clearing the scoped fields
+// of the types used by the results of the given service, nil if there is
+// none.
+func filterFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	types := filteredTypes(svc)
+	if len(types) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name+" scoped fields", sd.PkgName, []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}),
+	}
+	for _, ut := range types {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "security-filter-scopes",
+			Source: filterT,
+			Data:   buildFilterData(sd, ut, types),
+		})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "scopes.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// filteredTypes returns the user types used by the results of the given
+// service that have scoped fields or fields holding such types, in the order
+// they are found.
+func filteredTypes(svc *goaexpr.ServiceExpr) []goaexpr.UserType {
+	var (
+		all  []goaexpr.UserType
+		seen = make(map[string]bool)
+	)
+	for _, m := range svc.Methods {
+		walk.Attribute(m.Result, func(_ string, att *goaexpr.AttributeExpr) error {
+			if ut, ok := att.Type.(goaexpr.UserType); ok && !seen[ut.ID()] && goaexpr.AsObject(ut) != nil {
+				seen[ut.ID()] = true
+				all = append(all, ut)
+			}
+			return nil
+		})
+	}
+	// Compute the types to filter until no type is added to handle the
+	// types nested in types that appear first.
+	filtered := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, ut := range all {
+			if filtered[ut.ID()] {
+				continue
+			}
+			for _, nat := range *goaexpr.AsObject(ut) {
+				if len(expr.Scopes(nat.Attribute)) > 0 || nestedType(nat.Attribute, filtered) != nil {
+					filtered[ut.ID()], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	var types []goaexpr.UserType
+	for _, ut := range all {
+		if filtered[ut.ID()] {
+			types = append(types, ut)
+		}
+	}
+	return types
+}
+
+// nestedType returns the user type held by att, its elements or its values if
+// it is filtered, nil otherwise.
+func nestedType(att *goaexpr.AttributeExpr, filtered map[string]bool) goaexpr.UserType {
+	t := att.Type
+	if arr := goaexpr.AsArray(t); arr != nil {
+		t = arr.ElemType.Type
+	} else if m := goaexpr.AsMap(t); m != nil {
+		t = m.ElemType.Type
+	}
+	if ut, ok := t.(goaexpr.UserType); ok && filtered[ut.ID()] {
+		return ut
+	}
+	return nil
+}
+
+// buildFilterData returns the data necessary to render the method clearing
+// the scoped fields of the given type.
+func buildFilterData(sd *service.Data, ut goaexpr.UserType, types []goaexpr.UserType) *filterData {
+	filtered := make(map[string]bool, len(types))
+	for _, t := range types {
+		filtered[t.ID()] = true
+	}
+	data := &filterData{TypeName: sd.Scope.GoTypeName(&goaexpr.AttributeExpr{Type: ut})}
+	for _, nat := range *goaexpr.AsObject(ut) {
+		name := codegen.Goify(nat.Name, true)
+		if scopes := expr.Scopes(nat.Attribute); len(scopes) > 0 {
+			data.Fields = append(data.Fields, &scopedFieldData{FieldName: name, Scopes: quote(scopes)})
+			continue
+		}
+		if nestedType(nat.Attribute, filtered) != nil {
+			many := goaexpr.AsArray(nat.Attribute.Type) != nil || goaexpr.AsMap(nat.Attribute.Type) != nil
+			data.Nested = append(data.Nested, &nestedFieldData{FieldName: name, Many: many})
+		}
+	}
+	return data
+}
+
+// endpointFilter makes the service endpoints of the methods whose results have
+// scoped fields clear the fields the principal may not read if f is the
+// endpoints file of a service.
+func endpointFilter(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || len(filteredTypes(svc)) == 0 {
+		return
+	}
+	codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Name: "authz", Path: pkgPath})
+	for _, s := range f.Section("endpoint-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["filterScopes"] = filterScopes
+		s.Source = strings.Replace(s.Source,
+			"\t\tvres := {{ $.ViewedResult.Init.Name }}",
+			"{{ with filterScopes .ServiceName .Name }}\t\t{{ . }}\n{{ end }}\t\tvres := {{ $.ViewedResult.Init.Name }}", 1)
+		// The links plugin populates the links of the results in the
+		// same branch, filter the results once the links are set.
+		s.Source = strings.Replace(s.Source,
+			"\t\t{{ . }}\n\t\treturn res, nil",
+			"\t\t{{ . }}\n{{ with filterScopes $.ServiceName $.Name }}\t\t{{ . }}\n{{ end }}\t\treturn res, nil", 1)
+		s.Source = strings.Replace(s.Source,
+			"\t\treturn s.{{ .VarName }}(ctx{{ if .PayloadRef }}, {{ $payload }}{{ end }})\n",
+			filterEndpointT, 1)
+	}
+}
+
+// filterScopes returns the statement clearing the scoped fields of the result
+// of the given method, the empty string if the result has none.
+func filterScopes(svc, method string) string {
+	s := goaexpr.Root.Service(svc)
+	if s == nil {
+		return ""
+	}
+	m := s.Method(method)
+	if m == nil || m.Result == nil {
+		return ""
+	}
+	filtered := make(map[string]bool)
+	for _, ut := range filteredTypes(s) {
+		filtered[ut.ID()] = true
+	}
+	if nestedType(m.Result, filtered) == nil {
+		return ""
+	}
+	if goaexpr.AsArray(m.Result.Type) != nil || goaexpr.AsMap(m.Result.Type) != nil {
+		return "for _, r := range res {\n\t\t\tr.FilterScopes(authz.ContextScopes(ctx))\n\t\t}"
+	}
+	return "res.FilterScopes(authz.ContextScopes(ctx))"
+}
+
+// documentScopes adds the scopes required by the result attributes to the
+// descriptions of the response body schemas and the x-field-scopes extension
+// to the operations if f is an OpenAPI file.
+func documentScopes(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("security", op) {
+				return nil
+			}
+			m := walk.OperationMethod(goaexpr.Root, op)
+			if m == nil || m.Result == nil {
+				return nil
+			}
+			fields := make(map[string][]string)
+			walk.Attribute(m.Result, func(path string, att *goaexpr.AttributeExpr) error {
+				if scopes := expr.Scopes(att); len(scopes) > 0 {
+					fields[path] = scopes
+				}
+				return nil
+			})
+			if len(fields) == 0 {
+				return nil
+			}
+			if op.Extensions == nil {
+				op.Extensions = make(map[string]interface{})
+			}
+			op.Extensions["x-field-scopes"] = fields
+			for _, resp := range op.Responses {
+				documentSchema(spec, m.Result, resp.Schema, make(map[*openapi.Schema]struct{}))
+			}
+			return nil
+		})
+	})
+}
+
+// documentSchema adds the required scopes to the descriptions of the given
+// schema properties and of the nested schemas recursively.
+func documentSchema(spec *openapi.V2, att *goaexpr.AttributeExpr, s *openapi.Schema, seen map[*openapi.Schema]struct{}) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		s = spec.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if s == nil {
+			return
+		}
+	}
+	if _, ok := seen[s]; ok {
+		return
+	}
+	seen[s] = struct{}{}
+	if o := goaexpr.AsObject(att.Type); o != nil {
+		for _, nat := range *o {
+			p := s.Properties[nat.Name]
+			if p == nil {
+				continue
+			}
+			if scopes := expr.Scopes(nat.Attribute); len(scopes) > 0 {
+				note := fmt.Sprintf("Requires the %s scopes.", strings.Join(scopes, ", "))
+				if len(scopes) == 1 {
+					note = fmt.Sprintf("Requires the %s scope.", scopes[0])
+				}
+				if !strings.Contains(p.Description, note) {
+					p.Description = strings.TrimSpace(p.Description + " " + note)
+				}
+			}
+			documentSchema(spec, nat.Attribute, p, seen)
+		}
+	}
+	if a := goaexpr.AsArray(att.Type); a != nil {
+		documentSchema(spec, a.ElemType, s.Items, seen)
+	}
+}
+
+// quote returns the comma separated list of the given quoted strings.
+func quote(ss []string) string {
+	qs := make([]string, len(ss))
+	for i, s := range ss {
+		qs[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(qs, ", ")
+}
+
+// input: endpointMethodData
+const filterEndpointT = `	{{- with filterScopes .ServiceName .Name }}
+		res, err := s.{{ $.VarName }}(ctx{{ if $.PayloadRef }}, {{ $payload }}{{ end }})
+		if err != nil {
+			return nil, err
+		}
+		{{ . }}
+		return res, nil
+	{{- else }}
+		return s.{{ .VarName }}(ctx{{ if .PayloadRef }}, {{ $payload }}{{ end }})
+	{{- end }}
+`
+
+// input: filterData
+const filterT = `{{ printf "FilterScopes clears the fields of res that require scopes not listed in scopes." | comment }}
+func (res *{{ .TypeName }}) FilterScopes(scopes []string) {
+	if res == nil {
+		return
+	}
+{{- range .Fields }}
+	if !authz.HasScopes(scopes, {{ .Scopes }}) {
+		res.{{ .FieldName }} = nil
+	}
+{{- end }}
+{{- range .Nested }}
+	{{- if .Many }}
+	for _, v := range res.{{ .FieldName }} {
+		v.FilterScopes(scopes)
+	}
+	{{- else }}
+	res.{{ .FieldName }}.FilterScopes(scopes)
+	{{- end }}
+{{- end }}
+}
+`
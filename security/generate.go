@@ -0,0 +1,671 @@
+package security
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/security/expr"
+	"goa.design/plugins/v3/stream"
+)
+
+const (
+	// accessTokenTTL is the default lifetime of the access tokens.
+	accessTokenTTL = 15 * time.Minute
+	// refreshTokenTTL is the default lifetime of the refresh tokens.
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+type (
+	// signerData contains the data necessary to render the JWT signers of
+	// a service.
+	signerData struct {
+		// Issuer is the name of the API.
+		Issuer string
+		// Methods lists the quoted names of the login methods.
+		Methods string
+		// TTL is the Go code of the access token lifetime.
+		TTL string
+		// RefreshTTL is the Go code of the refresh token lifetime.
+		RefreshTTL string
+		// Schemes lists the JWT schemes used by the design.
+		Schemes []*jwtSchemeData
+	}
+
+	// jwtSchemeData describes a JWT scheme.
+	jwtSchemeData struct {
+		// Name is the scheme name.
+		Name string
+		// VarName is the Go name of the scheme.
+		VarName string
+		// Scopes lists the scopes defined by the scheme.
+		Scopes []string
+	}
+
+	// matrix is the authorization matrix listing the security requirements
+	// of each endpoint.
+	matrix struct {
+		// API is the name of the API.
+		API string `json:"api"`
+		// Endpoints lists the endpoints in the order they are defined.
+		Endpoints []*endpointAuthz `json:"endpoints"`
+	}
+
+	// endpointAuthz describes the access model of an endpoint.
+	endpointAuthz struct {
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the method.
+		Method string `json:"method"`
+		// Routes lists the HTTP routes of the endpoint if any.
+		Routes []*route `json:"routes,omitempty"`
+		// Public is true if the endpoint has no security requirement.
+		Public bool `json:"public"`
+		// Requirements lists the alternative security requirements: a
+		// request must satisfy one of them.
+		Requirements []*requirement `json:"requirements,omitempty"`
+		// Relation is the relation the principal must have with the
+		// object identified by the payload if any.
+		Relation *relation `json:"relation,omitempty"`
+		// Network lists the networks allowed and denied to call the
+		// endpoint if any.
+		Network *network `json:"network,omitempty"`
+	}
+
+	// route is a HTTP route.
+	route struct {
+		// Method is the HTTP method.
+		Method string `json:"method"`
+		// Path is the full path including the API and service base
+		// paths.
+		Path string `json:"path"`
+	}
+
+	// requirement lists the schemes a request must satisfy together with
+	// the required scopes.
+	requirement struct {
+		// Schemes lists the schemes.
+		Schemes []*scheme `json:"schemes"`
+		// Scopes lists the required scopes if any.
+		Scopes []string `json:"scopes,omitempty"`
+	}
+
+	// relation is a relation required by an endpoint.
+	relation struct {
+		// Resource is the object type.
+		Resource string `json:"resource"`
+		// Relation is the relation name.
+		Relation string `json:"relation"`
+		// Attribute is the name of the payload attribute holding the
+		// object identifier.
+		Attribute string `json:"attribute"`
+	}
+
+	// network lists the CIDRs of the networks allowed and denied to call an
+	// endpoint.
+	network struct {
+		// Allow lists the allowed networks, all the networks not denied
+		// are allowed if empty.
+		Allow []string `json:"allow,omitempty"`
+		// Deny lists the denied networks.
+		Deny []string `json:"deny,omitempty"`
+	}
+
+	// scheme is a security scheme.
+	scheme struct {
+		// Name is the scheme name.
+		Name string `json:"name"`
+		// Type is the scheme type: "Basic", "APIKey", "JWT" or "OAuth2".
+		Type string `json:"type"`
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "security",
+		Cmd:      "gen",
+		Generate: Generate,
+		After:    []string{"links"},
+	})
+}
+
+// Generate produces the authz.json authorization matrix which lists the
+// security schemes and scopes required by each endpoint so that policy
+// engines, gateways and auditors can consume the access model without
+// parsing the OpenAPI specification.
+//
+// Generate also makes the endpoints of the services with an OPA policy query
+// the policy once the requests are authenticated and produces the
+// opa_input.json JSON schema of the policy input. It defines the basic auth
+// verifiers of the services with a htpasswd file, the brute-force guards of the
+// services with a brute-force protection and the JWT signers of the services
+// with login methods. It makes the clients of the services with a token
+// propagation send the token of the incoming request or a token exchanged for
+// it. It makes the endpoints clear the result fields that require scopes the
+// principal does not have. It produces the openfga.json OpenFGA authorization
+// model of the resources and the helpers checking the relations required by
+// the methods. It defines the WebAuthn relying parties implementing the
+// passkey ceremonies of the services. It makes the endpoints of the methods
+// requiring a second factor check that the principal verified it and defines
+// the TOTP second factors enrolling the users. Finally it makes the endpoints
+// of the methods with network rules check the client IP before authenticating
+// the requests and defines the network guards of the services.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.EnabledAny("security") {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("security", f) {
+			continue
+		}
+		if len(expr.Root.OPAs) > 0 {
+			endpointAuthorize(f)
+		}
+		if len(expr.Root.SecondFactors) > 0 {
+			endpointRequireSecondFactor(f)
+		}
+		if len(expr.Root.Networks) > 0 {
+			endpointCheckNetwork(f)
+		}
+		if len(expr.Root.BruteForces) > 0 {
+			endpointProtect(f)
+		}
+		if len(expr.Root.Propagations) > 0 {
+			endpointStoreToken(f)
+			clientPropagate(f)
+		}
+		if len(expr.Root.ScopedFields) > 0 {
+			endpointFilter(f)
+			documentScopes(f)
+		}
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			path := filepath.Join(codegen.Gendir, "security", "authz.json")
+			files = append(files, stream.File(path, "security-authz", buildMatrix(r), stream.IndentedJSON))
+			var svcs []*goaexpr.ServiceExpr
+			for _, svc := range r.Services {
+				if !config.Enabled("security", svc.Name) {
+					continue
+				}
+				if f := authorizerFile(svc); f != nil {
+					files = append(files, f)
+					svcs = append(svcs, svc)
+				}
+				if f := verifierFile(svc); f != nil {
+					files = append(files, f)
+				}
+				if f := guardFile(svc); f != nil {
+					files = append(files, f)
+				}
+				if f := tokenSourceFile(svc); f != nil {
+					files = append(files, f)
+				}
+				if f := signerFile(r, svc); f != nil {
+					files = append(files, f)
+				}
+				if f := filterFile(svc); f != nil {
+					files = append(files, f)
+				}
+				if f := relationFile(svc); f != nil {
+					files = append(files, f)
+				}
+				if f := webAuthnFile(r, svc); f != nil {
+					files = append(files, f)
+				}
+				if f := totpFile(r, svc); f != nil {
+					files = append(files, f)
+				}
+				if f := networkFile(svc); f != nil {
+					files = append(files, f)
+				}
+			}
+			if len(svcs) > 0 {
+				path := filepath.Join(codegen.Gendir, "security", "opa_input.json")
+				files = append(files, stream.File(path, "security-opa-input", inputSchema(r, svcs), stream.IndentedJSON))
+			}
+			if len(expr.Root.Resources) > 0 {
+				path := filepath.Join(codegen.Gendir, "security", "openfga.json")
+				files = append(files, stream.File(path, "security-openfga", buildFGAModel(), stream.IndentedJSON))
+			}
+		}
+	}
+	return files, nil
+}
+
+// buildMatrix returns the authorization matrix of the endpoints of the services
+// for which the plugin is enabled.
+func buildMatrix(r *goaexpr.RootExpr) *matrix {
+	m := &matrix{API: r.API.Name, Endpoints: []*endpointAuthz{}}
+	for _, svc := range r.Services {
+		if !config.Enabled("security", svc.Name) {
+			continue
+		}
+		for _, meth := range svc.Methods {
+			e := &endpointAuthz{
+				Service: svc.Name,
+				Method:  meth.Name,
+				Routes:  routes(r, meth),
+				Public:  len(meth.Requirements) == 0,
+			}
+			for _, req := range meth.Requirements {
+				rq := &requirement{Scopes: req.Scopes}
+				for _, s := range req.Schemes {
+					rq.Schemes = append(rq.Schemes, &scheme{Name: s.SchemeName, Type: s.Type()})
+				}
+				e.Requirements = append(e.Requirements, rq)
+			}
+			if c := expr.Root.RelationCheck(svc.Name, meth.Name); c != nil {
+				e.Relation = &relation{Resource: c.Resource, Relation: c.Relation, Attribute: c.Attribute}
+			}
+			if allow, deny := expr.Root.Network(svc.Name, meth.Name); len(allow) > 0 || len(deny) > 0 {
+				e.Network = &network{Allow: allow, Deny: deny}
+			}
+			m.Endpoints = append(m.Endpoints, e)
+		}
+	}
+	return m
+}
+
+// verifierFile returns the file defining the basic auth verifier of the given
+// service, nil if the service has no htpasswd file or no method requiring basic
+// auth.
+func verifierFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	b := expr.Root.BasicAuth(svc.Name)
+	if b == nil || !basicAuth(svc) {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "basic_auth.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" basic auth verifier", sd.PkgName, []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}),
+			{
+				Name:   "security-basic-auth-verifier",
+				Source: verifierT,
+				Data:   map[string]interface{}{"ServiceName": svc.Name, "Path": b.Path},
+			},
+		},
+	}
+}
+
+// guardFile returns the file defining the brute-force guard of the given
+// service, nil if the service has no brute-force protection or no method
+// requiring basic auth.
+func guardFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	b := expr.Root.BruteForce(svc.Name)
+	if b == nil || !basicAuth(svc) {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "brute_force.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" brute-force guard", sd.PkgName, []*codegen.ImportSpec{
+				{Path: "time"},
+				{Name: "authz", Path: pkgPath},
+			}),
+			{
+				Name:   "security-brute-force-guard",
+				Source: guardT,
+				Data: map[string]interface{}{
+					"ServiceName": svc.Name,
+					"MaxAttempts": b.MaxAttempts,
+					"Backoff":     genutil.DurationCode(b.Backoff),
+					"Lockout":     genutil.DurationCode(b.Lockout),
+				},
+			},
+		},
+	}
+}
+
+// webAuthnFile returns the file defining the WebAuthn relying party of the
+// given service, nil if the service defines none.
+func webAuthnFile(r *goaexpr.RootExpr, svc *goaexpr.ServiceExpr) *codegen.File {
+	w := expr.Root.WebAuthn(svc.Name)
+	if w == nil {
+		return nil
+	}
+	origins := make([]string, len(w.Origins))
+	for i, o := range w.Origins {
+		origins[i] = fmt.Sprintf("%q", o)
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "webauthn.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" WebAuthn relying party", sd.PkgName, []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}),
+			{
+				Name:   "security-webauthn",
+				Source: webAuthnT,
+				Data: map[string]interface{}{
+					"ServiceName": svc.Name,
+					"RPID":        w.RPID,
+					"RPName":      r.API.Name,
+					"Origins":     strings.Join(origins, ", "),
+				},
+			},
+		},
+	}
+}
+
+// totpFile returns the file defining the TOTP second factor of the given
+// service, nil if no method of the service requires it.
+func totpFile(r *goaexpr.RootExpr, svc *goaexpr.ServiceExpr) *codegen.File {
+	if len(secondFactorMethods(svc)) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "totp.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" TOTP second factor", sd.PkgName, []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}),
+			{
+				Name:   "security-totp",
+				Source: totpT,
+				Data:   map[string]interface{}{"ServiceName": svc.Name, "Issuer": r.API.Name},
+			},
+		},
+	}
+}
+
+// endpointRequireSecondFactor makes the service endpoints of the methods that
+// require a second factor check that the principal verified it once the
+// request is authenticated if f is the endpoints file of such a service. The
+// check runs before the OPA authorization if any.
+func endpointRequireSecondFactor(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || len(secondFactorMethods(svc)) == 0 {
+		return
+	}
+	codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Name: "authz", Path: pkgPath})
+	for _, s := range f.Section("endpoint-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["requireSecondFactor"] = requireSecondFactor
+		s.Source = strings.Replace(s.Source,
+			"\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n{{-",
+			"\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n{{- with requireSecondFactor .ServiceName .Name }}\n{{ . }}\n{{- end }}\n{{-", 1)
+	}
+}
+
+// requireSecondFactor returns the statements checking that the principal
+// verified a second factor, the empty string if the given method does not
+// require one.
+func requireSecondFactor(svc, method string) string {
+	s := goaexpr.Root.Service(svc)
+	if s == nil {
+		return ""
+	}
+	for _, m := range secondFactorMethods(s) {
+		if m.Name == method {
+			return "\t\tif err = authz.RequireSecondFactor(ctx); err != nil {\n\t\t\treturn nil, err\n\t\t}"
+		}
+	}
+	return ""
+}
+
+// secondFactorMethods returns the methods of the given service that require
+// authentication and a second factor.
+func secondFactorMethods(svc *goaexpr.ServiceExpr) []*goaexpr.MethodExpr {
+	var ms []*goaexpr.MethodExpr
+	for _, m := range svc.Methods {
+		if len(m.Requirements) > 0 && expr.Root.SecondFactor(svc.Name, m.Name) != nil {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// networkFile returns the file defining the network guard of the given
+// service, nil if no method of the service has network rules.
+func networkFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	var policies []map[string]interface{}
+	for _, m := range svc.Methods {
+		allow, deny := expr.Root.Network(svc.Name, m.Name)
+		if len(allow) == 0 && len(deny) == 0 {
+			continue
+		}
+		policies = append(policies, map[string]interface{}{"Method": m.Name, "Allow": allow, "Deny": deny})
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "network.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" network guard", sd.PkgName, []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}),
+			{
+				Name:   "security-network-guard",
+				Source: networkGuardT,
+				Data: map[string]interface{}{
+					"ServiceName":    svc.Name,
+					"Policies":       policies,
+					"TrustedProxies": expr.Root.TrustedProxies(),
+				},
+			},
+		},
+	}
+}
+
+// endpointCheckNetwork makes the service endpoints of the methods with network
+// rules check the client IP before authenticating the request if f is the
+// endpoints file of such a service.
+func endpointCheckNetwork(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || networkFile(svc) == nil {
+		return
+	}
+	for _, s := range f.Section("endpoint-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["checkNetwork"] = checkNetwork
+		s.Source = strings.Replace(s.Source,
+			"\treturn func(ctx context.Context, req interface{}) (interface{}, error) {\n",
+			"\treturn func(ctx context.Context, req interface{}) (interface{}, error) {\n{{- with checkNetwork .ServiceName .Name }}\n{{ . }}\n{{- end }}\n", 1)
+	}
+}
+
+// checkNetwork returns the statements checking that the client IP is allowed
+// to call the given method, the empty string if the method has no network
+// rules.
+func checkNetwork(svc, method string) string {
+	if allow, deny := expr.Root.Network(svc, method); len(allow) == 0 && len(deny) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\t\tif err := NetworkGuard.Check(ctx, %q); err != nil {\n\t\t\treturn nil, err\n\t\t}", method)
+}
+
+// endpointProtect makes the service endpoints of the methods that require basic
+// auth call the basic auth function through the brute-force guard if f is the
+// endpoints file of a service with a brute-force protection.
+func endpointProtect(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || expr.Root.BruteForce(svc.Name) == nil || !basicAuth(svc) {
+		return
+	}
+	for _, s := range f.Section("endpoint-method") {
+		s.Source = strings.Replace(s.Source,
+			"ctx, err = auth{{ .Type }}Fn(ctx, {{ if .UsernamePointer }}",
+			"ctx, err = BruteForceGuard.Protect(auth{{ .Type }}Fn)(ctx, {{ if .UsernamePointer }}", 1)
+	}
+}
+
+// signerFile returns the file defining the JWT signers issuing the tokens of
+// the login methods of the given service, nil if the service has no login
+// method or the design no method secured with JWT. A login method is a method
+// whose payload defines the Username and Password attributes.
+func signerFile(r *goaexpr.RootExpr, svc *goaexpr.ServiceExpr) *codegen.File {
+	var logins []string
+	for _, m := range svc.Methods {
+		if m.Payload != nil &&
+			goaexpr.TaggedAttribute(m.Payload, "security:username") != "" &&
+			goaexpr.TaggedAttribute(m.Payload, "security:password") != "" {
+			logins = append(logins, fmt.Sprintf("%q", m.Name))
+		}
+	}
+	if len(logins) == 0 {
+		return nil
+	}
+	data := &signerData{
+		Issuer:     r.API.Name,
+		Methods:    strings.Join(logins, ", "),
+		TTL:        genutil.DurationCode(accessTokenTTL),
+		RefreshTTL: genutil.DurationCode(refreshTokenTTL),
+	}
+	seen := make(map[string]bool)
+	for _, s := range r.Services {
+		for _, m := range s.Methods {
+			for _, req := range m.Requirements {
+				for _, sch := range req.Schemes {
+					if sch.Kind != goaexpr.JWTKind || seen[sch.SchemeName] {
+						continue
+					}
+					seen[sch.SchemeName] = true
+					js := &jwtSchemeData{Name: sch.SchemeName, VarName: codegen.Goify(sch.SchemeName, true)}
+					for _, sc := range sch.Scopes {
+						js.Scopes = append(js.Scopes, sc.Name)
+					}
+					data.Schemes = append(data.Schemes, js)
+				}
+			}
+		}
+	}
+	if len(data.Schemes) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "jwt_signer.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" JWT signers", sd.PkgName, []*codegen.ImportSpec{
+				{Path: "time"},
+				{Name: "authz", Path: pkgPath},
+			}),
+			{
+				Name:   "security-jwt-signer",
+				Source: signerT,
+				Data:   data,
+			},
+		},
+	}
+}
+
+// basicAuth returns true if a method of the given service requires basic auth.
+func basicAuth(svc *goaexpr.ServiceExpr) bool {
+	for _, m := range svc.Methods {
+		for _, req := range m.Requirements {
+			for _, s := range req.Schemes {
+				if s.Kind == goaexpr.BasicAuthKind {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// routes returns the HTTP routes of the given method.
+func routes(r *goaexpr.RootExpr, m *goaexpr.MethodExpr) []*route {
+	if r.API == nil || r.API.HTTP == nil {
+		return nil
+	}
+	svc := r.API.HTTP.Service(m.Service.Name)
+	if svc == nil {
+		return nil
+	}
+	e := svc.Endpoint(m.Name)
+	if e == nil {
+		return nil
+	}
+	var rts []*route
+	for _, rt := range e.Routes {
+		for _, p := range rt.FullPaths() {
+			rts = append(rts, &route{Method: rt.Method, Path: p})
+		}
+	}
+	return rts
+}
+
+// input: map[string]interface{}{"ServiceName": string, "Path": string}
+const verifierT = `{{ printf "BasicAuthVerifier verifies the basic auth credentials of the requests made to the %q service endpoints against the bcrypt password hashes of the htpasswd file defined in the design. The BasicAuth method of the service may delegate to it, set its Store to use a different user store." .ServiceName | comment }}
+var BasicAuthVerifier = authz.NewBasicAuthVerifier(authz.NewHtpasswdFile({{ printf "%q" .Path }}))
+`
+
+// input: map[string]interface{}{"ServiceName": string, "MaxAttempts": int, "Backoff": string, "Lockout": string}
+const guardT = `{{ printf "BruteForceGuard limits the failed basic auth attempts made to the %q service endpoints per client IP and username as defined in the design. Set its Store to share the failed attempts between the instances of the service." .ServiceName | comment }}
+var BruteForceGuard = &authz.BruteForceGuard{
+	Store:       authz.NewMemoryAttemptStore({{ .Lockout }}),
+	MaxAttempts: {{ .MaxAttempts }},
+	Backoff:     {{ .Backoff }},
+	Lockout:     {{ .Lockout }},
+}
+`
+
+// input: map[string]interface{}{"ServiceName": string, "RPID": string, "RPName": string, "Origins": string}
+const webAuthnT = `{{ printf "WebAuthn implements the passkey ceremonies of the %q service for the %q relying party. The webauthn methods of the service may delegate to it, set its Challenges and Credentials to share the challenges between the instances of the service and to persist the credentials." .ServiceName .RPID | comment }}
+var WebAuthn = authz.NewWebAuthn({{ printf "%q" .RPID }}, {{ printf "%q" .RPName }}, {{ .Origins }})
+`
+
+// input: map[string]interface{}{"ServiceName": string, "Issuer": string}
+const totpT = `{{ printf "TOTP enrolls the users in the time-based one-time password second factor required by the %q service endpoints and verifies their codes. The principal must have verified a code, see authz.WithSecondFactor, to call the endpoints. Set its Store to persist the enrollments." .ServiceName | comment }}
+var TOTP = authz.NewTOTP({{ printf "%q" .Issuer }})
+`
+
+// input: map[string]interface{}{"ServiceName": string, "Policies": []map[string]interface{}, "TrustedProxies": []string}
+const networkGuardT = `{{ printf "NetworkGuard rejects the requests made to the %q service endpoints from the networks denied by the design before authenticating them. The client IP is read from the X-Forwarded-For header when the request comes from a trusted proxy." .ServiceName | comment }}
+var NetworkGuard = authz.MustNetworkGuard(map[string]*authz.NetworkPolicy{
+{{- range .Policies }}
+	{{ printf "%q" .Method }}: {
+	{{- if .Allow }}
+		Allow: []string{ {{- range $i, $c := .Allow }}{{ if $i }}, {{ end }}{{ printf "%q" $c }}{{ end }} },
+	{{- end }}
+	{{- if .Deny }}
+		Deny: []string{ {{- range $i, $c := .Deny }}{{ if $i }}, {{ end }}{{ printf "%q" $c }}{{ end }} },
+	{{- end }}
+	},
+{{- end }}
+}{{ range .TrustedProxies }}, {{ printf "%q" . }}{{ end }})
+`
+
+// input: *signerData
+const signerT = `{{ range .Schemes }}{{ printf "New%sSigner returns a signer issuing the tokens accepted by the %q security scheme, typically in the %s login method once the credentials are verified. key is the HMAC key used to sign and verify the tokens." .VarName .Name $.Methods | comment }}
+func New{{ .VarName }}Signer(key []byte) *authz.JWTSigner {
+	return &authz.JWTSigner{
+		Key:        key,
+		Issuer:     {{ printf "%q" $.Issuer }},
+		TTL:        {{ $.TTL }},
+		RefreshTTL: {{ $.RefreshTTL }},
+	}
+}
+
+{{ printf "%sScopes lists the scopes defined by the %q security scheme." .VarName .Name | comment }}
+var {{ .VarName }}Scopes = []string{ {{- range $i, $s := .Scopes }}{{ if $i }}, {{ end }}{{ printf "%q" $s }}{{ end }} }
+{{ end }}`
@@ -0,0 +1,428 @@
+package security_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/security"
+	"goa.design/plugins/v3/security/expr"
+	"goa.design/plugins/v3/security/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.GroupDSL, expr.Root)
+	fs, err := security.Generate("", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := plugintest.File(t, fs, "gen/security/authz.json")
+	plugintest.Golden(t, "authz.json", plugintest.Render(t, f))
+}
+
+func TestGenerateOPA(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.OPADSL, expr.Root)
+	var fs []*codegen.File
+	for _, svc := range root.Services {
+		fs = append(fs, service.EndpointFile("goa.design/plugins/v3/security/gen", svc))
+	}
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Path, Golden string
+	}{
+		{"gen/billing/endpoints.go", "billing-endpoints.golden"},
+		{"gen/ledger/endpoints.go", "ledger-endpoints.golden"},
+		{"gen/billing/authz.go", "billing-authz.golden"},
+		{"gen/ledger/authz.go", "ledger-authz.golden"},
+		{"gen/security/opa_input.json", "opa_input.json"},
+	}
+	for _, c := range cases {
+		t.Run(c.Golden, func(t *testing.T) {
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, plugintest.File(t, fs, c.Path)))
+		})
+	}
+}
+
+func TestGenerateBasicAuth(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.BasicAuthDSL, expr.Root)
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	f := plugintest.File(t, fs, "gen/billing/basic_auth.go")
+	plugintest.Golden(t, "billing-basic-auth.golden", plugintest.Render(t, f))
+}
+
+func TestGenerateBruteForce(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.BruteForceDSL, expr.Root)
+	var fs []*codegen.File
+	for _, svc := range root.Services {
+		fs = append(fs, service.EndpointFile("goa.design/plugins/v3/security/gen", svc))
+	}
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugintest.Golden(t, "billing-brute-force.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/billing/brute_force.go")))
+	plugintest.Golden(t, "billing-protected-endpoints.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/billing/endpoints.go")))
+	for _, f := range fs {
+		if f.Path == "gen/status/brute_force.go" {
+			t.Error("got brute-force guard for service without basic auth")
+		}
+	}
+}
+
+func TestGeneratePropagation(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.PropagationDSL, expr.Root)
+	var fs []*codegen.File
+	for _, svc := range root.Services {
+		fs = append(fs, service.EndpointFile("goa.design/plugins/v3/security/gen", svc))
+		fs = append(fs, service.ClientFile(svc))
+	}
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Path, Golden string
+	}{
+		{"gen/ledger/client.go", "ledger-client.golden"},
+		{"gen/billing/client.go", "billing-client.golden"},
+		{"gen/ledger/endpoints.go", "ledger-token-endpoints.golden"},
+		{"gen/ledger/token_source.go", "ledger-token-source.golden"},
+		{"gen/billing/token_source.go", "billing-token-source.golden"},
+	}
+	for _, c := range cases {
+		t.Run(c.Golden, func(t *testing.T) {
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, plugintest.File(t, fs, c.Path)))
+		})
+	}
+}
+
+func TestGenerateJWTSigner(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.LoginDSL, expr.Root)
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	f := plugintest.File(t, fs, "gen/session/jwt_signer.go")
+	plugintest.Golden(t, "session-jwt-signer.golden", plugintest.Render(t, f))
+}
+
+func TestGenerateScopedFields(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.ScopedFieldsDSL, expr.Root)
+	fs, err := httpcodegen.OpenAPIFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs = append(fs, service.EndpointFile("goa.design/plugins/v3/security/gen", root.Service("hr")))
+	fs, err = security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugintest.Golden(t, "hr-scopes.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/hr/scopes.go")))
+	plugintest.Golden(t, "hr-endpoints.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/hr/endpoints.go")))
+	spec := plugintest.Spec(t, fs)
+	plugintest.ValidateSpec(t, spec)
+	b, err := json.MarshalIndent(spec.Paths["/team"], "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugintest.Golden(t, "hr-team-path.json", string(b))
+	if d := spec.Definitions["HrTeamResponseBody"].Properties["budget"].Description; d != "Yearly budget Requires the hr:read, hr:admin scopes." {
+		t.Errorf("got budget description %q", d)
+	}
+	// The Employee type is used by the members and lead attributes.
+	fields, _ := spec.Paths["/team"].(*openapi.Path).Get.Extensions["x-field-scopes"].(map[string][]string)
+	for _, f := range []string{"members[].salary", "lead.salary"} {
+		if _, ok := fields[f]; !ok {
+			t.Errorf("%s not listed in x-field-scopes", f)
+		}
+	}
+}
+
+func TestGenerateRelations(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.RelationDSL, expr.Root)
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Path, Golden string
+	}{
+		{"gen/security/openfga.json", "openfga.json"},
+		{"gen/security/authz.json", "docs-authz.json"},
+		{"gen/docs/relations.go", "docs-relations.golden"},
+	}
+	for _, c := range cases {
+		t.Run(c.Golden, func(t *testing.T) {
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, plugintest.File(t, fs, c.Path)))
+		})
+	}
+}
+
+func TestGenerateWebAuthn(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.WebAuthnDSL, expr.Root)
+	fs, err := httpcodegen.OpenAPIFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugintest.Golden(t, "users-webauthn.golden", plugintest.Render(t, plugintest.File(t, fs, "gen/users/webauthn.go")))
+	svc := root.Service("users")
+	cases := []struct {
+		Method string
+		Public bool
+		Token  bool
+		Path   string
+	}{
+		{expr.WebAuthnRegistrationOptionsMethod, false, true, "/webauthn/registration/options"},
+		{expr.WebAuthnRegisterMethod, false, true, "/webauthn/registration"},
+		{expr.WebAuthnLoginOptionsMethod, true, false, "/webauthn/login/options"},
+		{expr.WebAuthnLoginMethod, true, false, "/webauthn/login"},
+	}
+	spec := plugintest.Spec(t, fs)
+	plugintest.ValidateSpec(t, spec)
+	for _, c := range cases {
+		t.Run(c.Method, func(t *testing.T) {
+			m := svc.Method(c.Method)
+			if m == nil {
+				t.Fatal("method not defined")
+			}
+			if public := len(m.Requirements) == 0; public != c.Public {
+				t.Errorf("got public %v, expected %v", public, c.Public)
+			}
+			if token := m.Payload.Find("token") != nil; token != c.Token {
+				t.Errorf("got token attribute %v, expected %v", token, c.Token)
+			}
+			path, ok := spec.Paths[c.Path].(*openapi.Path)
+			if !ok {
+				t.Fatalf("path %s not found", c.Path)
+			}
+			ext := path.Post.Extensions["x-webauthn"]
+			expected := map[string]interface{}{"rpId": "example.com", "step": c.Method}
+			if !reflect.DeepEqual(ext, expected) {
+				t.Errorf("got x-webauthn extension %#v, expected %#v", ext, expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSecondFactor(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.SecondFactorDSL, expr.Root)
+	var fs []*codegen.File
+	for _, svc := range root.Services {
+		fs = append(fs, service.EndpointFile("goa.design/plugins/v3/security/gen", svc))
+	}
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Path, Golden string
+	}{
+		{"gen/billing/endpoints.go", "billing-second-factor-endpoints.golden"},
+		{"gen/ledger/endpoints.go", "ledger-second-factor-endpoints.golden"},
+		{"gen/billing/totp.go", "billing-totp.golden"},
+	}
+	for _, c := range cases {
+		t.Run(c.Golden, func(t *testing.T) {
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, plugintest.File(t, fs, c.Path)))
+		})
+	}
+	statuses := []struct {
+		Service, Method string
+		Status          int
+	}{
+		{"billing", "charge", goaexpr.StatusUnauthorized},
+		{"billing", "status", 0},
+		{"ledger", "balance", 0},
+		{"ledger", "transfer", goaexpr.StatusUnauthorized},
+	}
+	for _, c := range statuses {
+		var status int
+		for _, herr := range root.API.HTTP.Service(c.Service).Endpoint(c.Method).HTTPErrors {
+			if herr.Name == security.SecondFactorRequiredErrorName {
+				status = herr.Response.StatusCode
+			}
+		}
+		if status != c.Status {
+			t.Errorf("%s.%s: got second factor error status %d, expected %d", c.Service, c.Method, status, c.Status)
+		}
+	}
+}
+
+func TestGenerateNetwork(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.NetworkDSL, expr.Root)
+	var fs []*codegen.File
+	for _, svc := range root.Services {
+		fs = append(fs, service.EndpointFile("goa.design/plugins/v3/security/gen", svc))
+	}
+	fs, err := security.Generate("goa.design/plugins/v3/security/gen", []eval.Root{root}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Path, Golden string
+	}{
+		{"gen/admin/endpoints.go", "admin-network-endpoints.golden"},
+		{"gen/admin/network.go", "admin-network.golden"},
+		{"gen/security/authz.json", "network-authz.golden"},
+	}
+	for _, c := range cases {
+		t.Run(c.Golden, func(t *testing.T) {
+			plugintest.Golden(t, c.Golden, plugintest.Render(t, plugintest.File(t, fs, c.Path)))
+		})
+	}
+	for _, m := range []string{"purge", "health"} {
+		var status int
+		for _, herr := range root.API.HTTP.Service("admin").Endpoint(m).HTTPErrors {
+			if herr.Name == security.NetworkDeniedErrorName {
+				status = herr.Response.StatusCode
+			}
+		}
+		if status != goaexpr.StatusForbidden {
+			t.Errorf("admin.%s: got network error status %d, expected %d", m, status, goaexpr.StatusForbidden)
+		}
+	}
+}
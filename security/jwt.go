@@ -0,0 +1,240 @@
+package security
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+	goasecurity "goa.design/goa/v3/security"
+)
+
+type (
+	// JWTSigner issues and verifies JSON Web Tokens signed with HMAC
+	// SHA-256. The access tokens hold the subject and the scopes of the
+	// principal in the "sub" and "scopes" claims. The refresh tokens are
+	// only accepted by Refresh.
+	JWTSigner struct {
+		// Key is the HMAC key.
+		Key []byte
+		// Issuer is the value of the "iss" claim if not empty. The
+		// tokens of other issuers are then rejected.
+		Issuer string
+		// TTL is the lifetime of the access tokens.
+		TTL time.Duration
+		// RefreshTTL is the lifetime of the refresh tokens, no refresh
+		// token is issued if 0.
+		RefreshTTL time.Duration
+	}
+
+	// Claims is the JWT claims set.
+	Claims map[string]interface{}
+
+	// Tokens are the tokens issued to a principal.
+	Tokens struct {
+		// AccessToken is the access token.
+		AccessToken string `json:"access_token"`
+		// RefreshToken is the refresh token if any.
+		RefreshToken string `json:"refresh_token,omitempty"`
+		// ExpiresIn is the lifetime of the access token in seconds.
+		ExpiresIn int64 `json:"expires_in"`
+	}
+)
+
+const (
+	// tokenTypeClaim is the claim distinguishing the refresh tokens.
+	tokenTypeClaim = "typ"
+	// refreshTokenType is the type of the refresh tokens.
+	refreshTokenType = "refresh"
+)
+
+// jwtHeader is the encoded header of the tokens.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims returns the claims of an access token issued now to the given
+// subject with the given scopes. The returned claims may be completed before
+// calling Sign.
+func (s *JWTSigner) Claims(subject string, scopes ...string) Claims {
+	now := time.Now()
+	c := Claims{
+		"sub":    subject,
+		"iat":    now.Unix(),
+		"exp":    now.Add(s.TTL).Unix(),
+		"scopes": scopes,
+	}
+	if s.Issuer != "" {
+		c["iss"] = s.Issuer
+	}
+	return c
+}
+
+// Issue returns the access token and, if RefreshTTL is not 0, the refresh
+// token of the given subject with the given scopes.
+func (s *JWTSigner) Issue(subject string, scopes ...string) (*Tokens, error) {
+	access, err := s.Sign(s.Claims(subject, scopes...))
+	if err != nil {
+		return nil, err
+	}
+	ts := &Tokens{AccessToken: access, ExpiresIn: int64(s.TTL / time.Second)}
+	if s.RefreshTTL > 0 {
+		c := s.Claims(subject, scopes...)
+		c["exp"] = time.Unix(c["iat"].(int64), 0).Add(s.RefreshTTL).Unix()
+		c[tokenTypeClaim] = refreshTokenType
+		if ts.RefreshToken, err = s.Sign(c); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+// Refresh returns new tokens for the subject and scopes of the given refresh
+// token. It returns an "unauthorized" error if the token is invalid, expired
+// or is not a refresh token.
+func (s *JWTSigner) Refresh(refreshToken string) (*Tokens, error) {
+	c, err := s.parse(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if c[tokenTypeClaim] != refreshTokenType {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "not a refresh token")
+	}
+	return s.Issue(c.Subject(), c.Scopes()...)
+}
+
+// Sign returns the signed token holding the given claims.
+func (s *JWTSigner) Sign(c Claims) (string, error) {
+	if len(s.Key) == 0 {
+		return "", errors.New("JWT signer has no key")
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(b)
+	return unsigned + "." + s.signature(unsigned), nil
+}
+
+// Parse verifies the signature, the expiry, the issuer and the "nbf" time of
+// the given access token and returns its claims. It returns an "unauthorized"
+// error if the token is invalid, expired, not valid yet, issued by another
+// issuer or is a refresh token.
+func (s *JWTSigner) Parse(token string) (Claims, error) {
+	c, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if c[tokenTypeClaim] == refreshTokenType {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "refresh tokens are not accepted")
+	}
+	return c, nil
+}
+
+// JWTAuth verifies the given access token and the scopes required by the
+// scheme. It has the signature of the goa JWT auth functions so that the
+// service JWTAuth methods may delegate to it. It returns a copy of ctx holding
+// the claims as principal, see WithPrincipal, if the token is valid and an
+// "unauthorized" error otherwise.
+func (s *JWTSigner) JWTAuth(ctx context.Context, token string, scheme *goasecurity.JWTScheme) (context.Context, error) {
+	c, err := s.Parse(token)
+	if err != nil {
+		return ctx, err
+	}
+	if scheme != nil {
+		if err := scheme.Validate(c.Scopes()); err != nil {
+			return ctx, goa.PermanentError(UnauthorizedErrorName, err.Error())
+		}
+	}
+	return WithPrincipal(ctx, c), nil
+}
+
+// Subject returns the value of the "sub" claim.
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+// Scopes returns the value of the "scopes" claim.
+func (c Claims) Scopes() []string {
+	switch v := c["scopes"].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		scopes := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				scopes = append(scopes, str)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+// SecondFactor returns true if the "amr" claim lists the "otp" or "mfa"
+// authentication method.
+func (c Claims) SecondFactor() bool {
+	var amr []string
+	switch v := c["amr"].(type) {
+	case []string:
+		amr = v
+	case []interface{}:
+		for _, m := range v {
+			if s, ok := m.(string); ok {
+				amr = append(amr, s)
+			}
+		}
+	}
+	for _, m := range amr {
+		if m == "otp" || m == "mfa" {
+			return true
+		}
+	}
+	return false
+}
+
+// parse verifies the signature, the expiry, the issuer and the "nbf" time of
+// the given token and returns its claims.
+func (s *JWTSigner) parse(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "invalid token")
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.signature(parts[0]+"."+parts[1]))) {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "invalid token signature")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "invalid token claims")
+	}
+	var c Claims
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "invalid token claims")
+	}
+	now := time.Now().Unix()
+	exp, ok := c["exp"].(float64)
+	if !ok || now >= int64(exp) {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "token expired")
+	}
+	if v, ok := c["nbf"]; ok {
+		nbf, ok := v.(float64)
+		if !ok || now < int64(nbf) {
+			return nil, goa.PermanentError(UnauthorizedErrorName, "token not valid yet")
+		}
+	}
+	if iss, _ := c["iss"].(string); s.Issuer != "" && iss != s.Issuer {
+		return nil, goa.PermanentError(UnauthorizedErrorName, "invalid token issuer")
+	}
+	return c, nil
+}
+
+// signature returns the encoded HMAC SHA-256 signature of the given string.
+func (s *JWTSigner) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,174 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"goa.design/goa/v3/http/middleware"
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/plugins/v3/security/expr"
+)
+
+// NetworkDeniedErrorName is the name of the error returned by the endpoints
+// when the client IP is not allowed to call the method. The AllowCIDR and
+// DenyCIDR DSL map it to the 403 Forbidden status.
+const NetworkDeniedErrorName = expr.NetworkDeniedErrorName
+
+type (
+	// NetworkPolicy lists the networks allowed and denied to call a method.
+	NetworkPolicy struct {
+		// Allow lists the CIDRs of the networks allowed to call the
+		// method. All the networks are allowed if empty.
+		Allow []string
+		// Deny lists the CIDRs of the networks denied to call the
+		// method. They take precedence over the allowed networks.
+		Deny []string
+	}
+
+	// NetworkGuard rejects the requests made from the networks the
+	// policies of the methods do not allow. Create it with
+	// NewNetworkGuard.
+	NetworkGuard struct {
+		// ClientIP returns the IP of the client making the request
+		// given its context, the empty string if unknown. The default
+		// returns the host of the remote address stored in the context
+		// by the goa PopulateRequestContext HTTP middleware, or the
+		// last address of the X-Forwarded-For header which is not a
+		// trusted proxy if the remote address is one. The client IP
+		// is unknown if the header is invalid.
+		ClientIP func(ctx context.Context) string
+
+		// policies lists the parsed network policies indexed by method
+		// name.
+		policies map[string]*networkPolicy
+		// trustedProxies lists the networks of the trusted proxies.
+		trustedProxies []*net.IPNet
+	}
+
+	// networkPolicy is a NetworkPolicy with parsed CIDRs.
+	networkPolicy struct {
+		allow []*net.IPNet
+		deny  []*net.IPNet
+	}
+)
+
+// NewNetworkGuard returns a guard enforcing the given network policies indexed
+// by method name. The methods without policy accept all the networks. The
+// X-Forwarded-For header of the requests made by the proxies in the networks
+// with the given CIDRs is trusted. NewNetworkGuard returns an error if a CIDR
+// is invalid.
+func NewNetworkGuard(policies map[string]*NetworkPolicy, trustedProxies ...string) (*NetworkGuard, error) {
+	g := &NetworkGuard{policies: make(map[string]*networkPolicy, len(policies))}
+	for method, p := range policies {
+		if p == nil || len(p.Allow) == 0 && len(p.Deny) == 0 {
+			continue
+		}
+		allow, err := parseCIDRs(p.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %s", method, err)
+		}
+		deny, err := parseCIDRs(p.Deny)
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %s", method, err)
+		}
+		g.policies[method] = &networkPolicy{allow: allow, deny: deny}
+	}
+	proxies, err := parseCIDRs(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("trusted proxies: %s", err)
+	}
+	g.trustedProxies = proxies
+	return g, nil
+}
+
+// MustNetworkGuard is like NewNetworkGuard but panics if a CIDR is invalid.
+// The generated code uses it as the CIDRs are validated with the design.
+func MustNetworkGuard(policies map[string]*NetworkPolicy, trustedProxies ...string) *NetworkGuard {
+	g, err := NewNetworkGuard(policies, trustedProxies...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Check returns a "network_denied" error if the policy of the method with the
+// given name does not allow the client making the request. The client is
+// rejected if its IP is unknown.
+func (g *NetworkGuard) Check(ctx context.Context, method string) error {
+	p, ok := g.policies[method]
+	if !ok {
+		return nil
+	}
+	ip := g.clientIP(ctx)
+	if ip == nil {
+		return goa.PermanentError(NetworkDeniedErrorName, "client IP is unknown")
+	}
+	if contains(p.deny, ip) {
+		return goa.PermanentError(NetworkDeniedErrorName, "client IP %s is denied", ip)
+	}
+	if len(p.allow) > 0 && !contains(p.allow, ip) {
+		return goa.PermanentError(NetworkDeniedErrorName, "client IP %s is not allowed", ip)
+	}
+	return nil
+}
+
+// clientIP returns the IP of the client making the request, nil if unknown.
+func (g *NetworkGuard) clientIP(ctx context.Context) net.IP {
+	if g.ClientIP != nil {
+		return net.ParseIP(g.ClientIP(ctx))
+	}
+	addr, _ := ctx.Value(middleware.RequestRemoteAddrKey).(string)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil || !contains(g.trustedProxies, ip) {
+		return ip
+	}
+	forwarded, _ := ctx.Value(middleware.RequestXForwardedForKey).(string)
+	if forwarded == "" {
+		forwarded, _ = ctx.Value(middleware.RequestXRealIPKey).(string)
+	}
+	if forwarded == "" {
+		return ip
+	}
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// The trusted proxies appended to an invalid header forged
+			// by the client.
+			return nil
+		}
+		ip = hop
+		if !contains(g.trustedProxies, hop) {
+			return ip
+		}
+	}
+	return ip
+}
+
+// parseCIDRs parses the given CIDRs.
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %s", c, err)
+		}
+		nets[i] = n
+	}
+	return nets, nil
+}
+
+// contains returns true if ip belongs to one of the given networks.
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
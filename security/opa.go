@@ -0,0 +1,226 @@
+package security
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/security/expr"
+)
+
+// schema is the subset of JSON schema used to describe the policy input.
+type schema struct {
+	// Schema is the URI of the JSON schema dialect.
+	Schema string `json:"$schema,omitempty"`
+	// Title is the schema title.
+	Title string `json:"title,omitempty"`
+	// Description describes the value.
+	Description string `json:"description,omitempty"`
+	// Type is the JSON type of the value.
+	Type string `json:"type,omitempty"`
+	// Const is the only valid value if any.
+	Const string `json:"const,omitempty"`
+	// Enum lists the valid values if any.
+	Enum []string `json:"enum,omitempty"`
+	// Properties describes the object properties.
+	Properties map[string]*schema `json:"properties,omitempty"`
+	// Required lists the required object properties.
+	Required []string `json:"required,omitempty"`
+	// OneOf lists the alternative schemas.
+	OneOf []*schema `json:"oneOf,omitempty"`
+}
+
+// pkgPath is the import path of the package implementing the authorizers.
+const pkgPath = "goa.design/plugins/v3/security"
+
+// authorizerFile returns the file defining the authorizer of the given service,
+// nil if the service has no OPA policy or no method requiring authentication.
+func authorizerFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	o := expr.Root.OPA(svc.Name)
+	if o == nil || len(authorizedMethods(svc)) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	specs := []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}
+	if o.CacheTTL > 0 {
+		specs = append([]*codegen.ImportSpec{{Path: "time"}}, specs...)
+	}
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "authz.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" authorizer", sd.PkgName, specs),
+			{
+				Name:   "security-authorizer",
+				Source: authorizerT,
+				Data: map[string]interface{}{
+					"ServiceName": svc.Name,
+					"URL":         o.URL,
+					"TTL":         genutil.DurationCode(o.CacheTTL),
+				},
+			},
+		},
+	}
+}
+
+// endpointAuthorize makes the service endpoints of the methods that require
+// authentication call the authorizer once the request is authenticated if f
+// is the endpoints file of a service with an OPA policy.
+func endpointAuthorize(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || expr.Root.OPA(svc.Name) == nil || len(authorizedMethods(svc)) == 0 {
+		return
+	}
+	codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Name: "authz", Path: pkgPath})
+	for _, s := range f.Section("endpoint-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["authorize"] = authorize
+		s.Source = strings.Replace(s.Source,
+			"\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n{{- end }}\n{{- if .ServerStream }}",
+			"\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n{{- with authorize .ServiceName .Name $payload }}\n{{ . }}\n{{- end }}\n{{- end }}\n{{- if .ServerStream }}", 1)
+	}
+}
+
+// authorize returns the statements calling the authorizer of the given method,
+// the empty string if the method does not require authentication. payload is
+// the name of the variable holding the payload.
+func authorize(svc, method, payload string) string {
+	s := goaexpr.Root.Service(svc)
+	if s == nil {
+		return ""
+	}
+	m := s.Method(method)
+	if m == nil || len(m.Requirements) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\t\tif err = OPAAuthorizer.Authorize(ctx, &authz.Input{\n")
+	fmt.Fprintf(&b, "\t\t\tService: %q,\n\t\t\tMethod: %q,\n", svc, method)
+	if atts := summary(m); len(atts) > 0 {
+		b.WriteString("\t\t\tPayload: map[string]interface{}{\n")
+		for _, nat := range atts {
+			fmt.Fprintf(&b, "\t\t\t\t%q: %s.%s,\n", nat.Name, payload, codegen.Goify(nat.Name, true))
+		}
+		b.WriteString("\t\t\t},\n")
+	}
+	b.WriteString("\t\t}); err != nil {\n\t\t\treturn nil, err\n\t\t}")
+	return b.String()
+}
+
+// inputSchema returns the JSON schema of the policy input documents of the
+// endpoints of the given services.
+func inputSchema(r *goaexpr.RootExpr, svcs []*goaexpr.ServiceExpr) *schema {
+	s := &schema{
+		Schema: "http://json-schema.org/draft-07/schema#",
+		Title:  fmt.Sprintf("%s OPA policy input", r.API.Name),
+		Type:   "object",
+		Properties: map[string]*schema{
+			"principal": {Description: "Principal stored in the request context by the authentication functions."},
+			"service":   {Type: "string"},
+			"method":    {Type: "string"},
+			"payload":   {Type: "object"},
+		},
+		Required: []string{"service", "method"},
+	}
+	for _, svc := range svcs {
+		s.Properties["service"].Enum = append(s.Properties["service"].Enum, svc.Name)
+		for _, m := range authorizedMethods(svc) {
+			e := &schema{
+				Properties: map[string]*schema{
+					"service": {Const: svc.Name},
+					"method":  {Const: m.Name},
+				},
+			}
+			if atts := summary(m); len(atts) > 0 {
+				p := &schema{Type: "object", Properties: make(map[string]*schema)}
+				for _, nat := range atts {
+					p.Properties[nat.Name] = &schema{Description: nat.Attribute.Description, Type: jsonType(nat.Attribute.Type)}
+					if m.Payload.IsRequired(nat.Name) || nat.Attribute.DefaultValue != nil {
+						p.Required = append(p.Required, nat.Name)
+					}
+				}
+				e.Properties["payload"] = p
+			}
+			s.OneOf = append(s.OneOf, e)
+		}
+	}
+	return s
+}
+
+// authorizedMethods returns the methods of the given service that require
+// authentication.
+func authorizedMethods(svc *goaexpr.ServiceExpr) []*goaexpr.MethodExpr {
+	var ms []*goaexpr.MethodExpr
+	for _, m := range svc.Methods {
+		if len(m.Requirements) > 0 {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// summary returns the payload attributes of the given method included in the
+// policy input: the primitive attributes that do not hold credentials.
+func summary(m *goaexpr.MethodExpr) []*goaexpr.NamedAttributeExpr {
+	if m.Payload == nil {
+		return nil
+	}
+	obj := goaexpr.AsObject(m.Payload.Type)
+	if obj == nil {
+		return nil
+	}
+	var atts []*goaexpr.NamedAttributeExpr
+	for _, nat := range *obj {
+		if jsonType(nat.Attribute.Type) == "" || credential(nat.Attribute) {
+			continue
+		}
+		atts = append(atts, nat)
+	}
+	return atts
+}
+
+// credential returns true if the given attribute holds credentials, i.e. it is
+// defined with Username, Password, APIKey, Token or AccessToken.
+func credential(att *goaexpr.AttributeExpr) bool {
+	for k := range att.Meta {
+		switch {
+		case k == "security:username", k == "security:password", k == "security:token",
+			k == "security:accesstoken", strings.HasPrefix(k, "security:apikey:"):
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON type of the given primitive type, the empty string
+// if the type is not a primitive type or is Any.
+func jsonType(dt goaexpr.DataType) string {
+	switch dt.Kind() {
+	case goaexpr.BooleanKind:
+		return "boolean"
+	case goaexpr.IntKind, goaexpr.Int32Kind, goaexpr.Int64Kind,
+		goaexpr.UIntKind, goaexpr.UInt32Kind, goaexpr.UInt64Kind:
+		return "integer"
+	case goaexpr.Float32Kind, goaexpr.Float64Kind:
+		return "number"
+	case goaexpr.StringKind, goaexpr.BytesKind:
+		return "string"
+	default:
+		return ""
+	}
+}
+
+// input: map[string]interface{}{"ServiceName": string, "URL": string, "TTL": string}
+const authorizerT = `{{ printf "OPAAuthorizer authorizes the authenticated requests made to the %q service endpoints. It queries the Open Policy Agent policy defined in the design by default, set it to use a different policy or authorizer." .ServiceName | comment }}
+var OPAAuthorizer authz.Authorizer = authz.NewOPAAuthorizer({{ printf "%q" .URL }}, {{ .TTL }})
+`
@@ -0,0 +1,150 @@
+package security
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/security/expr"
+)
+
+// tokenSourceFile returns the file defining the token source of the clients of
+// the given service, nil if the service has no token propagation or no method
+// secured with JWT or OAuth2.
+func tokenSourceFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	p := expr.Root.Propagation(svc.Name)
+	if p == nil || len(tokenMethods(svc)) == 0 {
+		return nil
+	}
+	sd := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "token_source.go")
+	return &codegen.File{
+		Path: path,
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name+" client token source", sd.PkgName, []*codegen.ImportSpec{{Name: "authz", Path: pkgPath}}),
+			{
+				Name:   "security-token-source",
+				Source: tokenSourceT,
+				Data: map[string]interface{}{
+					"ServiceName": svc.Name,
+					"URL":         p.ExchangeURL,
+					"Audience":    p.Audience,
+				},
+			},
+		},
+	}
+}
+
+// endpointStoreToken makes the service endpoints of the methods secured with
+// JWT or OAuth2 store the token of the request in the context so that the
+// clients called by the service methods may propagate it if f is the endpoints
+// file of a service.
+func endpointStoreToken(f *codegen.File) {
+	if filepath.Base(f.Path) != "endpoints.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || len(tokenMethods(svc)) == 0 {
+		return
+	}
+	codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Name: "authz", Path: pkgPath})
+	cred := "{{ if $s.CredPointer }}token{{ else }}{{ $payload }}.{{ $s.CredField }}{{ end }}"
+	for _, s := range f.Section("endpoint-method") {
+		s.Source = strings.Replace(s.Source,
+			"ctx, err = auth{{ .Type }}Fn(ctx, "+cred+", &sc)",
+			"ctx, err = auth{{ .Type }}Fn(authz.WithToken(ctx, "+cred+"), "+cred+", &sc)", -1)
+	}
+}
+
+// clientPropagate makes the client methods of the endpoints secured with JWT or
+// OAuth2 set the token of the payload with the token source when it is not set
+// if f is the client file of a service with a token propagation.
+func clientPropagate(f *codegen.File) {
+	if filepath.Base(f.Path) != "client.go" {
+		return
+	}
+	svc := goaexpr.Root.Service(config.FileService(f))
+	if svc == nil || expr.Root.Propagation(svc.Name) == nil || len(tokenMethods(svc)) == 0 {
+		return
+	}
+	for _, s := range f.Section("client-method") {
+		if s.FuncMap == nil {
+			s.FuncMap = make(map[string]interface{})
+		}
+		s.FuncMap["propagateToken"] = propagateToken
+		s.Source = strings.Replace(s.Source,
+			"\t{{ if .ResultRef }}ires{{ else }}_{{ end }}, err = c.{{ .VarName}}Endpoint(",
+			"{{- with propagateToken .ServiceName .Name }}\n{{ . }}\n{{- end }}\n\t{{ if .ResultRef }}ires{{ else }}_{{ end }}, err = c.{{ .VarName}}Endpoint(", 1)
+	}
+}
+
+// propagateToken returns the statements setting the token of the payload of the
+// given method with the token source, the empty string if the method is not
+// secured with JWT or OAuth2.
+func propagateToken(svc, method string) string {
+	s := goaexpr.Root.Service(svc)
+	if s == nil {
+		return ""
+	}
+	m := s.Method(method)
+	if m == nil {
+		return ""
+	}
+	name := tokenAttribute(m)
+	if name == "" {
+		return ""
+	}
+	field := codegen.Goify(name, true)
+	if m.Payload.IsPrimitivePointer(name, true) {
+		return fmt.Sprintf("\tif p.%s == nil {\n\t\tvar token string\n\t\tif token, err = TokenSource.Token(ctx); err != nil {\n\t\t\treturn\n\t\t}\n\t\tif token != \"\" {\n\t\t\tp.%s = &token\n\t\t}\n\t}", field, field)
+	}
+	return fmt.Sprintf("\tif p.%s == \"\" {\n\t\tif p.%s, err = TokenSource.Token(ctx); err != nil {\n\t\t\treturn\n\t\t}\n\t}", field, field)
+}
+
+// tokenMethods returns the methods of the given service whose payload holds
+// the token of a JWT or OAuth2 scheme.
+func tokenMethods(svc *goaexpr.ServiceExpr) []*goaexpr.MethodExpr {
+	var ms []*goaexpr.MethodExpr
+	for _, m := range svc.Methods {
+		if tokenAttribute(m) != "" {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// tokenAttribute returns the name of the payload attribute holding the token
+// of the JWT or OAuth2 scheme securing the given method, the empty string if
+// there is none.
+func tokenAttribute(m *goaexpr.MethodExpr) string {
+	if m.Payload == nil || goaexpr.AsObject(m.Payload.Type) == nil {
+		return ""
+	}
+	for _, req := range m.Requirements {
+		for _, s := range req.Schemes {
+			switch s.Kind {
+			case goaexpr.JWTKind:
+				if name := goaexpr.TaggedAttribute(m.Payload, "security:token"); name != "" {
+					return name
+				}
+			case goaexpr.OAuth2Kind:
+				if name := goaexpr.TaggedAttribute(m.Payload, "security:accesstoken"); name != "" {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// input: map[string]interface{}{"ServiceName": string, "URL": string, "Audience": string}
+const tokenSourceT = `{{ if .URL }}{{ printf "TokenSource provides the tokens sent by the %q service client to the endpoints secured with JWT or OAuth2 when the payload does not set them. It exchanges the token of the incoming request for a token issued for the audience defined in the design, set it to use a different token source." .ServiceName | comment }}
+var TokenSource authz.TokenSource = authz.NewTokenExchanger({{ printf "%q" .URL }}, {{ printf "%q" .Audience }})
+{{- else }}{{ printf "TokenSource provides the tokens sent by the %q service client to the endpoints secured with JWT or OAuth2 when the payload does not set them. It forwards the token of the incoming request, set it to use a different token source." .ServiceName | comment }}
+var TokenSource authz.TokenSource = authz.ForwardToken{}
+{{- end }}
+`
@@ -0,0 +1,206 @@
+package security
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/security/expr"
+)
+
+type (
+	// fgaModel is an OpenFGA authorization model.
+	fgaModel struct {
+		// SchemaVersion is the version of the model schema.
+		SchemaVersion string `json:"schema_version"`
+		// TypeDefinitions lists the object types.
+		TypeDefinitions []*fgaType `json:"type_definitions"`
+	}
+
+	// fgaType is an OpenFGA type definition.
+	fgaType struct {
+		// Type is the type name.
+		Type string `json:"type"`
+		// Relations maps the relation names to their definitions.
+		Relations map[string]*fgaUserset `json:"relations,omitempty"`
+		// Metadata lists the types that may be directly related to
+		// the objects.
+		Metadata *fgaMetadata `json:"metadata,omitempty"`
+	}
+
+	// fgaUserset is an OpenFGA relation definition.
+	fgaUserset struct {
+		// This is set if the users may be directly related.
+		This *struct{} `json:"this,omitempty"`
+		// ComputedUserset is set if the users having another relation
+		// with the same object have the relation.
+		ComputedUserset *fgaObjectRelation `json:"computedUserset,omitempty"`
+		// Union is set if the users of any of the children usersets
+		// have the relation.
+		Union *fgaUsersets `json:"union,omitempty"`
+	}
+
+	// fgaObjectRelation is a relation of the same object.
+	fgaObjectRelation struct {
+		// Relation is the relation name.
+		Relation string `json:"relation"`
+	}
+
+	// fgaUsersets lists usersets.
+	fgaUsersets struct {
+		// Child lists the usersets.
+		Child []*fgaUserset `json:"child"`
+	}
+
+	// fgaMetadata is the metadata of an OpenFGA type definition.
+	fgaMetadata struct {
+		// Relations maps the relation names to their metadata.
+		Relations map[string]*fgaRelationMetadata `json:"relations"`
+	}
+
+	// fgaRelationMetadata is the metadata of an OpenFGA relation.
+	fgaRelationMetadata struct {
+		// DirectlyRelatedUserTypes lists the types of the users that
+		// may be directly related to the objects.
+		DirectlyRelatedUserTypes []*fgaRelationReference `json:"directly_related_user_types"`
+	}
+
+	// fgaRelationReference is a type of users directly related to objects.
+	fgaRelationReference struct {
+		// Type is the object type.
+		Type string `json:"type"`
+		// Relation is set if the users are the users having the
+		// relation with an object of the type.
+		Relation string `json:"relation,omitempty"`
+		// Wildcard is set if all the objects of the type are related.
+		Wildcard *struct{} `json:"wildcard,omitempty"`
+	}
+
+	// relationCheckData contains the data necessary to render the helper
+	// checking the relation required by a method.
+	relationCheckData struct {
+		// VarName is the Go name of the method.
+		VarName string
+		// MethodName is the name of the method.
+		MethodName string
+		// PayloadRef is the reference to the payload type.
+		PayloadRef string
+		// Resource is the name of the object type.
+		Resource string
+		// Relation is the name of the required relation.
+		Relation string
+		// Attribute is the name of the payload attribute holding the
+		// object identifier.
+		Attribute string
+		// FieldName is the name of the payload field holding the object
+		// identifier.
+		FieldName string
+	}
+)
+
+// buildFGAModel returns the OpenFGA authorization model of the resources. The
+// types of the users which are not resources are defined first.
+func buildFGAModel() *fgaModel {
+	m := &fgaModel{SchemaVersion: "1.1"}
+	seen := make(map[string]bool)
+	for _, r := range expr.Root.Resources {
+		seen[r.Name] = true
+	}
+	for _, r := range expr.Root.Resources {
+		for _, rel := range r.Relations {
+			for _, ut := range rel.UserTypes {
+				if name := relationReference(ut).Type; !seen[name] {
+					seen[name] = true
+					m.TypeDefinitions = append(m.TypeDefinitions, &fgaType{Type: name})
+				}
+			}
+		}
+	}
+	for _, r := range expr.Root.Resources {
+		t := &fgaType{
+			Type:      r.Name,
+			Relations: make(map[string]*fgaUserset),
+			Metadata:  &fgaMetadata{Relations: make(map[string]*fgaRelationMetadata)},
+		}
+		for _, rel := range r.Relations {
+			var children []*fgaUserset
+			md := &fgaRelationMetadata{DirectlyRelatedUserTypes: []*fgaRelationReference{}}
+			if len(rel.UserTypes) > 0 {
+				children = append(children, &fgaUserset{This: &struct{}{}})
+				for _, ut := range rel.UserTypes {
+					md.DirectlyRelatedUserTypes = append(md.DirectlyRelatedUserTypes, relationReference(ut))
+				}
+			}
+			for _, i := range rel.ImpliedBy {
+				children = append(children, &fgaUserset{ComputedUserset: &fgaObjectRelation{Relation: i}})
+			}
+			if len(children) == 1 {
+				t.Relations[rel.Name] = children[0]
+			} else {
+				t.Relations[rel.Name] = &fgaUserset{Union: &fgaUsersets{Child: children}}
+			}
+			t.Metadata.Relations[rel.Name] = md
+		}
+		m.TypeDefinitions = append(m.TypeDefinitions, t)
+	}
+	return m
+}
+
+// relationReference returns the OpenFGA reference of the given user type.
+func relationReference(ut string) *fgaRelationReference {
+	if strings.HasSuffix(ut, ":*") {
+		return &fgaRelationReference{Type: strings.TrimSuffix(ut, ":*"), Wildcard: &struct{}{}}
+	}
+	if i := strings.Index(ut, "#"); i > 0 {
+		return &fgaRelationReference{Type: ut[:i], Relation: ut[i+1:]}
+	}
+	return &fgaRelationReference{Type: ut}
+}
+
+// relationFile returns the file defining the helpers checking the relations
+// required by the methods of the given service, nil if there is none.
+func relationFile(svc *goaexpr.ServiceExpr) *codegen.File {
+	sd := service.Services.Get(svc.Name)
+	var sections []*codegen.SectionTemplate
+	for _, m := range svc.Methods {
+		c := expr.Root.RelationCheck(svc.Name, m.Name)
+		if c == nil {
+			continue
+		}
+		md := sd.Method(m.Name)
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "security-relation-check",
+			Source: relationCheckT,
+			Data: &relationCheckData{
+				VarName:    md.VarName,
+				MethodName: m.Name,
+				PayloadRef: md.PayloadRef,
+				Resource:   c.Resource,
+				Relation:   c.Relation,
+				Attribute:  c.Attribute,
+				FieldName:  codegen.Goify(c.Attribute, true),
+			},
+		})
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	header := codegen.Header(svc.Name+" relation checks", sd.PkgName, []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "fmt"},
+		{Name: "authz", Path: pkgPath},
+	})
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "relations.go"),
+		SectionTemplates: append([]*codegen.SectionTemplate{header}, sections...),
+	}
+}
+
+// input: *relationCheckData
+const relationCheckT = `{{ printf "Check%sRelation checks that user has the %q relation with the %q object identified by the %q attribute of the %q method payload. It returns a \"forbidden\" error if the user does not have the relation." .VarName .Relation .Resource .Attribute .MethodName | comment }}
+func Check{{ .VarName }}Relation(ctx context.Context, c authz.RelationChecker, user string, p {{ .PayloadRef }}) error {
+	return authz.CheckRelation(ctx, c, user, {{ printf "%q" .Relation }}, fmt.Sprintf({{ printf "%q" (printf "%s:%%v" .Resource) }}, p.{{ .FieldName }}))
+}
+`
@@ -0,0 +1,235 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+const (
+	// ForbiddenErrorName is the name of the error returned when the policy
+	// denies a request. Map it to the 403 Forbidden status with the goa
+	// Error and Response DSL functions.
+	ForbiddenErrorName = "forbidden"
+
+	// UnavailableErrorName is the name of the error returned when the
+	// policy cannot be queried, goa renders it with the 503 Service
+	// Unavailable status.
+	UnavailableErrorName = "authorization_unavailable"
+
+	// maxCacheEntries is the number of cached decisions above which the
+	// expired decisions are evicted.
+	maxCacheEntries = 10000
+)
+
+type (
+	// Input is the input document given to the policy.
+	Input struct {
+		// Principal is the principal stored in the request context by
+		// the authentication functions with WithPrincipal if any.
+		Principal interface{} `json:"principal,omitempty"`
+		// Service is the name of the service.
+		Service string `json:"service"`
+		// Method is the name of the method.
+		Method string `json:"method"`
+		// Payload summarizes the payload: it maps the names of the
+		// primitive payload attributes that do not hold credentials to
+		// their values.
+		Payload map[string]interface{} `json:"payload,omitempty"`
+	}
+
+	// Authorizer authorizes the authenticated requests made to the
+	// endpoints. Authorize returns an error if the request must be denied.
+	Authorizer interface {
+		Authorize(ctx context.Context, in *Input) error
+	}
+
+	// OPAAuthorizer is an Authorizer querying an Open Policy Agent policy
+	// through the OPA data API.
+	OPAAuthorizer struct {
+		// URL is the URL of the data API document holding the
+		// decision.
+		URL string
+		// TTL is the duration the decisions are cached for, 0 disables
+		// caching.
+		TTL time.Duration
+		// Client is the HTTP client used to query the policy.
+		Client *http.Client
+
+		mu    sync.Mutex
+		cache map[string]decision
+	}
+
+	// decision is a cached policy decision.
+	decision struct {
+		allow   bool
+		expires time.Time
+	}
+
+	// principalKey is the context key of the principal.
+	principalKey struct{}
+
+	// scopesKey is the context key of the scopes.
+	scopesKey struct{}
+)
+
+// NewOPAAuthorizer returns an authorizer querying the OPA data API document at
+// the given URL and caching the decisions for the given duration.
+func NewOPAAuthorizer(url string, ttl time.Duration) *OPAAuthorizer {
+	return &OPAAuthorizer{
+		URL:    url,
+		TTL:    ttl,
+		Client: http.DefaultClient,
+		cache:  make(map[string]decision),
+	}
+}
+
+// WithPrincipal returns a copy of ctx holding the given principal. The
+// authentication functions call WithPrincipal so that the policy is given the
+// authenticated principal, e.g. the JWT claims.
+func WithPrincipal(ctx context.Context, principal interface{}) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// ContextPrincipal returns the principal stored in ctx, nil if there is none.
+func ContextPrincipal(ctx context.Context) interface{} {
+	return ctx.Value(principalKey{})
+}
+
+// WithScopes returns a copy of ctx holding the scopes granted to the
+// principal. The authentication functions call WithScopes when the principal
+// does not carry its scopes, see ContextScopes.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// ContextScopes returns the scopes granted to the principal: the scopes stored
+// in ctx with WithScopes if any, the scopes of the principal otherwise if it
+// implements a Scopes method returning them such as Claims.
+func ContextScopes(ctx context.Context) []string {
+	if scopes, ok := ctx.Value(scopesKey{}).([]string); ok {
+		return scopes
+	}
+	if p, ok := ContextPrincipal(ctx).(interface{ Scopes() []string }); ok {
+		return p.Scopes()
+	}
+	return nil
+}
+
+// HasScopes returns true if scopes contains all the required scopes.
+func HasScopes(scopes []string, required ...string) bool {
+	for _, r := range required {
+		found := false
+		for _, s := range scopes {
+			if s == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Authorize queries the policy with the given input completed with the
+// principal stored in ctx. It returns a "forbidden" error if the policy denies
+// the request or does not define the decision and an
+// "authorization_unavailable" error if the policy cannot be queried.
+func (a *OPAAuthorizer) Authorize(ctx context.Context, in *Input) error {
+	if in.Principal == nil {
+		in.Principal = ContextPrincipal(ctx)
+	}
+	body, err := json.Marshal(map[string]interface{}{"input": in})
+	if err != nil {
+		return goa.Fault("failed to encode policy input: %s", err)
+	}
+	key := string(body)
+	allow, ok := a.cached(key)
+	if !ok {
+		if allow, err = a.query(ctx, body); err != nil {
+			return goa.TemporaryError(UnavailableErrorName, "failed to query policy: %s", err)
+		}
+		a.store(key, allow)
+	}
+	if !allow {
+		return goa.PermanentError(ForbiddenErrorName, "not authorized to call %s.%s", in.Service, in.Method)
+	}
+	return nil
+}
+
+// query posts the given input document to the data API and returns the
+// decision.
+func (a *OPAAuthorizer) query(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequest("POST", a.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c := a.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var res struct {
+		Result *bool `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return false, err
+	}
+	// An undefined decision denies the request.
+	return res.Result != nil && *res.Result, nil
+}
+
+// cached returns the cached decision for the given key if any.
+func (a *OPAAuthorizer) cached(key string) (allow, ok bool) {
+	if a.TTL <= 0 {
+		return false, false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d, ok := a.cache[key]
+	if !ok {
+		return false, false
+	}
+	if time.Now().After(d.expires) {
+		delete(a.cache, key)
+		return false, false
+	}
+	return d.allow, true
+}
+
+// store caches the given decision.
+func (a *OPAAuthorizer) store(key string, allow bool) {
+	if a.TTL <= 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.cache == nil {
+		a.cache = make(map[string]decision)
+	}
+	if len(a.cache) >= maxCacheEntries {
+		for k, d := range a.cache {
+			if now.After(d.expires) {
+				delete(a.cache, k)
+			}
+		}
+	}
+	a.cache[key] = decision{allow: allow, expires: now.Add(a.TTL)}
+}
@@ -0,0 +1,1016 @@
+package security_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/middleware"
+	goa "goa.design/goa/v3/pkg"
+	goasecurity "goa.design/goa/v3/security"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/security"
+	"goa.design/plugins/v3/security/expr"
+	"goa.design/plugins/v3/security/testdata"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSecurityGroup(t *testing.T) {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	root := plugintest.RunDSL(t, testdata.GroupDSL, expr.Root)
+	if len(expr.Root.Groups) != 2 {
+		t.Fatalf("got %d groups, expected 2", len(expr.Root.Groups))
+	}
+	cases := []struct {
+		Service, Method string
+		Schemes         string
+		Scopes          string
+	}{
+		{"billing", "charge", "jwt", "svc:internal"},
+		{"billing", "refund", "api_key", ""},
+		{"ledger", "balance", "jwt", "svc:internal"},
+	}
+	for _, c := range cases {
+		m := root.Service(c.Service).Method(c.Method)
+		if len(m.Requirements) != 1 {
+			t.Errorf("%s: got %d requirements, expected 1", c.Method, len(m.Requirements))
+			continue
+		}
+		req := m.Requirements[0]
+		var schemes []string
+		for _, s := range req.Schemes {
+			schemes = append(schemes, s.SchemeName)
+		}
+		if got := strings.Join(schemes, " "); got != c.Schemes {
+			t.Errorf("%s: got schemes %q, expected %q", c.Method, got, c.Schemes)
+		}
+		if got := strings.Join(req.Scopes, " "); got != c.Scopes {
+			t.Errorf("%s: got scopes %q, expected %q", c.Method, got, c.Scopes)
+		}
+	}
+	// The services get their own copy of the requirement.
+	billing, ledger := root.Service("billing"), root.Service("ledger")
+	if billing.Requirements[0] == ledger.Requirements[0] || billing.Requirements[0].Schemes[0] == ledger.Requirements[0].Schemes[0] {
+		t.Error("services share the group requirement, expected copies")
+	}
+}
+
+func TestSecurityGroupErrors(t *testing.T) {
+	cases := []struct {
+		Name  string
+		DSL   func()
+		Error string
+	}{
+		{"undefined-scope", testdata.UndefinedScopeDSL, `scope "svc:admin" is not defined by the security schemes of the group`},
+		{"unknown-group", testdata.UnknownGroupDSL, `security group "unknown" not found`},
+		{"opa-url", testdata.InvalidOPADSL, `invalid URL "localhost:8181", must be an absolute HTTP URL`},
+		{"opa-cache", testdata.InvalidOPADSL, `decision cache duration must not be negative`},
+		{"brute-force-attempts", testdata.InvalidBruteForceDSL, `maximum number of attempts must be positive`},
+		{"brute-force-backoff", testdata.InvalidBruteForceDSL, `backoff delay must not be negative`},
+		{"brute-force-lockout", testdata.InvalidBruteForceDSL, `lockout duration must be positive`},
+		{"propagation-url", testdata.InvalidPropagationDSL, `invalid token exchange URL "sts.example.com/token", must be an absolute HTTP URL`},
+		{"scoped-field-scope", testdata.InvalidScopedFieldDSL, `scope "hr:write" is not defined by any security scheme`},
+		{"scoped-field-required", testdata.InvalidScopedFieldDSL, `attribute "salary" with required scopes cannot be required`},
+		{"relation-user-type", testdata.InvalidRelationDSL, `user type "team#member" of relation "editor" references a relation which is not defined`},
+		{"relation-grant", testdata.InvalidRelationDSL, `relation "viewer" has no user type and is not implied by another relation`},
+		{"relation-check", testdata.InvalidRelationDSL, `relation "owner" is not defined by resource "document"`},
+		{"relation-attribute", testdata.InvalidRelationDSL, `payload attribute "id" must be required`},
+		{"webauthn-rp-id", testdata.InvalidWebAuthnDSL, `invalid relying party ID "https://example.com", must be a domain such as "example.com"`},
+		{"webauthn-https", testdata.InvalidWebAuthnDSL, `invalid origin "http://example.com", must use HTTPS`},
+		{"webauthn-domain", testdata.InvalidWebAuthnDSL, `origin "https://example.org" is not in the domain of relying party ID "example.com"`},
+		{"webauthn-path", testdata.InvalidWebAuthnDSL, `invalid origin "https://example.com/login", must be an absolute URL without path`},
+		{"second-factor-kind", testdata.InvalidSecondFactorDSL, `unsupported second factor "sms", must be "totp"`},
+		{"second-factor-public", testdata.InvalidSecondFactorDSL, `method has no security requirement`},
+		{"network-proxy", testdata.InvalidNetworkDSL, `invalid CIDR "10.0.0.1"`},
+		{"network-allow", testdata.InvalidNetworkDSL, `invalid CIDR "10.0.0.0/33"`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if err := runInvalidDSL(c.DSL); err == nil || !strings.Contains(err.Error(), c.Error) {
+				t.Errorf("got error %v, expected %q", err, c.Error)
+			}
+		})
+	}
+}
+
+func TestOPAAuthorizer(t *testing.T) {
+	var (
+		queries int
+		input   map[string]interface{}
+		result  = `{"result": true}`
+		status  = http.StatusOK
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		var body struct {
+			Input map[string]interface{} `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid policy query: %s", err)
+		}
+		input = body.Input
+		w.WriteHeader(status)
+		w.Write([]byte(result))
+	}))
+	defer srv.Close()
+
+	a := security.NewOPAAuthorizer(srv.URL, time.Minute)
+	ctx := security.WithPrincipal(context.Background(), "alice")
+	in := func() *security.Input {
+		return &security.Input{Service: "billing", Method: "charge", Payload: map[string]interface{}{"amount": 10}}
+	}
+	if err := a.Authorize(ctx, in()); err != nil {
+		t.Fatalf("got error %s, expected request to be allowed", err)
+	}
+	if input["principal"] != "alice" || input["service"] != "billing" || input["method"] != "charge" {
+		t.Errorf("got input %v", input)
+	}
+	if err := a.Authorize(ctx, in()); err != nil || queries != 1 {
+		t.Errorf("got error %v and %d queries, expected cached decision", err, queries)
+	}
+
+	// The decisions are cached per input.
+	result = `{"result": false}`
+	if err := a.Authorize(security.WithPrincipal(ctx, "bob"), in()); errorName(err) != security.ForbiddenErrorName {
+		t.Errorf("got error %v, expected forbidden error", err)
+	}
+	// An undefined decision denies the request.
+	result = `{}`
+	if err := security.NewOPAAuthorizer(srv.URL, 0).Authorize(ctx, in()); errorName(err) != security.ForbiddenErrorName {
+		t.Errorf("got error %v, expected forbidden error", err)
+	}
+	status = http.StatusInternalServerError
+	if err := security.NewOPAAuthorizer(srv.URL, 0).Authorize(ctx, in()); errorName(err) != security.UnavailableErrorName {
+		t.Errorf("got error %v, expected unavailable error", err)
+	}
+	if queries != 4 {
+		t.Errorf("got %d queries, expected 4", queries)
+	}
+}
+
+func TestOpenFGAChecker(t *testing.T) {
+	var (
+		path    string
+		tuple   map[string]string
+		allowed = true
+		status  = http.StatusOK
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		var body struct {
+			TupleKey map[string]string `json:"tuple_key"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid check request: %s", err)
+		}
+		tuple = body.TupleKey
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]bool{"allowed": allowed})
+	}))
+	defer srv.Close()
+
+	c := security.NewOpenFGAChecker(srv.URL, "store")
+	ctx := context.Background()
+	if err := security.CheckRelation(ctx, c, "user:alice", "viewer", "document:42"); err != nil {
+		t.Fatalf("got error %s, expected relation", err)
+	}
+	if path != "/stores/store/check" {
+		t.Errorf("got path %q", path)
+	}
+	if tuple["user"] != "user:alice" || tuple["relation"] != "viewer" || tuple["object"] != "document:42" {
+		t.Errorf("got tuple %v", tuple)
+	}
+	allowed = false
+	if err := security.CheckRelation(ctx, c, "user:bob", "viewer", "document:42"); errorName(err) != security.ForbiddenErrorName {
+		t.Errorf("got error %v, expected forbidden error", err)
+	}
+	status = http.StatusInternalServerError
+	if err := security.CheckRelation(ctx, c, "user:bob", "viewer", "document:42"); errorName(err) != security.UnavailableErrorName {
+		t.Errorf("got error %v, expected unavailable error", err)
+	}
+}
+
+func TestBasicAuthVerifier(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := security.ParseHtpasswd(strings.NewReader("# users\n\nalice:" + string(hash) + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := security.NewBasicAuthVerifier(users)
+	ctx, err := v.BasicAuth(context.Background(), "alice", "secret", nil)
+	if err != nil {
+		t.Fatalf("got error %s, expected valid credentials", err)
+	}
+	if p := security.ContextPrincipal(ctx); p != "alice" {
+		t.Errorf("got principal %v, expected alice", p)
+	}
+	if _, err := v.BasicAuth(context.Background(), "alice", "wrong", nil); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error for invalid password", err)
+	}
+	if _, err := v.BasicAuth(context.Background(), "bob", "secret", nil); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error for unknown user", err)
+	}
+}
+
+func TestBruteForceGuard(t *testing.T) {
+	var calls int
+	auth := func(ctx context.Context, user, pass string, _ *goasecurity.BasicScheme) (context.Context, error) {
+		calls++
+		if pass != "secret" {
+			return ctx, goa.PermanentError(security.UnauthorizedErrorName, "invalid credentials")
+		}
+		return ctx, nil
+	}
+	store := security.NewMemoryAttemptStore(time.Hour)
+	g := &security.BruteForceGuard{
+		Store:       store,
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+		Lockout:     time.Hour,
+		ClientIP:    func(context.Context) string { return "192.0.2.1" },
+	}
+	fn := g.Protect(auth)
+	ctx := context.Background()
+	if _, err := fn(ctx, "alice", "wrong", nil); errorName(err) != security.UnauthorizedErrorName {
+		t.Fatalf("got error %v, expected unauthorized error", err)
+	}
+	if _, err := fn(ctx, "alice", "secret", nil); errorName(err) != security.TooManyAttemptsErrorName {
+		t.Errorf("got error %v, expected too many attempts error during backoff", err)
+	}
+	if _, err := fn(ctx, "bob", "secret", nil); errorName(err) != security.TooManyAttemptsErrorName {
+		t.Errorf("got error %v, expected too many attempts error for same client IP", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, expected 1", calls)
+	}
+
+	a, err := store.Attempts(ctx, "user:alice")
+	if err != nil || a == nil || a.Failures != 1 {
+		t.Fatalf("got attempts %v (%v), expected 1 failure", a, err)
+	}
+	last := time.Now().Add(-90 * time.Second)
+	cases := []struct {
+		Name     string
+		Failures int
+		Wait     bool
+	}{
+		{"backoff-elapsed", 1, false},
+		{"backoff-doubled", 2, true},
+		{"lockout", 3, true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w := g.Wait(&security.Attempts{Failures: c.Failures, Last: last}, time.Now())
+			if (w > 0) != c.Wait {
+				t.Errorf("got wait %s, expected wait %v", w, c.Wait)
+			}
+		})
+	}
+	if w := g.Wait(&security.Attempts{Failures: 3, Last: time.Now().Add(-2 * time.Hour)}, time.Now()); w != 0 {
+		t.Errorf("got wait %s, expected lockout to be over", w)
+	}
+
+	if err := store.Reset(ctx, "ip:192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Reset(ctx, "user:alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fn(ctx, "alice", "secret", nil); err != nil {
+		t.Fatalf("got error %v, expected valid credentials", err)
+	}
+	if _, err := fn(ctx, "carol", "secret", nil); err != nil {
+		t.Errorf("got error %v, expected no wait after success", err)
+	}
+}
+
+func TestBruteForceGuardMaxAttempts(t *testing.T) {
+	g := &security.BruteForceGuard{Store: security.NewMemoryAttemptStore(time.Hour), Lockout: time.Hour}
+	fn := g.Protect(func(ctx context.Context, _, _ string, _ *goasecurity.BasicScheme) (context.Context, error) {
+		t.Error("got call, expected guard to reject the attempt")
+		return ctx, nil
+	})
+	_, err := fn(context.Background(), "alice", "secret", nil)
+	if serr, ok := err.(*goa.ServiceError); !ok || !serr.Fault {
+		t.Errorf("got error %v, expected fault for zero MaxAttempts", err)
+	}
+}
+
+func TestBruteForceGuardConcurrent(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	auth := func(ctx context.Context, user, pass string, _ *goasecurity.BasicScheme) (context.Context, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return ctx, goa.PermanentError(security.UnauthorizedErrorName, "invalid credentials")
+	}
+	g := &security.BruteForceGuard{
+		Store:       security.NewMemoryAttemptStore(time.Hour),
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+		Lockout:     time.Hour,
+		ClientIP:    func(context.Context) string { return "192.0.2.1" },
+	}
+	fn := g.Protect(auth)
+	ctx := context.Background()
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, err := fn(ctx, "alice", "guess", nil)
+			errs <- err
+		}()
+	}
+	// Only MaxAttempts guesses may be in progress, the others are
+	// rejected without waiting for them to fail.
+	timeout := time.After(5 * time.Second)
+	for rejected := 0; rejected < 7; rejected++ {
+		select {
+		case err := <-errs:
+			if errorName(err) != security.TooManyAttemptsErrorName {
+				t.Fatalf("got error %v, expected too many attempts error", err)
+			}
+		case <-timeout:
+			t.Fatalf("got %d rejected attempts, expected 7", rejected)
+		}
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-errs; errorName(err) != security.UnauthorizedErrorName {
+			t.Errorf("got error %v, expected unauthorized error", err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("got %d calls, expected 3", n)
+	}
+	if _, err := fn(ctx, "alice", "secret", nil); errorName(err) != security.TooManyAttemptsErrorName {
+		t.Errorf("got error %v, expected client to be locked out", err)
+	}
+}
+
+func TestTokenExchanger(t *testing.T) {
+	var exchanges int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if g := r.PostForm.Get("grant_type"); g != "urn:ietf:params:oauth:grant-type:token-exchange" {
+			t.Errorf("got grant type %q", g)
+		}
+		if a := r.PostForm.Get("audience"); a != "ledger" {
+			t.Errorf("got audience %q, expected ledger", a)
+		}
+		if id, secret, _ := r.BasicAuth(); id != "billing" || secret != "secret" {
+			t.Errorf("got client credentials %q:%q", id, secret)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("subject_token") != "incoming" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"unknown token"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"exchanged","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","token_type":"Bearer","expires_in":300}`))
+	}))
+	defer srv.Close()
+
+	e := security.NewTokenExchanger(srv.URL, "ledger")
+	e.ClientID, e.ClientSecret = "billing", "secret"
+	ctx := security.WithToken(context.Background(), "incoming")
+	for i := 0; i < 2; i++ {
+		token, err := e.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "exchanged" {
+			t.Errorf("got token %q, expected exchanged", token)
+		}
+	}
+	if exchanges != 1 {
+		t.Errorf("got %d exchanges, expected 1", exchanges)
+	}
+	if _, err := e.Token(security.WithToken(context.Background(), "other")); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("got error %v, expected invalid_grant error", err)
+	}
+	if token, err := e.Token(context.Background()); err != nil || token != "" {
+		t.Errorf("got token %q (%v), expected no token without incoming token", token, err)
+	}
+	if token, _ := (security.ForwardToken{}).Token(ctx); token != "incoming" {
+		t.Errorf("got forwarded token %q, expected incoming", token)
+	}
+}
+
+func TestParseHtpasswd(t *testing.T) {
+	cases := []struct {
+		Name, Content, Error string
+	}{
+		{"no-hash", "alice\n", "line 1: invalid entry, must be of the form user:hash"},
+		{"md5", "# users\nalice:$apr1$r31.....$HqJZimcKQFAMYayBlzkrA/\n", `line 2: user "alice": only bcrypt hashes are supported`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if _, err := security.ParseHtpasswd(strings.NewReader(c.Content)); err == nil || err.Error() != c.Error {
+				t.Errorf("got error %v, expected %q", err, c.Error)
+			}
+		})
+	}
+}
+
+func TestHtpasswdFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "htpasswd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	write := func(user string, modTime time.Time) {
+		hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(f.Name(), []byte(user+":"+string(hash)+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(f.Name(), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("alice", time.Now().Add(-time.Hour))
+	store := security.NewHtpasswdFile(f.Name())
+	if h, err := store.PasswordHash(context.Background(), "alice"); err != nil || h == nil {
+		t.Fatalf("got hash %q and error %v, expected alice hash", h, err)
+	}
+	// The file is read again when it changes.
+	write("bob", time.Now())
+	if h, err := store.PasswordHash(context.Background(), "alice"); err != nil || h != nil {
+		t.Errorf("got hash %q and error %v, expected no alice hash", h, err)
+	}
+	if h, err := store.PasswordHash(context.Background(), "bob"); err != nil || h == nil {
+		t.Errorf("got hash %q and error %v, expected bob hash", h, err)
+	}
+}
+
+func TestJWTSigner(t *testing.T) {
+	s := &security.JWTSigner{Key: []byte("key"), Issuer: "billing", TTL: time.Minute, RefreshTTL: time.Hour}
+	ts, err := s.Issue("alice", "api:read", "api:write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.ExpiresIn != 60 || ts.RefreshToken == "" {
+		t.Errorf("got tokens %+v, expected refresh token and 60s expiry", ts)
+	}
+	scheme := &goasecurity.JWTScheme{RequiredScopes: []string{"api:write"}}
+	ctx, err := s.JWTAuth(context.Background(), ts.AccessToken, scheme)
+	if err != nil {
+		t.Fatalf("got error %s, expected valid token", err)
+	}
+	c, ok := security.ContextPrincipal(ctx).(security.Claims)
+	if !ok || c.Subject() != "alice" || c["iss"] != "billing" {
+		t.Errorf("got principal %v, expected alice claims", security.ContextPrincipal(ctx))
+	}
+	cases := []struct {
+		Name   string
+		Token  string
+		Scheme *goasecurity.JWTScheme
+	}{
+		{"refresh-token", ts.RefreshToken, nil},
+		{"missing-scope", ts.AccessToken, &goasecurity.JWTScheme{RequiredScopes: []string{"api:admin"}}},
+		{"signature", ts.AccessToken[:len(ts.AccessToken)-2] + "xx", nil},
+		{"malformed", "token", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if _, err := s.JWTAuth(context.Background(), c.Token, c.Scheme); errorName(err) != security.UnauthorizedErrorName {
+				t.Errorf("got error %v, expected unauthorized error", err)
+			}
+		})
+	}
+	exp := time.Now().Add(time.Minute).Unix()
+	claims := []struct {
+		Name   string
+		Claims security.Claims
+	}{
+		{"expired", security.Claims{"sub": "alice", "iss": "billing", "exp": time.Now().Add(-time.Minute).Unix()}},
+		{"other-issuer", security.Claims{"sub": "alice", "iss": "admin", "exp": exp}},
+		{"no-issuer", security.Claims{"sub": "alice", "exp": exp}},
+		{"not-valid-yet", security.Claims{"sub": "alice", "iss": "billing", "exp": exp, "nbf": time.Now().Add(30 * time.Second).Unix()}},
+		{"invalid-nbf", security.Claims{"sub": "alice", "iss": "billing", "exp": exp, "nbf": "now"}},
+	}
+	for _, c := range claims {
+		t.Run(c.Name, func(t *testing.T) {
+			token, err := s.Sign(c.Claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Parse(token); errorName(err) != security.UnauthorizedErrorName {
+				t.Errorf("got error %v, expected unauthorized error", err)
+			}
+		})
+	}
+	valid, err := s.Sign(security.Claims{"sub": "alice", "iss": "billing", "exp": exp, "nbf": time.Now().Add(-time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Parse(valid); err != nil {
+		t.Errorf("got error %s, expected token valid since nbf", err)
+	}
+
+	refreshed, err := s.Refresh(ts.RefreshToken)
+	if err != nil {
+		t.Fatalf("got error %s, expected refreshed tokens", err)
+	}
+	c, err = s.Parse(refreshed.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(c.Scopes(), " "); c.Subject() != "alice" || got != "api:read api:write" {
+		t.Errorf("got subject %q and scopes %q, expected alice with api:read api:write", c.Subject(), got)
+	}
+	if _, err := s.Refresh(ts.AccessToken); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error for access token", err)
+	}
+}
+
+func TestWebAuthn(t *testing.T) {
+	w := security.NewWebAuthn("example.com", "Example", "https://example.com")
+	a := newAuthenticator(t, "example.com", "https://example.com")
+	ctx := context.Background()
+
+	opts, err := w.RegistrationOptions(ctx, "alice", "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.RP.ID != "example.com" || opts.User.ID != base64.RawURLEncoding.EncodeToString([]byte("alice")) {
+		t.Errorf("got relying party %q and user %q", opts.RP.ID, opts.User.ID)
+	}
+	if err := w.Register(ctx, "bob", a.create(opts.Challenge)); errorName(err) != security.InvalidCredentialErrorName {
+		t.Errorf("got error %v, expected invalid credential error for other user", err)
+	}
+	opts, err = w.RegistrationOptions(ctx, "alice", "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mismatched := a.create(opts.Challenge)
+	mismatched.(map[string]interface{})["rawId"] = base64.RawURLEncoding.EncodeToString([]byte("other-credential"))
+	if err := w.Register(ctx, "alice", mismatched); errorName(err) != security.InvalidCredentialErrorName {
+		t.Errorf("got error %v, expected invalid credential error for mismatched credential ID", err)
+	}
+	rsaKeys := []struct {
+		Name     string
+		Bits     int
+		Exponent []byte
+	}{
+		{"weak-rsa-key", 1024, []byte{1, 0, 1}},
+		{"even-rsa-exponent", 2048, []byte{1, 0, 0}},
+		{"short-rsa-exponent", 2048, []byte{3}},
+		{"long-rsa-exponent", 2048, []byte{1, 0, 0, 0, 0, 0, 0, 0, 1}},
+	}
+	for _, k := range rsaKeys {
+		t.Run(k.Name, func(t *testing.T) {
+			opts, err := w.RegistrationOptions(ctx, "alice", "Alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := rsa.GenerateKey(rand.Reader, k.Bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := newAuthenticator(t, "example.com", "https://example.com")
+			b.cose, _ = cbor.Marshal(map[int]interface{}{1: 3, 3: -257, -1: key.N.Bytes(), -2: k.Exponent})
+			if err := w.Register(ctx, "alice", b.create(opts.Challenge)); errorName(err) != security.InvalidCredentialErrorName {
+				t.Errorf("got error %v, expected invalid credential error", err)
+			}
+		})
+	}
+	opts, err = w.RegistrationOptions(ctx, "alice", "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Register(ctx, "alice", a.create(opts.Challenge)); err != nil {
+		t.Fatalf("got error %s, expected credential to be registered", err)
+	}
+	if err := w.Register(ctx, "alice", a.create(opts.Challenge)); errorName(err) != security.InvalidCredentialErrorName {
+		t.Errorf("got error %v, expected invalid credential error for reused challenge", err)
+	}
+	opts, err = w.RegistrationOptions(ctx, "alice", "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts.ExcludeCredentials) != 1 {
+		t.Errorf("got %d excluded credentials, expected 1", len(opts.ExcludeCredentials))
+	}
+
+	lopts, err := w.LoginOptions(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lopts.AllowCredentials) != 1 {
+		t.Errorf("got %d allowed credentials, expected 1", len(lopts.AllowCredentials))
+	}
+	user, err := w.Login(ctx, a.get(lopts.Challenge, 1))
+	if err != nil {
+		t.Fatalf("got error %s, expected valid assertion", err)
+	}
+	if user != "alice" {
+		t.Errorf("got user %q, expected alice", user)
+	}
+
+	cases := []struct {
+		Name       string
+		Credential func(challenge string) interface{}
+	}{
+		{"replayed-counter", func(c string) interface{} { return a.get(c, 1) }},
+		{"other-origin", func(c string) interface{} {
+			b := newAuthenticator(t, "example.com", "https://evil.com")
+			b.key, b.id = a.key, a.id
+			return b.get(c, 5)
+		}},
+		{"other-relying-party", func(c string) interface{} {
+			b := newAuthenticator(t, "evil.com", "https://example.com")
+			b.key, b.id = a.key, a.id
+			return b.get(c, 5)
+		}},
+		{"other-key", func(c string) interface{} {
+			b := newAuthenticator(t, "example.com", "https://example.com")
+			b.id = a.id
+			return b.get(c, 5)
+		}},
+		{"unknown-challenge", func(string) interface{} { return a.get("unknown", 5) }},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			opts, err := w.LoginOptions(ctx, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Login(ctx, c.Credential(opts.Challenge)); errorName(err) != security.UnauthorizedErrorName {
+				t.Errorf("got error %v, expected unauthorized error", err)
+			}
+		})
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	totp := security.NewTOTP("Billing")
+	store := totp.Store.(*security.MemoryTOTPStore)
+	ctx := context.Background()
+	key, err := totp.Enroll(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key.URI, "otpauth://totp/Billing:alice?") || !strings.Contains(key.URI, "secret="+key.Secret) {
+		t.Errorf("got URI %q", key.URI)
+	}
+	e, err := store.Enrollment(ctx, "alice")
+	if err != nil || e == nil {
+		t.Fatalf("got enrollment %v (%v)", e, err)
+	}
+	if err := totp.Verify(ctx, "alice", totp.Code(e.Secret, time.Now())); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error before confirmation", err)
+	}
+	if err := totp.Confirm(ctx, "alice", totp.Code(e.Secret, time.Now().Add(time.Hour))); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error for invalid code", err)
+	}
+	if err := totp.Confirm(ctx, "alice", totp.Code(e.Secret, time.Now().Add(-30*time.Second))); err != nil {
+		t.Fatalf("got error %s, expected enrollment to be confirmed", err)
+	}
+	if err := totp.Verify(ctx, "alice", totp.Code(e.Secret, time.Now())); err != nil {
+		t.Errorf("got error %s, expected valid code", err)
+	}
+	if err := totp.Verify(ctx, "alice", totp.Code(e.Secret, time.Now())); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error for replayed code", err)
+	}
+	if err := totp.Verify(ctx, "bob", "123456"); errorName(err) != security.UnauthorizedErrorName {
+		t.Errorf("got error %v, expected unauthorized error for unknown user", err)
+	}
+
+	// RFC 6238 test vector for 8 digits with SHA-1.
+	totp.Digits = 8
+	if code := totp.Code([]byte("12345678901234567890"), time.Unix(59, 0)); code != "94287082" {
+		t.Errorf("got code %s, expected 94287082", code)
+	}
+}
+
+func TestTOTPParams(t *testing.T) {
+	ctx := context.Background()
+	totp := security.NewTOTP("Billing")
+	totp.Digits, totp.Period = 0, 0
+	key, err := totp.Enroll(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(key.URI, "digits=6") || !strings.Contains(key.URI, "period=30") {
+		t.Errorf("got URI %q, expected default digits and period", key.URI)
+	}
+	cases := []struct {
+		Name   string
+		Digits int
+		Period time.Duration
+	}{
+		{"short-period", 6, 500 * time.Millisecond},
+		{"fractional-period", 6, 1500 * time.Millisecond},
+		{"too-few-digits", 4, 30 * time.Second},
+		{"too-many-digits", 9, 30 * time.Second},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			totp := security.NewTOTP("Billing")
+			totp.Digits, totp.Period = c.Digits, c.Period
+			if _, err := totp.Enroll(ctx, "alice"); err == nil {
+				t.Error("got no error, expected invalid configuration to fail")
+			}
+			if code := totp.Code([]byte("12345678901234567890"), time.Now()); code != "" {
+				t.Errorf("got code %q, expected none", code)
+			}
+			if err := totp.Verify(ctx, "alice", "123456"); errorName(err) != "fault" {
+				t.Errorf("got error %v, expected fault", err)
+			}
+		})
+	}
+}
+
+func TestTOTPConcurrent(t *testing.T) {
+	totp := security.NewTOTP("Billing")
+	store := totp.Store.(*security.MemoryTOTPStore)
+	ctx := context.Background()
+	if _, err := totp.Enroll(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := store.Enrollment(ctx, "alice")
+	if err := totp.Confirm(ctx, "alice", totp.Code(e.Secret, time.Now().Add(-30*time.Second))); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	totp.Store = &barrierTOTPStore{MemoryTOTPStore: store, wg: &wg}
+	code := totp.Code(e.Secret, time.Now())
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- totp.Verify(ctx, "alice", code) }()
+	}
+	var accepted int
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		switch errorName(err) {
+		case "":
+			accepted++
+		case security.UnauthorizedErrorName:
+		default:
+			t.Errorf("got error %v, expected unauthorized error", err)
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("got code accepted %d times, expected once", accepted)
+	}
+}
+
+func TestRequireSecondFactor(t *testing.T) {
+	cases := []struct {
+		Name string
+		Ctx  context.Context
+		Err  string
+	}{
+		{"none", context.Background(), security.SecondFactorRequiredErrorName},
+		{"password", security.WithPrincipal(context.Background(), security.Claims{"amr": []interface{}{"pwd"}}), security.SecondFactorRequiredErrorName},
+		{"claims", security.WithPrincipal(context.Background(), security.Claims{"amr": []interface{}{"pwd", "otp"}}), ""},
+		{"context", security.WithSecondFactor(context.Background()), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if err := security.RequireSecondFactor(c.Ctx); errorName(err) != c.Err {
+				t.Errorf("got error %v, expected %q", err, c.Err)
+			}
+		})
+	}
+}
+
+func TestNetworkGuard(t *testing.T) {
+	guard, err := security.NewNetworkGuard(map[string]*security.NetworkPolicy{
+		"health": {Allow: []string{"10.0.0.0/8", "192.168.0.0/16"}, Deny: []string{"192.168.1.0/24"}},
+		"list":   {Deny: []string{"203.0.113.0/24"}},
+	}, "10.0.0.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Name      string
+		Method    string
+		Remote    string
+		Forwarded string
+		Err       string
+	}{
+		{"allowed", "health", "10.1.2.3:4000", "", ""},
+		{"not-allowed", "health", "198.51.100.1:4000", "", security.NetworkDeniedErrorName},
+		{"denied", "health", "192.168.1.7:4000", "", security.NetworkDeniedErrorName},
+		{"deny-only", "list", "198.51.100.1:4000", "", ""},
+		{"deny-only-denied", "list", "203.0.113.9:4000", "", security.NetworkDeniedErrorName},
+		{"no-policy", "show", "203.0.113.9:4000", "", ""},
+		{"unknown", "health", "", "", security.NetworkDeniedErrorName},
+		{"proxy", "health", "10.0.0.1:4000", "192.168.2.1, 10.0.0.2", ""},
+		{"proxy-denied", "health", "10.0.0.1:4000", "192.168.1.7", security.NetworkDeniedErrorName},
+		{"proxy-spoofed", "health", "10.0.0.1:4000", "10.1.2.3, 198.51.100.1", security.NetworkDeniedErrorName},
+		{"proxy-invalid", "health", "10.0.0.1:4000", "garbage, 10.0.0.2", security.NetworkDeniedErrorName},
+		{"untrusted-forwarded", "health", "198.51.100.1:4000", "10.1.2.3", security.NetworkDeniedErrorName},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), middleware.RequestRemoteAddrKey, c.Remote)
+			ctx = context.WithValue(ctx, middleware.RequestXForwardedForKey, c.Forwarded)
+			if err := guard.Check(ctx, c.Method); errorName(err) != c.Err {
+				t.Errorf("got error %v, expected %q", err, c.Err)
+			}
+		})
+	}
+}
+
+func TestNewNetworkGuard(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Policies map[string]*security.NetworkPolicy
+		Proxies  []string
+	}{
+		{"allow", map[string]*security.NetworkPolicy{"health": {Allow: []string{"10.0.0.0/33"}}}, nil},
+		{"deny", map[string]*security.NetworkPolicy{"health": {Deny: []string{"garbage"}}}, nil},
+		{"proxy", nil, []string{"10.0.0.1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if _, err := security.NewNetworkGuard(c.Policies, c.Proxies...); err == nil {
+				t.Error("got no error, expected invalid CIDR error")
+			}
+		})
+	}
+}
+
+// errorName returns the name of the given goa service error, the empty string
+// if err is not a service error.
+func errorName(err error) string {
+	if serr, ok := err.(*goa.ServiceError); ok {
+		return serr.Name
+	}
+	return ""
+}
+
+// runInvalidDSL runs the given DSL and returns the resulting error.
+func runInvalidDSL(dsl func()) error {
+	expr.Root.Groups = nil
+	expr.Root.OPAs = nil
+	expr.Root.BasicAuths = nil
+	expr.Root.BruteForces = nil
+	expr.Root.Propagations = nil
+	expr.Root.ScopedFields = nil
+	expr.Root.Resources = nil
+	expr.Root.RelationChecks = nil
+	expr.Root.WebAuthns = nil
+	expr.Root.SecondFactors = nil
+	expr.Root.Networks = nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.GeneratedTypes = &goaexpr.GeneratedRoot{}
+	goaexpr.Root.API = goaexpr.NewAPIExpr("test api", func() {})
+	goaexpr.Root.API.Servers = []*goaexpr.ServerExpr{goaexpr.Root.API.DefaultServer()}
+	eval.Register(goaexpr.Root)
+	eval.Register(goaexpr.Root.GeneratedTypes)
+	eval.Register(expr.Root)
+	if !eval.Execute(dsl, nil) {
+		return eval.Context.Errors
+	}
+	return eval.RunDSL()
+}
+
+// barrierTOTPStore is a TOTP store whose Enrollment returns once all the
+// requests of wg read the enrollment so that they verify the same state.
+type barrierTOTPStore struct {
+	*security.MemoryTOTPStore
+	wg *sync.WaitGroup
+}
+
+// Enrollment reads the enrollment and waits for the other requests to read it.
+func (s *barrierTOTPStore) Enrollment(ctx context.Context, user string) (*security.TOTPEnrollment, error) {
+	e, err := s.MemoryTOTPStore.Enrollment(ctx, user)
+	s.wg.Done()
+	s.wg.Wait()
+	return e, err
+}
+
+// authenticator is a software WebAuthn authenticator producing the credentials
+// of a single ES256 passkey.
+type authenticator struct {
+	rpID   string
+	origin string
+	id     []byte
+	key    *ecdsa.PrivateKey
+	// cose is the COSE public key registered by create, the public key
+	// of key if nil.
+	cose []byte
+}
+
+// newAuthenticator returns an authenticator with a new key.
+func newAuthenticator(t *testing.T, rpID, origin string) *authenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authenticator{rpID: rpID, origin: origin, id: []byte("credential-" + rpID), key: key}
+}
+
+// create returns the JSON representation of the credential created for the
+// given challenge.
+func (a *authenticator) create(challenge string) interface{} {
+	coseKey := a.cose
+	if coseKey == nil {
+		coseKey, _ = cbor.Marshal(map[int]interface{}{
+			1:  2,
+			3:  -7,
+			-1: 1,
+			-2: a.key.X.Bytes(),
+			-3: a.key.Y.Bytes(),
+		})
+	}
+	authData := a.authData(0x41, 0)
+	authData = append(authData, make([]byte, 16)...)
+	authData = append(authData, byte(len(a.id)>>8), byte(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, coseKey...)
+	att, _ := cbor.Marshal(map[string]interface{}{"fmt": "none", "attStmt": map[string]interface{}{}, "authData": authData})
+	return a.credential(map[string]interface{}{
+		"clientDataJSON":    a.clientData("webauthn.create", challenge),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(att),
+	})
+}
+
+// get returns the JSON representation of the assertion made for the given
+// challenge with the given signature counter.
+func (a *authenticator) get(challenge string, count uint32) interface{} {
+	authData := a.authData(0x01, count)
+	cd := a.clientData("webauthn.get", challenge)
+	raw, _ := base64.RawURLEncoding.DecodeString(cd)
+	hash := sha256.Sum256(raw)
+	digest := sha256.Sum256(append(authData, hash[:]...))
+	r, s, _ := ecdsa.Sign(rand.Reader, a.key, digest[:])
+	sig, _ := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	return a.credential(map[string]interface{}{
+		"clientDataJSON":    cd,
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(sig),
+		"userHandle":        base64.RawURLEncoding.EncodeToString([]byte("alice")),
+	})
+}
+
+// authData returns the authenticator data with the given flags and counter.
+func (a *authenticator) authData(flags byte, count uint32) []byte {
+	hash := sha256.Sum256([]byte(a.rpID))
+	return append(hash[:], flags, byte(count>>24), byte(count>>16), byte(count>>8), byte(count))
+}
+
+// clientData returns the base64url encoded client data.
+func (a *authenticator) clientData(typ, challenge string) string {
+	b, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": a.origin})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// credential returns the decoded JSON representation of the credential with
+// the given response as received in a payload.
+func (a *authenticator) credential(resp map[string]interface{}) interface{} {
+	b, _ := json.Marshal(map[string]interface{}{
+		"id":       base64.RawURLEncoding.EncodeToString(a.id),
+		"rawId":    base64.RawURLEncoding.EncodeToString(a.id),
+		"type":     "public-key",
+		"response": resp,
+	})
+	var v interface{}
+	json.Unmarshal(b, &v)
+	return v
+}
@@ -0,0 +1,555 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	security "goa.design/plugins/v3/security/dsl"
+)
+
+var GroupDSL = func() {
+	var JWT = JWTSecurity("jwt", func() {
+		Scope("svc:internal", "Access to the internal services")
+		Scope("svc:admin", "Administration")
+	})
+	APIKeySecurity("api_key")
+	security.SecurityGroup("internal", JWT, func() {
+		Scope("svc:internal")
+	})
+	security.SecurityGroup("partner", "api_key")
+	Service("billing", func() {
+		security.UseSecurityGroup("internal")
+		Method("charge", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("amount", Int)
+			})
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+		Method("refund", func() {
+			security.UseSecurityGroup("partner")
+			Payload(func() {
+				APIKey("api_key", "key", String)
+			})
+			HTTP(func() {
+				POST("/refunds")
+				Param("key")
+			})
+		})
+	})
+	Service("ledger", func() {
+		security.UseSecurityGroup("internal")
+		Method("balance", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				GET("/balance")
+			})
+		})
+	})
+	Service("status", func() {
+		Method("check", func() {
+			HTTP(func() {
+				GET("/status")
+				GET("/healthz")
+			})
+		})
+	})
+}
+
+var UndefinedScopeDSL = func() {
+	var JWT = JWTSecurity("jwt", func() {
+		Scope("svc:internal")
+	})
+	security.SecurityGroup("admin", JWT, func() {
+		Scope("svc:admin")
+	})
+}
+
+var UnknownGroupDSL = func() {
+	Service("billing", func() {
+		security.UseSecurityGroup("unknown")
+	})
+}
+
+var OPADSL = func() {
+	var JWT = JWTSecurity("jwt", func() {
+		Scope("billing:write", "Create charges")
+	})
+	API("billing", func() {
+		security.OPA("http://localhost:8181/v1/data/billing/allow")
+	})
+	Service("billing", func() {
+		security.OPA("http://opa:8181/v1/data/billing/allow", func() {
+			security.DecisionCache(30 * time.Second)
+		})
+		Security(JWT, func() {
+			Scope("billing:write")
+		})
+		Method("charge", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("account", String, "Account charged")
+				Attribute("amount", Int)
+				Attribute("tags", ArrayOf(String))
+				Required("account")
+			})
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+		Method("status", func() {
+			NoSecurity()
+			HTTP(func() {
+				GET("/status")
+			})
+		})
+	})
+	Service("ledger", func() {
+		Security(JWT)
+		Method("balance", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/balance")
+			})
+		})
+	})
+}
+
+var InvalidOPADSL = func() {
+	API("billing", func() {
+		security.OPA("localhost:8181", func() {
+			security.DecisionCache(-time.Second)
+		})
+	})
+}
+
+var BasicAuthDSL = func() {
+	var Basic = BasicAuthSecurity("basic")
+	API("billing", func() {
+		security.HtpasswdFile("/etc/billing/users.htpasswd")
+	})
+	Service("billing", func() {
+		Security(Basic)
+		Method("charge", func() {
+			Payload(func() {
+				Username("user", String)
+				Password("pass", String)
+			})
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+	})
+	Service("status", func() {
+		Method("check", func() {
+			HTTP(func() {
+				GET("/status")
+			})
+		})
+	})
+}
+
+var BruteForceDSL = func() {
+	var Basic = BasicAuthSecurity("basic")
+	var JWT = JWTSecurity("jwt")
+	API("billing", func() {
+		security.BruteForceProtection()
+	})
+	Service("billing", func() {
+		security.BruteForceProtection(func() {
+			security.MaxAttempts(3)
+			security.Backoff(500 * time.Millisecond)
+			security.Lockout(time.Hour)
+		})
+		Method("charge", func() {
+			Security(Basic)
+			Payload(func() {
+				Username("user", String)
+				Password("pass", String)
+			})
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+		Method("refund", func() {
+			Security(JWT)
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				POST("/refunds")
+			})
+		})
+	})
+	Service("status", func() {
+		Security(JWT)
+		Method("check", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				GET("/status")
+			})
+		})
+	})
+}
+
+var InvalidBruteForceDSL = func() {
+	API("billing", func() {
+		security.BruteForceProtection(func() {
+			security.MaxAttempts(0)
+			security.Backoff(-time.Second)
+			security.Lockout(0)
+		})
+	})
+}
+
+var PropagationDSL = func() {
+	var JWT = JWTSecurity("jwt")
+	var OAuth2 = OAuth2Security("oauth2", func() {
+		ClientCredentialsFlow("https://sts.example.com/token", "")
+	})
+	API("billing", func() {
+		security.ForwardToken()
+	})
+	Service("ledger", func() {
+		security.ExchangeToken("https://sts.example.com/token", "ledger")
+		Security(JWT)
+		Method("post", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("amount", Int)
+				Required("token")
+			})
+			HTTP(func() {
+				POST("/entries")
+			})
+		})
+		Method("status", func() {
+			NoSecurity()
+			HTTP(func() {
+				GET("/status")
+			})
+		})
+	})
+	Service("billing", func() {
+		Security(OAuth2)
+		Method("charge", func() {
+			Payload(func() {
+				AccessToken("token", String)
+			})
+			Result(String)
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+	})
+}
+
+var InvalidPropagationDSL = func() {
+	API("billing", func() {
+		security.ExchangeToken("sts.example.com/token", "billing")
+	})
+}
+
+var LoginDSL = func() {
+	var Basic = BasicAuthSecurity("basic")
+	var JWT = JWTSecurity("jwt", func() {
+		Scope("api:read", "Read access")
+		Scope("api:write", "Write access")
+	})
+	API("billing", func() {})
+	Service("session", func() {
+		Method("login", func() {
+			Security(Basic)
+			Payload(func() {
+				Username("user", String)
+				Password("pass", String)
+			})
+			Result(String)
+			HTTP(func() {
+				POST("/login")
+			})
+		})
+	})
+	Service("billing", func() {
+		Security(JWT, func() {
+			Scope("api:write")
+		})
+		Method("charge", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+	})
+}
+
+var ScopedFieldsDSL = func() {
+	var JWT = JWTSecurity("jwt", func() {
+		Scope("hr:read", "Read compensation data")
+		Scope("hr:admin", "Administration")
+	})
+	var Employee = ResultType("application/vnd.employee", func() {
+		TypeName("Employee")
+		Attributes(func() {
+			Attribute("name", String)
+			Attribute("salary", Int, func() {
+				security.RequireScopes("hr:read")
+			})
+			Attribute("manager", "Employee")
+			Required("name")
+		})
+	})
+	var Team = Type("Team", func() {
+		Attribute("name", String)
+		Attribute("members", ArrayOf(Employee))
+		Attribute("lead", Employee)
+		Attribute("budget", Int, "Yearly budget", func() {
+			security.RequireScopes("hr:read", "hr:admin")
+		})
+	})
+	Service("hr", func() {
+		Security(JWT)
+		Method("show", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(Employee)
+			HTTP(func() {
+				GET("/employees/show")
+			})
+		})
+		Method("list", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(CollectionOf(Employee))
+			HTTP(func() {
+				GET("/employees")
+			})
+		})
+		Method("team", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(Team)
+			HTTP(func() {
+				GET("/team")
+			})
+		})
+		Method("count", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/count")
+			})
+		})
+	})
+}
+
+var InvalidScopedFieldDSL = func() {
+	JWTSecurity("jwt", func() {
+		Scope("hr:read")
+	})
+	Type("Employee", func() {
+		Attribute("salary", Int, func() {
+			security.RequireScopes("hr:write")
+		})
+		Required("salary")
+	})
+}
+
+var RelationDSL = func() {
+	var JWT = JWTSecurity("jwt")
+	security.Resource("group", func() {
+		security.Relation("member", "user")
+	})
+	security.Resource("document", func() {
+		security.Relation("owner", "user")
+		security.Relation("editor", "user", "group#member")
+		security.Relation("viewer", "user:*")
+		security.Implies("owner", "editor")
+		security.Implies("editor", "viewer")
+	})
+	Service("docs", func() {
+		Security(JWT)
+		Method("show", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("id", String)
+				Required("id")
+			})
+			security.RequireRelation("document", "viewer", "id")
+			HTTP(func() {
+				GET("/documents/{id}")
+			})
+		})
+		Method("archive", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("id", Int64)
+				Required("id")
+			})
+			security.RequireRelation("document", "owner", "id")
+			HTTP(func() {
+				POST("/documents/{id}/archive")
+			})
+		})
+		Method("list", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				GET("/documents")
+			})
+		})
+	})
+}
+
+var InvalidRelationDSL = func() {
+	security.Resource("document", func() {
+		security.Relation("editor", "team#member")
+		security.Relation("viewer")
+	})
+	Service("docs", func() {
+		Method("show", func() {
+			Payload(func() {
+				Attribute("id", String)
+			})
+			security.RequireRelation("document", "owner", "id")
+		})
+	})
+}
+
+var WebAuthnDSL = func() {
+	var JWT = JWTSecurity("jwt")
+	Service("users", func() {
+		Security(JWT)
+		security.WebAuthn("example.com", "https://example.com", "https://login.example.com")
+		Error("unauthorized")
+		Method("show", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				GET("/users/me")
+				Response("unauthorized", StatusUnauthorized)
+			})
+		})
+	})
+}
+
+var InvalidWebAuthnDSL = func() {
+	Service("users", func() {
+		security.WebAuthn("https://example.com")
+	})
+	Service("accounts", func() {
+		security.WebAuthn("example.com", "http://example.com", "https://example.org", "https://example.com/login")
+	})
+}
+
+var SecondFactorDSL = func() {
+	var JWT = JWTSecurity("jwt")
+	Service("billing", func() {
+		Security(JWT)
+		security.SecondFactor(security.TOTP)
+		Method("charge", func() {
+			Payload(func() {
+				Token("token", String)
+				Attribute("amount", Int)
+			})
+			HTTP(func() {
+				POST("/charges")
+			})
+		})
+		Method("status", func() {
+			NoSecurity()
+			HTTP(func() {
+				GET("/status")
+			})
+		})
+	})
+	Service("ledger", func() {
+		Security(JWT)
+		security.OPA("http://opa:8181/v1/data/ledger/allow")
+		Method("balance", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/balance")
+			})
+		})
+		Method("transfer", func() {
+			security.SecondFactor(security.TOTP)
+			Payload(func() {
+				Token("token", String)
+				Attribute("amount", Int)
+			})
+			HTTP(func() {
+				POST("/transfers")
+			})
+		})
+	})
+}
+
+var InvalidSecondFactorDSL = func() {
+	Service("billing", func() {
+		security.SecondFactor("sms")
+	})
+	Service("ledger", func() {
+		Method("status", func() {
+			security.SecondFactor(security.TOTP)
+		})
+	})
+}
+
+var NetworkDSL = func() {
+	var JWT = JWTSecurity("jwt")
+	API("billing", func() {
+		security.DenyCIDR("203.0.113.0/24")
+		security.TrustedProxy("10.0.0.0/24")
+	})
+	Service("admin", func() {
+		Security(JWT)
+		security.AllowCIDR("10.0.0.0/8")
+		Method("purge", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				DELETE("/cache")
+			})
+		})
+		Method("health", func() {
+			NoSecurity()
+			security.AllowCIDR("10.0.0.0/8", "192.168.0.0/16")
+			security.DenyCIDR("192.168.1.0/24")
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+}
+
+var InvalidNetworkDSL = func() {
+	API("billing", func() {
+		security.TrustedProxy("10.0.0.1")
+	})
+	Service("admin", func() {
+		security.AllowCIDR("10.0.0.0/33")
+	})
+}
@@ -0,0 +1,227 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenExchangeGrantType is the grant type of the token exchange
+	// requests.
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// accessTokenType is the type of the exchanged tokens.
+	accessTokenType = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+type (
+	// TokenSource provides the tokens sent by the clients to the endpoints
+	// secured with JWT or OAuth2.
+	TokenSource interface {
+		// Token returns the token to send given the context of the
+		// request being handled, the empty string if there is none.
+		Token(ctx context.Context) (string, error)
+	}
+
+	// ForwardToken is a TokenSource returning the token of the incoming
+	// request, see WithToken.
+	ForwardToken struct{}
+
+	// TokenExchanger is a TokenSource exchanging the token of the incoming
+	// request for a token issued for an audience with an OAuth 2.0 token
+	// exchange (RFC 8693) endpoint. The exchanged tokens are cached until
+	// they expire, the tokens expiring soonest are evicted first when the
+	// cache is full.
+	TokenExchanger struct {
+		// URL is the URL of the token exchange endpoint.
+		URL string
+		// Audience is the audience of the exchanged tokens if not empty.
+		Audience string
+		// Scopes lists the scopes requested for the exchanged tokens if
+		// any.
+		Scopes []string
+		// ClientID and ClientSecret are the credentials used to
+		// authenticate with the token exchange endpoint using basic auth
+		// if ClientID is not empty.
+		ClientID, ClientSecret string
+		// Client is the HTTP client used to exchange the tokens.
+		Client *http.Client
+
+		mu    sync.Mutex
+		cache map[string]exchangedToken
+	}
+
+	// exchangedToken is a cached exchanged token.
+	exchangedToken struct {
+		token   string
+		expires time.Time
+	}
+
+	// tokenKey is the context key of the incoming token.
+	tokenKey struct{}
+)
+
+// WithToken returns a copy of ctx holding the token of the incoming request.
+// The generated endpoints call WithToken before authenticating the requests
+// secured with JWT or OAuth2 when the design propagates the tokens.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// ContextToken returns the token stored in ctx, the empty string if there is
+// none.
+func ContextToken(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+// Token returns the token of the incoming request.
+func (ForwardToken) Token(ctx context.Context) (string, error) {
+	return ContextToken(ctx), nil
+}
+
+// NewTokenExchanger returns a token source exchanging the incoming tokens for
+// tokens issued for the given audience with the token exchange endpoint at the
+// given URL.
+func NewTokenExchanger(url, audience string) *TokenExchanger {
+	return &TokenExchanger{
+		URL:      url,
+		Audience: audience,
+		Client:   http.DefaultClient,
+		cache:    make(map[string]exchangedToken),
+	}
+}
+
+// Token returns the token issued in exchange for the token of the incoming
+// request, the empty string if there is no incoming token.
+func (e *TokenExchanger) Token(ctx context.Context) (string, error) {
+	subject := ContextToken(ctx)
+	if subject == "" {
+		return "", nil
+	}
+	if token, ok := e.cached(subject); ok {
+		return token, nil
+	}
+	token, expiresIn, err := e.exchange(ctx, subject)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange token: %s", err)
+	}
+	if expiresIn > 0 {
+		// Stop using the token a little before it expires so that it
+		// does not expire in flight.
+		e.store(subject, token, time.Now().Add(expiresIn-expiresIn/10))
+	}
+	return token, nil
+}
+
+// exchange posts the token exchange request and returns the issued token and
+// its lifetime.
+func (e *TokenExchanger) exchange(ctx context.Context, subject string) (string, time.Duration, error) {
+	form := url.Values{
+		"grant_type":         {tokenExchangeGrantType},
+		"subject_token":      {subject},
+		"subject_token_type": {accessTokenType},
+	}
+	if e.Audience != "" {
+		form.Set("audience", e.Audience)
+	}
+	if len(e.Scopes) > 0 {
+		form.Set("scope", strings.Join(e.Scopes, " "))
+	}
+	req, err := http.NewRequest("POST", e.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if e.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(e.ClientID), url.QueryEscape(e.ClientSecret))
+	}
+	c := e.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	var res struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil && resp.StatusCode == http.StatusOK {
+		return "", 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		if res.Error != "" {
+			return "", 0, fmt.Errorf("%s: %s", res.Error, res.ErrorDescription)
+		}
+		return "", 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if res.AccessToken == "" {
+		return "", 0, fmt.Errorf("response has no access token")
+	}
+	return res.AccessToken, time.Duration(res.ExpiresIn) * time.Second, nil
+}
+
+// cached returns the cached token exchanged for the given subject token if
+// any.
+func (e *TokenExchanger) cached(subject string) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	t, ok := e.cache[subject]
+	if !ok {
+		return "", false
+	}
+	if !time.Now().Before(t.expires) {
+		delete(e.cache, subject)
+		return "", false
+	}
+	return t.token, true
+}
+
+// store caches the token exchanged for the given subject token.
+func (e *TokenExchanger) store(subject, token string, expires time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	if e.cache == nil {
+		e.cache = make(map[string]exchangedToken)
+	}
+	if len(e.cache) >= maxCacheEntries {
+		e.prune(now)
+	}
+	e.cache[subject] = exchangedToken{token: token, expires: expires}
+}
+
+// prune removes the expired tokens from the cache. If the cache is still full
+// it also removes the tokens that expire soonest so that a quarter of the
+// cache is free.
+func (e *TokenExchanger) prune(now time.Time) {
+	for k, t := range e.cache {
+		if !now.Before(t.expires) {
+			delete(e.cache, k)
+		}
+	}
+	if len(e.cache) < maxCacheEntries {
+		return
+	}
+	subjects := make([]string, 0, len(e.cache))
+	for k := range e.cache {
+		subjects = append(subjects, k)
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		return e.cache[subjects[i]].expires.Before(e.cache[subjects[j]].expires)
+	})
+	for _, k := range subjects[:len(subjects)-maxCacheEntries*3/4] {
+		delete(e.cache, k)
+	}
+}
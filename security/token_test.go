@@ -0,0 +1,28 @@
+package security
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTokenExchangerCacheLimit(t *testing.T) {
+	e := NewTokenExchanger("https://auth.example.com/token", "ledger")
+	now := time.Now()
+	for i := 0; i < maxCacheEntries; i++ {
+		e.store(fmt.Sprintf("subject-%d", i), "token", now.Add(time.Duration(i+1)*time.Second))
+	}
+	e.store("new", "token", now.Add(time.Hour))
+	if n := len(e.cache); n > maxCacheEntries*3/4+1 {
+		t.Errorf("got %d cached tokens, expected at most %d", n, maxCacheEntries*3/4+1)
+	}
+	if _, ok := e.cached("new"); !ok {
+		t.Error("got no cached token, expected new token to be cached")
+	}
+	if _, ok := e.cached("subject-0"); ok {
+		t.Error("got cached token, expected token expiring soonest to be evicted")
+	}
+	if _, ok := e.cached(fmt.Sprintf("subject-%d", maxCacheEntries-1)); !ok {
+		t.Error("got no cached token, expected token expiring last to be kept")
+	}
+}
@@ -0,0 +1,317 @@
+package security
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/plugins/v3/security/expr"
+)
+
+// SecondFactorRequiredErrorName is the name of the error returned by the
+// endpoints requiring a second factor when the principal has not verified it.
+// The SecondFactor DSL maps it to the 401 Unauthorized status.
+const SecondFactorRequiredErrorName = expr.SecondFactorRequiredErrorName
+
+type (
+	// TOTPEnrollment is the enrollment of a user in the TOTP second
+	// factor.
+	TOTPEnrollment struct {
+		// Secret is the shared secret.
+		Secret []byte
+		// Confirmed is true once the user has proven that the
+		// authenticator app generates valid codes.
+		Confirmed bool
+		// LastStep is the time step of the last accepted code. The codes
+		// of this step and of the previous steps are rejected to
+		// prevent replays.
+		LastStep int64
+	}
+
+	// TOTPStore records the TOTP enrollments of the users.
+	TOTPStore interface {
+		// Enrollment returns the enrollment of the user with the given
+		// identifier, nil if the user is not enrolled.
+		Enrollment(ctx context.Context, user string) (*TOTPEnrollment, error)
+		// SaveEnrollment records the enrollment of the user with the
+		// given identifier.
+		SaveEnrollment(ctx context.Context, user string, e *TOTPEnrollment) error
+		// UseStep records that a code of the given time step was
+		// accepted for the user with the given identifier and confirms
+		// the enrollment. It returns false without recording the step
+		// if the LastStep of the enrollment is already at or after it.
+		// The comparison and the update must be atomic so that
+		// concurrent requests cannot use the same code twice.
+		UseStep(ctx context.Context, user string, step int64) (bool, error)
+	}
+
+	// MemoryTOTPStore is a TOTPStore keeping the enrollments in memory.
+	// The enrollments are lost when the service stops.
+	MemoryTOTPStore struct {
+		mu          sync.Mutex
+		enrollments map[string]TOTPEnrollment
+	}
+
+	// TOTP enrolls the users in the time-based one-time password second
+	// factor defined by RFC 6238 and verifies their codes. The codes are
+	// computed with HMAC-SHA1 as expected by the authenticator apps.
+	TOTP struct {
+		// Issuer is the name of the service displayed by the
+		// authenticator apps.
+		Issuer string
+		// Digits is the number of digits of the codes, between 6 and 8.
+		// The codes have 6 digits if zero.
+		Digits int
+		// Period is the duration of a time step, a whole number of
+		// seconds. The time steps last 30 seconds if zero.
+		Period time.Duration
+		// Skew is the number of time steps before and after the current
+		// one whose codes are accepted to tolerate clock drift.
+		Skew int
+		// Store records the enrollments.
+		Store TOTPStore
+	}
+
+	// TOTPKey is the key given to the authenticator app of a user.
+	TOTPKey struct {
+		// Secret is the base32 encoded shared secret the user may type
+		// in the authenticator app.
+		Secret string `json:"secret"`
+		// URI is the otpauth URI of the key, typically rendered as a QR
+		// code.
+		URI string `json:"uri"`
+	}
+
+	// secondFactorKey is the context key of the second factor flag.
+	secondFactorKey struct{}
+)
+
+// NewTOTP returns a TOTP second factor with the given issuer name generating
+// 6-digit codes every 30 seconds and keeping the enrollments in memory. The
+// codes of the previous and next time steps are accepted.
+func NewTOTP(issuer string) *TOTP {
+	return &TOTP{
+		Issuer: issuer,
+		Digits: 6,
+		Period: 30 * time.Second,
+		Skew:   1,
+		Store:  NewMemoryTOTPStore(),
+	}
+}
+
+// NewMemoryTOTPStore returns an empty TOTP store.
+func NewMemoryTOTPStore() *MemoryTOTPStore {
+	return &MemoryTOTPStore{enrollments: make(map[string]TOTPEnrollment)}
+}
+
+// Enrollment returns the enrollment of the given user.
+func (s *MemoryTOTPStore) Enrollment(_ context.Context, user string) (*TOTPEnrollment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.enrollments[user]
+	if !ok {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// SaveEnrollment records the enrollment of the given user.
+func (s *MemoryTOTPStore) SaveEnrollment(_ context.Context, user string, e *TOTPEnrollment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.enrollments == nil {
+		s.enrollments = make(map[string]TOTPEnrollment)
+	}
+	s.enrollments[user] = *e
+	return nil
+}
+
+// UseStep records the given time step in the enrollment of the given user
+// unless the enrollment recorded the same or a later step.
+func (s *MemoryTOTPStore) UseStep(_ context.Context, user string, step int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.enrollments[user]
+	if !ok || e.LastStep >= step {
+		return false, nil
+	}
+	e.LastStep = step
+	e.Confirmed = true
+	s.enrollments[user] = e
+	return true, nil
+}
+
+// Enroll generates a new secret for the user with the given identifier and
+// returns the key to add to the authenticator app. The enrollment must be
+// confirmed with Confirm before the codes are accepted by Verify. Enrolling
+// again replaces the secret.
+func (t *TOTP) Enroll(ctx context.Context, user string) (*TOTPKey, error) {
+	if t.Store == nil {
+		return nil, goa.Fault("TOTP has no store")
+	}
+	digits, period, err := t.params()
+	if err != nil {
+		return nil, goa.Fault("%s", err)
+	}
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, goa.Fault("failed to generate secret: %s", err)
+	}
+	if err := t.Store.SaveEnrollment(ctx, user, &TOTPEnrollment{Secret: secret}); err != nil {
+		return nil, goa.Fault("failed to record enrollment: %s", err)
+	}
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+	q := url.Values{}
+	q.Set("secret", encoded)
+	q.Set("issuer", t.Issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(digits))
+	q.Set("period", fmt.Sprint(period))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + t.Issuer + ":" + user,
+		RawQuery: q.Encode(),
+	}
+	return &TOTPKey{Secret: encoded, URI: u.String()}, nil
+}
+
+// Confirm verifies the code generated by the authenticator app of the user
+// with the given identifier after Enroll and confirms the enrollment. It
+// returns an "unauthorized" error if the user is not enrolled or if the code is
+// invalid.
+func (t *TOTP) Confirm(ctx context.Context, user, code string) error {
+	return t.verify(ctx, user, code, true)
+}
+
+// Verify verifies the code of the user with the given identifier. It returns
+// an "unauthorized" error if the user has no confirmed enrollment, if the code
+// is invalid or if it was already used. The authentication functions or the
+// login method call WithSecondFactor once the code is verified.
+func (t *TOTP) Verify(ctx context.Context, user, code string) error {
+	return t.verify(ctx, user, code, false)
+}
+
+// Code returns the code generated at the given time with the given secret, the
+// empty string if Digits or Period is invalid.
+func (t *TOTP) Code(secret []byte, at time.Time) string {
+	digits, period, err := t.params()
+	if err != nil {
+		return ""
+	}
+	return totpCode(secret, at.Unix()/period, digits)
+}
+
+// verify verifies the code of the given user and records its time step. The
+// enrollment is confirmed if confirm is true and must be confirmed otherwise.
+func (t *TOTP) verify(ctx context.Context, user, code string, confirm bool) error {
+	if t.Store == nil {
+		return goa.Fault("TOTP has no store")
+	}
+	digits, period, err := t.params()
+	if err != nil {
+		return goa.Fault("%s", err)
+	}
+	e, err := t.Store.Enrollment(ctx, user)
+	if err != nil {
+		return goa.Fault("failed to retrieve enrollment: %s", err)
+	}
+	if e == nil || (!confirm && !e.Confirmed) {
+		return goa.PermanentError(UnauthorizedErrorName, "user is not enrolled in TOTP")
+	}
+	now := time.Now().Unix() / period
+	step := int64(-1)
+	for i := -t.Skew; i <= t.Skew; i++ {
+		s := now + int64(i)
+		if s > e.LastStep && subtle.ConstantTimeCompare([]byte(totpCode(e.Secret, s, digits)), []byte(code)) == 1 {
+			step = s
+			break
+		}
+	}
+	if step < 0 {
+		return goa.PermanentError(UnauthorizedErrorName, "invalid TOTP code")
+	}
+	ok, err := t.Store.UseStep(ctx, user, step)
+	if err != nil {
+		return goa.Fault("failed to record enrollment: %s", err)
+	}
+	if !ok {
+		return goa.PermanentError(UnauthorizedErrorName, "TOTP code was already used")
+	}
+	return nil
+}
+
+// params returns the number of digits of the codes and the duration of the
+// time steps in seconds.
+func (t *TOTP) params() (int, int64, error) {
+	digits, period := t.Digits, t.Period
+	if digits == 0 {
+		digits = 6
+	}
+	if period == 0 {
+		period = 30 * time.Second
+	}
+	if digits < 6 || digits > 8 {
+		return 0, 0, fmt.Errorf("TOTP codes must have 6 to 8 digits, got %d", digits)
+	}
+	if period < time.Second || period%time.Second != 0 {
+		return 0, 0, fmt.Errorf("TOTP period must be a whole number of seconds, got %s", period)
+	}
+	return digits, int64(period / time.Second), nil
+}
+
+// totpCode returns the code of the given time step with the given number of
+// digits as defined by RFC 4226.
+func totpCode(secret []byte, step int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, v%mod)
+}
+
+// WithSecondFactor returns a copy of ctx recording that the principal has
+// verified a second factor. The authentication functions call it when the
+// credentials prove it, e.g. a JWT issued once the TOTP code was verified.
+func WithSecondFactor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, secondFactorKey{}, true)
+}
+
+// ContextSecondFactor returns true if the principal has verified a second
+// factor: if WithSecondFactor was called or if the principal implements a
+// SecondFactor method returning true such as Claims.
+func ContextSecondFactor(ctx context.Context) bool {
+	if ok, _ := ctx.Value(secondFactorKey{}).(bool); ok {
+		return true
+	}
+	if p, ok := ContextPrincipal(ctx).(interface{ SecondFactor() bool }); ok {
+		return p.SecondFactor()
+	}
+	return false
+}
+
+// RequireSecondFactor returns a "second_factor_required" error if the principal
+// stored in ctx has not verified a second factor. The endpoints of the methods
+// requiring a second factor call it once the request is authenticated.
+func RequireSecondFactor(ctx context.Context) error {
+	if !ContextSecondFactor(ctx) {
+		return goa.PermanentError(SecondFactorRequiredErrorName, "second factor required")
+	}
+	return nil
+}
@@ -0,0 +1,545 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// InvalidCredentialErrorName is the name of the error returned when a passkey
+// cannot be registered. Map it to the 400 Bad Request status with the goa Error
+// and Response DSL functions.
+const InvalidCredentialErrorName = "invalid_credential"
+
+// minRSAKeyBits is the minimum size of the modulus of the RS256 credential
+// public keys.
+const minRSAKeyBits = 2048
+
+type (
+	// WebAuthnCredential is a passkey registered by a user.
+	WebAuthnCredential struct {
+		// ID is the credential identifier.
+		ID []byte
+		// UserID is the identifier of the user.
+		UserID string
+		// PublicKey is the COSE encoded public key of the credential.
+		PublicKey []byte
+		// SignCount is the last signature counter reported by the
+		// authenticator.
+		SignCount uint32
+	}
+
+	// WebAuthnChallengeStore records the challenges sent to the clients
+	// until they are used.
+	WebAuthnChallengeStore interface {
+		// Save records the challenge issued to the user with the given
+		// identifier, the empty string if the user is unknown. The
+		// challenge expires after ttl.
+		Save(ctx context.Context, challenge, userID string, ttl time.Duration) error
+		// Take deletes the given challenge and returns the identifier
+		// of the user it was issued to. ok is false if the challenge is
+		// unknown or expired.
+		Take(ctx context.Context, challenge string) (userID string, ok bool, err error)
+	}
+
+	// WebAuthnCredentialStore records the passkeys of the users.
+	WebAuthnCredentialStore interface {
+		// AddCredential records a new credential.
+		AddCredential(ctx context.Context, c *WebAuthnCredential) error
+		// Credentials returns the credentials of the user with the
+		// given identifier.
+		Credentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error)
+		// Credential returns the credential with the given identifier,
+		// nil if there is none.
+		Credential(ctx context.Context, id []byte) (*WebAuthnCredential, error)
+		// UpdateSignCount records the signature counter of the
+		// credential with the given identifier.
+		UpdateSignCount(ctx context.Context, id []byte, count uint32) error
+	}
+
+	// MemoryChallengeStore is a WebAuthnChallengeStore keeping the
+	// challenges in memory. It is not shared between the instances of a
+	// service.
+	MemoryChallengeStore struct {
+		mu         sync.Mutex
+		challenges map[string]memoryChallenge
+	}
+
+	// MemoryCredentialStore is a WebAuthnCredentialStore keeping the
+	// credentials in memory. The credentials are lost when the service
+	// stops.
+	MemoryCredentialStore struct {
+		mu          sync.Mutex
+		credentials []*WebAuthnCredential
+	}
+
+	// WebAuthn implements the registration and authentication ceremonies
+	// of passkeys with a relying party. The attestation statements are
+	// verified but not checked against trust anchors so the authenticators
+	// are not authenticated, the ES256 and RS256 algorithms are supported.
+	WebAuthn struct {
+		// RPID is the relying party identifier, e.g. "example.com".
+		RPID string
+		// RPName is the relying party name displayed by the
+		// authenticators.
+		RPName string
+		// Origins lists the origins of the web applications, e.g.
+		// "https://example.com".
+		Origins []string
+		// Timeout is the time the users have to complete a ceremony.
+		Timeout time.Duration
+		// Challenges records the challenges.
+		Challenges WebAuthnChallengeStore
+		// Credentials records the credentials.
+		Credentials WebAuthnCredentialStore
+	}
+
+	// WebAuthnCreationOptions is the JSON representation of the options
+	// given to navigator.credentials.create, the buffers are base64url
+	// encoded.
+	WebAuthnCreationOptions struct {
+		Challenge              string                          `json:"challenge"`
+		RP                     *WebAuthnEntity                 `json:"rp"`
+		User                   *WebAuthnEntity                 `json:"user"`
+		PubKeyCredParams       []*WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+		Timeout                int64                           `json:"timeout"`
+		ExcludeCredentials     []*WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+		Attestation            string                          `json:"attestation"`
+		AuthenticatorSelection map[string]string               `json:"authenticatorSelection"`
+	}
+
+	// WebAuthnRequestOptions is the JSON representation of the options
+	// given to navigator.credentials.get, the buffers are base64url
+	// encoded.
+	WebAuthnRequestOptions struct {
+		Challenge        string                          `json:"challenge"`
+		RPID             string                          `json:"rpId"`
+		Timeout          int64                           `json:"timeout"`
+		AllowCredentials []*WebAuthnCredentialDescriptor `json:"allowCredentials"`
+		UserVerification string                          `json:"userVerification"`
+	}
+
+	// WebAuthnEntity describes the relying party or the user.
+	WebAuthnEntity struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName,omitempty"`
+	}
+
+	// WebAuthnCredentialParameter is an accepted credential algorithm.
+	WebAuthnCredentialParameter struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	}
+
+	// WebAuthnCredentialDescriptor identifies a credential.
+	WebAuthnCredentialDescriptor struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+
+	// memoryChallenge is a challenge recorded by MemoryChallengeStore.
+	memoryChallenge struct {
+		userID  string
+		expires time.Time
+	}
+)
+
+// NewWebAuthn returns the ceremonies of the relying party with the given
+// identifier, name and origins. The challenges and credentials are kept in
+// memory and the users have 5 minutes to complete a ceremony.
+func NewWebAuthn(rpID, rpName string, origins ...string) *WebAuthn {
+	return &WebAuthn{
+		RPID:        rpID,
+		RPName:      rpName,
+		Origins:     origins,
+		Timeout:     5 * time.Minute,
+		Challenges:  NewMemoryChallengeStore(),
+		Credentials: NewMemoryCredentialStore(),
+	}
+}
+
+// NewMemoryChallengeStore returns an empty challenge store.
+func NewMemoryChallengeStore() *MemoryChallengeStore {
+	return &MemoryChallengeStore{challenges: make(map[string]memoryChallenge)}
+}
+
+// Save records the given challenge.
+func (s *MemoryChallengeStore) Save(_ context.Context, challenge, userID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.challenges == nil {
+		s.challenges = make(map[string]memoryChallenge)
+	}
+	now := time.Now()
+	if len(s.challenges) >= maxCacheEntries {
+		for k, c := range s.challenges {
+			if !now.Before(c.expires) {
+				delete(s.challenges, k)
+			}
+		}
+	}
+	s.challenges[challenge] = memoryChallenge{userID: userID, expires: now.Add(ttl)}
+	return nil
+}
+
+// Take deletes the given challenge and returns the user it was issued to.
+func (s *MemoryChallengeStore) Take(_ context.Context, challenge string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.challenges[challenge]
+	if !ok {
+		return "", false, nil
+	}
+	delete(s.challenges, challenge)
+	if !time.Now().Before(c.expires) {
+		return "", false, nil
+	}
+	return c.userID, true, nil
+}
+
+// NewMemoryCredentialStore returns an empty credential store.
+func NewMemoryCredentialStore() *MemoryCredentialStore {
+	return &MemoryCredentialStore{}
+}
+
+// AddCredential records the given credential.
+func (s *MemoryCredentialStore) AddCredential(_ context.Context, c *WebAuthnCredential) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *c
+	s.credentials = append(s.credentials, &cp)
+	return nil
+}
+
+// Credentials returns the credentials of the given user.
+func (s *MemoryCredentialStore) Credentials(_ context.Context, userID string) ([]*WebAuthnCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var creds []*WebAuthnCredential
+	for _, c := range s.credentials {
+		if c.UserID == userID {
+			cp := *c
+			creds = append(creds, &cp)
+		}
+	}
+	return creds, nil
+}
+
+// Credential returns the credential with the given identifier.
+func (s *MemoryCredentialStore) Credential(_ context.Context, id []byte) (*WebAuthnCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.credentials {
+		if bytes.Equal(c.ID, id) {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateSignCount records the signature counter of the given credential.
+func (s *MemoryCredentialStore) UpdateSignCount(_ context.Context, id []byte, count uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.credentials {
+		if bytes.Equal(c.ID, id) {
+			c.SignCount = count
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown credential %s", base64.RawURLEncoding.EncodeToString(id))
+}
+
+// RegistrationOptions returns the options of the creation of a passkey by the
+// user with the given identifier and name. The credentials already registered
+// by the user are excluded.
+func (w *WebAuthn) RegistrationOptions(ctx context.Context, userID, userName string) (*WebAuthnCreationOptions, error) {
+	challenge, err := w.challenge(ctx, "create", userID)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := w.Credentials.Credentials(ctx, userID)
+	if err != nil {
+		return nil, goa.Fault("failed to retrieve credentials: %s", err)
+	}
+	return &WebAuthnCreationOptions{
+		Challenge: challenge,
+		RP:        &WebAuthnEntity{ID: w.RPID, Name: w.RPName},
+		User: &WebAuthnEntity{
+			ID:          base64.RawURLEncoding.EncodeToString([]byte(userID)),
+			Name:        userName,
+			DisplayName: userName,
+		},
+		PubKeyCredParams: []*WebAuthnCredentialParameter{
+			{Type: "public-key", Alg: int(webauthncose.AlgES256)},
+			{Type: "public-key", Alg: int(webauthncose.AlgRS256)},
+		},
+		Timeout:                int64(w.Timeout / time.Millisecond),
+		ExcludeCredentials:     descriptors(creds),
+		Attestation:            "none",
+		AuthenticatorSelection: map[string]string{"residentKey": "preferred", "userVerification": "preferred"},
+	}, nil
+}
+
+// Register verifies the credential created by navigator.credentials.create
+// with the options returned by RegistrationOptions for the user with the given
+// identifier and records it. credential is the decoded JSON representation of
+// the PublicKeyCredential. Register returns an "invalid_credential" error if
+// the credential cannot be registered.
+func (w *WebAuthn) Register(ctx context.Context, userID string, credential interface{}) error {
+	b, err := w.credentialJSON(credential)
+	if err != nil {
+		return err
+	}
+	cred, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(b))
+	if err != nil {
+		return invalidCredential(protocolError(err))
+	}
+	challenge := cred.Response.CollectedClientData.Challenge
+	owner, err := w.take(ctx, "create", challenge)
+	if err != nil {
+		return invalidCredential(err)
+	}
+	if owner != userID {
+		return goa.PermanentError(InvalidCredentialErrorName, "challenge was issued to another user")
+	}
+	if err := cred.Verify(challenge, false, w.RPID, w.Origins); err != nil {
+		return invalidCredential(protocolError(err))
+	}
+	ad := cred.Response.AttestationObject.AuthData
+	if !ad.Flags.HasAttestedCredentialData() {
+		return goa.PermanentError(InvalidCredentialErrorName, "missing attested credential data")
+	}
+	if !bytes.Equal(cred.RawID, ad.AttData.CredentialID) {
+		return goa.PermanentError(InvalidCredentialErrorName, "credential ID does not match attested credential data")
+	}
+	if err := checkPublicKey(ad.AttData.CredentialPublicKey); err != nil {
+		return invalidCredential(err)
+	}
+	existing, err := w.Credentials.Credential(ctx, ad.AttData.CredentialID)
+	if err != nil {
+		return goa.Fault("failed to retrieve credential: %s", err)
+	}
+	if existing != nil {
+		return goa.PermanentError(InvalidCredentialErrorName, "credential is already registered")
+	}
+	c := &WebAuthnCredential{
+		ID:        ad.AttData.CredentialID,
+		UserID:    userID,
+		PublicKey: ad.AttData.CredentialPublicKey,
+		SignCount: ad.Counter,
+	}
+	if err := w.Credentials.AddCredential(ctx, c); err != nil {
+		return goa.Fault("failed to record credential: %s", err)
+	}
+	return nil
+}
+
+// LoginOptions returns the options of the authentication of the user with the
+// given identifier. The options allow the credentials of the user only unless
+// userID is empty in which case the authenticator lets the user pick one of
+// the discoverable credentials of the relying party.
+func (w *WebAuthn) LoginOptions(ctx context.Context, userID string) (*WebAuthnRequestOptions, error) {
+	challenge, err := w.challenge(ctx, "get", userID)
+	if err != nil {
+		return nil, err
+	}
+	opts := &WebAuthnRequestOptions{
+		Challenge:        challenge,
+		RPID:             w.RPID,
+		Timeout:          int64(w.Timeout / time.Millisecond),
+		AllowCredentials: []*WebAuthnCredentialDescriptor{},
+		UserVerification: "preferred",
+	}
+	if userID != "" {
+		creds, err := w.Credentials.Credentials(ctx, userID)
+		if err != nil {
+			return nil, goa.Fault("failed to retrieve credentials: %s", err)
+		}
+		opts.AllowCredentials = descriptors(creds)
+	}
+	return opts, nil
+}
+
+// Login verifies the assertion returned by navigator.credentials.get with the
+// options returned by LoginOptions and returns the identifier of the user
+// owning the credential. credential is the decoded JSON representation of the
+// PublicKeyCredential. Login returns an "unauthorized" error if the assertion
+// is invalid or if the signature counter shows that the authenticator may have
+// been cloned.
+func (w *WebAuthn) Login(ctx context.Context, credential interface{}) (string, error) {
+	b, err := w.credentialJSON(credential)
+	if err != nil {
+		return "", err
+	}
+	cred, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(b))
+	if err != nil {
+		return "", unauthorized(protocolError(err))
+	}
+	challenge := cred.Response.CollectedClientData.Challenge
+	owner, err := w.take(ctx, "get", challenge)
+	if err != nil {
+		return "", unauthorized(err)
+	}
+	c, err := w.Credentials.Credential(ctx, cred.RawID)
+	if err != nil {
+		return "", goa.Fault("failed to retrieve credential: %s", err)
+	}
+	if c == nil {
+		return "", goa.PermanentError(UnauthorizedErrorName, "unknown credential")
+	}
+	if owner != "" && owner != c.UserID {
+		return "", goa.PermanentError(UnauthorizedErrorName, "challenge was issued to another user")
+	}
+	if len(cred.Response.UserHandle) > 0 && string(cred.Response.UserHandle) != c.UserID {
+		return "", goa.PermanentError(UnauthorizedErrorName, "user handle does not match credential")
+	}
+	if err := cred.Verify(challenge, w.RPID, w.Origins, "", false, c.PublicKey); err != nil {
+		return "", unauthorized(protocolError(err))
+	}
+	count := cred.Response.AuthenticatorData.Counter
+	if count > 0 || c.SignCount > 0 {
+		if count <= c.SignCount {
+			return "", goa.PermanentError(UnauthorizedErrorName, "signature counter did not increase, the authenticator may be cloned")
+		}
+		if err := w.Credentials.UpdateSignCount(ctx, c.ID, count); err != nil {
+			return "", goa.Fault("failed to record signature counter: %s", err)
+		}
+	}
+	return c.UserID, nil
+}
+
+// challenge generates and records a challenge of the ceremony of the given
+// type issued to the given user.
+func (w *WebAuthn) challenge(ctx context.Context, ceremony, userID string) (string, error) {
+	if w.Challenges == nil || w.Credentials == nil {
+		return "", goa.Fault("WebAuthn has no challenge or credential store")
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", goa.Fault("failed to generate challenge: %s", err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(b)
+	if err := w.Challenges.Save(ctx, ceremony+":"+challenge, userID, w.Timeout); err != nil {
+		return "", goa.Fault("failed to record challenge: %s", err)
+	}
+	return challenge, nil
+}
+
+// credentialJSON returns the JSON representation of the given credential.
+func (w *WebAuthn) credentialJSON(credential interface{}) ([]byte, error) {
+	if w.Challenges == nil || w.Credentials == nil {
+		return nil, goa.Fault("WebAuthn has no challenge or credential store")
+	}
+	switch v := credential.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	b, err := json.Marshal(credential)
+	if err != nil {
+		return nil, goa.Fault("failed to encode credential: %s", err)
+	}
+	return b, nil
+}
+
+// take consumes the given challenge of the ceremony of the given type and
+// returns the identifier of the user it was issued to.
+func (w *WebAuthn) take(ctx context.Context, ceremony, challenge string) (string, error) {
+	userID, ok, err := w.Challenges.Take(ctx, ceremony+":"+challenge)
+	if err != nil {
+		return "", goa.Fault("failed to retrieve challenge: %s", err)
+	}
+	if !ok {
+		return "", errors.New("unknown or expired challenge")
+	}
+	return userID, nil
+}
+
+// checkPublicKey verifies that the given COSE key is a ES256 key on the P-256
+// curve or a RS256 key of at least 2048 bits with a valid public exponent.
+func checkPublicKey(b []byte) error {
+	key, err := webauthncose.ParsePublicKey(b)
+	if err != nil {
+		return fmt.Errorf("invalid credential public key: %s", err)
+	}
+	switch k := key.(type) {
+	case webauthncose.EC2PublicKeyData:
+		if k.Algorithm != int64(webauthncose.AlgES256) || webauthncose.COSEEllipticCurve(k.Curve) != webauthncose.P256 {
+			return fmt.Errorf("unsupported credential algorithm %d", k.Algorithm)
+		}
+		if !elliptic.P256().IsOnCurve(new(big.Int).SetBytes(k.XCoord), new(big.Int).SetBytes(k.YCoord)) {
+			return errors.New("invalid ES256 credential public key")
+		}
+	case webauthncose.RSAPublicKeyData:
+		if k.Algorithm != int64(webauthncose.AlgRS256) {
+			return fmt.Errorf("unsupported credential algorithm %d", k.Algorithm)
+		}
+		if n := new(big.Int).SetBytes(k.Modulus).BitLen(); n < minRSAKeyBits {
+			return fmt.Errorf("RS256 credential public key has %d bits, at least %d are required", n, minRSAKeyBits)
+		}
+		// The signatures are verified with the exponent read from 3
+		// bytes.
+		if len(k.Exponent) != 3 {
+			return errors.New("invalid RS256 credential public key exponent")
+		}
+		e := int(k.Exponent[0])<<16 | int(k.Exponent[1])<<8 | int(k.Exponent[2])
+		if e < 3 || e%2 == 0 {
+			return errors.New("invalid RS256 credential public key exponent")
+		}
+	default:
+		return errors.New("unsupported credential public key")
+	}
+	return nil
+}
+
+// descriptors returns the descriptors of the given credentials.
+func descriptors(creds []*WebAuthnCredential) []*WebAuthnCredentialDescriptor {
+	ds := make([]*WebAuthnCredentialDescriptor, len(creds))
+	for i, c := range creds {
+		ds[i] = &WebAuthnCredentialDescriptor{Type: "public-key", ID: base64.RawURLEncoding.EncodeToString(c.ID)}
+	}
+	return ds
+}
+
+// protocolError returns the given WebAuthn protocol error with its debug
+// information.
+func protocolError(err error) error {
+	if perr, ok := err.(*protocol.Error); ok && perr.DevInfo != "" {
+		return fmt.Errorf("%s: %s", perr.Details, perr.DevInfo)
+	}
+	return err
+}
+
+// invalidCredential returns err as an "invalid_credential" error unless it
+// reports a server fault.
+func invalidCredential(err error) error {
+	if _, ok := err.(*goa.ServiceError); ok {
+		return err
+	}
+	return goa.PermanentError(InvalidCredentialErrorName, "%s", err)
+}
+
+// unauthorized returns err as an "unauthorized" error unless it reports a
+// server fault.
+func unauthorized(err error) error {
+	if _, ok := err.(*goa.ServiceError); ok {
+		return err
+	}
+	return goa.PermanentError(UnauthorizedErrorName, "%s", err)
+}
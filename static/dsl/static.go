@@ -0,0 +1,75 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/static/expr"
+
+	// Register code generators for the static plugin
+	_ "goa.design/plugins/v3/static"
+)
+
+// Assets serves the static assets of the given directory under the given
+// request path prefix. The generated MountAssets function of the service HTTP
+// server mounts the handlers serving the files of a file system, typically an
+// embed.FS. The assets are not documented in the OpenAPI specification.
+//
+// Assets must appear in a Service expression.
+//
+// Assets accepts an optional DSL function as argument.
+//
+// Example:
+//
+//    import static "goa.design/plugins/v3/static/dsl"
+//
+//    var _ = Service("web", func() {
+//        static.Assets("/app", "dist", func() {
+//            static.CacheControl("public, max-age=3600")
+//            static.Fallback("index.html")
+//        })
+//    })
+//
+func Assets(path, dir string, fn ...func()) {
+	svc, ok := eval.Current().(*goaexpr.ServiceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(fn) > 1 {
+		eval.ReportError("too many arguments")
+		return
+	}
+	a := &expr.AssetsExpr{Path: path, Dir: dir, Service: svc}
+	if len(fn) == 1 {
+		if !eval.Execute(fn[0], a) {
+			return
+		}
+	}
+	expr.Root.Assets = append(expr.Root.Assets, a)
+}
+
+// CacheControl sets the value of the Cache-Control header of the responses
+// serving the assets. The fallback file is always served with "no-cache" so
+// that clients pick up new application builds.
+//
+// CacheControl must appear in an Assets expression.
+func CacheControl(value string) {
+	if a, ok := eval.Current().(*expr.AssetsExpr); ok {
+		a.CacheControl = value
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Fallback sets the file served for the request paths which do not match an
+// asset, typically the index of a single page application which implements
+// client side routing. The path is relative to the assets directory.
+//
+// Fallback must appear in an Assets expression.
+func Fallback(file string) {
+	if a, ok := eval.Current().(*expr.AssetsExpr); ok {
+		a.Fallback = file
+		return
+	}
+	eval.IncompatibleDSL()
+}
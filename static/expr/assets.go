@@ -0,0 +1,63 @@
+package expr
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// AssetsExpr describes a tree of static assets served by a service.
+	AssetsExpr struct {
+		// Path is the request path prefix under which the assets are
+		// served.
+		Path string
+		// Dir is the directory of the file system containing the assets.
+		Dir string
+		// CacheControl is the value of the Cache-Control header of the
+		// responses, empty if the header is not set.
+		CacheControl string
+		// Fallback is the file served for the request paths that do not
+		// match an asset, e.g. the index of a single page application.
+		// The requests for missing assets are answered with 404 Not
+		// Found if empty.
+		Fallback string
+		// Service is the service serving the assets.
+		Service *expr.ServiceExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (a *AssetsExpr) EvalName() string {
+	return fmt.Sprintf("assets %q of service %q", a.Path, a.Service.Name)
+}
+
+// Validate makes sure the path and directory are well formed and that no
+// other assets of the service use the same path.
+func (a *AssetsExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if !strings.HasPrefix(a.Path, "/") {
+		verr.Add(a, "path must start with /")
+	} else if strings.ContainsAny(a.Path, "{}*") {
+		verr.Add(a, "path cannot contain wildcards")
+	}
+	if a.Dir == "" || path.IsAbs(a.Dir) || strings.HasPrefix(path.Clean(a.Dir), "..") {
+		verr.Add(a, "directory must be a relative path inside the file system")
+	}
+	if strings.HasPrefix(a.Fallback, "/") || strings.HasPrefix(path.Clean(a.Fallback), "..") {
+		verr.Add(a, "fallback must be a file path relative to the directory")
+	}
+	for _, other := range Root.ServiceAssets(a.Service.Name) {
+		if other != a && other.Path == a.Path {
+			verr.Add(a, "path is already used by other assets of the service")
+			break
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
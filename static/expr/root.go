@@ -0,0 +1,60 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the static assets served by the services.
+	RootExpr struct {
+		// Assets lists the asset definitions in the order they appear
+		// in the design.
+		Assets []*AssetsExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "static plugin"
+}
+
+// WalkSets iterates over the asset definitions.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	aexps := make(eval.ExpressionSet, len(r.Assets))
+	for i, a := range r.Assets {
+		aexps[i] = a
+	}
+	walk(aexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/static/dsl"}
+}
+
+// ServiceAssets returns the asset definitions of the given service.
+func (r *RootExpr) ServiceAssets(svc string) []*AssetsExpr {
+	var as []*AssetsExpr
+	for _, a := range r.Assets {
+		if a.Service.Name == svc {
+			as = append(as, a)
+		}
+	}
+	return as
+}
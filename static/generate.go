@@ -0,0 +1,115 @@
+package static
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/static/expr"
+)
+
+// assetsData contains the data necessary to render the handlers serving a
+// tree of static assets.
+type assetsData struct {
+	// Path is the request path prefix.
+	Path string
+	// Patterns lists the route patterns mounted on the muxer.
+	Patterns []string
+	// Dir is the directory of the file system containing the assets.
+	Dir string
+	// CacheControl is the value of the Cache-Control header.
+	CacheControl string
+	// Fallback is the file served for the unknown paths.
+	Fallback string
+}
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "static",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the function mounting the handlers serving the static
+// assets of each HTTP service that defines some.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.API.HTTP.Services {
+				if !config.Enabled("static", svc.Name()) {
+					continue
+				}
+				if f := assetsFile(svc); f != nil {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// assetsFile returns the file defining the function mounting the asset
+// handlers of the given HTTP service, nil if the service serves no assets.
+func assetsFile(svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	as := expr.Root.ServiceAssets(svc.Name())
+	if len(as) == 0 {
+		return nil
+	}
+	var data []*assetsData
+	for _, a := range as {
+		p := strings.TrimSuffix(a.Path, "/")
+		prefix := p
+		if prefix == "" {
+			prefix = "/"
+		}
+		data = append(data, &assetsData{
+			Path:         prefix,
+			Patterns:     []string{prefix, p + "/{*filepath}"},
+			Dir:          a.Dir,
+			CacheControl: a.CacheControl,
+			Fallback:     a.Fallback,
+		})
+	}
+	sd := httpcodegen.HTTPServices.Get(svc.Name())
+	return &codegen.File{
+		Path: filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(sd.Service.VarName), "server", "assets.go"),
+		SectionTemplates: []*codegen.SectionTemplate{
+			codegen.Header(svc.Name()+" HTTP server static assets", "server", []*codegen.ImportSpec{
+				{Path: "io/fs"},
+				codegen.GoaNamedImport("http", "goahttp"),
+				{Path: "goa.design/plugins/v3/static"},
+			}),
+			{Name: "static-mount", Source: mountAssetsT, Data: data},
+		},
+	}
+}
+
+// input: []*assetsData
+const mountAssetsT = `// MountAssets configures the mux to serve the static assets of the service
+// from fsys, typically an embed.FS embedding the asset directories. The paths
+// of the asset directories are relative to the root of fsys.
+func MountAssets(mux goahttp.Muxer, fsys fs.FS) error {
+{{- range . }}
+	{
+		sub, err := fs.Sub(fsys, {{ printf "%q" .Dir }})
+		if err != nil {
+			return err
+		}
+		h := static.Handler(sub, {{ printf "%q" .Path }}, {{ printf "%q" .CacheControl }}, {{ printf "%q" .Fallback }})
+		for _, method := range []string{"GET", "HEAD"} {
+		{{- range .Patterns }}
+			mux.Handle(method, {{ printf "%q" . }}, h.ServeHTTP)
+		{{- end }}
+		}
+	}
+{{- end }}
+	return nil
+}
+`
@@ -0,0 +1,32 @@
+package static_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/plugins/v3/static"
+	"goa.design/plugins/v3/static/expr"
+	"goa.design/plugins/v3/static/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Assets = nil
+	httpcodegen.RunHTTPDSL(t, testdata.AssetsDSL)
+	fs, err := static.Generate("gen", []eval.Root{goaexpr.Root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if fs[0].Path != "gen/http/web/server/assets.go" {
+		t.Errorf("got path %q, expected gen/http/web/server/assets.go", fs[0].Path)
+	}
+	code := codegen.SectionCode(t, fs[0].SectionTemplates[1])
+	if code != testdata.MountAssetsCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.MountAssetsCode))
+	}
+}
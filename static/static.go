@@ -0,0 +1,76 @@
+package static
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Handler returns a HTTP handler serving the files of fsys. The request paths
+// are stripped from the given prefix and resolved relative to the root of
+// fsys. The requests made to a directory serve its index.html file. The
+// Cache-Control header of the responses is set to cacheControl unless empty.
+// The requests for missing files serve the fallback file with the "no-cache"
+// Cache-Control header if fallback is not empty and are answered with 404 Not
+// Found otherwise.
+func Handler(fsys fs.FS, prefix, cacheControl, fallback string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(prefix, "/"))
+		name = strings.TrimPrefix(path.Clean("/"+name), "/")
+		if name == "" {
+			name = "."
+		}
+		cache := cacheControl
+		f, data, stat, err := open(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) && fallback != "" {
+			cache = "no-cache"
+			f, data, stat, err = open(fsys, fallback)
+		}
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		if cache != "" {
+			w.Header().Set("Cache-Control", cache)
+		}
+		http.ServeContent(w, r, stat.Name(), stat.ModTime(), data)
+	})
+}
+
+// open opens the named file and returns its seekable content and information.
+// Directories resolve to their index.html file. The caller must close the
+// returned file.
+func open(fsys fs.FS, name string) (fs.File, io.ReadSeeker, fs.FileInfo, error) {
+	stat, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if stat.IsDir() {
+		name = path.Join(name, "index.html")
+		if stat, err = fs.Stat(fsys, name); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return f, rs, stat, nil
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, nil, err
+	}
+	return f, bytes.NewReader(b), stat, nil
+}
@@ -0,0 +1,46 @@
+package static
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":      {Data: []byte("index")},
+		"app.js":          {Data: []byte("app")},
+		"docs/index.html": {Data: []byte("docs")},
+	}
+	cases := []struct {
+		Name     string
+		Path     string
+		Fallback string
+		Status   int
+		Body     string
+		Cache    string
+	}{
+		{"file", "/app/app.js", "", http.StatusOK, "app", "public, max-age=60"},
+		{"root", "/app", "", http.StatusOK, "index", "public, max-age=60"},
+		{"directory", "/app/docs/", "", http.StatusOK, "docs", "public, max-age=60"},
+		{"traversal", "/app/../app.js", "", http.StatusOK, "app", "public, max-age=60"},
+		{"missing", "/app/orders/42", "", http.StatusNotFound, "", ""},
+		{"fallback", "/app/orders/42", "index.html", http.StatusOK, "index", "no-cache"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handler(fsys, "/app", "public, max-age=60", c.Fallback).ServeHTTP(w, httptest.NewRequest("GET", c.Path, nil))
+			if w.Code != c.Status {
+				t.Fatalf("got status %d, expected %d", w.Code, c.Status)
+			}
+			if c.Body != "" && w.Body.String() != c.Body {
+				t.Errorf("got body %q, expected %q", w.Body.String(), c.Body)
+			}
+			if got := w.Header().Get("Cache-Control"); got != c.Cache {
+				t.Errorf("got Cache-Control %q, expected %q", got, c.Cache)
+			}
+		})
+	}
+}
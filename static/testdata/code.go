@@ -0,0 +1,31 @@
+package testdata
+
+var MountAssetsCode = `// MountAssets configures the mux to serve the static assets of the service
+// from fsys, typically an embed.FS embedding the asset directories. The paths
+// of the asset directories are relative to the root of fsys.
+func MountAssets(mux goahttp.Muxer, fsys fs.FS) error {
+	{
+		sub, err := fs.Sub(fsys, "dist")
+		if err != nil {
+			return err
+		}
+		h := static.Handler(sub, "/app", "public, max-age=3600", "index.html")
+		for _, method := range []string{"GET", "HEAD"} {
+			mux.Handle(method, "/app", h.ServeHTTP)
+			mux.Handle(method, "/app/{*filepath}", h.ServeHTTP)
+		}
+	}
+	{
+		sub, err := fs.Sub(fsys, "site/docs")
+		if err != nil {
+			return err
+		}
+		h := static.Handler(sub, "/docs", "", "")
+		for _, method := range []string{"GET", "HEAD"} {
+			mux.Handle(method, "/docs", h.ServeHTTP)
+			mux.Handle(method, "/docs/{*filepath}", h.ServeHTTP)
+		}
+	}
+	return nil
+}
+`
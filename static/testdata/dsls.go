@@ -0,0 +1,21 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	static "goa.design/plugins/v3/static/dsl"
+)
+
+var AssetsDSL = func() {
+	Service("web", func() {
+		static.Assets("/app", "dist", func() {
+			static.CacheControl("public, max-age=3600")
+			static.Fallback("index.html")
+		})
+		static.Assets("/docs/", "site/docs")
+		Method("health", func() {
+			HTTP(func() {
+				GET("/health")
+			})
+		})
+	})
+}
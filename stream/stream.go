@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+
+	"goa.design/goa/v3/codegen"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Encoder writes the representation of the given data to w.
+type Encoder func(w io.Writer, data interface{}) error
+
+// File returns a file whose content is written by encoding data with enc
+// directly to the file rather than rendering a template into a string first.
+// This keeps the memory used to render large documents down. The file has a
+// single section with the given name whose data is data so that the plugins
+// running later can still inspect or modify the document, or replace it by
+// setting the section data. The section source is empty: the content is
+// written by the file finalizer once goa has created the file.
+func File(path, section string, data interface{}, enc Encoder) *codegen.File {
+	s := &codegen.SectionTemplate{Name: section, Data: data}
+	return &codegen.File{
+		Path:             path,
+		SectionTemplates: []*codegen.SectionTemplate{s},
+		FinalizeFunc: func(p string) error {
+			f, err := os.OpenFile(p, os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			w := bufio.NewWriter(f)
+			if err := enc(w, s.Data); err != nil {
+				f.Close()
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		},
+	}
+}
+
+// JSON writes the compact JSON representation of data followed by a newline.
+func JSON(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+// IndentedJSON writes the JSON representation of data indented with two
+// spaces followed by a newline.
+func IndentedJSON(w io.Writer, data interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+// YAML writes the YAML representation of data. If data is a slice or an array
+// each element is written as a separate YAML document, nothing is written if
+// it is empty.
+func YAML(w io.Writer, data interface{}) error {
+	enc := yaml.NewEncoder(w)
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		if v.Len() == 0 {
+			return nil // the encoder cannot be closed before writing
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := enc.Encode(v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	} else if err := enc.Encode(data); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,74 @@
+package stream_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/stream"
+)
+
+func TestFile(t *testing.T) {
+	type doc struct {
+		Name  string   `json:"name" yaml:"name"`
+		Items []string `json:"items,omitempty" yaml:"items,omitempty"`
+	}
+	cases := []struct {
+		Name     string
+		Data     interface{}
+		Encoder  stream.Encoder
+		Expected string
+	}{
+		{"json", &doc{Name: "a", Items: []string{"x"}}, stream.JSON, "{\"name\":\"a\",\"items\":[\"x\"]}\n"},
+		{"indented-json", &doc{Name: "a"}, stream.IndentedJSON, "{\n  \"name\": \"a\"\n}\n"},
+		{"yaml", &doc{Name: "a", Items: []string{"x"}}, stream.YAML, "name: a\nitems:\n- x\n"},
+		{"yaml-documents", []*doc{{Name: "a"}, {Name: "b"}}, stream.YAML, "name: a\n---\nname: b\n"},
+		{"yaml-no-document", []*doc{}, stream.YAML, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			f := stream.File("gen/doc", "doc", c.Data, c.Encoder)
+			if len(f.SectionTemplates) != 1 || f.SectionTemplates[0].Name != "doc" || f.SectionTemplates[0].Data == nil {
+				t.Fatalf("invalid section, expected a single section holding the data")
+			}
+			if got := plugintest.Render(t, f); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestFileSectionData(t *testing.T) {
+	f := stream.File("gen/doc.json", "doc", 42, stream.JSON)
+	f.SectionTemplates[0].Data = 43
+	if got := plugintest.Render(t, f); got != "43\n" {
+		t.Errorf("got %q, expected the section data to be written", got)
+	}
+}
+
+func TestFileTruncates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gen", "doc.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte("previous content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.File("gen/doc.json", "doc", 42, stream.JSON).Render(dir); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "42\n" {
+		t.Errorf("got %q, expected the previous content to be replaced", b)
+	}
+}
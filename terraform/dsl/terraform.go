@@ -0,0 +1,92 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/terraform/expr"
+
+	// Register code generators for the Terraform plugin
+	_ "goa.design/plugins/v3/terraform"
+)
+
+const (
+	// CloudRun deploys the server to Google Cloud Run behind Google Cloud
+	// API Gateway.
+	CloudRun = expr.CloudRun
+	// ECS deploys the server to AWS Elastic Container Service on Fargate
+	// behind Amazon API Gateway.
+	ECS = expr.ECS
+)
+
+// Module describes the Terraform module deploying the server. The servers
+// without a module are deployed to Cloud Run by modules requiring the image
+// and region.
+//
+// Module must appear in a Server expression.
+//
+// Module accepts a DSL function as argument.
+//
+// Example:
+//
+//    import terraform "goa.design/plugins/v3/terraform/dsl"
+//
+//    var _ = API("calc", func() {
+//        Server("calc", func() {
+//            terraform.Module(func() {
+//                terraform.Platform(terraform.ECS)
+//                terraform.Image("registry.example.com/calc:1.2.0")
+//                terraform.Region("eu-west-1")
+//            })
+//        })
+//    })
+//
+func Module(fn func()) {
+	s, ok := eval.Current().(*goaexpr.ServerExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if expr.Root.Module(s.Name) != nil {
+		eval.ReportError("Terraform module of server %q defined twice", s.Name)
+		return
+	}
+	m := &expr.ModuleExpr{Platform: expr.CloudRun, Server: s}
+	if !eval.Execute(fn, m) {
+		return
+	}
+	expr.Root.Modules = append(expr.Root.Modules, m)
+}
+
+// Platform sets the platform running the server, CloudRun or ECS. The default
+// is CloudRun.
+//
+// Platform must appear in a Module expression.
+func Platform(platform string) {
+	if m, ok := eval.Current().(*expr.ModuleExpr); ok {
+		m.Platform = platform
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Image sets the default value of the container image variable of the module.
+//
+// Image must appear in a Module expression.
+func Image(image string) {
+	if m, ok := eval.Current().(*expr.ModuleExpr); ok {
+		m.Image = image
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// Region sets the default value of the region variable of the module.
+//
+// Region must appear in a Module expression.
+func Region(region string) {
+	if m, ok := eval.Current().(*expr.ModuleExpr); ok {
+		m.Region = region
+		return
+	}
+	eval.IncompatibleDSL()
+}
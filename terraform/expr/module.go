@@ -0,0 +1,45 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// CloudRun is the Google Cloud Run platform.
+	CloudRun = "cloudrun"
+	// ECS is the AWS Elastic Container Service platform.
+	ECS = "ecs"
+)
+
+// ModuleExpr describes the Terraform module deploying a server.
+type ModuleExpr struct {
+	// Platform is the platform running the server, CloudRun or ECS.
+	Platform string
+	// Image is the default container image, empty if the module requires
+	// it.
+	Image string
+	// Region is the default region, empty if the module requires it.
+	Region string
+	// Server is the deployed server.
+	Server *expr.ServerExpr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (m *ModuleExpr) EvalName() string {
+	return fmt.Sprintf("Terraform module of %s", m.Server.EvalName())
+}
+
+// Validate ensures the module expression is valid.
+func (m *ModuleExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if m.Platform != CloudRun && m.Platform != ECS {
+		verr.Add(m, "invalid platform %q, must be %q or %q", m.Platform, CloudRun, ECS)
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
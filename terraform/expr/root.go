@@ -0,0 +1,59 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the Terraform modules defined in the design.
+	RootExpr struct {
+		// Modules lists the modules in the order they appear in the
+		// design.
+		Modules []*ModuleExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "Terraform plugin"
+}
+
+// WalkSets iterates over the modules.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	mexps := make(eval.ExpressionSet, len(r.Modules))
+	for i, m := range r.Modules {
+		mexps[i] = m
+	}
+	walk(mexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/terraform/dsl"}
+}
+
+// Module returns the module of the given server, nil if there isn't one.
+func (r *RootExpr) Module(server string) *ModuleExpr {
+	for _, m := range r.Modules {
+		if m.Server.Name == server {
+			return m
+		}
+	}
+	return nil
+}
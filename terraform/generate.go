@@ -0,0 +1,536 @@
+package terraform
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/terraform/expr"
+)
+
+type (
+	// moduleData contains the data necessary to render the files of the
+	// module deploying a server.
+	moduleData struct {
+		// Name is the name of the deployed resources.
+		Name string
+		// Platform is the platform running the server.
+		Platform string
+		// Ports lists the ports exposed by the container.
+		Ports []*portData
+		// Gateway is true if the module deploys an API gateway in front
+		// of the server, i.e. if the server exposes HTTP endpoints.
+		Gateway bool
+		// Args lists the container arguments.
+		Args []string
+		// Secrets lists the secrets given to the container.
+		Secrets []*secretData
+		// Variables lists the module variables.
+		Variables []*variableData
+	}
+
+	// portData describes a port exposed by the container.
+	portData struct {
+		// Name is the port name ("http" or "grpc").
+		Name string
+		// Number is the port number.
+		Number int
+	}
+
+	// secretData describes a secret given to the container as an
+	// environment variable.
+	secretData struct {
+		// Env is the name of the environment variable.
+		Env string
+		// Variable is the name of the module variable referencing the
+		// secret.
+		Variable string
+	}
+
+	// variableData describes a module variable.
+	variableData struct {
+		// Name is the variable name.
+		Name string
+		// Description is the variable description.
+		Description string
+		// Type is the Terraform type of the variable.
+		Type string
+		// Default is the HCL literal of the default value, empty if the
+		// variable is required.
+		Default string
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "terraform",
+		Cmd:      "gen",
+		Generate: Generate,
+		Parallel: true,
+	})
+}
+
+// Generate produces the Terraform module deploying each server defined in the
+// design.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("terraform", "") {
+		return files, nil
+	}
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, s := range r.API.Servers {
+				files = append(files, moduleFiles(r, s)...)
+			}
+		}
+	}
+	return files, nil
+}
+
+// moduleFiles returns the main.tf, variables.tf and outputs.tf files of the
+// module deploying the given server.
+func moduleFiles(r *goaexpr.RootExpr, s *goaexpr.ServerExpr) []*codegen.File {
+	m := expr.Root.Module(s.Name)
+	if m == nil {
+		m = &expr.ModuleExpr{Platform: expr.CloudRun, Server: s}
+	}
+	data := buildModuleData(r, m)
+	dir := filepath.Join(codegen.Gendir, "terraform", data.Name)
+	main, outputs := cloudRunT, cloudRunOutputsT
+	if m.Platform == expr.ECS {
+		main, outputs = ecsT, ecsOutputsT
+	}
+	file := func(name, source string) *codegen.File {
+		return &codegen.File{
+			Path: filepath.Join(dir, name),
+			SectionTemplates: []*codegen.SectionTemplate{{
+				Name:   "terraform-" + strings.TrimSuffix(name, ".tf"),
+				Source: source,
+				Data:   data,
+			}},
+		}
+	}
+	return []*codegen.File{
+		file("main.tf", main),
+		file("variables.tf", variablesT),
+		file("outputs.tf", outputs),
+	}
+}
+
+// buildModuleData computes the data of the module m.
+func buildModuleData(r *goaexpr.RootExpr, m *expr.ModuleExpr) *moduleData {
+	s := m.Server
+	data := &moduleData{
+		Name:     codegen.KebabCase(s.Name),
+		Platform: m.Platform,
+		Args:     []string{"--host=${var.host}"},
+	}
+	var vars []*variableData
+	if m.Platform == expr.CloudRun {
+		vars = append(vars, &variableData{Name: "project", Description: "Google Cloud project ID.", Type: "string"})
+	}
+	vars = append(vars,
+		&variableData{Name: "image", Description: "Container image of the server.", Type: "string", Default: literal(m.Image)},
+		&variableData{Name: "region", Description: "Region the server is deployed to.", Type: "string", Default: literal(m.Region)},
+	)
+	if len(s.Hosts) > 0 {
+		h := s.Hosts[0]
+		names := make([]string, len(s.Hosts))
+		for i, h := range s.Hosts {
+			names[i] = h.Name
+		}
+		vars = append(vars, &variableData{
+			Name:        "host",
+			Description: fmt.Sprintf("Server host (valid values: %s).", strings.Join(names, ", ")),
+			Type:        "string",
+			Default:     literal(h.Name),
+		})
+		data.Ports = ports(h)
+		for _, v := range *goaexpr.AsObject(h.Attribute().Type) {
+			name := "host_" + codegen.SnakeCase(v.Name)
+			def := ""
+			if v.Attribute.DefaultValue != nil {
+				def = literal(fmt.Sprint(v.Attribute.DefaultValue))
+			}
+			vars = append(vars, &variableData{
+				Name:        name,
+				Description: description(v.Attribute, fmt.Sprintf("Value of the %s host variable.", v.Name)),
+				Type:        "string",
+				Default:     def,
+			})
+			data.Args = append(data.Args, fmt.Sprintf("--%s=${var.%s}", v.Name, name))
+		}
+	}
+	for _, name := range schemes(r, s) {
+		secret := &secretData{Env: strings.ToUpper(codegen.SnakeCase(name)) + "_SECRET"}
+		desc := fmt.Sprintf("Name of the Secret Manager secret holding the secret of the %s security scheme.", name)
+		secret.Variable = codegen.SnakeCase(name) + "_secret"
+		if m.Platform == expr.ECS {
+			desc = fmt.Sprintf("ARN of the Secrets Manager secret holding the secret of the %s security scheme.", name)
+			secret.Variable += "_arn"
+		}
+		data.Secrets = append(data.Secrets, secret)
+		vars = append(vars, &variableData{Name: secret.Variable, Description: desc, Type: "string"})
+	}
+	for _, p := range data.Ports {
+		if p.Name == "http" {
+			data.Gateway = true
+		}
+	}
+	switch m.Platform {
+	case expr.CloudRun:
+		if data.Gateway {
+			vars = append(vars, &variableData{
+				Name:        "openapi_spec",
+				Description: "Path to the OpenAPI specification served by the API gateway, the specification generated by goa by default.",
+				Type:        "string",
+				Default:     `""`,
+			})
+		}
+	case expr.ECS:
+		vars = append(vars,
+			&variableData{Name: "cluster_arn", Description: "ARN of the ECS cluster running the service.", Type: "string"},
+			&variableData{Name: "subnets", Description: "Subnets of the service tasks.", Type: "list(string)"},
+			&variableData{Name: "security_groups", Description: "Security groups of the service tasks.", Type: "list(string)", Default: "[]"},
+			&variableData{Name: "execution_role_arn", Description: "ARN of the task execution role, it must be allowed to read the secrets.", Type: "string"},
+			&variableData{Name: "cpu", Description: "CPU units of the task.", Type: "number", Default: "256"},
+			&variableData{Name: "memory", Description: "Memory of the task in MiB.", Type: "number", Default: "512"},
+			&variableData{Name: "desired_count", Description: "Number of tasks.", Type: "number", Default: "1"},
+		)
+		if data.Gateway {
+			vars = append(vars, &variableData{
+				Name:        "backend_url",
+				Description: "URL of the load balancer or service discovery endpoint the API gateway forwards the requests to.",
+				Type:        "string",
+			})
+		}
+	}
+	data.Variables = vars
+	return data
+}
+
+// ports returns the ports of the URIs of the given host, the host variables
+// are replaced with their default values.
+func ports(h *goaexpr.HostExpr) []*portData {
+	var ps []*portData
+	for _, uri := range h.URIs {
+		u := string(uri)
+		for _, v := range *goaexpr.AsObject(h.Attribute().Type) {
+			if v.Attribute.DefaultValue != nil {
+				u = strings.Replace(u, "{"+v.Name+"}", fmt.Sprint(v.Attribute.DefaultValue), -1)
+			}
+		}
+		parsed, err := url.Parse(u)
+		if err != nil {
+			continue
+		}
+		name := "http"
+		if strings.HasPrefix(parsed.Scheme, "grpc") {
+			name = "grpc"
+		}
+		number, err := strconv.Atoi(parsed.Port())
+		if err != nil {
+			number = 80
+			if parsed.Scheme == "https" || parsed.Scheme == "grpcs" {
+				number = 443
+			}
+		}
+		var found bool
+		for _, p := range ps {
+			if p.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ps = append(ps, &portData{Name: name, Number: number})
+		}
+	}
+	return ps
+}
+
+// schemes returns the names of the security schemes used by the endpoints of
+// the services of the given server in the order they appear in the design.
+func schemes(r *goaexpr.RootExpr, s *goaexpr.ServerExpr) []string {
+	used := make(map[string]bool)
+	mark := func(reqs []*goaexpr.SecurityExpr) {
+		for _, req := range reqs {
+			for _, sch := range req.Schemes {
+				used[sch.SchemeName] = true
+			}
+		}
+	}
+	mark(r.API.Requirements)
+	for _, name := range s.Services {
+		svc := r.Service(name)
+		if svc == nil {
+			continue
+		}
+		mark(svc.Requirements)
+		for _, m := range svc.Methods {
+			mark(m.Requirements)
+		}
+	}
+	var names []string
+	for _, sch := range r.Schemes {
+		if used[sch.SchemeName] {
+			names = append(names, sch.SchemeName)
+			delete(used, sch.SchemeName)
+		}
+	}
+	return names
+}
+
+// description returns the description of the given attribute followed by the
+// valid values if any, def if the attribute has no description.
+func description(att *goaexpr.AttributeExpr, def string) string {
+	desc := att.Description
+	if desc == "" {
+		desc = def
+	}
+	if v := att.Validation; v != nil && len(v.Values) > 0 {
+		vals := make([]string, len(v.Values))
+		for i, val := range v.Values {
+			vals[i] = fmt.Sprint(val)
+		}
+		desc = fmt.Sprintf("%s (valid values: %s)", strings.TrimSuffix(desc, "."), strings.Join(vals, ", "))
+		if !strings.HasSuffix(desc, ".") {
+			desc += "."
+		}
+	}
+	return desc
+}
+
+// literal returns the HCL literal of the given string, the empty string if
+// s is empty.
+func literal(s string) string {
+	if s == "" {
+		return ""
+	}
+	return strconv.Quote(s)
+}
+
+// input: moduleData
+const cloudRunT = `terraform {
+  required_providers {
+    google = {
+      source = "hashicorp/google"
+    }
+{{- if .Gateway }}
+    google-beta = {
+      source = "hashicorp/google-beta"
+    }
+{{- end }}
+  }
+}
+
+resource "google_cloud_run_service" "this" {
+  name     = {{ printf "%q" .Name }}
+  project  = var.project
+  location = var.region
+
+  template {
+    spec {
+      containers {
+        image = var.image
+        args = [
+{{- range .Args }}
+          {{ printf "%q" . }},
+{{- end }}
+        ]
+{{- if .Ports }}{{ with index .Ports 0 }}
+
+        ports {
+          name           = {{ if eq .Name "grpc" }}"h2c"{{ else }}"http1"{{ end }}
+          container_port = {{ .Number }}
+        }
+{{- end }}{{ end }}
+{{- range .Secrets }}
+
+        env {
+          name = {{ printf "%q" .Env }}
+          value_from {
+            secret_key_ref {
+              name = var.{{ .Variable }}
+              key  = "latest"
+            }
+          }
+        }
+{{- end }}
+      }
+    }
+  }
+}
+{{- if .Gateway }}
+
+resource "google_api_gateway_api" "this" {
+  provider = google-beta
+  project  = var.project
+  api_id   = {{ printf "%q" .Name }}
+}
+
+resource "google_api_gateway_api_config" "this" {
+  provider             = google-beta
+  project              = var.project
+  api                  = google_api_gateway_api.this.api_id
+  api_config_id_prefix = "{{ .Name }}-"
+
+  openapi_documents {
+    document {
+      path     = "openapi.json"
+      contents = filebase64(coalesce(var.openapi_spec, "${path.module}/../../http/openapi.json"))
+    }
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "google_api_gateway_gateway" "this" {
+  provider   = google-beta
+  project    = var.project
+  region     = var.region
+  api_config = google_api_gateway_api_config.this.id
+  gateway_id = {{ printf "%q" .Name }}
+}
+{{- end }}
+`
+
+// input: moduleData
+const cloudRunOutputsT = `output "url" {
+  description = "URL of the Cloud Run service."
+  value       = google_cloud_run_service.this.status[0].url
+}
+{{- if .Gateway }}
+
+output "gateway_url" {
+  description = "URL of the API gateway."
+  value       = "https://${google_api_gateway_gateway.this.default_hostname}"
+}
+{{- end }}
+`
+
+// input: moduleData
+const ecsT = `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+resource "aws_cloudwatch_log_group" "this" {
+  name = "/ecs/{{ .Name }}"
+}
+
+resource "aws_ecs_task_definition" "this" {
+  family                   = {{ printf "%q" .Name }}
+  requires_compatibilities = ["FARGATE"]
+  network_mode             = "awsvpc"
+  cpu                      = var.cpu
+  memory                   = var.memory
+  execution_role_arn       = var.execution_role_arn
+  container_definitions = jsonencode([{
+    name      = {{ printf "%q" .Name }}
+    image     = var.image
+    essential = true
+    command = [
+{{- range .Args }}
+      {{ printf "%q" . }},
+{{- end }}
+    ]
+    portMappings = [
+{{- range .Ports }}
+      { name = {{ printf "%q" .Name }}, containerPort = {{ .Number }}, protocol = "tcp" },
+{{- end }}
+    ]
+{{- if .Secrets }}
+    secrets = [
+{{- range .Secrets }}
+      { name = {{ printf "%q" .Env }}, valueFrom = var.{{ .Variable }} },
+{{- end }}
+    ]
+{{- end }}
+    logConfiguration = {
+      logDriver = "awslogs"
+      options = {
+        awslogs-group         = aws_cloudwatch_log_group.this.name
+        awslogs-region        = var.region
+        awslogs-stream-prefix = {{ printf "%q" .Name }}
+      }
+    }
+  }])
+}
+
+resource "aws_ecs_service" "this" {
+  name            = {{ printf "%q" .Name }}
+  cluster         = var.cluster_arn
+  task_definition = aws_ecs_task_definition.this.arn
+  desired_count   = var.desired_count
+  launch_type     = "FARGATE"
+
+  network_configuration {
+    subnets         = var.subnets
+    security_groups = var.security_groups
+  }
+}
+{{- if .Gateway }}
+
+resource "aws_apigatewayv2_api" "this" {
+  name          = {{ printf "%q" .Name }}
+  protocol_type = "HTTP"
+}
+
+resource "aws_apigatewayv2_integration" "this" {
+  api_id             = aws_apigatewayv2_api.this.id
+  integration_type   = "HTTP_PROXY"
+  integration_method = "ANY"
+  integration_uri    = "${var.backend_url}/{proxy}"
+}
+
+resource "aws_apigatewayv2_route" "this" {
+  api_id    = aws_apigatewayv2_api.this.id
+  route_key = "ANY /{proxy+}"
+  target    = "integrations/${aws_apigatewayv2_integration.this.id}"
+}
+
+resource "aws_apigatewayv2_stage" "this" {
+  api_id      = aws_apigatewayv2_api.this.id
+  name        = "$default"
+  auto_deploy = true
+}
+{{- end }}
+`
+
+// input: moduleData
+const ecsOutputsT = `output "service_name" {
+  description = "Name of the ECS service."
+  value       = aws_ecs_service.this.name
+}
+{{- if .Gateway }}
+
+output "gateway_url" {
+  description = "URL of the API gateway."
+  value       = aws_apigatewayv2_api.this.api_endpoint
+}
+{{- end }}
+`
+
+// input: moduleData
+const variablesT = `{{- range $i, $v := .Variables }}{{ if $i }}
+{{ end }}variable {{ printf "%q" .Name }} {
+  description = {{ printf "%q" .Description }}
+  type        = {{ .Type }}
+{{- if .Default }}
+  default     = {{ .Default }}
+{{- end }}
+}
+{{ end }}`
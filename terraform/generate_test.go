@@ -0,0 +1,69 @@
+package terraform_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/terraform"
+	"goa.design/plugins/v3/terraform/expr"
+	"goa.design/plugins/v3/terraform/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+	}{
+		{"default", testdata.DefaultDSL},
+		{"cloudrun", testdata.CloudRunDSL},
+		{"grpc", testdata.GRPCDSL},
+		{"ecs", testdata.ECSDSL},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Modules = nil
+			root := codegen.RunDSLWithFunc(t, c.DSL, func() {
+				eval.Register(expr.Root)
+			})
+			fs, err := terraform.Generate("", []eval.Root{root}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fs) != 3 {
+				t.Fatalf("got %d files, expected 3", len(fs))
+			}
+			for _, f := range fs {
+				name := strings.TrimSuffix(filepath.Base(f.Path), ".tf")
+				plugintest.Golden(t, fmt.Sprintf("%s-%s.tf", c.Name, name), plugintest.Render(t, f))
+			}
+		})
+	}
+}
+
+func TestInvalidPlatform(t *testing.T) {
+	expr.Root.Modules = nil
+	eval.Reset()
+	goaexpr.Root = new(goaexpr.RootExpr)
+	goaexpr.Root.GeneratedTypes = &goaexpr.GeneratedRoot{}
+	eval.Register(goaexpr.Root)
+	eval.Register(goaexpr.Root.GeneratedTypes)
+	eval.Register(expr.Root)
+	var err error
+	if eval.Execute(testdata.InvalidPlatformDSL, nil) {
+		err = eval.RunDSL()
+	} else {
+		err = eval.Context.Errors
+	}
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), `invalid platform "lambda"`) {
+		t.Errorf("got error %q, expected invalid platform", err)
+	}
+}
@@ -0,0 +1,75 @@
+terraform {
+  required_providers {
+    google = {
+      source = "hashicorp/google"
+    }
+    google-beta = {
+      source = "hashicorp/google-beta"
+    }
+  }
+}
+
+resource "google_cloud_run_service" "this" {
+  name     = "calc-server"
+  project  = var.project
+  location = var.region
+
+  template {
+    spec {
+      containers {
+        image = var.image
+        args = [
+          "--host=${var.host}",
+          "--region=${var.host_region}",
+        ]
+
+        ports {
+          name           = "http1"
+          container_port = 8443
+        }
+
+        env {
+          name = "JWT_SECRET"
+          value_from {
+            secret_key_ref {
+              name = var.jwt_secret
+              key  = "latest"
+            }
+          }
+        }
+      }
+    }
+  }
+}
+
+resource "google_api_gateway_api" "this" {
+  provider = google-beta
+  project  = var.project
+  api_id   = "calc-server"
+}
+
+resource "google_api_gateway_api_config" "this" {
+  provider             = google-beta
+  project              = var.project
+  api                  = google_api_gateway_api.this.api_id
+  api_config_id_prefix = "calc-server-"
+
+  openapi_documents {
+    document {
+      path     = "openapi.json"
+      contents = filebase64(coalesce(var.openapi_spec, "${path.module}/../../http/openapi.json"))
+    }
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "google_api_gateway_gateway" "this" {
+  provider   = google-beta
+  project    = var.project
+  region     = var.region
+  api_config = google_api_gateway_api_config.this.id
+  gateway_id = "calc-server"
+}
@@ -0,0 +1,39 @@
+variable "project" {
+  description = "Google Cloud project ID."
+  type        = string
+}
+
+variable "image" {
+  description = "Container image of the server."
+  type        = string
+  default     = "gcr.io/calc/calc:1.2.0"
+}
+
+variable "region" {
+  description = "Region the server is deployed to."
+  type        = string
+  default     = "europe-west1"
+}
+
+variable "host" {
+  description = "Server host (valid values: production, development)."
+  type        = string
+  default     = "production"
+}
+
+variable "host_region" {
+  description = "Region of the calc service (valid values: eu, us)."
+  type        = string
+  default     = "eu"
+}
+
+variable "jwt_secret" {
+  description = "Name of the Secret Manager secret holding the secret of the jwt security scheme."
+  type        = string
+}
+
+variable "openapi_spec" {
+  description = "Path to the OpenAPI specification served by the API gateway, the specification generated by goa by default."
+  type        = string
+  default     = ""
+}
@@ -0,0 +1,9 @@
+output "url" {
+  description = "URL of the Cloud Run service."
+  value       = google_cloud_run_service.this.status[0].url
+}
+
+output "gateway_url" {
+  description = "URL of the API gateway."
+  value       = "https://${google_api_gateway_gateway.this.default_hostname}"
+}
@@ -0,0 +1,26 @@
+variable "project" {
+  description = "Google Cloud project ID."
+  type        = string
+}
+
+variable "image" {
+  description = "Container image of the server."
+  type        = string
+}
+
+variable "region" {
+  description = "Region the server is deployed to."
+  type        = string
+}
+
+variable "host" {
+  description = "Server host (valid values: local)."
+  type        = string
+  default     = "local"
+}
+
+variable "openapi_spec" {
+  description = "Path to the OpenAPI specification served by the API gateway, the specification generated by goa by default."
+  type        = string
+  default     = ""
+}
@@ -0,0 +1,116 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	terraform "goa.design/plugins/v3/terraform/dsl"
+)
+
+var DefaultDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			Host("local", func() {
+				URI("http://localhost:8000")
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			HTTP(func() {
+				GET("/add")
+			})
+		})
+	})
+}
+
+var CloudRunDSL = func() {
+	var JWTAuth = JWTSecurity("jwt", func() {
+		Scope("api:read")
+	})
+	API("calc", func() {
+		Server("calc-server", func() {
+			Host("production", func() {
+				URI("https://{region}.calc.example.com:8443")
+				URI("grpcs://{region}.calc.example.com:9443")
+				Variable("region", String, "Region of the calc service", func() {
+					Enum("eu", "us")
+					Default("eu")
+				})
+			})
+			Host("development", func() {
+				URI("http://localhost:8000")
+			})
+			terraform.Module(func() {
+				terraform.Image("gcr.io/calc/calc:1.2.0")
+				terraform.Region("europe-west1")
+			})
+		})
+	})
+	Service("calc", func() {
+		Security(JWTAuth)
+		Method("add", func() {
+			Payload(func() {
+				Token("token", String)
+			})
+			HTTP(func() {
+				GET("/add")
+			})
+			GRPC(func() {})
+		})
+	})
+}
+
+var GRPCDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			Host("local", func() {
+				URI("grpc://localhost:8080")
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			GRPC(func() {})
+		})
+	})
+}
+
+var ECSDSL = func() {
+	var (
+		JWTAuth   = JWTSecurity("jwt")
+		BasicAuth = BasicAuthSecurity("basic")
+	)
+	API("calc", func() {
+		Server("calc-server", func() {
+			Host("production", func() {
+				URI("https://calc.example.com:8443")
+			})
+			terraform.Module(func() {
+				terraform.Platform(terraform.ECS)
+				terraform.Image("123456789012.dkr.ecr.eu-west-1.amazonaws.com/calc:1.2.0")
+			})
+		})
+	})
+	Service("calc", func() {
+		Method("add", func() {
+			Security(JWTAuth, BasicAuth)
+			Payload(func() {
+				Token("token", String)
+				Username("user", String)
+				Password("pass", String)
+			})
+			HTTP(func() {
+				GET("/add")
+			})
+		})
+	})
+}
+
+var InvalidPlatformDSL = func() {
+	API("calc", func() {
+		Server("calc", func() {
+			terraform.Module(func() {
+				terraform.Platform("lambda")
+			})
+		})
+	})
+}
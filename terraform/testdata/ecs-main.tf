@@ -0,0 +1,80 @@
+terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+resource "aws_cloudwatch_log_group" "this" {
+  name = "/ecs/calc-server"
+}
+
+resource "aws_ecs_task_definition" "this" {
+  family                   = "calc-server"
+  requires_compatibilities = ["FARGATE"]
+  network_mode             = "awsvpc"
+  cpu                      = var.cpu
+  memory                   = var.memory
+  execution_role_arn       = var.execution_role_arn
+  container_definitions = jsonencode([{
+    name      = "calc-server"
+    image     = var.image
+    essential = true
+    command = [
+      "--host=${var.host}",
+    ]
+    portMappings = [
+      { name = "http", containerPort = 8443, protocol = "tcp" },
+    ]
+    secrets = [
+      { name = "JWT_SECRET", valueFrom = var.jwt_secret_arn },
+      { name = "BASIC_SECRET", valueFrom = var.basic_secret_arn },
+    ]
+    logConfiguration = {
+      logDriver = "awslogs"
+      options = {
+        awslogs-group         = aws_cloudwatch_log_group.this.name
+        awslogs-region        = var.region
+        awslogs-stream-prefix = "calc-server"
+      }
+    }
+  }])
+}
+
+resource "aws_ecs_service" "this" {
+  name            = "calc-server"
+  cluster         = var.cluster_arn
+  task_definition = aws_ecs_task_definition.this.arn
+  desired_count   = var.desired_count
+  launch_type     = "FARGATE"
+
+  network_configuration {
+    subnets         = var.subnets
+    security_groups = var.security_groups
+  }
+}
+
+resource "aws_apigatewayv2_api" "this" {
+  name          = "calc-server"
+  protocol_type = "HTTP"
+}
+
+resource "aws_apigatewayv2_integration" "this" {
+  api_id             = aws_apigatewayv2_api.this.id
+  integration_type   = "HTTP_PROXY"
+  integration_method = "ANY"
+  integration_uri    = "${var.backend_url}/{proxy}"
+}
+
+resource "aws_apigatewayv2_route" "this" {
+  api_id    = aws_apigatewayv2_api.this.id
+  route_key = "ANY /{proxy+}"
+  target    = "integrations/${aws_apigatewayv2_integration.this.id}"
+}
+
+resource "aws_apigatewayv2_stage" "this" {
+  api_id      = aws_apigatewayv2_api.this.id
+  name        = "$default"
+  auto_deploy = true
+}
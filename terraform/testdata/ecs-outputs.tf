@@ -0,0 +1,9 @@
+output "service_name" {
+  description = "Name of the ECS service."
+  value       = aws_ecs_service.this.name
+}
+
+output "gateway_url" {
+  description = "URL of the API gateway."
+  value       = aws_apigatewayv2_api.this.api_endpoint
+}
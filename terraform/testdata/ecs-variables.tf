@@ -0,0 +1,70 @@
+variable "image" {
+  description = "Container image of the server."
+  type        = string
+  default     = "123456789012.dkr.ecr.eu-west-1.amazonaws.com/calc:1.2.0"
+}
+
+variable "region" {
+  description = "Region the server is deployed to."
+  type        = string
+}
+
+variable "host" {
+  description = "Server host (valid values: production)."
+  type        = string
+  default     = "production"
+}
+
+variable "jwt_secret_arn" {
+  description = "ARN of the Secrets Manager secret holding the secret of the jwt security scheme."
+  type        = string
+}
+
+variable "basic_secret_arn" {
+  description = "ARN of the Secrets Manager secret holding the secret of the basic security scheme."
+  type        = string
+}
+
+variable "cluster_arn" {
+  description = "ARN of the ECS cluster running the service."
+  type        = string
+}
+
+variable "subnets" {
+  description = "Subnets of the service tasks."
+  type        = list(string)
+}
+
+variable "security_groups" {
+  description = "Security groups of the service tasks."
+  type        = list(string)
+  default     = []
+}
+
+variable "execution_role_arn" {
+  description = "ARN of the task execution role, it must be allowed to read the secrets."
+  type        = string
+}
+
+variable "cpu" {
+  description = "CPU units of the task."
+  type        = number
+  default     = 256
+}
+
+variable "memory" {
+  description = "Memory of the task in MiB."
+  type        = number
+  default     = 512
+}
+
+variable "desired_count" {
+  description = "Number of tasks."
+  type        = number
+  default     = 1
+}
+
+variable "backend_url" {
+  description = "URL of the load balancer or service discovery endpoint the API gateway forwards the requests to."
+  type        = string
+}
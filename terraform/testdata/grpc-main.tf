@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    google = {
+      source = "hashicorp/google"
+    }
+  }
+}
+
+resource "google_cloud_run_service" "this" {
+  name     = "calc"
+  project  = var.project
+  location = var.region
+
+  template {
+    spec {
+      containers {
+        image = var.image
+        args = [
+          "--host=${var.host}",
+        ]
+
+        ports {
+          name           = "h2c"
+          container_port = 8080
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,4 @@
+output "url" {
+  description = "URL of the Cloud Run service."
+  value       = google_cloud_run_service.this.status[0].url
+}
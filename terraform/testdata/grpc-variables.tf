@@ -0,0 +1,20 @@
+variable "project" {
+  description = "Google Cloud project ID."
+  type        = string
+}
+
+variable "image" {
+  description = "Container image of the server."
+  type        = string
+}
+
+variable "region" {
+  description = "Region the server is deployed to."
+  type        = string
+}
+
+variable "host" {
+  description = "Server host (valid values: local)."
+  type        = string
+  default     = "local"
+}
@@ -0,0 +1,48 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/timeout/expr"
+
+	// Register code generators for the timeout plugin
+	_ "goa.design/plugins/v3/timeout"
+)
+
+// Timeout sets the maximum duration of the requests made to the enclosing
+// service or method. A method timeout overrides the timeout of its service.
+//
+// The generated HTTP handlers set the deadline of the request context passed
+// to the service and respond with 504 Gateway Timeout when the method returns
+// an error after the deadline is exceeded. The generated HTTP clients set the
+// same deadline on the requests they make. The timeout is documented in the
+// OpenAPI specification with the x-timeout extension.
+//
+// Timeout must appear in a Service or Method expression.
+//
+// Example:
+//
+//    import timeout "goa.design/plugins/v3/timeout/dsl"
+//
+//    var _ = Service("catalog", func() {
+//        timeout.Timeout(5 * time.Second)
+//        Method("export", func() {
+//            timeout.Timeout(time.Minute) // Overrides the service timeout
+//            HTTP(func() {
+//                GET("/export")
+//            })
+//        })
+//    })
+//
+func Timeout(d time.Duration) {
+	switch actual := eval.Current().(type) {
+	case *goaexpr.ServiceExpr:
+		expr.Root.Timeouts = append(expr.Root.Timeouts, &expr.TimeoutExpr{Duration: d, Service: actual})
+	case *goaexpr.MethodExpr:
+		expr.Root.Timeouts = append(expr.Root.Timeouts, &expr.TimeoutExpr{Duration: d, Method: actual})
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,65 @@
+package expr
+
+import (
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the timeouts defined in the design.
+	RootExpr struct {
+		// Timeouts lists the service and method timeouts.
+		Timeouts []*TimeoutExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "timeout plugin"
+}
+
+// WalkSets iterates over the timeouts.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	texps := make(eval.ExpressionSet, len(r.Timeouts))
+	for i, t := range r.Timeouts {
+		texps[i] = t
+	}
+	walk(texps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/timeout/dsl"}
+}
+
+// Timeout returns the timeout of the given method: the method timeout if
+// defined, the service timeout otherwise. It returns 0 if neither is defined.
+func (r *RootExpr) Timeout(svc, method string) time.Duration {
+	var d time.Duration
+	for _, t := range r.Timeouts {
+		switch {
+		case t.Method != nil && t.Method.Service.Name == svc && t.Method.Name == method:
+			return t.Duration
+		case t.Service != nil && t.Service.Name == svc:
+			d = t.Duration
+		}
+	}
+	return d
+}
@@ -0,0 +1,44 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// TimeoutExpr describes the maximum duration of the requests made to a
+	// service or to a method.
+	TimeoutExpr struct {
+		// Duration is the timeout.
+		Duration time.Duration
+		// Service is the service the timeout applies to, nil for method
+		// timeouts.
+		Service *expr.ServiceExpr
+		// Method is the method the timeout applies to, nil for service
+		// timeouts.
+		Method *expr.MethodExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (t *TimeoutExpr) EvalName() string {
+	if t.Method != nil {
+		return fmt.Sprintf("timeout of %s", t.Method.EvalName())
+	}
+	return fmt.Sprintf("timeout of %s", t.Service.EvalName())
+}
+
+// Validate makes sure the timeout is positive.
+func (t *TimeoutExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if t.Duration <= 0 {
+		verr.Add(t, "timeout must be positive")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,135 @@
+package timeout
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/genutil"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/timeout/expr"
+	"goa.design/plugins/v3/walk"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "timeout",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate sets the deadline of the requests handled by the HTTP servers and
+// made by the HTTP clients of the methods that have a timeout and documents
+// the timeouts in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Timeouts) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("timeout", f) {
+			continue
+		}
+		serverTimeout(f)
+		clientTimeout(f)
+		documentTimeout(f)
+	}
+	return files, nil
+}
+
+// serverTimeout sets the deadline of the request contexts in the HTTP
+// handlers of the methods that have a timeout and maps the errors returned
+// after the deadline to timeout errors.
+func serverTimeout(f *codegen.File) {
+	if filepath.Base(f.Path) != "server.go" {
+		return
+	}
+	for _, s := range f.Section("server-handler-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || ed.ServerStream != nil {
+			continue
+		}
+		d := expr.Root.Timeout(ed.ServiceName, ed.Method.Name)
+		if d == 0 {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "time"},
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/timeout"})
+		svc := "ctx = context.WithValue(ctx, goa.ServiceKey, {{ printf \"%q\" .ServiceName }})"
+		s.Source = strings.Replace(s.Source, svc,
+			svc+"\n\t\tctx, cancel := context.WithTimeout(ctx, "+genutil.DurationCode(d)+")\n\t\tdefer cancel()", 1)
+		call := "res, err := endpoint(ctx, {{ if .Payload.Ref }}payload{{ else }}nil{{ end }})"
+		s.Source = strings.Replace(s.Source, call, call+"\n\t\terr = timeout.Error(ctx, err)", 1)
+	}
+}
+
+// clientTimeout sets the deadline of the requests made by the HTTP client
+// endpoints of the methods that have a timeout.
+func clientTimeout(f *codegen.File) {
+	if filepath.Base(f.Path) != "client.go" {
+		return
+	}
+	for _, s := range f.Section("client-endpoint-init") {
+		ed, ok := s.Data.(*httpcodegen.EndpointData)
+		if !ok || ed.ClientStream != nil {
+			continue
+		}
+		d := expr.Root.Timeout(ed.ServiceName, ed.Method.Name)
+		if d == 0 {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Path: "time"})
+		decl := "return func(ctx context.Context, v interface{}) (interface{}, error) {"
+		s.Source = strings.Replace(s.Source, decl,
+			decl+"\n\t\tctx, cancel := context.WithTimeout(ctx, "+genutil.DurationCode(d)+")\n\t\tdefer cancel()", 1)
+	}
+}
+
+// documentTimeout adds the x-timeout extension and the response returned when
+// the deadline is exceeded to the operations that have a timeout if f is an
+// OpenAPI file.
+func documentTimeout(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("timeout", op) {
+				return nil
+			}
+			d := timeout(op)
+			if d == 0 {
+				return nil
+			}
+			if op.Extensions == nil {
+				op.Extensions = make(map[string]interface{})
+			}
+			op.Extensions["x-timeout"] = d.String()
+			status := strconv.Itoa(http.StatusGatewayTimeout)
+			if _, ok := op.Responses[status]; !ok {
+				op.Responses[status] = &openapi.Response{
+					Description: fmt.Sprintf("%s response returned when the request takes longer than %s.", http.StatusText(http.StatusGatewayTimeout), d),
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// timeout returns the timeout of the method corresponding to the given
+// operation, 0 if the method has none.
+func timeout(op *openapi.Operation) time.Duration {
+	m := walk.OperationMethod(goaexpr.Root, op)
+	if m == nil {
+		return 0
+	}
+	return expr.Root.Timeout(m.Service.Name, m.Name)
+}
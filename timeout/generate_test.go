@@ -0,0 +1,64 @@
+package timeout_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/timeout"
+	"goa.design/plugins/v3/timeout/expr"
+	"goa.design/plugins/v3/timeout/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	expr.Root.Timeouts = nil
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	fs := append(httpcodegen.ServerFiles("", goaexpr.Root), httpcodegen.ClientFiles("", goaexpr.Root)...)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err = timeout.Generate("", []eval.Root{goaexpr.Root}, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range fs {
+		switch f.Path {
+		case filepath.Join("gen", "http", "catalog", "server", "server.go"):
+			code := codegen.SectionCode(t, f.Section("server-handler-init")[0])
+			if code != testdata.ListHandlerCode {
+				t.Errorf("invalid server handler code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ListHandlerCode))
+			}
+		case filepath.Join("gen", "http", "catalog", "client", "client.go"):
+			code := codegen.SectionCode(t, f.Section("client-endpoint-init")[1])
+			if code != testdata.ExportClientEndpointCode {
+				t.Errorf("invalid client endpoint code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.ExportClientEndpointCode))
+			}
+		case filepath.Join("gen", "http", "health", "server", "server.go"):
+			for _, s := range f.SectionTemplates {
+				if _, ok := s.Data.(*httpcodegen.EndpointData); ok && s.Name == "server-handler-init" {
+					if code := codegen.SectionCode(t, s); strings.Contains(code, "WithTimeout") {
+						t.Errorf("unexpected timeout in handler without timeout:\n%s", code)
+					}
+				}
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	cases := map[string]string{"/items": "5s", "/export": "1m30s", "/health": ""}
+	for path, expected := range cases {
+		op := spec.Paths[path].(*openapi.Path).Get
+		got, _ := op.Extensions["x-timeout"].(string)
+		if got != expected {
+			t.Errorf("%s: got x-timeout %q, expected %q", path, got, expected)
+		}
+		if _, ok := op.Responses["504"]; ok != (expected != "") {
+			t.Errorf("%s: got 504 response %v, expected %v", path, ok, expected != "")
+		}
+	}
+}
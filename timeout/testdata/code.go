@@ -0,0 +1,65 @@
+package testdata
+
+var ListHandlerCode = `// NewListHandler creates a HTTP handler which loads the HTTP request and calls
+// the "Catalog" service "List" endpoint.
+func NewListHandler(
+	endpoint goa.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		encodeResponse = EncodeListResponse(enc)
+		encodeError    = goahttp.ErrorEncoder(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "List")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "Catalog")
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		res, err := endpoint(ctx, nil)
+		err = timeout.Error(ctx, err)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+`
+
+var ExportClientEndpointCode = `// Export returns an endpoint that makes HTTP requests to the Catalog service
+// Export server.
+func (c *Client) Export() goa.Endpoint {
+	var (
+		encodeRequest  = EncodeExportRequest(c.encoder)
+		decodeResponse = DecodeExportResponse(c.decoder, c.RestoreResponseBody)
+	)
+	return func(ctx context.Context, v interface{}) (interface{}, error) {
+		ctx, cancel := context.WithTimeout(ctx, 90*time.Second)
+		defer cancel()
+		req, err := c.BuildExportRequest(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		err = encodeRequest(req, v)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.ExportDoer.Do(req)
+
+		if err != nil {
+			return nil, goahttp.ErrRequestError("Catalog", "Export", err)
+		}
+		return decodeResponse(resp)
+	}
+}
+`
@@ -0,0 +1,41 @@
+package testdata
+
+import (
+	"time"
+
+	. "goa.design/goa/v3/dsl"
+	timeout "goa.design/plugins/v3/timeout/dsl"
+)
+
+var CatalogDSL = func() {
+	Service("Catalog", func() {
+		timeout.Timeout(5 * time.Second)
+		Method("List", func() {
+			Result(ArrayOf(String))
+			HTTP(func() {
+				GET("/items")
+				Response(StatusOK)
+			})
+		})
+		Method("Export", func() {
+			timeout.Timeout(90 * time.Second)
+			Payload(func() {
+				Attribute("format", String)
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/export")
+				Param("format")
+			})
+		})
+	})
+	Service("Health", func() {
+		Method("Check", func() {
+			Result(String)
+			HTTP(func() {
+				GET("/health")
+				Response(StatusOK)
+			})
+		})
+	})
+}
@@ -0,0 +1,20 @@
+package timeout
+
+import (
+	"context"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+// ErrorName is the name of the error returned by Error.
+const ErrorName = "timeout"
+
+// Error returns the error rendered with the 504 Gateway Timeout status by the
+// goa HTTP error encoder if err is not nil and the deadline of ctx is
+// exceeded. It returns err otherwise.
+func Error(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return goa.TemporaryTimeoutError(ErrorName, "request timed out: %s", err.Error())
+}
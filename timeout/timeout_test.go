@@ -0,0 +1,48 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestError(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	errFailed := errors.New("failed")
+	cases := []struct {
+		Name    string
+		Ctx     context.Context
+		Err     error
+		Timeout bool
+	}{
+		{"no-error", expired, nil, false},
+		{"not-expired", context.Background(), errFailed, false},
+		{"canceled", canceled, errFailed, false},
+		{"expired", expired, errFailed, true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := Error(c.Ctx, c.Err)
+			if !c.Timeout {
+				if err != c.Err {
+					t.Errorf("got error %v, expected %v", err, c.Err)
+				}
+				return
+			}
+			serr, ok := err.(*goa.ServiceError)
+			if !ok {
+				t.Fatalf("got error %T, expected *goa.ServiceError", err)
+			}
+			if serr.Name != ErrorName || !serr.Timeout || !serr.Temporary {
+				t.Errorf("got error %+v, expected temporary timeout error", serr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/validation/expr"
+
+	// Register code generators for the validation plugin
+	_ "goa.design/plugins/v3/validation"
+)
+
+// CustomFormat validates the attribute value using the named custom format.
+// The generated Validators struct of each service using the attribute in a
+// method payload has a field holding the function implementing the format.
+// The same format may be used by any number of attributes. The OpenAPI
+// specification documents the format name in the attribute schema.
+//
+// CustomFormat must appear in an Attribute expression and applies to String
+// attributes only.
+//
+// Example:
+//
+//    import validation "goa.design/plugins/v3/validation/dsl"
+//
+//    var Card = Type("Card", func() {
+//        Attribute("number", String, func() {
+//            validation.CustomFormat("credit-card")
+//        })
+//    })
+//
+func CustomFormat(name string) {
+	att, ok := eval.Current().(*goaexpr.AttributeExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	att.Meta[expr.FormatKey] = []string{name}
+	expr.Root.Formats = append(expr.Root.Formats, &expr.FormatExpr{Name: name, Attribute: att})
+}
+
+// Rule defines a semantic check spanning multiple attributes of an object.
+// The generated Validators struct of each service using the object in a
+// method payload has a field holding the function implementing the check.
+// The description and the names of the checked attributes are documented in
+// the OpenAPI specification. Rule names must be unique in the design.
+//
+// Rule must appear in a Type, ResultType, Payload or Attribute expression
+// describing an object.
+//
+// Example:
+//
+//    var Period = Type("Period", func() {
+//        Attribute("start", String, func() {
+//            Format(FormatDateTime)
+//        })
+//        Attribute("end", String, func() {
+//            Format(FormatDateTime)
+//        })
+//        validation.Rule("period-order", "start must be before end", "start", "end")
+//    })
+//
+func Rule(name, description string, attributes ...string) {
+	var att *goaexpr.AttributeExpr
+	switch e := eval.Current().(type) {
+	case *goaexpr.AttributeExpr:
+		att = e
+	case *goaexpr.ResultTypeExpr:
+		att = e.AttributeExpr
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(attributes) == 0 {
+		eval.ReportError("rule %q has no attribute", name)
+		return
+	}
+	if att.Meta == nil {
+		att.Meta = make(goaexpr.MetaExpr)
+	}
+	att.Meta[expr.RuleKey] = append(att.Meta[expr.RuleKey], name)
+	expr.Root.Rules = append(expr.Root.Rules, &expr.RuleExpr{
+		Name:        name,
+		Description: description,
+		Attributes:  attributes,
+		Attribute:   att,
+	})
+}
+
+// SpecValidation generates a HTTP middleware validating the incoming requests
+// against the OpenAPI specification of the API before they reach the goa
+// handlers. The middleware is a defense-in-depth layer that checks the
+// parameters and bodies of the documented operations with a generic OpenAPI
+// validator.
+//
+// SpecValidation must appear in the API expression.
+//
+// Example:
+//
+//    var _ = API("bookings", func() {
+//        validation.SpecValidation()
+//    })
+//
+func SpecValidation() {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.SpecValidation = true
+}
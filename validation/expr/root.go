@@ -0,0 +1,100 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+const (
+	// FormatKey is the meta key recording the custom format of an
+	// attribute. The meta is copied together with the attribute when goa
+	// builds the service and transport types.
+	FormatKey = "validation:format"
+	// RuleKey is the meta key recording the names of the rules of an
+	// object attribute.
+	RuleKey = "validation:rule"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the custom validations defined in the design.
+	RootExpr struct {
+		// Formats lists the uses of custom formats in the order they
+		// appear in the design.
+		Formats []*FormatExpr
+		// Rules lists the validation rules in the order they appear in
+		// the design.
+		Rules []*RuleExpr
+		// SpecValidation is true if the requests are validated against
+		// the OpenAPI specification of the API.
+		SpecValidation bool
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "validation plugin"
+}
+
+// WalkSets iterates over the custom formats and the validation rules.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	fexps := make(eval.ExpressionSet, len(r.Formats))
+	for i, f := range r.Formats {
+		fexps[i] = f
+	}
+	walk(fexps)
+	rexps := make(eval.ExpressionSet, len(r.Rules))
+	for i, rule := range r.Rules {
+		rexps[i] = rule
+	}
+	walk(rexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/validation/dsl"}
+}
+
+// Rule returns the validation rule with the given name, nil if there is none.
+func (r *RootExpr) Rule(name string) *RuleExpr {
+	for _, rule := range r.Rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Format returns the name of the custom format of the given attribute, the
+// empty string if the attribute does not use a custom format.
+func Format(att *expr.AttributeExpr) string {
+	if f, ok := att.Meta[FormatKey]; ok && len(f) > 0 {
+		return f[0]
+	}
+	return ""
+}
+
+// Rules returns the validation rules of the given object attribute.
+func Rules(att *expr.AttributeExpr) []*RuleExpr {
+	var rules []*RuleExpr
+	for _, name := range att.Meta[RuleKey] {
+		if r := Root.Rule(name); r != nil {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,85 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// FormatExpr describes the use of a custom format by an attribute.
+	FormatExpr struct {
+		// Name is the name of the format.
+		Name string
+		// Attribute is the attribute using the format.
+		Attribute *expr.AttributeExpr
+	}
+
+	// RuleExpr describes a semantic check spanning multiple attributes of
+	// an object.
+	RuleExpr struct {
+		// Name is the name of the rule.
+		Name string
+		// Description describes the check.
+		Description string
+		// Attributes lists the names of the checked attributes.
+		Attributes []string
+		// Attribute is the object attribute the rule applies to.
+		Attribute *expr.AttributeExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (f *FormatExpr) EvalName() string {
+	return fmt.Sprintf("custom format %q", f.Name)
+}
+
+// Validate makes sure the format applies to a string attribute.
+func (f *FormatExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if f.Name == "" {
+		verr.Add(f, "custom format name cannot be empty")
+	}
+	if f.Attribute.Type != expr.String {
+		verr.Add(f, "custom formats apply to String attributes only")
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (r *RuleExpr) EvalName() string {
+	return fmt.Sprintf("validation rule %q", r.Name)
+}
+
+// Validate makes sure the rule name is unique and that the checked attributes
+// exist.
+func (r *RuleExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if r.Name == "" {
+		verr.Add(r, "rule name cannot be empty")
+	}
+	for _, rule := range Root.Rules {
+		if rule != r && rule.Name == r.Name {
+			verr.Add(r, "rule %q is defined more than once", r.Name)
+			break
+		}
+	}
+	o := expr.AsObject(r.Attribute.Type)
+	if o == nil {
+		verr.Add(r, "rules apply to object attributes only")
+		return verr
+	}
+	for _, name := range r.Attributes {
+		if o.Attribute(name) == nil {
+			verr.Add(r, "attribute %q is not defined", name)
+		}
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,515 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/validation/expr"
+	"goa.design/plugins/v3/walk"
+)
+
+type (
+	// fileData contains the data necessary to render the custom validation
+	// code of a service.
+	fileData struct {
+		// Validators lists the fields of the Validators struct.
+		Validators []*validatorData
+		// Methods lists the methods whose payload uses custom
+		// validations.
+		Methods []*methodData
+		// Types lists the types using custom validations.
+		Types []*typeData
+	}
+
+	// validatorData describes a field of the Validators struct.
+	validatorData struct {
+		// Name is the field name.
+		Name string
+		// Description is the field documentation.
+		Description string
+		// Type is the signature of the validation function.
+		Type string
+	}
+
+	// methodData describes a method whose payload uses custom
+	// validations.
+	methodData struct {
+		// Name is the method name.
+		Name string
+		// VarName is the name of the method endpoint field.
+		VarName string
+		// PayloadRef is the reference to the payload type.
+		PayloadRef string
+		// Check is the name of the function validating the payload.
+		Check string
+		// Entries lists the custom validations documented in the OpenAPI
+		// specification.
+		Entries []*entryData
+	}
+
+	// typeData describes the custom validations of a type.
+	typeData struct {
+		// Name is the name of the type.
+		Name string
+		// Ref is the reference to the type.
+		Ref string
+		// Check is the name of the function validating the type.
+		Check string
+		// Formats lists the attributes using custom formats.
+		Formats []*formatData
+		// Nested lists the attributes whose type uses custom
+		// validations.
+		Nested []*nestedData
+		// Rules lists the rules applying to the type.
+		Rules []*ruleData
+	}
+
+	// formatData describes an attribute using a custom format.
+	formatData struct {
+		// Attribute is the attribute name.
+		Attribute string
+		// Field is the name of the struct field.
+		Field string
+		// Pointer is true if the field is a pointer.
+		Pointer bool
+		// Format is the name of the format.
+		Format string
+		// Validator is the name of the Validators field.
+		Validator string
+	}
+
+	// nestedData describes an attribute whose type uses custom
+	// validations.
+	nestedData struct {
+		// Attribute is the attribute name.
+		Attribute string
+		// Field is the name of the struct field.
+		Field string
+		// Type is the data of the attribute type.
+		Type *typeData
+	}
+
+	// ruleData describes a rule applying to a type.
+	ruleData struct {
+		// Name is the name of the rule.
+		Name string
+		// Validator is the name of the Validators field.
+		Validator string
+		// Rule is the rule expression.
+		Rule *expr.RuleExpr
+	}
+
+	// entryData describes a custom validation in the x-validation OpenAPI
+	// extension.
+	entryData struct {
+		// Name is the name of the format or rule.
+		Name string `json:"name" yaml:"name"`
+		// Kind is "format" or "rule".
+		Kind string `json:"kind" yaml:"kind"`
+		// Description is the rule description.
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+		// Attributes lists the paths to the validated attributes.
+		Attributes []string `json:"attributes" yaml:"attributes"`
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "validation",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the code running the custom validations of the method
+// payloads, documents the custom validations in the OpenAPI specification
+// and produces the middleware validating the requests against the
+// specification if enabled.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if expr.Root.SpecValidation {
+		if f := specValidationFile(files); f != nil {
+			files = append(files, f)
+		}
+	}
+	if len(expr.Root.Formats) == 0 && len(expr.Root.Rules) == 0 {
+		return files, nil
+	}
+	methods := make(map[string]*methodData)
+	for _, root := range roots {
+		if r, ok := root.(*goaexpr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if !config.Enabled("validation", svc.Name) {
+					continue
+				}
+				data := buildFileData(svc)
+				if len(data.Methods) == 0 {
+					continue
+				}
+				for _, m := range data.Methods {
+					methods[fmt.Sprintf("%s#%s", svc.Name, m.Name)] = m
+				}
+				files = append(files, validationFile(svc, data))
+			}
+		}
+	}
+	for _, f := range files {
+		if !config.EnabledFile("validation", f) {
+			continue
+		}
+		documentValidations(f, methods)
+	}
+	return files, nil
+}
+
+// validationFile returns the file defining the Validators struct of the
+// service and the functions running the custom validations.
+func validationFile(svc *goaexpr.ServiceExpr, data *fileData) *codegen.File {
+	sd := service.Services.Get(svc.Name)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(svc.Name+" service custom validations", sd.PkgName, []*codegen.ImportSpec{
+			{Path: "context"},
+			codegen.GoaImport(""),
+		}),
+		{Name: "validation-validators", Source: validatorsT, Data: data},
+	}
+	for _, m := range data.Methods {
+		sections = append(sections, &codegen.SectionTemplate{Name: "validation-endpoint", Source: endpointT, Data: m})
+	}
+	for _, t := range data.Types {
+		sections = append(sections, &codegen.SectionTemplate{Name: "validation-check", Source: checkT, Data: t})
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, codegen.SnakeCase(sd.VarName), "validation.go"),
+		SectionTemplates: sections,
+	}
+}
+
+// specValidationFile returns the file defining the middleware validating the
+// requests against the OpenAPI specification, nil if there is no
+// specification. The specification is serialized when the file is rendered so
+// that it includes the changes made by the other plugins.
+func specValidationFile(files []*codegen.File) *codegen.File {
+	for _, f := range files {
+		for _, s := range f.Section("openapi") {
+			spec, ok := s.Data.(*openapi.V2)
+			if !ok {
+				continue
+			}
+			return &codegen.File{
+				Path: filepath.Join(codegen.Gendir, "http", "specvalidation", "specvalidation.go"),
+				SectionTemplates: []*codegen.SectionTemplate{
+					codegen.Header("OpenAPI request validation", "specvalidation", []*codegen.ImportSpec{
+						{Path: "net/http"},
+						{Path: "goa.design/plugins/v3/validation"},
+					}),
+					{
+						Name:    "validation-spec",
+						Source:  specT,
+						Data:    spec,
+						FuncMap: map[string]interface{}{"toJSON": toJSON},
+					},
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// toJSON returns the Go string literal of the JSON representation of the
+// given specification.
+func toJSON(spec *openapi.V2) string {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		panic("validation: " + err.Error()) // bug
+	}
+	return strconv.Quote(string(b))
+}
+
+// buildFileData computes the data necessary to render the custom validation
+// code of the given service. Only the payloads of the methods that do not
+// stream are validated.
+func buildFileData(svc *goaexpr.ServiceExpr) *fileData {
+	var (
+		sd         = service.Services.Get(svc.Name)
+		data       = &fileData{}
+		seen       = make(map[string]*typeData)
+		validators = make(map[string]struct{})
+	)
+	addValidator := func(name, description, typ string) {
+		if _, ok := validators[name]; ok {
+			return
+		}
+		validators[name] = struct{}{}
+		data.Validators = append(data.Validators, &validatorData{Name: name, Description: description, Type: typ})
+	}
+	var collect func(ut goaexpr.UserType) *typeData
+	collect = func(ut goaexpr.UserType) *typeData {
+		if td, ok := seen[ut.ID()]; ok {
+			return td
+		}
+		seen[ut.ID()] = nil
+		att := ut.Attribute()
+		o := goaexpr.AsObject(att.Type)
+		if o == nil {
+			return nil
+		}
+		name := sd.Scope.GoTypeName(&goaexpr.AttributeExpr{Type: ut})
+		td := &typeData{
+			Name:  name,
+			Ref:   sd.Scope.GoTypeRef(&goaexpr.AttributeExpr{Type: ut}),
+			Check: "check" + name,
+		}
+		for _, nat := range *o {
+			if f := expr.Format(nat.Attribute); f != "" && nat.Attribute.Type == goaexpr.String {
+				v := codegen.Goify(f, true)
+				addValidator(v, fmt.Sprintf("%s validates the values of the %q format.", v, f), "func(string) error")
+				td.Formats = append(td.Formats, &formatData{
+					Attribute: nat.Name,
+					Field:     codegen.GoifyAtt(nat.Attribute, nat.Name, true),
+					Pointer:   att.IsPrimitivePointer(nat.Name, true),
+					Format:    f,
+					Validator: v,
+				})
+			}
+			if u, ok := nat.Attribute.Type.(goaexpr.UserType); ok {
+				if nested := collect(u); nested != nil {
+					td.Nested = append(td.Nested, &nestedData{
+						Attribute: nat.Name,
+						Field:     codegen.GoifyAtt(nat.Attribute, nat.Name, true),
+						Type:      nested,
+					})
+				}
+			}
+		}
+		for _, r := range expr.Rules(att) {
+			v := codegen.Goify(r.Name, true)
+			desc := fmt.Sprintf("%s implements the %q rule.", v, r.Name)
+			if r.Description != "" {
+				desc = fmt.Sprintf("%s implements the %q rule: %s", v, r.Name, r.Description)
+			}
+			addValidator(v, desc, "func("+td.Ref+") error")
+			td.Rules = append(td.Rules, &ruleData{Name: r.Name, Validator: v, Rule: r})
+		}
+		if len(td.Formats) == 0 && len(td.Nested) == 0 && len(td.Rules) == 0 {
+			return nil
+		}
+		seen[ut.ID()] = td
+		data.Types = append(data.Types, td)
+		return td
+	}
+	for _, m := range svc.Methods {
+		if m.IsStreaming() {
+			continue
+		}
+		ut, ok := m.Payload.Type.(goaexpr.UserType)
+		if !ok {
+			continue
+		}
+		td := collect(ut)
+		if td == nil {
+			continue
+		}
+		md := sd.Method(m.Name)
+		data.Methods = append(data.Methods, &methodData{
+			Name:       m.Name,
+			VarName:    md.VarName,
+			PayloadRef: md.PayloadRef,
+			Check:      td.Check,
+			Entries:    entries(td, "", make(map[*typeData]struct{})),
+		})
+	}
+	return data
+}
+
+// entries returns the custom validations of the given type documented in the
+// x-validation OpenAPI extension. prefix is the path to the type attribute.
+func entries(td *typeData, prefix string, seen map[*typeData]struct{}) []*entryData {
+	if _, ok := seen[td]; ok {
+		return nil
+	}
+	seen[td] = struct{}{}
+	var es []*entryData
+	for _, f := range td.Formats {
+		es = append(es, &entryData{Name: f.Format, Kind: "format", Attributes: []string{prefix + f.Attribute}})
+	}
+	for _, r := range td.Rules {
+		atts := make([]string, len(r.Rule.Attributes))
+		for i, a := range r.Rule.Attributes {
+			atts[i] = prefix + a
+		}
+		es = append(es, &entryData{Name: r.Name, Kind: "rule", Description: r.Rule.Description, Attributes: atts})
+	}
+	for _, n := range td.Nested {
+		es = append(es, entries(n.Type, prefix+n.Attribute+".", seen)...)
+	}
+	return es
+}
+
+// documentValidations sets the custom formats of the request parameters and
+// body schemas and adds the x-validation extension to the operations if f is
+// an OpenAPI file.
+func documentValidations(f *codegen.File, methods map[string]*methodData) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("validation", op) {
+				return nil
+			}
+			documentFormats(spec, op)
+			if m, ok := methods[op.OperationID]; ok && len(m.Entries) > 0 {
+				if op.Extensions == nil {
+					op.Extensions = make(map[string]interface{})
+				}
+				op.Extensions["x-validation"] = m.Entries
+			}
+			return nil
+		})
+	})
+}
+
+// documentFormats sets the custom formats of the parameters and body schema
+// of the given operation.
+func documentFormats(spec *openapi.V2, op *openapi.Operation) {
+	e := walk.OperationEndpoint(goaexpr.Root, op)
+	if e == nil {
+		return
+	}
+	for _, param := range op.Parameters {
+		var ma *goaexpr.MappedAttributeExpr
+		switch param.In {
+		case "path", "query":
+			ma = e.Params
+		case "header":
+			ma = e.Headers
+		case "body":
+			if e.Body != nil {
+				documentSchema(spec, e.Body, param.Schema, make(map[*openapi.Schema]struct{}))
+			}
+			continue
+		}
+		if ma == nil || goaexpr.AsObject(ma.Type) == nil {
+			continue
+		}
+		goaexpr.WalkMappedAttr(ma, func(name, elem string, att *goaexpr.AttributeExpr) error {
+			if elem == param.Name {
+				if f := expr.Format(att); f != "" {
+					param.Format = f
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// documentSchema sets the custom formats of the given schema and of its
+// properties and items recursively.
+func documentSchema(spec *openapi.V2, att *goaexpr.AttributeExpr, s *openapi.Schema, seen map[*openapi.Schema]struct{}) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		s = spec.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if s == nil {
+			return
+		}
+	}
+	if _, ok := seen[s]; ok {
+		return
+	}
+	seen[s] = struct{}{}
+	if f := expr.Format(att); f != "" {
+		s.Format = f
+	}
+	if o := goaexpr.AsObject(att.Type); o != nil {
+		for _, nat := range *o {
+			documentSchema(spec, nat.Attribute, s.Properties[nat.Name], seen)
+		}
+	}
+	if a := goaexpr.AsArray(att.Type); a != nil {
+		documentSchema(spec, a.ElemType, s.Items, seen)
+	}
+}
+
+// input: fileData
+const validatorsT = `// Validators lists the implementations of the custom validations used by the
+// service method payloads. All the fields must be set.
+type Validators struct {
+{{- range .Validators }}
+	{{ comment .Description }}
+	{{ .Name }} {{ .Type }}
+{{- end }}
+}
+
+// UseValidators wraps the endpoints of the methods whose payload uses custom
+// validations so that the validations run before the methods are called.
+func UseValidators(e *Endpoints, v *Validators) {
+{{- range .Methods }}
+	e.{{ .VarName }} = validate{{ .VarName }}(e.{{ .VarName }}, v)
+{{- end }}
+}
+`
+
+// input: methodData
+const endpointT = `{{ printf "validate%s runs the custom validations of the %q method payload before calling the endpoint." .VarName .Name | comment }}
+func validate{{ .VarName }}(ep goa.Endpoint, v *Validators) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		if err := {{ .Check }}(req.({{ .PayloadRef }}), v); err != nil {
+			return nil, err
+		}
+		return ep(ctx, req)
+	}
+}
+`
+
+// input: typeData
+const checkT = `{{ printf "%s runs the custom validations of the %s type." .Check .Name | comment }}
+func {{ .Check }}(v {{ .Ref }}, vs *Validators) (err error) {
+{{- range .Formats }}
+	{{- if .Pointer }}
+	if v.{{ .Field }} != nil {
+		if err2 := vs.{{ .Validator }}(*v.{{ .Field }}); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidFormatError({{ printf "%q" .Attribute }}, *v.{{ .Field }}, {{ printf "%q" .Format }}, err2))
+		}
+	}
+	{{- else }}
+	if err2 := vs.{{ .Validator }}(v.{{ .Field }}); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidFormatError({{ printf "%q" .Attribute }}, v.{{ .Field }}, {{ printf "%q" .Format }}, err2))
+	}
+	{{- end }}
+{{- end }}
+{{- range .Nested }}
+	if v.{{ .Field }} != nil {
+		err = goa.MergeErrors(err, {{ .Type.Check }}(v.{{ .Field }}, vs))
+	}
+{{- end }}
+{{- range .Rules }}
+	if err2 := vs.{{ .Validator }}(v); err2 != nil {
+		err = goa.MergeErrors(err, goa.PermanentError("invalid_rule", "%s: %s", {{ printf "%q" .Name }}, err2))
+	}
+{{- end }}
+	return
+}
+`
+
+// input: *openapi.V2
+const specT = `// Spec is the OpenAPI specification of the API in JSON used to validate the
+// requests.
+const Spec = {{ toJSON . }}
+
+// Middleware returns a HTTP middleware validating the requests against Spec
+// before they reach the handlers. The requests that fail validation are
+// answered with 400 Bad Request.
+func Middleware() (func(http.Handler) http.Handler, error) {
+	return validation.SpecMiddleware([]byte(Spec))
+}
+`
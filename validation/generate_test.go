@@ -0,0 +1,116 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/codegen/service"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/validation"
+	"goa.design/plugins/v3/validation/expr"
+	"goa.design/plugins/v3/validation/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		Name     string
+		DSL      func()
+		Path     string
+		Code     string
+		Route    string
+		Property [2]string
+		Entries  []string
+	}{
+		{"format", testdata.FormatDSL, "gen/payments/validation.go", testdata.FormatCode, "/payments", [2]string{"CardRequestBody", "number"}, []string{"coupon-code", "credit-card"}},
+		{"rule", testdata.RuleDSL, "gen/bookings/validation.go", testdata.RuleCode, "/bookings", [2]string{"BookingsBookRequestBody", "room"}, []string{"room-code", "period-order"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			expr.Root.Formats, expr.Root.Rules = nil, nil
+			service.Services = make(service.ServicesData)
+			httpcodegen.RunHTTPDSL(t, c.DSL)
+			ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fs, err := validation.Generate("gen", []eval.Root{goaexpr.Root}, ofs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := fs[len(fs)-1]
+			if f.Path != c.Path {
+				t.Fatalf("got file %q, expected %q", f.Path, c.Path)
+			}
+			var sections []string
+			for _, s := range f.SectionTemplates[1:] {
+				sections = append(sections, codegen.SectionCode(t, s))
+			}
+			code := strings.Join(sections, "\n")
+			if code != c.Code {
+				t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code))
+			}
+			spec := plugintest.Spec(t, ofs)
+			plugintest.ValidateSpec(t, spec)
+			def, ok := spec.Definitions[c.Property[0]]
+			if !ok {
+				t.Fatalf("definition %q not found in OpenAPI spec", c.Property[0])
+			}
+			if p := def.Properties[c.Property[1]]; p == nil || p.Format == "" {
+				t.Errorf("custom format of property %q not found in OpenAPI spec", c.Property[1])
+			}
+			op := spec.Paths[c.Route].(*openapi.Path).Post
+			b, err := json.Marshal(op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc struct {
+				Validations []struct {
+					Name string `json:"name"`
+				} `json:"x-validation"`
+			}
+			if err := json.Unmarshal(b, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if len(doc.Validations) != len(c.Entries) {
+				t.Fatalf("got %d x-validation entries, expected %d", len(doc.Validations), len(c.Entries))
+			}
+			for i, e := range doc.Validations {
+				if e.Name != c.Entries[i] {
+					t.Errorf("got x-validation entry %q at index %d, expected %q", e.Name, i, c.Entries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSpecValidation(t *testing.T) {
+	expr.Root.Formats, expr.Root.Rules = nil, nil
+	defer func() { expr.Root.SpecValidation = false }()
+	openapi.Definitions = make(map[string]*openapi.Schema)
+	httpcodegen.RunHTTPDSL(t, testdata.SpecDSL)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := validation.Generate("gen", []eval.Root{goaexpr.Root}, ofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != len(ofs)+1 {
+		t.Fatalf("got %d files, expected %d", len(fs), len(ofs)+1)
+	}
+	f := fs[len(fs)-1]
+	if f.Path != "gen/http/specvalidation/specvalidation.go" {
+		t.Fatalf("got file %q, expected gen/http/specvalidation/specvalidation.go", f.Path)
+	}
+	code := codegen.SectionCode(t, f.SectionTemplates[1])
+	if code != testdata.SpecCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.SpecCode))
+	}
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// SpecMiddleware returns a HTTP middleware validating the requests against the
+// given OpenAPI 2 specification in JSON. The requests matching an operation of
+// the specification whose parameters or body do not validate are answered
+// with 400 Bad Request, the others are handed to the next handler. The host
+// and schemes of the specification are ignored, only the base path is used
+// to match the requests. The security requirements are left to the handlers
+// and the bodies whose content type has no registered decoder are not
+// validated.
+func SpecMiddleware(spec []byte) (func(http.Handler) http.Handler, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(spec, &doc2); err != nil {
+		return nil, err
+	}
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, err
+	}
+	doc.Servers = nil
+	if doc2.BasePath != "" && doc2.BasePath != "/" {
+		doc.Servers = openapi3.Servers{{URL: doc2.BasePath}}
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, params, err := router.FindRoute(r)
+			if err != nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if err := validateRequest(r, route, params); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// validateRequest validates the given request against the matching route of
+// the specification. The request body is restored after validation.
+func validateRequest(r *http.Request, route *routers.Route, params map[string]string) error {
+	opts := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || openapi3filter.RegisteredBodyDecoder(mt) == nil {
+			opts.ExcludeRequestBody = true
+		}
+	}
+	return openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: params,
+		Route:      route,
+		Options:    opts,
+	})
+}
@@ -0,0 +1,93 @@
+package validation_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goa.design/plugins/v3/validation"
+)
+
+const spec = `{
+  "swagger": "2.0",
+  "info": {"title": "rooms", "version": "1.0"},
+  "host": "localhost:80",
+  "basePath": "/api",
+  "paths": {
+    "/rooms/{id}": {
+      "get": {
+        "operationId": "Rooms#Show",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "integer", "minimum": 1}],
+        "responses": {"200": {"description": "OK"}}
+      }
+    },
+    "/rooms": {
+      "post": {
+        "operationId": "Rooms#Create",
+        "parameters": [{
+          "name": "CreateRequestBody",
+          "in": "body",
+          "required": true,
+          "schema": {
+            "type": "object",
+            "properties": {"name": {"type": "string", "maxLength": 5}},
+            "required": ["name"]
+          }
+        }],
+        "responses": {"201": {"description": "Created"}}
+      }
+    }
+  }
+}`
+
+func TestSpecMiddleware(t *testing.T) {
+	mw, err := validation.SpecMiddleware([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		Name        string
+		Method      string
+		Path        string
+		ContentType string
+		Body        string
+		Status      int
+	}{
+		{"valid-param", "GET", "/api/rooms/2", "", "", http.StatusOK},
+		{"invalid-param", "GET", "/api/rooms/0", "", "", http.StatusBadRequest},
+		{"valid-body", "POST", "/api/rooms", "application/json", `{"name":"blue"}`, http.StatusOK},
+		{"missing-field", "POST", "/api/rooms", "application/json", `{}`, http.StatusBadRequest},
+		{"too-long", "POST", "/api/rooms", "application/json", `{"name":"purple"}`, http.StatusBadRequest},
+		{"unknown-content-type", "POST", "/api/rooms", "application/cbor", "\xa0", http.StatusOK},
+		{"undocumented", "GET", "/health", "", "", http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var body string
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				body = string(b)
+			}))
+			r := httptest.NewRequest(c.Method, "http://example.com"+c.Path, strings.NewReader(c.Body))
+			if c.ContentType != "" {
+				r.Header.Set("Content-Type", c.ContentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != c.Status {
+				t.Errorf("got status %d, expected %d: %s", w.Code, c.Status, w.Body.String())
+			}
+			if w.Code == http.StatusOK && body != c.Body {
+				t.Errorf("got body %q, expected %q", body, c.Body)
+			}
+		})
+	}
+}
+
+func TestSpecMiddlewareInvalidSpec(t *testing.T) {
+	if _, err := validation.SpecMiddleware([]byte("{")); err == nil {
+		t.Error("expected an error")
+	}
+}
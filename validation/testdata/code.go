@@ -0,0 +1,101 @@
+package testdata
+
+var FormatCode = `// Validators lists the implementations of the custom validations used by the
+// service method payloads. All the fields must be set.
+type Validators struct {
+	// CreditCard validates the values of the "credit-card" format.
+	CreditCard func(string) error
+	// CouponCode validates the values of the "coupon-code" format.
+	CouponCode func(string) error
+}
+
+// UseValidators wraps the endpoints of the methods whose payload uses custom
+// validations so that the validations run before the methods are called.
+func UseValidators(e *Endpoints, v *Validators) {
+	e.Pay = validatePay(e.Pay, v)
+}
+
+// validatePay runs the custom validations of the "Pay" method payload before
+// calling the endpoint.
+func validatePay(ep goa.Endpoint, v *Validators) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		if err := checkPayPayload(req.(*PayPayload), v); err != nil {
+			return nil, err
+		}
+		return ep(ctx, req)
+	}
+}
+
+// checkCard runs the custom validations of the Card type.
+func checkCard(v *Card, vs *Validators) (err error) {
+	if err2 := vs.CreditCard(v.Number); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidFormatError("number", v.Number, "credit-card", err2))
+	}
+	return
+}
+
+// checkPayPayload runs the custom validations of the PayPayload type.
+func checkPayPayload(v *PayPayload, vs *Validators) (err error) {
+	if v.Coupon != nil {
+		if err2 := vs.CouponCode(*v.Coupon); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidFormatError("coupon", *v.Coupon, "coupon-code", err2))
+		}
+	}
+	if v.Card != nil {
+		err = goa.MergeErrors(err, checkCard(v.Card, vs))
+	}
+	return
+}
+`
+
+var RuleCode = `// Validators lists the implementations of the custom validations used by the
+// service method payloads. All the fields must be set.
+type Validators struct {
+	// RoomCode validates the values of the "room-code" format.
+	RoomCode func(string) error
+	// PeriodOrder implements the "period-order" rule: start must be before end
+	PeriodOrder func(*Period) error
+}
+
+// UseValidators wraps the endpoints of the methods whose payload uses custom
+// validations so that the validations run before the methods are called.
+func UseValidators(e *Endpoints, v *Validators) {
+	e.Book = validateBook(e.Book, v)
+}
+
+// validateBook runs the custom validations of the "Book" method payload before
+// calling the endpoint.
+func validateBook(ep goa.Endpoint, v *Validators) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		if err := checkPeriod(req.(*Period), v); err != nil {
+			return nil, err
+		}
+		return ep(ctx, req)
+	}
+}
+
+// checkPeriod runs the custom validations of the Period type.
+func checkPeriod(v *Period, vs *Validators) (err error) {
+	if v.Room != nil {
+		if err2 := vs.RoomCode(*v.Room); err2 != nil {
+			err = goa.MergeErrors(err, goa.InvalidFormatError("room", *v.Room, "room-code", err2))
+		}
+	}
+	if err2 := vs.PeriodOrder(v); err2 != nil {
+		err = goa.MergeErrors(err, goa.PermanentError("invalid_rule", "%s: %s", "period-order", err2))
+	}
+	return
+}
+`
+
+var SpecCode = `// Spec is the OpenAPI specification of the API in JSON used to validate the
+// requests.
+const Spec = "{\"swagger\":\"2.0\",\"info\":{\"title\":\"\",\"version\":\"\"},\"host\":\"localhost:80\",\"consumes\":[\"application/json\",\"application/xml\",\"application/gob\"],\"produces\":[\"application/json\",\"application/xml\",\"application/gob\"],\"paths\":{\"/rooms/{id}\":{\"get\":{\"tags\":[\"Rooms\"],\"summary\":\"Show Rooms\",\"operationId\":\"Rooms#Show\",\"parameters\":[{\"name\":\"id\",\"in\":\"path\",\"required\":true,\"type\":\"integer\",\"minimum\":1}],\"responses\":{\"200\":{\"description\":\"OK response.\",\"schema\":{\"type\":\"string\"}}},\"schemes\":[\"http\"]}}}}"
+
+// Middleware returns a HTTP middleware validating the requests against Spec
+// before they reach the handlers. The requests that fail validation are
+// answered with 400 Bad Request.
+func Middleware() (func(http.Handler) http.Handler, error) {
+	return validation.SpecMiddleware([]byte(Spec))
+}
+`
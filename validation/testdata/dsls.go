@@ -0,0 +1,78 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	validation "goa.design/plugins/v3/validation/dsl"
+)
+
+var FormatDSL = func() {
+	var Card = Type("Card", func() {
+		Attribute("number", String, func() {
+			validation.CustomFormat("credit-card")
+		})
+		Attribute("holder", String)
+		Required("number")
+	})
+	Service("Payments", func() {
+		Method("Pay", func() {
+			Payload(func() {
+				Attribute("card", Card)
+				Attribute("coupon", String, func() {
+					validation.CustomFormat("coupon-code")
+				})
+				Required("card")
+			})
+			HTTP(func() {
+				POST("/payments")
+				Param("coupon")
+			})
+		})
+	})
+}
+
+var RuleDSL = func() {
+	var Period = Type("Period", func() {
+		Attribute("start", String, func() {
+			Format(FormatDateTime)
+		})
+		Attribute("end", String, func() {
+			Format(FormatDateTime)
+		})
+		Attribute("room", String, func() {
+			validation.CustomFormat("room-code")
+		})
+		validation.Rule("period-order", "start must be before end", "start", "end")
+	})
+	Service("Bookings", func() {
+		Method("Book", func() {
+			Payload(Period)
+			HTTP(func() {
+				POST("/bookings")
+			})
+		})
+		Method("List", func() {
+			HTTP(func() {
+				GET("/bookings")
+			})
+		})
+	})
+}
+
+var SpecDSL = func() {
+	API("rooms", func() {
+		validation.SpecValidation()
+	})
+	Service("Rooms", func() {
+		Method("Show", func() {
+			Payload(func() {
+				Attribute("id", Int, func() {
+					Minimum(1)
+				})
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/rooms/{id}")
+			})
+		})
+	})
+}
@@ -0,0 +1,35 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var WalkDSL = func() {
+	var Address = Type("Address", func() {
+		Attribute("street", String)
+	})
+	var Node = Type("Node", func() {
+		Attribute("name", String)
+		Attribute("tags", MapOf(String, ArrayOf(String)))
+		Attribute("billing", Address)
+		Attribute("shipping", Address)
+		Attribute("children", ArrayOf("Node"))
+	})
+	Service("tree", func() {
+		Method("show", func() {
+			Payload(Node)
+			HTTP(func() {
+				POST("/nodes")
+				PUT("/trees")
+			})
+		})
+		Method("list", func() {
+			HTTP(func() {
+				GET("/nodes")
+			})
+		})
+	})
+	Service("admin", func() {
+		Method("reset", func() {})
+	})
+}
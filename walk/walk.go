@@ -0,0 +1,228 @@
+package walk
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+)
+
+// Stop may be returned by the visitor functions to stop walking without
+// error: the walk functions return nil when the visitor returns Stop.
+var Stop = errors.New("stop walking")
+
+// Services calls fn for each service of the design in the order they are
+// defined. Services stops and returns the error if fn returns one.
+func Services(r *expr.RootExpr, fn func(*expr.ServiceExpr) error) error {
+	for _, svc := range r.Services {
+		if err := fn(svc); err != nil {
+			return stop(err)
+		}
+	}
+	return nil
+}
+
+// Methods calls fn for each method of each service of the design. Methods
+// stops and returns the error if fn returns one.
+func Methods(r *expr.RootExpr, fn func(*expr.MethodExpr) error) error {
+	return Services(r, func(svc *expr.ServiceExpr) error {
+		for _, m := range svc.Methods {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// HTTPServices calls fn for each HTTP service of the design. HTTPServices
+// stops and returns the error if fn returns one.
+func HTTPServices(r *expr.RootExpr, fn func(*expr.HTTPServiceExpr) error) error {
+	if r.API == nil || r.API.HTTP == nil {
+		return nil
+	}
+	for _, svc := range r.API.HTTP.Services {
+		if err := fn(svc); err != nil {
+			return stop(err)
+		}
+	}
+	return nil
+}
+
+// HTTPEndpoints calls fn for each HTTP endpoint of each HTTP service of the
+// design. HTTPEndpoints stops and returns the error if fn returns one.
+func HTTPEndpoints(r *expr.RootExpr, fn func(*expr.HTTPEndpointExpr) error) error {
+	return HTTPServices(r, func(svc *expr.HTTPServiceExpr) error {
+		for _, e := range svc.HTTPEndpoints {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Attribute calls fn for att and for each attribute nested in att depth first:
+// the object fields, the array elements and the map keys and elements. The
+// attributes of a user type are visited under each path using the type except
+// where the type is nested in itself so that recursive types do not cause
+// infinite recursion. fn is given the path to the attribute
+// relative to att, e.g. "items[].name", "" for att itself. Attribute stops
+// and returns the error if fn returns one.
+func Attribute(att *expr.AttributeExpr, fn func(path string, att *expr.AttributeExpr) error) error {
+	return stop(attribute("", att, fn, make(map[string]bool)))
+}
+
+// attribute implements Attribute, seen records the user types enclosing att.
+func attribute(path string, att *expr.AttributeExpr, fn func(string, *expr.AttributeExpr) error, seen map[string]bool) error {
+	if att == nil {
+		return nil
+	}
+	if err := fn(path, att); err != nil {
+		return err
+	}
+	return children(path, att, fn, seen)
+}
+
+// children calls attribute on the attributes nested in att.
+func children(path string, att *expr.AttributeExpr, fn func(string, *expr.AttributeExpr) error, seen map[string]bool) error {
+	switch t := att.Type.(type) {
+	case expr.UserType:
+		if seen[t.ID()] {
+			return nil
+		}
+		seen[t.ID()] = true
+		defer delete(seen, t.ID())
+		return children(path, t.Attribute(), fn, seen)
+	case *expr.Array:
+		return attribute(path+"[]", t.ElemType, fn, seen)
+	case *expr.Map:
+		if err := attribute(path+"[key]", t.KeyType, fn, seen); err != nil {
+			return err
+		}
+		return attribute(path+"[]", t.ElemType, fn, seen)
+	case *expr.Object:
+		for _, nat := range *t {
+			p := nat.Name
+			if path != "" {
+				p = path + "." + nat.Name
+			}
+			if err := attribute(p, nat.Attribute, fn, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Specs calls fn for each OpenAPI specification rendered by the given file.
+// Specs stops and returns the error if fn returns one.
+func Specs(f *codegen.File, fn func(*openapi.V2) error) error {
+	for _, s := range f.Section("openapi") {
+		spec, ok := s.Data.(*openapi.V2)
+		if !ok {
+			continue
+		}
+		if err := fn(spec); err != nil {
+			return stop(err)
+		}
+	}
+	return nil
+}
+
+// Operations calls fn for each operation of the given OpenAPI specification
+// sorted by path and then by method. fn is given the key of the path in the
+// specification and the lower case HTTP method. Operations stops and returns
+// the error if fn returns one.
+func Operations(spec *openapi.V2, fn func(path, method string, op *openapi.Operation) error) error {
+	keys := make([]string, 0, len(spec.Paths))
+	for k := range spec.Paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p, ok := spec.Paths[k].(*openapi.Path)
+		if !ok {
+			continue
+		}
+		ops := []struct {
+			method string
+			op     *openapi.Operation
+		}{
+			{"get", p.Get}, {"put", p.Put}, {"post", p.Post}, {"delete", p.Delete},
+			{"options", p.Options}, {"head", p.Head}, {"patch", p.Patch},
+		}
+		for _, o := range ops {
+			if o.op == nil {
+				continue
+			}
+			if err := fn(k, o.method, o.op); err != nil {
+				return stop(err)
+			}
+		}
+	}
+	return nil
+}
+
+// stop returns nil if err is Stop, err otherwise.
+func stop(err error) error {
+	if err == Stop {
+		return nil
+	}
+	return err
+}
+
+// OperationMethod returns the method corresponding to the given OpenAPI
+// operation, nil if the operation does not correspond to a method (e.g. file
+// servers).
+func OperationMethod(r *expr.RootExpr, op *openapi.Operation) *expr.MethodExpr {
+	// The operation IDs are of the form "service#method" followed by
+	// "#index" for the additional routes of a method.
+	ids := strings.SplitN(op.OperationID, "#", 3)
+	if len(ids) < 2 {
+		return nil
+	}
+	svc := r.Service(ids[0])
+	if svc == nil {
+		return nil
+	}
+	return svc.Method(ids[1])
+}
+
+// OperationEndpoint returns the HTTP endpoint corresponding to the given
+// OpenAPI operation, nil if there is none.
+func OperationEndpoint(r *expr.RootExpr, op *openapi.Operation) *expr.HTTPEndpointExpr {
+	m := OperationMethod(r, op)
+	if m == nil || r.API == nil || r.API.HTTP == nil {
+		return nil
+	}
+	svc := r.API.HTTP.Service(m.Service.Name)
+	if svc == nil {
+		return nil
+	}
+	return svc.Endpoint(m.Name)
+}
+
+// HasBody returns true if the given OpenAPI operation accepts a request body.
+func HasBody(op *openapi.Operation) bool {
+	for _, p := range op.Parameters {
+		if p.In == "body" {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendMediaType appends mt to the media types mts of an OpenAPI
+// specification or operation unless mts already contains it.
+func AppendMediaType(mts []string, mt string) []string {
+	for _, m := range mts {
+		if m == mt {
+			return mts
+		}
+	}
+	return append(mts, mt)
+}
@@ -0,0 +1,92 @@
+package walk_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/walk"
+	"goa.design/plugins/v3/walk/testdata"
+)
+
+func TestMethods(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.WalkDSL)
+	var names []string
+	err := walk.Methods(expr.Root, func(m *expr.MethodExpr) error {
+		names = append(names, m.Service.Name+"#"+m.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, " "); got != "tree#show tree#list admin#reset" {
+		t.Errorf("got %q", got)
+	}
+
+	names = nil
+	err = walk.HTTPEndpoints(expr.Root, func(e *expr.HTTPEndpointExpr) error {
+		names = append(names, e.Service.Name()+"#"+e.Name())
+		return walk.Stop
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, " "); got != "tree#show" {
+		t.Errorf("got %q, expected walk to stop after the first endpoint", got)
+	}
+
+	boom := errors.New("boom")
+	if err := walk.Services(expr.Root, func(*expr.ServiceExpr) error { return boom }); err != boom {
+		t.Errorf("got error %v, expected %v", err, boom)
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.WalkDSL)
+	var paths []string
+	err := walk.Attribute(expr.Root.Service("tree").Method("show").Payload, func(path string, _ *expr.AttributeExpr) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"", "name", "tags", "tags[key]", "tags[]", "tags[][]", "billing", "billing.street", "shipping", "shipping.street", "children", "children[]"}
+	if got := strings.Join(paths, " "); got != strings.Join(expected, " ") {
+		t.Errorf("got paths %q, expected %q", got, strings.Join(expected, " "))
+	}
+}
+
+func TestOperations(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.WalkDSL)
+	openapi.Definitions = make(map[string]*openapi.Schema)
+	spec, err := openapi.NewV2(expr.Root, expr.Root.API.Servers[0].Hosts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &codegen.File{SectionTemplates: []*codegen.SectionTemplate{{Name: "openapi", Data: spec}}}
+	var ops []string
+	err = walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(path, method string, op *openapi.Operation) error {
+			m := walk.OperationMethod(expr.Root, op)
+			e := walk.OperationEndpoint(expr.Root, op)
+			if m == nil || e == nil || e.MethodExpr != m {
+				t.Errorf("%s %s: got method %v and endpoint %v", method, path, m, e)
+				return nil
+			}
+			ops = append(ops, method+" "+path+" "+m.Name)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"get /nodes list", "post /nodes show", "put /trees show"}
+	if got := strings.Join(ops, ", "); got != strings.Join(expected, ", ") {
+		t.Errorf("got operations %q, expected %q", got, strings.Join(expected, ", "))
+	}
+}
@@ -0,0 +1,112 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/webhooks/expr"
+
+	// Register code generators for the webhooks plugin
+	_ "goa.design/plugins/v3/webhooks"
+)
+
+// Event defines an outbound webhook event. Event takes the event name as
+// first argument and the event payload as second argument. The payload is
+// defined the same way as a type: using an existing type, a type and a DSL
+// function or a DSL function only.
+//
+// Event must appear at the top level of the design. Services use Publishes to
+// declare the events they send.
+//
+// Example:
+//
+//    import webhooks "goa.design/plugins/v3/webhooks/dsl"
+//
+//    var OrderCreated = webhooks.Event("order_created", func() {
+//        Description("Sent when an order is created.")
+//        Attribute("id", String, "Order ID")
+//        Attribute("total", Float64, "Order total")
+//        Required("id", "total")
+//    })
+//
+//    var OrderShipped = webhooks.Event("order_shipped", Order)
+//
+func Event(name string, args ...interface{}) *expr.EventExpr {
+	if _, ok := eval.Current().(eval.TopExpr); !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if expr.Root.Event(name) != nil {
+		eval.ReportError("event %#v defined twice", name)
+		return nil
+	}
+	if len(args) == 0 {
+		eval.ReportError("missing event payload")
+		return nil
+	}
+	if len(args) > 2 {
+		eval.ReportError("too many arguments")
+		return nil
+	}
+	var (
+		base goaexpr.DataType
+		fn   func()
+	)
+	switch a := args[0].(type) {
+	case goaexpr.DataType:
+		base = a
+		if len(args) == 2 {
+			d, ok := args[1].(func())
+			if !ok {
+				eval.InvalidArgError("function", args[1])
+				return nil
+			}
+			fn = d
+		}
+	case func():
+		base = &goaexpr.Object{}
+		fn = a
+		if len(args) == 2 {
+			eval.ReportError("only one argument allowed when it is a function")
+			return nil
+		}
+	default:
+		eval.InvalidArgError("type or function", args[0])
+		return nil
+	}
+	e := &expr.EventExpr{
+		Name:    name,
+		Payload: &goaexpr.AttributeExpr{Type: base, DSLFunc: fn},
+	}
+	expr.Root.Events = append(expr.Root.Events, e)
+	return e
+}
+
+// Publishes declares the webhook events sent by the service. The plugin
+// generates a publisher interface for the service with one method per event.
+//
+// Publishes must appear in a Service expression.
+//
+// Example:
+//
+//    var _ = Service("orders", func() {
+//        webhooks.Publishes(OrderCreated, OrderShipped)
+//    })
+//
+func Publishes(events ...*expr.EventExpr) {
+	svc, ok := eval.Current().(*goaexpr.ServiceExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	p := expr.Root.Publisher(svc.Name)
+	if p == nil {
+		p = &expr.PublisherExpr{Service: svc}
+		expr.Root.Publishers = append(expr.Root.Publishers, p)
+	}
+	for _, e := range events {
+		if e == nil {
+			continue // error reported by Event
+		}
+		p.Events = append(p.Events, e)
+	}
+}
@@ -0,0 +1,90 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+type (
+	// EventExpr describes an outbound webhook event.
+	EventExpr struct {
+		// Name is the event name.
+		Name string
+		// Payload describes the event payload. The payload DSL is
+		// executed with the attribute as current expression.
+		Payload *expr.AttributeExpr
+	}
+
+	// PublisherExpr describes the events published by a service.
+	PublisherExpr struct {
+		// Service is the publishing service.
+		Service *expr.ServiceExpr
+		// Events lists the published events.
+		Events []*EventExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (e *EventExpr) EvalName() string {
+	return fmt.Sprintf("event %q", e.Name)
+}
+
+// DSL returns the function which runs the payload DSL.
+func (e *EventExpr) DSL() func() {
+	return func() {
+		if e.Payload.DSLFunc != nil {
+			eval.Execute(e.Payload.DSLFunc, e.Payload)
+		}
+	}
+}
+
+// Validate ensures the event expression is valid.
+func (e *EventExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	if e.Name == "" {
+		verr.Add(e, "event name cannot be empty")
+	}
+	for _, other := range Root.Events {
+		if other != e && other.Name == e.Name {
+			verr.Add(e, "event %q defined twice", e.Name)
+			break
+		}
+	}
+	if e.Payload.Type == nil || e.Payload.Type == expr.Empty {
+		verr.Add(e, "event must define a payload")
+	} else {
+		verr.Merge(e.Payload.Validate("payload", e))
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// Finalize finalizes the event payload.
+func (e *EventExpr) Finalize() {
+	e.Payload.Finalize()
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (p *PublisherExpr) EvalName() string {
+	return fmt.Sprintf("Publishes of %s", p.Service.EvalName())
+}
+
+// Validate ensures the publisher expression is valid.
+func (p *PublisherExpr) Validate() error {
+	verr := new(eval.ValidationErrors)
+	seen := make(map[string]bool)
+	for _, e := range p.Events {
+		if seen[e.Name] {
+			verr.Add(p, "event %q published twice", e.Name)
+		}
+		seen[e.Name] = true
+	}
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	return verr
+}
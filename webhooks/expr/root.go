@@ -0,0 +1,77 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the webhook events defined in the design.
+	RootExpr struct {
+		// Events lists the events in the order they appear in the
+		// design.
+		Events []*EventExpr
+		// Publishers lists the services publishing events.
+		Publishers []*PublisherExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "webhooks plugin"
+}
+
+// WalkSets iterates over the events and publishers.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	eexps := make(eval.ExpressionSet, len(r.Events))
+	for i, e := range r.Events {
+		eexps[i] = e
+	}
+	walk(eexps)
+	pexps := make(eval.ExpressionSet, len(r.Publishers))
+	for i, p := range r.Publishers {
+		pexps[i] = p
+	}
+	walk(pexps)
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/webhooks/dsl"}
+}
+
+// Event returns the event with the given name, nil if there isn't one.
+func (r *RootExpr) Event(name string) *EventExpr {
+	for _, e := range r.Events {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// Publisher returns the publisher for the given service, nil if the service
+// does not publish events.
+func (r *RootExpr) Publisher(svc string) *PublisherExpr {
+	for _, p := range r.Publishers {
+		if p.Service.Name == svc {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,430 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+	"goa.design/plugins/v3/webhooks/expr"
+)
+
+type (
+	// eventData contains the data necessary to render the code of an
+	// event.
+	eventData struct {
+		// Name is the event name.
+		Name string
+		// Const is the name of the constant holding the event name.
+		Const string
+		// MethodName is the name of the publisher method.
+		MethodName string
+		// Doc is the publisher method documentation.
+		Doc string
+		// PayloadRef is the reference to the payload Go type.
+		PayloadRef string
+	}
+
+	// typeData contains the data necessary to render a payload type.
+	typeData struct {
+		// Name is the type name.
+		Name string
+		// Description is the type description.
+		Description string
+		// Def is the type definition.
+		Def string
+	}
+
+	// publisherData contains the data necessary to render the publisher of
+	// a service.
+	publisherData struct {
+		// Service is the service name.
+		Service string
+		// Interface is the name of the publisher interface.
+		Interface string
+		// Struct is the name of the publisher implementation.
+		Struct string
+		// Constructor is the name of the publisher constructor.
+		Constructor string
+		// Events lists the published events.
+		Events []*eventData
+	}
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "webhooks",
+		Cmd:      "gen",
+		Generate: Generate,
+	})
+}
+
+// Generate produces the webhooks package which defines the event payload
+// types and the publishers, the AsyncAPI document describing the events and
+// documents the events in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if len(expr.Root.Events) == 0 {
+		return files, nil
+	}
+	for _, f := range files {
+		if !config.EnabledFile("webhooks", f) {
+			continue
+		}
+		documentEvents(f)
+	}
+	return append(files, webhooksFile(), asyncAPIFile()), nil
+}
+
+// webhooksFile returns the file defining the event payload types and the
+// publishers.
+func webhooksFile() *codegen.File {
+	scope := codegen.NewNameScope()
+	var (
+		types  []*typeData
+		events = make(map[string]*eventData)
+		seen   = make(map[string]bool)
+	)
+	for _, e := range expr.Root.Events {
+		ed := &eventData{
+			Name:       e.Name,
+			Const:      codegen.Goify(e.Name, true) + "Event",
+			MethodName: "Publish" + codegen.Goify(e.Name, true),
+		}
+		ed.Doc = fmt.Sprintf("%s publishes the %q event.", ed.MethodName, e.Name)
+		if e.Payload.Description != "" {
+			ed.Doc += " " + e.Payload.Description
+		}
+		if _, ok := e.Payload.Type.(*goaexpr.Object); ok {
+			name := scope.Unique(codegen.Goify(e.Name, true) + "Payload")
+			desc := e.Payload.Description
+			if desc == "" {
+				desc = fmt.Sprintf("%s is the payload of the %q event.", name, e.Name)
+			}
+			types = append(types, &typeData{
+				Name:        name,
+				Description: desc,
+				Def:         typeDef(scope, e.Payload),
+			})
+			ed.PayloadRef = "*" + name
+		} else {
+			ed.PayloadRef = scope.GoTypeRef(e.Payload)
+		}
+		codegen.Walk(e.Payload, func(att *goaexpr.AttributeExpr) error {
+			ut, ok := att.Type.(goaexpr.UserType)
+			if !ok || seen[ut.ID()] {
+				return nil
+			}
+			seen[ut.ID()] = true
+			name := scope.GoTypeName(att)
+			desc := ut.Attribute().Description
+			if desc == "" {
+				desc = fmt.Sprintf("%s is used in the webhook event payloads.", name)
+			}
+			def := scope.GoTypeDef(ut.Attribute(), false, true)
+			if goaexpr.IsObject(ut) {
+				def = typeDef(scope, ut.Attribute())
+			}
+			types = append(types, &typeData{
+				Name:        name,
+				Description: desc,
+				Def:         def,
+			})
+			return nil
+		})
+		events[e.Name] = ed
+	}
+
+	evs := make([]*eventData, len(expr.Root.Events))
+	for i, e := range expr.Root.Events {
+		evs[i] = events[e.Name]
+	}
+	fpath := filepath.Join(codegen.Gendir, "webhooks", "webhooks.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Webhook events", "webhooks", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "goa.design/plugins/v3/webhooks", Name: "goawebhooks"},
+		}),
+		{
+			Name:   "webhooks-events",
+			Source: eventsT,
+			Data:   map[string]interface{}{"Events": evs, "Types": types},
+		},
+	}
+	for _, p := range expr.Root.Publishers {
+		if len(p.Events) == 0 {
+			continue
+		}
+		pd := &publisherData{
+			Service:     p.Service.Name,
+			Interface:   codegen.Goify(p.Service.Name, true) + "Publisher",
+			Struct:      codegen.Goify(p.Service.Name, false) + "Publisher",
+			Constructor: "New" + codegen.Goify(p.Service.Name, true) + "Publisher",
+		}
+		for _, e := range p.Events {
+			pd.Events = append(pd.Events, events[e.Name])
+		}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "webhooks-publisher",
+			Source: publisherT,
+			Data:   pd,
+		})
+	}
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// asyncAPIFile returns the file containing the AsyncAPI document describing
+// the webhook events.
+func asyncAPIFile() *codegen.File {
+	api := goaexpr.Root.API
+	doc := &asyncAPI{
+		AsyncAPI: "2.0.0",
+		Info: &asyncInfo{
+			Title:       api.Title,
+			Version:     api.Version,
+			Description: api.Description,
+		},
+		Channels: make(map[string]*asyncChan),
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = api.Name
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = "1.0"
+	}
+	var schemas []*openapi.Schema
+	for _, e := range expr.Root.Events {
+		payload := openapi.AttributeTypeSchema(api, e.Payload)
+		schemas = append(schemas, payload)
+		doc.Channels[e.Name] = &asyncChan{
+			Description: e.Payload.Description,
+			Subscribe: &asyncOperation{
+				OperationID: "Publish" + codegen.Goify(e.Name, true),
+				Summary:     fmt.Sprintf("%s webhook event", e.Name),
+				Message: &asyncMessage{
+					Name:        e.Name,
+					ContentType: "application/json",
+					Headers:     headersSchema(),
+					Payload:     payload,
+				},
+			},
+		}
+	}
+	if defs := referencedDefinitions(schemas); len(defs) > 0 {
+		doc.Components = &asyncComponents{Schemas: defs}
+	}
+	section := &codegen.SectionTemplate{
+		Name:    "asyncapi",
+		FuncMap: template.FuncMap{"toJSON": toJSON},
+		Source:  "{{ toJSON . }}",
+		Data:    doc,
+	}
+	return &codegen.File{
+		Path:             filepath.Join(codegen.Gendir, "webhooks", "asyncapi.json"),
+		SectionTemplates: []*codegen.SectionTemplate{section},
+	}
+}
+
+// documentEvents lists the webhook events under the x-webhooks extension of
+// the OpenAPI specification paths if f is an OpenAPI file.
+func documentEvents(f *codegen.File) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		hooks := make(map[string]*openapi.Path)
+		for _, e := range expr.Root.Events {
+			params := []*openapi.Parameter{{
+				Name:     "payload",
+				In:       "body",
+				Required: true,
+				Schema:   openapi.AttributeTypeSchema(goaexpr.Root.API, e.Payload),
+			}}
+			for _, h := range headers() {
+				params = append(params, &openapi.Parameter{
+					Name:        h[0],
+					In:          "header",
+					Description: h[1],
+					Required:    true,
+					Type:        "string",
+				})
+			}
+			hooks[e.Name] = &openapi.Path{Post: &openapi.Operation{
+				Tags:        publishers(e),
+				Summary:     fmt.Sprintf("%s webhook event", e.Name),
+				Description: e.Payload.Description,
+				OperationID: "webhooks#" + e.Name,
+				Consumes:    []string{"application/json"},
+				Parameters:  params,
+				Responses: map[string]*openapi.Response{
+					"200": {Description: "The subscriber acknowledges the event, any 2xx status code is accepted."},
+				},
+			}}
+		}
+		spec.Paths["x-webhooks"] = hooks
+		for n, d := range openapi.Definitions {
+			if spec.Definitions == nil {
+				spec.Definitions = make(map[string]*openapi.Schema)
+			}
+			if _, ok := spec.Definitions[n]; !ok {
+				spec.Definitions[n] = d
+			}
+		}
+		return nil
+	})
+}
+
+// typeDef returns the Go struct definition of the given object attribute. The
+// struct fields have JSON tags so that the payloads are serialized using the
+// design attribute names.
+func typeDef(scope *codegen.NameScope, att *goaexpr.AttributeExpr) string {
+	obj := goaexpr.AsObject(att.Type)
+	ss := []string{"struct {"}
+	for _, nat := range *obj {
+		at := nat.Attribute
+		var tdef string
+		if _, ok := at.Type.(*goaexpr.Object); ok {
+			tdef = typeDef(scope, at)
+		} else {
+			tdef = scope.GoTypeDef(at, false, true)
+		}
+		if goaexpr.IsObject(at.Type) || att.IsPrimitivePointer(nat.Name, true) {
+			tdef = "*" + tdef
+		}
+		var desc string
+		if at.Description != "" {
+			desc = codegen.Comment(at.Description) + "\n\t"
+		}
+		tag := nat.Name
+		if !att.IsRequired(nat.Name) {
+			tag += ",omitempty"
+		}
+		ss = append(ss, fmt.Sprintf("\t%s%s %s `json:%q`", desc, codegen.GoifyAtt(at, nat.Name, true), tdef, tag))
+	}
+	ss = append(ss, "}")
+	return strings.Join(ss, "\n")
+}
+
+// headers returns the names and descriptions of the headers set by the
+// deliverer.
+func headers() [][2]string {
+	return [][2]string{
+		{EventHeader, "Name of the event."},
+		{IDHeader, "Unique ID of the delivery, retries of the same delivery use the same ID."},
+		{SignatureHeader, `Signature of the request body of the form "t=<unix timestamp>,v1=<hex encoded HMAC-SHA256 of the timestamp, a dot and the body>".`},
+	}
+}
+
+// headersSchema returns the JSON schema describing the headers set by the
+// deliverer.
+func headersSchema() *openapi.Schema {
+	s := openapi.NewSchema()
+	s.Type = openapi.Object
+	for _, h := range headers() {
+		p := openapi.NewSchema()
+		p.Type = openapi.String
+		p.Description = h[1]
+		s.Properties[h[0]] = p
+		s.Required = append(s.Required, h[0])
+	}
+	return s
+}
+
+// publishers returns the names of the services publishing the event.
+func publishers(e *expr.EventExpr) []string {
+	var svcs []string
+	for _, p := range expr.Root.Publishers {
+		for _, pe := range p.Events {
+			if pe == e {
+				svcs = append(svcs, p.Service.Name)
+				break
+			}
+		}
+	}
+	return svcs
+}
+
+// referencedDefinitions returns the definitions referenced by the given
+// schemas directly or indirectly.
+func referencedDefinitions(schemas []*openapi.Schema) map[string]*openapi.Schema {
+	defs := make(map[string]*openapi.Schema)
+	var collect func(*openapi.Schema)
+	collect = func(s *openapi.Schema) {
+		if s == nil {
+			return
+		}
+		if name := strings.TrimPrefix(s.Ref, "#/definitions/"); name != s.Ref {
+			if d, ok := openapi.Definitions[name]; ok {
+				if _, seen := defs[name]; !seen {
+					defs[name] = d
+					collect(d)
+				}
+			}
+		}
+		collect(s.Items)
+		for _, p := range s.Properties {
+			collect(p)
+		}
+		for _, a := range s.AnyOf {
+			collect(a)
+		}
+	}
+	for _, s := range schemas {
+		collect(s)
+	}
+	return defs
+}
+
+// toJSON returns the indented JSON representation of the AsyncAPI document
+// with the references rewritten to point to the document components.
+func toJSON(d interface{}) string {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		panic("webhooks: " + err.Error()) // bug
+	}
+	return strings.Replace(string(b), `"#/definitions/`, `"#/components/schemas/`, -1)
+}
+
+// input: map[string]interface{}{"Events": []*eventData, "Types": []*typeData}
+const eventsT = `const (
+{{- range .Events }}
+	{{ printf "%s is the name of the %q event." .Const .Name | comment }}
+	{{ .Const }} = {{ printf "%q" .Name }}
+{{- end }}
+)
+{{ range .Types }}
+{{ comment .Description }}
+type {{ .Name }} {{ .Def }}
+{{ end }}
+`
+
+// input: publisherData
+const publisherT = `{{ printf "%s publishes the webhook events of the %q service." .Interface .Service | comment }}
+type {{ .Interface }} interface {
+{{- range .Events }}
+	{{ comment .Doc }}
+	{{ .MethodName }}(ctx context.Context, payload {{ .PayloadRef }}) error
+{{- end }}
+}
+
+{{ printf "%s returns the %s implementation which delivers the events using d." .Constructor .Interface | comment }}
+func {{ .Constructor }}(d *goawebhooks.Deliverer) {{ .Interface }} {
+	return &{{ .Struct }}{d: d}
+}
+
+{{ printf "%s implements %s." .Struct .Interface | comment }}
+type {{ .Struct }} struct {
+	d *goawebhooks.Deliverer
+}
+{{ range .Events }}
+{{ printf "%s publishes the %q event." .MethodName .Name | comment }}
+func (p *{{ $.Struct }}) {{ .MethodName }}(ctx context.Context, payload {{ .PayloadRef }}) error {
+	return p.d.Deliver(ctx, {{ .Const }}, payload)
+}
+{{ end }}
+`
@@ -0,0 +1,103 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/plugintest"
+	"goa.design/plugins/v3/webhooks"
+	"goa.design/plugins/v3/webhooks/expr"
+	"goa.design/plugins/v3/webhooks/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	runDSL(t, testdata.EventsDSL)
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs, err := webhooks.Generate("", []eval.Root{goaexpr.Root}, ofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gen, async bool
+	for _, f := range fs {
+		switch filepath.Base(f.Path) {
+		case "webhooks.go":
+			gen = true
+			sections := f.Section("webhooks-events")
+			if len(sections) != 1 {
+				t.Fatalf("got %d events sections, expected 1", len(sections))
+			}
+			code := codegen.SectionCode(t, sections[0])
+			if code != testdata.EventsCode {
+				t.Errorf("invalid events code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.EventsCode))
+			}
+			sections = f.Section("webhooks-publisher")
+			if len(sections) != 1 {
+				t.Fatalf("got %d publisher sections, expected 1", len(sections))
+			}
+			code = codegen.SectionCode(t, sections[0])
+			if code != testdata.PublisherCode {
+				t.Errorf("invalid publisher code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, testdata.PublisherCode))
+			}
+		case "asyncapi.json":
+			async = true
+			var doc map[string]interface{}
+			if err := json.Unmarshal([]byte(sectionText(t, f.SectionTemplates[0])), &doc); err != nil {
+				t.Fatalf("invalid AsyncAPI document: %s", err)
+			}
+			chans, _ := doc["channels"].(map[string]interface{})
+			for _, e := range []string{"order_created", "order_shipped"} {
+				if _, ok := chans[e]; !ok {
+					t.Errorf("channel %q not found in AsyncAPI document", e)
+				}
+			}
+		}
+	}
+	if !gen {
+		t.Error("webhooks file not generated")
+	}
+	if !async {
+		t.Error("AsyncAPI file not generated")
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	hooks, ok := spec.Paths["x-webhooks"].(map[string]*openapi.Path)
+	if !ok {
+		t.Fatal("x-webhooks not found in OpenAPI spec")
+	}
+	for _, e := range []string{"order_created", "order_shipped"} {
+		p, ok := hooks[e]
+		if !ok {
+			t.Errorf("event %q not found in OpenAPI spec", e)
+			continue
+		}
+		if len(p.Post.Parameters) != 4 {
+			t.Errorf("got %d parameters for event %q, expected 4", len(p.Post.Parameters), e)
+		}
+	}
+}
+
+// runDSL runs the given DSL with the webhooks plugin root registered so that
+// the event payload DSLs get executed.
+func runDSL(t *testing.T, dsl func()) {
+	expr.Root.Events = nil
+	expr.Root.Publishers = nil
+	plugintest.RunDSL(t, dsl, expr.Root)
+}
+
+// sectionText renders the given section without formatting it as Go code.
+func sectionText(t *testing.T, s *codegen.SectionTemplate) string {
+	var buf bytes.Buffer
+	if err := s.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
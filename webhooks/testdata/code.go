@@ -0,0 +1,54 @@
+package testdata
+
+const EventsCode = `const (
+	// OrderCreatedEvent is the name of the "order_created" event.
+	OrderCreatedEvent = "order_created"
+	// OrderShippedEvent is the name of the "order_shipped" event.
+	OrderShippedEvent = "order_shipped"
+)
+
+// Sent when an order is created.
+type OrderCreatedPayload struct {
+	Order    *Order  ` + "`" + `json:"order"` + "`" + `
+	Customer *string ` + "`" + `json:"customer,omitempty"` + "`" + `
+}
+
+// Order is used in the webhook event payloads.
+type Order struct {
+	// Order ID
+	ID string ` + "`" + `json:"id"` + "`" + `
+	// Order total
+	Total *float64 ` + "`" + `json:"total,omitempty"` + "`" + `
+}
+`
+
+const PublisherCode = `// OrdersPublisher publishes the webhook events of the "Orders" service.
+type OrdersPublisher interface {
+	// PublishOrderCreated publishes the "order_created" event. Sent when an order
+	// is created.
+	PublishOrderCreated(ctx context.Context, payload *OrderCreatedPayload) error
+	// PublishOrderShipped publishes the "order_shipped" event.
+	PublishOrderShipped(ctx context.Context, payload *Order) error
+}
+
+// NewOrdersPublisher returns the OrdersPublisher implementation which delivers
+// the events using d.
+func NewOrdersPublisher(d *goawebhooks.Deliverer) OrdersPublisher {
+	return &ordersPublisher{d: d}
+}
+
+// ordersPublisher implements OrdersPublisher.
+type ordersPublisher struct {
+	d *goawebhooks.Deliverer
+}
+
+// PublishOrderCreated publishes the "order_created" event.
+func (p *ordersPublisher) PublishOrderCreated(ctx context.Context, payload *OrderCreatedPayload) error {
+	return p.d.Deliver(ctx, OrderCreatedEvent, payload)
+}
+
+// PublishOrderShipped publishes the "order_shipped" event.
+func (p *ordersPublisher) PublishOrderShipped(ctx context.Context, payload *Order) error {
+	return p.d.Deliver(ctx, OrderShippedEvent, payload)
+}
+`
@@ -0,0 +1,30 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	webhooks "goa.design/plugins/v3/webhooks/dsl"
+)
+
+var EventsDSL = func() {
+	var Order = Type("Order", func() {
+		Attribute("id", String, "Order ID")
+		Attribute("total", Float64, "Order total")
+		Required("id")
+	})
+	var OrderCreated = webhooks.Event("order_created", func() {
+		Description("Sent when an order is created.")
+		Attribute("order", Order)
+		Attribute("customer", String)
+		Required("order")
+	})
+	var OrderShipped = webhooks.Event("order_shipped", Order)
+	Service("Orders", func() {
+		webhooks.Publishes(OrderCreated, OrderShipped)
+		Method("Create", func() {
+			Payload(Order)
+			HTTP(func() {
+				POST("/orders")
+			})
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package webhooks
+
+import "goa.design/goa/v3/http/codegen/openapi"
+
+type (
+	// asyncAPI is the data structure serialized to create the AsyncAPI
+	// document describing the webhook events.
+	asyncAPI struct {
+		AsyncAPI   string                `json:"asyncapi"`
+		Info       *asyncInfo            `json:"info"`
+		Channels   map[string]*asyncChan `json:"channels"`
+		Components *asyncComponents      `json:"components,omitempty"`
+	}
+
+	asyncInfo struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	asyncChan struct {
+		Description string          `json:"description,omitempty"`
+		Subscribe   *asyncOperation `json:"subscribe"`
+	}
+
+	asyncOperation struct {
+		OperationID string        `json:"operationId"`
+		Summary     string        `json:"summary,omitempty"`
+		Message     *asyncMessage `json:"message"`
+	}
+
+	asyncMessage struct {
+		Name        string          `json:"name"`
+		ContentType string          `json:"contentType"`
+		Headers     *openapi.Schema `json:"headers"`
+		Payload     *openapi.Schema `json:"payload"`
+	}
+
+	asyncComponents struct {
+		Schemas map[string]*openapi.Schema `json:"schemas"`
+	}
+)
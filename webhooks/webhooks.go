@@ -0,0 +1,209 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+const (
+	// EventHeader is the name of the request header holding the event name.
+	EventHeader = "X-Webhook-Event"
+	// IDHeader is the name of the request header holding the unique ID of
+	// the delivery. Retries of the same delivery use the same ID so that
+	// subscribers can detect duplicates.
+	IDHeader = "X-Webhook-Id"
+	// SignatureHeader is the name of the request header holding the
+	// signature of the request body.
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+type (
+	// Subscription describes a subscriber endpoint.
+	Subscription struct {
+		// URL is the URL the events are posted to.
+		URL string
+		// Secret is the key used to sign the requests.
+		Secret []byte
+	}
+
+	// Subscriptions is the interface implemented by the stores listing the
+	// subscribers of each event.
+	Subscriptions interface {
+		// Subscriptions returns the subscriptions to the given event.
+		Subscriptions(ctx context.Context, event string) ([]*Subscription, error)
+	}
+
+	// Deliverer posts the events to the subscribers.
+	Deliverer struct {
+		// Subscriptions lists the event subscribers.
+		Subscriptions Subscriptions
+		// Doer sends the HTTP requests.
+		Doer goahttp.Doer
+		// MaxRetries is the maximum number of retries of a failed
+		// delivery.
+		MaxRetries int
+		// Backoff is the delay before the first retry, the delay doubles
+		// with each retry.
+		Backoff time.Duration
+	}
+)
+
+// NewDeliverer returns a deliverer which uses the default HTTP client and
+// retries failed deliveries up to 3 times.
+func NewDeliverer(subs Subscriptions) *Deliverer {
+	return &Deliverer{
+		Subscriptions: subs,
+		Doer:          http.DefaultClient,
+		MaxRetries:    3,
+		Backoff:       time.Second,
+	}
+}
+
+// Deliver posts the JSON representation of the payload to all the
+// subscribers of the event. A delivery is retried if the request fails or if
+// the subscriber responds with a 5xx or 429 status code. Deliver returns an
+// error if any delivery fails after all retries.
+func (d *Deliverer) Deliver(ctx context.Context, event string, payload interface{}) error {
+	subs, err := d.Subscriptions.Subscriptions(ctx, event)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %q event payload: %s", event, err)
+	}
+	var errs []string
+	for _, sub := range subs {
+		if err := d.deliver(ctx, event, sub, body); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", sub.URL, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to deliver %q event: %s", event, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Sign returns the signature of the request body sent at the given time. The
+// signature is the hex encoded HMAC-SHA256 of the timestamp and body using the
+// subscription secret as key.
+func Sign(secret, body []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac(secret, ts, body)))
+}
+
+// Verify checks the signature of a request body. Subscribers use it to
+// authenticate the requests. tolerance is the maximum age of the signature,
+// zero disables the check.
+func Verify(secret, body []byte, signature string, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(signature, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+	if ts == "" || sig == "" {
+		return errors.New("malformed signature")
+	}
+	if tolerance > 0 {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return errors.New("malformed signature timestamp")
+		}
+		if time.Since(time.Unix(sec, 0)) > tolerance {
+			return errors.New("signature expired")
+		}
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	if !hmac.Equal(expected, mac(secret, ts, body)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// deliver posts the event to a single subscriber, retrying on failure.
+func (d *Deliverer) deliver(ctx context.Context, event string, sub *Subscription, body []byte) error {
+	id := newID()
+	backoff := d.Backoff
+	var err error
+	for attempt := 0; attempt <= d.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		var retry bool
+		if retry, err = d.post(ctx, event, id, sub, body); !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// post sends a single delivery request. It returns true if the request should
+// be retried.
+func (d *Deliverer) post(ctx context.Context, event, id string, sub *Subscription, body []byte) (bool, error) {
+	req, err := http.NewRequest("POST", sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(IDHeader, id)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, body, time.Now()))
+	resp, err := d.Doer.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return true, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// mac computes the HMAC-SHA256 of the timestamp and body.
+func mac(secret []byte, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// newID returns a random delivery ID.
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
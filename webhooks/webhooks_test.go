@@ -0,0 +1,102 @@
+package webhooks
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"id":"1"}`)
+	cases := []struct {
+		Name      string
+		Secret    []byte
+		Body      []byte
+		Signature string
+		Tolerance time.Duration
+		Error     string
+	}{
+		{"valid", secret, body, Sign(secret, body, time.Now()), time.Minute, ""},
+		{"no-tolerance", secret, body, Sign(secret, body, time.Now().Add(-time.Hour)), 0, ""},
+		{"expired", secret, body, Sign(secret, body, time.Now().Add(-time.Hour)), time.Minute, "signature expired"},
+		{"wrong-secret", []byte("other"), body, Sign(secret, body, time.Now()), time.Minute, "invalid signature"},
+		{"wrong-body", secret, []byte(`{"id":"2"}`), Sign(secret, body, time.Now()), time.Minute, "invalid signature"},
+		{"malformed", secret, body, "v1=abc", time.Minute, "malformed signature"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := Verify(c.Secret, c.Body, c.Signature, c.Tolerance)
+			if c.Error == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if c.Error != "" && (err == nil || err.Error() != c.Error) {
+				t.Fatalf("got error %v, expected %q", err, c.Error)
+			}
+		})
+	}
+}
+
+type subscriptions []*Subscription
+
+func (s subscriptions) Subscriptions(ctx context.Context, event string) ([]*Subscription, error) {
+	return s, nil
+}
+
+func TestDeliver(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Statuses []int
+		Attempts int
+		Error    bool
+	}{
+		{"success", []int{http.StatusOK}, 1, false},
+		{"retried", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusAccepted}, 3, false},
+		{"exhausted", []int{500, 500, 500}, 3, true},
+		{"rejected", []int{http.StatusBadRequest}, 1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				attempts int
+				ids      = make(map[string]bool)
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				if err := Verify([]byte("secret"), body, r.Header.Get(SignatureHeader), time.Minute); err != nil {
+					t.Errorf("invalid signature: %s", err)
+				}
+				if e := r.Header.Get(EventHeader); e != "created" {
+					t.Errorf("got event %q, expected %q", e, "created")
+				}
+				ids[r.Header.Get(IDHeader)] = true
+				w.WriteHeader(c.Statuses[attempts])
+				attempts++
+			}))
+			defer srv.Close()
+			d := NewDeliverer(subscriptions{{URL: srv.URL, Secret: []byte("secret")}})
+			d.MaxRetries = 2
+			d.Backoff = time.Millisecond
+			err := d.Deliver(context.Background(), "created", map[string]string{"id": "1"})
+			if c.Error && err == nil {
+				t.Error("expected an error")
+			}
+			if !c.Error && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if err != nil && !strings.Contains(err.Error(), srv.URL) {
+				t.Errorf("error %q does not mention subscriber URL", err)
+			}
+			if attempts != c.Attempts {
+				t.Errorf("got %d attempts, expected %d", attempts, c.Attempts)
+			}
+			if len(ids) != 1 {
+				t.Errorf("got %d delivery IDs, expected 1", len(ids))
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/xml/expr"
+
+	// Register code generators for the XML plugin
+	_ "goa.design/plugins/v3/xml"
+)
+
+// XML enables the application/xml media type on the HTTP endpoints of the API
+// or service. The generated HTTP servers decode the XML request bodies and
+// encode the responses with XML when the first media type of the request
+// Accept header is XML. The generated HTTP clients decode the XML response
+// bodies.
+//
+// The XML element names default to the attribute names. The "xml:name" meta
+// overrides the element name of an attribute or of the root element of the
+// bodies whose type is a user type. The "xml:attr" meta renders a primitive
+// attribute as an XML attribute of its parent element.
+//
+// XML must appear in an API or Service expression.
+//
+// Example:
+//
+//    import xml "goa.design/plugins/v3/xml/dsl"
+//
+//    var Item = Type("Item", func() {
+//        Meta("xml:name", "item")
+//        Attribute("id", String, func() {
+//            Meta("xml:attr")
+//        })
+//        Attribute("tags", ArrayOf(String), func() {
+//            Meta("xml:name", "tag")
+//        })
+//    })
+//
+//    var _ = Service("catalog", func() {
+//        xml.XML()
+//    })
+//
+func XML() {
+	switch e := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+		expr.Root.API = true
+	case *goaexpr.ServiceExpr:
+		expr.Root.Services = append(expr.Root.Services, e.Name)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,58 @@
+package expr
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/goa/v3/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the services whose HTTP endpoints support XML.
+	RootExpr struct {
+		// API is true if XML is enabled for all the services.
+		API bool
+		// Services lists the names of the services that enable XML.
+		Services []string
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "XML plugin"
+}
+
+// WalkSets does nothing as the plugin defines no expression to validate.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/v3/xml/dsl"}
+}
+
+// Enabled returns true if the HTTP endpoints of the given service support
+// XML.
+func (r *RootExpr) Enabled(svc string) bool {
+	if r.API {
+		return true
+	}
+	for _, s := range r.Services {
+		if s == svc {
+			return true
+		}
+	}
+	return false
+}
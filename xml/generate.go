@@ -0,0 +1,294 @@
+package xml
+
+import (
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/config"
+	"goa.design/plugins/v3/registry"
+	"goa.design/plugins/v3/walk"
+	"goa.design/plugins/v3/xml/expr"
+)
+
+// Register the plugin Generator functions.
+func init() {
+	registry.Register(&registry.Plugin{
+		Name:     "xml",
+		Cmd:      "gen",
+		Prepare:  Prepare,
+		Generate: Generate,
+	})
+}
+
+// Prepare sets the struct tags of the attributes of the HTTP bodies of the XML
+// services that define an XML element name or that are rendered as XML
+// attributes.
+func Prepare(genpkg string, roots []eval.Root) error {
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok || r.API == nil || r.API.HTTP == nil {
+			continue
+		}
+		walk.HTTPEndpoints(r, func(e *goaexpr.HTTPEndpointExpr) error {
+			if !config.Enabled("xml", e.Service.Name()) || !expr.Root.Enabled(e.Service.Name()) {
+				return nil
+			}
+			atts := []*goaexpr.AttributeExpr{e.Body}
+			for _, resp := range e.Responses {
+				atts = append(atts, resp.Body)
+			}
+			for _, he := range e.HTTPErrors {
+				atts = append(atts, he.Response.Body)
+			}
+			for _, att := range atts {
+				walk.Attribute(att, func(_ string, a *goaexpr.AttributeExpr) error {
+					tagAttribute(a)
+					return nil
+				})
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// Generate makes the HTTP servers and clients of the XML services support XML
+// bodies and documents the media type in the OpenAPI specification.
+func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		r, ok := root.(*goaexpr.RootExpr)
+		if !ok {
+			continue
+		}
+		for _, f := range files {
+			if !config.EnabledFile("xml", f) {
+				continue
+			}
+			switch filepath.Base(f.Path) {
+			case "server.go":
+				serverXML(f, r)
+			case "client.go":
+				clientXML(f)
+			default:
+				documentXML(f, r)
+			}
+		}
+	}
+	return files, nil
+}
+
+// tagAttribute sets the struct tags of the child attributes of att that define
+// the "xml:name" or "xml:attr" meta.
+func tagAttribute(att *goaexpr.AttributeExpr) {
+	if ut, ok := att.Type.(goaexpr.UserType); ok {
+		att = ut.Attribute()
+	}
+	obj, ok := att.Type.(*goaexpr.Object)
+	if !ok {
+		return
+	}
+	for _, nat := range *obj {
+		if tags := structTags(att, nat.Name, nat.Attribute); tags != nil {
+			if nat.Attribute.Meta == nil {
+				nat.Attribute.Meta = make(goaexpr.MetaExpr)
+			}
+			for k, v := range tags {
+				nat.Attribute.Meta[k] = v
+			}
+		}
+	}
+}
+
+// structTags returns the struct tag meta of the child attribute of parent
+// with the given name, nil if the attribute uses the default XML element name
+// or already defines struct tags. The form and json tags are set as well as
+// goa omits the default tags of the attributes that define struct tags.
+func structTags(parent *goaexpr.AttributeExpr, name string, att *goaexpr.AttributeExpr) goaexpr.MetaExpr {
+	for k := range att.Meta {
+		if strings.HasPrefix(k, "struct:tag:") {
+			return nil
+		}
+	}
+	elem := name
+	if n, ok := att.Meta["xml:name"]; ok && len(n) > 0 && n[0] != "" {
+		elem = n[0]
+	}
+	_, isAttr := att.Meta["xml:attr"]
+	if elem == name && !isAttr {
+		return nil
+	}
+	json, tag := []string{name}, []string{elem}
+	if isAttr && goaexpr.IsPrimitive(att.Type) {
+		tag = append(tag, "attr")
+	}
+	if !parent.IsRequired(name) {
+		json = append(json, "omitempty")
+		tag = append(tag, "omitempty")
+	}
+	return goaexpr.MetaExpr{
+		"struct:tag:form": json,
+		"struct:tag:json": json,
+		"struct:tag:xml":  tag,
+	}
+}
+
+// serverXML defines the XML element names of the response bodies of the
+// service in the HTTP server file and wraps the request decoder and response
+// encoder given to the HTTP server constructor with their XML counterparts.
+func serverXML(f *codegen.File, r *goaexpr.RootExpr) {
+	for _, s := range f.Section("server-init") {
+		data, ok := s.Data.(*httpcodegen.ServiceData)
+		if !ok || !expr.Root.Enabled(data.Service.Name) {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/xml"})
+		f.SectionTemplates = append(f.SectionTemplates, &codegen.SectionTemplate{
+			Name:   "xml-element-names",
+			Source: elementNamesT,
+			Data: map[string]interface{}{
+				"Service": data.Service.Name,
+				"Names":   elementNames(r, data),
+			},
+		})
+		s.Source = strings.Replace(s.Source, "return &{{ .ServerStruct }}{",
+			"dec = xml.WrapRequestDecoder(dec)\n\tenc = xml.WrapResponseEncoder(enc, XMLElementNames)\n\treturn &{{ .ServerStruct }}{", 1)
+	}
+}
+
+// clientXML wraps the response decoder given to the HTTP client constructor
+// of the XML services with its XML counterpart.
+func clientXML(f *codegen.File) {
+	for _, s := range f.Section("client-init") {
+		data, ok := s.Data.(*httpcodegen.ServiceData)
+		if !ok || !expr.Root.Enabled(data.Service.Name) {
+			continue
+		}
+		codegen.AddImport(f.SectionTemplates[0],
+			&codegen.ImportSpec{Path: "goa.design/plugins/v3/xml"})
+		s.Source = strings.Replace(s.Source, "return &{{ .ClientStruct }}{",
+			"dec = xml.WrapResponseDecoder(dec)\n\treturn &{{ .ClientStruct }}{", 1)
+	}
+}
+
+// documentXML adds the XML media type to the media types consumed and
+// produced by the operations of the XML services if f is an OpenAPI file.
+func documentXML(f *codegen.File, r *goaexpr.RootExpr) {
+	walk.Specs(f, func(spec *openapi.V2) error {
+		return walk.Operations(spec, func(_, _ string, op *openapi.Operation) error {
+			if !config.EnabledOperation("xml", op) || !isXMLOperation(r, op) {
+				return nil
+			}
+			if walk.HasBody(op) {
+				if len(op.Consumes) == 0 {
+					op.Consumes = append([]string{}, spec.Consumes...)
+				}
+				op.Consumes = walk.AppendMediaType(op.Consumes, MediaType)
+			}
+			if len(op.Produces) == 0 {
+				op.Produces = append([]string{}, spec.Produces...)
+			}
+			op.Produces = walk.AppendMediaType(op.Produces, MediaType)
+			return nil
+		})
+	})
+}
+
+// elementNames returns the names of the root elements of the response bodies
+// of the given service indexed by Go type name. The names are read from the
+// "xml:name" meta of the result and error types and default to the names of
+// the types.
+func elementNames(r *goaexpr.RootExpr, data *httpcodegen.ServiceData) map[string]string {
+	names := make(map[string]string)
+	svc := r.API.HTTP.Service(data.Service.Name)
+	for _, ed := range data.Endpoints {
+		e := svc.Endpoint(ed.Method.Name)
+		if e == nil {
+			continue
+		}
+		if ed.Result != nil {
+			for _, resp := range ed.Result.Responses {
+				addElementNames(names, resp.ServerBody, e.MethodExpr.Result)
+			}
+		}
+		for _, gerr := range ed.Errors {
+			for _, er := range gerr.Errors {
+				if err := e.MethodExpr.Error(er.Name); err != nil && er.Response != nil {
+					addElementNames(names, er.Response.ServerBody, err.AttributeExpr)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// addElementNames adds the root element name of the body types to names as
+// well as the name of the elements of their items if the bodies are arrays.
+// The root element names of the arrays are also indexed by the name of the
+// unnamed slice types as the generated encoders may encode such slices.
+func addElementNames(names map[string]string, bodies []*httpcodegen.TypeData, att *goaexpr.AttributeExpr) {
+	if att == nil {
+		return
+	}
+	root := elementName(att)
+	var item string
+	if arr := goaexpr.AsArray(att.Type); arr != nil {
+		item = elementName(arr.ElemType)
+	}
+	for _, body := range bodies {
+		if root != "" {
+			names[body.VarName] = root
+		}
+		if !strings.HasPrefix(body.Def, "[]") {
+			continue
+		}
+		elem := strings.TrimLeft(body.Def, "[]*")
+		if codegen.Goify(elem, true) != elem {
+			continue
+		}
+		if root != "" {
+			names["[]"+elem] = root
+		}
+		if item != "" {
+			names[elem] = item
+		}
+	}
+}
+
+// elementName returns the XML element name of the values of the given
+// attribute, the empty string if the attribute type is not a user type and
+// the attribute does not define one.
+func elementName(att *goaexpr.AttributeExpr) string {
+	if n, ok := att.Meta["xml:name"]; ok && len(n) > 0 {
+		return n[0]
+	}
+	ut, ok := att.Type.(goaexpr.UserType)
+	if !ok {
+		return ""
+	}
+	if n, ok := ut.Attribute().Meta["xml:name"]; ok && len(n) > 0 {
+		return n[0]
+	}
+	return ut.Name()
+}
+
+// isXMLOperation returns true if the given operation corresponds to an
+// endpoint of a XML service.
+func isXMLOperation(r *goaexpr.RootExpr, op *openapi.Operation) bool {
+	m := walk.OperationMethod(r, op)
+	return m != nil && expr.Root.Enabled(m.Service.Name)
+}
+
+// input: map[string]interface{}{"Service": string, "Names": map[string]string}
+const elementNamesT = `{{ printf "XMLElementNames maps the Go type names of the %q service HTTP response bodies to the names of their XML root elements." .Service | comment }}
+var XMLElementNames = map[string]string{
+{{- range $typ, $name := .Names }}
+	{{ printf "%q" $typ }}: {{ printf "%q" $name }},
+{{- end }}
+}
+`
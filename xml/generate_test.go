@@ -0,0 +1,99 @@
+package xml_test
+
+import (
+	"testing"
+
+	"goa.design/goa/v3/codegen"
+	"goa.design/goa/v3/eval"
+	goaexpr "goa.design/goa/v3/expr"
+	httpcodegen "goa.design/goa/v3/http/codegen"
+	"goa.design/goa/v3/http/codegen/openapi"
+	"goa.design/plugins/v3/xml"
+	"goa.design/plugins/v3/xml/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.CatalogDSL)
+	roots := []eval.Root{goaexpr.Root}
+	if err := xml.Prepare("", roots); err != nil {
+		t.Fatal(err)
+	}
+	ofs, err := httpcodegen.OpenAPIFiles(goaexpr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := append(httpcodegen.ServerFiles("", goaexpr.Root), httpcodegen.ServerTypeFiles("", goaexpr.Root)...)
+	fs, err = xml.Generate("", roots, append(fs, ofs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections := map[string]map[string]string{
+		"gen/http/catalog/server/server.go": {
+			"xml-element-names": testdata.ElementNamesCode,
+			"server-init":       testdata.ServerInitCode,
+		},
+		"gen/http/catalog/server/types.go": {
+			"request-body-type-decl": testdata.CreateRequestBodyCode,
+		},
+	}
+	for _, f := range fs {
+		switch f.Path {
+		case "gen/http/admin/server/server.go":
+			if len(f.Section("xml-element-names")) > 0 {
+				t.Errorf("unexpected XML element names in service without XML")
+			}
+		case "gen/http/admin/server/types.go":
+			for _, s := range f.Section("request-body-type-decl") {
+				if code := codegen.SectionCode(t, s); code != adminBodyCode {
+					t.Errorf("invalid admin request body, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, adminBodyCode))
+				}
+			}
+		}
+		for name, expected := range sections[f.Path] {
+			ss := f.Section(name)
+			if len(ss) != 1 {
+				t.Fatalf("%s: got %d %q sections, expected 1", f.Path, len(ss), name)
+			}
+			code := codegen.SectionCode(t, ss[0])
+			if code != expected {
+				t.Errorf("%s: invalid %q code, got:\n%s\ngot vs. expected:\n%s", f.Path, name, code, codegen.Diff(t, code, expected))
+			}
+		}
+	}
+	spec := ofs[0].SectionTemplates[0].Data.(*openapi.V2)
+	cases := map[string]struct {
+		Op       *openapi.Operation
+		Consumes bool
+		Produces bool
+	}{
+		"show":   {spec.Paths["/items/{id}"].(*openapi.Path).Get, false, true},
+		"create": {spec.Paths["/items"].(*openapi.Path).Post, true, true},
+		"purge":  {spec.Paths["/purge"].(*openapi.Path).Post, false, false},
+	}
+	for k, c := range cases {
+		if actual := contains(c.Op.Consumes, xml.MediaType); actual != c.Consumes {
+			t.Errorf("%s: got XML consumed %v, expected %v", k, actual, c.Consumes)
+		}
+		if actual := contains(c.Op.Produces, xml.MediaType); actual != c.Produces {
+			t.Errorf("%s: got XML produced %v, expected %v", k, actual, c.Produces)
+		}
+	}
+}
+
+func contains(mts []string, mt string) bool {
+	for _, m := range mts {
+		if m == mt {
+			return true
+		}
+	}
+	return false
+}
+
+const adminBodyCode = `// PurgeRequestBody is the type of the "Admin" service "Purge" endpoint HTTP
+// request body.
+type PurgeRequestBody struct {
+	ID   *string  ` + "`" + `form:"id,omitempty" json:"id,omitempty" xml:"id,omitempty"` + "`" + `
+	Name *string  ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
+	Tags []string ` + "`" + `form:"tags,omitempty" json:"tags,omitempty" xml:"tags,omitempty"` + "`" + `
+}
+`
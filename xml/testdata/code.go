@@ -0,0 +1,45 @@
+package testdata
+
+var ElementNamesCode = `// XMLElementNames maps the Go type names of the "Catalog" service HTTP
+// response bodies to the names of their XML root elements.
+var XMLElementNames = map[string]string{
+	"CreateResponseBody":       "item",
+	"ItemResponse":             "item",
+	"ListResponseBody":         "items",
+	"ShowNotFoundResponseBody": "error",
+	"ShowResponseBody":         "item",
+	"[]ItemResponse":           "items",
+}
+`
+
+var ServerInitCode = `// New instantiates HTTP handlers for all the Catalog service endpoints.
+func New(
+	e *catalog.Endpoints,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) *Server {
+	dec = xml.WrapRequestDecoder(dec)
+	enc = xml.WrapResponseEncoder(enc, XMLElementNames)
+	return &Server{
+		Mounts: []*MountPoint{
+			{"Show", "GET", "/items/{id}"},
+			{"List", "GET", "/items"},
+			{"Create", "POST", "/items"},
+		},
+		Show:   NewShowHandler(e.Show, mux, dec, enc, eh),
+		List:   NewListHandler(e.List, mux, dec, enc, eh),
+		Create: NewCreateHandler(e.Create, mux, dec, enc, eh),
+	}
+}
+`
+
+var CreateRequestBodyCode = `// CreateRequestBody is the type of the "Catalog" service "Create" endpoint
+// HTTP request body.
+type CreateRequestBody struct {
+	ID   *string  ` + "`" + `form:"id" json:"id" xml:"id,attr"` + "`" + `
+	Name *string  ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
+	Tags []string ` + "`" + `form:"tags,omitempty" json:"tags,omitempty" xml:"tag,omitempty"` + "`" + `
+}
+`
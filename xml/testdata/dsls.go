@@ -0,0 +1,58 @@
+package testdata
+
+import (
+	. "goa.design/goa/v3/dsl"
+	xml "goa.design/plugins/v3/xml/dsl"
+)
+
+var CatalogDSL = func() {
+	var Item = Type("Item", func() {
+		Meta("xml:name", "item")
+		Attribute("id", String, func() {
+			Meta("xml:attr")
+		})
+		Attribute("name", String)
+		Attribute("tags", ArrayOf(String), func() {
+			Meta("xml:name", "tag")
+		})
+		Required("id", "name")
+	})
+	Service("Catalog", func() {
+		xml.XML()
+		Method("Show", func() {
+			Payload(String)
+			Result(Item)
+			Error("not_found")
+			HTTP(func() {
+				GET("/items/{id}")
+				Response(StatusOK)
+				Response("not_found", StatusNotFound)
+			})
+		})
+		Method("List", func() {
+			Result(ArrayOf(Item), func() {
+				Meta("xml:name", "items")
+			})
+			HTTP(func() {
+				GET("/items")
+				Response(StatusOK)
+			})
+		})
+		Method("Create", func() {
+			Payload(Item)
+			Result(Item)
+			HTTP(func() {
+				POST("/items")
+				Response(StatusCreated)
+			})
+		})
+	})
+	Service("Admin", func() {
+		Method("Purge", func() {
+			Payload(Item)
+			HTTP(func() {
+				POST("/purge")
+			})
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package xml
+
+import (
+	"context"
+	stdxml "encoding/xml"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	goahttp "goa.design/goa/v3/http"
+	"goa.design/plugins/v3/encoding"
+)
+
+// MediaType is the XML media type.
+const MediaType = "application/xml"
+
+// Format is the XML format: application/xml, text/xml and the media types with
+// the +xml suffix. Its encoders name the root elements after the Go type names
+// of the encoded values.
+var Format = format(nil)
+
+// IsXML returns true if the given Content-Type or Accept header value is an
+// XML media type, that is application/xml, text/xml or a media type with the
+// +xml suffix.
+func IsXML(ct string) bool {
+	return Format.Is(ct)
+}
+
+// WrapRequestDecoder returns a HTTP request decoder that decodes the bodies of
+// the requests whose Content-Type is XML and uses dec for the other requests.
+func WrapRequestDecoder(dec func(*http.Request) goahttp.Decoder) func(*http.Request) goahttp.Decoder {
+	return Format.WrapRequestDecoder(dec)
+}
+
+// WrapResponseEncoder returns a HTTP response encoder that encodes the
+// responses with XML if the response content type set in the design or the
+// first media type of the request Accept header is XML and uses enc otherwise.
+// names maps the Go type names of the response bodies to the names of their
+// root elements.
+func WrapResponseEncoder(enc func(context.Context, http.ResponseWriter) goahttp.Encoder, names map[string]string) func(context.Context, http.ResponseWriter) goahttp.Encoder {
+	return format(names).WrapResponseEncoder(enc)
+}
+
+// WrapResponseDecoder returns a HTTP response decoder that decodes the bodies
+// of the responses whose Content-Type is XML and uses dec for the other
+// responses.
+func WrapResponseDecoder(dec func(*http.Response) goahttp.Decoder) func(*http.Response) goahttp.Decoder {
+	return Format.WrapResponseDecoder(dec)
+}
+
+// RequestEncoder is a HTTP request encoder that encodes the request bodies
+// with XML. It also sets the Accept header so that the servers generated with
+// the plugin respond with XML.
+func RequestEncoder(r *http.Request) goahttp.Encoder {
+	return Format.RequestEncoder(r)
+}
+
+// NewEncoder returns a XML encoder writing to w. The root element is named
+// after the entry of names indexed by the Go type name of the encoded value
+// and defaults to the type name. Slices are encoded as a root element
+// containing one element per item so that the documents are well formed.
+func NewEncoder(w io.Writer, names map[string]string) goahttp.Encoder {
+	return &encoder{enc: stdxml.NewEncoder(w), names: names}
+}
+
+// NewDecoder returns a XML decoder reading from r. The decoder reads the
+// slices from the documents written by the encoders returned by NewEncoder.
+func NewDecoder(r io.Reader) goahttp.Decoder {
+	return &decoder{dec: stdxml.NewDecoder(r)}
+}
+
+type (
+	// encoder encodes values with XML, naming the root elements after the
+	// configured names.
+	encoder struct {
+		enc   *stdxml.Encoder
+		names map[string]string
+	}
+
+	// decoder decodes XML documents into values, including slices.
+	decoder struct {
+		dec *stdxml.Decoder
+	}
+)
+
+// Encode writes the XML encoding of v.
+func (e *encoder) Encode(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil
+	}
+	if !isSlice(rv.Type()) {
+		return e.enc.EncodeElement(v, e.start(rv.Type(), "result"))
+	}
+	start := e.start(rv.Type(), "items")
+	if err := e.enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		if err := e.enc.EncodeElement(item.Interface(), e.start(item.Type(), "item")); err != nil {
+			return err
+		}
+	}
+	if err := e.enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	return e.enc.Flush()
+}
+
+// start returns the start element of the values of type t, def is the element
+// name used when t is unnamed and names does not define one.
+func (e *encoder) start(t reflect.Type, def string) stdxml.StartElement {
+	t = indirect(t)
+	name := t.Name()
+	key := name
+	if key == "" && t.Kind() == reflect.Slice {
+		key = "[]" + indirect(t.Elem()).Name()
+	}
+	if n, ok := e.names[key]; ok {
+		name = n
+	}
+	if name == "" {
+		name = def
+	}
+	return stdxml.StartElement{Name: stdxml.Name{Local: name}}
+}
+
+// Decode reads the next XML document into v.
+func (d *decoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || !isSlice(rv.Elem().Type()) {
+		return d.dec.Decode(v)
+	}
+	s := rv.Elem()
+	if err := d.skipToStart(); err != nil {
+		return err
+	}
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case stdxml.StartElement:
+			item := reflect.New(s.Type().Elem())
+			if err := d.dec.DecodeElement(item.Interface(), &t); err != nil {
+				return err
+			}
+			s.Set(reflect.Append(s, item.Elem()))
+		case stdxml.EndElement:
+			return nil
+		}
+	}
+}
+
+// skipToStart reads the tokens up to the start of the root element.
+func (d *decoder) skipToStart() error {
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		if _, ok := tok.(stdxml.StartElement); ok {
+			return nil
+		}
+	}
+}
+
+// indirect returns the type pointed to by t if t is a pointer type, t
+// otherwise.
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isSlice returns true if t is a slice type other than []byte.
+func isSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// format returns the XML format whose encoders name the root elements after
+// names.
+func format(names map[string]string) *encoding.Format {
+	return &encoding.Format{
+		MediaType: MediaType,
+		Match: func(mt string) bool {
+			return mt == MediaType || mt == "text/xml" || strings.HasSuffix(mt, "+xml")
+		},
+		Codec: encoding.NewCodec(func(w io.Writer) goahttp.Encoder { return NewEncoder(w, names) }, NewDecoder),
+	}
+}
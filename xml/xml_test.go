@@ -0,0 +1,108 @@
+package xml
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+type (
+	itemBody struct {
+		ID   string   `xml:"id,attr"`
+		Tags []string `xml:"tag"`
+	}
+
+	listBody []*itemBody
+)
+
+func TestEncode(t *testing.T) {
+	names := map[string]string{"itemBody": "item", "[]itemBody": "items"}
+	cases := map[string]struct {
+		Value    interface{}
+		Expected string
+	}{
+		"struct":       {&itemBody{ID: "1", Tags: []string{"a", "b"}}, `<item id="1"><tag>a</tag><tag>b</tag></item>`},
+		"unnamed":      {[]*itemBody{{ID: "1"}, {ID: "2"}}, `<items><item id="1"></item><item id="2"></item></items>`},
+		"named":        {listBody{{ID: "1"}}, `<listBody><item id="1"></item></listBody>`},
+		"primitive":    {"foo", `<string>foo</string>`},
+		"unnamed item": {[]string{"a"}, `<items><string>a</string></items>`},
+	}
+	for k, c := range cases {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, names).Encode(c.Value); err != nil {
+			t.Fatalf("%s: %s", k, err)
+		}
+		if buf.String() != c.Expected {
+			t.Errorf("%s: got %s, expected %s", k, buf.String(), c.Expected)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var item itemBody
+	if err := NewDecoder(bytes.NewBufferString(`<item id="1"><tag>a</tag></item>`)).Decode(&item); err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "1" || len(item.Tags) != 1 || item.Tags[0] != "a" {
+		t.Errorf("got %+v, expected ID 1 and tag a", item)
+	}
+	var list listBody
+	if err := NewDecoder(bytes.NewBufferString(`<?xml version="1.0"?><items><item id="1"></item><item id="2"><tag>b</tag></item></items>`)).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "1" || list[1].ID != "2" || list[1].Tags[0] != "b" {
+		t.Errorf("got %+v, expected items 1 and 2", list)
+	}
+}
+
+func TestWrapResponseEncoder(t *testing.T) {
+	enc := WrapResponseEncoder(goahttp.ResponseEncoder, map[string]string{"itemBody": "item"})
+	cases := map[string]struct {
+		Accept      string
+		ContentType string
+		Expected    string
+	}{
+		"xml":          {"application/xml", "", "application/xml"},
+		"text":         {"text/xml, application/json", "", "text/xml"},
+		"suffix":       {"application/vnd.item+xml", "", "application/vnd.item+xml"},
+		"json":         {"application/json, application/xml", "", "application/json"},
+		"content type": {"application/xml", "application/json", "application/json"},
+	}
+	for k, c := range cases {
+		ctx := context.WithValue(context.Background(), goahttp.AcceptTypeKey, c.Accept)
+		if c.ContentType != "" {
+			ctx = context.WithValue(ctx, goahttp.ContentTypeKey, c.ContentType)
+		}
+		w := httptest.NewRecorder()
+		if err := enc(ctx, w).Encode(&itemBody{ID: "1"}); err != nil {
+			t.Fatalf("%s: %s", k, err)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != c.Expected {
+			t.Errorf("%s: got Content-Type %q, expected %q", k, ct, c.Expected)
+		}
+	}
+}
+
+func TestWrapRequestDecoder(t *testing.T) {
+	dec := WrapRequestDecoder(goahttp.RequestDecoder)
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`<item id="1"></item>`))
+	r.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	var item itemBody
+	if err := dec(r).Decode(&item); err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "1" {
+		t.Errorf("got ID %q, expected %q", item.ID, "1")
+	}
+	r = httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"ID":"2"}`))
+	r.Header.Set("Content-Type", "application/json")
+	if err := dec(r).Decode(&item); err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "2" {
+		t.Errorf("got ID %q, expected %q", item.ID, "2")
+	}
+}
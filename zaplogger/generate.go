@@ -9,6 +9,7 @@ import (
 	"goa.design/goa/v3/codegen"
 	"goa.design/goa/v3/eval"
 	"goa.design/goa/v3/expr"
+	"goa.design/plugins/v3/config"
 )
 
 type fileToModify struct {
@@ -26,6 +27,9 @@ func init() {
 
 // Generate generates zap logger specific files.
 func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("zaplogger", "") {
+		return files, nil
+	}
 	for _, root := range roots {
 		if r, ok := root.(*expr.RootExpr); ok {
 			files = append(files, GenerateFiles(genpkg, r)...)
@@ -38,6 +42,9 @@ func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codeg
 // the log import reference when needed
 // It also modify the initially generated main and service files
 func UpdateExample(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	if !config.Enabled("zaplogger", "") {
+		return files, nil
+	}
 
 	filesToModify := []*fileToModify{}
 